@@ -0,0 +1,228 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/osv-scanner/internal/local"
+	"github.com/google/osv-scanner/internal/tlsconfig"
+	"github.com/google/osv-scanner/pkg/reporter"
+	"github.com/urfave/cli/v2"
+)
+
+// dbFlags are the flags of every db subcommand, which set where the local databases are stored
+// and how TLS connections are verified
+func dbFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:      "cache-dir",
+			Usage:     "sets the directory that local databases are cached in",
+			EnvVars:   []string{"OSV_SCANNER_CACHE_DIR"},
+			TakesFile: true,
+		},
+		&cli.StringFlag{
+			Name:   "experimental-local-db-path",
+			Usage:  "sets the path that local databases should be stored",
+			Hidden: true,
+		},
+		&cli.StringFlag{
+			Name:      "ca-cert",
+			Usage:     "path to a PEM encoded certificate to trust in addition to the system roots when making network requests",
+			TakesFile: true,
+		},
+		&cli.BoolFlag{
+			Name:  "insecure-skip-tls",
+			Usage: "DANGEROUS: disables TLS certificate verification for all network requests, making them vulnerable to interception",
+		},
+	}
+}
+
+func Command(stdout, stderr io.Writer, r *reporter.Reporter) *cli.Command {
+	return &cli.Command{
+		Name:  "db",
+		Usage: "[EXPERIMENTAL] exports and imports local databases, for scanning with --experimental-offline in networks without internet access",
+		Subcommands: []*cli.Command{
+			{
+				Name:        "export",
+				Usage:       "packages the local databases into a bundle",
+				Description: "the bundle is a zstd compressed tar archive of every local database that has been downloaded, along with a manifest of their checksums",
+				Flags: append(dbFlags(), &cli.StringFlag{
+					Name:      "output",
+					Aliases:   []string{"o"},
+					Usage:     "the path to write the bundle to, such as bundle.tar.zst (required)",
+					TakesFile: true,
+					Required:  true,
+				}),
+				Action: func(c *cli.Context) error {
+					var err error
+					*r, err = exportAction(c, stdout, stderr)
+
+					return err
+				},
+			},
+			{
+				Name:        "verify",
+				Usage:       "checks that the local databases have not been corrupted or tampered with",
+				Description: "each database is checked against the checksum recorded when it was downloaded or imported, and that every record in it can be read",
+				Flags: append(dbFlags(), &cli.StringFlag{
+					Name:      "signature-key",
+					Usage:     "also checks that each archive is signed by the given PEM encoded public key, as made by \"cosign sign-blob --key\" (keyless signatures and Sigstore bundles are not supported)",
+					TakesFile: true,
+				}),
+				Action: func(c *cli.Context) error {
+					var err error
+					*r, err = verifyAction(c, stdout, stderr)
+
+					return err
+				},
+			},
+			{
+				Name:        "import",
+				Usage:       "replaces the local databases with those of a bundle",
+				Description: "every database in the bundle is checked against its manifest before any local databases are replaced",
+				ArgsUsage:   "<bundle.tar.zst>",
+				Flags:       dbFlags(),
+				Action: func(c *cli.Context) error {
+					var err error
+					*r, err = importAction(c, stdout, stderr)
+
+					return err
+				},
+			},
+		},
+	}
+}
+
+func exportAction(context *cli.Context, stdout, stderr io.Writer) (reporter.Reporter, error) {
+	r := reporter.NewTableReporter(stdout, stderr, reporter.InfoLevel, false, 0)
+
+	if err := tlsconfig.Configure(context.String("ca-cert"), context.Bool("insecure-skip-tls")); err != nil {
+		return r, err
+	}
+
+	dbBasePath, err := local.DatabasesPath(context.String("experimental-local-db-path"), context.String("cache-dir"))
+	if err != nil {
+		return r, err
+	}
+
+	output := context.String("output")
+
+	// write to a temporary file first, so that a failed export does not leave a partial bundle behind
+	f, err := os.CreateTemp(filepath.Dir(output), filepath.Base(output)+".*")
+	if err != nil {
+		return r, fmt.Errorf("could not create %s: %w", output, err)
+	}
+	defer os.Remove(f.Name())
+
+	manifest, err := local.ExportBundle(f, dbBasePath)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return r, fmt.Errorf("could not export local databases: %w", err)
+	}
+
+	//nolint:gosec // being world readable is fine, like the databases in it
+	if err := os.Chmod(f.Name(), 0644); err != nil {
+		return r, err
+	}
+
+	if err := os.Rename(f.Name(), output); err != nil {
+		return r, err
+	}
+
+	for _, db := range manifest.Databases {
+		r.Infof("Exported %s local db, with data as of %s\n", db.Ecosystem, db.ExportedAt.UTC().Format(time.RFC3339))
+	}
+	r.Infof("Exported %d local databases to %s\n", len(manifest.Databases), output)
+
+	return r, nil
+}
+
+func importAction(context *cli.Context, stdout, stderr io.Writer) (reporter.Reporter, error) {
+	r := reporter.NewTableReporter(stdout, stderr, reporter.InfoLevel, false, 0)
+
+	if err := tlsconfig.Configure(context.String("ca-cert"), context.Bool("insecure-skip-tls")); err != nil {
+		return r, err
+	}
+
+	if context.NArg() != 1 {
+		return r, errors.New("expected the path of exactly one bundle to import")
+	}
+
+	dbBasePath, err := local.DatabasesPath(context.String("experimental-local-db-path"), context.String("cache-dir"))
+	if err != nil {
+		return r, err
+	}
+
+	bundle := context.Args().First()
+
+	f, err := os.Open(bundle)
+	if err != nil {
+		return r, fmt.Errorf("could not open %s: %w", bundle, err)
+	}
+	defer f.Close()
+
+	manifest, err := local.ImportBundle(f, dbBasePath)
+	if err != nil {
+		return r, fmt.Errorf("could not import %s: %w", bundle, err)
+	}
+
+	for _, db := range manifest.Databases {
+		r.Infof("Imported %s local db, with data as of %s\n", db.Ecosystem, db.ExportedAt.UTC().Format(time.RFC3339))
+	}
+	r.Infof("Imported %d local databases to %s\n", len(manifest.Databases), dbBasePath)
+
+	return r, nil
+}
+
+func verifyAction(context *cli.Context, stdout, stderr io.Writer) (reporter.Reporter, error) {
+	r := reporter.NewTableReporter(stdout, stderr, reporter.InfoLevel, false, 0)
+
+	if err := tlsconfig.Configure(context.String("ca-cert"), context.Bool("insecure-skip-tls")); err != nil {
+		return r, err
+	}
+
+	dbBasePath, err := local.DatabasesPath(context.String("experimental-local-db-path"), context.String("cache-dir"))
+	if err != nil {
+		return r, err
+	}
+
+	verifications, err := local.VerifyDatabases(dbBasePath, local.Mirror{SignatureKey: context.String("signature-key")})
+	if err != nil {
+		return r, err
+	}
+
+	if len(verifications) == 0 {
+		return r, fmt.Errorf("there are no local databases in %s to verify", dbBasePath)
+	}
+
+	failed := 0
+	for _, v := range verifications {
+		name := string(v.Ecosystem) + "/" + filepath.Base(v.StoredAt)
+
+		switch {
+		case v.Err != nil:
+			failed++
+			r.Errorf("%s failed verification: %v\n", name, v.Err)
+		case v.Checksummed && v.Signed:
+			r.Infof("%s is intact, matching its checksum and signature\n", name)
+		case v.Checksummed:
+			r.Infof("%s is intact, matching its checksum\n", name)
+		case v.Signed:
+			r.Infof("%s can be read and matches its signature, but has no checksum recorded\n", name)
+		default:
+			r.Infof("%s can be read, but has no checksum recorded as it was not downloaded by osv-scanner\n", name)
+		}
+	}
+
+	if failed > 0 {
+		return r, fmt.Errorf("%d of %d local databases failed verification", failed, len(verifications))
+	}
+
+	return r, nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/osv-scanner/internal/testutility"
+)
+
+func TestRun_DB(t *testing.T) {
+	t.Parallel()
+
+	tests := []cliTestCase{
+		{
+			name: "export requires an output",
+			args: []string{"", "db", "export"},
+			exit: 127,
+		},
+		{
+			name: "import requires a bundle",
+			args: []string{"", "db", "import"},
+			exit: 127,
+		},
+		{
+			name: "import of a bundle that does not exist",
+			args: []string{"", "db", "import", "./fixtures/does-not-exist.tar.zst"},
+			exit: 127,
+		},
+		{
+			name: "import of a file that is not a bundle",
+			args: []string{"", "db", "import", "./fixtures/locks-many/package-lock.json"},
+			exit: 127,
+		},
+		{
+			name: "verify with a signature key that does not exist",
+			args: []string{"", "db", "verify", "--signature-key", "./fixtures/does-not-exist.pub"},
+			exit: 127,
+		},
+		{
+			name: "verify with a ca certificate that does not exist",
+			args: []string{"", "db", "verify", "--ca-cert", "./fixtures/does-not-exist.pem"},
+			exit: 127,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			testCli(t, tt)
+		})
+	}
+}
+
+func TestRun_DB_ExportImport(t *testing.T) {
+	t.Parallel()
+
+	exportDir := testutility.CreateTestDir(t)
+	importDir := testutility.CreateTestDir(t)
+	bundle := filepath.Join(testutility.CreateTestDir(t), "bundle.tar.zst")
+
+	buf := new(bytes.Buffer)
+	writer := zip.NewWriter(buf)
+	f, err := writer.Create("GHSA-1.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte(`{"id":"GHSA-1"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	archive := filepath.Join(exportDir, "databases", "npm", "all.zip")
+	if err := os.MkdirAll(filepath.Dir(archive), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(archive, buf.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	runCli(t, cliTestCase{args: []string{"", "db", "export", "--cache-dir", exportDir, "--output", bundle}, exit: 0})
+	runCli(t, cliTestCase{args: []string{"", "db", "import", "--cache-dir", importDir, bundle}, exit: 0})
+	runCli(t, cliTestCase{args: []string{"", "db", "verify", "--cache-dir", importDir}, exit: 0})
+
+	got, err := os.ReadFile(filepath.Join(importDir, "databases", "npm", "all.zip"))
+	if err != nil {
+		t.Fatalf("expected the database to be imported: %v", err)
+	}
+
+	if !bytes.Equal(got, buf.Bytes()) {
+		t.Errorf("expected the database to be imported as it was exported")
+	}
+}
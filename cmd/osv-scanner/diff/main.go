@@ -0,0 +1,126 @@
+package diff
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/ci"
+	"github.com/google/osv-scanner/pkg/osvscanner"
+	"github.com/google/osv-scanner/pkg/reporter"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/term"
+)
+
+func Command(stdout, stderr io.Writer, r *reporter.Reporter) *cli.Command {
+	return &cli.Command{
+		Name:        "diff",
+		Usage:       "compares two json results generated by osv-scanner, without scanning",
+		Description: "reports the findings in the new results that are not in the old results, exiting non-zero if there are any",
+		ArgsUsage:   "[old json results] [new json results]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "format",
+				Aliases: []string{"f"},
+				Usage:   "sets the output format; value can be: " + strings.Join(reporter.Format(), ", "),
+				Value:   "table",
+				Action: func(context *cli.Context, s string) error {
+					if slices.Contains(reporter.Format(), s) {
+						return nil
+					}
+
+					return fmt.Errorf("unsupported output format \"%s\" - must be one of: %s", s, strings.Join(reporter.Format(), ", "))
+				},
+			},
+			&cli.StringFlag{
+				Name:      "output",
+				Usage:     "saves the result to the given file path",
+				TakesFile: true,
+			},
+			&cli.StringFlag{
+				Name:  "verbosity",
+				Usage: "specify the level of information that should be provided during runtime; value can be: " + strings.Join(reporter.VerbosityLevels(), ", "),
+				Value: "info",
+			},
+			&cli.StringFlag{
+				Name:  "log-format",
+				Usage: "sets the format of runtime information, with json printing it to stderr as JSON lines separately from the results; value can be: " + strings.Join(reporter.LogFormats(), ", "),
+				Value: "text",
+				Action: func(context *cli.Context, s string) error {
+					if slices.Contains(reporter.LogFormats(), s) {
+						return nil
+					}
+
+					return fmt.Errorf("unsupported log format \"%s\" - must be one of: %s", s, strings.Join(reporter.LogFormats(), ", "))
+				},
+			},
+		},
+		Action: func(c *cli.Context) error {
+			var err error
+			*r, err = action(c, stdout, stderr)
+
+			return err
+		},
+	}
+}
+
+func action(context *cli.Context, stdout, stderr io.Writer) (reporter.Reporter, error) {
+	if context.NArg() != 2 {
+		return nil, errors.New("diff requires exactly two arguments: the old and new json results")
+	}
+
+	outputPath := context.String("output")
+
+	termWidth := 0
+	var err error
+	if outputPath != "" { // Output is definitely a file
+		stdout, err = os.Create(outputPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create output file: %w", err)
+		}
+	} else { // Output might be a terminal
+		if stdoutAsFile, ok := stdout.(*os.File); ok {
+			termWidth, _, err = term.GetSize(int(stdoutAsFile.Fd()))
+			if err != nil { // If output is not a terminal,
+				termWidth = 0
+			}
+		}
+	}
+
+	verbosityLevel, err := reporter.ParseVerbosityLevel(context.String("verbosity"))
+	if err != nil {
+		return nil, err
+	}
+	r, err := reporter.New(context.String("format"), stdout, stderr, verbosityLevel, termWidth)
+	if err != nil {
+		return r, err
+	}
+	if context.String("log-format") == "json" {
+		r = reporter.NewJSONLogReporter(r, stderr, verbosityLevel)
+	}
+
+	oldResults, err := ci.LoadVulnResults(context.Args().Get(0))
+	if err != nil {
+		return r, err
+	}
+
+	newResults, err := ci.LoadVulnResults(context.Args().Get(1))
+	if err != nil {
+		return r, err
+	}
+
+	added := ci.Compare(r, oldResults, newResults)
+
+	if errPrint := r.PrintResult(&added); errPrint != nil {
+		return r, fmt.Errorf("failed to write output: %w", errPrint)
+	}
+
+	if len(ci.Findings(added)) > 0 {
+		return r, osvscanner.ErrNewVulnerabilitiesFound
+	}
+
+	return r, nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/google/osv-scanner/internal/testutility"
+)
+
+func TestRun_Diff(t *testing.T) {
+	t.Parallel()
+
+	tests := []cliTestCase{
+		{
+			name: "no arguments",
+			args: []string{"", "diff"},
+			exit: 127,
+		},
+		{
+			name: "missing file",
+			args: []string{"", "diff", "./diff/fixtures/old.json", "./diff/fixtures/does-not-exist.json"},
+			exit: 127,
+		},
+		{
+			name: "same results",
+			args: []string{"", "diff", "./diff/fixtures/old.json", "./diff/fixtures/old.json"},
+			exit: 0,
+		},
+		{
+			name: "new findings",
+			args: []string{"", "diff", "--verbosity", "verbose", "./diff/fixtures/old.json", "./diff/fixtures/new.json"},
+			exit: 5,
+		},
+		{
+			name: "new findings in json",
+			args: []string{"", "diff", "--format", "json", "./diff/fixtures/old.json", "./diff/fixtures/new.json"},
+			exit: 5,
+		},
+		{
+			name: "only removed findings",
+			args: []string{"", "diff", "./diff/fixtures/new.json", "./diff/fixtures/old.json"},
+			exit: 0,
+		},
+		{
+			name: "unsupported log format",
+			args: []string{"", "diff", "--log-format", "xml", "./diff/fixtures/old.json", "./diff/fixtures/new.json"},
+			exit: 127,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			stdout, stderr := runCli(t, tt)
+
+			testutility.NewSnapshot().MatchText(t, stdout)
+			testutility.NewSnapshot().MatchText(t, stderr)
+		})
+	}
+}
+
+func TestRun_DiffWith(t *testing.T) {
+	t.Parallel()
+
+	tests := []cliTestCase{
+		{
+			name: "missing previous results",
+			args: []string{"", "--diff-with", "./diff/fixtures/does-not-exist.json", "./fixtures/locks-many/composer.lock"},
+			exit: 127,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			stdout, stderr := runCli(t, tt)
+
+			testutility.NewSnapshot().MatchText(t, stdout)
+			testutility.NewSnapshot().MatchText(t, stderr)
+		})
+	}
+}
@@ -7,10 +7,12 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/google/osv-scanner/internal/cachedir"
 	"github.com/google/osv-scanner/internal/remediation"
 	"github.com/google/osv-scanner/internal/resolution/client"
 	"github.com/google/osv-scanner/internal/resolution/lockfile"
 	"github.com/google/osv-scanner/internal/resolution/manifest"
+	"github.com/google/osv-scanner/internal/tlsconfig"
 	"github.com/google/osv-scanner/pkg/depsdev"
 	"github.com/google/osv-scanner/pkg/reporter"
 	"github.com/urfave/cli/v2"
@@ -31,6 +33,10 @@ type osvFixOptions struct {
 	Lockfile   string
 	LockfileRW lockfile.LockfileIO
 	RelockCmd  string
+	// CachePath is where the resolution cache of the manifest is stored
+	CachePath string
+	// Remediation is the format to print the remediation plan in, rather than applying it
+	Remediation string
 }
 
 func Command(stdout, stderr io.Writer, r *reporter.Reporter) *cli.Command {
@@ -105,6 +111,21 @@ func Command(stdout, stderr io.Writer, r *reporter.Reporter) *cli.Command {
 				Usage:    "apply the top N patches",
 				Value:    -1,
 			},
+			&cli.StringFlag{
+				Category: autoModeCategory,
+				Name:     "remediation",
+				Usage:    "print the plan of upgrades that remediate the vulnerabilities instead of applying them; value can be: json, table",
+				Action: func(ctx *cli.Context, s string) error {
+					if s != "json" && s != "table" {
+						return fmt.Errorf("unsupported remediation format \"%s\" - must be one of: json, table", s)
+					}
+					if !ctx.Bool("non-interactive") {
+						return errors.New("remediation plans can only be printed in non-interactive mode")
+					}
+
+					return nil
+				},
+			},
 
 			&cli.BoolFlag{
 				// TODO: allow for finer control e.g. specific packages, major/minor/patch
@@ -146,6 +167,20 @@ func Command(stdout, stderr io.Writer, r *reporter.Reporter) *cli.Command {
 				Name:     "ignore-dev",
 				Usage:    "ignore vulnerabilities affecting only development dependencies",
 			},
+			&cli.StringFlag{
+				Name:      "ca-cert",
+				Usage:     "path to a PEM encoded certificate to trust in addition to the system roots when making network requests",
+				TakesFile: true,
+			},
+			&cli.BoolFlag{
+				Name:  "insecure-skip-tls",
+				Usage: "DANGEROUS: disables TLS certificate verification for all network requests, making them vulnerable to interception",
+			},
+			&cli.StringFlag{
+				Name:      "cache-dir",
+				Usage:     "directory to store the resolution cache in, defaulting to the osv-scanner directory within the user cache directory",
+				TakesFile: true,
+			},
 		},
 		Action: func(ctx *cli.Context) error {
 			var err error
@@ -166,6 +201,10 @@ func action(ctx *cli.Context, stdout, stderr io.Writer) (reporter.Reporter, erro
 		return nil, errors.New("manifest or lockfile is required")
 	}
 
+	if err := tlsconfig.Configure(ctx.String("ca-cert"), ctx.Bool("insecure-skip-tls")); err != nil {
+		return nil, err
+	}
+
 	opts := osvFixOptions{
 		RemediationOptions: remediation.RemediationOptions{
 			IgnoreVulns:   ctx.StringSlice("ignore-vulns"),
@@ -176,14 +215,23 @@ func action(ctx *cli.Context, stdout, stderr io.Writer) (reporter.Reporter, erro
 			AvoidPkgs:     ctx.StringSlice("disallow-package-upgrades"),
 			AllowMajor:    !ctx.Bool("disallow-major-upgrades"),
 		},
-		Manifest:  ctx.String("manifest"),
-		Lockfile:  ctx.String("lockfile"),
-		RelockCmd: ctx.String("relock-cmd"),
+		Manifest:    ctx.String("manifest"),
+		Lockfile:    ctx.String("lockfile"),
+		RelockCmd:   ctx.String("relock-cmd"),
+		Remediation: ctx.String("remediation"),
 		Client: client.ResolutionClient{
 			VulnerabilityClient: client.NewOSVClient(),
 		},
 	}
 
+	if opts.Manifest != "" {
+		var err error
+		opts.CachePath, err = cachedir.ResolutionCachePath(ctx.String("cache-dir"), opts.Manifest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create cache directory: %w", err)
+		}
+	}
+
 	switch ctx.String("data-source") {
 	case "deps.dev":
 		cl, err := client.NewDepsDevClient(depsdev.DepsdevAPI)
@@ -232,6 +280,20 @@ func action(ctx *cli.Context, stdout, stderr io.Writer) (reporter.Reporter, erro
 	r := reporter.NewTableReporter(stdout, stderr, reporter.InfoLevel, false, 0)
 	maxUpgrades := ctx.Int("apply-top")
 
+	if opts.Remediation != "" {
+		if opts.Remediation == "json" {
+			// keep stdout for the plan so that it can be parsed
+			r = reporter.NewTableReporter(stderr, stderr, reporter.InfoLevel, false, 0)
+		}
+
+		switch ctx.String("strategy") {
+		case "relock":
+			return r, planRelock(ctx.Context, r, stdout, opts, maxUpgrades)
+		case "in-place":
+			return r, planInPlace(ctx.Context, r, stdout, opts, maxUpgrades)
+		}
+	}
+
 	switch ctx.String("strategy") {
 	case "relock":
 		return r, autoRelock(ctx.Context, r, opts, maxUpgrades)
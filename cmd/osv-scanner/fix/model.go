@@ -201,13 +201,13 @@ type doRelockMsg struct {
 	err error
 }
 
-func doRelock(ctx context.Context, cl client.ResolutionClient, m manif.Manifest, matchFn func(resolution.ResolutionVuln) bool) tea.Msg {
+func doRelock(ctx context.Context, cl client.ResolutionClient, m manif.Manifest, cachePath string, matchFn func(resolution.ResolutionVuln) bool) tea.Msg {
 	res, err := resolution.Resolve(ctx, cl, m)
 	if err != nil {
 		return doRelockMsg{nil, err}
 	}
 
-	if err := cl.WriteCache(m.FilePath); err != nil {
+	if err := cl.WriteCache(cachePath); err != nil {
 		return doRelockMsg{nil, err}
 	}
 
@@ -226,9 +226,9 @@ func doInitialRelock(ctx context.Context, opts osvFixOptions) tea.Msg {
 	if err != nil {
 		return doRelockMsg{err: err}
 	}
-	opts.Client.PreFetch(ctx, m.Requirements, m.FilePath)
+	opts.Client.PreFetch(ctx, m.Requirements, opts.CachePath)
 
-	return doRelock(ctx, opts.Client, m, opts.MatchVuln)
+	return doRelock(ctx, opts.Client, m, opts.CachePath, opts.MatchVuln)
 }
 
 // tui.ViewModel for showing non-interactive strings
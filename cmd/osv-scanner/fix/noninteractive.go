@@ -16,20 +16,25 @@ import (
 	"github.com/google/osv-scanner/pkg/reporter"
 )
 
-func autoInPlace(ctx context.Context, r reporter.Reporter, opts osvFixOptions, maxUpgrades int) error {
+// computeInPlace scans the lockfile and finds the in-place patches for its vulnerabilities
+func computeInPlace(ctx context.Context, r reporter.Reporter, opts osvFixOptions) (remediation.InPlaceResult, error) {
 	r.Infof("Scanning %s...\n", opts.Lockfile)
 	f, err := lockfile.OpenLocalDepFile(opts.Lockfile)
 	if err != nil {
-		return err
+		return remediation.InPlaceResult{}, err
 	}
 
 	g, err := opts.LockfileRW.Read(f)
 	f.Close()
 	if err != nil {
-		return err
+		return remediation.InPlaceResult{}, err
 	}
 
-	res, err := remediation.ComputeInPlacePatches(ctx, opts.Client, g, opts.RemediationOptions)
+	return remediation.ComputeInPlacePatches(ctx, opts.Client, g, opts.RemediationOptions)
+}
+
+func autoInPlace(ctx context.Context, r reporter.Reporter, opts osvFixOptions, maxUpgrades int) error {
+	res, err := computeInPlace(ctx, r, opts)
 	if err != nil {
 		return err
 	}
@@ -55,18 +60,12 @@ func autoInPlace(ctx context.Context, r reporter.Reporter, opts osvFixOptions, m
 // returns the top {maxUpgrades} compatible patches, the number of vulns fixed, and the number of potentially fixable vulns left unfixed
 // if maxUpgrades is < 0, do as many patches as possible
 func autoChooseInPlacePatches(res remediation.InPlaceResult, maxUpgrades int) ([]lf.DependencyPatch, int, int) {
-	// Keep track of the VersionKeys we've already patched so we know which patches are incompatible
-	seenVKs := make(map[resolve.VersionKey]bool)
-
 	// Key vulnerabilities by (ID, package name, package version) to be consistent with scan action's counting
 	type vulnKey struct {
 		id string
 		vk resolve.VersionKey
 	}
 	uniqueVulns := make(map[vulnKey]struct{})
-	var patches []lf.DependencyPatch
-	numFixed := 0
-
 	for _, p := range res.Patches {
 		vk := resolve.VersionKey{
 			PackageKey: p.Pkg,
@@ -77,37 +76,68 @@ func autoChooseInPlacePatches(res remediation.InPlaceResult, maxUpgrades int) ([
 		for _, rv := range p.ResolvedVulns {
 			uniqueVulns[vulnKey{id: rv.Vulnerability.ID, vk: vk}] = struct{}{}
 		}
+	}
+
+	chosen, _ := chooseInPlacePatches(res, maxUpgrades)
+	patches := make([]lf.DependencyPatch, 0, len(chosen))
+	numFixed := 0
+	for _, p := range chosen {
+		patches = append(patches, p.DependencyPatch)
+		numFixed += len(p.ResolvedVulns)
+	}
+
+	return patches, numFixed, len(uniqueVulns) - numFixed
+}
+
+// returns the top {maxUpgrades} compatible patches, along with the patches that are incompatible with them
+// if maxUpgrades is < 0, choose as many patches as possible
+func chooseInPlacePatches(res remediation.InPlaceResult, maxUpgrades int) ([]remediation.InPlacePatch, []remediation.InPlacePatch) {
+	// Keep track of the VersionKeys we've already patched so we know which patches are incompatible
+	seenVKs := make(map[resolve.VersionKey]bool)
+	var chosen, incompatible []remediation.InPlacePatch
+
+	for _, p := range res.Patches {
+		vk := resolve.VersionKey{
+			PackageKey: p.Pkg,
+			Version:    p.OrigVersion,
+		}
+
+		if seenVKs[vk] {
+			incompatible = append(incompatible, p)
+			continue
+		}
 
 		// If we still are picking more patches, and we haven't already patched this specific version,
-		// then add this patch to our final set of patches and count the vulnerabilities
-		if maxUpgrades != 0 && !seenVKs[vk] {
+		// then add this patch to our final set of patches
+		if maxUpgrades != 0 {
 			seenVKs[vk] = true
-			patches = append(patches, p.DependencyPatch)
+			chosen = append(chosen, p)
 			maxUpgrades--
-			numFixed += len(p.ResolvedVulns)
 		}
 	}
 
-	return patches, numFixed, len(uniqueVulns) - numFixed
+	return chosen, incompatible
 }
 
-func autoRelock(ctx context.Context, r reporter.Reporter, opts osvFixOptions, maxUpgrades int) error {
+// computeRelock resolves the manifest and finds the patches to its requirements
+// that remediate the vulnerabilities in the resolved dependencies
+func computeRelock(ctx context.Context, r reporter.Reporter, opts osvFixOptions) (manifest.Manifest, *resolution.ResolutionResult, []resolution.ResolutionDiff, error) {
 	r.Infof("Resolving %s...\n", opts.Manifest)
 	f, err := lockfile.OpenLocalDepFile(opts.Manifest)
 	if err != nil {
-		return err
+		return manifest.Manifest{}, nil, nil, err
 	}
 
 	manif, err := opts.ManifestRW.Read(f)
 	f.Close()
 	if err != nil {
-		return err
+		return manifest.Manifest{}, nil, nil, err
 	}
 
-	opts.Client.PreFetch(ctx, manif.Requirements, manif.FilePath)
+	opts.Client.PreFetch(ctx, manif.Requirements, opts.CachePath)
 	res, err := resolution.Resolve(ctx, opts.Client, manif)
 	if err != nil {
-		return err
+		return manifest.Manifest{}, nil, nil, err
 	}
 
 	if errs := res.Errors(); len(errs) > 0 {
@@ -117,18 +147,27 @@ func autoRelock(ctx context.Context, r reporter.Reporter, opts osvFixOptions, ma
 
 	res.FilterVulns(opts.MatchVuln)
 	// TODO: count vulnerabilities per unique version as scan action does
-	totalVulns := len(res.Vulns)
-	r.Infof("Found %d vulnerabilities matching the filter\n", totalVulns)
+	r.Infof("Found %d vulnerabilities matching the filter\n", len(res.Vulns))
 
 	allPatches, err := remediation.ComputeRelaxPatches(ctx, opts.Client, res, opts.RemediationOptions)
 	if err != nil {
-		return err
+		return manifest.Manifest{}, nil, nil, err
 	}
 
-	if err := opts.Client.WriteCache(manif.FilePath); err != nil {
+	if err := opts.Client.WriteCache(opts.CachePath); err != nil {
 		r.Warnf("WARNING: failed to write resolution cache: %v\n", err)
 	}
 
+	return manif, res, allPatches, nil
+}
+
+func autoRelock(ctx context.Context, r reporter.Reporter, opts osvFixOptions, maxUpgrades int) error {
+	manif, res, allPatches, err := computeRelock(ctx, r, opts)
+	if err != nil {
+		return err
+	}
+	totalVulns := len(res.Vulns)
+
 	if len(allPatches) == 0 {
 		r.Infof("No dependency patches are possible\n")
 		r.Infof("REMAINING-VULNS: %d\n", totalVulns)
@@ -191,28 +230,46 @@ func autoRelock(ctx context.Context, r reporter.Reporter, opts osvFixOptions, ma
 // if maxUpgrades is < 0, do as many patches as possible
 func autoChooseRelockPatches(diffs []resolution.ResolutionDiff, maxUpgrades int) ([]manifest.DependencyPatch, int) {
 	var patches []manifest.DependencyPatch
-	pkgChanged := make(map[resolve.VersionKey]bool) // dependencies we've already applied a patch to
 	numFixed := 0
 
+	chosen, _ := chooseRelockDiffs(diffs, maxUpgrades)
+	for _, diff := range chosen {
+		// Add all individual package patches to the final patch list, and count the number of vulns this is anticipated to resolve
+		numFixed += len(diff.RemovedVulns)
+		patches = append(patches, diff.Deps...)
+	}
+
+	return patches, numFixed
+}
+
+// returns the top {maxUpgrades} compatible diffs, along with the diffs that are incompatible with them
+// if maxUpgrades is < 0, choose as many diffs as possible
+func chooseRelockDiffs(diffs []resolution.ResolutionDiff, maxUpgrades int) ([]resolution.ResolutionDiff, []resolution.ResolutionDiff) {
+	var chosen, incompatible []resolution.ResolutionDiff
+	pkgChanged := make(map[resolve.VersionKey]bool) // dependencies we've already applied a patch to
+
 	for _, diff := range diffs {
-		// If we are not picking any more patches, or this patch is incompatible with existing patches, skip adding it to the patch list.
 		// A patch is incompatible if any of its changed packages have already been changed by an existing patch.
-		if maxUpgrades == 0 || slices.ContainsFunc(diff.Deps, func(dp manifest.DependencyPatch) bool {
+		if slices.ContainsFunc(diff.Deps, func(dp manifest.DependencyPatch) bool {
 			return pkgChanged[resolve.VersionKey{PackageKey: dp.Pkg, Version: dp.OrigRequire}]
 		}) {
+			incompatible = append(incompatible, diff)
 			continue
 		}
 
-		// Add all individual package patches to the final patch list, and count the number of vulns this is anticipated to resolve
-		numFixed += len(diff.RemovedVulns)
+		// If we are not picking any more patches, skip adding it to the patch list.
+		if maxUpgrades == 0 {
+			continue
+		}
+
+		chosen = append(chosen, diff)
 		for _, dp := range diff.Deps {
-			patches = append(patches, dp)
 			pkgChanged[resolve.VersionKey{PackageKey: dp.Pkg, Version: dp.OrigRequire}] = true
 		}
 		maxUpgrades--
 	}
 
-	return patches, numFixed
+	return chosen, incompatible
 }
 
 func relockUnfixableVulns(diffs []resolution.ResolutionDiff) []*resolution.ResolutionVuln {
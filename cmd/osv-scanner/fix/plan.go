@@ -0,0 +1,218 @@
+package fix
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/remediation"
+	"github.com/google/osv-scanner/internal/resolution"
+	"github.com/google/osv-scanner/pkg/reporter"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// remediationPlan describes the upgrades that would remediate the vulnerabilities, without applying them
+type remediationPlan struct {
+	Strategy string `json:"strategy"`
+	Path     string `json:"path"`
+	// TotalVulns is the number of vulnerabilities that match the selection flags
+	TotalVulns int                  `json:"total_vulnerabilities"`
+	Upgrades   []remediationUpgrade `json:"upgrades"`
+	// Conflicts are the vulnerabilities that could only be fixed by upgrading
+	// a dependency to a different version than the one it is upgraded to
+	Conflicts []remediationConflict `json:"conflicts"`
+	// Unfixable are the IDs of the vulnerabilities that no upgrade can fix
+	Unfixable []string `json:"unfixable"`
+}
+
+// remediationUpgrade is an upgrade to a dependency, along with the vulnerabilities that it fixes
+type remediationUpgrade struct {
+	Package string   `json:"package"`
+	From    string   `json:"from"`
+	To      string   `json:"to"`
+	Fixes   []string `json:"fixes"`
+}
+
+// remediationConflict is a vulnerability that would be fixed by upgrading the package to a version
+// that conflicts with the version the plan upgrades it to, so that no single version fixes everything
+type remediationConflict struct {
+	ID      string `json:"id"`
+	Package string `json:"package"`
+	// FixedBy is the version that would fix the vulnerability
+	FixedBy string `json:"fixed_by"`
+	// Planned is the version that the package is upgraded to in the plan
+	Planned string `json:"planned"`
+}
+
+func vulnIDs(vulns []resolution.ResolutionVuln) []string {
+	ids := make([]string, 0, len(vulns))
+	for _, v := range vulns {
+		ids = append(ids, v.Vulnerability.ID)
+	}
+	slices.Sort(ids)
+
+	return slices.Compact(ids)
+}
+
+// addConflicts records the vulnerabilities that are fixed by the incompatible upgrades but not by any
+// of the planned ones, against the version that each package is planned to be upgraded to
+func (p *remediationPlan) addConflicts(fixes []string, pkg, fixedBy string) {
+	planned := slices.IndexFunc(p.Upgrades, func(u remediationUpgrade) bool { return u.Package == pkg })
+	if planned < 0 {
+		return
+	}
+
+	for _, id := range fixes {
+		fixedByPlan := slices.ContainsFunc(p.Upgrades, func(u remediationUpgrade) bool { return slices.Contains(u.Fixes, id) })
+		reported := slices.ContainsFunc(p.Conflicts, func(c remediationConflict) bool { return c.ID == id && c.Package == pkg })
+		if fixedByPlan || reported {
+			continue
+		}
+
+		p.Conflicts = append(p.Conflicts, remediationConflict{
+			ID:      id,
+			Package: pkg,
+			FixedBy: fixedBy,
+			Planned: p.Upgrades[planned].To,
+		})
+	}
+}
+
+// inPlacePlan describes the in-place patches that would be applied to the lockfile
+func inPlacePlan(path string, res remediation.InPlaceResult, maxUpgrades int) remediationPlan {
+	chosen, incompatible := chooseInPlacePatches(res, maxUpgrades)
+	_, nFixed, nRemain := autoChooseInPlacePatches(res, maxUpgrades)
+
+	plan := remediationPlan{
+		Strategy:   "in-place",
+		Path:       path,
+		TotalVulns: nFixed + nRemain + len(res.Unfixable),
+		Upgrades:   []remediationUpgrade{},
+		Conflicts:  []remediationConflict{},
+		Unfixable:  vulnIDs(res.Unfixable),
+	}
+
+	for _, p := range chosen {
+		plan.Upgrades = append(plan.Upgrades, remediationUpgrade{
+			Package: p.Pkg.Name,
+			From:    p.OrigVersion,
+			To:      p.NewVersion,
+			Fixes:   vulnIDs(p.ResolvedVulns),
+		})
+	}
+
+	for _, p := range incompatible {
+		plan.addConflicts(vulnIDs(p.ResolvedVulns), p.Pkg.Name, p.NewVersion)
+	}
+
+	return plan
+}
+
+// relockPlan describes the patches that would be applied to the requirements of the manifest
+func relockPlan(path string, res *resolution.ResolutionResult, diffs []resolution.ResolutionDiff, maxUpgrades int) remediationPlan {
+	chosen, incompatible := chooseRelockDiffs(diffs, maxUpgrades)
+
+	plan := remediationPlan{
+		Strategy:   "relock",
+		Path:       path,
+		TotalVulns: len(res.Vulns),
+		Upgrades:   []remediationUpgrade{},
+		Conflicts:  []remediationConflict{},
+		Unfixable:  vulnIDs(res.Vulns),
+	}
+
+	if len(diffs) > 0 {
+		unfixable := relockUnfixableVulns(diffs)
+		ids := make([]string, 0, len(unfixable))
+		for _, v := range unfixable {
+			ids = append(ids, v.Vulnerability.ID)
+		}
+		slices.Sort(ids)
+		plan.Unfixable = slices.Compact(ids)
+	}
+
+	for _, diff := range chosen {
+		for _, dp := range diff.Deps {
+			plan.Upgrades = append(plan.Upgrades, remediationUpgrade{
+				Package: dp.Pkg.Name,
+				From:    dp.OrigRequire,
+				To:      dp.NewRequire,
+				Fixes:   vulnIDs(diff.RemovedVulns),
+			})
+		}
+	}
+
+	for _, diff := range incompatible {
+		for _, dp := range diff.Deps {
+			plan.addConflicts(vulnIDs(diff.RemovedVulns), dp.Pkg.Name, dp.NewRequire)
+		}
+	}
+
+	return plan
+}
+
+// printRemediationPlan prints the plan in the format, which is either "json" or "table"
+func printRemediationPlan(w io.Writer, plan remediationPlan, format string) error {
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(plan)
+	}
+
+	fmt.Fprintf(w, "Remediation plan for %s (%s strategy):\n", plan.Path, plan.Strategy)
+
+	if len(plan.Upgrades) == 0 {
+		fmt.Fprintf(w, "No upgrades can fix the %d matching vulnerabilities\n", plan.TotalVulns)
+	} else {
+		upgradesTable := table.NewWriter()
+		upgradesTable.SetOutputMirror(w)
+		upgradesTable.SetStyle(table.StyleRounded)
+		upgradesTable.AppendHeader(table.Row{"Package", "From", "To", "Fixes"})
+		for _, u := range plan.Upgrades {
+			upgradesTable.AppendRow(table.Row{u.Package, u.From, u.To, strings.Join(u.Fixes, "\n")})
+		}
+		upgradesTable.Render()
+	}
+
+	if len(plan.Conflicts) > 0 {
+		fmt.Fprintf(w, "Conflicting upgrades, where no single version fixes every vulnerability:\n")
+		conflictsTable := table.NewWriter()
+		conflictsTable.SetOutputMirror(w)
+		conflictsTable.SetStyle(table.StyleRounded)
+		conflictsTable.AppendHeader(table.Row{"Vulnerability", "Package", "Fixed by", "Planned"})
+		for _, c := range plan.Conflicts {
+			conflictsTable.AppendRow(table.Row{c.ID, c.Package, c.FixedBy, c.Planned})
+		}
+		conflictsTable.Render()
+	}
+
+	if len(plan.Unfixable) > 0 {
+		fmt.Fprintf(w, "Unfixable vulnerabilities: %s\n", strings.Join(plan.Unfixable, ", "))
+	}
+
+	return nil
+}
+
+// planInPlace prints the plan of in-place patches for the lockfile, without changing it
+func planInPlace(ctx context.Context, r reporter.Reporter, w io.Writer, opts osvFixOptions, maxUpgrades int) error {
+	res, err := computeInPlace(ctx, r, opts)
+	if err != nil {
+		return err
+	}
+
+	return printRemediationPlan(w, inPlacePlan(opts.Lockfile, res, maxUpgrades), opts.Remediation)
+}
+
+// planRelock prints the plan of patches for the manifest, without changing it or the lockfile
+func planRelock(ctx context.Context, r reporter.Reporter, w io.Writer, opts osvFixOptions, maxUpgrades int) error {
+	_, res, allPatches, err := computeRelock(ctx, r, opts)
+	if err != nil {
+		return err
+	}
+
+	return printRemediationPlan(w, relockPlan(opts.Manifest, res, allPatches, maxUpgrades), opts.Remediation)
+}
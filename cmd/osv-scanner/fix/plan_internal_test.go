@@ -0,0 +1,150 @@
+package fix
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"deps.dev/util/resolve"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/internal/remediation"
+	"github.com/google/osv-scanner/internal/resolution"
+	lf "github.com/google/osv-scanner/internal/resolution/lockfile"
+	"github.com/google/osv-scanner/internal/resolution/manifest"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func resolutionVulns(ids ...string) []resolution.ResolutionVuln {
+	vulns := make([]resolution.ResolutionVuln, 0, len(ids))
+	for _, id := range ids {
+		vulns = append(vulns, resolution.ResolutionVuln{Vulnerability: models.Vulnerability{ID: id}})
+	}
+
+	return vulns
+}
+
+func npmPackage(name string) resolve.PackageKey {
+	return resolve.PackageKey{System: resolve.NPM, Name: name}
+}
+
+func Test_relockPlan(t *testing.T) {
+	t.Parallel()
+
+	res := &resolution.ResolutionResult{Vulns: resolutionVulns("GHSA-1", "GHSA-2", "GHSA-3", "GHSA-4")}
+	diffs := []resolution.ResolutionDiff{
+		{
+			Original:      res,
+			RemovedVulns:  resolutionVulns("GHSA-1", "GHSA-2"),
+			ManifestPatch: manifest.ManifestPatch{Deps: []manifest.DependencyPatch{{Pkg: npmPackage("express"), OrigRequire: "^4.0.0", NewRequire: "^4.17.3"}}},
+		},
+		{
+			// fixing GHSA-3 needs a version of express that conflicts with the one that fixes the others
+			Original:      res,
+			RemovedVulns:  resolutionVulns("GHSA-1", "GHSA-3"),
+			ManifestPatch: manifest.ManifestPatch{Deps: []manifest.DependencyPatch{{Pkg: npmPackage("express"), OrigRequire: "^4.0.0", NewRequire: "^5.0.0"}}},
+		},
+	}
+
+	want := remediationPlan{
+		Strategy:   "relock",
+		Path:       "package.json",
+		TotalVulns: 4,
+		Upgrades: []remediationUpgrade{
+			{Package: "express", From: "^4.0.0", To: "^4.17.3", Fixes: []string{"GHSA-1", "GHSA-2"}},
+		},
+		Conflicts: []remediationConflict{
+			{ID: "GHSA-3", Package: "express", FixedBy: "^5.0.0", Planned: "^4.17.3"},
+		},
+		Unfixable: []string{"GHSA-4"},
+	}
+
+	if diff := cmp.Diff(want, relockPlan("package.json", res, diffs, -1)); diff != "" {
+		t.Errorf("relockPlan() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_relockPlan_NoPatches(t *testing.T) {
+	t.Parallel()
+
+	res := &resolution.ResolutionResult{Vulns: resolutionVulns("GHSA-2", "GHSA-1")}
+
+	want := remediationPlan{
+		Strategy:   "relock",
+		Path:       "package.json",
+		TotalVulns: 2,
+		Upgrades:   []remediationUpgrade{},
+		Conflicts:  []remediationConflict{},
+		Unfixable:  []string{"GHSA-1", "GHSA-2"},
+	}
+
+	if diff := cmp.Diff(want, relockPlan("package.json", res, nil, -1)); diff != "" {
+		t.Errorf("relockPlan() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_inPlacePlan(t *testing.T) {
+	t.Parallel()
+
+	res := remediation.InPlaceResult{
+		Patches: []remediation.InPlacePatch{
+			{
+				DependencyPatch: lf.DependencyPatch{Pkg: npmPackage("qs"), OrigVersion: "6.7.0", NewVersion: "6.7.3"},
+				ResolvedVulns:   resolutionVulns("GHSA-1"),
+			},
+			{
+				DependencyPatch: lf.DependencyPatch{Pkg: npmPackage("qs"), OrigVersion: "6.7.0", NewVersion: "6.10.3"},
+				ResolvedVulns:   resolutionVulns("GHSA-1", "GHSA-2"),
+			},
+			{
+				DependencyPatch: lf.DependencyPatch{Pkg: npmPackage("lodash"), OrigVersion: "4.17.20", NewVersion: "4.17.21"},
+				ResolvedVulns:   resolutionVulns("GHSA-3"),
+			},
+		},
+		Unfixable: resolutionVulns("GHSA-4"),
+	}
+
+	want := remediationPlan{
+		Strategy:   "in-place",
+		Path:       "package-lock.json",
+		TotalVulns: 4,
+		Upgrades: []remediationUpgrade{
+			{Package: "qs", From: "6.7.0", To: "6.7.3", Fixes: []string{"GHSA-1"}},
+			{Package: "lodash", From: "4.17.20", To: "4.17.21", Fixes: []string{"GHSA-3"}},
+		},
+		Conflicts: []remediationConflict{
+			{ID: "GHSA-2", Package: "qs", FixedBy: "6.10.3", Planned: "6.7.3"},
+		},
+		Unfixable: []string{"GHSA-4"},
+	}
+
+	if diff := cmp.Diff(want, inPlacePlan("package-lock.json", res, -1)); diff != "" {
+		t.Errorf("inPlacePlan() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_printRemediationPlan_JSON(t *testing.T) {
+	t.Parallel()
+
+	plan := remediationPlan{
+		Strategy:   "in-place",
+		Path:       "package-lock.json",
+		TotalVulns: 1,
+		Upgrades:   []remediationUpgrade{{Package: "qs", From: "6.7.0", To: "6.7.3", Fixes: []string{"GHSA-1"}}},
+		Conflicts:  []remediationConflict{},
+		Unfixable:  []string{},
+	}
+
+	var buf bytes.Buffer
+	if err := printRemediationPlan(&buf, plan, "json"); err != nil {
+		t.Fatalf("printRemediationPlan() error = %v", err)
+	}
+
+	var got remediationPlan
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("printed plan is not valid JSON: %v\n%s", err, buf.String())
+	}
+
+	if diff := cmp.Diff(plan, got); diff != "" {
+		t.Errorf("printed plan mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -6,8 +6,12 @@ import (
 	"os"
 	"slices"
 
+	"github.com/google/osv-scanner/cmd/osv-scanner/db"
+	"github.com/google/osv-scanner/cmd/osv-scanner/diff"
 	"github.com/google/osv-scanner/cmd/osv-scanner/fix"
+	"github.com/google/osv-scanner/cmd/osv-scanner/query"
 	"github.com/google/osv-scanner/cmd/osv-scanner/scan"
+	"github.com/google/osv-scanner/cmd/osv-scanner/serveapi"
 	"github.com/google/osv-scanner/cmd/osv-scanner/update"
 	"github.com/google/osv-scanner/internal/version"
 	"github.com/google/osv-scanner/pkg/osv"
@@ -44,6 +48,10 @@ func run(args []string, stdout, stderr io.Writer) int {
 			scan.Command(stdout, stderr, &r),
 			fix.Command(stdout, stderr, &r),
 			update.Command(stdout, stderr, &r),
+			diff.Command(stdout, stderr, &r),
+			query.Command(stdout, stderr, &r),
+			serveapi.Command(stdout, stderr, &r),
+			db.Command(stdout, stderr, &r),
 		},
 	}
 
@@ -53,12 +61,26 @@ func run(args []string, stdout, stderr io.Writer) int {
 		if r == nil {
 			r = reporter.NewTableReporter(stdout, stderr, reporter.InfoLevel, false, 0)
 		}
+		var exitCodeErr scan.ExitCodeError
 		switch {
+		case errors.As(err, &exitCodeErr):
+			return exitCodeErr.Code
+		case errors.Is(err, osvscanner.ErrVulnerabilitiesAndLicenseViolationsFound):
+			return 4
+		case errors.Is(err, osvscanner.ErrLicenseViolationsFound):
+			return 2
+		case errors.Is(err, osvscanner.ErrUnknownLicensesFound):
+			return 3
+		case errors.Is(err, osvscanner.ErrNewVulnerabilitiesFound):
+			return 5
 		case errors.Is(err, osvscanner.VulnerabilitiesFoundErr):
 			return 1
 		case errors.Is(err, osvscanner.NoPackagesFoundErr):
 			r.Errorf("No package sources found, --help for usage information.\n")
 			return 128
+		case errors.Is(err, osvscanner.ErrTimedOut), errors.Is(err, osvscanner.ErrInterrupted):
+			r.Errorf("%v, so the results are incomplete\n", err)
+			return 130
 		case errors.Is(err, osvscanner.ErrAPIFailed):
 			r.Errorf("%v\n", err)
 			return 129
@@ -3,6 +3,8 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
+	"encoding/xml"
 	"errors"
 	"os"
 	"path/filepath"
@@ -591,7 +593,7 @@ func TestRun_Licenses(t *testing.T) {
 		{
 			name: "Vulnerabilities and license violations with allowlist",
 			args: []string{"", "--experimental-licenses", "MIT", "--config=./fixtures/osv-scanner-empty-config.toml", "./fixtures/locks-many/package-lock.json"},
-			exit: 1,
+			exit: 4,
 		},
 		{
 			name: "Vulnerabilities and all license violations allowlisted",
@@ -601,12 +603,12 @@ func TestRun_Licenses(t *testing.T) {
 		{
 			name: "Some packages with license violations and show-all-packages in json",
 			args: []string{"", "--format=json", "--experimental-licenses", "MIT", "--experimental-all-packages", "./fixtures/locks-licenses/package-lock.json"},
-			exit: 1,
+			exit: 2,
 		},
 		{
 			name: "Some packages with license violations in json",
 			args: []string{"", "--format=json", "--experimental-licenses", "MIT", "./fixtures/locks-licenses/package-lock.json"},
-			exit: 1,
+			exit: 2,
 		},
 		{
 			name: "No license violations and show-all-packages in json",
@@ -790,3 +792,334 @@ func TestRun_InsertDefaultCommand(t *testing.T) {
 		testutility.NewSnapshot().MatchText(t, normalizeStdStream(t, stderr))
 	}
 }
+
+func TestRun_ValidateConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []cliTestCase{
+		{
+			name: "valid config",
+			args: []string{"", "--validate-config", "./fixtures/osv-scanner-empty-config.toml"},
+			exit: 0,
+		},
+		{
+			name: "config with unknown keys",
+			args: []string{"", "--validate-config", "./fixtures/osv-scanner-invalid-config.toml"},
+			exit: 127,
+		},
+		{
+			name: "config that does not exist",
+			args: []string{"", "--validate-config", "./fixtures/osv-scanner-missing-config.toml"},
+			exit: 127,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			testCli(t, tt)
+		})
+	}
+}
+
+func TestRun_VersionDetails(t *testing.T) {
+	t.Parallel()
+
+	tests := []cliTestCase{
+		{
+			name: "checking against the api",
+			args: []string{"", "--version-details"},
+			exit: 0,
+		},
+		{
+			name: "scan targets are not scanned",
+			args: []string{"", "--version-details", "./fixtures/locks-many/composer.lock"},
+			exit: 0,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			testCli(t, tt)
+		})
+	}
+}
+
+func TestRun_OutputDir(t *testing.T) {
+	t.Parallel()
+
+	tests := []cliTestCase{
+		{
+			name: "multiple formats without an output directory",
+			args: []string{"", "--format", "json", "--format", "sarif", "./fixtures/locks-many/composer.lock"},
+			exit: 127,
+		},
+		{
+			name: "output file and output directory",
+			args: []string{"", "--output", "results.json", "--output-dir", "results", "--format", "json", "./fixtures/locks-many/composer.lock"},
+			exit: 127,
+		},
+		{
+			name: "output with a format and output directory",
+			args: []string{"", "--output", "results.sarif:sarif", "--output-dir", "results", "./fixtures/locks-many/composer.lock"},
+			exit: 127,
+		},
+		{
+			name: "unsupported format in an output directory",
+			args: []string{"", "--output-dir", "results", "--format", "json", "--format", "unknown", "./fixtures/locks-many/composer.lock"},
+			exit: 127,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			testCli(t, tt)
+		})
+	}
+}
+
+func TestRun_OutputURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []cliTestCase{
+		{
+			name: "output header without an output url",
+			args: []string{"", "--output-header", "Authorization: Bearer token", "./fixtures/locks-many/composer.lock"},
+			exit: 127,
+		},
+		{
+			name: "malformed output header",
+			args: []string{"", "--output-url", "http://localhost:1/results", "--output-header", "Bearer token", "./fixtures/locks-many/composer.lock"},
+			exit: 127,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			testCli(t, tt)
+		})
+	}
+}
+
+func TestRun_MachineReadableStdout(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		format string
+		valid  func(stdout []byte) error
+	}{
+		{
+			format: "json",
+			valid: func(stdout []byte) error {
+				return json.Unmarshal(stdout, &map[string]any{})
+			},
+		},
+		{
+			format: "sarif",
+			valid: func(stdout []byte) error {
+				return json.Unmarshal(stdout, &map[string]any{})
+			},
+		},
+		{
+			format: "junit",
+			valid: func(stdout []byte) error {
+				return xml.Unmarshal(stdout, &struct{}{})
+			},
+		},
+		{
+			format: "html",
+			valid: func(stdout []byte) error {
+				if !bytes.HasPrefix(stdout, []byte("<!DOCTYPE html>")) {
+					return errors.New("does not start with a doctype")
+				}
+
+				return nil
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.format, func(t *testing.T) {
+			t.Parallel()
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			// the local databases cannot be loaded offline from an empty directory, and one of
+			// the lockfiles is invalid, so there are errors and info messages alongside the report
+			ec := run([]string{
+				"",
+				"--format", tt.format,
+				"--experimental-offline",
+				"--experimental-local-db-path", t.TempDir(),
+				"./fixtures/locks-many-with-invalid",
+			}, stdout, stderr)
+
+			if ec != 127 {
+				t.Errorf("cli exited with code %d, not %d", ec, 127)
+			}
+
+			if err := tt.valid(stdout.Bytes()); err != nil {
+				t.Errorf("stdout is not a valid %s report: %v\n%s", tt.format, err, stdout.String())
+			}
+
+			for _, message := range []string{"Scanning dir", "could not load db"} {
+				if !strings.Contains(stderr.String(), message) {
+					t.Errorf("expected stderr to contain %q, but got:\n%s", message, stderr.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRun_DryRun(t *testing.T) {
+	t.Parallel()
+
+	tests := []cliTestCase{
+		{
+			name: "directory as a table",
+			args: []string{"", "--dry-run", "./fixtures/locks-many"},
+			exit: 0,
+		},
+		{
+			name: "lockfile and docker image as json",
+			args: []string{"", "--dry-run", "--format", "json", "--docker", "debian:bookworm", "-L", "./fixtures/locks-many/composer.lock"},
+			exit: 0,
+		},
+		{
+			name: "only some packages as markdown",
+			args: []string{"", "--dry-run", "--format", "markdown", "--only-packages", "npm:balanced-match", "./fixtures/locks-many"},
+			exit: 0,
+		},
+		{
+			name: "no packages",
+			args: []string{"", "--dry-run", "./fixtures/locks-empty"},
+			exit: 128,
+		},
+		{
+			name: "unsupported format",
+			args: []string{"", "--dry-run", "--format", "sarif", "./fixtures/locks-many"},
+			exit: 127,
+		},
+		{
+			name: "output directory",
+			args: []string{"", "--dry-run", "--output-dir", "results", "./fixtures/locks-many"},
+			exit: 127,
+		},
+		{
+			name: "several outputs",
+			args: []string{"", "--dry-run", "--output", "results.json:json", "--output", "results.md:markdown", "./fixtures/locks-many"},
+			exit: 127,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			testCli(t, tt)
+		})
+	}
+}
+
+func TestRun_Summary(t *testing.T) {
+	t.Parallel()
+
+	tests := []cliTestCase{
+		{
+			name: "with a format that is not human-readable",
+			args: []string{"", "--summary", "--format", "json", "./fixtures/locks-many/composer.lock"},
+			exit: 127,
+		},
+		{
+			name: "with serve",
+			args: []string{"", "--summary", "--serve", "./fixtures/locks-many/composer.lock"},
+			exit: 127,
+		},
+		{
+			name: "with dry run",
+			args: []string{"", "--summary", "--dry-run", "./fixtures/locks-many/composer.lock"},
+			exit: 127,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			testCli(t, tt)
+		})
+	}
+}
+
+func TestRun_FailOnUnknownLicense(t *testing.T) {
+	t.Parallel()
+
+	tests := []cliTestCase{
+		{
+			name: "without scanning licenses",
+			args: []string{"", "--fail-on-unknown-license", "./fixtures/locks-many/composer.lock"},
+			exit: 127,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			testCli(t, tt)
+		})
+	}
+}
+
+func TestRun_FailOnLicense(t *testing.T) {
+	t.Parallel()
+
+	tests := []cliTestCase{
+		{
+			name: "without an allowlist",
+			args: []string{"", "--fail-on-license", "warn", "./fixtures/locks-many/composer.lock"},
+			exit: 127,
+		},
+		{
+			name: "unknown mode",
+			args: []string{"", "--fail-on-license", "ignore", "--experimental-licenses", "MIT", "./fixtures/locks-many/composer.lock"},
+			exit: 127,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			testCli(t, tt)
+		})
+	}
+}
+
+func TestRun_ParsersDir(t *testing.T) {
+	t.Parallel()
+
+	tests := []cliTestCase{
+		{
+			name: "directory does not exist",
+			args: []string{"", "--experimental-parsers-dir", "./fixtures/does-not-exist", "./fixtures/locks-many/composer.lock"},
+			exit: 127,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			testCli(t, tt)
+		})
+	}
+}
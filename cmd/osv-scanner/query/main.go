@@ -0,0 +1,221 @@
+package query
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/osv-scanner/internal/tlsconfig"
+	"github.com/google/osv-scanner/pkg/config"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osvscanner"
+	"github.com/google/osv-scanner/pkg/reporter"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/term"
+)
+
+func Command(stdout, stderr io.Writer, r *reporter.Reporter) *cli.Command {
+	return &cli.Command{
+		Name:        "query",
+		Usage:       "checks versions of packages against the OSV database, without a manifest",
+		Description: "each package is given as ecosystem:name@version (e.g. npm:lodash@4.17.19), or read from stdin one per line if none are given or the argument is -",
+		ArgsUsage:   "[ecosystem:name@version...]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "format",
+				Aliases: []string{"f"},
+				Usage:   "sets the output format; value can be: " + strings.Join(reporter.Format(), ", "),
+				Value:   "table",
+				Action: func(context *cli.Context, s string) error {
+					if slices.Contains(reporter.Format(), s) {
+						return nil
+					}
+
+					return fmt.Errorf("unsupported output format \"%s\" - must be one of: %s", s, strings.Join(reporter.Format(), ", "))
+				},
+			},
+			&cli.StringFlag{
+				Name:      "output",
+				Usage:     "saves the result to the given file path",
+				TakesFile: true,
+			},
+			&cli.StringFlag{
+				Name:      "ca-cert",
+				Usage:     "path to a PEM encoded certificate to trust in addition to the system roots when making network requests",
+				TakesFile: true,
+			},
+			&cli.BoolFlag{
+				Name:  "insecure-skip-tls",
+				Usage: "DANGEROUS: disables TLS certificate verification for all network requests, making them vulnerable to interception",
+			},
+			&cli.StringFlag{
+				Name:  "verbosity",
+				Usage: "specify the level of information that should be provided during runtime; value can be: " + strings.Join(reporter.VerbosityLevels(), ", "),
+				Value: "info",
+			},
+			&cli.StringFlag{
+				Name:  "log-format",
+				Usage: "sets the format of runtime information, with json printing it to stderr as JSON lines separately from the results; value can be: " + strings.Join(reporter.LogFormats(), ", "),
+				Value: "text",
+				Action: func(context *cli.Context, s string) error {
+					if slices.Contains(reporter.LogFormats(), s) {
+						return nil
+					}
+
+					return fmt.Errorf("unsupported log format \"%s\" - must be one of: %s", s, strings.Join(reporter.LogFormats(), ", "))
+				},
+			},
+			&cli.BoolFlag{
+				Name:  "no-cache",
+				Usage: "do not read or write the cache of query results",
+			},
+			&cli.StringFlag{
+				Name:      "cache-dir",
+				Usage:     "sets the directory that query results and local databases are cached in",
+				EnvVars:   []string{"OSV_SCANNER_CACHE_DIR"},
+				TakesFile: true,
+			},
+			&cli.DurationFlag{
+				Name:  "experimental-query-cache-ttl",
+				Usage: "keeps using cached query results for this long, such as 24h, even after the database of their ecosystem is updated",
+				Action: func(context *cli.Context, d time.Duration) error {
+					if d < 0 {
+						return errors.New("--experimental-query-cache-ttl cannot be negative")
+					}
+
+					return nil
+				},
+			},
+			&cli.BoolFlag{
+				Name:  "experimental-local-db",
+				Usage: "checks for vulnerabilities using local databases",
+			},
+			&cli.BoolFlag{
+				Name:  "experimental-offline",
+				Usage: "checks for vulnerabilities using local databases that are already cached",
+			},
+			&cli.StringFlag{
+				Name:   "experimental-local-db-path",
+				Usage:  "sets the path that local databases should be stored",
+				Hidden: true,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			var err error
+			*r, err = action(c, stdout, stderr)
+
+			return err
+		},
+	}
+}
+
+// readCoordinates reads coordinates with one on each line,
+// skipping blank lines and lines starting with "#"
+func readCoordinates(reader io.Reader) ([]string, error) {
+	var coordinates []string
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		coordinates = append(coordinates, line)
+	}
+
+	return coordinates, scanner.Err()
+}
+
+func action(context *cli.Context, stdout, stderr io.Writer) (reporter.Reporter, error) {
+	if err := tlsconfig.Configure(context.String("ca-cert"), context.Bool("insecure-skip-tls")); err != nil {
+		return nil, err
+	}
+
+	coordinates := context.Args().Slice()
+	if len(coordinates) == 0 || (len(coordinates) == 1 && coordinates[0] == "-") {
+		var err error
+		coordinates, err = readCoordinates(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read packages from stdin: %w", err)
+		}
+	}
+
+	if len(coordinates) == 0 {
+		return nil, errors.New("query requires at least one package in the form of ecosystem:name@version")
+	}
+
+	// make sure every coordinate is valid before anything is queried
+	for _, coordinate := range coordinates {
+		if _, err := osvscanner.ParsePackageCoordinate(coordinate); err != nil {
+			return nil, err
+		}
+	}
+
+	outputPath := context.String("output")
+
+	termWidth := 0
+	var err error
+	if outputPath != "" { // Output is definitely a file
+		stdout, err = os.Create(outputPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create output file: %w", err)
+		}
+	} else { // Output might be a terminal
+		if stdoutAsFile, ok := stdout.(*os.File); ok {
+			termWidth, _, err = term.GetSize(int(stdoutAsFile.Fd()))
+			if err != nil { // If output is not a terminal,
+				termWidth = 0
+			}
+		}
+	}
+
+	verbosityLevel, err := reporter.ParseVerbosityLevel(context.String("verbosity"))
+	if err != nil {
+		return nil, err
+	}
+	r, err := reporter.New(context.String("format"), stdout, stderr, verbosityLevel, termWidth)
+	if err != nil {
+		return r, err
+	}
+	if context.String("log-format") == "json" {
+		r = reporter.NewJSONLogReporter(r, stderr, verbosityLevel)
+	}
+
+	envConfig, err := config.LoadEnv(os.LookupEnv)
+	if err != nil {
+		return r, err
+	}
+
+	vulnResult, err := osvscanner.DoScanWithContext(context.Context, osvscanner.ScannerActions{
+		PackageCoordinates: coordinates,
+		NoCache:            context.Bool("no-cache"),
+		CacheDir:           context.String("cache-dir"),
+		EnvConfig:          envConfig,
+		ExperimentalScannerActions: osvscanner.ExperimentalScannerActions{
+			LocalDBPath:    context.String("experimental-local-db-path"),
+			QueryCacheTTL:  context.Duration("experimental-query-cache-ttl"),
+			CompareLocally: context.Bool("experimental-local-db"),
+			CompareOffline: context.Bool("experimental-offline"),
+		},
+	}, r)
+	if err != nil && !errors.Is(err, osvscanner.VulnerabilitiesFoundErr) {
+		return r, err
+	}
+
+	if vulnResult.Results == nil {
+		// Want 0 vulnerabilities to show in JSON as an empty list, not null.
+		vulnResult.Results = []models.PackageSource{}
+	}
+
+	if errPrint := r.PrintResult(&vulnResult); errPrint != nil {
+		return r, fmt.Errorf("failed to write output: %w", errPrint)
+	}
+
+	// This may be nil.
+	return r, err
+}
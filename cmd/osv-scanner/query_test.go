@@ -0,0 +1,51 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/google/osv-scanner/internal/testutility"
+)
+
+func TestRun_Query(t *testing.T) {
+	t.Parallel()
+
+	tests := []cliTestCase{
+		{
+			name: "missing version",
+			args: []string{"", "query", "npm:lodash"},
+			exit: 127,
+		},
+		{
+			name: "unknown ecosystem",
+			args: []string{"", "query", "npm:lodash@4.17.19", "not-an-ecosystem:lodash@4.17.19"},
+			exit: 127,
+		},
+		{
+			name: "unsupported format",
+			args: []string{"", "query", "--format", "unknown", "npm:lodash@4.17.19"},
+			exit: 127,
+		},
+		{
+			name: "unsupported log format",
+			args: []string{"", "query", "--log-format", "xml", "npm:lodash@4.17.19"},
+			exit: 127,
+		},
+		{
+			name: "ca certificate that does not exist",
+			args: []string{"", "query", "--ca-cert", "./fixtures/does-not-exist.pem", "npm:lodash@4.17.19"},
+			exit: 127,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			stdout, stderr := runCli(t, tt)
+
+			testutility.NewSnapshot().MatchText(t, stdout)
+			testutility.NewSnapshot().MatchText(t, stderr)
+		})
+	}
+}
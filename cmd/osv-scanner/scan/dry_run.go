@@ -0,0 +1,35 @@
+package scan
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/pkg/osvscanner"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+// dryRunFormats are the formats that the targets of a dry run can be printed in
+var dryRunFormats = []string{"table", "markdown", "json"}
+
+// printScanTargets prints what would be scanned with the actions in the format,
+// without scanning any of it for vulnerabilities
+func printScanTargets(ctx context.Context, r reporter.Reporter, actions osvscanner.ScannerActions, format string, stdout io.Writer, termWidth int) error {
+	targets, err := osvscanner.ListScanTargetsWithContext(ctx, actions, r)
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		if err := output.PrintScanTargetsJSON(targets, stdout); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+
+		return nil
+	}
+
+	output.PrintScanTargetsTable(targets, stdout, format == "markdown", termWidth)
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package scan
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+// notifyInterrupted returns a copy of ctx that is cancelled when the process is interrupted,
+// so that the scan stops and the results found so far are still reported.
+//
+// The signals are no longer caught once the scan is interrupted, so interrupting it
+// again exits straight away, such as if the results are taking too long to output.
+func notifyInterrupted(ctx context.Context, r reporter.Reporter) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case <-signals:
+			signal.Stop(signals)
+			r.Warnf("Interrupted, stopping the scan and reporting the results found so far (interrupt again to exit immediately)\n")
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, func() {
+		signal.Stop(signals)
+		cancel()
+	}
+}
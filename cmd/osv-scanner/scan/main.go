@@ -1,13 +1,27 @@
 package scan
 
 import (
+	stdcontext "context"
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
 	"slices"
 	"strings"
+	"time"
 
+	"github.com/google/osv-scanner/internal/cachedir"
+	"github.com/google/osv-scanner/internal/ci"
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/internal/tlsconfig"
+	"github.com/google/osv-scanner/internal/utility/severity"
+	"github.com/google/osv-scanner/pkg/config"
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/osv"
 	"github.com/google/osv-scanner/pkg/osvscanner"
 	"github.com/google/osv-scanner/pkg/reporter"
 	"github.com/google/osv-scanner/pkg/spdx"
@@ -40,33 +54,155 @@ func Command(stdout, stderr io.Writer, r *reporter.Reporter) *cli.Command {
 				Usage:     "scan sbom file on this path",
 				TakesFile: true,
 			},
+			&cli.StringSliceFlag{
+				Name:      "dockerfile",
+				Usage:     "scan the packages pinned in the Dockerfile on this path, without building it",
+				TakesFile: true,
+			},
+			&cli.StringSliceFlag{
+				Name:      "purls",
+				Usage:     "scan the packages in the file on this path, which has a package URL (purl) on each line",
+				TakesFile: true,
+			},
 			&cli.StringFlag{
 				Name:      "config",
 				Usage:     "set/override config file",
 				TakesFile: true,
 			},
 			&cli.StringFlag{
+				Name:      "validate-config",
+				Usage:     "validate the config file on this path and exit without scanning",
+				TakesFile: true,
+			},
+			&cli.BoolFlag{
+				Name:  "version-details",
+				Usage: "report the version of osv-scanner and how fresh the vulnerability data is, then exit without scanning",
+			},
+			&cli.DurationFlag{
+				Name:  "max-db-age",
+				Usage: "warn about local databases with data older than this (e.g. 168h) when using --version-details",
+			},
+			&cli.StringSliceFlag{
 				Name:    "format",
 				Aliases: []string{"f"},
-				Usage:   "sets the output format; value can be: " + strings.Join(reporter.Format(), ", "),
-				Value:   "table",
-				Action: func(context *cli.Context, s string) error {
-					if slices.Contains(reporter.Format(), s) {
-						return nil
+				Usage:   "sets the output format, which can be repeated when using --output-dir; value can be: " + strings.Join(reporter.Format(), ", "),
+				Value:   cli.NewStringSlice("table"),
+				Action: func(context *cli.Context, ss []string) error {
+					for _, s := range ss {
+						if !slices.Contains(reporter.Format(), s) {
+							return fmt.Errorf("unsupported output format \"%s\" - must be one of: %s", s, strings.Join(reporter.Format(), ", "))
+						}
 					}
 
-					return fmt.Errorf("unsupported output format \"%s\" - must be one of: %s", s, strings.Join(reporter.Format(), ", "))
+					return nil
 				},
 			},
 			&cli.BoolFlag{
 				Name:  "json",
 				Usage: "sets output to json (deprecated, use --format json instead)",
 			},
+			&cli.StringSliceFlag{
+				Name: "output",
+				Usage: "saves the result to the given file path instead of printing it, in the given format if the path ends with :format " +
+					"(e.g. results.sarif:sarif); when repeated or given a format, the result in --format is still printed to the console",
+				TakesFile: true,
+			},
+			&cli.StringFlag{
+				Name:      "output-dir",
+				Usage:     "saves the result in each of the given formats to a file in the given directory, named after the format",
+				TakesFile: true,
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "list the files that would be scanned and the ecosystems of their packages, without querying for vulnerabilities",
+			},
+			&cli.BoolFlag{
+				Name:  "report-include-fixed",
+				Usage: "include the versions that fix each vulnerability in the results",
+				Value: true,
+			},
+			&cli.BoolFlag{
+				Name:  "summary",
+				Usage: "only print the totals of the findings and the most severe vulnerabilities to the console, while --output or --output-dir still saves the full result",
+			},
+			&cli.IntFlag{
+				Name:  "summary-top",
+				Usage: "the number of the most severe vulnerabilities to print when using --summary",
+				Value: 10,
+			},
+			&cli.BoolFlag{
+				Name:  "serve",
+				Usage: "serves the result as a self-contained HTML report on the --serve-address once the scan finishes",
+			},
 			&cli.StringFlag{
-				Name:      "output",
-				Usage:     "saves the result to the given file path",
+				Name:  "serve-address",
+				Usage: "the host and port to serve the html report on, such as 0.0.0.0:8000 to make it reachable from other machines",
+				Value: defaultServeAddress,
+			},
+			&cli.StringFlag{
+				Name:      "serve-tls-cert",
+				Usage:     "serves the html report over https with this certificate, which requires --serve-tls-key",
+				TakesFile: true,
+			},
+			&cli.StringFlag{
+				Name:      "serve-tls-key",
+				Usage:     "the private key of the --serve-tls-cert certificate",
 				TakesFile: true,
 			},
+			&cli.DurationFlag{
+				Name:  "serve-rescan-interval",
+				Usage: "when serving the html report, re-runs the scan this often (such as 30s) so that the report updates while fixing vulnerabilities",
+			},
+			&cli.StringFlag{
+				Name:  "html-title",
+				Usage: "sets the title of the html report, such as the name of the project or team",
+			},
+			&cli.StringFlag{
+				Name:  "html-header",
+				Usage: "sets a blurb to show under the title of the html report",
+			},
+			&cli.BoolFlag{
+				Name:  "dedupe-advisories",
+				Usage: "in the table and markdown formats, list each advisory once with all the packages it affects, rather than once for each package",
+			},
+			&cli.StringSliceFlag{
+				Name:  "output-columns",
+				Usage: "sets the columns of the csv format and their order, which defaults to all of them; value can be: " + strings.Join(reporter.CSVColumns(), ", "),
+				Action: func(_ *cli.Context, columns []string) error {
+					for _, column := range columns {
+						if !slices.Contains(reporter.CSVColumns(), column) {
+							return fmt.Errorf("unsupported output column \"%s\" - must be one of: %s", column, strings.Join(reporter.CSVColumns(), ", "))
+						}
+					}
+
+					return nil
+				},
+			},
+			&cli.StringFlag{
+				Name:  "redact-packages",
+				Usage: "replaces the names of packages matching this regular expression with a token derived from the name in the result, such as for sharing it externally",
+				Action: func(_ *cli.Context, pattern string) error {
+					if _, err := regexp.Compile(pattern); err != nil {
+						return fmt.Errorf("--redact-packages %q is not a valid regular expression: %w", pattern, err)
+					}
+
+					return nil
+				},
+			},
+			&cli.StringFlag{
+				Name:  "output-url",
+				Usage: "sends the result in JSON format to the given URL as a POST request when the scan finishes",
+			},
+			&cli.StringFlag{
+				Name:    "output-header",
+				Usage:   "sets a header (e.g. \"Authorization: Bearer <token>\") to send with the result to --output-url",
+				EnvVars: []string{"OSV_SCANNER_OUTPUT_HEADER"},
+			},
+			&cli.DurationFlag{
+				Name:  "output-url-timeout",
+				Usage: "how long to wait for --output-url to accept the result before failing",
+				Value: 30 * time.Second,
+			},
 			&cli.BoolFlag{
 				Name:  "skip-git",
 				Usage: "skip scanning git repositories",
@@ -78,6 +214,109 @@ func Command(stdout, stderr io.Writer, r *reporter.Reporter) *cli.Command {
 				Usage:   "check subdirectories",
 				Value:   false,
 			},
+			&cli.BoolFlag{
+				Name:  "no-cache",
+				Usage: "always query OSV instead of reusing results from previous scans",
+				Value: false,
+			},
+			&cli.StringFlag{
+				Name:      "cache-dir",
+				Usage:     "directory to store the query cache and local databases in, defaulting to the osv-scanner directory within the user cache directory",
+				TakesFile: true,
+			},
+			&cli.DurationFlag{
+				Name:  "experimental-query-cache-ttl",
+				Usage: "keeps using cached query results for this long, such as 24h, even after the database of their ecosystem is updated",
+				Action: func(context *cli.Context, d time.Duration) error {
+					if d < 0 {
+						return errors.New("--experimental-query-cache-ttl cannot be negative")
+					}
+
+					return nil
+				},
+			},
+			&cli.BoolFlag{
+				Name:  "clear-cache",
+				Usage: "remove everything cached in the cache directory before scanning, exiting afterwards if there is nothing to scan",
+			},
+			&cli.StringFlag{
+				Name:  "git-ref",
+				Usage: "scan the lockfiles in the given directories as they exist in their git repository at this ref, without checking it out",
+			},
+			&cli.StringFlag{
+				Name:      "changed-files",
+				Usage:     "only scan the lockfiles and manifests in the given directories that are listed in this file, such as the output of git diff --name-only, or - to read the list from stdin",
+				TakesFile: true,
+			},
+			&cli.StringSliceFlag{
+				Name:  "include",
+				Usage: "only scan the paths in the given directories that match this glob (e.g. \"services/**\"), relative to the directory; can be repeated",
+			},
+			&cli.StringSliceFlag{
+				Name:  "exclude",
+				Usage: "do not scan the paths in the given directories that match this glob (e.g. \"**/testdata\"), relative to the directory, even if they are included; can be repeated",
+			},
+			&cli.BoolFlag{
+				Name:  "no-progress",
+				Usage: "do not show the progress of long-running operations when outputting to a terminal",
+				Value: false,
+			},
+			&cli.DurationFlag{
+				Name:  "timeout",
+				Usage: "stop the scan if it takes longer than this (e.g. 10m), reporting any results found before then",
+				Action: func(_ *cli.Context, d time.Duration) error {
+					if d < 0 {
+						return errors.New("--timeout cannot be negative")
+					}
+
+					return nil
+				},
+			},
+			&cli.Float64Flag{
+				Name:  "rate-limit",
+				Usage: "limit requests to the OSV API to this many per second",
+				Action: func(context *cli.Context, f float64) error {
+					if f < 0 {
+						return errors.New("--rate-limit cannot be negative")
+					}
+
+					return nil
+				},
+			},
+			&cli.IntFlag{
+				Name:  "concurrency",
+				Usage: "the most requests to make to the OSV API at once, which is reduced while requests are being rate limited; defaults to 25",
+				Action: func(context *cli.Context, i int) error {
+					if i < 0 {
+						return errors.New("--concurrency cannot be negative")
+					}
+
+					return nil
+				},
+			},
+			&cli.BoolFlag{
+				Name:  "manifest-only",
+				Usage: "scan the manifests in the given directories instead of their lockfiles, resolving each requirement to the latest matching version",
+				Value: false,
+			},
+			&cli.BoolFlag{
+				Name:  "strict-resolve",
+				Usage: "when using --manifest-only, fail the scan if any requirement cannot be resolved instead of skipping it",
+			},
+			&cli.StringSliceFlag{
+				Name:    "maven-registry",
+				Usage:   "when using --manifest-only, fetch Maven parent POMs and BOMs from this registry instead of Maven Central, which can be repeated to try each registry in order",
+				EnvVars: []string{"OSV_SCANNER_MAVEN_REGISTRY"},
+				Action: func(_ *cli.Context, registries []string) error {
+					for _, registry := range registries {
+						if u, err := url.Parse(registry); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+							return fmt.Errorf("--maven-registry %q is not a valid http or https URL", registry)
+						}
+					}
+
+					return nil
+				},
+			},
 			&cli.BoolFlag{
 				Name:  "experimental-call-analysis",
 				Usage: "[Deprecated] attempt call analysis on code to detect only active vulnerabilities",
@@ -96,11 +335,127 @@ func Command(stdout, stderr io.Writer, r *reporter.Reporter) *cli.Command {
 				Name:  "no-call-analysis",
 				Usage: "disables call graph analysis",
 			},
+			&cli.StringSliceFlag{
+				Name:      "vex",
+				Usage:     "suppresses the vulnerabilities that the statements of this OpenVEX document say packages are not affected by or have fixed; can be repeated",
+				TakesFile: true,
+			},
+			&cli.StringFlag{
+				Name:      "baseline",
+				Usage:     "suppresses the findings recorded in this baseline file, which is created with the findings of the scan if it does not exist",
+				TakesFile: true,
+			},
+			&cli.StringFlag{
+				Name:      "diff-with",
+				Usage:     "compares the results against previously saved json results, only outputting and exiting non-zero for the findings that are new",
+				TakesFile: true,
+			},
+			&cli.IntFlag{
+				Name:  "exit-code",
+				Usage: "exit with this code when the scan completes, regardless of any vulnerabilities or license violations found",
+				Action: func(_ *cli.Context, code int) error {
+					if code < 0 || code > 255 {
+						return fmt.Errorf("--exit-code must be between 0 and 255, got %d", code)
+					}
+
+					return nil
+				},
+			},
+			&cli.StringFlag{
+				Name:  "since",
+				Usage: "only report vulnerabilities published or modified since this RFC3339 timestamp or duration ago (e.g. 168h)",
+			},
+			&cli.BoolFlag{
+				Name:  "show-paths",
+				Usage: "show the paths from a direct dependency down to each vulnerable package, for lockfiles that record them (currently package-lock.json)",
+			},
+			&cli.StringSliceFlag{
+				Name:  "only-packages",
+				Usage: "only scan the packages matching these comma-separated ecosystem:name selectors, and their dependencies",
+				Action: func(_ *cli.Context, selectors []string) error {
+					for _, selector := range selectors {
+						if _, err := osvscanner.ParsePackageSelector(selector); err != nil {
+							return fmt.Errorf("--only-packages: %w", err)
+						}
+					}
+
+					return nil
+				},
+			},
+			&cli.StringFlag{
+				Name:  "cvss-version",
+				Usage: "the CVSS version (2, 3 or 4) whose scores are used for the severity of vulnerabilities that have several, falling back to the newest available; defaults to the highest score of any version",
+				Action: func(_ *cli.Context, version string) error {
+					if _, err := severity.ParseVersion(version); err != nil {
+						return fmt.Errorf("--cvss-version: %w", err)
+					}
+
+					return nil
+				},
+			},
+			&cli.StringFlag{
+				Name:  "fail-on-severity",
+				Usage: "only exit with a non-zero code for vulnerabilities with at least this severity rating (low, medium, high or critical, or one of the ratings of --severity-mapping), ignoring those without a score",
+			},
+			&cli.StringSliceFlag{
+				Name:  "severity-mapping",
+				Usage: "rate severity scores with these comma-separated rating:minimum thresholds, such as Low:0,Moderate:5,Severe:8, instead of the ratings defined by CVSS",
+				Action: func(_ *cli.Context, thresholds []string) error {
+					if _, err := severity.ParseMapping(thresholds); err != nil {
+						return fmt.Errorf("--severity-mapping: %w", err)
+					}
+
+					return nil
+				},
+			},
+			&cli.BoolFlag{
+				Name:  "exclude-dev",
+				Usage: "exclude vulnerabilities in development dependencies, such as npm devDependencies or Maven test scope dependencies",
+			},
+			&cli.BoolFlag{
+				Name:  "include-dev",
+				Usage: "include vulnerabilities in development dependencies (default)",
+			},
+			&cli.StringSliceFlag{
+				Name:      "repository",
+				Usage:     "scan the repository with its root at this path, attributing each source found in it to the repository; may be repeated",
+				TakesFile: true,
+			},
+			&cli.StringFlag{
+				Name:      "repositories-file",
+				Usage:     "scan each repository listed in this file, one root per line, as with --repository",
+				TakesFile: true,
+			},
+			&cli.BoolFlag{
+				Name:  "scope-node-version",
+				Usage: "exclude npm vulnerabilities that are scoped to Node versions other than the one pinned by .nvmrc or engines.node",
+			},
+			&cli.StringFlag{
+				Name:      "ca-cert",
+				Usage:     "path to a PEM encoded certificate to trust in addition to the system roots when making network requests",
+				TakesFile: true,
+			},
+			&cli.BoolFlag{
+				Name:  "insecure-skip-tls",
+				Usage: "DANGEROUS: disables TLS certificate verification for all network requests, making them vulnerable to interception",
+			},
 			&cli.StringFlag{
 				Name:  "verbosity",
 				Usage: "specify the level of information that should be provided during runtime; value can be: " + strings.Join(reporter.VerbosityLevels(), ", "),
 				Value: "info",
 			},
+			&cli.StringFlag{
+				Name:  "log-format",
+				Usage: "sets the format of runtime information, with json printing it to stderr as JSON lines separately from the results; value can be: " + strings.Join(reporter.LogFormats(), ", "),
+				Value: "text",
+				Action: func(context *cli.Context, s string) error {
+					if slices.Contains(reporter.LogFormats(), s) {
+						return nil
+					}
+
+					return fmt.Errorf("unsupported log format \"%s\" - must be one of: %s", s, strings.Join(reporter.LogFormats(), ", "))
+				},
+			},
 			&cli.BoolFlag{
 				Name:  "experimental-local-db",
 				Usage: "checks for vulnerabilities using local databases",
@@ -114,17 +469,69 @@ func Command(stdout, stderr io.Writer, r *reporter.Reporter) *cli.Command {
 				Usage:  "sets the path that local databases should be stored",
 				Hidden: true,
 			},
+			&cli.StringFlag{
+				Name:  "experimental-local-db-mirror",
+				Usage: "sets the base URL that local databases should be downloaded from",
+			},
+			&cli.StringFlag{
+				Name:    "experimental-local-db-mirror-header",
+				Usage:   "sets a header (e.g. \"Authorization: Bearer <token>\") to send when downloading local databases",
+				EnvVars: []string{"OSV_SCANNER_LOCAL_DB_MIRROR_HEADER"},
+			},
+			&cli.StringFlag{
+				Name:      "experimental-local-db-signature-key",
+				Usage:     "only uses local databases signed by the given PEM encoded public key, with signatures made by \"cosign sign-blob --key\" next to each archive (keyless signatures and Sigstore bundles are not supported)",
+				TakesFile: true,
+			},
+			&cli.StringSliceFlag{
+				Name:  "experimental-advisory-source",
+				Usage: "also downloads local databases from the given advisory source, as [osv:]<url> for an OSV format feed laid out like the OSV bucket or ghsa:<url> for a zip archive of a GitHub Advisory Database mirror",
+			},
+			&cli.BoolFlag{
+				Name:  "experimental-download-all-ecosystems",
+				Usage: "downloads the local database of every ecosystem with --experimental-local-db, rather than only those of the packages being scanned",
+			},
+			&cli.BoolFlag{
+				Name:  "experimental-exclude-inactive-python-packages",
+				Usage: "excludes Python packages whose environment markers are not satisfied by the Python interpreter on the PATH",
+			},
+			&cli.BoolFlag{
+				Name:  "experimental-yanked",
+				Usage: "checks crates.io and PyPI for package versions that have been yanked",
+			},
 			&cli.BoolFlag{
 				Name:  "experimental-all-packages",
-				Usage: "when json output is selected, prints all packages",
+				Usage: "when json or junit output is selected, prints all packages (spdx-2-3 output always does)",
 			},
 			&cli.BoolFlag{
 				Name:  "experimental-licenses-summary",
 				Usage: "report a license summary, implying the --experimental-all-packages flag",
 			},
 			&cli.StringSliceFlag{
-				Name:  "experimental-licenses",
-				Usage: "report on licenses based on an allowlist",
+				Name:    "experimental-licenses",
+				Usage:   "report on licenses based on an allowlist",
+				EnvVars: []string{"OSV_SCANNER_LICENSES"},
+			},
+			&cli.StringFlag{
+				Name:  "fail-on-license",
+				Usage: "whether license violations fail the scan (error) or are only reported (warn); value can be: error, warn",
+				Value: "error",
+				Action: func(_ *cli.Context, mode string) error {
+					if mode != "error" && mode != "warn" {
+						return fmt.Errorf("--fail-on-license must be one of: error, warn, not %q", mode)
+					}
+
+					return nil
+				},
+			},
+			&cli.BoolFlag{
+				Name:  "fail-on-unknown-license",
+				Usage: "exit with a non-zero code if the license of any package could not be determined, requiring --experimental-licenses-summary or --experimental-licenses",
+			},
+			&cli.StringFlag{
+				Name:      "experimental-parsers-dir",
+				Usage:     "directory of executables that extract packages from formats that are not supported, see the docs for the protocol they follow",
+				TakesFile: true,
 			},
 			&cli.StringFlag{
 				Name:      "experimental-oci-image",
@@ -144,28 +551,102 @@ func Command(stdout, stderr io.Writer, r *reporter.Reporter) *cli.Command {
 }
 
 func action(context *cli.Context, stdout, stderr io.Writer) (reporter.Reporter, error) {
-	format := context.String("format")
+	if err := tlsconfig.Configure(context.String("ca-cert"), context.Bool("insecure-skip-tls")); err != nil {
+		return nil, err
+	}
+
+	formats := context.StringSlice("format")
 
 	if context.Bool("json") {
-		format = "json"
+		formats = []string{"json"}
+	}
+
+	// the result is saved to outputPath instead of being printed, or to each of the
+	// outputs as well as being printed when there are several or they have formats
+	var outputPath string
+	outputs := parseOutputs(context.StringSlice("output"), formats[0])
+	if len(outputs) == 1 && !outputs[0].explicit {
+		outputPath = outputs[0].path
+		outputs = nil
 	}
+	outputDir := context.String("output-dir")
 
-	outputPath := context.String("output")
+	if len(context.StringSlice("output")) > 0 && outputDir != "" {
+		return nil, errors.New("--output and --output-dir flags cannot both be set")
+	}
+	if outputDir == "" && len(formats) > 1 {
+		return nil, errors.New("--format can only be given more than once when using --output-dir")
+	}
+
+	if context.Bool("dry-run") {
+		if outputDir != "" || len(outputs) > 0 || context.Bool("serve") || context.IsSet("output-url") {
+			return nil, errors.New("--dry-run cannot be used with --output-dir, --serve, --output-url, or --output more than once or with a format")
+		}
+		if !slices.Contains(dryRunFormats, formats[0]) {
+			return nil, fmt.Errorf("--dry-run can only be used with --format %s", strings.Join(dryRunFormats, ", "))
+		}
+	}
+
+	summary := context.Bool("summary")
+	if summary {
+		if context.Bool("serve") || context.Bool("dry-run") {
+			return nil, errors.New("--summary cannot be used with --serve or --dry-run")
+		}
+		// without anywhere else to save the result, the summary replaces the output of the format
+		if outputPath == "" && outputDir == "" && len(outputs) == 0 && !slices.Contains(summaryFormats, formats[0]) {
+			return nil, fmt.Errorf("--summary can only be used with --format %s unless the result is saved with --output or --output-dir", strings.Join(summaryFormats, ", "))
+		}
+	}
+
+	// the console is where the summary is printed, even if the result is saved to a file
+	console := stdout
+	consoleWidth := terminalWidth(stdout)
 
 	termWidth := 0
 	var err error
-	if outputPath != "" { // Output is definitely a file
+	var servePath string
+	serveOptions := ServeOptions{
+		Address:        context.String("serve-address"),
+		TLSCertFile:    context.String("serve-tls-cert"),
+		TLSKeyFile:     context.String("serve-tls-key"),
+		RescanInterval: context.Duration("serve-rescan-interval"),
+	}
+	if !context.Bool("serve") {
+		for _, flag := range []string{"serve-address", "serve-tls-cert", "serve-tls-key", "serve-rescan-interval"} {
+			if context.IsSet(flag) {
+				return nil, fmt.Errorf("--%s can only be used with --serve", flag)
+			}
+		}
+	}
+	if context.Bool("serve") {
+		if len(context.StringSlice("output")) > 0 || outputDir != "" {
+			return nil, errors.New("--serve cannot be used with --output or --output-dir")
+		}
+		if context.IsSet("format") && formats[0] != "html" {
+			return nil, errors.New("--serve can only be used with --format html")
+		}
+		formats = []string{"html"}
+
+		if err := validateServeOptions(serveOptions); err != nil {
+			return nil, err
+		}
+
+		reportFile, err := os.CreateTemp("", "osv-scanner-*.html")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create html report: %w", err)
+		}
+		defer os.Remove(reportFile.Name())
+		defer reportFile.Close()
+
+		servePath = reportFile.Name()
+		stdout = reportFile
+	} else if outputPath != "" { // Output is definitely a file
 		stdout, err = os.Create(outputPath)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create output file: %w", err)
 		}
 	} else { // Output might be a terminal
-		if stdoutAsFile, ok := stdout.(*os.File); ok {
-			termWidth, _, err = term.GetSize(int(stdoutAsFile.Fd()))
-			if err != nil { // If output is not a terminal,
-				termWidth = 0
-			}
-		}
+		termWidth = consoleWidth
 	}
 
 	if context.Bool("experimental-licenses-summary") && context.IsSet("experimental-licenses") {
@@ -178,7 +659,27 @@ func action(context *cli.Context, stdout, stderr io.Writer) (reporter.Reporter,
 			return nil, errors.New("--experimental-licenses requires at least one value")
 		}
 		if unrecognized := spdx.Unrecognized(allowlist); len(unrecognized) > 0 {
-			return nil, fmt.Errorf("--experimental-licenses requires comma-separated spdx licenses. The following license(s) are not recognized as spdx: %s", strings.Join(unrecognized, ","))
+			return nil, fmt.Errorf("--experimental-licenses requires comma-separated spdx licenses or license expressions. The following license(s) are not recognized as spdx: %s", strings.Join(unrecognized, ","))
+		}
+	}
+
+	if context.Bool("fail-on-unknown-license") && !context.Bool("experimental-licenses-summary") && !context.IsSet("experimental-licenses") {
+		return nil, errors.New("--fail-on-unknown-license requires --experimental-licenses-summary or --experimental-licenses")
+	}
+
+	if context.IsSet("fail-on-license") && !context.IsSet("experimental-licenses") {
+		return nil, errors.New("--fail-on-license requires --experimental-licenses")
+	}
+
+	if context.Bool("exclude-dev") && context.Bool("include-dev") {
+		return nil, errors.New("--exclude-dev and --include-dev flags cannot both be set")
+	}
+
+	var since time.Time
+	if context.IsSet("since") {
+		since, err = parseSince(context.String("since"), time.Now())
+		if err != nil {
+			return nil, err
 		}
 	}
 
@@ -186,11 +687,88 @@ func action(context *cli.Context, stdout, stderr io.Writer) (reporter.Reporter,
 	if err != nil {
 		return nil, err
 	}
-	r, err := reporter.New(format, stdout, stderr, verbosityLevel, termWidth)
+	// the report is stamped with when the scan started, as results are fetched throughout it
+	options := reporter.Options{
+		HTML: reporter.HTMLOptions{
+			Title:     context.String("html-title"),
+			Header:    context.String("html-header"),
+			ScannedAt: time.Now(),
+			Rescan:    servePath != "",
+		},
+		Table: reporter.TableOptions{
+			DedupeAdvisories: context.Bool("dedupe-advisories"),
+		},
+		CSV: reporter.CSVOptions{
+			Columns: context.StringSlice("output-columns"),
+		},
+	}
+
+	var r reporter.Reporter
+	switch {
+	case summary && outputPath == "" && outputDir == "":
+		r = reporter.NewSummaryReporter(console, stderr, verbosityLevel, formats[0] == "markdown", consoleWidth, context.Int("summary-top"))
+	case summary:
+		var results []reporter.Reporter
+		results, err = newFileReporters(outputPath, outputDir, formats, stdout, stderr, verbosityLevel, options)
+		summaryReporter := reporter.NewSummaryReporter(console, stderr, verbosityLevel, false, consoleWidth, context.Int("summary-top"))
+		r = reporter.NewMultiReporter(summaryReporter, append([]reporter.Reporter{summaryReporter}, results...)...)
+	case outputDir != "":
+		r, err = newOutputDirReporter(outputDir, formats, stdout, stderr, verbosityLevel, termWidth, options)
+	default:
+		r, err = reporter.NewWithOptions(formats[0], stdout, stderr, verbosityLevel, termWidth, options)
+	}
 	if err != nil {
 		return r, err
 	}
 
+	if len(outputs) > 0 {
+		results, err := newOutputReporters(outputs, stderr, verbosityLevel, options)
+		if err != nil {
+			return r, err
+		}
+
+		r = reporter.NewMultiReporter(r, append([]reporter.Reporter{r}, results...)...)
+	}
+
+	if outputURL := context.String("output-url"); outputURL != "" {
+		header, err := parseOutputHeader(context.String("output-header"))
+		if err != nil {
+			return r, err
+		}
+
+		r = reporter.NewMultiReporter(r, r, reporter.NewHTTPReporter(outputURL, header, context.Duration("output-url-timeout"), stderr, verbosityLevel))
+	} else if context.IsSet("output-header") {
+		return r, errors.New("--output-header can only be set when using --output-url")
+	}
+
+	if context.String("log-format") == "json" {
+		r = reporter.NewJSONLogReporter(r, stderr, verbosityLevel)
+	}
+
+	// redacting wraps every other reporter, so that no output contains the names
+	if pattern := context.String("redact-packages"); pattern != "" {
+		r = reporter.NewRedactingReporter(r, regexp.MustCompile(pattern))
+	}
+
+	envConfig, err := config.LoadEnv(os.LookupEnv)
+	if err != nil {
+		return r, err
+	}
+
+	if context.IsSet("validate-config") {
+		validateConfig(r, context.String("validate-config"))
+
+		return r, nil
+	}
+
+	if dir := context.String("experimental-parsers-dir"); dir != "" {
+		names, err := lockfile.LoadExternalExtractors(dir)
+		if err != nil {
+			return r, fmt.Errorf("failed to load parsers from %s: %w", dir, err)
+		}
+		r.Verbosef("Loaded %d external %s: %s\n", len(names), output.Form(len(names), "parser", "parsers"), strings.Join(names, ", "))
+	}
+
 	var callAnalysisStates map[string]bool
 	if context.IsSet("experimental-call-analysis") {
 		callAnalysisStates = createCallAnalysisStates([]string{"all"}, context.StringSlice("no-call-analysis"))
@@ -199,9 +777,31 @@ func action(context *cli.Context, stdout, stderr io.Writer) (reporter.Reporter,
 		callAnalysisStates = createCallAnalysisStates(context.StringSlice("call-analysis"), context.StringSlice("no-call-analysis"))
 	}
 
-	vulnResult, err := osvscanner.DoScan(osvscanner.ScannerActions{
+	// the limits apply to every request to the OSV API made by the process, including those of any rescans
+	osv.SetRateLimit(context.Float64("rate-limit"))
+	osv.SetConcurrency(context.Int("concurrency"))
+
+	ctx, stop := notifyInterrupted(context.Context, r)
+	defer stop()
+
+	if timeout := context.Duration("timeout"); timeout > 0 {
+		var cancel stdcontext.CancelFunc
+		ctx, cancel = stdcontext.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	// the fixed versions are needed for the fixed-version column of the csv format, which is one of its defaults,
+	// and for the remediations of the csaf-2-0 format
+	csvColumns := context.StringSlice("output-columns")
+	formatFixedVersions := slices.Contains(formats, "csaf-2-0") ||
+		slices.Contains(formats, "csv") && (len(csvColumns) == 0 || slices.Contains(csvColumns, "fixed-version"))
+
+	actions := osvscanner.ScannerActions{
 		LockfilePaths:        context.StringSlice("lockfile"),
 		SBOMPaths:            context.StringSlice("sbom"),
+		DockerfilePaths:      context.StringSlice("dockerfile"),
+		PURLPaths:            context.StringSlice("purls"),
+		ShowPaths:            context.Bool("show-paths"),
 		DockerContainerNames: context.StringSlice("docker"),
 		Recursive:            context.Bool("recursive"),
 		SkipGit:              context.Bool("skip-git"),
@@ -209,23 +809,92 @@ func action(context *cli.Context, stdout, stderr io.Writer) (reporter.Reporter,
 		ConfigOverridePath:   context.String("config"),
 		DirectoryPaths:       context.Args().Slice(),
 		CallAnalysisStates:   callAnalysisStates,
+		Since:                since,
+		GitRef:               context.String("git-ref"),
+		ChangedFilesPath:     context.String("changed-files"),
+		NoCache:              context.Bool("no-cache"),
+		CacheDir:             context.String("cache-dir"),
+		ReportFixedVersions:  context.Bool("report-include-fixed") || formatFixedVersions,
+		ManifestOnly:         context.Bool("manifest-only"),
+		StrictResolve:        context.Bool("strict-resolve"),
+		MavenRegistries:      context.StringSlice("maven-registry"),
+		OnlyPackages:         context.StringSlice("only-packages"),
+		CVSSVersion:          context.String("cvss-version"),
+		SeverityMapping:      context.StringSlice("severity-mapping"),
+		NoProgress:           context.Bool("no-progress"),
+		ExcludeDev:           context.Bool("exclude-dev"),
+		ScopeNodeVersion:     context.Bool("scope-node-version"),
+		RepositoryPaths:      context.StringSlice("repository"),
+		RepositoriesFilePath: context.String("repositories-file"),
+		IncludePaths:         context.StringSlice("include"),
+		ExcludePaths:         context.StringSlice("exclude"),
+		EnvConfig:            envConfig,
+		BaselinePath:         context.String("baseline"),
+		VEXPaths:             context.StringSlice("vex"),
 		ExperimentalScannerActions: osvscanner.ExperimentalScannerActions{
-			LocalDBPath:    context.String("experimental-local-db-path"),
-			CompareLocally: context.Bool("experimental-local-db"),
-			CompareOffline: context.Bool("experimental-offline"),
+			LocalDBPath:                   context.String("experimental-local-db-path"),
+			QueryCacheTTL:                 context.Duration("experimental-query-cache-ttl"),
+			LocalDBMirrorURL:              context.String("experimental-local-db-mirror"),
+			LocalDBMirrorHeader:           context.String("experimental-local-db-mirror-header"),
+			LocalDBSignatureKey:           context.String("experimental-local-db-signature-key"),
+			DownloadAllEcosystems:         context.Bool("experimental-download-all-ecosystems"),
+			AdvisorySources:               context.StringSlice("experimental-advisory-source"),
+			CompareLocally:                context.Bool("experimental-local-db"),
+			CompareOffline:                context.Bool("experimental-offline"),
+			ExcludeInactivePythonPackages: context.Bool("experimental-exclude-inactive-python-packages"),
+			CheckYanked:                   context.Bool("experimental-yanked"),
 			// License summary mode causes all
 			// packages to appear in the json as
 			// every package has a license - even
-			// if it's just the UNKNOWN license.
+			// if it's just the UNKNOWN license,
+			// and an SBOM has to list every package.
 			ShowAllPackages: context.Bool("experimental-all-packages") ||
-				context.Bool("experimental-licenses-summary"),
-			ScanLicensesSummary:   context.Bool("experimental-licenses-summary"),
-			ScanLicensesAllowlist: context.StringSlice("experimental-licenses"),
-			ScanOCIImage:          context.String("experimental-oci-image"),
+				context.Bool("experimental-licenses-summary") ||
+				slices.Contains(formats, "spdx-2-3"),
+			ScanLicensesSummary:     context.Bool("experimental-licenses-summary"),
+			ScanLicensesAllowlist:   context.StringSlice("experimental-licenses"),
+			ScanOCIImage:            context.String("experimental-oci-image"),
+			FailOnUnknownLicense:    context.Bool("fail-on-unknown-license"),
+			WarnOnLicenseViolations: context.String("fail-on-license") == "warn",
+			FailOnSeverity:          context.String("fail-on-severity"),
 		},
-	}, r)
+	}
+
+	if context.Bool("clear-cache") {
+		if err := cachedir.Clear(actions.CacheDir); err != nil {
+			return r, fmt.Errorf("failed to clear cache: %w", err)
+		}
+		r.Infof("Cleared the cache in %s\n", cachedir.Dir(actions.CacheDir))
+
+		if !hasScanTargets(actions) {
+			return r, nil
+		}
+	}
+
+	if context.Bool("version-details") {
+		return r, printVersionDetails(r, actions, context.Duration("max-db-age"), time.Now())
+	}
+
+	if context.Bool("dry-run") {
+		return r, printScanTargets(ctx, r, actions, formats[0], stdout, termWidth)
+	}
+
+	// the previous results are loaded before scanning so that a mistyped path does not waste a scan
+	if diffWith := context.String("diff-with"); diffWith != "" {
+		loaded, errLoad := ci.LoadVulnResults(diffWith)
+		if errLoad != nil {
+			return r, fmt.Errorf("failed to load previous results: %w", errLoad)
+		}
+		actions.PreviousResults = &loaded
+	}
+
+	vulnResult, err := osvscanner.DoScanWithContext(ctx, actions, r)
+	// interrupts only stop the scan, so that they stop the server as usual when serving the report
+	stop()
 
-	if err != nil && !errors.Is(err, osvscanner.VulnerabilitiesFoundErr) {
+	// the results found before the scan timed out or was interrupted are still output, as they may be useful
+	incomplete := errors.Is(err, osvscanner.ErrTimedOut) || errors.Is(err, osvscanner.ErrInterrupted)
+	if err != nil && !errors.Is(err, osvscanner.VulnerabilitiesFoundErr) && !incomplete {
 		return r, err
 	}
 
@@ -233,6 +902,213 @@ func action(context *cli.Context, stdout, stderr io.Writer) (reporter.Reporter,
 		return r, fmt.Errorf("failed to write output: %w", errPrint)
 	}
 
+	if incomplete {
+		return r, err
+	}
+
+	if servePath != "" {
+		var redact *regexp.Regexp
+		if pattern := context.String("redact-packages"); pattern != "" {
+			redact = regexp.MustCompile(pattern)
+		}
+
+		rescan := newRescan(actions, options.HTML, stderr, verbosityLevel, redact, context.Duration("timeout"))
+		if errServe := ServeHTML(r, servePath, rescan, serveOptions); errServe != nil {
+			return r, fmt.Errorf("failed to serve html report: %w", errServe)
+		}
+	}
+
+	if context.IsSet("exit-code") {
+		if code := context.Int("exit-code"); code != 0 {
+			return r, ExitCodeError{Code: code}
+		}
+
+		return r, nil
+	}
+
 	// This may be nil.
 	return r, err
 }
+
+// summaryFormats are the formats that --summary can replace the output of
+var summaryFormats = []string{"table", "markdown"}
+
+// terminalWidth returns the width of w if it is a terminal, or 0 if it is not
+func terminalWidth(w io.Writer) int {
+	if f, ok := w.(*os.File); ok {
+		if width, _, err := term.GetSize(int(f.Fd())); err == nil {
+			return width
+		}
+	}
+
+	return 0
+}
+
+// newFileReporters returns the reporters that save the full result when only its summary
+// is printed to the console, which is either the file at outputPath in the first format,
+// or a file for each format in outputDir
+func newFileReporters(outputPath string, outputDir string, formats []string, stdout, stderr io.Writer, level reporter.VerbosityLevel, options reporter.Options) ([]reporter.Reporter, error) {
+	if outputDir == "" {
+		r, err := reporter.NewWithOptions(formats[0], stdout, stderr, level, 0, options)
+		if err != nil {
+			return nil, err
+		}
+
+		return []reporter.Reporter{r}, nil
+	}
+
+	return newOutputDirReporters(outputDir, formats, stderr, level, options)
+}
+
+// newOutputDirReporter creates a reporter that writes the results in each of the formats
+// to a file in outputDir, while runtime information is still printed to stdout and stderr
+func newOutputDirReporter(outputDir string, formats []string, stdout, stderr io.Writer, level reporter.VerbosityLevel, termWidth int, options reporter.Options) (reporter.Reporter, error) {
+	results, err := newOutputDirReporters(outputDir, formats, stderr, level, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return reporter.NewMultiReporter(reporter.NewTableReporter(stdout, stderr, level, false, termWidth), results...), nil
+}
+
+// newOutputDirReporters returns a reporter for each of the formats that saves the result to
+// a file in outputDir named after the format
+func newOutputDirReporters(outputDir string, formats []string, stderr io.Writer, level reporter.VerbosityLevel, options reporter.Options) ([]reporter.Reporter, error) {
+	if err := os.MkdirAll(outputDir, 0750); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	results := make([]reporter.Reporter, 0, len(formats))
+	seen := make(map[string]bool, len(formats))
+
+	for _, format := range formats {
+		if seen[format] {
+			continue
+		}
+		seen[format] = true
+
+		name, err := reporter.FileName(format)
+		if err != nil {
+			return nil, err
+		}
+
+		f, err := os.Create(filepath.Join(outputDir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create output file: %w", err)
+		}
+
+		r, err := reporter.NewWithOptions(format, f, stderr, level, 0, options)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, r)
+	}
+
+	return results, nil
+}
+
+// outputDestination is a file that the result is saved to by --output
+type outputDestination struct {
+	path   string
+	format string
+	// explicit is whether the format was given with the path, rather than being the --format
+	explicit bool
+}
+
+// parseOutputs parses the values of --output, which are each a path that can end with :format to save
+// the result in that format instead of defaultFormat; the suffix is only taken to be a format if it is
+// one, so that paths with colons in them (such as on Windows) are not mistaken for having one
+func parseOutputs(values []string, defaultFormat string) []outputDestination {
+	outputs := make([]outputDestination, 0, len(values))
+	for _, value := range values {
+		output := outputDestination{path: value, format: defaultFormat}
+		if i := strings.LastIndex(value, ":"); i > 0 && slices.Contains(reporter.Format(), value[i+1:]) {
+			output = outputDestination{path: value[:i], format: value[i+1:], explicit: true}
+		}
+		outputs = append(outputs, output)
+	}
+
+	return outputs
+}
+
+// newOutputReporters returns a reporter for each of the outputs that saves the result to its file in its format
+func newOutputReporters(outputs []outputDestination, stderr io.Writer, level reporter.VerbosityLevel, options reporter.Options) ([]reporter.Reporter, error) {
+	results := make([]reporter.Reporter, 0, len(outputs))
+	for _, output := range outputs {
+		f, err := os.Create(output.path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create output file: %w", err)
+		}
+
+		r, err := reporter.NewWithOptions(output.format, f, stderr, level, 0, options)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, r)
+	}
+
+	return results, nil
+}
+
+// parseOutputHeader parses the value of the --output-header flag, which is in the form of "Name: value"
+func parseOutputHeader(value string) (http.Header, error) {
+	header := http.Header{}
+	if value == "" {
+		return header, nil
+	}
+
+	name, val, ok := strings.Cut(value, ":")
+	if !ok || strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("invalid --output-header %q, expected \"Name: value\"", value)
+	}
+	header.Set(strings.TrimSpace(name), strings.TrimSpace(val))
+
+	return header, nil
+}
+
+// ExitCodeError is returned when the exit code of the scan has been
+// overridden with the --exit-code flag.
+type ExitCodeError struct {
+	Code int
+}
+
+func (e ExitCodeError) Error() string {
+	return fmt.Sprintf("exit code overridden to %d", e.Code)
+}
+
+// parseSince parses the value of the --since flag, which is either an RFC3339
+// timestamp or a duration relative to now.
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("--since must be an RFC3339 timestamp or a duration: %q", value)
+	}
+
+	return now.Add(-d), nil
+}
+
+// validateConfig reports every problem with the config file at the given path,
+// which causes the reporter to have errored if there are any
+func validateConfig(r reporter.Reporter, configPath string) {
+	errs := config.ValidateFile(configPath)
+
+	for _, err := range errs {
+		r.Errorf("%s: %v\n", configPath, err)
+	}
+
+	if len(errs) == 0 {
+		r.Infof("%s is valid\n", configPath)
+	}
+}
+
+// hasScanTargets reports if the actions have anything to scan
+func hasScanTargets(actions osvscanner.ScannerActions) bool {
+	return len(actions.LockfilePaths) > 0 || len(actions.SBOMPaths) > 0 || len(actions.DockerfilePaths) > 0 ||
+		len(actions.PURLPaths) > 0 || len(actions.DirectoryPaths) > 0 || len(actions.GitCommits) > 0 ||
+		len(actions.DockerContainerNames) > 0 || actions.ScanOCIImage != "" ||
+		len(actions.RepositoryPaths) > 0 || actions.RepositoriesFilePath != ""
+}
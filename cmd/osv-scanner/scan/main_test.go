@@ -0,0 +1,60 @@
+package scan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseSince(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "2024-01-01T00:00:00Z", want: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{value: "168h", want: now.Add(-168 * time.Hour)},
+		{value: "last week", wantErr: true},
+	}
+
+	for _, testCase := range testCases {
+		got, err := parseSince(testCase.value, now)
+
+		if testCase.wantErr != (err != nil) {
+			t.Errorf("parseSince(%q) error = %v, wantErr %v", testCase.value, err, testCase.wantErr)
+		}
+
+		if !got.Equal(testCase.want) {
+			t.Errorf("parseSince(%q) = %v, want %v", testCase.value, got, testCase.want)
+		}
+	}
+}
+
+func TestParseOutputs(t *testing.T) {
+	t.Parallel()
+
+	got := parseOutputs([]string{
+		"results.json",
+		"results.sarif:sarif",
+		"report.html:html",
+		`C:\results\osv.json`,
+		"results:unknown",
+	}, "table")
+
+	want := []outputDestination{
+		{path: "results.json", format: "table"},
+		{path: "results.sarif", format: "sarif", explicit: true},
+		{path: "report.html", format: "html", explicit: true},
+		{path: `C:\results\osv.json`, format: "table"},
+		{path: "results:unknown", format: "table"},
+	}
+
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(outputDestination{})); diff != "" {
+		t.Errorf("parseOutputs() mismatch (-want +got):\n%s", diff)
+	}
+}
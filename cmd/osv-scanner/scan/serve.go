@@ -0,0 +1,297 @@
+package scan
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/pkg/osvscanner"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+// defaultServeAddress is the address that --serve serves the HTML report on, which is only
+// reachable from the same machine so that the report is not exposed to the network by accident
+const defaultServeAddress = "127.0.0.1:8000"
+
+// ServeOptions configures how ServeHTML serves the report
+type ServeOptions struct {
+	// Address is the host and port to listen on
+	Address string
+	// TLSCertFile and TLSKeyFile are the certificate and key to serve the report over HTTPS with,
+	// which is served over plain HTTP if they are not set
+	TLSCertFile string
+	TLSKeyFile  string
+	// RescanInterval is how often to regenerate the report, which is never if it is zero
+	RescanInterval time.Duration
+}
+
+// validateServeOptions checks that the address can be listened on and that TLS is fully configured
+func validateServeOptions(options ServeOptions) error {
+	if _, _, err := net.SplitHostPort(options.Address); err != nil {
+		return fmt.Errorf("--serve-address must be a host and port such as %s: %w", defaultServeAddress, err)
+	}
+	if (options.TLSCertFile == "") != (options.TLSKeyFile == "") {
+		return errors.New("--serve-tls-cert and --serve-tls-key must be set together")
+	}
+	if options.RescanInterval < 0 {
+		return errors.New("--serve-rescan-interval cannot be negative")
+	}
+
+	return nil
+}
+
+// serveURL returns the URL that the report is served at, which uses localhost
+// when listening on every interface as that always reaches the server
+func serveURL(options ServeOptions) string {
+	host, port, _ := net.SplitHostPort(options.Address)
+	if ip := net.ParseIP(host); host == "" || ip != nil && ip.IsUnspecified() {
+		host = "localhost"
+	}
+
+	scheme := "http"
+	if options.TLSCertFile != "" {
+		scheme = "https"
+	}
+
+	return scheme + "://" + net.JoinHostPort(host, port)
+}
+
+// rescanFunc re-runs the scan and writes an HTML report of its results to w
+type rescanFunc func(ctx context.Context, w io.Writer) error
+
+// newRescan returns a function that re-runs the scan with the actions, redacting the results in the
+// same way as the first scan, except for sending them to --output-url
+func newRescan(actions osvscanner.ScannerActions, options reporter.HTMLOptions, stderr io.Writer, level reporter.VerbosityLevel, redact *regexp.Regexp, timeout time.Duration) rescanFunc {
+	return func(ctx context.Context, w io.Writer) error {
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		options.ScannedAt = time.Now()
+		var r reporter.Reporter = reporter.NewHTMLReporterWithOptions(w, stderr, level, options)
+		if redact != nil {
+			r = reporter.NewRedactingReporter(r, redact)
+		}
+
+		vulnResult, err := osvscanner.DoScanWithContext(ctx, actions, r)
+		if err != nil && !errors.Is(err, osvscanner.VulnerabilitiesFoundErr) {
+			return err
+		}
+
+		return r.PrintResult(&vulnResult)
+	}
+}
+
+// reportServer serves the HTML report at reportPath, which can be regenerated by rescanning
+type reportServer struct {
+	reportPath string
+	// rescan regenerates the report, which cannot be done if it is nil
+	rescan rescanFunc
+
+	// reportMu guards the report file while it is being replaced
+	reportMu sync.RWMutex
+	// rescanMu makes sure that only one rescan runs at a time
+	rescanMu sync.Mutex
+
+	changedMu sync.Mutex
+	// changed is closed whenever the report is regenerated, and then replaced for the next time
+	changed chan struct{}
+}
+
+func newReportServer(reportPath string, rescan rescanFunc) *reportServer {
+	return &reportServer{reportPath: reportPath, rescan: rescan, changed: make(chan struct{})}
+}
+
+// waitForChange returns a channel that is closed the next time the report is regenerated
+func (s *reportServer) waitForChange() <-chan struct{} {
+	s.changedMu.Lock()
+	defer s.changedMu.Unlock()
+
+	return s.changed
+}
+
+// regenerate re-runs the scan and replaces the report with the results, only once the new report has been
+// completely written so that the report is never served partially written, and tells the pages about it
+func (s *reportServer) regenerate(ctx context.Context) error {
+	if s.rescan == nil {
+		return errors.New("the report cannot be regenerated")
+	}
+
+	s.rescanMu.Lock()
+	defer s.rescanMu.Unlock()
+
+	var report bytes.Buffer
+	if err := s.rescan(ctx, &report); err != nil {
+		return err
+	}
+
+	s.reportMu.Lock()
+	err := os.WriteFile(s.reportPath, report.Bytes(), 0600)
+	s.reportMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	s.changedMu.Lock()
+	close(s.changed)
+	s.changed = make(chan struct{})
+	s.changedMu.Unlock()
+
+	return nil
+}
+
+// handleReport serves the HTML report, which is read on each request so that a regenerated report is served.
+//
+// The report is self-contained, so the content security policy forbids loading anything other than its
+// inline styles and script, and it is not cached as the report may be regenerated.
+func (s *reportServer) handleReport(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path != "/" {
+		http.NotFound(w, req)
+		return
+	}
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	s.reportMu.RLock()
+	report, err := os.ReadFile(s.reportPath)
+	s.reportMu.RUnlock()
+	if err != nil {
+		http.Error(w, "failed to read report", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Security-Policy", fmt.Sprintf(
+		"default-src 'none'; style-src 'unsafe-inline'; script-src %s; connect-src 'self'; form-action 'self'",
+		output.HTMLScriptHash(),
+	))
+
+	if req.Method == http.MethodHead {
+		return
+	}
+
+	_, _ = w.Write(report)
+}
+
+// isSameOrigin reports whether the request came from a page served by the server,
+// so that other sites cannot make the server rescan
+func isSameOrigin(req *http.Request) bool {
+	origin := req.Header.Get("Origin")
+	if origin == "" {
+		// browsers always send the origin of cross-origin POST requests
+		return true
+	}
+
+	u, err := url.Parse(origin)
+
+	return err == nil && u.Host == req.Host
+}
+
+// handleRescan regenerates the report, and then redirects to it so that the rescan
+// button works even when scripts are disabled
+func (s *reportServer) handleRescan(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+		return
+	}
+	if !isSameOrigin(req) {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	// the scan carries on even if the page that started it is closed, as other pages are waiting for it
+	if err := s.regenerate(context.WithoutCancel(req.Context())); err != nil {
+		http.Error(w, "failed to rescan: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, req, "/", http.StatusSeeOther)
+}
+
+// handleEvents streams a "report" server-sent event each time the report is regenerated,
+// which the pages of the report reload themselves on
+func (s *reportServer) handleEvents(w http.ResponseWriter, req *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	// the page is waiting for changes from the moment that it is told the stream has started
+	changed := s.waitForChange()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-req.Context().Done():
+			return
+		case <-changed:
+			changed = s.waitForChange()
+			fmt.Fprint(w, "event: report\ndata: regenerated\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+// ServeHTML serves the HTML report at reportPath as per the options, which blocks until the server fails
+// or the process is interrupted. The report is regenerated with rescan when asked to by its page,
+// and periodically if the options have a rescan interval.
+func ServeHTML(r reporter.Reporter, reportPath string, rescan rescanFunc, options ServeOptions) error {
+	s := newReportServer(reportPath, rescan)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handleReport)
+	mux.HandleFunc("/rescan", s.handleRescan)
+	mux.HandleFunc("/events", s.handleEvents)
+
+	server := &http.Server{
+		Addr:              options.Address,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	if options.RescanInterval > 0 {
+		ticker := time.NewTicker(options.RescanInterval)
+		defer ticker.Stop()
+
+		go func() {
+			for range ticker.C {
+				if err := s.regenerate(context.Background()); err != nil {
+					r.Warnf("Failed to rescan: %v\n", err)
+				}
+			}
+		}()
+	}
+
+	r.Infof("Serving HTML report at %s, press Ctrl+C to stop\n", serveURL(options))
+
+	if options.TLSCertFile != "" {
+		return server.ListenAndServeTLS(options.TLSCertFile, options.TLSKeyFile)
+	}
+
+	return server.ListenAndServe()
+}
@@ -0,0 +1,243 @@
+package scan
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeReport(t *testing.T, report string) string {
+	t.Helper()
+
+	reportPath := filepath.Join(t.TempDir(), "report.html")
+	if err := os.WriteFile(reportPath, []byte(report), 0600); err != nil {
+		t.Fatalf("could not write report: %v", err)
+	}
+
+	return reportPath
+}
+
+func TestReportServer_HandleReport(t *testing.T) {
+	t.Parallel()
+
+	report := "<!DOCTYPE html><html><body>report</body></html>"
+	s := newReportServer(writeReport(t, report), nil)
+
+	testCases := []struct {
+		method     string
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{method: http.MethodGet, path: "/", wantStatus: http.StatusOK, wantBody: report},
+		{method: http.MethodHead, path: "/", wantStatus: http.StatusOK, wantBody: ""},
+		{method: http.MethodPost, path: "/", wantStatus: http.StatusMethodNotAllowed},
+		{method: http.MethodGet, path: "/favicon.ico", wantStatus: http.StatusNotFound},
+	}
+
+	for _, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		s.handleReport(rec, httptest.NewRequest(testCase.method, testCase.path, nil))
+
+		if rec.Code != testCase.wantStatus {
+			t.Errorf("%s %s: got status %d, want %d", testCase.method, testCase.path, rec.Code, testCase.wantStatus)
+		}
+
+		if testCase.wantStatus != http.StatusOK {
+			continue
+		}
+
+		if got := rec.Body.String(); got != testCase.wantBody {
+			t.Errorf("%s %s: got body %q, want %q", testCase.method, testCase.path, got, testCase.wantBody)
+		}
+		if got := rec.Header().Get("Content-Type"); got != "text/html; charset=utf-8" {
+			t.Errorf("%s %s: got Content-Type %q", testCase.method, testCase.path, got)
+		}
+		if got := rec.Header().Get("Cache-Control"); got != "no-store" {
+			t.Errorf("%s %s: got Cache-Control %q", testCase.method, testCase.path, got)
+		}
+		if got := rec.Header().Get("Content-Security-Policy"); !strings.Contains(got, "script-src 'sha256-") {
+			t.Errorf("%s %s: got Content-Security-Policy %q, which does not allow the inline script", testCase.method, testCase.path, got)
+		}
+	}
+}
+
+func TestReportServer_HandleRescan(t *testing.T) {
+	t.Parallel()
+
+	rescans := 0
+	s := newReportServer(writeReport(t, "first"), func(_ context.Context, w io.Writer) error {
+		rescans++
+		_, err := fmt.Fprintf(w, "rescan %d", rescans)
+
+		return err
+	})
+	changed := s.waitForChange()
+
+	testCases := []struct {
+		method     string
+		origin     string
+		wantStatus int
+		wantReport string
+	}{
+		{method: http.MethodPost, wantStatus: http.StatusSeeOther, wantReport: "rescan 1"},
+		{method: http.MethodPost, origin: "http://example.com", wantStatus: http.StatusSeeOther, wantReport: "rescan 2"},
+		{method: http.MethodPost, origin: "http://evil.example", wantStatus: http.StatusForbidden, wantReport: "rescan 2"},
+		{method: http.MethodGet, wantStatus: http.StatusMethodNotAllowed, wantReport: "rescan 2"},
+	}
+
+	for _, testCase := range testCases {
+		req := httptest.NewRequest(testCase.method, "/rescan", nil)
+		if testCase.origin != "" {
+			req.Header.Set("Origin", testCase.origin)
+		}
+		rec := httptest.NewRecorder()
+		s.handleRescan(rec, req)
+
+		if rec.Code != testCase.wantStatus {
+			t.Errorf("%s /rescan from %q: got status %d, want %d", testCase.method, testCase.origin, rec.Code, testCase.wantStatus)
+		}
+
+		report, err := os.ReadFile(s.reportPath)
+		if err != nil {
+			t.Fatalf("could not read report: %v", err)
+		}
+		if string(report) != testCase.wantReport {
+			t.Errorf("%s /rescan from %q: got report %q, want %q", testCase.method, testCase.origin, report, testCase.wantReport)
+		}
+	}
+
+	select {
+	case <-changed:
+	default:
+		t.Errorf("regenerating the report should have been announced")
+	}
+}
+
+func TestReportServer_HandleRescan_Error(t *testing.T) {
+	t.Parallel()
+
+	s := newReportServer(writeReport(t, "first"), func(_ context.Context, w io.Writer) error {
+		fmt.Fprint(w, "partial")
+
+		return errors.New("scan failed")
+	})
+
+	rec := httptest.NewRecorder()
+	s.handleRescan(rec, httptest.NewRequest(http.MethodPost, "/rescan", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("got status %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+
+	// the report that was already there should still be served
+	report, err := os.ReadFile(s.reportPath)
+	if err != nil {
+		t.Fatalf("could not read report: %v", err)
+	}
+	if string(report) != "first" {
+		t.Errorf("got report %q, want %q", report, "first")
+	}
+}
+
+func TestReportServer_HandleEvents(t *testing.T) {
+	t.Parallel()
+
+	s := newReportServer(writeReport(t, "first"), func(_ context.Context, w io.Writer) error {
+		_, err := fmt.Fprint(w, "second")
+		return err
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(s.handleEvents))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL) //nolint:noctx // the server is closed at the end of the test
+	if err != nil {
+		t.Fatalf("could not connect to the events: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if got := resp.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Errorf("got Content-Type %q", got)
+	}
+
+	if err := s.regenerate(context.Background()); err != nil {
+		t.Fatalf("could not regenerate the report: %v", err)
+	}
+
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	if err != nil {
+		t.Fatalf("could not read an event: %v", err)
+	}
+	if line != "event: report\n" {
+		t.Errorf("got event %q, want %q", line, "event: report\n")
+	}
+}
+
+func TestValidateServeOptions(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		options ServeOptions
+		wantErr string
+	}{
+		{name: "default", options: ServeOptions{Address: defaultServeAddress}},
+		{name: "all interfaces", options: ServeOptions{Address: ":8080"}},
+		{name: "ipv6", options: ServeOptions{Address: "[::1]:8000"}},
+		{name: "tls", options: ServeOptions{Address: defaultServeAddress, TLSCertFile: "cert.pem", TLSKeyFile: "key.pem"}},
+		{name: "no port", options: ServeOptions{Address: "127.0.0.1"}, wantErr: "--serve-address must be a host and port"},
+		{name: "cert without key", options: ServeOptions{Address: defaultServeAddress, TLSCertFile: "cert.pem"}, wantErr: "must be set together"},
+		{name: "key without cert", options: ServeOptions{Address: defaultServeAddress, TLSKeyFile: "key.pem"}, wantErr: "must be set together"},
+		{name: "negative interval", options: ServeOptions{Address: defaultServeAddress, RescanInterval: -1}, wantErr: "cannot be negative"},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateServeOptions(testCase.options)
+			if testCase.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateServeOptions() unexpected error: %v", err)
+				}
+
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), testCase.wantErr) {
+				t.Errorf("validateServeOptions() error = %v, want it to contain %q", err, testCase.wantErr)
+			}
+		})
+	}
+}
+
+func TestServeURL(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		options ServeOptions
+		want    string
+	}{
+		{options: ServeOptions{Address: defaultServeAddress}, want: "http://127.0.0.1:8000"},
+		{options: ServeOptions{Address: ":8080"}, want: "http://localhost:8080"},
+		{options: ServeOptions{Address: "0.0.0.0:8000"}, want: "http://localhost:8000"},
+		{options: ServeOptions{Address: "[::]:8000"}, want: "http://localhost:8000"},
+		{options: ServeOptions{Address: "[::1]:8000"}, want: "http://[::1]:8000"},
+		{options: ServeOptions{Address: "devbox.internal:8443", TLSCertFile: "cert.pem", TLSKeyFile: "key.pem"}, want: "https://devbox.internal:8443"},
+	}
+
+	for _, testCase := range testCases {
+		if got := serveURL(testCase.options); got != testCase.want {
+			t.Errorf("serveURL(%q) = %q, want %q", testCase.options.Address, got, testCase.want)
+		}
+	}
+}
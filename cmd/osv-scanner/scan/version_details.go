@@ -0,0 +1,65 @@
+package scan
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/osv-scanner/internal/local"
+	"github.com/google/osv-scanner/internal/version"
+	"github.com/google/osv-scanner/pkg/osv"
+	"github.com/google/osv-scanner/pkg/osvscanner"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+// formatDetailsTime formats a time in the details of the vulnerability data
+func formatDetailsTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// printVersionDetails reports the version of osv-scanner and how fresh the vulnerability
+// data that the actions would scan against is, warning about any local database
+// whose data is older than maxAge if it is not zero
+func printVersionDetails(r reporter.Reporter, actions osvscanner.ScannerActions, maxAge time.Duration, now time.Time) error {
+	r.Infof("osv-scanner version: %s\n", version.OSVVersion)
+
+	if !actions.CompareLocally && !actions.CompareOffline {
+		r.Infof("Vulnerabilities are checked against the OSV API at %s, which serves the latest data\n", osv.QueryEndpoint)
+
+		return nil
+	}
+
+	dbBasePath, err := local.DatabasesPath(actions.LocalDBPath, actions.CacheDir)
+	if err != nil {
+		return err
+	}
+
+	details, err := local.CachedDatabaseDetails(dbBasePath, actions.CompareOffline, local.Mirror{
+		URL:    actions.LocalDBMirrorURL,
+		Header: actions.LocalDBMirrorHeader,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read local databases: %w", err)
+	}
+
+	if len(details) == 0 {
+		r.Warnf("No local databases are cached in %s\n", dbBasePath)
+
+		return nil
+	}
+
+	r.Infof("Local databases are cached in %s\n", dbBasePath)
+
+	for _, d := range details {
+		r.Infof("%s: data as of %s, downloaded at %s\n", d.Ecosystem, formatDetailsTime(d.DataAt()), formatDetailsTime(d.DownloadedAt))
+
+		if d.RemoteModifiedAt.After(d.DownloadedAt) {
+			r.Infof("%s: a newer version from %s will be downloaded by the next scan\n", d.Ecosystem, formatDetailsTime(d.RemoteModifiedAt))
+		}
+
+		if age := now.Sub(d.DataAt()); maxAge > 0 && age > maxAge {
+			r.Warnf("%s: data is %s old, which is older than --max-db-age of %s\n", d.Ecosystem, age.Round(time.Second), maxAge)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,276 @@
+package serveapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/osv-scanner/internal/local"
+	"github.com/google/osv-scanner/internal/tlsconfig"
+	"github.com/google/osv-scanner/pkg/config"
+	"github.com/google/osv-scanner/pkg/grpcserver"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osvscanner"
+	"github.com/google/osv-scanner/pkg/reporter"
+	"github.com/google/osv-scanner/pkg/scannerpb"
+	"github.com/urfave/cli/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// defaultAddress is only reachable from the same machine,
+// so that the server is not exposed to the network by accident
+const defaultAddress = "127.0.0.1:8080"
+
+func Command(stdout, stderr io.Writer, r *reporter.Reporter) *cli.Command {
+	return &cli.Command{
+		Name:  "serve-api",
+		Usage: "[EXPERIMENTAL] serves REST and gRPC APIs that scan packages, lockfiles and SBOMs",
+		Description: "lockfiles and SBOMs are scanned by POSTing them to /v1/scan, which responds with the results as JSON " +
+			"in the same form as --format json, and the osvscanner.v1.Scanner gRPC service is served on --grpc-address if set; " +
+			"the query cache and local databases are shared between requests",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "address",
+				Usage: "the host and port to listen on, where a host of 0.0.0.0 listens on every interface",
+				Value: defaultAddress,
+			},
+			&cli.StringFlag{
+				Name:  "grpc-address",
+				Usage: "also serves the gRPC interface on the given host and port, such as 127.0.0.1:8081",
+			},
+			&cli.StringFlag{
+				Name:      "grpc-directory-root",
+				Usage:     "allows gRPC requests to scan the directories within this directory of the server, which they cannot scan any of if not set",
+				TakesFile: true,
+			},
+			&cli.StringFlag{
+				Name:      "tls-cert",
+				Usage:     "serves the API over TLS with the given certificate file, which requires --tls-key",
+				TakesFile: true,
+			},
+			&cli.StringFlag{
+				Name:      "tls-key",
+				Usage:     "the private key file of the --tls-cert certificate",
+				TakesFile: true,
+			},
+			&cli.StringFlag{
+				Name:      "ca-cert",
+				Usage:     "path to a PEM encoded certificate to trust in addition to the system roots when making network requests",
+				TakesFile: true,
+			},
+			&cli.BoolFlag{
+				Name:  "insecure-skip-tls",
+				Usage: "DANGEROUS: disables TLS certificate verification for all network requests, making them vulnerable to interception",
+			},
+			&cli.Int64Flag{
+				Name:  "max-upload-size",
+				Usage: "the largest file in bytes that can be uploaded to be scanned",
+				Value: 32 << 20,
+			},
+			&cli.DurationFlag{
+				Name:  "scan-timeout",
+				Usage: "limits how long each scan can take, such as 2m, which is unlimited if not set",
+			},
+			&cli.StringFlag{
+				Name:  "verbosity",
+				Usage: "specify the level of information that should be logged for each scan; value can be: " + strings.Join(reporter.VerbosityLevels(), ", "),
+				Value: "warn",
+			},
+			&cli.BoolFlag{
+				Name:  "no-cache",
+				Usage: "do not read or write the cache of query results",
+			},
+			&cli.StringFlag{
+				Name:      "cache-dir",
+				Usage:     "sets the directory that query results and local databases are cached in",
+				EnvVars:   []string{"OSV_SCANNER_CACHE_DIR"},
+				TakesFile: true,
+			},
+			&cli.DurationFlag{
+				Name:  "experimental-query-cache-ttl",
+				Usage: "keeps using cached query results for this long, such as 24h, even after the database of their ecosystem is updated",
+				Action: func(context *cli.Context, d time.Duration) error {
+					if d < 0 {
+						return errors.New("--experimental-query-cache-ttl cannot be negative")
+					}
+
+					return nil
+				},
+			},
+			&cli.BoolFlag{
+				Name:  "experimental-local-db",
+				Usage: "checks for vulnerabilities using local databases",
+			},
+			&cli.BoolFlag{
+				Name:  "experimental-offline",
+				Usage: "checks for vulnerabilities using local databases that are already cached",
+			},
+			&cli.StringFlag{
+				Name:   "experimental-local-db-path",
+				Usage:  "sets the path that local databases should be stored",
+				Hidden: true,
+			},
+			&cli.DurationFlag{
+				Name:  "experimental-local-db-reload-interval",
+				Usage: "how long local databases are kept in memory between scans before being loaded again to pick up any updates",
+				Value: time.Hour,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			var err error
+			*r, err = action(c, stdout, stderr)
+
+			return err
+		},
+	}
+}
+
+func action(context *cli.Context, stdout, stderr io.Writer) (reporter.Reporter, error) {
+	r := reporter.NewTableReporter(stdout, stderr, reporter.InfoLevel, false, 0)
+
+	address, grpcAddress := context.String("address"), context.String("grpc-address")
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		return r, fmt.Errorf("--address must be a host and port such as %s: %w", defaultAddress, err)
+	}
+	if _, _, err := net.SplitHostPort(grpcAddress); grpcAddress != "" && err != nil {
+		return r, fmt.Errorf("--grpc-address must be a host and port such as 127.0.0.1:8081: %w", err)
+	}
+	directoryRoot := context.String("grpc-directory-root")
+	if directoryRoot != "" {
+		if info, err := os.Stat(directoryRoot); err != nil || !info.IsDir() {
+			return r, fmt.Errorf("--grpc-directory-root must be a directory: %s", directoryRoot)
+		}
+	}
+	certFile, keyFile := context.String("tls-cert"), context.String("tls-key")
+	if (certFile == "") != (keyFile == "") {
+		return r, errors.New("--tls-cert and --tls-key must be set together")
+	}
+	if context.Int64("max-upload-size") <= 0 {
+		return r, errors.New("--max-upload-size must be greater than zero")
+	}
+	if err := tlsconfig.Configure(context.String("ca-cert"), context.Bool("insecure-skip-tls")); err != nil {
+		return r, err
+	}
+
+	level, err := reporter.ParseVerbosityLevel(context.String("verbosity"))
+	if err != nil {
+		return r, err
+	}
+
+	envConfig, err := config.LoadEnv(os.LookupEnv)
+	if err != nil {
+		return r, err
+	}
+
+	compareLocally := context.Bool("experimental-local-db") || context.Bool("experimental-offline")
+	if compareLocally {
+		local.RetainDatabases(context.Duration("experimental-local-db-reload-interval"))
+	}
+
+	actions := osvscanner.ScannerActions{
+		NoCache:    context.Bool("no-cache"),
+		CacheDir:   context.String("cache-dir"),
+		NoProgress: true,
+		EnvConfig:  envConfig,
+		ExperimentalScannerActions: osvscanner.ExperimentalScannerActions{
+			LocalDBPath:    context.String("experimental-local-db-path"),
+			QueryCacheTTL:  context.Duration("experimental-query-cache-ttl"),
+			CompareLocally: compareLocally,
+			CompareOffline: context.Bool("experimental-offline"),
+		},
+	}
+	scan := oneAtATime(osvscanner.DoScanWithContext)
+
+	s := &apiServer{
+		actions:       actions,
+		scan:          scan,
+		stderr:        stderr,
+		level:         level,
+		maxUploadSize: context.Int64("max-upload-size"),
+		timeout:       context.Duration("scan-timeout"),
+	}
+
+	server := &http.Server{
+		Addr:              address,
+		Handler:           s.handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errs := make(chan error, 2)
+
+	if grpcAddress != "" {
+		grpcServer, err := newGRPCServer(grpcserver.Options{Actions: actions, Scan: scan, Log: stderr, Level: level, DirectoryRoot: directoryRoot}, certFile, keyFile)
+		if err != nil {
+			return r, err
+		}
+
+		listener, err := net.Listen("tcp", grpcAddress)
+		if err != nil {
+			return r, err
+		}
+
+		go func() { errs <- grpcServer.Serve(listener) }()
+		r.Infof("Serving the gRPC interface at %s\n", grpcAddress)
+	}
+
+	scheme := "http"
+	if certFile != "" {
+		scheme = "https"
+	}
+	r.Infof("Serving the scan API at %s://%s/v1/scan, press Ctrl+C to stop\n", scheme, address)
+
+	go func() {
+		if certFile != "" {
+			errs <- server.ListenAndServeTLS(certFile, keyFile)
+		} else {
+			errs <- server.ListenAndServe()
+		}
+	}()
+
+	// both servers keep serving until one of them fails
+	return r, <-errs
+}
+
+// oneAtATime runs the scans one at a time, as the scanner was written for
+// processes that only ever run one, including those of both servers.
+//
+// Scans that are waiting for their turn stop waiting once their context is done,
+// so that requests which time out or are cancelled do not queue up behind a long scan.
+func oneAtATime(scan grpcserver.ScanFunc) grpcserver.ScanFunc {
+	turn := make(chan struct{}, 1)
+
+	return func(ctx context.Context, actions osvscanner.ScannerActions, r reporter.Reporter) (models.VulnerabilityResults, error) {
+		select {
+		case turn <- struct{}{}:
+		case <-ctx.Done():
+			return models.VulnerabilityResults{}, ctx.Err()
+		}
+		defer func() { <-turn }()
+
+		return scan(ctx, actions, r)
+	}
+}
+
+// newGRPCServer returns a gRPC server of the scanner, which uses TLS if there is a certificate
+func newGRPCServer(options grpcserver.Options, certFile, keyFile string) (*grpc.Server, error) {
+	var serverOptions []grpc.ServerOption
+	if certFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not load the TLS certificate: %w", err)
+		}
+		serverOptions = append(serverOptions, grpc.Creds(creds))
+	}
+
+	server := grpc.NewServer(serverOptions...)
+	scannerpb.RegisterScannerServer(server, grpcserver.New(options))
+
+	return server, nil
+}
@@ -0,0 +1,49 @@
+package serveapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osvscanner"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+func Test_oneAtATime(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	scan := oneAtATime(func(context.Context, osvscanner.ScannerActions, reporter.Reporter) (models.VulnerabilityResults, error) {
+		started <- struct{}{}
+		<-finish
+
+		return models.VulnerabilityResults{}, nil
+	})
+
+	done := make(chan error)
+	go func() {
+		_, err := scan(context.Background(), osvscanner.ScannerActions{}, &reporter.VoidReporter{})
+		done <- err
+	}()
+	<-started
+
+	// a scan waiting for its turn stops waiting when its context is done
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := scan(ctx, osvscanner.ScannerActions{}, &reporter.VoidReporter{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("scan() error = %v, want %v", err, context.Canceled)
+	}
+
+	close(finish)
+	if err := <-done; err != nil {
+		t.Errorf("scan() error = %v", err)
+	}
+
+	// the next scan gets its turn once the first one is finished
+	go func() { <-started }()
+	if _, err := scan(context.Background(), osvscanner.ScannerActions{}, &reporter.VoidReporter{}); err != nil {
+		t.Errorf("scan() error = %v", err)
+	}
+}
@@ -0,0 +1,232 @@
+package serveapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/internal/sbom"
+	"github.com/google/osv-scanner/pkg/grpcserver"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osvscanner"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+// uploadFormField is the field of multipart uploads that the file to scan is read from
+const uploadFormField = "file"
+
+// apiServer scans the lockfiles and SBOMs uploaded to it, using the same actions for every scan
+// apart from the file that is scanned, so that the caches and databases are shared between requests
+type apiServer struct {
+	actions osvscanner.ScannerActions
+	// scan runs each scan, which is osvscanner.DoScanWithContext outside of tests
+	scan   grpcserver.ScanFunc
+	stderr io.Writer
+	level  reporter.VerbosityLevel
+	// maxUploadSize is the largest file in bytes that can be uploaded
+	maxUploadSize int64
+	// timeout limits how long each scan can take, which is unlimited if it is zero
+	timeout time.Duration
+}
+
+// apiError is the body of responses to requests that could not be scanned
+type apiError struct {
+	Error string `json:"error"`
+}
+
+func writeJSONError(w http.ResponseWriter, status int, format string, a ...any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(apiError{Error: fmt.Sprintf(format, a...)})
+}
+
+// upload is a file that has been uploaded to be scanned
+type upload struct {
+	// name is the base name of the file, which determines how it is parsed unless parseAs is set
+	name    string
+	content io.Reader
+	// sbom is whether the file is an SBOM rather than a lockfile
+	sbom    bool
+	parseAs string
+}
+
+// readUpload reads the file to scan from the request, which is either the body of the request with the
+// name of the file given by the "filename" parameter, or the "file" field of a multipart form
+func readUpload(req *http.Request) (upload, error) {
+	query := req.URL.Query()
+	u := upload{name: query.Get("filename"), content: req.Body, parseAs: query.Get("parse-as")}
+
+	if mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type")); mediaType == "multipart/form-data" {
+		file, header, err := req.FormFile(uploadFormField)
+		if err != nil {
+			return upload{}, fmt.Errorf("could not read the %q field of the form: %w", uploadFormField, err)
+		}
+		u.content = file
+		if u.name == "" {
+			u.name = header.Filename
+		}
+	}
+
+	// only the base name is kept, so that a name cannot place the file outside of the directory it is scanned in
+	u.name = filepath.Base(filepath.Clean("/" + filepath.FromSlash(u.name)))
+	if u.name == "" || u.name == "." || u.name == string(filepath.Separator) {
+		return upload{}, errors.New("the name of the file is needed to know how to parse it, which can be given with the filename parameter")
+	}
+
+	switch kind := query.Get("type"); kind {
+	case "lockfile":
+	case "sbom":
+		u.sbom = true
+	case "":
+		u.sbom = slices.ContainsFunc(sbom.Providers, func(provider sbom.Reader) bool {
+			return provider.MatchesRecognizedFileNames(u.name)
+		})
+	default:
+		return upload{}, fmt.Errorf("unsupported type %q - must be either lockfile or sbom", kind)
+	}
+
+	if u.sbom && u.parseAs != "" {
+		return upload{}, errors.New("parse-as can only be used with lockfiles")
+	}
+
+	return u, nil
+}
+
+// scanUpload writes the upload to a temporary directory and scans it, reporting its results
+// against the name that it was uploaded with rather than where it was written to
+func (s *apiServer) scanUpload(ctx context.Context, u upload) (models.VulnerabilityResults, error) {
+	dir, err := os.MkdirTemp("", "osv-scanner-serve-api-")
+	if err != nil {
+		return models.VulnerabilityResults{}, err
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, u.name)
+	f, err := os.Create(path)
+	if err != nil {
+		return models.VulnerabilityResults{}, err
+	}
+	if _, err := io.Copy(f, u.content); err != nil {
+		f.Close()
+
+		return models.VulnerabilityResults{}, err
+	}
+	if err := f.Close(); err != nil {
+		return models.VulnerabilityResults{}, err
+	}
+
+	actions := s.actions
+	if u.sbom {
+		actions.SBOMPaths = []string{path}
+	} else {
+		// the parser is always given so that the path is never mistaken for one
+		actions.LockfilePaths = []string{u.parseAs + ":" + path}
+	}
+
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+
+	r := reporter.NewJSONReporter(io.Discard, s.stderr, s.level)
+	vulnResult, err := s.scan(ctx, actions, r)
+
+	for i := range vulnResult.Results {
+		if vulnResult.Results[i].Source.Path == path {
+			vulnResult.Results[i].Source.Path = u.name
+		}
+	}
+
+	return vulnResult, err
+}
+
+// handleScan scans the uploaded file, responding with the results in the same JSON as --format json.
+//
+// Finding vulnerabilities is what the request is for rather than an error, and a file without any
+// packages has no vulnerabilities, so both are successful responses.
+func (s *apiServer) handleScan(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		writeJSONError(w, http.StatusMethodNotAllowed, "%s", http.StatusText(http.StatusMethodNotAllowed))
+
+		return
+	}
+
+	req.Body = http.MaxBytesReader(w, req.Body, s.maxUploadSize)
+
+	u, err := readUpload(req)
+	if err != nil {
+		s.writeUploadError(w, err)
+		return
+	}
+
+	vulnResult, err := s.scanUpload(req.Context(), u)
+	switch {
+	case err == nil, errors.Is(err, osvscanner.VulnerabilitiesFoundErr), errors.Is(err, osvscanner.NoPackagesFoundErr):
+	case errors.Is(err, osvscanner.ErrAPIFailed):
+		writeJSONError(w, http.StatusBadGateway, "%v", err)
+		return
+	case errors.Is(err, osvscanner.ErrTimedOut), errors.Is(err, context.DeadlineExceeded):
+		writeJSONError(w, http.StatusGatewayTimeout, "%v", err)
+		return
+	default:
+		s.writeUploadError(w, err)
+		return
+	}
+
+	if vulnResult.Results == nil {
+		// Want 0 vulnerabilities to show in JSON as an empty list, not null.
+		vulnResult.Results = []models.PackageSource{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	if err := output.PrintJSONResults(&vulnResult, w); err != nil {
+		fmt.Fprintf(s.stderr, "Failed to write the results of scanning %s: %v\n", u.name, err)
+	}
+}
+
+// writeUploadError responds to a request whose upload could not be read or scanned
+func (s *apiServer) writeUploadError(w http.ResponseWriter, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeJSONError(w, http.StatusRequestEntityTooLarge, "the upload is larger than the limit of %d bytes", tooLarge.Limit)
+		return
+	}
+
+	writeJSONError(w, http.StatusUnprocessableEntity, "%v", err)
+}
+
+// handleHealth reports that the server is able to handle requests
+func (s *apiServer) handleHealth(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = io.WriteString(w, "ok\n")
+}
+
+func (s *apiServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/scan", s.handleScan)
+	mux.HandleFunc("/healthz", s.handleHealth)
+
+	return mux
+}
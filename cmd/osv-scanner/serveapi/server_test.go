@@ -0,0 +1,304 @@
+package serveapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osvscanner"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+// fakeScan pretends to scan the lockfile or SBOM in the actions, recording the
+// actions and the contents of the file and finding a vulnerability in lodash
+type fakeScan struct {
+	actions  osvscanner.ScannerActions
+	contents string
+	err      error
+}
+
+func (f *fakeScan) scan(_ context.Context, actions osvscanner.ScannerActions, _ reporter.Reporter) (models.VulnerabilityResults, error) {
+	f.actions = actions
+
+	path := ""
+	sourceType := "sbom"
+	if len(actions.SBOMPaths) > 0 {
+		path = actions.SBOMPaths[0]
+	} else {
+		path = actions.LockfilePaths[0][strings.Index(actions.LockfilePaths[0], ":")+1:]
+		sourceType = "lockfile"
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return models.VulnerabilityResults{}, err
+	}
+	f.contents = string(contents)
+
+	if f.err != nil {
+		return models.VulnerabilityResults{}, f.err
+	}
+
+	return models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: path, Type: sourceType},
+				Packages: []models.PackageVulns{
+					{
+						Package: models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"},
+						Groups:  []models.GroupInfo{{IDs: []string{"GHSA-35jh-r3h4-6jhm"}}},
+					},
+				},
+			},
+		},
+	}, osvscanner.VulnerabilitiesFoundErr
+}
+
+func newTestServer(scan *fakeScan) *apiServer {
+	return &apiServer{
+		actions:       osvscanner.ScannerActions{NoCache: true},
+		scan:          scan.scan,
+		stderr:        io.Discard,
+		level:         reporter.ErrorLevel,
+		maxUploadSize: 1024,
+	}
+}
+
+func multipartUpload(t *testing.T, filename, contents string) (*bytes.Buffer, string) {
+	t.Helper()
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile(uploadFormField, filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.WriteString(part, contents); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return body, writer.FormDataContentType()
+}
+
+func TestAPIServer_HandleScan(t *testing.T) {
+	t.Parallel()
+
+	multipartBody, multipartType := multipartUpload(t, "../../package-lock.json", `{"lockfileVersion": 3}`)
+
+	tests := []struct {
+		name        string
+		target      string
+		body        io.Reader
+		contentType string
+		wantPath    string
+		wantType    string
+		wantParseAs string
+	}{
+		{
+			name:     "raw body",
+			target:   "/v1/scan?filename=package-lock.json",
+			body:     strings.NewReader(`{"lockfileVersion": 3}`),
+			wantPath: "package-lock.json",
+			wantType: "lockfile",
+		},
+		{
+			name:        "multipart form",
+			target:      "/v1/scan",
+			body:        multipartBody,
+			contentType: multipartType,
+			wantPath:    "package-lock.json",
+			wantType:    "lockfile",
+		},
+		{
+			name:     "sbom by name",
+			target:   "/v1/scan?filename=bom.cdx.json",
+			body:     strings.NewReader(`{"lockfileVersion": 3}`),
+			wantPath: "bom.cdx.json",
+			wantType: "sbom",
+		},
+		{
+			name:     "sbom by type",
+			target:   "/v1/scan?filename=deps.json&type=sbom",
+			body:     strings.NewReader(`{"lockfileVersion": 3}`),
+			wantPath: "deps.json",
+			wantType: "sbom",
+		},
+		{
+			name:        "parse as",
+			target:      "/v1/scan?filename=deps.json&parse-as=package-lock.json",
+			body:        strings.NewReader(`{"lockfileVersion": 3}`),
+			wantPath:    "deps.json",
+			wantType:    "lockfile",
+			wantParseAs: "package-lock.json",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			scan := &fakeScan{}
+			req := httptest.NewRequest(http.MethodPost, tt.target, tt.body)
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rec := httptest.NewRecorder()
+			newTestServer(scan).handleScan(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("got status %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+			}
+			if got := rec.Header().Get("Content-Type"); got != "application/json" {
+				t.Errorf("got Content-Type %q", got)
+			}
+			if scan.contents != `{"lockfileVersion": 3}` {
+				t.Errorf("scanned %q instead of the uploaded file", scan.contents)
+			}
+			if !scan.actions.NoCache {
+				t.Errorf("the actions of the server were not used for the scan")
+			}
+			if tt.wantType == "lockfile" && !strings.HasPrefix(scan.actions.LockfilePaths[0], tt.wantParseAs+":") {
+				t.Errorf("lockfile was scanned as %q, want it parsed as %q", scan.actions.LockfilePaths[0], tt.wantParseAs)
+			}
+
+			var got models.VulnerabilityResults
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("response is not valid JSON: %v\n%s", err, rec.Body.String())
+			}
+
+			want := []models.PackageSource{{
+				Source: models.SourceInfo{Path: tt.wantPath, Type: tt.wantType},
+				Packages: []models.PackageVulns{{
+					Package: models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"},
+					Groups:  []models.GroupInfo{{IDs: []string{"GHSA-35jh-r3h4-6jhm"}}},
+				}},
+			}}
+			if diff := cmp.Diff(want, got.Results); diff != "" {
+				t.Errorf("results mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAPIServer_HandleScan_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		scanErr    error
+		wantStatus int
+	}{
+		{
+			name:       "wrong method",
+			method:     http.MethodGet,
+			target:     "/v1/scan?filename=package-lock.json",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:       "no filename",
+			method:     http.MethodPost,
+			target:     "/v1/scan",
+			body:       "{}",
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "unsupported type",
+			method:     http.MethodPost,
+			target:     "/v1/scan?filename=package-lock.json&type=image",
+			body:       "{}",
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "sbom parsed as a lockfile",
+			method:     http.MethodPost,
+			target:     "/v1/scan?filename=bom.cdx.json&parse-as=package-lock.json",
+			body:       "{}",
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "too large",
+			method:     http.MethodPost,
+			target:     "/v1/scan?filename=package-lock.json",
+			body:       strings.Repeat("a", 2048),
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:       "unparsable",
+			method:     http.MethodPost,
+			target:     "/v1/scan?filename=package-lock.json",
+			body:       "{}",
+			scanErr:    errors.New("could not parse"),
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "osv.dev unavailable",
+			method:     http.MethodPost,
+			target:     "/v1/scan?filename=package-lock.json",
+			body:       "{}",
+			scanErr:    fmt.Errorf("%w: osv.dev query failed", osvscanner.ErrAPIFailed),
+			wantStatus: http.StatusBadGateway,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			newTestServer(&fakeScan{err: tt.scanErr}).handleScan(rec, httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body)))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("got status %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+
+			var got apiError
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil || got.Error == "" {
+				t.Errorf("response is not an error: %v\n%s", err, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestAPIServer_HandleScan_RemovesUpload(t *testing.T) {
+	t.Parallel()
+
+	scan := &fakeScan{}
+	rec := httptest.NewRecorder()
+	newTestServer(scan).handleScan(rec, httptest.NewRequest(http.MethodPost, "/v1/scan?filename=package-lock.json", strings.NewReader("{}")))
+
+	dir := filepath.Dir(scan.actions.LockfilePaths[0][1:])
+	if _, err := os.Stat(dir); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("the directory the upload was scanned in was not removed: %v", err)
+	}
+}
+
+func TestAPIServer_HandleHealth(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	newTestServer(&fakeScan{}).handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("got status %d, want %d", rec.Code, http.StatusOK)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/google/osv-scanner/internal/remediation/suggest"
 	"github.com/google/osv-scanner/internal/resolution/client"
 	"github.com/google/osv-scanner/internal/resolution/manifest"
+	"github.com/google/osv-scanner/internal/tlsconfig"
 	"github.com/google/osv-scanner/pkg/depsdev"
 	"github.com/google/osv-scanner/pkg/lockfile"
 	"github.com/google/osv-scanner/pkg/reporter"
@@ -40,6 +41,15 @@ func Command(stdout, stderr io.Writer, r *reporter.Reporter) *cli.Command {
 				Name:  "ignore-dev",
 				Usage: "whether to ignore development dependencies for updates",
 			},
+			&cli.StringFlag{
+				Name:      "ca-cert",
+				Usage:     "path to a PEM encoded certificate to trust in addition to the system roots when making network requests",
+				TakesFile: true,
+			},
+			&cli.BoolFlag{
+				Name:  "insecure-skip-tls",
+				Usage: "DANGEROUS: disables TLS certificate verification for all network requests, making them vulnerable to interception",
+			},
 		},
 		Action: func(ctx *cli.Context) error {
 			var err error
@@ -67,6 +77,10 @@ func action(ctx *cli.Context, stdout, stderr io.Writer) (reporter.Reporter, erro
 		AvoidMajor: ctx.StringSlice("disallow-major-upgrades"),
 		IgnoreDev:  ctx.Bool("ignore-dev"),
 	}
+	if err := tlsconfig.Configure(ctx.String("ca-cert"), ctx.Bool("insecure-skip-tls")); err != nil {
+		return nil, err
+	}
+
 	if _, err := os.Stat(options.Manifest); errors.Is(err, os.ErrNotExist) {
 		return nil, fmt.Errorf("file not found: %s", options.Manifest)
 	} else if err != nil {
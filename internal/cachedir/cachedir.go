@@ -0,0 +1,85 @@
+// Package cachedir locates the directory that all of osv-scanner's on-disk caches
+// are stored in, so that they can be found, restored in CI, and cleared in one place.
+package cachedir
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+const envKeyCacheDir = "OSV_SCANNER_CACHE_DIR"
+
+// The subdirectories of the cache directory that each cache is stored in
+const (
+	// Databases is where the local databases used for offline scanning are downloaded to
+	Databases = "databases"
+	// Queries is where the results of querying OSV for packages are cached
+	Queries = "queries"
+	// Resolution is where the registry responses used when resolving manifests are cached
+	Resolution = "resolution"
+)
+
+var subdirectories = []string{Databases, Queries, Resolution}
+
+// Dir returns the cache directory, which is dir if it is set, otherwise the
+// OSV_SCANNER_CACHE_DIR environment variable if that is set, otherwise the
+// osv-scanner directory within the user cache directory or the temp directory
+func Dir(dir string) string {
+	if dir != "" {
+		return dir
+	}
+
+	if dir, ok := os.LookupEnv(envKeyCacheDir); ok && dir != "" {
+		return dir
+	}
+
+	base, err := os.UserCacheDir()
+	if err != nil {
+		base = os.TempDir()
+	}
+
+	return filepath.Join(base, "osv-scanner")
+}
+
+// Subdirectory returns the path of the subdirectory within the cache directory,
+// creating it if it does not exist yet
+func Subdirectory(dir string, name string) (string, error) {
+	path := filepath.Join(Dir(dir), name)
+
+	return path, os.MkdirAll(path, 0750)
+}
+
+// ResolutionCachePath returns the path that the resolution cache of the manifest is stored at,
+// which clients add their own extension to. Caches are keyed by the absolute path of the
+// manifest so that manifests with the same name in different projects do not share a cache.
+func ResolutionCachePath(dir string, manifestPath string) (string, error) {
+	resolutionDir, err := Subdirectory(dir, Resolution)
+	if err != nil {
+		return "", err
+	}
+
+	manifestPath, err = filepath.Abs(manifestPath)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256([]byte(manifestPath))
+
+	return filepath.Join(resolutionDir, filepath.Base(manifestPath)+"-"+hex.EncodeToString(hash[:8])), nil
+}
+
+// Clear removes all the caches from the cache directory, leaving anything
+// else that happens to be in the directory untouched
+func Clear(dir string) error {
+	var errs []error
+	for _, name := range subdirectories {
+		if err := os.RemoveAll(filepath.Join(Dir(dir), name)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
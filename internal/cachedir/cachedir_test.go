@@ -0,0 +1,86 @@
+package cachedir_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/osv-scanner/internal/cachedir"
+)
+
+func TestDir(t *testing.T) {
+	t.Setenv("OSV_SCANNER_CACHE_DIR", "/from/env")
+
+	if got := cachedir.Dir("/from/flag"); got != "/from/flag" {
+		t.Errorf("Dir() = %s, want the given directory", got)
+	}
+	if got := cachedir.Dir(""); got != "/from/env" {
+		t.Errorf("Dir() = %s, want the directory from the environment", got)
+	}
+
+	t.Setenv("OSV_SCANNER_CACHE_DIR", "")
+
+	if got := cachedir.Dir(""); filepath.Base(got) != "osv-scanner" {
+		t.Errorf("Dir() = %s, want a default osv-scanner directory", got)
+	}
+}
+
+func TestResolutionCachePath(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	first, err := cachedir.ResolutionCachePath(dir, "project-a/package.json")
+	if err != nil {
+		t.Fatalf("ResolutionCachePath() error = %v", err)
+	}
+	second, err := cachedir.ResolutionCachePath(dir, "project-b/package.json")
+	if err != nil {
+		t.Fatalf("ResolutionCachePath() error = %v", err)
+	}
+	again, err := cachedir.ResolutionCachePath(dir, "./project-a/../project-a/package.json")
+	if err != nil {
+		t.Fatalf("ResolutionCachePath() error = %v", err)
+	}
+
+	if first == second {
+		t.Errorf("manifests in different projects share the cache %s", first)
+	}
+	if first != again {
+		t.Errorf("the same manifest has different caches: %s and %s", first, again)
+	}
+	if filepath.Dir(first) != filepath.Join(dir, cachedir.Resolution) {
+		t.Errorf("ResolutionCachePath() = %s, want it to be in the resolution subdirectory", first)
+	}
+}
+
+func TestClear(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for _, name := range []string{cachedir.Databases, cachedir.Queries, cachedir.Resolution} {
+		sub, err := cachedir.Subdirectory(dir, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(sub, "cached"), []byte("data"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	unrelated := filepath.Join(dir, "unrelated.txt")
+	if err := os.WriteFile(unrelated, []byte("data"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cachedir.Clear(dir); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "unrelated.txt" {
+		t.Errorf("Clear() left %v, want only files that are not caches to remain", entries)
+	}
+}
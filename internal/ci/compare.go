@@ -0,0 +1,50 @@
+package ci
+
+import (
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/internal/utility/results"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+// Compare returns the findings in the new results that are not in the old results, printing how many
+// findings were added and removed (that is, fixed) so that the results of a scan can be gated on only
+// what it introduced
+func Compare(r reporter.Reporter, oldResults, newResults models.VulnerabilityResults) models.VulnerabilityResults {
+	added := DiffVulnerabilityResults(oldResults, newResults)
+	removed := DiffVulnerabilityResults(newResults, oldResults)
+
+	if added.Results == nil {
+		// Want 0 vulnerabilities to show in JSON as an empty list, not null.
+		added.Results = []models.PackageSource{}
+	}
+
+	reportFindings(r, "added", added)
+	reportFindings(r, "removed", removed)
+
+	return added
+}
+
+// Findings returns the vulnerabilities in the results, ignoring any license violations
+func Findings(vulnResults models.VulnerabilityResults) []models.VulnerabilityFlattened {
+	var vulns []models.VulnerabilityFlattened
+	for _, vf := range vulnResults.Flatten() {
+		if vf.Vulnerability.ID != "" {
+			vulns = append(vulns, vf)
+		}
+	}
+
+	return vulns
+}
+
+// reportFindings prints a summary of the given findings, which are identified
+// by their source, package, version, and vulnerability ID
+func reportFindings(r reporter.Reporter, kind string, vulnResults models.VulnerabilityResults) {
+	flattened := Findings(vulnResults)
+
+	r.Infof("%d %s %s\n", len(flattened), output.Form(len(flattened), "finding", "findings"), kind)
+
+	for _, vf := range flattened {
+		r.Verbosef("  %s: %s in %s\n", vf.Vulnerability.ID, results.PkgToString(vf.Package), vf.Source.String())
+	}
+}
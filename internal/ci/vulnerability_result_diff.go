@@ -13,7 +13,8 @@ import (
 //
 // Current implementation is O(n^2) on the number of vulns, but can be reduced to linear time
 func DiffVulnerabilityResults(oldRes, newRes models.VulnerabilityResults) models.VulnerabilityResults {
-	result := models.VulnerabilityResults{}
+	// the sources are still those of the new scan, as they are what the findings are relative to
+	result := models.VulnerabilityResults{ScannedSources: newRes.ScannedSources}
 	for _, ps := range newRes.Results {
 		sourceIdx := slices.IndexFunc(oldRes.Results, func(elem models.PackageSource) bool { return elem.Source == ps.Source })
 		if sourceIdx == -1 {
@@ -57,6 +58,15 @@ func DiffVulnerabilityResults(oldRes, newRes models.VulnerabilityResults) models
 			// Rebuild the groups lost in the previous step
 			groups := grouper.Group(grouper.ConvertVulnerabilityToIDAliases(resultPV.Vulnerabilities))
 			for i, group := range groups {
+				// keep the severity that the group was given when scanning, as that may have
+				// been for a preferred CVSS version or used a custom severity mapping
+				j := slices.IndexFunc(pv.Groups, func(g models.GroupInfo) bool { return slices.Contains(g.IDs, group.IDs[0]) })
+				if j != -1 && pv.Groups[j].MaxSeverity != "" {
+					groups[i].MaxSeverity = pv.Groups[j].MaxSeverity
+					groups[i].SeverityRating = pv.Groups[j].SeverityRating
+
+					continue
+				}
 				groups[i].MaxSeverity = output.MaxSeverity(group, *resultPV)
 			}
 			resultPV.Groups = groups
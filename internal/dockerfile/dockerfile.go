@@ -0,0 +1,353 @@
+// Package dockerfile statically extracts the base images and explicitly installed
+// system packages from a Dockerfile, without building it.
+package dockerfile
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Image is a reference to an image that a stage is built from
+type Image struct {
+	Name   string
+	Tag    string
+	Digest string
+}
+
+func (i Image) String() string {
+	ref := i.Name
+	if i.Tag != "" {
+		ref += ":" + i.Tag
+	}
+	if i.Digest != "" {
+		ref += "@" + i.Digest
+	}
+
+	return ref
+}
+
+// Install is a package that is explicitly installed by a RUN instruction
+type Install struct {
+	Name string
+	// Version is empty if the install is not pinned to a specific version
+	Version string
+	// Line is where the RUN instruction containing the install starts
+	Line int
+}
+
+// Stage is the instructions from a FROM instruction up to the next one
+type Stage struct {
+	// Name is what the stage can be referred to by in later FROM instructions
+	Name string
+	// Base is the image that the stage is ultimately built from, which is
+	// resolved through any earlier stages that are used as a base
+	Base Image
+	// Line is where the FROM instruction of the stage is
+	Line int
+	// Installs are the packages installed with apt or apt-get
+	Installs []Install
+}
+
+type Dockerfile struct {
+	Stages []Stage
+}
+
+type instruction struct {
+	keyword string
+	args    string
+	line    int
+}
+
+// readInstructions splits a Dockerfile into instructions, joining lines that are
+// continued with a trailing backslash and dropping comments
+func readInstructions(r io.Reader) ([]instruction, error) {
+	var instructions []instruction
+
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	start := 0
+	var current strings.Builder
+
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+
+		// comments and empty lines are allowed within continued instructions too
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if current.Len() == 0 {
+			start = lineNumber
+		} else {
+			current.WriteString(" ")
+		}
+
+		line, continued := strings.CutSuffix(line, "\\")
+		current.WriteString(strings.TrimSpace(line))
+
+		if continued {
+			continue
+		}
+
+		keyword, args, _ := strings.Cut(current.String(), " ")
+		instructions = append(instructions, instruction{
+			keyword: strings.ToUpper(keyword),
+			args:    strings.TrimSpace(args),
+			line:    start,
+		})
+		current.Reset()
+	}
+
+	if current.Len() > 0 {
+		keyword, args, _ := strings.Cut(current.String(), " ")
+		instructions = append(instructions, instruction{
+			keyword: strings.ToUpper(keyword),
+			args:    strings.TrimSpace(args),
+			line:    start,
+		})
+	}
+
+	return instructions, scanner.Err()
+}
+
+// expand replaces references to the given variables, leaving any unknown ones as is
+func expand(s string, vars map[string]string) string {
+	return os.Expand(s, func(name string) string {
+		if value, ok := vars[name]; ok {
+			return value
+		}
+
+		return "${" + name + "}"
+	})
+}
+
+// parseImage parses an image reference in the form of name[:tag][@digest]
+func parseImage(ref string) Image {
+	var image Image
+
+	ref, image.Digest, _ = strings.Cut(ref, "@")
+
+	// a colon before the last slash is the port of a registry rather than a tag
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		ref, image.Tag = ref[:i], ref[i+1:]
+	}
+
+	image.Name = ref
+
+	return image
+}
+
+// parseFrom parses the arguments of a FROM instruction, returning the image
+// reference and the name of the stage, if any
+func parseFrom(args string) (string, string) {
+	fields := strings.Fields(args)
+
+	// skip flags such as --platform
+	for len(fields) > 0 && strings.HasPrefix(fields[0], "--") {
+		fields = fields[1:]
+	}
+
+	if len(fields) == 0 {
+		return "", ""
+	}
+
+	if len(fields) == 3 && strings.EqualFold(fields[1], "as") {
+		return fields[0], fields[2]
+	}
+
+	return fields[0], ""
+}
+
+// splitCommands splits a shell command line into the words of each of its
+// simple commands, without attempting to support the full shell syntax
+func splitCommands(script string) [][]string {
+	var commands [][]string
+	var words []string
+
+	for _, word := range strings.Fields(script) {
+		// operators are not always separated from the commands with spaces
+		for word != "" {
+			i := strings.IndexAny(word, "&|;()")
+			if i == -1 {
+				words = append(words, strings.Trim(word, `"'`))
+				break
+			}
+
+			if i > 0 {
+				words = append(words, strings.Trim(word[:i], `"'`))
+			}
+			if len(words) > 0 {
+				commands = append(commands, words)
+				words = nil
+			}
+			word = strings.TrimLeft(word[i:], "&|;()")
+		}
+	}
+
+	if len(words) > 0 {
+		commands = append(commands, words)
+	}
+
+	return commands
+}
+
+// aptOptionsWithValues are the options of apt-get which take a separate value
+var aptOptionsWithValues = map[string]bool{
+	"-o":                true,
+	"--option":          true,
+	"-c":                true,
+	"--config-file":     true,
+	"-t":                true,
+	"--target-release":  true,
+	"--default-release": true,
+}
+
+// parseInstalls parses the packages that are installed by a command, if it
+// is an "apt-get install" or "apt install" command
+func parseInstalls(command []string) []Install {
+	// skip wrappers that run the actual command
+	for len(command) > 0 && (command[0] == "sudo" || command[0] == "env" || strings.Contains(command[0], "=")) {
+		command = command[1:]
+	}
+
+	if len(command) == 0 || (command[0] != "apt-get" && command[0] != "apt") {
+		return nil
+	}
+
+	var installs []Install
+	seenInstall := false
+
+	for i := 1; i < len(command); i++ {
+		arg := command[i]
+
+		if strings.HasPrefix(arg, "-") {
+			if aptOptionsWithValues[arg] {
+				i++
+			}
+
+			continue
+		}
+
+		if !seenInstall {
+			if arg != "install" {
+				return nil
+			}
+			seenInstall = true
+
+			continue
+		}
+
+		// packages can also be installed from a local .deb file, which cannot be assessed
+		if strings.Contains(arg, "/") {
+			continue
+		}
+
+		name, version, _ := strings.Cut(arg, "=")
+		// the architecture and target release cannot change the version that is installed
+		name, _, _ = strings.Cut(name, ":")
+		name, _, _ = strings.Cut(name, "/")
+
+		installs = append(installs, Install{Name: name, Version: version})
+	}
+
+	return installs
+}
+
+// parseRun parses the packages installed by a RUN instruction, which can be in
+// either the shell or exec form
+func parseRun(args string) []Install {
+	// flags such as --mount come before the command
+	for strings.HasPrefix(args, "--") {
+		_, args, _ = strings.Cut(args, " ")
+		args = strings.TrimSpace(args)
+	}
+
+	var exec []string
+	if strings.HasPrefix(args, "[") && json.Unmarshal([]byte(args), &exec) == nil {
+		// a shell can still be used explicitly with the exec form
+		if len(exec) == 3 && strings.HasSuffix(exec[0], "sh") && exec[1] == "-c" {
+			args = exec[2]
+		} else {
+			return parseInstalls(exec)
+		}
+	}
+
+	var installs []Install
+	for _, command := range splitCommands(args) {
+		installs = append(installs, parseInstalls(command)...)
+	}
+
+	return installs
+}
+
+// Parse extracts the stages of a Dockerfile
+func Parse(r io.Reader) (Dockerfile, error) {
+	instructions, err := readInstructions(r)
+	if err != nil {
+		return Dockerfile{}, fmt.Errorf("failed to read Dockerfile: %w", err)
+	}
+
+	var dockerfile Dockerfile
+	// args declared before the first FROM can be used in FROM instructions
+	globalArgs := map[string]string{}
+	stages := map[string]Image{}
+
+	for _, inst := range instructions {
+		switch inst.keyword {
+		case "ARG":
+			if len(dockerfile.Stages) > 0 {
+				continue
+			}
+
+			for _, arg := range strings.Fields(inst.args) {
+				if name, value, ok := strings.Cut(arg, "="); ok {
+					globalArgs[name] = strings.Trim(value, `"'`)
+				}
+			}
+		case "FROM":
+			ref, name := parseFrom(expand(inst.args, globalArgs))
+			if ref == "" {
+				return Dockerfile{}, fmt.Errorf("line %d: FROM instruction is missing an image", inst.line)
+			}
+
+			base, ok := stages[strings.ToLower(ref)]
+			if !ok {
+				base = parseImage(ref)
+			}
+
+			if name != "" {
+				stages[strings.ToLower(name)] = base
+			}
+
+			dockerfile.Stages = append(dockerfile.Stages, Stage{Name: name, Base: base, Line: inst.line})
+		case "RUN":
+			if len(dockerfile.Stages) == 0 {
+				return Dockerfile{}, fmt.Errorf("line %d: RUN instruction is before any FROM instruction", inst.line)
+			}
+
+			stage := &dockerfile.Stages[len(dockerfile.Stages)-1]
+			for _, install := range parseRun(inst.args) {
+				install.Line = inst.line
+				stage.Installs = append(stage.Installs, install)
+			}
+		}
+	}
+
+	return dockerfile, nil
+}
+
+// ParseFile extracts the stages of the Dockerfile at the given path
+func ParseFile(path string) (Dockerfile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Dockerfile{}, err
+	}
+	defer f.Close()
+
+	return Parse(f)
+}
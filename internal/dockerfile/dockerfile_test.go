@@ -0,0 +1,98 @@
+package dockerfile_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/internal/dockerfile"
+)
+
+func TestParseFile(t *testing.T) {
+	t.Parallel()
+
+	got, err := dockerfile.ParseFile("fixtures/Dockerfile")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	runtime := dockerfile.Image{Name: "debian", Tag: "bookworm-slim"}
+	want := dockerfile.Dockerfile{
+		Stages: []dockerfile.Stage{
+			{
+				Name: "build",
+				Base: dockerfile.Image{
+					Name:   "golang",
+					Tag:    "1.22-bookworm",
+					Digest: "sha256:d0902bacefdde1cf45528c098d14e55d78c107def8a22d148eabd71582d7a99",
+				},
+				Line: 4,
+			},
+			{
+				Name: "runtime",
+				Base: runtime,
+				Line: 7,
+				Installs: []dockerfile.Install{
+					{Name: "curl", Version: "7.88.1-10+deb12u5", Line: 8},
+					{Name: "ca-certificates", Line: 8},
+					{Name: "libssl3", Version: "3.0.11-1~deb12u2", Line: 8},
+				},
+			},
+			{
+				Base: runtime,
+				Line: 16,
+				Installs: []dockerfile.Install{
+					{Name: "git", Version: "1:2.39.2-1.1", Line: 17},
+					{Name: "openssl", Version: "3.0.11-1~deb12u2", Line: 18},
+				},
+			},
+		},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ParseFile() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParse_Images(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		from string
+		want dockerfile.Image
+	}{
+		{from: "ubuntu", want: dockerfile.Image{Name: "ubuntu"}},
+		{from: "ubuntu:22.04", want: dockerfile.Image{Name: "ubuntu", Tag: "22.04"}},
+		{from: "ubuntu@sha256:abc", want: dockerfile.Image{Name: "ubuntu", Digest: "sha256:abc"}},
+		{from: "localhost:5000/debian", want: dockerfile.Image{Name: "localhost:5000/debian"}},
+		{from: "localhost:5000/debian:12 as base", want: dockerfile.Image{Name: "localhost:5000/debian", Tag: "12"}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.from, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := dockerfile.Parse(strings.NewReader("FROM " + tt.from))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, got.Stages[0].Base); diff != "" {
+				t.Errorf("Parse() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	t.Parallel()
+
+	for _, content := range []string{
+		"FROM --platform=linux/amd64",
+		"RUN apt-get install -y curl=7.88.1-10+deb12u5",
+	} {
+		if _, err := dockerfile.Parse(strings.NewReader(content)); err == nil {
+			t.Errorf("expected an error parsing %q", content)
+		}
+	}
+}
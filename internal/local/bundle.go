@@ -0,0 +1,324 @@
+package local
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/klauspost/compress/zstd"
+)
+
+// bundleManifestName is the first file of every bundle, which describes the databases in it
+const bundleManifestName = "manifest.json"
+
+// bundleVersion is the version of the format of the bundles that are exported,
+// with bundles of newer versions not being imported as they may not be understood
+const bundleVersion = 1
+
+// maxBundleManifestSize is the largest manifest that is read, as a bundle is not trusted until it is checked
+const maxBundleManifestSize = 1 << 20
+
+// BundleManifest describes the databases in a bundle, as its first file
+type BundleManifest struct {
+	Version   int              `json:"version"`
+	CreatedAt time.Time        `json:"created_at"`
+	Databases []BundleDatabase `json:"databases"`
+}
+
+// BundleDatabase describes a database in a bundle, so that it can be checked before it is imported
+type BundleDatabase struct {
+	Ecosystem lockfile.Ecosystem `json:"ecosystem"`
+	// Path is where the database is in the bundle, which matches where it is
+	// stored in the databases directory, such as npm/records.gob
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+	// ExportedAt is when the newest entry in the database was exported
+	ExportedAt time.Time `json:"exported_at"`
+	// Signature is that of the database that was downloaded with it, if there was one
+	Signature string `json:"signature,omitempty"`
+}
+
+// validBundlePath reports whether the path is of a database in an ecosystem directory,
+// so that importing a bundle cannot write anywhere else
+func validBundlePath(p string) bool {
+	ecosystem, name, ok := strings.Cut(p, "/")
+
+	return ok && ecosystem != "" && ecosystem != "." && ecosystem != ".." &&
+		!strings.ContainsAny(ecosystem, `/\`) && (name == "all.zip" || name == recordStoreFileName)
+}
+
+func hashFile(p string) (int64, string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return 0, "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return size, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func writeBundleFile(tw *tar.Writer, name string, size int64, content io.Reader) error {
+	err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     size,
+		Mode:     0644,
+		ModTime:  time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(tw, content)
+
+	return err
+}
+
+// ExportBundle writes the local databases that are cached in dbBasePath to w as a bundle,
+// which is a zstd compressed tar archive of the databases that starts with a manifest of them
+// along with their checksums, so that they can be carried into networks without internet access
+// and imported there by ImportBundle for offline scans.
+func ExportBundle(w io.Writer, dbBasePath string) (BundleManifest, error) {
+	details, err := CachedDatabaseDetails(dbBasePath, true, Mirror{})
+	if err != nil {
+		return BundleManifest{}, err
+	}
+
+	if len(details) == 0 {
+		return BundleManifest{}, fmt.Errorf("there are no local databases in %s to export", dbBasePath)
+	}
+
+	manifest := BundleManifest{Version: bundleVersion, CreatedAt: time.Now().UTC()}
+	for _, d := range details {
+		size, sum, err := hashFile(d.StoredAt)
+		if err != nil {
+			return BundleManifest{}, err
+		}
+
+		// databases that have changed since they were stored are not exported, so they do not spread
+		if _, err := matchesChecksum(d.StoredAt, sum); err != nil {
+			return BundleManifest{}, err
+		}
+
+		signature, err := os.ReadFile(d.StoredAt + signatureSuffix)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return BundleManifest{}, err
+		}
+
+		manifest.Databases = append(manifest.Databases, BundleDatabase{
+			Ecosystem:  d.Ecosystem,
+			Path:       string(d.Ecosystem) + "/" + path.Base(d.StoredAt),
+			Size:       size,
+			SHA256:     sum,
+			ExportedAt: d.ExportedAt,
+			Signature:  strings.TrimSpace(string(signature)),
+		})
+	}
+
+	m, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return BundleManifest{}, err
+	}
+
+	zw, err := zstd.NewWriter(w)
+	if err != nil {
+		return BundleManifest{}, err
+	}
+	tw := tar.NewWriter(zw)
+
+	if err := writeBundleFile(tw, bundleManifestName, int64(len(m)), bytes.NewReader(m)); err != nil {
+		return BundleManifest{}, err
+	}
+
+	for i, db := range manifest.Databases {
+		f, err := os.Open(details[i].StoredAt)
+		if err != nil {
+			return BundleManifest{}, err
+		}
+
+		err = writeBundleFile(tw, db.Path, db.Size, f)
+		f.Close()
+
+		if err != nil {
+			return BundleManifest{}, fmt.Errorf("could not write %s to the bundle: %w", db.Path, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return BundleManifest{}, err
+	}
+
+	return manifest, zw.Close()
+}
+
+func readBundleManifest(tr *tar.Reader) (BundleManifest, error) {
+	header, err := tr.Next()
+	if err != nil {
+		return BundleManifest{}, fmt.Errorf("could not read the bundle: %w", err)
+	}
+
+	if header.Name != bundleManifestName {
+		return BundleManifest{}, fmt.Errorf("the bundle does not start with a %s", bundleManifestName)
+	}
+
+	var manifest BundleManifest
+	if err := json.NewDecoder(io.LimitReader(tr, maxBundleManifestSize)).Decode(&manifest); err != nil {
+		return BundleManifest{}, fmt.Errorf("could not read the manifest of the bundle: %w", err)
+	}
+
+	if manifest.Version > bundleVersion {
+		return BundleManifest{}, fmt.Errorf("the bundle is of version %d, which is newer than this version of osv-scanner supports", manifest.Version)
+	}
+
+	for _, db := range manifest.Databases {
+		if !validBundlePath(db.Path) || !strings.HasPrefix(db.Path, string(db.Ecosystem)+"/") {
+			return BundleManifest{}, fmt.Errorf("the bundle has a database at %q, which is not a valid path", db.Path)
+		}
+	}
+
+	return manifest, nil
+}
+
+// extractBundleFile writes the database to a temporary file next to where it will be stored,
+// checking that it matches its checksum
+func extractBundleFile(tr *tar.Reader, dbBasePath string, db BundleDatabase) (string, error) {
+	dir := path.Join(dbBasePath, path.Dir(db.Path))
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return "", err
+	}
+
+	f, err := os.CreateTemp(dir, path.Base(db.Path)+".*")
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	size, err := io.Copy(io.MultiWriter(f, h), io.LimitReader(tr, db.Size+1))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && (size != db.Size || hex.EncodeToString(h.Sum(nil)) != db.SHA256) {
+		err = fmt.Errorf("%s does not match the checksum in the manifest of the bundle", db.Path)
+	}
+	if err == nil {
+		//nolint:gosec // being world readable is fine, like the databases that are downloaded
+		err = os.Chmod(f.Name(), 0644)
+	}
+
+	if err != nil {
+		os.Remove(f.Name())
+
+		return "", err
+	}
+
+	return f.Name(), nil
+}
+
+// ImportBundle reads a bundle written by ExportBundle from r into dbBasePath, replacing the
+// databases of the ecosystems in it. Every database is checked against the manifest before any
+// are replaced, so that a bundle which has been truncated or tampered with changes nothing.
+func ImportBundle(r io.Reader, dbBasePath string) (BundleManifest, error) {
+	zr, err := zstd.NewReader(r)
+	if err != nil {
+		return BundleManifest{}, err
+	}
+	defer zr.Close()
+
+	tr := tar.NewReader(zr)
+
+	manifest, err := readBundleManifest(tr)
+	if err != nil {
+		return BundleManifest{}, err
+	}
+
+	expected := make(map[string]BundleDatabase, len(manifest.Databases))
+	for _, db := range manifest.Databases {
+		expected[db.Path] = db
+	}
+
+	extracted := make(map[string]string, len(manifest.Databases))
+	defer func() {
+		for _, tmp := range extracted {
+			os.Remove(tmp)
+		}
+	}()
+
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return BundleManifest{}, fmt.Errorf("could not read the bundle: %w", err)
+		}
+
+		db, ok := expected[header.Name]
+		if !ok {
+			return BundleManifest{}, fmt.Errorf("the bundle has %s, which is not in its manifest", header.Name)
+		}
+		if _, ok := extracted[header.Name]; ok {
+			return BundleManifest{}, fmt.Errorf("the bundle has %s more than once", header.Name)
+		}
+
+		tmp, err := extractBundleFile(tr, dbBasePath, db)
+		if err != nil {
+			return BundleManifest{}, err
+		}
+		extracted[header.Name] = tmp
+	}
+
+	for _, db := range manifest.Databases {
+		if _, ok := extracted[db.Path]; !ok {
+			return BundleManifest{}, fmt.Errorf("the bundle is missing %s", db.Path)
+		}
+	}
+
+	for _, db := range manifest.Databases {
+		storedAt := path.Join(dbBasePath, db.Path)
+
+		// remove the database of the ecosystem in either format along with its checksum
+		// and signature, so that nothing from before is ever used with what is imported
+		for _, name := range []string{"all.zip", recordStoreFileName} {
+			if other := path.Join(path.Dir(storedAt), name); other != storedAt {
+				removeDatabaseFiles(other)
+			} else {
+				_ = os.Remove(other + signatureSuffix)
+			}
+		}
+
+		if err := os.Rename(extracted[db.Path], storedAt); err != nil {
+			return BundleManifest{}, err
+		}
+		delete(extracted, db.Path)
+
+		if err := writeChecksum(storedAt, db.SHA256); err != nil {
+			return BundleManifest{}, err
+		}
+
+		if db.Signature != "" {
+			//nolint:gosec // being world readable is fine, like the databases
+			if err := os.WriteFile(storedAt+signatureSuffix, []byte(db.Signature+"\n"), 0644); err != nil {
+				return BundleManifest{}, err
+			}
+		}
+	}
+
+	return manifest, nil
+}
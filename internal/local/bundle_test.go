@@ -0,0 +1,229 @@
+package local_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/google/osv-scanner/internal/local"
+	"github.com/google/osv-scanner/internal/testutility"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/klauspost/compress/zstd"
+)
+
+type bundleFile struct {
+	name    string
+	content []byte
+}
+
+// writeBundle writes a bundle of the files with the manifest, which is not checked for being correct
+func writeBundle(t *testing.T, manifest local.BundleManifest, files ...bundleFile) []byte {
+	t.Helper()
+
+	m, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+	zw, err := zstd.NewWriter(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tw := tar.NewWriter(zw)
+
+	for _, f := range append([]bundleFile{{name: "manifest.json", content: m}}, files...) {
+		if err := tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: f.name, Size: int64(len(f.content)), Mode: 0644}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(f.content); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func sha256Hex(content []byte) string {
+	sum := sha256.Sum256(content)
+
+	return hex.EncodeToString(sum[:])
+}
+
+func TestExportBundle_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	exportDir := testutility.CreateTestDir(t)
+	importDir := testutility.CreateTestDir(t)
+
+	npm := zipOSVs(t, map[string]models.Vulnerability{"GHSA-1.json": {ID: "GHSA-1"}})
+	pypi := zipOSVs(t, map[string]models.Vulnerability{"PYSEC-1.json": {ID: "PYSEC-1"}})
+
+	cacheWrite(t, determineStoredAtPath(exportDir, "npm"), npm)
+	cacheWrite(t, determineStoredAtPath(exportDir, "PyPI"), pypi)
+
+	// a database that is being replaced by the bundle
+	cacheWrite(t, determineStoredAtPath(importDir, "npm"), zipOSVs(t, map[string]models.Vulnerability{
+		"GHSA-2.json": {ID: "GHSA-2"},
+	}))
+
+	bundle := new(bytes.Buffer)
+
+	exported, err := local.ExportBundle(bundle, exportDir)
+	if err != nil {
+		t.Fatalf("unexpected error \"%v\"", err)
+	}
+
+	if len(exported.Databases) != 2 || exported.Databases[0].Path != "PyPI/all.zip" || exported.Databases[1].Path != "npm/all.zip" {
+		t.Errorf("unexpected databases were exported: %+v", exported.Databases)
+	}
+
+	imported, err := local.ImportBundle(bundle, importDir)
+	if err != nil {
+		t.Fatalf("unexpected error \"%v\"", err)
+	}
+
+	if len(imported.Databases) != 2 {
+		t.Errorf("expected 2 databases to be imported, got %d", len(imported.Databases))
+	}
+
+	for name, want := range map[string][]byte{"npm": npm, "PyPI": pypi} {
+		got, err := os.ReadFile(determineStoredAtPath(importDir, name))
+		if err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+
+		if !bytes.Equal(got, want) {
+			t.Errorf("expected the %s database to be imported as it was exported", name)
+		}
+	}
+
+	db, err := local.NewZippedDB(context.Background(), importDir, "npm", "https://example.com/npm/all.zip", nil, true)
+	if err != nil {
+		t.Fatalf("unexpected error \"%v\"", err)
+	}
+
+	expectDBToHaveOSVs(t, db, []models.Vulnerability{{ID: "GHSA-1"}})
+}
+
+func TestExportBundle_NoDatabases(t *testing.T) {
+	t.Parallel()
+
+	_, err := local.ExportBundle(new(bytes.Buffer), testutility.CreateTestDir(t))
+
+	if err == nil || !strings.Contains(err.Error(), "there are no local databases") {
+		t.Errorf("expected an error about there being no databases, got \"%v\"", err)
+	}
+}
+
+func TestImportBundle_Invalid(t *testing.T) {
+	t.Parallel()
+
+	content := zipOSVs(t, map[string]models.Vulnerability{"GHSA-1.json": {ID: "GHSA-1"}})
+	database := local.BundleDatabase{
+		Ecosystem: "npm",
+		Path:      "npm/all.zip",
+		Size:      int64(len(content)),
+		SHA256:    sha256Hex(content),
+	}
+
+	withDatabase := func(db local.BundleDatabase) local.BundleManifest {
+		return local.BundleManifest{Version: 1, Databases: []local.BundleDatabase{db}}
+	}
+
+	tests := []struct {
+		name   string
+		bundle []byte
+		want   string
+	}{
+		{
+			name:   "not a bundle",
+			bundle: []byte("not a bundle"),
+			want:   "could not read the bundle",
+		},
+		{
+			name:   "checksum does not match",
+			bundle: writeBundle(t, withDatabase(database), bundleFile{name: "npm/all.zip", content: append(content[1:], 'x')}),
+			want:   "npm/all.zip does not match the checksum in the manifest of the bundle",
+		},
+		{
+			name:   "truncated database",
+			bundle: writeBundle(t, withDatabase(database), bundleFile{name: "npm/all.zip", content: content[1:]}),
+			want:   "npm/all.zip does not match the checksum in the manifest of the bundle",
+		},
+		{
+			name:   "missing database",
+			bundle: writeBundle(t, withDatabase(database)),
+			want:   "the bundle is missing npm/all.zip",
+		},
+		{
+			name:   "database not in manifest",
+			bundle: writeBundle(t, withDatabase(database), bundleFile{name: "PyPI/all.zip", content: content}),
+			want:   "the bundle has PyPI/all.zip, which is not in its manifest",
+		},
+		{
+			name: "path outside of the databases directory",
+			bundle: writeBundle(t, withDatabase(local.BundleDatabase{
+				Ecosystem: "..",
+				Path:      "../all.zip",
+				Size:      database.Size,
+				SHA256:    database.SHA256,
+			}), bundleFile{name: "../all.zip", content: content}),
+			want: "which is not a valid path",
+		},
+		{
+			name:   "newer version",
+			bundle: writeBundle(t, local.BundleManifest{Version: 2}),
+			want:   "the bundle is of version 2",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			testDir := testutility.CreateTestDir(t)
+			existing := zipOSVs(t, map[string]models.Vulnerability{"GHSA-2.json": {ID: "GHSA-2"}})
+			cacheWrite(t, determineStoredAtPath(testDir, "npm"), existing)
+
+			_, err := local.ImportBundle(bytes.NewReader(tt.bundle), testDir)
+
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected an error containing %q, got \"%v\"", tt.want, err)
+			}
+
+			got, err := os.ReadFile(determineStoredAtPath(testDir, "npm"))
+			if err != nil {
+				t.Fatalf("unexpected error \"%v\"", err)
+			}
+
+			if !bytes.Equal(got, existing) {
+				t.Errorf("expected the existing database to not be replaced")
+			}
+
+			entries, err := os.ReadDir(path.Join(testDir, "npm"))
+			if err != nil {
+				t.Fatalf("unexpected error \"%v\"", err)
+			}
+
+			if len(entries) != 1 {
+				t.Errorf("expected no temporary files to be left behind, got %d files", len(entries))
+			}
+		})
+	}
+}
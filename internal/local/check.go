@@ -1,11 +1,18 @@
 package local
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"path"
+	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/google/osv-scanner/internal/cachedir"
+	"github.com/google/osv-scanner/internal/utility/vulns"
 	"github.com/google/osv-scanner/pkg/lockfile"
 	"github.com/google/osv-scanner/pkg/models"
 	"github.com/google/osv-scanner/pkg/osv"
@@ -15,8 +22,72 @@ import (
 const zippedDBRemoteHost = "https://osv-vulnerabilities.storage.googleapis.com"
 const envKeyLocalDBCacheDirectory = "OSV_SCANNER_LOCAL_DB_CACHE_DIRECTORY"
 
-func loadDB(dbBasePath string, ecosystem lockfile.Ecosystem, offline bool) (*ZipDB, error) {
-	return NewZippedDB(dbBasePath, string(ecosystem), fmt.Sprintf("%s/%s/all.zip", zippedDBRemoteHost, ecosystem), offline)
+// Mirror describes where the zipped databases should be downloaded from.
+//
+// The zero value downloads from the public OSV storage bucket.
+type Mirror struct {
+	// URL is the base URL that the databases live under, in the form of <URL>/<ecosystem>/all.zip
+	URL string
+	// Header is an optional header to send with each request, in the form of "Name: value"
+	Header string
+	// SignatureKey is the path to an optional PEM encoded public key that every archive must be signed by,
+	// with the signature of each being at <URL>/<ecosystem>/all.zip.sig as made by "cosign sign-blob --key"
+	SignatureKey string
+	// Sources are where else advisories are downloaded from, with packages being checked against the
+	// records of each source as well as those of the mirror
+	Sources []AdvisorySource
+}
+
+func (m Mirror) headers() (http.Header, error) {
+	if m.Header == "" {
+		return nil, nil
+	}
+
+	name, value, ok := strings.Cut(m.Header, ":")
+	if !ok || strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("invalid header %q, expected \"Name: value\"", m.Header)
+	}
+
+	headers := http.Header{}
+	headers.Set(strings.TrimSpace(name), strings.TrimSpace(value))
+
+	return headers, nil
+}
+
+// archiveURL returns the url that the zipped database of the ecosystem is downloaded from
+func (m Mirror) archiveURL(ecosystem lockfile.Ecosystem) string {
+	host := zippedDBRemoteHost
+	if m.URL != "" {
+		host = strings.TrimSuffix(m.URL, "/")
+	}
+
+	return fmt.Sprintf("%s/%s/all.zip", host, ecosystem)
+}
+
+// configure sets the header and signature key of the mirror on the database, which are
+// used for the databases of its advisory sources too
+func (m Mirror) configure(db *ZipDB) error {
+	headers, err := m.headers()
+	if err != nil {
+		return err
+	}
+
+	key, err := m.signatureKey()
+	if err != nil {
+		return err
+	}
+
+	db.Headers = headers
+	db.SignatureKey = key
+
+	return nil
+}
+
+// newDB returns the database of the ecosystem that is downloaded from the mirror, without loading it
+func (m Mirror) newDB(dbBasePath string, ecosystem lockfile.Ecosystem, offline bool) (*ZipDB, error) {
+	db := newZipDB(dbBasePath, string(ecosystem), m.archiveURL(ecosystem), nil, offline)
+
+	return db, m.configure(db)
 }
 
 func toPackageDetails(query *osv.Query) (lockfile.PackageDetails, error) {
@@ -48,70 +119,81 @@ func toPackageDetails(query *osv.Query) (lockfile.PackageDetails, error) {
 // use to store local databases.
 //
 // if a local path is explicitly provided either by the localDBPath parameter
-// or via the envKeyLocalDBCacheDirectory environment variable, the scanner will
-// attempt to use the user cache directory if possible or otherwise the temp directory
+// or via the envKeyLocalDBCacheDirectory environment variable, the databases are
+// stored in an "osv-scanner" directory within it as they always have been, otherwise
+// they are stored in the databases subdirectory of the cache directory
 //
-// if an error occurs at any point when a local path is not explicitly provided,
-// the scanner will fall back to the temp directory first before finally erroring
-func setupLocalDBDirectory(localDBPath string) (string, error) {
-	var err error
-
-	// fallback to the env variable if a local database path has not been provided
-	if localDBPath == "" {
+// if an error occurs when neither a local path nor a cache directory is explicitly
+// provided, the scanner will fall back to the temp directory before finally erroring
+//
+// an explicitly provided cache directory takes precedence over the environment variable
+func setupLocalDBDirectory(localDBPath string, cacheDir string) (string, error) {
+	// fallback to the env variable if neither a local database path nor a cache directory has been provided
+	if localDBPath == "" && cacheDir == "" {
 		if p, envSet := os.LookupEnv(envKeyLocalDBCacheDirectory); envSet {
 			localDBPath = p
 		}
 	}
 
-	implicitPath := localDBPath == ""
-
-	// if we're implicitly picking a path, use the user cache directory if available
-	if implicitPath {
-		localDBPath, err = os.UserCacheDir()
+	if localDBPath != "" {
+		altPath := path.Join(localDBPath, "osv-scanner")
 
-		if err != nil {
-			localDBPath = os.TempDir()
-		}
+		return altPath, os.MkdirAll(altPath, 0750)
 	}
 
-	altPath := path.Join(localDBPath, "osv-scanner")
-	err = os.MkdirAll(altPath, 0750)
+	dbBasePath, err := cachedir.Subdirectory(cacheDir, cachedir.Databases)
 	if err == nil {
-		return altPath, nil
+		return dbBasePath, nil
 	}
 
 	// if we're implicitly picking a path, try the temp directory before giving up
-	if implicitPath && localDBPath != os.TempDir() {
-		return setupLocalDBDirectory(os.TempDir())
+	if tempDir := filepath.Join(os.TempDir(), "osv-scanner"); cacheDir == "" && cachedir.Dir("") != tempDir {
+		return cachedir.Subdirectory(tempDir, cachedir.Databases)
 	}
 
 	return "", err
 }
 
-func MakeRequest(r reporter.Reporter, query osv.BatchedQuery, offline bool, localDBPath string) (*osv.HydratedBatchedResponse, error) {
+func MakeRequest(ctx context.Context, r reporter.Reporter, query osv.BatchedQuery, offline bool, localDBPath string, cacheDir string, mirror Mirror) (*osv.HydratedBatchedResponse, error) {
 	results := make([]osv.Response, 0, len(query.Queries))
-	dbs := make(map[lockfile.Ecosystem]*ZipDB)
+	dbs := make(map[string]*ZipDB)
+	// the advisory source databases that could not be loaded, so that each is only reported once
+	failedSourceDBs := make(map[string]bool)
 
-	dbBasePath, err := setupLocalDBDirectory(localDBPath)
+	dbBasePath, err := setupLocalDBDirectory(localDBPath, cacheDir)
 
 	if err != nil {
 		return &osv.HydratedBatchedResponse{}, fmt.Errorf("could not create %s: %w", dbBasePath, err)
 	}
 
-	loadDBFromCache := func(ecosystem lockfile.Ecosystem) (*ZipDB, error) {
-		if db, ok := dbs[ecosystem]; ok {
+	loadDBFromCache := func(db *ZipDB) (*ZipDB, error) {
+		storedAt := db.StoredAt
+		if db, ok := dbs[storedAt]; ok {
 			return db, nil
 		}
 
-		db, err := loadDB(dbBasePath, ecosystem, offline)
+		if err := mirror.configure(db); err != nil {
+			return nil, err
+		}
+
+		start := time.Now()
+		db, reused, err := loadRetainedDB(ctx, db, mirror)
 
 		if err != nil {
 			return nil, err
 		}
 
-		r.Infof("Loaded %s local db from %s\n", db.Name, db.StoredAt)
+		if !reused {
+			r.Infof("Loaded %s local db from %s\n", db.Name, db.StoredAt)
+			reporter.Logger(r).Debug("Loaded local db",
+				"name", db.Name,
+				"url", db.ArchiveURL,
+				"offline", offline,
+				"duration", time.Since(start),
+			)
+		}
 
-		dbs[ecosystem] = db
+		dbs[storedAt] = db
 
 		return db, nil
 	}
@@ -140,17 +222,46 @@ func MakeRequest(r reporter.Reporter, query osv.BatchedQuery, offline bool, loca
 			continue
 		}
 
-		db, err := loadDBFromCache(pkg.Ecosystem)
+		vulnerabilities := models.Vulnerabilities{}
+
+		db, err := loadDBFromCache(newZipDB(dbBasePath, string(pkg.Ecosystem), mirror.archiveURL(pkg.Ecosystem), nil, offline))
 
 		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
 			// currently, this will actually only error if the PURL cannot be parses
 			r.Errorf("could not load db for %s ecosystem: %v\n", pkg.Ecosystem, err)
-			results = append(results, osv.Response{Vulns: []models.Vulnerability{}})
+		} else {
+			vulnerabilities = db.VulnerabilitiesAffectingPackage(pkg)
+		}
 
-			continue
+		for _, source := range mirror.Sources {
+			db := source.newDB(dbBasePath, pkg.Ecosystem, offline)
+			if failedSourceDBs[db.StoredAt] {
+				continue
+			}
+
+			storedAt := db.StoredAt
+			if db, err = loadDBFromCache(db); err != nil {
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+				r.Errorf("could not load db for %s ecosystem from %s: %v\n", pkg.Ecosystem, source, err)
+				failedSourceDBs[storedAt] = true
+
+				continue
+			}
+
+			// a record can be in more than one source, such as under its own ID in one and as an alias in another
+			for _, vulnerability := range db.VulnerabilitiesAffectingPackage(pkg) {
+				if !vulns.Include(vulnerabilities, vulnerability) {
+					vulnerabilities = append(vulnerabilities, vulnerability)
+				}
+			}
 		}
 
-		results = append(results, osv.Response{Vulns: db.VulnerabilitiesAffectingPackage(pkg)})
+		results = append(results, osv.Response{Vulns: vulnerabilities})
 	}
 
 	return &osv.HydratedBatchedResponse{Results: results}, nil
@@ -0,0 +1,192 @@
+package local
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+// DatabaseDetails describes a local database that has been cached on disk
+type DatabaseDetails struct {
+	Ecosystem lockfile.Ecosystem
+	// the path to the zip archive or record store on disk
+	StoredAt string
+	// when the database was downloaded, or last checked for updates if it is a record store
+	DownloadedAt time.Time
+	// when the newest entry in the database was exported, which is zero if it has no entries
+	ExportedAt time.Time
+	// when the zip archive was last modified on the db host, which is only fetched when online
+	RemoteModifiedAt time.Time
+}
+
+// DataAt returns when the vulnerabilities in the database are current as of
+func (d DatabaseDetails) DataAt() time.Time {
+	if !d.ExportedAt.IsZero() {
+		return d.ExportedAt
+	}
+
+	return d.DownloadedAt
+}
+
+// DatabasesPath returns the path that local databases are stored in
+func DatabasesPath(localDBPath string, cacheDir string) (string, error) {
+	dbBasePath, err := setupLocalDBDirectory(localDBPath, cacheDir)
+
+	if err != nil {
+		return "", fmt.Errorf("could not create %s: %w", dbBasePath, err)
+	}
+
+	return dbBasePath, nil
+}
+
+// fetchRemoteArchiveModified returns when the zip archive at the url was last modified,
+// per the Last-Modified header of the db host
+func fetchRemoteArchiveModified(url string, headers http.Header) (time.Time, error) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodHead, url, nil)
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	setRequestHeaders(req, headers)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return time.Time{}, fmt.Errorf("db host returned %s", resp.Status)
+	}
+
+	modified := resp.Header.Get("Last-Modified")
+
+	if modified == "" {
+		return time.Time{}, errors.New("db host did not return a Last-Modified header")
+	}
+
+	return http.ParseTime(modified)
+}
+
+// readDatabaseDetails reads the details of the zip archive stored at the path,
+// using only its central directory so that the entries do not need to be decompressed
+func readDatabaseDetails(ecosystem lockfile.Ecosystem, storedAt string) (DatabaseDetails, error) {
+	info, err := os.Stat(storedAt)
+	if err != nil {
+		return DatabaseDetails{}, err
+	}
+
+	zipReader, err := zip.OpenReader(storedAt)
+	if err != nil {
+		return DatabaseDetails{}, fmt.Errorf("could not read OSV database archive: %w", err)
+	}
+	defer zipReader.Close()
+
+	details := DatabaseDetails{
+		Ecosystem:    ecosystem,
+		StoredAt:     storedAt,
+		DownloadedAt: info.ModTime(),
+	}
+
+	for _, zipFile := range zipReader.File {
+		if strings.HasSuffix(zipFile.Name, ".json") && zipFile.Modified.After(details.ExportedAt) {
+			details.ExportedAt = zipFile.Modified
+		}
+	}
+
+	return details, nil
+}
+
+// readRecordStoreDetails reads the details of the record store stored at the path
+func readRecordStoreDetails(ecosystem lockfile.Ecosystem, storedAt string) (DatabaseDetails, error) {
+	s, err := readRecordStore(storedAt)
+	if err != nil {
+		return DatabaseDetails{}, err
+	}
+
+	return DatabaseDetails{
+		Ecosystem:    ecosystem,
+		StoredAt:     storedAt,
+		DownloadedAt: s.UpdatedAt,
+		ExportedAt:   s.exportedAt(),
+	}, nil
+}
+
+// readCachedDatabaseDetails reads the details of the database that is used for the ecosystem, which is
+// its record store unless its zip archive has been downloaded since, matching how databases are loaded
+func readCachedDatabaseDetails(ecosystem lockfile.Ecosystem, dir string) (DatabaseDetails, bool, error) {
+	zipAt := path.Join(dir, "all.zip")
+	zipInfo, zipErr := os.Stat(zipAt)
+
+	indexedAt := path.Join(dir, recordStoreFileName)
+	if _, err := os.Stat(indexedAt); err == nil {
+		d, err := readRecordStoreDetails(ecosystem, indexedAt)
+		if err != nil && zipErr != nil {
+			return DatabaseDetails{}, false, fmt.Errorf("%s: %w", indexedAt, err)
+		}
+		if err == nil && (zipErr != nil || !zipInfo.ModTime().After(d.DownloadedAt)) {
+			return d, true, nil
+		}
+	}
+
+	if zipErr != nil {
+		return DatabaseDetails{}, false, nil
+	}
+
+	d, err := readDatabaseDetails(ecosystem, zipAt)
+	if err != nil {
+		return DatabaseDetails{}, false, fmt.Errorf("%s: %w", zipAt, err)
+	}
+
+	return d, true, nil
+}
+
+// CachedDatabaseDetails returns the details of each local database that is cached in dbBasePath,
+// sorted by ecosystem. Unless offline, when each database was last modified on the db host
+// is also fetched, though failing to do so is not an error as a newer version may still be available.
+func CachedDatabaseDetails(dbBasePath string, offline bool, mirror Mirror) ([]DatabaseDetails, error) {
+	headers, err := mirror.headers()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dbBasePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var details []DatabaseDetails
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		d, ok, err := readCachedDatabaseDetails(lockfile.Ecosystem(entry.Name()), path.Join(dbBasePath, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		if !offline {
+			if modified, err := fetchRemoteArchiveModified(mirror.archiveURL(d.Ecosystem), headers); err == nil {
+				d.RemoteModifiedAt = modified
+			}
+		}
+
+		details = append(details, d)
+	}
+
+	return details, nil
+}
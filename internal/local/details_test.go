@@ -0,0 +1,123 @@
+package local_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"net/http"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/google/osv-scanner/internal/local"
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func zipWithModified(t *testing.T, modified map[string]time.Time) []byte {
+	t.Helper()
+
+	buf := new(bytes.Buffer)
+	writer := zip.NewWriter(buf)
+
+	for name, m := range modified {
+		f, err := writer.CreateHeader(&zip.FileHeader{Name: name, Modified: m})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = f.Write([]byte("{}")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestCachedDatabaseDetails_Offline(t *testing.T) {
+	t.Parallel()
+
+	testDir := t.TempDir()
+
+	newest := time.Date(2024, 3, 2, 10, 0, 0, 0, time.UTC)
+	downloaded := time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC)
+
+	storedAt := determineStoredAtPath(testDir, "npm")
+	cacheWrite(t, storedAt, zipWithModified(t, map[string]time.Time{
+		"GHSA-1.json": newest.Add(-time.Hour),
+		"GHSA-2.json": newest,
+		// only json files are vulnerabilities
+		"README.md": newest.Add(time.Hour),
+	}))
+	if err := os.Chtimes(storedAt, downloaded, downloaded); err != nil {
+		t.Fatal(err)
+	}
+
+	// directories without a database are skipped
+	if err := os.MkdirAll(path.Join(testDir, "PyPI"), 0750); err != nil {
+		t.Fatal(err)
+	}
+
+	details, err := local.CachedDatabaseDetails(testDir, true, local.Mirror{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(details) != 1 {
+		t.Fatalf("expected 1 database, got %d", len(details))
+	}
+
+	d := details[0]
+
+	if d.Ecosystem != lockfile.NpmEcosystem || d.StoredAt != storedAt {
+		t.Errorf("unexpected database %s at %s", d.Ecosystem, d.StoredAt)
+	}
+	if !d.ExportedAt.Equal(newest) {
+		t.Errorf("expected data to have been exported at %v, got %v", newest, d.ExportedAt)
+	}
+	if !d.DownloadedAt.Equal(downloaded) {
+		t.Errorf("expected database to have been downloaded at %v, got %v", downloaded, d.DownloadedAt)
+	}
+	if !d.RemoteModifiedAt.IsZero() {
+		t.Errorf("expected remote to not be checked when offline, got %v", d.RemoteModifiedAt)
+	}
+}
+
+func TestCachedDatabaseDetails_Online(t *testing.T) {
+	t.Parallel()
+
+	modified := time.Date(2024, 3, 4, 12, 30, 0, 0, time.UTC)
+
+	ts := createZipServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead || r.URL.Path != "/npm/all.zip" {
+			t.Errorf("unexpected %s request to %s", r.Method, r.URL.Path)
+		}
+
+		w.Header().Set("Last-Modified", modified.Format(http.TimeFormat))
+	})
+
+	testDir := t.TempDir()
+
+	cacheWrite(t, determineStoredAtPath(testDir, "npm"), zipWithModified(t, map[string]time.Time{}))
+
+	details, err := local.CachedDatabaseDetails(testDir, false, local.Mirror{URL: ts.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(details) != 1 {
+		t.Fatalf("expected 1 database, got %d", len(details))
+	}
+
+	d := details[0]
+
+	if !d.RemoteModifiedAt.Equal(modified) {
+		t.Errorf("expected remote to have been modified at %v, got %v", modified, d.RemoteModifiedAt)
+	}
+	// without any entries, the data is only as fresh as when it was downloaded
+	if !d.ExportedAt.IsZero() || !d.DataAt().Equal(d.DownloadedAt) {
+		t.Errorf("expected data to be as of when it was downloaded, got %v", d.DataAt())
+	}
+}
@@ -0,0 +1,167 @@
+package local
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+// ecosystemsURL returns the url of the list of every ecosystem that the db host has a database for
+func (m Mirror) ecosystemsURL() string {
+	host := zippedDBRemoteHost
+	if m.URL != "" {
+		host = strings.TrimSuffix(m.URL, "/")
+	}
+
+	return host + "/ecosystems.txt"
+}
+
+// fetchEcosystems fetches the ecosystems that the db host has a database for, per its ecosystems.txt
+func fetchEcosystems(ctx context.Context, mirror Mirror, headers http.Header) ([]lockfile.Ecosystem, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, mirror.ecosystemsURL(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	setRequestHeaders(req, headers)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("db host returned %s", resp.Status)
+	}
+
+	var ecosystems []lockfile.Ecosystem
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			ecosystems = append(ecosystems, lockfile.Ecosystem(line))
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return ecosystems, nil
+}
+
+// update downloads or updates the database on disk along with its package index
+func (db *ZipDB) update(ctx context.Context) error {
+	return db.load(ctx)
+}
+
+// download is a database that DownloadDatabases downloads, described by label when it cannot be
+type download struct {
+	db    *ZipDB
+	label string
+}
+
+// sourceDownloads returns the databases of every ecosystem that the advisory source has
+func (m Mirror) sourceDownloads(ctx context.Context, dbBasePath string, source AdvisorySource, headers http.Header) ([]download, error) {
+	if source.Kind == AdvisorySourceGHSA {
+		db, err := m.newSourceDB(dbBasePath, source, "", false)
+		if err != nil {
+			return nil, err
+		}
+
+		return []download{{db: db, label: "advisories from " + source.String()}}, nil
+	}
+
+	ecosystems, err := fetchEcosystems(ctx, Mirror{URL: source.URL}, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	downloads := make([]download, 0, len(ecosystems))
+	for _, ecosystem := range ecosystems {
+		db, err := m.newSourceDB(dbBasePath, source, ecosystem, false)
+		if err != nil {
+			return nil, err
+		}
+
+		downloads = append(downloads, download{db: db, label: fmt.Sprintf("db for %s ecosystem from %s", ecosystem, source)})
+	}
+
+	return downloads, nil
+}
+
+// DownloadDatabases downloads or updates the local database of every ecosystem that the db host has,
+// rather than only those of the packages being scanned, so that they are all available to later
+// offline scans, along with those of every advisory source of the mirror. The databases are not
+// loaded, which MakeRequest still does for the ecosystems it needs.
+//
+// The progress of the downloads is shown after each database if showProgress is set, and the
+// downloads stop once ctx is done.
+func DownloadDatabases(ctx context.Context, r reporter.Reporter, localDBPath string, cacheDir string, mirror Mirror, showProgress bool) error {
+	headers, err := mirror.headers()
+	if err != nil {
+		return err
+	}
+
+	dbBasePath, err := setupLocalDBDirectory(localDBPath, cacheDir)
+	if err != nil {
+		return fmt.Errorf("could not create %s: %w", dbBasePath, err)
+	}
+
+	ecosystems, err := fetchEcosystems(ctx, mirror, headers)
+	if err != nil {
+		return fmt.Errorf("could not list the ecosystems to download: %w", err)
+	}
+
+	downloads := make([]download, 0, len(ecosystems))
+	for _, ecosystem := range ecosystems {
+		db, err := mirror.newDB(dbBasePath, ecosystem, false)
+		if err != nil {
+			return err
+		}
+
+		downloads = append(downloads, download{db: db, label: fmt.Sprintf("db for %s ecosystem", ecosystem)})
+	}
+
+	for _, source := range mirror.Sources {
+		sourceDownloads, err := mirror.sourceDownloads(ctx, dbBasePath, source, headers)
+		if err != nil {
+			return fmt.Errorf("could not list the ecosystems to download from %s: %w", source, err)
+		}
+
+		downloads = append(downloads, sourceDownloads...)
+	}
+
+	failed := 0
+	if showProgress {
+		reporter.Progress(r, 0, len(downloads), "Downloaded %d/%d local databases")
+	}
+
+	for i, d := range downloads {
+		if err := d.db.update(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			r.Errorf("could not download %s: %v\n", d.label, err)
+			failed++
+		}
+
+		if showProgress {
+			reporter.Progress(r, i+1, len(downloads), "Downloaded %d/%d local databases")
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("could not download %d of %d local databases", failed, len(downloads))
+	}
+
+	r.Infof("Downloaded %d local databases to %s\n", len(downloads), dbBasePath)
+
+	return nil
+}
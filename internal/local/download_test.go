@@ -0,0 +1,129 @@
+package local_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/osv-scanner/internal/local"
+	"github.com/google/osv-scanner/internal/testutility"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+func TestDownloadDatabases(t *testing.T) {
+	t.Parallel()
+
+	testDir := testutility.CreateTestDir(t)
+
+	ts := createZipServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ecosystems.txt":
+			_, _ = io.WriteString(w, "npm\nPyPI\n\n")
+		case "/npm/all.zip", "/PyPI/all.zip":
+			_, _ = writeOSVsZip(t, w, map[string]models.Vulnerability{
+				"GHSA-1.json": {ID: "GHSA-1"},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	r := reporter.NewJSONReporter(io.Discard, io.Discard, reporter.ErrorLevel)
+
+	if err := local.DownloadDatabases(context.Background(), r, testDir, "", local.Mirror{URL: ts.URL}, false); err != nil {
+		t.Fatalf("unexpected error \"%v\"", err)
+	}
+
+	for _, ecosystem := range []string{"npm", "PyPI"} {
+		if _, err := os.Stat(determineStoredAtPath(path.Join(testDir, "osv-scanner"), ecosystem)); err != nil {
+			t.Errorf("expected the %s database to be downloaded: %v", ecosystem, err)
+		}
+	}
+}
+
+func TestDownloadDatabases_SomeFailed(t *testing.T) {
+	t.Parallel()
+
+	testDir := testutility.CreateTestDir(t)
+
+	ts := createZipServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ecosystems.txt":
+			_, _ = io.WriteString(w, "npm\nPyPI\n")
+		case "/npm/all.zip":
+			_, _ = writeOSVsZip(t, w, map[string]models.Vulnerability{
+				"GHSA-1.json": {ID: "GHSA-1"},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	stderr := &strings.Builder{}
+	r := reporter.NewJSONReporter(io.Discard, stderr, reporter.ErrorLevel)
+
+	err := local.DownloadDatabases(context.Background(), r, testDir, "", local.Mirror{URL: ts.URL}, false)
+
+	if err == nil || err.Error() != "could not download 1 of 2 local databases" {
+		t.Errorf("expected the failed download to be an error, got \"%v\"", err)
+	}
+
+	if !strings.Contains(stderr.String(), "could not download db for PyPI ecosystem") {
+		t.Errorf("expected the failed ecosystem to be reported, got %q", stderr.String())
+	}
+
+	if _, err := os.Stat(determineStoredAtPath(path.Join(testDir, "osv-scanner"), "npm")); err != nil {
+		t.Errorf("expected the npm database to still be downloaded: %v", err)
+	}
+}
+
+func TestDownloadDatabases_AdvisorySources(t *testing.T) {
+	t.Parallel()
+
+	testDir := testutility.CreateTestDir(t)
+
+	var listed atomic.Int32
+	ts := createZipServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ecosystems.txt", "/feed/ecosystems.txt":
+			listed.Add(1)
+			_, _ = io.WriteString(w, "npm\n")
+		case "/npm/all.zip", "/feed/npm/all.zip", "/ghsa/main.zip":
+			_, _ = writeOSVsZip(t, w, map[string]models.Vulnerability{
+				"GHSA-1.json": {ID: "GHSA-1"},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	var sources []local.AdvisorySource
+	for _, s := range []string{ts.URL + "/feed", "ghsa:" + ts.URL + "/ghsa/main.zip"} {
+		source, err := local.ParseAdvisorySource(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sources = append(sources, source)
+	}
+
+	stdout := &strings.Builder{}
+	r := reporter.NewJSONReporter(io.Discard, stdout, reporter.InfoLevel)
+
+	if err := local.DownloadDatabases(context.Background(), r, testDir, "", local.Mirror{URL: ts.URL, Sources: sources}, false); err != nil {
+		t.Fatalf("unexpected error \"%v\"", err)
+	}
+
+	if got := listed.Load(); got != 2 {
+		t.Errorf("expected the ecosystems of the mirror and the osv source to be listed, but %d were", got)
+	}
+
+	if !strings.Contains(stdout.String(), "Downloaded 3 local databases") {
+		t.Errorf("expected 3 local databases to be downloaded, got %q", stdout.String())
+	}
+}
@@ -0,0 +1,399 @@
+package local
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/google/osv-scanner/internal/utility/vulns"
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+const packageIndexFileName = "index.db"
+
+// packageIndexVersion is the version of the layout of the package index,
+// with indexes of any other version being built again
+const packageIndexVersion = "1"
+
+// packageIndexBatchSize is how many records are written to the package index in each transaction
+// when it is built, so that the whole database does not need to be held in memory while writing it
+const packageIndexBatchSize = 5000
+
+var (
+	// the bucket of how the index was built, so that it is only used with the database it was built from
+	indexMetaBucket = []byte("meta")
+	// the bucket of every record in the database, in the OSV schema and keyed by its ID
+	indexRecordsBucket = []byte("records")
+	// the bucket of the records affecting each package, with a bucket for each ecosystem holding a
+	// bucket for each package, which is keyed by the ID of each record affecting it with the affected
+	// ranges and versions of the package in that record
+	indexAffectedBucket = []byte("affected")
+)
+
+var errPackageIndexStale = errors.New("the package index was not built from the current database")
+
+// packageIndexSource identifies the database a package index was built from
+type packageIndexSource struct {
+	// Name is the file name of the database, being either all.zip or records.gob
+	Name string
+	// SHA256 is the checksum of the database
+	SHA256 string
+	// SignedBy identifies the key that the database was checked to be signed by, if any
+	SignedBy string
+}
+
+func (s packageIndexSource) entries() map[string]string {
+	return map[string]string{
+		"version":   packageIndexVersion,
+		"source":    s.Name,
+		"sha256":    s.SHA256,
+		"signed_by": s.SignedBy,
+	}
+}
+
+// signatureKeyID identifies the public key, which is empty when there is no key
+func signatureKeyID(key crypto.PublicKey) string {
+	if key == nil {
+		return ""
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return ""
+	}
+
+	return sha256Hex(der)
+}
+
+// indexSource returns the source of the database stored at the path with the checksum
+func (db *ZipDB) indexSource(storedAt, sum string) packageIndexSource {
+	return packageIndexSource{Name: path.Base(storedAt), SHA256: sum, SignedBy: signatureKeyID(db.SignatureKey)}
+}
+
+// openPackageIndex opens the package index at the path read-only
+func openPackageIndex(indexedAt string) (*bolt.DB, error) {
+	return bolt.Open(indexedAt, 0644, &bolt.Options{ReadOnly: true, Timeout: 10 * time.Second})
+}
+
+// checkPackageIndex checks that the package index at the path was built from the source,
+// and that it has not been changed since it was built
+func checkPackageIndex(indexedAt string, source packageIndexSource) error {
+	if source.SHA256 == "" {
+		return errPackageIndexStale
+	}
+
+	_, sum, err := hashFile(indexedAt)
+	if err != nil {
+		return err
+	}
+
+	if ok, err := matchesChecksum(indexedAt, sum); !ok || err != nil {
+		return errors.Join(errPackageIndexStale, err)
+	}
+
+	index, err := openPackageIndex(indexedAt)
+	if err != nil {
+		return err
+	}
+	defer index.Close()
+
+	return index.View(func(tx *bolt.Tx) error {
+		meta := tx.Bucket(indexMetaBucket)
+		if meta == nil {
+			return errPackageIndexStale
+		}
+
+		for key, value := range source.entries() {
+			if string(meta.Get([]byte(key))) != value {
+				return errPackageIndexStale
+			}
+		}
+
+		return nil
+	})
+}
+
+// packageIndexWriter writes records to a package index in batches
+type packageIndexWriter struct {
+	index   *bolt.DB
+	tx      *bolt.Tx
+	pending int
+}
+
+// add writes the record to the index, committing the current batch once it is full
+func (w *packageIndexWriter) add(vulnerability models.Vulnerability, content []byte) error {
+	if vulnerability.ID == "" {
+		return nil
+	}
+
+	if w.tx == nil {
+		tx, err := w.index.Begin(true)
+		if err != nil {
+			return err
+		}
+		w.tx = tx
+	}
+
+	records, err := w.tx.CreateBucketIfNotExists(indexRecordsBucket)
+	if err != nil {
+		return err
+	}
+
+	if err := records.Put([]byte(vulnerability.ID), content); err != nil {
+		return err
+	}
+
+	affected, err := w.tx.CreateBucketIfNotExists(indexAffectedBucket)
+	if err != nil {
+		return err
+	}
+
+	// a record can have more than one entry for the same package, such as for different kinds of ranges
+	type pkgKey struct{ ecosystem, name string }
+	var pkgs []pkgKey
+	entries := make(map[pkgKey][]models.Affected)
+
+	for _, a := range vulnerability.Affected {
+		k := pkgKey{string(a.Package.Ecosystem), a.Package.Name}
+		if k.ecosystem == "" || k.name == "" {
+			continue
+		}
+
+		if _, ok := entries[k]; !ok {
+			pkgs = append(pkgs, k)
+		}
+		entries[k] = append(entries[k], a)
+	}
+
+	for _, k := range pkgs {
+		ecosystem, err := affected.CreateBucketIfNotExists([]byte(k.ecosystem))
+		if err != nil {
+			return err
+		}
+
+		pkg, err := ecosystem.CreateBucketIfNotExists([]byte(k.name))
+		if err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(entries[k])
+		if err != nil {
+			return err
+		}
+
+		if err := pkg.Put([]byte(vulnerability.ID), data); err != nil {
+			return err
+		}
+	}
+
+	w.pending++
+	if w.pending >= packageIndexBatchSize {
+		return w.commit()
+	}
+
+	return nil
+}
+
+func (w *packageIndexWriter) commit() error {
+	if w.tx == nil {
+		return nil
+	}
+
+	err := w.tx.Commit()
+	w.tx = nil
+	w.pending = 0
+
+	return err
+}
+
+// writeMeta records the source of the index, in the last transaction that is committed
+func (w *packageIndexWriter) writeMeta(source packageIndexSource) error {
+	if err := w.commit(); err != nil {
+		return err
+	}
+
+	return w.index.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{indexRecordsBucket, indexAffectedBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+
+		meta, err := tx.CreateBucketIfNotExists(indexMetaBucket)
+		if err != nil {
+			return err
+		}
+
+		for key, value := range source.entries() {
+			if err := meta.Put([]byte(key), []byte(value)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// buildPackageIndex writes the package index of the records that are passed to add by the given
+// function, replacing any existing index only once the new one has been completely written
+func (db *ZipDB) buildPackageIndex(source packageIndexSource, records func(add func(models.Vulnerability, []byte) error) error) error {
+	dir := path.Dir(db.PackageIndexAt)
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(dir, packageIndexFileName+".*")
+	if err != nil {
+		return err
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+
+	// the index is only synced once it has been completely written, as a partial one is never used
+	index, err := bolt.Open(f.Name(), 0644, &bolt.Options{NoSync: true, NoFreelistSync: true})
+	if err != nil {
+		return err
+	}
+
+	w := &packageIndexWriter{index: index}
+
+	err = records(w.add)
+	if err == nil {
+		err = w.writeMeta(source)
+	}
+	if w.tx != nil {
+		_ = w.tx.Rollback()
+	}
+	if err == nil {
+		err = index.Sync()
+	}
+	if closeErr := index.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	_, sum, err := hashFile(f.Name())
+	if err != nil {
+		return err
+	}
+
+	//nolint:gosec // being world readable is fine, like the databases
+	if err := os.Chmod(f.Name(), 0644); err != nil {
+		return err
+	}
+
+	if err := os.Rename(f.Name(), db.PackageIndexAt); err != nil {
+		return err
+	}
+
+	return writeChecksum(db.PackageIndexAt, sum)
+}
+
+// useOrBuildPackageIndex uses the package index of the database if it was built from the source,
+// otherwise building it from the records that are passed to add by the given function
+func (db *ZipDB) useOrBuildPackageIndex(source packageIndexSource, records func(add func(models.Vulnerability, []byte) error) error) error {
+	if checkPackageIndex(db.PackageIndexAt, source) == nil {
+		return nil
+	}
+
+	if err := db.buildPackageIndex(source, records); err != nil {
+		return fmt.Errorf("could not index %s: %w", db.StoredAt, err)
+	}
+
+	return nil
+}
+
+// viewPackageIndex calls fn with a read-only transaction of the package index
+func (db *ZipDB) viewPackageIndex(fn func(tx *bolt.Tx) error) error {
+	index, err := openPackageIndex(db.PackageIndexAt)
+	if err != nil {
+		return err
+	}
+	defer index.Close()
+
+	return index.View(fn)
+}
+
+// Vulnerabilities returns every vulnerability in the database, ordered by their ID
+func (db *ZipDB) Vulnerabilities(includeWithdrawn bool) []models.Vulnerability {
+	vulnerabilities := []models.Vulnerability{}
+
+	err := db.viewPackageIndex(func(tx *bolt.Tx) error {
+		return tx.Bucket(indexRecordsBucket).ForEach(func(id, content []byte) error {
+			var vulnerability models.Vulnerability
+			if err := json.Unmarshal(content, &vulnerability); err != nil {
+				return fmt.Errorf("%s is not a valid JSON record: %w", id, err)
+			}
+
+			if includeWithdrawn || vulnerability.Withdrawn.IsZero() {
+				vulnerabilities = append(vulnerabilities, vulnerability)
+			}
+
+			return nil
+		})
+	})
+
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Could not read %s: %v\n", db.PackageIndexAt, err)
+	}
+
+	return vulnerabilities
+}
+
+// VulnerabilitiesAffectingPackage returns the vulnerabilities that affect the package, only reading
+// the records that have an entry for it in the package index rather than every record in the database
+func (db *ZipDB) VulnerabilitiesAffectingPackage(pkg lockfile.PackageDetails) models.Vulnerabilities {
+	var vulnerabilities models.Vulnerabilities
+
+	err := db.viewPackageIndex(func(tx *bolt.Tx) error {
+		ecosystem := tx.Bucket(indexAffectedBucket).Bucket([]byte(pkg.Ecosystem))
+		if ecosystem == nil {
+			return nil
+		}
+
+		affecting := ecosystem.Bucket([]byte(pkg.Name))
+		if affecting == nil {
+			return nil
+		}
+
+		records := tx.Bucket(indexRecordsBucket)
+
+		return affecting.ForEach(func(id, entries []byte) error {
+			// only the entries for the package are needed to check if it is affected
+			candidate := models.Vulnerability{ID: string(id)}
+			if err := json.Unmarshal(entries, &candidate.Affected); err != nil {
+				return fmt.Errorf("%s is not indexed correctly: %w", id, err)
+			}
+
+			if !vulns.IsAffected(candidate, pkg) {
+				return nil
+			}
+
+			var vulnerability models.Vulnerability
+			if err := json.Unmarshal(records.Get(id), &vulnerability); err != nil {
+				return fmt.Errorf("%s is not a valid JSON record: %w", id, err)
+			}
+
+			if vulnerability.Withdrawn.IsZero() && !vulns.Include(vulnerabilities, vulnerability) {
+				vulnerabilities = append(vulnerabilities, vulnerability)
+			}
+
+			return nil
+		})
+	})
+
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Could not read %s: %v\n", db.PackageIndexAt, err)
+	}
+
+	return vulnerabilities
+}
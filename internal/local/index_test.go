@@ -0,0 +1,135 @@
+package local_test
+
+import (
+	"context"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/osv-scanner/internal/local"
+	"github.com/google/osv-scanner/internal/testutility"
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func affecting(name string, introduced, fixed string) models.Affected {
+	return models.Affected{
+		Package: models.Package{Ecosystem: "npm", Name: name},
+		Ranges: []models.Range{{
+			Type:   models.RangeSemVer,
+			Events: []models.Event{{Introduced: introduced}, {Fixed: fixed}},
+		}},
+	}
+}
+
+func expectAffectingIDs(t *testing.T, db *local.ZipDB, pkg lockfile.PackageDetails, expect []string) {
+	t.Helper()
+
+	ids := make([]string, 0, len(expect))
+	for _, vulnerability := range db.VulnerabilitiesAffectingPackage(pkg) {
+		ids = append(ids, vulnerability.ID)
+	}
+
+	if !reflect.DeepEqual(ids, expect) {
+		t.Errorf("expected %s@%s to be affected by %v, got %v", pkg.Name, pkg.Version, expect, ids)
+	}
+}
+
+func TestZipDB_VulnerabilitiesAffectingPackage(t *testing.T) {
+	t.Parallel()
+
+	testDir := testutility.CreateTestDir(t)
+
+	cacheWrite(t, determineStoredAtPath(testDir, "npm"), zipOSVs(t, map[string]models.Vulnerability{
+		"GHSA-1.json": {ID: "GHSA-1", Affected: []models.Affected{affecting("lodash", "0", "4.17.21")}},
+		"GHSA-2.json": {ID: "GHSA-2", Affected: []models.Affected{
+			affecting("lodash", "0", "4.17.12"),
+			affecting("underscore", "1.3.2", "1.12.1"),
+		}},
+		"GHSA-3.json": {ID: "GHSA-3", Affected: []models.Affected{affecting("underscore", "0", "1.12.1")}},
+		"GHSA-4.json": {
+			ID:        "GHSA-4",
+			Withdrawn: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			Affected:  []models.Affected{affecting("lodash", "0", "4.17.21")},
+		},
+	}))
+
+	db, err := local.NewZippedDB(context.Background(), testDir, "npm", "https://example.com/npm/all.zip", nil, true)
+	if err != nil {
+		t.Fatalf("unexpected error \"%v\"", err)
+	}
+
+	if _, err := os.Stat(path.Join(testDir, "npm", "index.db")); err != nil {
+		t.Errorf("expected the database to be indexed: %v", err)
+	}
+
+	expectAffectingIDs(t, db, lockfile.PackageDetails{Name: "lodash", Version: "4.17.11", Ecosystem: "npm", CompareAs: "npm"}, []string{"GHSA-1", "GHSA-2"})
+	expectAffectingIDs(t, db, lockfile.PackageDetails{Name: "lodash", Version: "4.17.20", Ecosystem: "npm", CompareAs: "npm"}, []string{"GHSA-1"})
+	expectAffectingIDs(t, db, lockfile.PackageDetails{Name: "lodash", Version: "4.17.21", Ecosystem: "npm", CompareAs: "npm"}, []string{})
+	expectAffectingIDs(t, db, lockfile.PackageDetails{Name: "underscore", Version: "1.0.0", Ecosystem: "npm", CompareAs: "npm"}, []string{"GHSA-3"})
+	expectAffectingIDs(t, db, lockfile.PackageDetails{Name: "underscore", Version: "1.5.0", Ecosystem: "npm", CompareAs: "npm"}, []string{"GHSA-2", "GHSA-3"})
+	expectAffectingIDs(t, db, lockfile.PackageDetails{Name: "left-pad", Version: "1.0.0", Ecosystem: "npm", CompareAs: "npm"}, []string{})
+	expectAffectingIDs(t, db, lockfile.PackageDetails{Name: "lodash", Version: "4.17.11", Ecosystem: "PyPI", CompareAs: "PyPI"}, []string{})
+}
+
+func TestZipDB_PackageIndex_DatabaseChanged(t *testing.T) {
+	t.Parallel()
+
+	testDir := testutility.CreateTestDir(t)
+	pkg := lockfile.PackageDetails{Name: "lodash", Version: "4.17.11", Ecosystem: "npm", CompareAs: "npm"}
+
+	cacheWrite(t, determineStoredAtPath(testDir, "npm"), zipOSVs(t, map[string]models.Vulnerability{
+		"GHSA-1.json": {ID: "GHSA-1", Affected: []models.Affected{affecting("lodash", "0", "4.17.21")}},
+	}))
+
+	db, err := local.NewZippedDB(context.Background(), testDir, "npm", "https://example.com/npm/all.zip", nil, true)
+	if err != nil {
+		t.Fatalf("unexpected error \"%v\"", err)
+	}
+
+	expectAffectingIDs(t, db, pkg, []string{"GHSA-1"})
+
+	// a newer archive has been downloaded manually
+	cacheWrite(t, determineStoredAtPath(testDir, "npm"), zipOSVs(t, map[string]models.Vulnerability{
+		"GHSA-2.json": {ID: "GHSA-2", Affected: []models.Affected{affecting("lodash", "0", "4.17.21")}},
+	}))
+
+	db, err = local.NewZippedDB(context.Background(), testDir, "npm", "https://example.com/npm/all.zip", nil, true)
+	if err != nil {
+		t.Fatalf("unexpected error \"%v\"", err)
+	}
+
+	expectAffectingIDs(t, db, pkg, []string{"GHSA-2"})
+}
+
+func TestZipDB_PackageIndex_Corrupted(t *testing.T) {
+	t.Parallel()
+
+	testDir := testutility.CreateTestDir(t)
+	pkg := lockfile.PackageDetails{Name: "lodash", Version: "4.17.11", Ecosystem: "npm", CompareAs: "npm"}
+
+	cacheWrite(t, determineStoredAtPath(testDir, "npm"), zipOSVs(t, map[string]models.Vulnerability{
+		"GHSA-1.json": {ID: "GHSA-1", Affected: []models.Affected{affecting("lodash", "0", "4.17.21")}},
+	}))
+
+	if _, err := local.NewZippedDB(context.Background(), testDir, "npm", "https://example.com/npm/all.zip", nil, true); err != nil {
+		t.Fatalf("unexpected error \"%v\"", err)
+	}
+
+	indexedAt := path.Join(testDir, "npm", "index.db")
+	content, err := os.ReadFile(indexedAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cacheWrite(t, indexedAt, content[:len(content)/2])
+
+	db, err := local.NewZippedDB(context.Background(), testDir, "npm", "https://example.com/npm/all.zip", nil, true)
+	if err != nil {
+		t.Fatalf("unexpected error \"%v\"", err)
+	}
+
+	expectAffectingIDs(t, db, pkg, []string{"GHSA-1"})
+}
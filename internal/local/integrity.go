@@ -0,0 +1,328 @@
+package local
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+// checksumSuffix is that of the file next to each database that records its checksum when it was
+// stored, in the same format as sha256sum so that it can also be checked with that
+const checksumSuffix = ".sha256"
+
+// signatureSuffix is that of the signature of each zip archive, both on the db host and on disk
+const signatureSuffix = ".sig"
+
+// ErrDatabaseCorrupted is returned for local databases that no longer match the checksum
+// recorded when they were stored, or whose signature is not valid
+var ErrDatabaseCorrupted = errors.New("the local OSV database has been corrupted or tampered with")
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// writeChecksum records the checksum of the database stored at the path
+func writeChecksum(storedAt string, sum string) error {
+	//nolint:gosec // being world readable is fine, like the databases
+	return os.WriteFile(storedAt+checksumSuffix, []byte(sum+"  "+path.Base(storedAt)+"\n"), 0644)
+}
+
+// verifyChecksum checks that the database stored at the path still matches the checksum that was
+// recorded when it was stored, returning whether there was one, as there is not for databases that
+// were downloaded manually
+func verifyChecksum(storedAt string, data []byte) (bool, error) {
+	return matchesChecksum(storedAt, sha256Hex(data))
+}
+
+// matchesChecksum checks the checksum of the database stored at the path against
+// the one that was recorded when it was stored, returning whether there was one
+func matchesChecksum(storedAt string, sum string) (bool, error) {
+	recorded, err := os.ReadFile(storedAt + checksumSuffix)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if want, _, _ := strings.Cut(strings.TrimSpace(string(recorded)), " "); sum != want {
+		return true, fmt.Errorf("%w: %s does not match its checksum", ErrDatabaseCorrupted, storedAt)
+	}
+
+	return true, nil
+}
+
+// removeDatabaseFiles removes the database stored at the path along with its checksum and signature
+func removeDatabaseFiles(storedAt string) {
+	for _, p := range []string{storedAt, storedAt + checksumSuffix, storedAt + signatureSuffix} {
+		_ = os.Remove(p)
+	}
+}
+
+// signatureKey returns the public key that archives must be signed by, if there is one
+func (m Mirror) signatureKey() (crypto.PublicKey, error) {
+	if m.SignatureKey == "" {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(m.SignatureKey)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the signature key: %w", err)
+	}
+
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not a PEM encoded public key", m.SignatureKey)
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the signature key: %w", err)
+	}
+
+	switch key.(type) {
+	case *ecdsa.PublicKey, ed25519.PublicKey, *rsa.PublicKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("%s is an unsupported type of public key", m.SignatureKey)
+	}
+}
+
+// verifySignature checks that the base64 encoded signature is of the data and was made by the key,
+// in the same way as signatures from "cosign sign-blob --key" are verified. Keyless signatures are
+// not supported, since checking their certificates requires access to Sigstore's transparency log
+func verifySignature(key crypto.PublicKey, data []byte, encoded []byte) error {
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+	if err != nil {
+		return fmt.Errorf("could not decode the signature: %w", err)
+	}
+
+	digest := sha256.Sum256(data)
+
+	var valid bool
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		valid = ecdsa.VerifyASN1(k, digest[:], signature)
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(k, data, signature)
+	case *rsa.PublicKey:
+		valid = rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], signature) == nil
+	}
+
+	if !valid {
+		return fmt.Errorf("%w: the signature is not valid", ErrDatabaseCorrupted)
+	}
+
+	return nil
+}
+
+// fetchSignature fetches the signature of the zip archive from next to it on the db host, checking
+// that it is a valid signature of the archive
+func (db *ZipDB) fetchSignature(ctx context.Context, archive []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, db.ArchiveURL+signatureSuffix, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	setRequestHeaders(req, db.Headers)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve the signature of the OSV database archive: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("db host returned %s for the signature of the OSV database archive", resp.Status)
+	}
+
+	signature, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifySignature(db.SignatureKey, archive, signature); err != nil {
+		return nil, fmt.Errorf("downloaded OSV database archive is not signed by the signature key: %w", err)
+	}
+
+	return signature, nil
+}
+
+// readStoredZip reads the zip archive stored on disk, checking that it has not changed since
+// it was downloaded and that it is signed by the signature key if there is one
+func (db *ZipDB) readStoredZip() ([]byte, error) {
+	data, err := os.ReadFile(db.StoredAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := verifyChecksum(db.StoredAt, data); err != nil {
+		return nil, err
+	}
+
+	if db.SignatureKey != nil {
+		signature, err := os.ReadFile(db.StoredAt + signatureSuffix)
+		if err != nil {
+			return nil, fmt.Errorf("%s has not been signed: %w", db.StoredAt, err)
+		}
+
+		if err := verifySignature(db.SignatureKey, data, signature); err != nil {
+			return nil, fmt.Errorf("%s: %w", db.StoredAt, err)
+		}
+	}
+
+	return data, nil
+}
+
+// DatabaseVerification is the result of verifying a local database
+type DatabaseVerification struct {
+	Ecosystem lockfile.Ecosystem
+	StoredAt  string
+	// Checksummed is whether the database had a checksum that was checked, which is only
+	// recorded for databases that were downloaded or imported by osv-scanner
+	Checksummed bool
+	// Signed is whether the signature of the database was checked
+	Signed bool
+	// Err is why the database failed to be verified, if it did
+	Err error
+}
+
+// verifyZipContent checks that every record in the zip archive can be read
+func verifyZipContent(data []byte) error {
+	zipReader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrDatabaseCorrupted, err)
+	}
+
+	for _, zipFile := range zipReader.File {
+		if !strings.HasSuffix(zipFile.Name, ".json") {
+			continue
+		}
+
+		content, err := readZipFile(zipFile)
+		if err != nil {
+			return fmt.Errorf("%w: could not read %s: %w", ErrDatabaseCorrupted, zipFile.Name, err)
+		}
+
+		if !json.Valid(content) {
+			return fmt.Errorf("%w: %s is not a valid JSON file", ErrDatabaseCorrupted, zipFile.Name)
+		}
+	}
+
+	return nil
+}
+
+// verifyRecordStoreContent checks that every record in the record store can be read
+func verifyRecordStoreContent(data []byte) error {
+	var s recordStore
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&s); err != nil {
+		return fmt.Errorf("%w: %w", ErrDatabaseCorrupted, err)
+	}
+
+	for id, record := range s.Records {
+		var summary recordSummary
+		if err := json.Unmarshal(record.JSON, &summary); err != nil || summary.ID != id {
+			return fmt.Errorf("%w: %s is not a valid JSON record", ErrDatabaseCorrupted, id)
+		}
+	}
+
+	return nil
+}
+
+func verifyDatabase(ecosystem lockfile.Ecosystem, storedAt string, key crypto.PublicKey) DatabaseVerification {
+	v := DatabaseVerification{Ecosystem: ecosystem, StoredAt: storedAt}
+
+	data, err := os.ReadFile(storedAt)
+	if err != nil {
+		v.Err = err
+
+		return v
+	}
+
+	if v.Checksummed, v.Err = verifyChecksum(storedAt, data); v.Err != nil {
+		return v
+	}
+
+	isStore := path.Base(storedAt) == recordStoreFileName
+	if isStore {
+		v.Err = verifyRecordStoreContent(data)
+	} else {
+		v.Err = verifyZipContent(data)
+	}
+	if v.Err != nil || key == nil {
+		return v
+	}
+
+	if isStore {
+		v.Err = errors.New("record stores are updated one record at a time, so cannot be checked against signatures")
+
+		return v
+	}
+
+	signature, err := os.ReadFile(storedAt + signatureSuffix)
+	if err != nil {
+		v.Err = fmt.Errorf("the archive has not been signed: %w", err)
+
+		return v
+	}
+
+	v.Signed = true
+	v.Err = verifySignature(key, data, signature)
+
+	return v
+}
+
+// VerifyDatabases checks every local database that is cached in dbBasePath, sorted by ecosystem,
+// against the checksum recorded when it was stored, and that every record in it can be read.
+// If the mirror has a signature key, zip archives are also checked to have been signed by it.
+func VerifyDatabases(dbBasePath string, mirror Mirror) ([]DatabaseVerification, error) {
+	key, err := mirror.signatureKey()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dbBasePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var verifications []DatabaseVerification
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		for _, name := range []string{"all.zip", recordStoreFileName} {
+			storedAt := path.Join(dbBasePath, entry.Name(), name)
+			if _, err := os.Stat(storedAt); err != nil {
+				continue
+			}
+
+			verifications = append(verifications, verifyDatabase(lockfile.Ecosystem(entry.Name()), storedAt, key))
+		}
+	}
+
+	return verifications, nil
+}
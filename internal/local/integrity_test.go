@@ -0,0 +1,314 @@
+package local_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/osv-scanner/internal/local"
+	"github.com/google/osv-scanner/internal/testutility"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+func TestNewZippedDB_Offline_Corrupted(t *testing.T) {
+	t.Parallel()
+
+	testDir := testutility.CreateTestDir(t)
+
+	ts := createZipServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = writeOSVsZip(t, w, map[string]models.Vulnerability{
+			"GHSA-1.json": {ID: "GHSA-1"},
+		})
+	})
+
+	if _, err := local.NewZippedDB(context.Background(), testDir, "my-db", ts.URL, nil, false); err != nil {
+		t.Fatalf("unexpected error \"%v\"", err)
+	}
+
+	// the archive is replaced with one that is still valid, but not what was downloaded
+	cacheWrite(t, determineStoredAtPath(testDir, "my-db"), zipOSVs(t, map[string]models.Vulnerability{
+		"GHSA-2.json": {ID: "GHSA-2"},
+	}))
+
+	_, err := local.NewZippedDB(context.Background(), testDir, "my-db", ts.URL, nil, true)
+
+	if !errors.Is(err, local.ErrDatabaseCorrupted) {
+		t.Errorf("expected the database to be corrupted, got \"%v\"", err)
+	}
+}
+
+func TestNewZippedDB_Online_Corrupted(t *testing.T) {
+	t.Parallel()
+
+	testDir := testutility.CreateTestDir(t)
+
+	var downloads atomic.Int32
+	ts := createZipServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			downloads.Add(1)
+		}
+
+		_, _ = writeOSVsZip(t, w, map[string]models.Vulnerability{
+			"GHSA-1.json": {ID: "GHSA-1"},
+		})
+	})
+
+	if _, err := local.NewZippedDB(context.Background(), testDir, "my-db", ts.URL, nil, false); err != nil {
+		t.Fatalf("unexpected error \"%v\"", err)
+	}
+
+	cacheWrite(t, determineStoredAtPath(testDir, "my-db"), zipOSVs(t, map[string]models.Vulnerability{
+		"GHSA-2.json": {ID: "GHSA-2"},
+	}))
+
+	db, err := local.NewZippedDB(context.Background(), testDir, "my-db", ts.URL, nil, false)
+	if err != nil {
+		t.Fatalf("unexpected error \"%v\"", err)
+	}
+
+	expectDBToHaveOSVs(t, db, []models.Vulnerability{{ID: "GHSA-1"}})
+
+	if got := downloads.Load(); got != 2 {
+		t.Errorf("expected the corrupted database to be downloaded again, but it was downloaded %d times", got)
+	}
+}
+
+func TestNewZippedDB_Online_DownloadDoesNotMatchChecksum(t *testing.T) {
+	t.Parallel()
+
+	testDir := testutility.CreateTestDir(t)
+
+	ts := createZipServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("x-goog-hash", "crc32c="+computeCRC32CHash(t, []byte("something else")))
+		_, _ = w.Write(zipOSVs(t, map[string]models.Vulnerability{
+			"GHSA-1.json": {ID: "GHSA-1"},
+		}))
+	})
+
+	_, err := local.NewZippedDB(context.Background(), testDir, "my-db", ts.URL, nil, false)
+
+	if err == nil || !strings.Contains(err.Error(), "does not match its checksum") {
+		t.Errorf("expected the download to not match its checksum, got \"%v\"", err)
+	}
+
+	if _, err := os.Stat(determineStoredAtPath(testDir, "my-db")); err == nil {
+		t.Errorf("expected the download to not be stored")
+	}
+}
+
+func TestNewZippedDB_Offline_EntryDoesNotMatchChecksum(t *testing.T) {
+	t.Parallel()
+
+	testDir := testutility.CreateTestDir(t)
+
+	buf := new(bytes.Buffer)
+	writer := zip.NewWriter(buf)
+	f, err := writer.CreateHeader(&zip.FileHeader{Name: "GHSA-1.json", Method: zip.Store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte(`{"id":"GHSA-1"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// an archive that was downloaded manually, so has no checksum of its own
+	cacheWrite(t, determineStoredAtPath(testDir, "my-db"), bytes.Replace(buf.Bytes(), []byte(`"GHSA-1"`), []byte(`"GHSA-2"`), 1))
+
+	_, err = local.NewZippedDB(context.Background(), testDir, "my-db", "https://example.com/my-db/all.zip", nil, true)
+
+	if !errors.Is(err, local.ErrDatabaseCorrupted) {
+		t.Errorf("expected the database to be corrupted, got \"%v\"", err)
+	}
+}
+
+// writeSignatureKey generates a key pair, writing the public key to the directory
+func writeSignatureKey(t *testing.T, dir string) (*ecdsa.PrivateKey, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := path.Join(dir, "cosign.pub")
+	cacheWrite(t, p, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	return key, p
+}
+
+func sign(t *testing.T, key *ecdsa.PrivateKey, data []byte) []byte {
+	t.Helper()
+
+	digest := sha256.Sum256(data)
+	signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return []byte(base64.StdEncoding.EncodeToString(signature))
+}
+
+func TestDownloadDatabases_Signed(t *testing.T) {
+	t.Parallel()
+
+	key, keyPath := writeSignatureKey(t, testutility.CreateTestDir(t))
+	archive := zipOSVs(t, map[string]models.Vulnerability{"GHSA-1.json": {ID: "GHSA-1"}})
+
+	tests := []struct {
+		name      string
+		signature []byte
+		wantErr   bool
+	}{
+		{name: "valid signature", signature: sign(t, key, archive)},
+		{name: "signature of something else", signature: sign(t, key, []byte("something else")), wantErr: true},
+		{name: "no signature", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			testDir := testutility.CreateTestDir(t)
+
+			ts := createZipServer(t, func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/ecosystems.txt":
+					_, _ = io.WriteString(w, "npm\n")
+				case "/npm/all.zip":
+					_, _ = w.Write(archive)
+				case "/npm/all.zip.sig":
+					if tt.signature == nil {
+						http.NotFound(w, r)
+
+						return
+					}
+					_, _ = w.Write(tt.signature)
+				default:
+					http.NotFound(w, r)
+				}
+			})
+
+			r := reporter.NewJSONReporter(io.Discard, io.Discard, reporter.ErrorLevel)
+			mirror := local.Mirror{URL: ts.URL, SignatureKey: keyPath}
+
+			err := local.DownloadDatabases(context.Background(), r, testDir, "", mirror, false)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected an error to be %t, got \"%v\"", tt.wantErr, err)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			verifications, err := local.VerifyDatabases(path.Join(testDir, "osv-scanner"), mirror)
+			if err != nil {
+				t.Fatalf("unexpected error \"%v\"", err)
+			}
+
+			if len(verifications) != 1 || verifications[0].Err != nil || !verifications[0].Checksummed || !verifications[0].Signed {
+				t.Errorf("expected the database to be verified, got %+v", verifications)
+			}
+		})
+	}
+}
+
+func TestDownloadDatabases_SignatureCancelled(t *testing.T) {
+	t.Parallel()
+
+	testDir := testutility.CreateTestDir(t)
+	_, keyPath := writeSignatureKey(t, testDir)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := createZipServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ecosystems.txt":
+			_, _ = io.WriteString(w, "npm\n")
+		case "/npm/all.zip":
+			_, _ = writeOSVsZip(t, w, map[string]models.Vulnerability{"GHSA-1.json": {ID: "GHSA-1"}})
+		case "/npm/all.zip.sig":
+			// the signature never arrives, so the download only ends if it is cancelled
+			cancel()
+			<-r.Context().Done()
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	r := reporter.NewJSONReporter(io.Discard, io.Discard, reporter.ErrorLevel)
+
+	err := local.DownloadDatabases(ctx, r, testDir, "", local.Mirror{URL: ts.URL, SignatureKey: keyPath}, false)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the download to be cancelled, got \"%v\"", err)
+	}
+}
+
+func TestVerifyDatabases(t *testing.T) {
+	t.Parallel()
+
+	testDir := testutility.CreateTestDir(t)
+
+	ts := createZipServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = writeOSVsZip(t, w, map[string]models.Vulnerability{
+			"GHSA-1.json": {ID: "GHSA-1"},
+		})
+	})
+
+	for _, name := range []string{"Go", "npm"} {
+		if _, err := local.NewZippedDB(context.Background(), testDir, name, ts.URL, nil, false); err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+	}
+
+	// npm is corrupted after being downloaded, while PyPI was downloaded manually
+	cacheWrite(t, determineStoredAtPath(testDir, "npm"), []byte("not an archive"))
+	cacheWrite(t, determineStoredAtPath(testDir, "PyPI"), zipOSVs(t, map[string]models.Vulnerability{
+		"PYSEC-1.json": {ID: "PYSEC-1"},
+	}))
+
+	verifications, err := local.VerifyDatabases(testDir, local.Mirror{})
+	if err != nil {
+		t.Fatalf("unexpected error \"%v\"", err)
+	}
+
+	if len(verifications) != 3 {
+		t.Fatalf("expected 3 databases to be verified, got %d", len(verifications))
+	}
+
+	if v := verifications[0]; v.Ecosystem != "Go" || v.Err != nil || !v.Checksummed {
+		t.Errorf("expected Go to be verified against its checksum, got %+v", v)
+	}
+	if v := verifications[1]; v.Ecosystem != "PyPI" || v.Err != nil || v.Checksummed {
+		t.Errorf("expected PyPI to be verified without a checksum, got %+v", v)
+	}
+	if v := verifications[2]; v.Ecosystem != "npm" || !errors.Is(v.Err, local.ErrDatabaseCorrupted) {
+		t.Errorf("expected npm to be corrupted, got %+v", v)
+	}
+}
@@ -0,0 +1,223 @@
+package local
+
+import (
+	"context"
+	"encoding/gob"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/osv-scanner/internal/cachedir"
+	"github.com/google/osv-scanner/pkg/osv"
+)
+
+const queryCacheFileName = "queries.gob"
+
+// QueryCache stores the IDs of the vulnerabilities that were returned when querying OSV
+// for a package, keyed by the package and the snapshot of its ecosystem's database
+// that was current at the time, so that repeated scans don't have to re-query packages
+// whose results cannot have changed.
+//
+// The snapshot of an ecosystem is identified by the checksum of its zipped database,
+// meaning cached results expire as soon as the database is updated, unless they were
+// cached more recently than the TTL of the cache.
+type QueryCache struct {
+	entries   map[string]queryCacheEntry
+	storedAt  string
+	host      string
+	headers   http.Header
+	snapshots map[string]string
+	ttl       time.Duration
+}
+
+type queryCacheEntry struct {
+	Ecosystem string
+	Snapshot  string
+	IDs       []string
+	// Modified is when each of the vulnerabilities was last modified, which is
+	// empty for entries that were cached before it was stored
+	Modified []time.Time
+	StoredAt time.Time
+}
+
+// LoadQueryCache loads the query cache from the queries subdirectory of cacheDir, or of the
+// default cache directory if that is empty, using the mirror to determine the current
+// snapshot of each ecosystem.
+//
+// If ttl is greater than zero, results that were cached less than ttl ago are used even if the
+// snapshot of their ecosystem has changed since, trading how up to date they are for not having
+// to query OSV for them again.
+//
+// A cache that does not exist or cannot be read is treated as being empty.
+func LoadQueryCache(cacheDir string, mirror Mirror, ttl time.Duration) (*QueryCache, error) {
+	queriesPath, err := cachedir.Subdirectory(cacheDir, cachedir.Queries)
+	if err != nil {
+		return nil, fmt.Errorf("could not create %s: %w", queriesPath, err)
+	}
+
+	headers, err := mirror.headers()
+	if err != nil {
+		return nil, err
+	}
+
+	host := zippedDBRemoteHost
+	if mirror.URL != "" {
+		host = strings.TrimSuffix(mirror.URL, "/")
+	}
+
+	cache := &QueryCache{
+		entries:   make(map[string]queryCacheEntry),
+		storedAt:  path.Join(queriesPath, queryCacheFileName),
+		host:      host,
+		headers:   headers,
+		snapshots: make(map[string]string),
+		ttl:       ttl,
+	}
+
+	if f, err := os.Open(cache.storedAt); err == nil {
+		defer f.Close()
+
+		// a corrupted cache is just as good as no cache, so ignore any errors
+		if err := gob.NewDecoder(f).Decode(&cache.entries); err != nil {
+			cache.entries = make(map[string]queryCacheEntry)
+		}
+	}
+
+	return cache, nil
+}
+
+// queryCacheKey returns the key to store the results of the query under, and the ecosystem
+// whose database the results come from. The key is empty if the query cannot be cached.
+func queryCacheKey(query *osv.Query) (string, string) {
+	if query.Commit != "" || query.Package.PURL != "" {
+		return "", ""
+	}
+
+	if query.Package.Ecosystem == "" || query.Package.Name == "" || query.Version == "" {
+		return "", ""
+	}
+
+	ecosystem, _, _ := strings.Cut(query.Package.Ecosystem, ":")
+
+	return strings.Join([]string{query.Package.Ecosystem, query.Package.Name, query.Version}, "\x00"), ecosystem
+}
+
+// snapshot returns the current snapshot of the database for the ecosystem,
+// or an empty string if it could not be determined
+func (c *QueryCache) snapshot(ecosystem string) string {
+	if snapshot, ok := c.snapshots[ecosystem]; ok {
+		return snapshot
+	}
+
+	snapshot := ""
+	hash, err := fetchRemoteArchiveCRC32CHash(context.Background(), fmt.Sprintf("%s/%s/all.zip", c.host, ecosystem), c.headers)
+	if err == nil {
+		snapshot = fmt.Sprintf("%08x", hash)
+	}
+
+	c.snapshots[ecosystem] = snapshot
+
+	return snapshot
+}
+
+// fresh returns whether the entry was cached recently enough to be used regardless of its snapshot
+func (c *QueryCache) fresh(entry queryCacheEntry) bool {
+	return c.ttl > 0 && time.Since(entry.StoredAt) < c.ttl
+}
+
+// Get returns the cached vulnerabilities for the query, if there are any
+// that are from the current snapshot of the ecosystem's database or are fresh
+func (c *QueryCache) Get(query *osv.Query) ([]osv.MinimalVulnerability, bool) {
+	key, ecosystem := queryCacheKey(query)
+	if key == "" {
+		return nil, false
+	}
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	if !c.fresh(entry) {
+		snapshot := c.snapshot(ecosystem)
+		if snapshot == "" || entry.Snapshot != snapshot {
+			return nil, false
+		}
+	}
+
+	vulns := make([]osv.MinimalVulnerability, 0, len(entry.IDs))
+	for i, id := range entry.IDs {
+		vuln := osv.MinimalVulnerability{ID: id}
+		if len(entry.Modified) == len(entry.IDs) {
+			vuln.Modified = entry.Modified[i]
+		}
+		vulns = append(vulns, vuln)
+	}
+
+	return vulns, true
+}
+
+// Set stores the vulnerabilities for the query against the current snapshot of the ecosystem's
+// database, doing nothing if the query cannot be cached or the snapshot cannot be determined
+// (unless the cache has a TTL, in which case the results are used until they are no longer fresh)
+func (c *QueryCache) Set(query *osv.Query, vulns []osv.MinimalVulnerability) {
+	key, ecosystem := queryCacheKey(query)
+	if key == "" {
+		return
+	}
+
+	snapshot := c.snapshot(ecosystem)
+	if snapshot == "" && c.ttl <= 0 {
+		return
+	}
+
+	ids := make([]string, 0, len(vulns))
+	modified := make([]time.Time, 0, len(vulns))
+	for _, vuln := range vulns {
+		ids = append(ids, vuln.ID)
+		modified = append(modified, vuln.Modified)
+	}
+
+	c.entries[key] = queryCacheEntry{
+		Ecosystem: ecosystem,
+		Snapshot:  snapshot,
+		IDs:       ids,
+		Modified:  modified,
+		StoredAt:  time.Now(),
+	}
+}
+
+// Write saves the cache to disk, dropping any entries that are known to be from an
+// outdated snapshot of their ecosystem's database and are no longer fresh
+func (c *QueryCache) Write() error {
+	for key, entry := range c.entries {
+		if c.fresh(entry) {
+			continue
+		}
+		if snapshot, ok := c.snapshots[entry.Ecosystem]; ok && snapshot != "" && snapshot != entry.Snapshot {
+			delete(c.entries, key)
+		}
+	}
+
+	// write to a temporary file first, so that concurrent scans never read a partial cache
+	f, err := os.CreateTemp(path.Dir(c.storedAt), queryCacheFileName+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if err := gob.NewEncoder(f).Encode(c.entries); err != nil {
+		f.Close()
+
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), c.storedAt)
+}
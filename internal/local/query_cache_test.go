@@ -0,0 +1,193 @@
+package local_test
+
+import (
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/osv-scanner/internal/local"
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/osv"
+)
+
+func createSnapshotServer(t *testing.T, snapshot *atomic.Value) local.Mirror {
+	t.Helper()
+
+	ts := createZipServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/npm/all.zip" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		if s, _ := snapshot.Load().(string); s != "" {
+			w.Header().Add("x-goog-hash", "crc32c="+computeCRC32CHash(t, []byte(s)))
+		}
+	})
+
+	return local.Mirror{URL: ts.URL}
+}
+
+func loadQueryCache(t *testing.T, dbBasePath string, mirror local.Mirror) *local.QueryCache {
+	t.Helper()
+
+	return loadQueryCacheWithTTL(t, dbBasePath, mirror, 0)
+}
+
+func loadQueryCacheWithTTL(t *testing.T, dbBasePath string, mirror local.Mirror, ttl time.Duration) *local.QueryCache {
+	t.Helper()
+
+	cache, err := local.LoadQueryCache(dbBasePath, mirror, ttl)
+	if err != nil {
+		t.Fatalf("unexpected error loading cache: %v", err)
+	}
+
+	return cache
+}
+
+func expectCachedVulns(t *testing.T, cache *local.QueryCache, query *osv.Query, expected []osv.MinimalVulnerability) {
+	t.Helper()
+
+	vulns, ok := cache.Get(query)
+
+	if expected == nil {
+		if ok {
+			t.Errorf("expected no cached results, but got %v", vulns)
+		}
+
+		return
+	}
+
+	if !ok {
+		t.Fatalf("expected cached results, but got none")
+	}
+
+	if !reflect.DeepEqual(vulns, expected) {
+		t.Errorf("expected %v but got %v", expected, vulns)
+	}
+}
+
+func TestQueryCache_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	var snapshot atomic.Value
+	snapshot.Store("1")
+	mirror := createSnapshotServer(t, &snapshot)
+	dbBasePath := t.TempDir()
+
+	query := osv.MakePkgRequest(lockfile.PackageDetails{Name: "lodash", Version: "4.17.20", Ecosystem: lockfile.NpmEcosystem})
+	vulns := []osv.MinimalVulnerability{{ID: "GHSA-35jh-r3h4-6jhm"}, {ID: "GHSA-29mw-wpgm-hmr9"}}
+
+	cache := loadQueryCache(t, dbBasePath, mirror)
+	expectCachedVulns(t, cache, query, nil)
+	cache.Set(query, vulns)
+	if err := cache.Write(); err != nil {
+		t.Fatalf("unexpected error writing cache: %v", err)
+	}
+
+	expectCachedVulns(t, loadQueryCache(t, dbBasePath, mirror), query, vulns)
+
+	// packages without any vulnerabilities should also be cached
+	safe := osv.MakePkgRequest(lockfile.PackageDetails{Name: "lodash", Version: "4.17.21", Ecosystem: lockfile.NpmEcosystem})
+	cache.Set(safe, nil)
+	expectCachedVulns(t, cache, safe, []osv.MinimalVulnerability{})
+}
+
+func TestQueryCache_SnapshotChanged(t *testing.T) {
+	t.Parallel()
+
+	var snapshot atomic.Value
+	snapshot.Store("1")
+	mirror := createSnapshotServer(t, &snapshot)
+	dbBasePath := t.TempDir()
+
+	query := osv.MakePkgRequest(lockfile.PackageDetails{Name: "lodash", Version: "4.17.20", Ecosystem: lockfile.NpmEcosystem})
+
+	cache := loadQueryCache(t, dbBasePath, mirror)
+	cache.Set(query, []osv.MinimalVulnerability{{ID: "GHSA-35jh-r3h4-6jhm"}})
+	if err := cache.Write(); err != nil {
+		t.Fatalf("unexpected error writing cache: %v", err)
+	}
+
+	snapshot.Store("2")
+
+	expectCachedVulns(t, loadQueryCache(t, dbBasePath, mirror), query, nil)
+}
+
+func TestQueryCache_UnknownSnapshot(t *testing.T) {
+	t.Parallel()
+
+	var snapshot atomic.Value
+	snapshot.Store("")
+	mirror := createSnapshotServer(t, &snapshot)
+
+	cache := loadQueryCache(t, t.TempDir(), mirror)
+
+	query := osv.MakePkgRequest(lockfile.PackageDetails{Name: "lodash", Version: "4.17.20", Ecosystem: lockfile.NpmEcosystem})
+	cache.Set(query, []osv.MinimalVulnerability{{ID: "GHSA-35jh-r3h4-6jhm"}})
+
+	expectCachedVulns(t, cache, query, nil)
+}
+
+func TestQueryCache_Uncacheable(t *testing.T) {
+	t.Parallel()
+
+	var snapshot atomic.Value
+	snapshot.Store("1")
+	mirror := createSnapshotServer(t, &snapshot)
+
+	cache := loadQueryCache(t, t.TempDir(), mirror)
+
+	for _, query := range []*osv.Query{
+		osv.MakeCommitRequest("9a6bd55c9d0722cb101fe85a3b22d89e4ff4fe52"),
+		osv.MakePURLRequest("pkg:npm/lodash@4.17.20"),
+	} {
+		cache.Set(query, []osv.MinimalVulnerability{{ID: "GHSA-35jh-r3h4-6jhm"}})
+		expectCachedVulns(t, cache, query, nil)
+	}
+}
+
+func TestQueryCache_TTL(t *testing.T) {
+	t.Parallel()
+
+	var snapshot atomic.Value
+	snapshot.Store("1")
+	mirror := createSnapshotServer(t, &snapshot)
+	dbBasePath := t.TempDir()
+
+	query := osv.MakePkgRequest(lockfile.PackageDetails{Name: "lodash", Version: "4.17.20", Ecosystem: lockfile.NpmEcosystem})
+	modified := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	vulns := []osv.MinimalVulnerability{{ID: "GHSA-35jh-r3h4-6jhm", Modified: modified}}
+
+	cache := loadQueryCacheWithTTL(t, dbBasePath, mirror, time.Hour)
+	cache.Set(query, vulns)
+	if err := cache.Write(); err != nil {
+		t.Fatalf("unexpected error writing cache: %v", err)
+	}
+
+	snapshot.Store("2")
+
+	// fresh results are used even though the snapshot has changed, along with when they were modified
+	expectCachedVulns(t, loadQueryCacheWithTTL(t, dbBasePath, mirror, time.Hour), query, vulns)
+
+	// but not once they are older than the TTL, or if there is no TTL
+	expectCachedVulns(t, loadQueryCacheWithTTL(t, dbBasePath, mirror, time.Nanosecond), query, nil)
+	expectCachedVulns(t, loadQueryCache(t, dbBasePath, mirror), query, nil)
+}
+
+func TestQueryCache_TTL_UnknownSnapshot(t *testing.T) {
+	t.Parallel()
+
+	var snapshot atomic.Value
+	snapshot.Store("")
+	mirror := createSnapshotServer(t, &snapshot)
+
+	cache := loadQueryCacheWithTTL(t, t.TempDir(), mirror, time.Hour)
+
+	query := osv.MakePkgRequest(lockfile.PackageDetails{Name: "lodash", Version: "4.17.20", Ecosystem: lockfile.NpmEcosystem})
+	cache.Set(query, []osv.MinimalVulnerability{{ID: "GHSA-35jh-r3h4-6jhm"}})
+
+	expectCachedVulns(t, cache, query, []osv.MinimalVulnerability{{ID: "GHSA-35jh-r3h4-6jhm"}})
+}
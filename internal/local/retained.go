@@ -0,0 +1,67 @@
+package local
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type retainedDB struct {
+	db       *ZipDB
+	loadedAt time.Time
+}
+
+// retained holds the databases loaded by previous requests when retaining is enabled,
+// keyed by where each database is stored and downloaded from
+var retained struct {
+	sync.Mutex
+	maxAge time.Duration
+	dbs    map[string]retainedDB
+}
+
+// RetainDatabases keeps the databases loaded by MakeRequest in memory, so that long-running
+// processes serving many scans do not read every archive again for each request.
+//
+// A retained database is loaded again once it is older than maxAge, to pick up any changes
+// to the archive; a maxAge of zero or less stops retaining databases and drops any already held.
+func RetainDatabases(maxAge time.Duration) {
+	retained.Lock()
+	defer retained.Unlock()
+
+	retained.maxAge = maxAge
+	retained.dbs = nil
+}
+
+// loadRetainedDB returns the retained version of the database if there is a fresh enough one,
+// otherwise loading it and retaining it if enabled, along with whether it was already loaded
+func loadRetainedDB(ctx context.Context, db *ZipDB, mirror Mirror) (*ZipDB, bool, error) {
+	retained.Lock()
+	defer retained.Unlock()
+
+	if retained.maxAge <= 0 {
+		db, err := openZipDB(ctx, db)
+
+		return db, false, err
+	}
+
+	key := db.StoredAt + "\x00" + db.ArchiveURL + "\x00" + mirror.Header + "\x00" + mirror.SignatureKey
+	if db.Offline {
+		key += "\x00offline"
+	}
+
+	if r, ok := retained.dbs[key]; ok && time.Since(r.loadedAt) < retained.maxAge {
+		return r.db, true, nil
+	}
+
+	db, err := openZipDB(ctx, db)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if retained.dbs == nil {
+		retained.dbs = make(map[string]retainedDB)
+	}
+	retained.dbs[key] = retainedDB{db: db, loadedAt: time.Now()}
+
+	return db, false, nil
+}
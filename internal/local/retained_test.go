@@ -0,0 +1,76 @@
+package local_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/osv-scanner/internal/local"
+	"github.com/google/osv-scanner/internal/testutility"
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+//nolint:paralleltest // retaining databases is process-wide
+func TestMakeRequest_RetainDatabases(t *testing.T) {
+	var requests atomic.Int32
+
+	ts := createZipServer(t, func(w http.ResponseWriter, r *http.Request) {
+		// only count requests for the archive, which this db host does not support updating incrementally
+		if strings.HasSuffix(r.URL.Path, "/modified_id.csv") {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		requests.Add(1)
+		_, _ = writeOSVsZip(t, w, map[string]models.Vulnerability{
+			"GHSA-1.json": {
+				ID: "GHSA-1",
+				Affected: []models.Affected{
+					{Package: models.Package{Ecosystem: "npm", Name: "lodash"}, Versions: []string{"4.17.20"}},
+				},
+			},
+		})
+	})
+
+	local.RetainDatabases(time.Hour)
+	t.Cleanup(func() { local.RetainDatabases(0) })
+
+	testDir := testutility.CreateTestDir(t)
+	r := reporter.NewJSONReporter(io.Discard, io.Discard, reporter.ErrorLevel)
+	query := osv.BatchedQuery{Queries: []*osv.Query{
+		osv.MakePkgRequest(lockfile.PackageDetails{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"}),
+	}}
+
+	for i := 0; i < 3; i++ {
+		resp, err := local.MakeRequest(context.Background(), r, query, false, testDir, "", local.Mirror{URL: ts.URL})
+		if err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+
+		if len(resp.Results) != 1 || len(resp.Results[0].Vulns) != 1 || resp.Results[0].Vulns[0].ID != "GHSA-1" {
+			t.Errorf("request %d did not find GHSA-1: %+v", i, resp.Results)
+		}
+	}
+
+	if got := requests.Load(); got != 1 {
+		t.Errorf("expected the database to be downloaded once, but it was downloaded %d times", got)
+	}
+
+	local.RetainDatabases(0)
+
+	if _, err := local.MakeRequest(context.Background(), r, query, false, testDir, "", local.Mirror{URL: ts.URL}); err != nil {
+		t.Fatalf("unexpected error \"%v\"", err)
+	}
+
+	if got := requests.Load(); got != 2 {
+		t.Errorf("expected the database to be checked again once no longer retained, but there were %d requests", got)
+	}
+}
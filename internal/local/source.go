@@ -0,0 +1,80 @@
+package local
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+// advisorySourcesDirName is the directory within the databases directory that
+// the databases of advisory sources are stored in, with a directory for each source
+const advisorySourcesDirName = "sources"
+
+// AdvisorySourceKind is the layout that an advisory source serves its records in
+type AdvisorySourceKind string
+
+const (
+	// AdvisorySourceOSV serves a zip archive of the records of each ecosystem
+	// at <URL>/<ecosystem>/all.zip, in the same layout as the OSV storage bucket
+	AdvisorySourceOSV AdvisorySourceKind = "osv"
+	// AdvisorySourceGHSA serves a single zip archive of the records of every ecosystem at its URL,
+	// such as a mirror of the GitHub Advisory Database repository whose advisories are in the OSV schema
+	AdvisorySourceGHSA AdvisorySourceKind = "ghsa"
+)
+
+// AdvisorySource is somewhere other than the mirror that advisories are downloaded from into
+// local databases, with packages being checked against the records of every source
+type AdvisorySource struct {
+	Kind AdvisorySourceKind
+	URL  string
+}
+
+func (s AdvisorySource) String() string {
+	return string(s.Kind) + ":" + s.URL
+}
+
+// ParseAdvisorySource parses an advisory source in the form of [<kind>:]<url>,
+// with the kind being either "osv" or "ghsa" and defaulting to "osv"
+func ParseAdvisorySource(source string) (AdvisorySource, error) {
+	s := AdvisorySource{Kind: AdvisorySourceOSV, URL: source}
+
+	if kind, rest, ok := strings.Cut(source, ":"); ok {
+		switch AdvisorySourceKind(kind) {
+		case AdvisorySourceOSV, AdvisorySourceGHSA:
+			s = AdvisorySource{Kind: AdvisorySourceKind(kind), URL: rest}
+		}
+	}
+
+	u, err := url.Parse(s.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return AdvisorySource{}, fmt.Errorf("invalid advisory source %q, expected [osv:|ghsa:]<url>", source)
+	}
+
+	return s, nil
+}
+
+// dir returns the directory that the databases of the source are stored in
+func (s AdvisorySource) dir(dbBasePath string) string {
+	return path.Join(dbBasePath, advisorySourcesDirName, string(s.Kind)+"-"+sha256Hex([]byte(s.URL))[:16])
+}
+
+// newDB returns the database of the source that has the records of the ecosystem, without loading it,
+// which for sources of the ghsa kind is the same database for every ecosystem
+func (s AdvisorySource) newDB(dbBasePath string, ecosystem lockfile.Ecosystem, offline bool) *ZipDB {
+	if s.Kind == AdvisorySourceGHSA {
+		return newZipDB(s.dir(dbBasePath), "all", s.URL, nil, offline)
+	}
+
+	return newZipDB(s.dir(dbBasePath), string(ecosystem), Mirror{URL: s.URL}.archiveURL(ecosystem), nil, offline)
+}
+
+// newSourceDB returns the database of the advisory source that has the records of the ecosystem,
+// without loading it, which is downloaded with the same header and signature key as the mirror
+func (m Mirror) newSourceDB(dbBasePath string, source AdvisorySource, ecosystem lockfile.Ecosystem, offline bool) (*ZipDB, error) {
+	db := source.newDB(dbBasePath, ecosystem, offline)
+
+	return db, m.configure(db)
+}
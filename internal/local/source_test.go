@@ -0,0 +1,151 @@
+package local_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/osv-scanner/internal/local"
+	"github.com/google/osv-scanner/internal/testutility"
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+func TestParseAdvisorySource(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		source  string
+		want    local.AdvisorySource
+		wantErr bool
+	}{
+		{
+			source: "https://osv.example.com",
+			want:   local.AdvisorySource{Kind: local.AdvisorySourceOSV, URL: "https://osv.example.com"},
+		},
+		{
+			source: "osv:https://osv.example.com",
+			want:   local.AdvisorySource{Kind: local.AdvisorySourceOSV, URL: "https://osv.example.com"},
+		},
+		{
+			source: "ghsa:https://ghsa.example.com/advisory-database/main.zip",
+			want:   local.AdvisorySource{Kind: local.AdvisorySourceGHSA, URL: "https://ghsa.example.com/advisory-database/main.zip"},
+		},
+		{source: "ghsa:", wantErr: true},
+		{source: "nvd:https://nvd.example.com", wantErr: true},
+		{source: "ftp://osv.example.com", wantErr: true},
+		{source: "osv.example.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.source, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := local.ParseAdvisorySource(tt.source)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected an error to be %t, got \"%v\"", tt.wantErr, err)
+			}
+
+			if got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func affectingLodash() []models.Affected {
+	return []models.Affected{
+		{Package: models.Package{Ecosystem: "npm", Name: "lodash"}, Versions: []string{"4.17.20"}},
+	}
+}
+
+func TestMakeRequest_AdvisorySources(t *testing.T) {
+	t.Parallel()
+
+	var online atomic.Bool
+	online.Store(true)
+
+	serve := func(archives map[string]map[string]models.Vulnerability) string {
+		ts := createZipServer(t, func(w http.ResponseWriter, r *http.Request) {
+			if !online.Load() {
+				t.Errorf("a server request was made when running offline")
+			}
+
+			osvs, ok := archives[r.URL.Path]
+			if !ok {
+				http.NotFound(w, r)
+
+				return
+			}
+
+			_, _ = writeOSVsZip(t, w, osvs)
+		})
+
+		return ts.URL
+	}
+
+	mirror := serve(map[string]map[string]models.Vulnerability{
+		"/npm/all.zip": {"GHSA-1.json": {ID: "GHSA-1", Affected: affectingLodash()}},
+	})
+	feed := serve(map[string]map[string]models.Vulnerability{
+		"/npm/all.zip": {
+			"INTERNAL-1.json": {ID: "INTERNAL-1", Affected: affectingLodash()},
+			// the same advisory as is in the mirror
+			"INTERNAL-2.json": {ID: "INTERNAL-2", Aliases: []string{"GHSA-1"}, Affected: affectingLodash()},
+		},
+	})
+	ghsa := serve(map[string]map[string]models.Vulnerability{
+		"/main.zip": {
+			"advisory-database-main/advisories/github-reviewed/2024/01/GHSA-2/GHSA-2.json": {ID: "GHSA-2", Affected: affectingLodash()},
+			"advisory-database-main/advisories/github-reviewed/2024/01/GHSA-3/GHSA-3.json": {ID: "GHSA-3", Affected: []models.Affected{
+				{Package: models.Package{Ecosystem: "PyPI", Name: "lodash"}, Versions: []string{"4.17.20"}},
+			}},
+		},
+	})
+
+	var sources []local.AdvisorySource
+	for _, s := range []string{feed, "ghsa:" + ghsa + "/main.zip"} {
+		source, err := local.ParseAdvisorySource(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sources = append(sources, source)
+	}
+
+	testDir := testutility.CreateTestDir(t)
+	r := reporter.NewJSONReporter(io.Discard, io.Discard, reporter.ErrorLevel)
+	query := osv.BatchedQuery{Queries: []*osv.Query{
+		osv.MakePkgRequest(lockfile.PackageDetails{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"}),
+	}}
+
+	for _, offline := range []bool{false, true} {
+		online.Store(!offline)
+
+		resp, err := local.MakeRequest(context.Background(), r, query, offline, testDir, "", local.Mirror{URL: mirror, Sources: sources})
+		if err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+
+		if len(resp.Results) != 1 {
+			t.Fatalf("expected 1 result, got %d", len(resp.Results))
+		}
+
+		ids := make([]string, 0, len(resp.Results[0].Vulns))
+		for _, vulnerability := range resp.Results[0].Vulns {
+			ids = append(ids, vulnerability.ID)
+		}
+		sort.Strings(ids)
+
+		if want := []string{"GHSA-1", "GHSA-2", "INTERNAL-1"}; !reflect.DeepEqual(ids, want) {
+			t.Errorf("expected lodash to be affected by %v when offline is %t, got %v", want, offline, ids)
+		}
+	}
+}
@@ -0,0 +1,444 @@
+package local
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/osv-scanner/pkg/models"
+	"golang.org/x/sync/errgroup"
+)
+
+const recordStoreFileName = "records.gob"
+
+// maxIncrementalRecords is the most records that are fetched one at a time to update a database,
+// beyond which downloading the whole database again is faster
+const maxIncrementalRecords = 1000
+
+// concurrentRecordRequests is how many records are fetched at once when updating a database
+const concurrentRecordRequests = 10
+
+var errNotModified = errors.New("not modified")
+
+// recordStore is the indexed form that databases are stored on disk in, keyed by the ID of each record
+// along with when it was last modified, so that a database can be updated by fetching only the records
+// that have been modified since, per the modified_id.csv that the db host has alongside each archive
+type recordStore struct {
+	// ETag is of the modified_id.csv that the records were last updated from, if known
+	ETag string
+	// UpdatedAt is when the records were last checked for updates
+	UpdatedAt time.Time
+	Records   map[string]storedRecord
+}
+
+type storedRecord struct {
+	Modified time.Time
+	// JSON is the record in the OSV schema
+	JSON []byte
+}
+
+// recordSummary is the part of a record that is needed to store it
+type recordSummary struct {
+	ID       string    `json:"id"`
+	Modified time.Time `json:"modified"`
+}
+
+func readRecordStore(storedAt string) (*recordStore, error) {
+	data, err := os.ReadFile(storedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := verifyChecksum(storedAt, data); err != nil {
+		return nil, err
+	}
+
+	var s recordStore
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&s); err != nil {
+		return nil, fmt.Errorf("could not read %s: %w", storedAt, err)
+	}
+
+	// the store is touched when it is checked for updates and there are none
+	if info, err := os.Stat(storedAt); err == nil && info.ModTime().After(s.UpdatedAt) {
+		s.UpdatedAt = info.ModTime()
+	}
+
+	if len(s.Records) == 0 {
+		return nil, fmt.Errorf("%s does not contain any OSV records", storedAt)
+	}
+
+	return &s, nil
+}
+
+func (s *recordStore) write(storedAt string) error {
+	if err := os.MkdirAll(path.Dir(storedAt), 0750); err != nil {
+		return err
+	}
+
+	// write to a temporary file first, so that concurrent scans never read a partial database
+	f, err := os.CreateTemp(path.Dir(storedAt), recordStoreFileName+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	h := sha256.New()
+	if err := gob.NewEncoder(io.MultiWriter(f, h)).Encode(s); err != nil {
+		f.Close()
+
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	//nolint:gosec // being world readable is fine, like the archives
+	if err := os.Chmod(f.Name(), 0644); err != nil {
+		return err
+	}
+
+	if err := os.Rename(f.Name(), storedAt); err != nil {
+		return err
+	}
+
+	return writeChecksum(storedAt, hex.EncodeToString(h.Sum(nil)))
+}
+
+// exportedAt returns when the newest record in the store was modified
+func (s *recordStore) exportedAt() time.Time {
+	var newest time.Time
+	for _, record := range s.Records {
+		if record.Modified.After(newest) {
+			newest = record.Modified
+		}
+	}
+
+	return newest
+}
+
+// each passes every record of the store to add, ordered by their ID
+func (s *recordStore) each(add func(models.Vulnerability, []byte) error) error {
+	ids := make([]string, 0, len(s.Records))
+	for id := range s.Records {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	for _, id := range ids {
+		var vulnerability models.Vulnerability
+		if err := json.Unmarshal(s.Records[id].JSON, &vulnerability); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "%s is not a valid JSON record: %v\n", id, err)
+
+			continue
+		}
+
+		if err := add(vulnerability, s.Records[id].JSON); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// newRecordStore indexes the records of the zip archive
+func newRecordStore(archive []byte) (*recordStore, error) {
+	zipReader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, fmt.Errorf("could not read OSV database archive: %w", err)
+	}
+
+	s := &recordStore{Records: make(map[string]storedRecord)}
+
+	for _, zipFile := range zipReader.File {
+		if !strings.HasSuffix(zipFile.Name, ".json") {
+			continue
+		}
+
+		content, err := readZipFile(zipFile)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Could not read %s: %v\n", zipFile.Name, err)
+
+			continue
+		}
+
+		var summary recordSummary
+		if err := json.Unmarshal(content, &summary); err != nil || summary.ID == "" {
+			_, _ = fmt.Fprintf(os.Stderr, "%s is not a valid JSON file: %v\n", zipFile.Name, err)
+
+			continue
+		}
+
+		s.Records[summary.ID] = storedRecord{Modified: summary.Modified, JSON: content}
+	}
+
+	return s, nil
+}
+
+func readZipFile(zipFile *zip.File) ([]byte, error) {
+	file, err := zipFile.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return io.ReadAll(file)
+}
+
+// modifiedIDsURL returns the url of the modified_id.csv of the database, which lists when each of
+// its records was last modified, or an empty string if the database is not from an all.zip archive
+func (db *ZipDB) modifiedIDsURL() string {
+	if !strings.HasSuffix(db.ArchiveURL, "/all.zip") {
+		return ""
+	}
+
+	return strings.TrimSuffix(db.ArchiveURL, "all.zip") + "modified_id.csv"
+}
+
+// recordURL returns the url that the record with the id is fetched from on its own
+func (db *ZipDB) recordURL(id string) string {
+	return strings.TrimSuffix(db.ArchiveURL, "all.zip") + url.PathEscape(id) + ".json"
+}
+
+// fetchModifiedIDs fetches when each record of the database was last modified, returning
+// errNotModified if the modified_id.csv still has the given etag
+func (db *ZipDB) fetchModifiedIDs(ctx context.Context, etag string) (map[string]time.Time, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, db.modifiedIDsURL(), nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	setRequestHeaders(req, db.Headers)
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, etag, errNotModified
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("db host returned %s", resp.Status)
+	}
+
+	reader := csv.NewReader(resp.Body)
+	reader.FieldsPerRecord = 2
+
+	modified := make(map[string]time.Time)
+	for {
+		line, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("could not read modified_id.csv: %w", err)
+		}
+
+		t, err := time.Parse(time.RFC3339Nano, line[0])
+		if err != nil {
+			return nil, "", fmt.Errorf("could not read modified_id.csv: %w", err)
+		}
+
+		modified[line[1]] = t
+	}
+
+	if len(modified) == 0 {
+		return nil, "", errors.New("modified_id.csv does not list any OSV records")
+	}
+
+	return modified, resp.Header.Get("ETag"), nil
+}
+
+// fetchRecord fetches the record with the id on its own
+func (db *ZipDB) fetchRecord(ctx context.Context, id string) (storedRecord, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, db.recordURL(id), nil)
+	if err != nil {
+		return storedRecord{}, err
+	}
+
+	setRequestHeaders(req, db.Headers)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return storedRecord{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return storedRecord{}, fmt.Errorf("db host returned %s for %s", resp.Status, id)
+	}
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return storedRecord{}, err
+	}
+
+	var summary recordSummary
+	if err := json.Unmarshal(content, &summary); err != nil {
+		return storedRecord{}, fmt.Errorf("%s is not a valid JSON record: %w", id, err)
+	}
+	if summary.ID != id {
+		return storedRecord{}, fmt.Errorf("the record fetched for %s is for %s", id, summary.ID)
+	}
+
+	return storedRecord{Modified: summary.Modified, JSON: content}, nil
+}
+
+// changedRecords returns the IDs of the records that have been modified since they were stored,
+// along with those that are not stored at all, ordered by their ID
+func changedRecords(s *recordStore, modified map[string]time.Time) []string {
+	var changed []string
+	for id, m := range modified {
+		// the modified times are compared to the second, as not every
+		// source of them is guaranteed to be any more precise than that
+		if record, ok := s.Records[id]; !ok || m.Truncate(time.Second).After(record.Modified.Truncate(time.Second)) {
+			changed = append(changed, id)
+		}
+	}
+	slices.Sort(changed)
+
+	return changed
+}
+
+// updateRecordStore fetches the records that have changed and stores them, removing any records
+// that no longer exist, which are those that are not listed despite being older than what is
+func (db *ZipDB) updateRecordStore(ctx context.Context, s *recordStore, modified map[string]time.Time) error {
+	changed := changedRecords(s, modified)
+	records := make([]storedRecord, len(changed))
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(concurrentRecordRequests)
+	for i, id := range changed {
+		i, id := i, id
+		g.Go(func() error {
+			record, err := db.fetchRecord(ctx, id)
+			records[i] = record
+
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	for i, id := range changed {
+		s.Records[id] = records[i]
+	}
+
+	var newest time.Time
+	for _, m := range modified {
+		if m.After(newest) {
+			newest = m
+		}
+	}
+
+	for id, record := range s.Records {
+		if _, ok := modified[id]; !ok && !record.Modified.Truncate(time.Second).After(newest.Truncate(time.Second)) {
+			delete(s.Records, id)
+		}
+	}
+
+	return nil
+}
+
+// loadRecordStore loads the database from its record store, which is updated incrementally with
+// only the records that have been modified since it was last updated unless the database is offline.
+//
+// If the store does not exist yet or is too out of date, the zip archive is downloaded
+// and indexed into the store, replacing any copy of the archive that was stored before.
+//
+// An error is returned if the database cannot be stored in this way, such as because the
+// db host does not have a modified_id.csv, in which case the zip archive should be used.
+func (db *ZipDB) loadRecordStore(ctx context.Context) (*recordStore, error) {
+	s, readErr := readRecordStore(db.IndexedAt)
+
+	if db.Offline {
+		if readErr != nil {
+			return nil, readErr
+		}
+
+		// prefer an archive that has been downloaded manually since the store was updated
+		if info, err := os.Stat(db.StoredAt); err == nil && info.ModTime().After(s.UpdatedAt) {
+			return nil, errors.New("the archive is newer than the record store")
+		}
+
+		return s, nil
+	}
+
+	if db.modifiedIDsURL() == "" {
+		return nil, errors.New("the database cannot be updated incrementally")
+	}
+
+	etag := ""
+	if readErr == nil {
+		etag = s.ETag
+	}
+
+	modified, etag, err := db.fetchModifiedIDs(ctx, etag)
+	if errors.Is(err, errNotModified) {
+		// the store is only touched rather than written again, so that its checksum
+		// does not change and the package index built from it can still be used
+		s.UpdatedAt = time.Now()
+		if err := os.Chtimes(db.IndexedAt, s.UpdatedAt, s.UpdatedAt); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Failed to save database to %s: %v\n", db.IndexedAt, err)
+		}
+
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if readErr != nil || len(changedRecords(s, modified)) > maxIncrementalRecords {
+		body, err := db.downloadZip(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		s, err = newRecordStore(body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the archive may not have every record that has been modified since it was exported,
+	// so any that are missing from it are still fetched, unless there are too many to
+	if len(changedRecords(s, modified)) <= maxIncrementalRecords {
+		if err := db.updateRecordStore(ctx, s, modified); err != nil {
+			return nil, err
+		}
+	}
+
+	s.ETag = etag
+	s.UpdatedAt = time.Now()
+
+	if err := s.write(db.IndexedAt); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Failed to save database to %s: %v\n", db.IndexedAt, err)
+	} else {
+		// the archive is no longer needed once its records are in the store
+		removeDatabaseFiles(db.StoredAt)
+	}
+
+	return s, nil
+}
@@ -0,0 +1,276 @@
+package local_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/osv-scanner/internal/local"
+	"github.com/google/osv-scanner/internal/testutility"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// fakeDBHost serves a database along with its modified_id.csv and each of its records,
+// recording the paths of the requests that it is sent
+type fakeDBHost struct {
+	mu       sync.Mutex
+	osvs     []models.Vulnerability
+	etag     string
+	requests []string
+}
+
+func (h *fakeDBHost) set(etag string, osvs ...models.Vulnerability) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.etag = etag
+	h.osvs = osvs
+	h.requests = nil
+}
+
+func (h *fakeDBHost) requested() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	requests := slices.Clone(h.requests)
+	slices.Sort(requests)
+
+	return requests
+}
+
+func (h *fakeDBHost) handle(t *testing.T) http.HandlerFunc {
+	t.Helper()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		h.requests = append(h.requests, r.URL.Path)
+
+		switch {
+		case r.URL.Path == "/npm/all.zip":
+			osvs := make(map[string]models.Vulnerability)
+			for _, osv := range h.osvs {
+				osvs[osv.ID+".json"] = osv
+			}
+			_, _ = writeOSVsZip(t, w, osvs)
+		case r.URL.Path == "/npm/modified_id.csv":
+			if h.etag != "" && r.Header.Get("If-None-Match") == h.etag {
+				w.WriteHeader(http.StatusNotModified)
+
+				return
+			}
+
+			w.Header().Set("ETag", h.etag)
+			for _, osv := range h.osvs {
+				fmt.Fprintf(w, "%s,%s\n", osv.Modified.Format(time.RFC3339Nano), osv.ID)
+			}
+		default:
+			for _, osv := range h.osvs {
+				if r.URL.Path == "/npm/"+osv.ID+".json" {
+					_ = json.NewEncoder(w).Encode(osv)
+
+					return
+				}
+			}
+
+			http.NotFound(w, r)
+		}
+	}
+}
+
+func TestNewZippedDB_Online_Incremental(t *testing.T) {
+	t.Parallel()
+
+	testDir := testutility.CreateTestDir(t)
+	exported := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	host := &fakeDBHost{}
+	ts := createZipServer(t, host.handle(t))
+
+	host.set(
+		`"1"`,
+		models.Vulnerability{ID: "GHSA-1", Modified: exported},
+		models.Vulnerability{ID: "GHSA-2", Modified: exported},
+	)
+
+	db, err := local.NewZippedDB(context.Background(), testDir, "npm", ts.URL+"/npm/all.zip", nil, false)
+	if err != nil {
+		t.Fatalf("unexpected error \"%v\"", err)
+	}
+
+	expectDBToHaveOSVs(t, db, []models.Vulnerability{
+		{ID: "GHSA-1", Modified: exported},
+		{ID: "GHSA-2", Modified: exported},
+	})
+
+	if want := []string{"/npm/all.zip", "/npm/modified_id.csv"}; !slices.Equal(host.requested(), want) {
+		t.Errorf("expected the database to be downloaded in full, but requested %v", host.requested())
+	}
+
+	if _, err := os.Stat(determineStoredAtPath(testDir, "npm")); err == nil {
+		t.Errorf("expected the archive to not be kept once its records were stored")
+	}
+
+	// GHSA-1 is removed, GHSA-2 is modified, and GHSA-3 is added
+	host.set(
+		`"2"`,
+		models.Vulnerability{ID: "GHSA-2", Modified: exported.Add(time.Hour), Summary: "modified"},
+		models.Vulnerability{ID: "GHSA-3", Modified: exported.Add(time.Hour)},
+	)
+
+	db, err = local.NewZippedDB(context.Background(), testDir, "npm", ts.URL+"/npm/all.zip", nil, false)
+	if err != nil {
+		t.Fatalf("unexpected error \"%v\"", err)
+	}
+
+	expectDBToHaveOSVs(t, db, []models.Vulnerability{
+		{ID: "GHSA-2", Modified: exported.Add(time.Hour), Summary: "modified"},
+		{ID: "GHSA-3", Modified: exported.Add(time.Hour)},
+	})
+
+	if want := []string{"/npm/GHSA-2.json", "/npm/GHSA-3.json", "/npm/modified_id.csv"}; !slices.Equal(host.requested(), want) {
+		t.Errorf("expected only the modified records to be fetched, but requested %v", host.requested())
+	}
+
+	if db.StoredAt != path.Join(testDir, "npm", "records.gob") {
+		t.Errorf("expected the database to be stored in its record store, not %s", db.StoredAt)
+	}
+}
+
+func TestNewZippedDB_Online_IncrementalNotModified(t *testing.T) {
+	t.Parallel()
+
+	testDir := testutility.CreateTestDir(t)
+	exported := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	host := &fakeDBHost{}
+	ts := createZipServer(t, host.handle(t))
+
+	host.set(`"1"`, models.Vulnerability{ID: "GHSA-1", Modified: exported})
+
+	if _, err := local.NewZippedDB(context.Background(), testDir, "npm", ts.URL+"/npm/all.zip", nil, false); err != nil {
+		t.Fatalf("unexpected error \"%v\"", err)
+	}
+
+	host.set(`"1"`, models.Vulnerability{ID: "GHSA-1", Modified: exported})
+
+	db, err := local.NewZippedDB(context.Background(), testDir, "npm", ts.URL+"/npm/all.zip", nil, false)
+	if err != nil {
+		t.Fatalf("unexpected error \"%v\"", err)
+	}
+
+	expectDBToHaveOSVs(t, db, []models.Vulnerability{{ID: "GHSA-1", Modified: exported}})
+
+	if want := []string{"/npm/modified_id.csv"}; !slices.Equal(host.requested(), want) {
+		t.Errorf("expected nothing to be fetched for an unmodified database, but requested %v", host.requested())
+	}
+}
+
+func TestNewZippedDB_Offline_WithRecordStore(t *testing.T) {
+	t.Parallel()
+
+	testDir := testutility.CreateTestDir(t)
+	exported := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	host := &fakeDBHost{}
+	ts := createZipServer(t, host.handle(t))
+
+	host.set(`"1"`, models.Vulnerability{ID: "GHSA-1", Modified: exported})
+
+	if _, err := local.NewZippedDB(context.Background(), testDir, "npm", ts.URL+"/npm/all.zip", nil, false); err != nil {
+		t.Fatalf("unexpected error \"%v\"", err)
+	}
+
+	host.set(`"1"`)
+
+	db, err := local.NewZippedDB(context.Background(), testDir, "npm", ts.URL+"/npm/all.zip", nil, true)
+	if err != nil {
+		t.Fatalf("unexpected error \"%v\"", err)
+	}
+
+	expectDBToHaveOSVs(t, db, []models.Vulnerability{{ID: "GHSA-1", Modified: exported}})
+
+	if len(host.requested()) != 0 {
+		t.Errorf("expected no requests to be made while offline, but requested %v", host.requested())
+	}
+}
+
+func TestNewZippedDB_Online_WithoutModifiedIDs(t *testing.T) {
+	t.Parallel()
+
+	testDir := testutility.CreateTestDir(t)
+
+	ts := createZipServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/modified_id.csv") {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		_, _ = writeOSVsZip(t, w, map[string]models.Vulnerability{
+			"GHSA-1.json": {ID: "GHSA-1"},
+		})
+	})
+
+	db, err := local.NewZippedDB(context.Background(), testDir, "npm", ts.URL+"/npm/all.zip", nil, false)
+	if err != nil {
+		t.Fatalf("unexpected error \"%v\"", err)
+	}
+
+	expectDBToHaveOSVs(t, db, []models.Vulnerability{{ID: "GHSA-1"}})
+
+	if db.StoredAt != determineStoredAtPath(testDir, "npm") {
+		t.Errorf("expected the archive to be stored when it cannot be updated incrementally, not %s", db.StoredAt)
+	}
+}
+
+func TestCachedDatabaseDetails_RecordStore(t *testing.T) {
+	t.Parallel()
+
+	testDir := testutility.CreateTestDir(t)
+	newest := time.Date(2024, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	host := &fakeDBHost{}
+	ts := createZipServer(t, host.handle(t))
+
+	host.set(
+		`"1"`,
+		models.Vulnerability{ID: "GHSA-1", Modified: newest.Add(-time.Hour)},
+		models.Vulnerability{ID: "GHSA-2", Modified: newest},
+	)
+
+	before := time.Now()
+	if _, err := local.NewZippedDB(context.Background(), testDir, "npm", ts.URL+"/npm/all.zip", nil, false); err != nil {
+		t.Fatalf("unexpected error \"%v\"", err)
+	}
+
+	details, err := local.CachedDatabaseDetails(testDir, true, local.Mirror{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(details) != 1 {
+		t.Fatalf("expected 1 database, got %d", len(details))
+	}
+
+	d := details[0]
+
+	if storedAt := path.Join(testDir, "npm", "records.gob"); d.StoredAt != storedAt {
+		t.Errorf("expected the database to be stored at %s, not %s", storedAt, d.StoredAt)
+	}
+	if !d.ExportedAt.Equal(newest) {
+		t.Errorf("expected data to have been exported at %v, got %v", newest, d.ExportedAt)
+	}
+	if d.DownloadedAt.Before(before) {
+		t.Errorf("expected the database to have been downloaded after %v, got %v", before, d.DownloadedAt)
+	}
+}
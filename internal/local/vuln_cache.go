@@ -0,0 +1,142 @@
+package local
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/google/osv-scanner/internal/cachedir"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+)
+
+const vulnCacheFileName = "vulns.gob"
+
+// vulnCacheMaxUnused is how long a vulnerability can go without being used before
+// it is dropped from the cache, so that the cache does not grow forever
+const vulnCacheMaxUnused = 30 * 24 * time.Hour
+
+// VulnCache stores the details of the vulnerabilities that were fetched from OSV, keyed
+// by their ID and when they were last modified, so that repeated scans only have to fetch
+// the vulnerabilities that have been modified since they were cached.
+type VulnCache struct {
+	entries  map[string]vulnCacheEntry
+	storedAt string
+	ttl      time.Duration
+}
+
+type vulnCacheEntry struct {
+	Modified time.Time
+	StoredAt time.Time
+	UsedAt   time.Time
+	// JSON is the vulnerability in the OSV schema
+	JSON []byte
+}
+
+// LoadVulnCache loads the vulnerability cache from the queries subdirectory of cacheDir,
+// or of the default cache directory if that is empty.
+//
+// Vulnerabilities whose last modified time is not known are used if they were cached less
+// than ttl ago, which they never are if ttl is zero.
+//
+// A cache that does not exist or cannot be read is treated as being empty.
+func LoadVulnCache(cacheDir string, ttl time.Duration) (*VulnCache, error) {
+	queriesPath, err := cachedir.Subdirectory(cacheDir, cachedir.Queries)
+	if err != nil {
+		return nil, fmt.Errorf("could not create %s: %w", queriesPath, err)
+	}
+
+	cache := &VulnCache{
+		entries:  make(map[string]vulnCacheEntry),
+		storedAt: path.Join(queriesPath, vulnCacheFileName),
+		ttl:      ttl,
+	}
+
+	if f, err := os.Open(cache.storedAt); err == nil {
+		defer f.Close()
+
+		// a corrupted cache is just as good as no cache, so ignore any errors
+		if err := gob.NewDecoder(f).Decode(&cache.entries); err != nil {
+			cache.entries = make(map[string]vulnCacheEntry)
+		}
+	}
+
+	return cache, nil
+}
+
+// Get returns the cached details of the vulnerability, if they are from when it was last modified
+func (c *VulnCache) Get(vuln osv.MinimalVulnerability) (models.Vulnerability, bool) {
+	entry, ok := c.entries[vuln.ID]
+	if !ok {
+		return models.Vulnerability{}, false
+	}
+
+	if vuln.Modified.IsZero() {
+		if c.ttl <= 0 || time.Since(entry.StoredAt) >= c.ttl {
+			return models.Vulnerability{}, false
+		}
+	} else if !entry.Modified.Truncate(time.Second).Equal(vuln.Modified.Truncate(time.Second)) {
+		return models.Vulnerability{}, false
+	}
+
+	var details models.Vulnerability
+	if err := json.Unmarshal(entry.JSON, &details); err != nil {
+		return models.Vulnerability{}, false
+	}
+
+	entry.UsedAt = time.Now()
+	c.entries[vuln.ID] = entry
+
+	return details, true
+}
+
+// Set stores the details of the vulnerability, doing nothing if they cannot be encoded
+func (c *VulnCache) Set(vuln models.Vulnerability) {
+	if vuln.ID == "" {
+		return
+	}
+
+	b, err := json.Marshal(vuln)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	c.entries[vuln.ID] = vulnCacheEntry{
+		Modified: vuln.Modified,
+		StoredAt: now,
+		UsedAt:   now,
+		JSON:     b,
+	}
+}
+
+// Write saves the cache to disk, dropping any vulnerabilities that have not been used in a while
+func (c *VulnCache) Write() error {
+	for id, entry := range c.entries {
+		if time.Since(entry.UsedAt) > vulnCacheMaxUnused {
+			delete(c.entries, id)
+		}
+	}
+
+	// write to a temporary file first, so that concurrent scans never read a partial cache
+	f, err := os.CreateTemp(path.Dir(c.storedAt), vulnCacheFileName+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if err := gob.NewEncoder(f).Encode(c.entries); err != nil {
+		f.Close()
+
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), c.storedAt)
+}
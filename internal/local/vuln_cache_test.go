@@ -0,0 +1,91 @@
+package local_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/internal/local"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+)
+
+func loadVulnCache(t *testing.T, dbBasePath string, ttl time.Duration) *local.VulnCache {
+	t.Helper()
+
+	cache, err := local.LoadVulnCache(dbBasePath, ttl)
+	if err != nil {
+		t.Fatalf("unexpected error loading cache: %v", err)
+	}
+
+	return cache
+}
+
+func TestVulnCache_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	dbBasePath := t.TempDir()
+	modified := time.Date(2024, 1, 1, 12, 30, 0, 0, time.UTC)
+	vuln := models.Vulnerability{
+		ID:       "GHSA-35jh-r3h4-6jhm",
+		Summary:  "Command Injection in lodash",
+		Aliases:  []string{"CVE-2021-23337"},
+		Modified: modified,
+	}
+
+	cache := loadVulnCache(t, dbBasePath, 0)
+	if _, ok := cache.Get(osv.MinimalVulnerability{ID: vuln.ID, Modified: modified}); ok {
+		t.Errorf("expected no cached details")
+	}
+
+	cache.Set(vuln)
+	if err := cache.Write(); err != nil {
+		t.Fatalf("unexpected error writing cache: %v", err)
+	}
+
+	cache = loadVulnCache(t, dbBasePath, 0)
+
+	got, ok := cache.Get(osv.MinimalVulnerability{ID: vuln.ID, Modified: modified})
+	if !ok {
+		t.Fatalf("expected cached details, but got none")
+	}
+	if diff := cmp.Diff(vuln, got); diff != "" {
+		t.Errorf("VulnCache.Get() mismatch (-want +got):\n%s", diff)
+	}
+
+	// the details are not used once the vulnerability has been modified since it was cached
+	if _, ok := cache.Get(osv.MinimalVulnerability{ID: vuln.ID, Modified: modified.Add(time.Hour)}); ok {
+		t.Errorf("expected no cached details for a vulnerability that has been modified")
+	}
+}
+
+func TestVulnCache_UnknownModified(t *testing.T) {
+	t.Parallel()
+
+	vuln := models.Vulnerability{ID: "GHSA-35jh-r3h4-6jhm", Modified: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	tests := []struct {
+		name string
+		ttl  time.Duration
+		want bool
+	}{
+		{name: "no ttl", ttl: 0, want: false},
+		{name: "fresh", ttl: time.Hour, want: true},
+		{name: "expired", ttl: time.Nanosecond, want: false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cache := loadVulnCache(t, t.TempDir(), tt.ttl)
+			cache.Set(vuln)
+			time.Sleep(time.Millisecond)
+
+			if _, ok := cache.Get(osv.MinimalVulnerability{ID: vuln.ID}); ok != tt.want {
+				t.Errorf("VulnCache.Get() = %v, want %v", ok, tt.want)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"archive/zip"
 	"bytes"
 	"context"
+	"crypto"
 	"encoding/base64"
 	"encoding/binary"
 	"encoding/json"
@@ -16,7 +17,6 @@ import (
 	"path"
 	"strings"
 
-	"github.com/google/osv-scanner/internal/utility/vulns"
 	"github.com/google/osv-scanner/pkg/lockfile"
 	"github.com/google/osv-scanner/pkg/models"
 	"github.com/google/osv-scanner/pkg/osv"
@@ -29,21 +29,32 @@ type ZipDB struct {
 	ArchiveURL string
 	// whether this database should make any network requests
 	Offline bool
+	// additional headers to send when making requests for the zip archive, such as for authentication
+	Headers http.Header
 	// the path to the zip archive on disk
 	StoredAt string
-	// the vulnerabilities that are loaded into this database
-	vulnerabilities []models.Vulnerability
+	// the path to the record store on disk, which the database is kept in instead
+	// of the zip archive when it can be updated incrementally
+	IndexedAt string
+	// the public key that the zip archive must be signed by, if any, with its signature
+	// being downloaded from next to the archive and stored next to it on disk
+	SignatureKey crypto.PublicKey
+	// the path to the index of the records in the database by the packages that they affect,
+	// which is built from the database whenever it changes and is what vulnerabilities are read from
+	PackageIndexAt string
 }
 
 var ErrOfflineDatabaseNotFound = errors.New("no offline version of the OSV database is available")
 
-func fetchRemoteArchiveCRC32CHash(url string) (uint32, error) {
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodHead, url, nil)
+func fetchRemoteArchiveCRC32CHash(ctx context.Context, url string, headers http.Header) (uint32, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
 
 	if err != nil {
 		return 0, err
 	}
 
+	setRequestHeaders(req, headers)
+
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return 0, err
@@ -55,7 +66,12 @@ func fetchRemoteArchiveCRC32CHash(url string) (uint32, error) {
 		return 0, fmt.Errorf("db host returned %s", resp.Status)
 	}
 
-	for _, value := range resp.Header.Values("x-goog-hash") {
+	return crc32CHashFromHeader(resp.Header)
+}
+
+// crc32CHashFromHeader returns the crc32c hash of the archive that the db host gave in its x-goog-hash header
+func crc32CHashFromHeader(header http.Header) (uint32, error) {
+	for _, value := range header.Values("x-goog-hash") {
 		if strings.HasPrefix(value, "crc32c=") {
 			value = strings.TrimPrefix(value, "crc32c=")
 			out, err := base64.StdEncoding.DecodeString(value)
@@ -75,19 +91,23 @@ func fetchLocalArchiveCRC32CHash(data []byte) uint32 {
 	return crc32.Checksum(data, crc32.MakeTable(crc32.Castagnoli))
 }
 
-func (db *ZipDB) fetchZip() ([]byte, error) {
-	cache, err := os.ReadFile(db.StoredAt)
+func (db *ZipDB) fetchZip(ctx context.Context) ([]byte, error) {
+	cache, err := db.readStoredZip()
 
 	if db.Offline {
-		if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
 			return nil, ErrOfflineDatabaseNotFound
 		}
 
+		if err != nil {
+			return nil, err
+		}
+
 		return cache, nil
 	}
 
 	if err == nil {
-		remoteHash, err := fetchRemoteArchiveCRC32CHash(db.ArchiveURL)
+		remoteHash, err := fetchRemoteArchiveCRC32CHash(ctx, db.ArchiveURL, db.Headers)
 
 		if err != nil {
 			return nil, err
@@ -98,16 +118,52 @@ func (db *ZipDB) fetchZip() ([]byte, error) {
 		}
 	}
 
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, db.ArchiveURL, nil)
+	body, err := db.downloadZip(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var signature []byte
+	if db.SignatureKey != nil {
+		signature, err = db.fetchSignature(ctx, body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = os.MkdirAll(path.Dir(db.StoredAt), 0750)
+
+	if err == nil {
+		//nolint:gosec // being world readable is fine
+		err = os.WriteFile(db.StoredAt, body, 0644)
+	}
+
+	if err == nil {
+		err = writeChecksum(db.StoredAt, sha256Hex(body))
+	}
+
+	if err == nil && signature != nil {
+		//nolint:gosec // being world readable is fine
+		err = os.WriteFile(db.StoredAt+signatureSuffix, signature, 0644)
+	}
 
 	if err != nil {
-		return nil, fmt.Errorf("could not retrieve OSV database archive: %w", err)
+		_, _ = fmt.Fprintf(os.Stderr, "Failed to save database to %s: %v\n", db.StoredAt, err)
 	}
 
-	if osv.RequestUserAgent != "" {
-		req.Header.Set("User-Agent", osv.RequestUserAgent)
+	return body, nil
+}
+
+// downloadZip downloads the zip archive of the database, checking that it is usable
+func (db *ZipDB) downloadZip(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, db.ArchiveURL, nil)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve OSV database archive: %w", err)
 	}
 
+	setRequestHeaders(req, db.Headers)
+
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("could not retrieve OSV database archive: %w", err)
@@ -127,36 +183,70 @@ func (db *ZipDB) fetchZip() ([]byte, error) {
 		return nil, fmt.Errorf("could not read OSV database archive from response: %w", err)
 	}
 
-	err = os.MkdirAll(path.Dir(db.StoredAt), 0750)
+	// make sure the archive was not corrupted while being downloaded, if the db host says what it should be
+	if remoteHash, err := crc32CHashFromHeader(resp.Header); err == nil && fetchLocalArchiveCRC32CHash(body) != remoteHash {
+		return nil, errors.New("downloaded OSV database archive does not match its checksum")
+	}
 
-	if err == nil {
-		//nolint:gosec // being world readable is fine
-		err = os.WriteFile(db.StoredAt, body, 0644)
+	// make sure the archive is usable before replacing the cached copy
+	if err := validateArchive(body); err != nil {
+		return nil, fmt.Errorf("downloaded OSV database archive is invalid: %w", err)
 	}
 
+	return body, nil
+}
+
+func setRequestHeaders(req *http.Request, headers http.Header) {
+	if osv.RequestUserAgent != "" {
+		req.Header.Set("User-Agent", osv.RequestUserAgent)
+	}
+
+	for key, values := range headers {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+}
+
+// validateArchive checks that the given data is a well-formed zip archive
+// that contains at least one OSV record
+func validateArchive(data []byte) error {
+	zipReader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
 	if err != nil {
-		_, _ = fmt.Fprintf(os.Stderr, "Failed to save database to %s: %v\n", db.StoredAt, err)
+		return err
 	}
 
-	return body, nil
+	for _, zipFile := range zipReader.File {
+		if strings.HasSuffix(zipFile.Name, ".json") {
+			return nil
+		}
+	}
+
+	return errors.New("archive does not contain any OSV records")
 }
 
-// Loads the given zip file into the database as an OSV.
-// It is assumed that the file is JSON and in the working directory of the db
-func (db *ZipDB) loadZipFile(zipFile *zip.File) {
+// Adds the given zip file to the package index of the database as an OSV.
+// It is assumed that the file is JSON and in the working directory of the db.
+//
+// Files that cannot be read are skipped, unless they do not match their checksum,
+// as then the archive has been corrupted and may not have every vulnerability.
+func loadZipFile(zipFile *zip.File, add func(models.Vulnerability, []byte) error) error {
 	file, err := zipFile.Open()
 	if err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "Could not read %s: %v\n", zipFile.Name, err)
 
-		return
+		return nil
 	}
 	defer file.Close()
 
 	content, err := io.ReadAll(file)
+	if errors.Is(err, zip.ErrChecksum) {
+		return fmt.Errorf("%w: %s does not match its checksum", ErrDatabaseCorrupted, zipFile.Name)
+	}
 	if err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "Could not read %s: %v\n", zipFile.Name, err)
 
-		return
+		return nil
 	}
 
 	var vulnerability models.Vulnerability
@@ -164,22 +254,56 @@ func (db *ZipDB) loadZipFile(zipFile *zip.File) {
 	if err := json.Unmarshal(content, &vulnerability); err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "%s is not a valid JSON file: %v\n", zipFile.Name, err)
 
-		return
+		return nil
 	}
 
-	db.vulnerabilities = append(db.vulnerabilities, vulnerability)
+	return add(vulnerability, content)
 }
 
-// load fetches a zip archive of the OSV database and loads known vulnerabilities
-// from it (which are assumed to be in json files following the OSV spec).
+// load fetches a zip archive of the OSV database and indexes the known vulnerabilities
+// in it (which are assumed to be in json files following the OSV spec) by the packages
+// that they affect, so that only the vulnerabilities of each package are read from disk.
 //
 // Internally, the archive is cached along with the date that it was fetched
 // so that a new version of the archive is only downloaded if it has been
 // modified, per HTTP caching standards.
-func (db *ZipDB) load() error {
-	db.vulnerabilities = []models.Vulnerability{}
+//
+// If the db host lists when each record was last modified, the records are
+// instead kept in a record store that only the modified records are fetched
+// into, rather than downloading the whole archive again.
+//
+// The index is only built again when the database has changed, and when offline
+// the database is only checksummed rather than loaded if the index was built from it.
+func (db *ZipDB) load(ctx context.Context) error {
+	if db.Offline {
+		storedAt := db.offlineSource()
+
+		if _, sum, err := hashFile(storedAt); err == nil {
+			if _, err := matchesChecksum(storedAt, sum); err != nil {
+				return err
+			}
+
+			if checkPackageIndex(db.PackageIndexAt, db.indexSource(storedAt, sum)) == nil {
+				db.StoredAt = storedAt
+
+				return nil
+			}
+		}
+	}
+
+	// records that are fetched one at a time are not signed, so signed databases are only ever archives
+	if db.SignatureKey == nil {
+		if s, err := db.loadRecordStore(ctx); err == nil {
+			db.StoredAt = db.IndexedAt
 
-	body, err := db.fetchZip()
+			// if the store could not be saved, the index is still built from the records that were loaded
+			_, sum, _ := hashFile(db.IndexedAt)
+
+			return db.useOrBuildPackageIndex(db.indexSource(db.IndexedAt, sum), s.each)
+		}
+	}
+
+	body, err := db.fetchZip(ctx)
 
 	if err != nil {
 		return err
@@ -190,58 +314,60 @@ func (db *ZipDB) load() error {
 		return fmt.Errorf("could not read OSV database archive: %w", err)
 	}
 
-	// Read all the files from the zip archive
-	for _, zipFile := range zipReader.File {
-		if !strings.HasSuffix(zipFile.Name, ".json") {
-			continue
-		}
+	return db.useOrBuildPackageIndex(db.indexSource(db.StoredAt, sha256Hex(body)), func(add func(models.Vulnerability, []byte) error) error {
+		// Read all the files from the zip archive
+		for _, zipFile := range zipReader.File {
+			if !strings.HasSuffix(zipFile.Name, ".json") {
+				continue
+			}
 
-		db.loadZipFile(zipFile)
-	}
+			if err := loadZipFile(zipFile, add); err != nil {
+				return err
+			}
+		}
 
-	return nil
+		return nil
+	})
 }
 
-func NewZippedDB(dbBasePath, name, url string, offline bool) (*ZipDB, error) {
-	db := &ZipDB{
-		Name:       name,
-		ArchiveURL: url,
-		Offline:    offline,
-		StoredAt:   path.Join(dbBasePath, name, "all.zip"),
-	}
-	if err := db.load(); err != nil {
-		return nil, fmt.Errorf("unable to fetch OSV database: %w", err)
+// offlineSource returns the path of the database that is used when offline, being its record store
+// unless its zip archive has been downloaded since or archives must be signed, matching loadRecordStore
+func (db *ZipDB) offlineSource() string {
+	if db.SignatureKey == nil {
+		if storeInfo, err := os.Stat(db.IndexedAt); err == nil {
+			if zipInfo, err := os.Stat(db.StoredAt); err != nil || !zipInfo.ModTime().After(storeInfo.ModTime()) {
+				return db.IndexedAt
+			}
+		}
 	}
 
-	return db, nil
+	return db.StoredAt
 }
 
-func (db *ZipDB) Vulnerabilities(includeWithdrawn bool) []models.Vulnerability {
-	if includeWithdrawn {
-		return db.vulnerabilities
+// newZipDB returns the database stored in dbBasePath, without loading it
+func newZipDB(dbBasePath, name, url string, headers http.Header, offline bool) *ZipDB {
+	return &ZipDB{
+		Name:           name,
+		ArchiveURL:     url,
+		Offline:        offline,
+		Headers:        headers,
+		StoredAt:       path.Join(dbBasePath, name, "all.zip"),
+		IndexedAt:      path.Join(dbBasePath, name, recordStoreFileName),
+		PackageIndexAt: path.Join(dbBasePath, name, packageIndexFileName),
 	}
+}
 
-	var vulnerabilities []models.Vulnerability
-
-	for _, vulnerability := range db.vulnerabilities {
-		if vulnerability.Withdrawn.IsZero() {
-			vulnerabilities = append(vulnerabilities, vulnerability)
-		}
+// openZipDB loads the database, downloading it with ctx if it needs to
+func openZipDB(ctx context.Context, db *ZipDB) (*ZipDB, error) {
+	if err := db.load(ctx); err != nil {
+		return nil, fmt.Errorf("unable to fetch OSV database: %w", err)
 	}
 
-	return vulnerabilities
+	return db, nil
 }
 
-func (db *ZipDB) VulnerabilitiesAffectingPackage(pkg lockfile.PackageDetails) models.Vulnerabilities {
-	var vulnerabilities models.Vulnerabilities
-
-	for _, vulnerability := range db.Vulnerabilities(false) {
-		if vulns.IsAffected(vulnerability, pkg) && !vulns.Include(vulnerabilities, vulnerability) {
-			vulnerabilities = append(vulnerabilities, vulnerability)
-		}
-	}
-
-	return vulnerabilities
+func NewZippedDB(ctx context.Context, dbBasePath, name, url string, headers http.Header, offline bool) (*ZipDB, error) {
+	return openZipDB(ctx, newZipDB(dbBasePath, name, url, headers, offline))
 }
 
 func (db *ZipDB) Check(pkgs []lockfile.PackageDetails) (models.Vulnerabilities, error) {
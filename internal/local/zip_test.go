@@ -3,6 +3,7 @@ package local_test
 import (
 	"archive/zip"
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/binary"
 	"encoding/json"
@@ -145,7 +146,7 @@ func TestNewZippedDB_Offline_WithoutCache(t *testing.T) {
 		t.Errorf("a server request was made when running offline")
 	})
 
-	_, err := local.NewZippedDB(testDir, "my-db", ts.URL, true)
+	_, err := local.NewZippedDB(context.Background(), testDir, "my-db", ts.URL, nil, true)
 
 	if !errors.Is(err, local.ErrOfflineDatabaseNotFound) {
 		t.Errorf("expected \"%v\" error but got \"%v\"", local.ErrOfflineDatabaseNotFound, err)
@@ -177,7 +178,7 @@ func TestNewZippedDB_Offline_WithCache(t *testing.T) {
 		"GHSA-5.json": {ID: "GHSA-5"},
 	}))
 
-	db, err := local.NewZippedDB(testDir, "my-db", ts.URL, true)
+	db, err := local.NewZippedDB(context.Background(), testDir, "my-db", ts.URL, nil, true)
 
 	if err != nil {
 		t.Fatalf("unexpected error \"%v\"", err)
@@ -195,7 +196,7 @@ func TestNewZippedDB_BadZip(t *testing.T) {
 		_, _ = w.Write([]byte("this is not a zip"))
 	})
 
-	_, err := local.NewZippedDB(testDir, "my-db", ts.URL, false)
+	_, err := local.NewZippedDB(context.Background(), testDir, "my-db", ts.URL, nil, false)
 
 	if err == nil {
 		t.Errorf("expected an error but did not get one")
@@ -207,7 +208,7 @@ func TestNewZippedDB_UnsupportedProtocol(t *testing.T) {
 
 	testDir := testutility.CreateTestDir(t)
 
-	_, err := local.NewZippedDB(testDir, "my-db", "file://hello-world", false)
+	_, err := local.NewZippedDB(context.Background(), testDir, "my-db", "file://hello-world", nil, false)
 
 	if err == nil {
 		t.Errorf("expected an error but did not get one")
@@ -237,7 +238,7 @@ func TestNewZippedDB_Online_WithoutCache(t *testing.T) {
 		})
 	})
 
-	db, err := local.NewZippedDB(testDir, "my-db", ts.URL, false)
+	db, err := local.NewZippedDB(context.Background(), testDir, "my-db", ts.URL, nil, false)
 
 	if err != nil {
 		t.Fatalf("unexpected error \"%v\"", err)
@@ -269,7 +270,7 @@ func TestNewZippedDB_Online_WithoutCacheAndNoHashHeader(t *testing.T) {
 		}))
 	})
 
-	db, err := local.NewZippedDB(testDir, "my-db", ts.URL, false)
+	db, err := local.NewZippedDB(context.Background(), testDir, "my-db", ts.URL, nil, false)
 
 	if err != nil {
 		t.Fatalf("unexpected error \"%v\"", err)
@@ -307,7 +308,7 @@ func TestNewZippedDB_Online_WithSameCache(t *testing.T) {
 
 	cacheWrite(t, determineStoredAtPath(testDir, "my-db"), cache)
 
-	db, err := local.NewZippedDB(testDir, "my-db", ts.URL, false)
+	db, err := local.NewZippedDB(context.Background(), testDir, "my-db", ts.URL, nil, false)
 
 	if err != nil {
 		t.Fatalf("unexpected error \"%v\"", err)
@@ -345,7 +346,7 @@ func TestNewZippedDB_Online_WithDifferentCache(t *testing.T) {
 		"GHSA-3.json": {ID: "GHSA-3"},
 	}))
 
-	db, err := local.NewZippedDB(testDir, "my-db", ts.URL, false)
+	db, err := local.NewZippedDB(context.Background(), testDir, "my-db", ts.URL, nil, false)
 
 	if err != nil {
 		t.Fatalf("unexpected error \"%v\"", err)
@@ -375,7 +376,7 @@ func TestNewZippedDB_Online_WithCacheButNoHashHeader(t *testing.T) {
 		"GHSA-3.json": {ID: "GHSA-3"},
 	}))
 
-	_, err := local.NewZippedDB(testDir, "my-db", ts.URL, false)
+	_, err := local.NewZippedDB(context.Background(), testDir, "my-db", ts.URL, nil, false)
 
 	if err == nil {
 		t.Errorf("expected an error but did not get one")
@@ -403,7 +404,7 @@ func TestNewZippedDB_Online_WithBadCache(t *testing.T) {
 
 	cacheWriteBad(t, determineStoredAtPath(testDir, "my-db"), "this is not json!")
 
-	db, err := local.NewZippedDB(testDir, "my-db", ts.URL, false)
+	db, err := local.NewZippedDB(context.Background(), testDir, "my-db", ts.URL, nil, false)
 
 	if err != nil {
 		t.Fatalf("unexpected error \"%v\"", err)
@@ -429,7 +430,7 @@ func TestNewZippedDB_FileChecks(t *testing.T) {
 		})
 	})
 
-	db, err := local.NewZippedDB(testDir, "my-db", ts.URL, false)
+	db, err := local.NewZippedDB(context.Background(), testDir, "my-db", ts.URL, nil, false)
 
 	if err != nil {
 		t.Fatalf("unexpected error \"%v\"", err)
@@ -437,3 +438,66 @@ func TestNewZippedDB_FileChecks(t *testing.T) {
 
 	expectDBToHaveOSVs(t, db, osvs)
 }
+
+func TestNewZippedDB_Online_WithHeaders(t *testing.T) {
+	t.Parallel()
+
+	osvs := []models.Vulnerability{{ID: "GHSA-1"}, {ID: "GHSA-2"}}
+
+	testDir := testutility.CreateTestDir(t)
+
+	ts := createZipServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer my-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		_, _ = writeOSVsZip(t, w, map[string]models.Vulnerability{
+			"GHSA-1.json": {ID: "GHSA-1"},
+			"GHSA-2.json": {ID: "GHSA-2"},
+		})
+	})
+
+	db, err := local.NewZippedDB(context.Background(), testDir, "my-db", ts.URL, http.Header{
+		"Authorization": {"Bearer my-token"},
+	}, false)
+
+	if err != nil {
+		t.Fatalf("unexpected error \"%v\"", err)
+	}
+
+	expectDBToHaveOSVs(t, db, osvs)
+}
+
+func TestNewZippedDB_Online_WithCacheAndEmptyArchive(t *testing.T) {
+	t.Parallel()
+
+	testDir := testutility.CreateTestDir(t)
+
+	ts := createZipServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = writeOSVsZip(t, w, map[string]models.Vulnerability{})
+	})
+
+	cache := zipOSVs(t, map[string]models.Vulnerability{
+		"GHSA-1.json": {ID: "GHSA-1"},
+	})
+
+	cacheWrite(t, determineStoredAtPath(testDir, "my-db"), cache)
+
+	_, err := local.NewZippedDB(context.Background(), testDir, "my-db", ts.URL, nil, false)
+
+	if err == nil {
+		t.Errorf("expected an error but did not get one")
+	}
+
+	content, err := os.ReadFile(determineStoredAtPath(testDir, "my-db"))
+
+	if err != nil {
+		t.Fatalf("unexpected error \"%v\"", err)
+	}
+
+	if !bytes.Equal(content, cache) {
+		t.Errorf("expected the cached database to not be replaced")
+	}
+}
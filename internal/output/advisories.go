@@ -0,0 +1,258 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/utility/results"
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/models"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+)
+
+// TableOptions customizes how the table and markdown outputs present the results
+type TableOptions struct {
+	// DedupeAdvisories lists each advisory once along with all the packages that it affects,
+	// rather than once for every package that it affects
+	DedupeAdvisories bool
+}
+
+// affectedPackage is a package that an advisory affects, with the columns that describe it
+type affectedPackage struct {
+	ecosystem string
+	name      string
+	version   string
+	fixed     string
+	source    string
+}
+
+// advisory is a group of vulnerabilities along with every package that it affects
+type advisory struct {
+	ids         []string
+	maxSeverity string
+	called      bool
+	affected    []affectedPackage
+}
+
+// higherSeverity returns whichever of the scores is higher, treating scores that are not numbers as the lowest
+func higherSeverity(a, b string) string {
+	aScore, aErr := strconv.ParseFloat(a, 64)
+	bScore, bErr := strconv.ParseFloat(b, 64)
+
+	if aErr != nil || (bErr == nil && bScore > aScore) {
+		return b
+	}
+
+	return a
+}
+
+// groupAdvisories combines the groups of vulnerabilities of every package in the results that have
+// the same IDs into an advisory, in the order that each advisory is first found in
+func groupAdvisories(vulnResult *models.VulnerabilityResults) []advisory {
+	workingDir := mustGetWorkingDirectory()
+
+	var advisories []advisory
+	indices := make(map[string]int)
+
+	for _, sourceRes := range vulnResult.Results {
+		sourcePath := sourceRes.Source.Path
+		if rel, err := filepath.Rel(workingDir, sourcePath); err == nil { // Simplify the path if possible
+			sourcePath = rel
+		}
+		if sourceRes.Source.Type == "manifest" {
+			sourcePath += " (resolved from manifest, not locked)"
+		}
+
+		for _, pkg := range sourceRes.Packages {
+			affected := affectedPackage{
+				ecosystem: pkg.Package.Ecosystem,
+				name:      pkg.Package.Name,
+				version:   pkg.Package.Version,
+				source:    sourcePath,
+			}
+			if pkg.Package.Ecosystem == "" && pkg.Package.Commit != "" {
+				pkgCommitStr := results.PkgToString(pkg.Package)
+				affected.ecosystem, affected.name, affected.version = "GIT", pkgCommitStr, pkgCommitStr
+			} else if lockfile.Ecosystem(pkg.Package.Ecosystem).IsDevGroup(pkg.DepGroups) {
+				affected.name += " (dev)"
+			}
+
+			for _, group := range pkg.Groups {
+				if len(group.IDs) == 0 {
+					continue
+				}
+
+				affected.fixed = FixedVersionsDescription(group)
+				key := fmt.Sprintf("%s:%t", group.IndexString(), group.IsCalled())
+
+				i, ok := indices[key]
+				if !ok {
+					i = len(advisories)
+					indices[key] = i
+					advisories = append(advisories, advisory{
+						ids:         group.IDs,
+						maxSeverity: group.MaxSeverity,
+						called:      group.IsCalled(),
+					})
+				}
+
+				advisories[i].maxSeverity = higherSeverity(advisories[i].maxSeverity, group.MaxSeverity)
+				advisories[i].affected = append(advisories[i].affected, affected)
+			}
+		}
+	}
+
+	return advisories
+}
+
+// advisoryRow builds a row for the advisory that lists each package it affects on its own line
+func advisoryRow(adv advisory, addStyling bool, showFixed bool) table.Row {
+	links := make([]string, 0, len(adv.ids))
+	for _, id := range adv.ids {
+		if addStyling {
+			links = append(links, OSVBaseVulnerabilityURL+text.Bold.EscapeSeq()+id+text.Reset.EscapeSeq())
+		} else {
+			links = append(links, OSVBaseVulnerabilityURL+id)
+		}
+	}
+
+	column := func(value func(affectedPackage) string) string {
+		values := make([]string, 0, len(adv.affected))
+		for _, affected := range adv.affected {
+			values = append(values, value(affected))
+		}
+
+		return strings.Join(values, "\n")
+	}
+
+	row := table.Row{
+		strings.Join(links, "\n"),
+		adv.maxSeverity,
+		column(func(a affectedPackage) string { return a.ecosystem }),
+		column(func(a affectedPackage) string { return a.name }),
+		column(func(a affectedPackage) string { return a.version }),
+	}
+	if showFixed {
+		row = append(row, column(func(a affectedPackage) string { return a.fixed }))
+	}
+
+	return append(row, column(func(a affectedPackage) string { return a.source }))
+}
+
+// advisoriesTableBuilder builds a row for each advisory, rather than for each package like tableBuilder,
+// followed by the uncalled and suppressed vulnerabilities
+func advisoriesTableBuilder(outputTable table.Writer, vulnResult *models.VulnerabilityResults, addStyling bool) table.Writer {
+	showFixed := hasFixedVersions(vulnResult)
+	if showFixed {
+		outputTable.AppendHeader(table.Row{"OSV URL", "CVSS", "Ecosystem", "Package", "Version", "Fixed version", "Source"})
+	} else {
+		outputTable.AppendHeader(table.Row{"OSV URL", "CVSS", "Ecosystem", "Package", "Version", "Source"})
+	}
+
+	advisories := groupAdvisories(vulnResult)
+	for _, adv := range advisories {
+		if adv.called {
+			outputTable.AppendRow(advisoryRow(adv, addStyling, showFixed))
+		}
+	}
+
+	var uncalled []advisory
+	for _, adv := range advisories {
+		if !adv.called {
+			uncalled = append(uncalled, adv)
+		}
+	}
+	if len(uncalled) > 0 {
+		outputTable.AppendSeparator()
+		outputTable.AppendRow(table.Row{"Uncalled vulnerabilities"})
+		outputTable.AppendSeparator()
+
+		for _, adv := range uncalled {
+			outputTable.AppendRow(advisoryRow(adv, addStyling, showFixed))
+		}
+	}
+
+	suppressedRows := suppressedTableBuilderInner(vulnResult, addStyling, showFixed)
+	if len(suppressedRows) > 0 {
+		outputTable.AppendSeparator()
+		outputTable.AppendRow(table.Row{"Suppressed vulnerabilities"})
+		outputTable.AppendSeparator()
+
+		for _, elem := range suppressedRows {
+			outputTable.AppendRow(elem.row, table.RowConfig{AutoMerge: elem.shouldMerge})
+		}
+	}
+
+	return outputTable
+}
+
+// printMarkdownAdvisories prints a table with a row for each advisory listing the packages that it
+// affects, with uncalled and suppressed vulnerabilities and the dependency paths listed separately
+func printMarkdownAdvisories(vulnResult *models.VulnerabilityResults, outputWriter io.Writer) {
+	showFixed := hasFixedVersions(vulnResult)
+	advisoriesTable := func(called bool) table.Writer {
+		outputTable := table.NewWriter()
+		outputTable.SetOutputMirror(outputWriter)
+		if showFixed {
+			outputTable.AppendHeader(table.Row{"OSV URL", "CVSS", "Ecosystem", "Package", "Version", "Fixed version", "Source"})
+		} else {
+			outputTable.AppendHeader(table.Row{"OSV URL", "CVSS", "Ecosystem", "Package", "Version", "Source"})
+		}
+
+		for _, adv := range groupAdvisories(vulnResult) {
+			if adv.called == called {
+				outputTable.AppendRow(advisoryRow(adv, false, showFixed))
+			}
+		}
+
+		return outputTable
+	}
+
+	calledTable := advisoriesTable(true)
+	uncalledTable := advisoriesTable(false)
+	suppressedRows := suppressedTableBuilderInner(vulnResult, false, showFixed)
+
+	if calledTable.Length() == 0 && uncalledTable.Length() == 0 && len(suppressedRows) == 0 {
+		return
+	}
+
+	fmt.Fprintf(outputWriter, "\n## Advisories\n")
+
+	if calledTable.Length() != 0 {
+		fmt.Fprintln(outputWriter)
+		calledTable.RenderMarkdown()
+	}
+
+	if uncalledTable.Length() != 0 {
+		fmt.Fprintf(outputWriter, "\nUncalled vulnerabilities:\n\n")
+		uncalledTable.RenderMarkdown()
+	}
+
+	if len(suppressedRows) > 0 {
+		suppressedTable := table.NewWriter()
+		suppressedTable.SetOutputMirror(outputWriter)
+		if showFixed {
+			suppressedTable.AppendHeader(table.Row{"OSV URL", "CVSS", "Ecosystem", "Package", "Version", "Fixed version", "Source"})
+		} else {
+			suppressedTable.AppendHeader(table.Row{"OSV URL", "CVSS", "Ecosystem", "Package", "Version", "Source"})
+		}
+		for _, elem := range suppressedRows {
+			suppressedTable.AppendRow(elem.row)
+		}
+
+		fmt.Fprintf(outputWriter, "\nSuppressed vulnerabilities:\n\n")
+		suppressedTable.RenderMarkdown()
+	}
+
+	pathsTable := table.NewWriter()
+	pathsTable.SetOutputMirror(outputWriter)
+	if pathsTable = dependencyPathsTableBuilder(pathsTable, vulnResult); pathsTable.Length() != 0 {
+		fmt.Fprintf(outputWriter, "\nDependency paths:\n\n")
+		pathsTable.RenderMarkdown()
+	}
+}
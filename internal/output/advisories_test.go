@@ -0,0 +1,83 @@
+package output_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/internal/testutility"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func TestDedupeAdvisories(t *testing.T) {
+	t.Parallel()
+
+	vulnResult := &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "path/to/package-lock.json", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package:         models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"},
+						Vulnerabilities: []models.Vulnerability{{ID: "GHSA-29mw-wpgm-hmr9"}, {ID: "GHSA-35jh-r3h4-6jhm"}},
+						Groups: []models.GroupInfo{
+							{IDs: []string{"GHSA-29mw-wpgm-hmr9"}, MaxSeverity: "5.3"},
+							{IDs: []string{"GHSA-35jh-r3h4-6jhm"}, MaxSeverity: "7.2"},
+						},
+					},
+					{
+						Package:         models.PackageInfo{Name: "lodash", Version: "4.17.15", Ecosystem: "npm"},
+						DepGroups:       []string{"dev"},
+						Vulnerabilities: []models.Vulnerability{{ID: "GHSA-35jh-r3h4-6jhm"}},
+						Groups:          []models.GroupInfo{{IDs: []string{"GHSA-35jh-r3h4-6jhm"}, MaxSeverity: "7.2"}},
+						Suppressed:      []models.SuppressedVulnerability{{ID: "GHSA-p6mc-m468-83gw", Reason: "not reachable"}},
+					},
+				},
+			},
+			{
+				Source: models.SourceInfo{Path: "other/path/to/package-lock.json", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package:         models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"},
+						Vulnerabilities: []models.Vulnerability{{ID: "GHSA-35jh-r3h4-6jhm"}},
+						Groups:          []models.GroupInfo{{IDs: []string{"GHSA-35jh-r3h4-6jhm"}, MaxSeverity: "7.2"}},
+						DependencyPaths: [][]string{{"webpack@5.0.0", "lodash@4.17.20"}},
+					},
+				},
+			},
+			{
+				Source: models.SourceInfo{Path: "path/to/go.mod", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package:         models.PackageInfo{Name: "stdlib", Version: "1.21.7", Ecosystem: "Go"},
+						Vulnerabilities: []models.Vulnerability{{ID: "GO-2024-2598"}},
+						Groups: []models.GroupInfo{
+							{
+								IDs:                  []string{"GO-2024-2598"},
+								ExperimentalAnalysis: map[string]models.AnalysisInfo{"GO-2024-2598": {Called: false}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	t.Run("table", func(t *testing.T) {
+		t.Parallel()
+
+		bufOut := bytes.Buffer{}
+		output.PrintTableResultsWithOptions(vulnResult, &bufOut, 0, output.TableOptions{DedupeAdvisories: true})
+
+		testutility.NewSnapshot().MatchText(t, bufOut.String())
+	})
+
+	t.Run("markdown", func(t *testing.T) {
+		t.Parallel()
+
+		bufOut := bytes.Buffer{}
+		output.PrintMarkdownTableResultsWithOptions(vulnResult, &bufOut, output.TableOptions{DedupeAdvisories: true})
+
+		testutility.NewSnapshot().MatchText(t, bufOut.String())
+	})
+}
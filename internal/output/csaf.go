@@ -0,0 +1,382 @@
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/osv-scanner/internal/utility/severity"
+	"github.com/google/osv-scanner/internal/version"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+type csafDocument struct {
+	Document        csafDocumentMeta    `json:"document"`
+	ProductTree     csafProductTree     `json:"product_tree"`
+	Vulnerabilities []csafVulnerability `json:"vulnerabilities"`
+}
+
+type csafDocumentMeta struct {
+	Category    string        `json:"category"`
+	CSAFVersion string        `json:"csaf_version"`
+	Publisher   csafPublisher `json:"publisher"`
+	Title       string        `json:"title"`
+	Tracking    csafTracking  `json:"tracking"`
+}
+
+type csafPublisher struct {
+	Category  string `json:"category"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+type csafTracking struct {
+	ID                 string         `json:"id"`
+	Status             string         `json:"status"`
+	Version            string         `json:"version"`
+	InitialReleaseDate string         `json:"initial_release_date"`
+	CurrentReleaseDate string         `json:"current_release_date"`
+	RevisionHistory    []csafRevision `json:"revision_history"`
+	Generator          csafGenerator  `json:"generator"`
+}
+
+type csafRevision struct {
+	Date    string `json:"date"`
+	Number  string `json:"number"`
+	Summary string `json:"summary"`
+}
+
+type csafGenerator struct {
+	Date   string `json:"date"`
+	Engine struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"engine"`
+}
+
+type csafProductTree struct {
+	Branches []csafBranch `json:"branches,omitempty"`
+}
+
+// csafBranch is a level of the product tree, which is a source, a package in it, or a version of that
+// package, with only the versions being products that the vulnerabilities can refer to
+type csafBranch struct {
+	Category string       `json:"category"`
+	Name     string       `json:"name"`
+	Branches []csafBranch `json:"branches,omitempty"`
+	Product  *csafProduct `json:"product,omitempty"`
+}
+
+type csafProduct struct {
+	Name                        string                           `json:"name"`
+	ProductID                   string                           `json:"product_id"`
+	ProductIdentificationHelper *csafProductIdentificationHelper `json:"product_identification_helper,omitempty"`
+}
+
+type csafProductIdentificationHelper struct {
+	PURL string `json:"purl"`
+}
+
+type csafVulnerability struct {
+	CVE           string            `json:"cve,omitempty"`
+	IDs           []csafID          `json:"ids,omitempty"`
+	Notes         []csafNote        `json:"notes"`
+	ProductStatus csafProductStatus `json:"product_status"`
+	Remediations  []csafRemediation `json:"remediations"`
+	Scores        []csafScore       `json:"scores,omitempty"`
+	References    []csafReference   `json:"references"`
+}
+
+type csafID struct {
+	SystemName string `json:"system_name"`
+	Text       string `json:"text"`
+}
+
+type csafNote struct {
+	Category string `json:"category"`
+	Text     string `json:"text"`
+}
+
+type csafProductStatus struct {
+	KnownAffected []string `json:"known_affected"`
+}
+
+type csafRemediation struct {
+	Category   string   `json:"category"`
+	Details    string   `json:"details"`
+	ProductIDs []string `json:"product_ids"`
+}
+
+type csafScore struct {
+	Products []string  `json:"products"`
+	CVSSV2   *csafCVSS `json:"cvss_v2,omitempty"`
+	CVSSV3   *csafCVSS `json:"cvss_v3,omitempty"`
+}
+
+type csafCVSS struct {
+	Version      string  `json:"version"`
+	VectorString string  `json:"vectorString"`
+	BaseScore    float64 `json:"baseScore"`
+	BaseSeverity string  `json:"baseSeverity,omitempty"`
+}
+
+type csafReference struct {
+	Category string `json:"category"`
+	Summary  string `json:"summary"`
+	URL      string `json:"url"`
+}
+
+// csafGroupVulns returns the vulnerabilities of the package that are in the group
+func csafGroupVulns(pkg models.PackageVulns, group models.GroupInfo) []models.Vulnerability {
+	var vulns []models.Vulnerability
+	for _, vuln := range pkg.Vulnerabilities {
+		if slices.Contains(group.IDs, vuln.ID) {
+			vulns = append(vulns, vuln)
+		}
+	}
+
+	return vulns
+}
+
+// csafIdentifiers returns the CVE of the group, as CSAF only allows each vulnerability to have
+// one, along with the other IDs and aliases of the group named after the database they are from
+func csafIdentifiers(group models.GroupInfo, vulns []models.Vulnerability) (string, []csafID) {
+	ids := append(slices.Clone(group.IDs), group.Aliases...)
+	for _, vuln := range vulns {
+		ids = append(ids, vuln.Aliases...)
+	}
+	slices.SortFunc(ids[len(group.IDs):], idSortFunc)
+
+	var cve string
+	var others []csafID
+	for _, id := range ids {
+		if strings.HasPrefix(id, "CVE-") && (cve == "" || cve == id) {
+			cve = id
+			continue
+		}
+		if slices.ContainsFunc(others, func(other csafID) bool { return other.Text == id }) {
+			continue
+		}
+
+		systemName, _, _ := strings.Cut(id, "-")
+		others = append(others, csafID{SystemName: systemName, Text: id})
+	}
+
+	return cve, others
+}
+
+// csafNotes describes the vulnerabilities with their summary, or their details if none of them have one
+func csafNotes(group models.GroupInfo, vulns []models.Vulnerability) []csafNote {
+	for _, vuln := range vulns {
+		if vuln.Summary != "" {
+			return []csafNote{{Category: "summary", Text: vuln.Summary}}
+		}
+	}
+
+	for _, vuln := range vulns {
+		if vuln.Details != "" {
+			return []csafNote{{Category: "description", Text: vuln.Details}}
+		}
+	}
+
+	return []csafNote{{Category: "general", Text: "See " + OSVBaseVulnerabilityURL + group.IDs[0]}}
+}
+
+// csafScores returns the highest CVSS v3 and v2 scores of the vulnerabilities,
+// which are the only versions of CVSS that CSAF 2.0 supports
+func csafScores(vulns []models.Vulnerability) []csafScore {
+	var score csafScore
+	for _, vuln := range vulns {
+		for _, sev := range vuln.Severity {
+			if sev.Type != models.SeverityCVSSV2 && sev.Type != models.SeverityCVSSV3 {
+				continue
+			}
+
+			baseScore, rating, err := severity.CalculateScore(sev)
+			if err != nil || baseScore < 0 {
+				continue
+			}
+
+			cvss := &csafCVSS{VectorString: sev.Score, BaseScore: baseScore}
+			if sev.Type == models.SeverityCVSSV2 {
+				cvss.Version = "2.0"
+				if score.CVSSV2 == nil || baseScore > score.CVSSV2.BaseScore {
+					score.CVSSV2 = cvss
+				}
+
+				continue
+			}
+
+			cvss.Version = strings.TrimPrefix(strings.Split(sev.Score, "/")[0], "CVSS:")
+			cvss.BaseSeverity = strings.ToUpper(rating)
+			if score.CVSSV3 == nil || baseScore > score.CVSSV3.BaseScore {
+				score.CVSSV3 = cvss
+			}
+		}
+	}
+
+	if score.CVSSV2 == nil && score.CVSSV3 == nil {
+		return nil
+	}
+
+	return []csafScore{score}
+}
+
+// csafRemediationAction returns the category and details of the remediation for the group,
+// which is either upgrading to the versions that fix it or that there is no fix available
+func csafRemediationAction(group models.GroupInfo) (string, string) {
+	if len(group.FixedVersions) == 0 {
+		return "none_available", "No fix is available"
+	}
+
+	return "vendor_fix", "Upgrade to " + strings.Join(group.FixedVersions, ", ")
+}
+
+// addProduct records that the vulnerability affects the product, along with how to remediate it
+func (v *csafVulnerability) addProduct(productID string, group models.GroupInfo) {
+	v.ProductStatus.KnownAffected = append(v.ProductStatus.KnownAffected, productID)
+	for i := range v.Scores {
+		v.Scores[i].Products = append(v.Scores[i].Products, productID)
+	}
+
+	category, details := csafRemediationAction(group)
+	i := slices.IndexFunc(v.Remediations, func(r csafRemediation) bool { return r.Category == category && r.Details == details })
+	if i < 0 {
+		v.Remediations = append(v.Remediations, csafRemediation{Category: category, Details: details})
+		i = len(v.Remediations) - 1
+	}
+	v.Remediations[i].ProductIDs = append(v.Remediations[i].ProductIDs, productID)
+}
+
+// csafPackageBranch returns the branch of the source for the package, adding one for it if there is not one already
+func csafPackageBranch(source *csafBranch, name string) *csafBranch {
+	i := slices.IndexFunc(source.Branches, func(b csafBranch) bool { return b.Name == name })
+	if i < 0 {
+		source.Branches = append(source.Branches, csafBranch{Category: "product_name", Name: name})
+		i = len(source.Branches) - 1
+	}
+
+	return &source.Branches[i]
+}
+
+// buildCSAFDocument describes the results as a CSAF 2.0 VEX document released at the given time, where
+// each version of a package that has vulnerabilities is a product under the source it was found in, and
+// each vulnerability is known to affect the products of every package that it was found in
+func buildCSAFDocument(vulnResult *models.VulnerabilityResults, released time.Time) csafDocument {
+	date := released.UTC().Format(time.RFC3339)
+
+	doc := csafDocument{
+		Document: csafDocumentMeta{
+			Category:    "csaf_vex",
+			CSAFVersion: "2.0",
+			Publisher: csafPublisher{
+				Category:  "other",
+				Name:      "OSV-Scanner",
+				Namespace: "https://github.com/google/osv-scanner",
+			},
+			Title: "OSV-Scanner report",
+			Tracking: csafTracking{
+				Status:             "final",
+				Version:            "1",
+				InitialReleaseDate: date,
+				CurrentReleaseDate: date,
+				RevisionHistory:    []csafRevision{{Date: date, Number: "1", Summary: "Initial version"}},
+				Generator:          csafGenerator{Date: date},
+			},
+		},
+		Vulnerabilities: []csafVulnerability{},
+	}
+	doc.Document.Tracking.Generator.Engine.Name = "OSV-Scanner"
+	doc.Document.Tracking.Generator.Engine.Version = version.OSVVersion
+
+	idHash := sha256.New()
+	fmt.Fprint(idHash, date)
+
+	// the vulnerabilities are keyed by the first ID of their group, which is the same for every package they affect
+	vulnIndices := make(map[string]int)
+	productCount := 0
+
+	for _, sourceRes := range vulnResult.Results {
+		source := csafBranch{Category: "product_family", Name: sourceRes.Source.Path}
+
+		for _, pkg := range sourceRes.Packages {
+			if !slices.ContainsFunc(pkg.Groups, func(g models.GroupInfo) bool { return len(g.IDs) > 0 }) {
+				continue
+			}
+
+			pkgVersion := pkg.Package.Version
+			if pkgVersion == "" {
+				pkgVersion = pkg.Package.Commit
+			}
+
+			product := &csafProduct{
+				Name:      fmt.Sprintf("%s %s in %s", pkg.Package.Name, pkgVersion, sourceRes.Source.Path),
+				ProductID: fmt.Sprintf("CSAFPID-%d", productCount),
+			}
+			productCount++
+			if purl, ok := models.PackageToPURL(pkg.Package); ok {
+				product.ProductIdentificationHelper = &csafProductIdentificationHelper{PURL: purl}
+			}
+
+			pkgBranch := csafPackageBranch(&source, pkg.Package.Name)
+			pkgBranch.Branches = append(pkgBranch.Branches, csafBranch{Category: "product_version", Name: pkgVersion, Product: product})
+			fmt.Fprint(idHash, "\x00", product.Name)
+
+			for _, group := range pkg.Groups {
+				if len(group.IDs) == 0 {
+					continue
+				}
+
+				i, ok := vulnIndices[group.IDs[0]]
+				if !ok {
+					vulns := csafGroupVulns(pkg, group)
+					cve, ids := csafIdentifiers(group, vulns)
+
+					vuln := csafVulnerability{
+						CVE:           cve,
+						IDs:           ids,
+						Notes:         csafNotes(group, vulns),
+						ProductStatus: csafProductStatus{KnownAffected: []string{}},
+						Scores:        csafScores(vulns),
+					}
+					for _, id := range group.IDs {
+						vuln.References = append(vuln.References, csafReference{
+							Category: "external",
+							Summary:  id,
+							URL:      OSVBaseVulnerabilityURL + id,
+						})
+					}
+
+					i = len(doc.Vulnerabilities)
+					vulnIndices[group.IDs[0]] = i
+					doc.Vulnerabilities = append(doc.Vulnerabilities, vuln)
+				}
+
+				doc.Vulnerabilities[i].addProduct(product.ProductID, group)
+			}
+		}
+
+		if len(source.Branches) > 0 {
+			doc.ProductTree.Branches = append(doc.ProductTree.Branches, source)
+		}
+	}
+
+	doc.Document.Tracking.ID = "osv-scanner-" + hex.EncodeToString(idHash.Sum(nil))
+
+	return doc
+}
+
+// PrintCSAFReport prints the vulnerabilities in the results as a CSAF 2.0 VEX document, with the packages
+// that have vulnerabilities as its products, grouped by the source that they were found in, for use by
+// tooling that supports the Common Security Advisory Framework
+func PrintCSAFReport(vulnResult *models.VulnerabilityResults, outputWriter io.Writer) error {
+	encoder := json.NewEncoder(outputWriter)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(buildCSAFDocument(vulnResult, time.Now()))
+}
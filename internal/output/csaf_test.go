@@ -0,0 +1,84 @@
+package output_test
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/internal/testutility"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// the release dates and the ID derived from them change with every run
+var csafDateRe = regexp.MustCompile(`"(id|date|initial_release_date|current_release_date)": ".*"`)
+
+func TestPrintCSAFReport(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		args models.VulnerabilityResults
+		want testutility.Snapshot
+	}{
+		{
+			name: "vulnerabilities",
+			args: testutility.LoadJSONFixture[models.VulnerabilityResults](t, "fixtures/test-vuln-results-a.json"),
+			want: testutility.NewSnapshot(),
+		},
+		{
+			name: "vulnerability affecting packages in multiple sources",
+			args: models.VulnerabilityResults{
+				Results: []models.PackageSource{
+					{
+						Source: models.SourceInfo{Path: "path/to/package-lock.json", Type: "lockfile"},
+						Packages: []models.PackageVulns{
+							{
+								Package: models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"},
+								Groups: []models.GroupInfo{
+									{IDs: []string{"GHSA-35jh-r3h4-6jhm"}, Aliases: []string{"CVE-2021-23337", "GHSA-35jh-r3h4-6jhm"}, FixedVersions: []string{"4.17.21"}},
+								},
+							},
+						},
+					},
+					{
+						Source: models.SourceInfo{Path: "path/to/other/package-lock.json", Type: "lockfile"},
+						Packages: []models.PackageVulns{
+							{
+								Package: models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"},
+								Groups: []models.GroupInfo{
+									{IDs: []string{"GHSA-35jh-r3h4-6jhm"}, Aliases: []string{"CVE-2021-23337", "GHSA-35jh-r3h4-6jhm"}, FixedVersions: []string{"4.17.21"}},
+								},
+							},
+							{
+								Package: models.PackageInfo{Name: "lodash", Version: "4.17.15", Ecosystem: "npm"},
+								Groups: []models.GroupInfo{
+									{IDs: []string{"GHSA-35jh-r3h4-6jhm"}, Aliases: []string{"CVE-2021-23337", "GHSA-35jh-r3h4-6jhm"}, FixedVersions: []string{}},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: testutility.NewSnapshot(),
+		},
+		{
+			name: "no vulnerabilities",
+			args: models.VulnerabilityResults{},
+			want: testutility.NewSnapshot(),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			bufOut := bytes.Buffer{}
+			err := output.PrintCSAFReport(&tt.args, &bufOut)
+			if err != nil {
+				t.Errorf("Error writing CSAF output: %s", err)
+			}
+			tt.want.MatchText(t, csafDateRe.ReplaceAllString(bufOut.String(), `"$1": "<redacted>"`))
+		})
+	}
+}
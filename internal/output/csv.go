@@ -0,0 +1,107 @@
+package output
+
+import (
+	"encoding/csv"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// csvColumns are the columns that can be included in the CSV output, in their default order
+var csvColumns = []string{"source", "ecosystem", "package", "version", "id", "cve", "severity", "fixed-version", "summary"}
+
+// CSVColumns returns the columns that can be included in the CSV output, in their default order
+func CSVColumns() []string {
+	return slices.Clone(csvColumns)
+}
+
+// csvValue returns the value of the column for the vulnerabilities of the group
+func csvValue(column string, source models.SourceInfo, pkg models.PackageVulns, group models.GroupInfo) string {
+	switch column {
+	case "source":
+		return source.String()
+	case "ecosystem":
+		return pkg.Package.Ecosystem
+	case "package":
+		return pkg.Package.Name
+	case "version":
+		if pkg.Package.Version == "" {
+			return pkg.Package.Commit
+		}
+
+		return pkg.Package.Version
+	case "id":
+		return strings.Join(group.IDs, ", ")
+	case "cve":
+		// the aliases of the group are not always set, so also look at the vulnerabilities in it
+		aliases := slices.Clone(group.Aliases)
+		for _, vuln := range pkg.Vulnerabilities {
+			if slices.Contains(group.IDs, vuln.ID) {
+				aliases = append(aliases, vuln.ID)
+				aliases = append(aliases, vuln.Aliases...)
+			}
+		}
+
+		var cves []string
+		for _, alias := range aliases {
+			if strings.HasPrefix(alias, "CVE-") {
+				cves = append(cves, alias)
+			}
+		}
+		slices.Sort(cves)
+
+		return strings.Join(slices.Compact(cves), ", ")
+	case "severity":
+		return group.MaxSeverity
+	case "fixed-version":
+		if group.FixedVersions == nil {
+			return ""
+		}
+
+		return FixedVersionsDescription(group)
+	case "summary":
+		for _, vuln := range pkg.Vulnerabilities {
+			if vuln.Summary != "" && slices.Contains(group.IDs, vuln.ID) {
+				return vuln.Summary
+			}
+		}
+	}
+
+	return ""
+}
+
+// PrintCSVReport prints a header row followed by a row for each group of vulnerabilities of every package,
+// with only the given columns in the order they are given, or all of them if no columns are given
+func PrintCSVReport(vulnResult *models.VulnerabilityResults, outputWriter io.Writer, columns []string) error {
+	if len(columns) == 0 {
+		columns = csvColumns
+	}
+
+	writer := csv.NewWriter(outputWriter)
+	if err := writer.Write(columns); err != nil {
+		return err
+	}
+
+	row := make([]string, len(columns))
+	for _, sourceRes := range vulnResult.Results {
+		for _, pkg := range sourceRes.Packages {
+			for _, group := range pkg.Groups {
+				if len(group.IDs) == 0 {
+					continue
+				}
+				for i, column := range columns {
+					row[i] = csvValue(column, sourceRes.Source, pkg, group)
+				}
+				if err := writer.Write(row); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
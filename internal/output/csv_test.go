@@ -0,0 +1,101 @@
+package output_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/internal/testutility"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func TestPrintCSVReport(t *testing.T) {
+	t.Parallel()
+
+	fixedVersions := models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "path/to/package-lock.json", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package:         models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"},
+						Vulnerabilities: []models.Vulnerability{{ID: "GHSA-35jh-r3h4-6jhm", Summary: "Command Injection in lodash"}},
+						Groups: []models.GroupInfo{
+							{
+								IDs:           []string{"GHSA-35jh-r3h4-6jhm"},
+								Aliases:       []string{"CVE-2021-23337", "GHSA-35jh-r3h4-6jhm"},
+								MaxSeverity:   "7.2",
+								FixedVersions: []string{"4.17.21"},
+							},
+						},
+					},
+					{
+						Package:           models.PackageInfo{Name: "left-pad", Version: "1.0.0", Ecosystem: "npm"},
+						LicenseViolations: []models.License{"WTFPL"},
+						Groups:            []models.GroupInfo{{}},
+					},
+					{
+						Package:         models.PackageInfo{Name: "node-serialize", Version: "0.0.4", Ecosystem: "npm"},
+						Vulnerabilities: []models.Vulnerability{{ID: "GHSA-q4v7-4rhw-9hqm", Summary: "Code Execution through IIFE in node-serialize"}},
+						Groups: []models.GroupInfo{
+							{
+								IDs:           []string{"GHSA-q4v7-4rhw-9hqm"},
+								Aliases:       []string{"CVE-2017-5941", "GHSA-q4v7-4rhw-9hqm"},
+								MaxSeverity:   "9.8",
+								FixedVersions: []string{},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		args    models.VulnerabilityResults
+		columns []string
+		want    testutility.Snapshot
+	}{
+		{
+			name: "all columns",
+			args: testutility.LoadJSONFixtureWithWindowsReplacements[models.VulnerabilityResults](t,
+				"fixtures/test-vuln-results-a.json",
+				map[string]string{
+					"/path/to/sub-rust-project/Cargo.lock": "D:\\\\path\\\\to\\\\sub-rust-project\\\\Cargo.lock",
+					"/path/to/go.mod":                      "D:\\\\path\\\\to\\\\go.mod",
+				},
+			),
+			want: testutility.NewSnapshot().WithWindowsReplacements(
+				map[string]string{
+					"lockfile:D:\\path\\to\\sub-rust-project\\Cargo.lock": "lockfile:/path/to/sub-rust-project/Cargo.lock",
+					"lockfile:D:\\path\\to\\go.mod":                       "lockfile:/path/to/go.mod",
+				},
+			),
+		},
+		{
+			name: "with fixed versions",
+			args: fixedVersions,
+			want: testutility.NewSnapshot(),
+		},
+		{
+			name:    "selected columns",
+			args:    fixedVersions,
+			columns: []string{"package", "version", "cve", "fixed-version"},
+			want:    testutility.NewSnapshot(),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			bufOut := bytes.Buffer{}
+			err := output.PrintCSVReport(&tt.args, &bufOut, tt.columns)
+			if err != nil {
+				t.Errorf("Error writing CSV output: %s", err)
+			}
+			tt.want.MatchText(t, bufOut.String())
+		})
+	}
+}
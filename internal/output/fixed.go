@@ -0,0 +1,44 @@
+package output
+
+import (
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// noFixAvailable describes the fixed versions of vulnerabilities that have not been fixed
+const noFixAvailable = "no fix available"
+
+// FixedVersionsDescription describes the versions that fix the vulnerabilities in the group
+func FixedVersionsDescription(group models.GroupInfo) string {
+	if len(group.FixedVersions) == 0 {
+		return noFixAvailable
+	}
+
+	return strings.Join(group.FixedVersions, ", ")
+}
+
+// packageHasFixedVersions reports if the fixed versions of the vulnerabilities of the package
+// were reported, which is the case even if none of them have a fix available
+func packageHasFixedVersions(pkg models.PackageVulns) bool {
+	for _, group := range pkg.Groups {
+		if group.FixedVersions != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasFixedVersions reports if the fixed versions of the vulnerabilities in the results were reported
+func hasFixedVersions(vulnResult *models.VulnerabilityResults) bool {
+	for _, sourceRes := range vulnResult.Results {
+		for _, pkg := range sourceRes.Packages {
+			if packageHasFixedVersions(pkg) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
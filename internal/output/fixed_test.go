@@ -0,0 +1,70 @@
+package output_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/internal/testutility"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func TestFixedVersions(t *testing.T) {
+	t.Parallel()
+
+	vulnResult := &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "path/to/package-lock.json", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package: models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"},
+						Vulnerabilities: []models.Vulnerability{
+							{ID: "GHSA-29mw-wpgm-hmr9"},
+							{ID: "GHSA-35jh-r3h4-6jhm"},
+						},
+						Groups: []models.GroupInfo{
+							{IDs: []string{"GHSA-29mw-wpgm-hmr9"}, MaxSeverity: "5.3", FixedVersions: []string{"4.17.21"}},
+							{IDs: []string{"GHSA-35jh-r3h4-6jhm"}, MaxSeverity: "7.2", FixedVersions: []string{}},
+						},
+						Suppressed: []models.SuppressedVulnerability{{ID: "GHSA-p6mc-m468-83gw", Reason: "not reachable"}},
+					},
+				},
+			},
+		},
+	}
+
+	t.Run("table", func(t *testing.T) {
+		t.Parallel()
+
+		bufOut := bytes.Buffer{}
+		output.PrintTableResults(vulnResult, &bufOut, 0)
+
+		testutility.NewSnapshot().MatchText(t, bufOut.String())
+	})
+
+	t.Run("markdown", func(t *testing.T) {
+		t.Parallel()
+
+		bufOut := bytes.Buffer{}
+		output.PrintMarkdownTableResults(vulnResult, &bufOut)
+
+		testutility.NewSnapshot().MatchText(t, bufOut.String())
+	})
+
+	t.Run("html", func(t *testing.T) {
+		t.Parallel()
+
+		bufOut := bytes.Buffer{}
+		if err := output.PrintHTMLReport(vulnResult, &bufOut); err != nil {
+			t.Fatalf("Error writing HTML output: %v", err)
+		}
+
+		for _, want := range []string{"<th>Fixed version</th>", "<td>4.17.21</td>", "<td>no fix available</td>"} {
+			if !strings.Contains(bufOut.String(), want) {
+				t.Errorf("HTML report should contain %q", want)
+			}
+		}
+	})
+}
@@ -0,0 +1,315 @@
+package output
+
+import (
+	"crypto/sha256"
+	_ "embed"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// The stylesheet is inlined into the report rather than linked to, so that
+// the report renders the same when viewed offline or saved for later
+//
+//go:embed html/style.css
+var htmlStyle string
+
+//go:embed html/report.gohtml
+var htmlReportTemplate string
+
+// The script for filtering and sorting the vulnerabilities is inlined for the same reason,
+// with the report still being readable (just not interactive) when scripts are disabled
+//
+//go:embed html/report.js
+var htmlScript string
+
+// HTMLScriptHash returns the hash of the inline script of HTML reports as a content security policy
+// source, so that the script can be allowed to run when serving a report without allowing any others
+func HTMLScriptHash() string {
+	sum := sha256.Sum256([]byte(htmlScript))
+
+	return "'sha256-" + base64.StdEncoding.EncodeToString(sum[:]) + "'"
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"vulnURL": func(id string) string { return OSVBaseVulnerabilityURL + id },
+}).Parse(htmlReportTemplate))
+
+type htmlGroup struct {
+	IDs      []string
+	Severity string
+	Rating   string
+	// Fixed describes the versions that fix the group, if they were reported for the package
+	Fixed string
+	// Fix is either "available" or "none" for filtering by whether the group has a fix,
+	// which is empty if the fixed versions were not reported for the package
+	Fix string
+}
+
+// SeverityClass is the class used to color the severity by its rating
+func (g htmlGroup) SeverityClass() string {
+	if g.Severity == "" {
+		return ""
+	}
+
+	return "severity-" + strings.ReplaceAll(strings.ToLower(g.Rating), " ", "-")
+}
+
+type htmlPackage struct {
+	Summary   string
+	Ecosystem string
+	// Licenses is a JSON array of the licenses of the package, for filtering by license
+	Licenses   string
+	Called     []htmlGroup
+	Uncalled   []htmlGroup
+	Suppressed []models.SuppressedVulnerability
+	// DependencyTree is the dependency paths of the package merged into a tree for each direct dependency
+	DependencyTree []htmlDependencyNode
+}
+
+// htmlDependencyNode is a package in the dependency paths of a vulnerable package, which is
+// the vulnerable package itself if it has no children
+type htmlDependencyNode struct {
+	Name     string
+	Children []htmlDependencyNode
+	// DeclaredIn is where a direct dependency is declared in the manifest, if that is known
+	DeclaredIn string
+}
+
+// htmlFilters are the options that the vulnerabilities in the report can be filtered by
+type htmlFilters struct {
+	Ratings    []string
+	Ecosystems []string
+	Licenses   []string
+	// Fixes is whether the vulnerabilities can be filtered by having a fix available,
+	// which is only the case if the fixed versions of some of them were reported
+	Fixes bool
+}
+
+type htmlSection struct {
+	Heading  string
+	Anchor   string
+	Packages []htmlPackage
+}
+
+// HTMLOptions customizes the branding of the HTML report
+type HTMLOptions struct {
+	// Title replaces "OSV-Scanner report" as the title and heading of the report
+	Title string
+	// Header is a blurb that is shown under the heading
+	Header string
+	// ScannedAt is when the scan ran, which is only shown if it is not zero
+	ScannedAt time.Time
+	// Rescan shows a button for re-running the scan, for reports that are being served, which also
+	// makes the report reload itself whenever the server says that it has been regenerated
+	Rescan bool
+}
+
+type htmlReport struct {
+	Title     string
+	Header    string
+	ScannedAt string
+	Rescan    bool
+	CSS       template.CSS
+	Script    template.JS
+	Summary   Summary
+	Filters   *htmlFilters
+	Sections  []htmlSection
+	Yanked    template.HTML
+	Licenses  template.HTML
+}
+
+// insertHTMLDependencyPath adds the path to the nodes, sharing the nodes of the paths that start the same way
+func insertHTMLDependencyPath(nodes []htmlDependencyNode, path []string) []htmlDependencyNode {
+	if len(path) == 0 {
+		return nodes
+	}
+
+	i := slices.IndexFunc(nodes, func(n htmlDependencyNode) bool { return n.Name == path[0] })
+	if i < 0 {
+		nodes = append(nodes, htmlDependencyNode{Name: path[0]})
+		i = len(nodes) - 1
+	}
+	nodes[i].Children = insertHTMLDependencyPath(nodes[i].Children, path[1:])
+
+	return nodes
+}
+
+// newHTMLDependencyTree merges the dependency paths of the package into a tree for each of the direct
+// dependencies that they start from, noting where each direct dependency is declared when that is known
+func newHTMLDependencyTree(pkg models.PackageVulns) []htmlDependencyNode {
+	var tree []htmlDependencyNode
+	for _, path := range pkg.DependencyPaths {
+		tree = insertHTMLDependencyPath(tree, path)
+	}
+
+	workingDir := mustGetWorkingDirectory()
+	for i := range tree {
+		for _, dep := range pkg.IntroducedBy {
+			if dep.Line == 0 || tree[i].Name != dep.Name+"@"+dep.Version {
+				continue
+			}
+
+			manifest := dep.Manifest
+			if rel, err := filepath.Rel(workingDir, manifest); err == nil { // Simplify the path if possible
+				manifest = rel
+			}
+			tree[i].DeclaredIn = fmt.Sprintf("%s:%d", manifest, dep.Line)
+		}
+	}
+
+	return tree
+}
+
+func newHTMLPackage(pkg models.PackageVulns) htmlPackage {
+	licenses := make([]string, 0, len(pkg.Licenses))
+	for _, license := range pkg.Licenses {
+		licenses = append(licenses, string(license))
+	}
+	licensesJSON, _ := json.Marshal(licenses)
+
+	result := htmlPackage{
+		Summary:    markdownPackageSummary(pkg),
+		Ecosystem:  pkg.Package.Ecosystem,
+		Licenses:   string(licensesJSON),
+		Suppressed: pkg.Suppressed,
+	}
+
+	result.DependencyTree = newHTMLDependencyTree(pkg)
+
+	showFixed := packageHasFixedVersions(pkg)
+	for _, group := range pkg.Groups {
+		g := htmlGroup{IDs: group.IDs, Severity: group.MaxSeverity, Rating: groupRating(group)}
+		if showFixed {
+			g.Fixed = FixedVersionsDescription(group)
+			g.Fix = "none"
+			if len(group.FixedVersions) > 0 {
+				g.Fix = "available"
+			}
+		}
+		if group.IsCalled() {
+			result.Called = append(result.Called, g)
+		} else {
+			result.Uncalled = append(result.Uncalled, g)
+		}
+	}
+
+	return result
+}
+
+// newHTMLFilters returns the ratings, ecosystems and licenses of the packages with vulnerabilities,
+// or nil if there are no vulnerabilities to filter
+func newHTMLFilters(vulnResult *models.VulnerabilityResults) *htmlFilters {
+	filters := &htmlFilters{}
+	for _, sourceRes := range vulnResult.Results {
+		for _, pkg := range sourceRes.Packages {
+			if len(pkg.Groups) == 0 {
+				continue
+			}
+
+			if pkg.Package.Ecosystem != "" && !slices.Contains(filters.Ecosystems, pkg.Package.Ecosystem) {
+				filters.Ecosystems = append(filters.Ecosystems, pkg.Package.Ecosystem)
+			}
+			for _, license := range pkg.Licenses {
+				if !slices.Contains(filters.Licenses, string(license)) {
+					filters.Licenses = append(filters.Licenses, string(license))
+				}
+			}
+			for _, group := range pkg.Groups {
+				if rating := groupRating(group); !slices.Contains(filters.Ratings, rating) {
+					filters.Ratings = append(filters.Ratings, rating)
+				}
+			}
+			filters.Fixes = filters.Fixes || packageHasFixedVersions(pkg)
+		}
+	}
+
+	if len(filters.Ratings) == 0 {
+		return nil
+	}
+
+	// the standard ratings are listed from most to least severe, before any from a custom severity mapping
+	slices.SortStableFunc(filters.Ratings, func(a, b string) int {
+		return ratingOrder(a) - ratingOrder(b)
+	})
+	slices.Sort(filters.Ecosystems)
+	slices.Sort(filters.Licenses)
+
+	return filters
+}
+
+// ratingOrder is the position of the rating in severityRatings, with other ratings coming after them
+func ratingOrder(rating string) int {
+	if i := slices.Index(severityRatings, rating); i >= 0 {
+		return i
+	}
+
+	return len(severityRatings)
+}
+
+// PrintHTMLReport prints the results as a single self-contained HTML page, laid out like
+// the markdown output, which does not reference any external stylesheets, scripts or fonts
+// so that it can be viewed in air-gapped environments.
+func PrintHTMLReport(vulnResult *models.VulnerabilityResults, outputWriter io.Writer) error {
+	return PrintHTMLReportWithOptions(vulnResult, outputWriter, HTMLOptions{})
+}
+
+// PrintHTMLReportWithOptions prints the results as an HTML report like PrintHTMLReport,
+// labelled with the title, header and scan time of the options
+func PrintHTMLReportWithOptions(vulnResult *models.VulnerabilityResults, outputWriter io.Writer, options HTMLOptions) error {
+	// make sure no section takes the anchors of the other headings
+	anchors := markdownAnchors{}
+	anchors.anchor("Contents")
+	anchors.anchor("Yanked or withdrawn")
+	anchors.anchor("Licenses")
+
+	report := htmlReport{
+		Title:   "OSV-Scanner report",
+		Header:  options.Header,
+		Rescan:  options.Rescan,
+		CSS:     template.CSS(htmlStyle), //nolint:gosec // the stylesheet is embedded, not user input
+		Script:  template.JS(htmlScript), //nolint:gosec // the script is embedded, not user input
+		Summary: NewSummary(vulnResult),
+	}
+	if options.Title != "" {
+		report.Title = options.Title
+	}
+	if !options.ScannedAt.IsZero() {
+		report.ScannedAt = options.ScannedAt.UTC().Format(time.RFC3339)
+	}
+
+	for _, section := range markdownSections(vulnResult, anchors) {
+		s := htmlSection{Heading: section.heading, Anchor: section.anchor}
+		for _, pkg := range section.packages {
+			s.Packages = append(s.Packages, newHTMLPackage(pkg))
+		}
+		report.Sections = append(report.Sections, s)
+	}
+	report.Filters = newHTMLFilters(vulnResult)
+
+	yankedTable := yankedTableBuilder(table.NewWriter(), vulnResult)
+	if yankedTable.Length() > 0 {
+		report.Yanked = template.HTML(yankedTable.RenderHTML()) //nolint:gosec
+	}
+
+	licenseTable := licenseTableBuilder(table.NewWriter(), vulnResult)
+	if licenseTable.Length() > 0 {
+		// go-pretty escapes the contents of the cells when rendering html
+		report.Licenses = template.HTML(licenseTable.RenderHTML()) //nolint:gosec
+	}
+
+	if err := htmlTemplate.Execute(outputWriter, report); err != nil {
+		return fmt.Errorf("failed to write html report: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+"use strict";
+
+(function () {
+  const filters = document.querySelector(".filters");
+  if (!filters) {
+    return;
+  }
+
+  // packages are the collapsible block of each package, which have its ecosystem and licenses
+  const packages = Array.from(document.querySelectorAll("details[data-ecosystem]"));
+  const status = filters.querySelector(".filter-status");
+
+  function selected(name) {
+    const select = filters.querySelector(`select[name="${name}"]`);
+
+    return select ? select.value : "";
+  }
+
+  function matches(pkg, row) {
+    const rating = selected("rating");
+    const ecosystem = selected("ecosystem");
+    const fix = selected("fix");
+    const license = selected("license");
+
+    return (
+      (rating === "" || row.dataset.rating === rating) &&
+      (ecosystem === "" || pkg.dataset.ecosystem === ecosystem) &&
+      (fix === "" || row.dataset.fix === fix) &&
+      (license === "" || JSON.parse(pkg.dataset.licenses).includes(license))
+    );
+  }
+
+  function applyFilters() {
+    const filtered = Array.from(filters.querySelectorAll("select")).some((select) => select.value !== "");
+    let total = 0;
+    let shown = 0;
+
+    for (const pkg of packages) {
+      let pkgShown = 0;
+      for (const groups of pkg.querySelectorAll(".groups")) {
+        let groupsShown = 0;
+        for (const row of groups.querySelectorAll("tbody tr")) {
+          row.hidden = !matches(pkg, row);
+          total++;
+          if (!row.hidden) {
+            groupsShown++;
+          }
+        }
+        groups.hidden = groupsShown === 0;
+        pkgShown += groupsShown;
+      }
+      shown += pkgShown;
+      pkg.hidden = filtered && pkgShown === 0;
+    }
+
+    // hide the sections, and their entries in the contents, that have no packages left
+    for (const section of document.querySelectorAll("section[data-section]")) {
+      const heading = section.querySelector("h2");
+      section.hidden = Array.from(section.querySelectorAll("details[data-ecosystem]")).every((pkg) => pkg.hidden);
+
+      const entry = document.querySelector(`a[href="#${CSS.escape(heading.id)}"]`);
+      if (entry) {
+        entry.parentElement.hidden = section.hidden;
+      }
+    }
+
+    status.textContent = filtered ? `Showing ${shown} of ${total} vulnerabilities` : "";
+  }
+
+  // sortValue is the number in the cell if it has one so that scores sort numerically, or else its text
+  function sortValue(row, column) {
+    const text = row.cells[column].textContent.trim();
+    const number = Number.parseFloat(text);
+
+    return Number.isNaN(number) ? text : number;
+  }
+
+  function compare(a, b) {
+    if (typeof a === typeof b) {
+      return a < b ? -1 : a > b ? 1 : 0;
+    }
+
+    // cells without a number, such as unscored vulnerabilities, sort before those with one
+    return typeof a === "number" ? 1 : -1;
+  }
+
+  function sortTable(button) {
+    const th = button.closest("th");
+    const table = th.closest("table");
+    const column = th.cellIndex;
+    const ascending = th.getAttribute("aria-sort") !== "ascending";
+
+    for (const other of table.querySelectorAll("th")) {
+      other.removeAttribute("aria-sort");
+    }
+    th.setAttribute("aria-sort", ascending ? "ascending" : "descending");
+
+    const tbody = table.tBodies[0];
+    const rows = Array.from(tbody.rows);
+    rows.sort((a, b) => compare(sortValue(a, column), sortValue(b, column)) * (ascending ? 1 : -1));
+    tbody.append(...rows);
+  }
+
+  filters.addEventListener("change", applyFilters);
+  filters.addEventListener("reset", () => setTimeout(applyFilters));
+  document.addEventListener("click", (event) => {
+    const button = event.target.closest("th button");
+    if (button) {
+      sortTable(button);
+    }
+  });
+
+  // the filters and sorting only work with scripts enabled, so they are only added now
+  filters.hidden = false;
+  for (const th of document.querySelectorAll("table.sortable th")) {
+    const button = document.createElement("button");
+    button.type = "button";
+    button.append(...th.childNodes);
+    th.append(button);
+  }
+})();
+
+(function () {
+  // the rescan button is only in reports that are being served
+  const rescan = document.querySelector("form.rescan");
+  if (!rescan) {
+    return;
+  }
+
+  const button = rescan.querySelector("button");
+  rescan.addEventListener("submit", async (event) => {
+    event.preventDefault();
+    button.disabled = true;
+    button.textContent = "Rescanning...";
+
+    try {
+      const response = await fetch(rescan.action, { method: "POST" });
+      if (!response.ok) {
+        throw new Error(await response.text());
+      }
+    } catch (error) {
+      button.disabled = false;
+      button.textContent = "Rescan";
+      button.title = `The last rescan failed: ${error.message}`;
+    }
+  });
+
+  // the server sends an event whenever the report is regenerated, however the rescan was started
+  const events = new EventSource("/events");
+  events.addEventListener("report", () => window.location.reload());
+})();
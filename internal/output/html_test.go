@@ -0,0 +1,216 @@
+package output_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/internal/testutility"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func TestPrintHTMLReport(t *testing.T) {
+	t.Parallel()
+
+	vulnResult := &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "path/to/package-lock.json", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package:         models.PackageInfo{Name: "ansi-html", Version: "0.0.1", Ecosystem: "npm"},
+						Vulnerabilities: []models.Vulnerability{{ID: "GHSA-whgm-jr23-g3j9"}},
+						Groups:          []models.GroupInfo{{IDs: []string{"GHSA-whgm-jr23-g3j9"}, MaxSeverity: "7.5"}},
+					},
+					{
+						Package:   models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"},
+						DepGroups: []string{"dev"},
+						Vulnerabilities: []models.Vulnerability{
+							{ID: "GHSA-29mw-wpgm-hmr9"},
+							{ID: "GHSA-35jh-r3h4-6jhm"},
+							{ID: "CVE-2021-23337"},
+						},
+						Groups: []models.GroupInfo{
+							{IDs: []string{"GHSA-29mw-wpgm-hmr9"}, MaxSeverity: "5.3"},
+							{IDs: []string{"CVE-2021-23337", "GHSA-35jh-r3h4-6jhm"}, MaxSeverity: "7.2"},
+						},
+						Suppressed: []models.SuppressedVulnerability{{ID: "GHSA-p6mc-m468-83gw", Reason: "<b>not</b> reachable"}},
+						DependencyPaths: [][]string{
+							{"lodash@4.17.20"},
+							{"webpack@5.0.0", "lodash@4.17.20"},
+							{"webpack@5.0.0", "terser-webpack-plugin@5.0.0", "lodash@4.17.20"},
+						},
+						IntroducedBy: []models.DirectDependency{
+							{Name: "lodash", Version: "4.17.20", Manifest: "path/to/package.json", Line: 8},
+							{Name: "webpack", Version: "5.0.0", Manifest: "path/to/package.json", Line: 12},
+						},
+					},
+				},
+			},
+			{
+				Source: models.SourceInfo{Path: "path/to/go.mod", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package:         models.PackageInfo{Name: "stdlib", Version: "1.21.7", Ecosystem: "Go"},
+						Vulnerabilities: []models.Vulnerability{{ID: "GO-2024-2598"}},
+						Groups: []models.GroupInfo{
+							{
+								IDs:                  []string{"GO-2024-2598"},
+								ExperimentalAnalysis: map[string]models.AnalysisInfo{"GO-2024-2598": {Called: false}},
+							},
+						},
+					},
+				},
+			},
+			{
+				Source: models.SourceInfo{Path: "other/path/to/go.mod", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{Package: models.PackageInfo{Name: "github.com/google/uuid", Version: "1.6.0", Ecosystem: "Go"}},
+				},
+			},
+		},
+	}
+
+	bufOut := bytes.Buffer{}
+	if err := output.PrintHTMLReport(vulnResult, &bufOut); err != nil {
+		t.Fatalf("Error writing HTML output: %v", err)
+	}
+
+	// the report must render without a network connection, so its script is inlined
+	for _, external := range []string{"<link", "<script src", "src=", "@import", "url("} {
+		if strings.Contains(bufOut.String(), external) {
+			t.Errorf("HTML report should be self-contained, but contains %q", external)
+		}
+	}
+
+	testutility.NewSnapshot().MatchText(t, bufOut.String())
+}
+
+func TestPrintHTMLReportWithOptions(t *testing.T) {
+	t.Parallel()
+
+	vulnResult := &models.VulnerabilityResults{Results: []models.PackageSource{}}
+
+	bufOut := bytes.Buffer{}
+	err := output.PrintHTMLReportWithOptions(vulnResult, &bufOut, output.HTMLOptions{
+		Title:     "Payments <team>",
+		Header:    "Nightly scan of the payments monorepo",
+		ScannedAt: time.Date(2024, 3, 2, 10, 30, 0, 0, time.FixedZone("", 60*60)),
+		Rescan:    true,
+	})
+	if err != nil {
+		t.Fatalf("Error writing HTML output: %v", err)
+	}
+
+	for _, want := range []string{
+		"<title>Payments &lt;team&gt;</title>",
+		"<h1>Payments &lt;team&gt;</h1>",
+		`<p class="header">Nightly scan of the payments monorepo</p>`,
+		`<p class="scanned-at">Scanned at <time datetime="2024-03-02T09:30:00Z">2024-03-02T09:30:00Z</time></p>`,
+		`<form class="rescan" method="post" action="/rescan"><button type="submit">Rescan</button></form>`,
+	} {
+		if !strings.Contains(bufOut.String(), want) {
+			t.Errorf("HTML report should contain %q, but got:\n%s", want, bufOut.String())
+		}
+	}
+}
+
+func TestPrintHTMLReport_Filters(t *testing.T) {
+	t.Parallel()
+
+	vulnResult := &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "path/to/package-lock.json", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package:         models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"},
+						Licenses:        []models.License{"MIT"},
+						Vulnerabilities: []models.Vulnerability{{ID: "GHSA-29mw-wpgm-hmr9"}, {ID: "GHSA-35jh-r3h4-6jhm"}},
+						Groups: []models.GroupInfo{
+							{IDs: []string{"GHSA-29mw-wpgm-hmr9"}, MaxSeverity: "5.3", FixedVersions: []string{"4.17.21"}},
+							{IDs: []string{"GHSA-35jh-r3h4-6jhm"}, MaxSeverity: "9.8", FixedVersions: []string{}},
+						},
+					},
+				},
+			},
+			{
+				Source: models.SourceInfo{Path: "path/to/go.mod", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package:         models.PackageInfo{Name: "stdlib", Version: "1.21.7", Ecosystem: "Go"},
+						Licenses:        []models.License{"BSD-3-Clause"},
+						Vulnerabilities: []models.Vulnerability{{ID: "GO-2024-2598"}},
+						Groups:          []models.GroupInfo{{IDs: []string{"GO-2024-2598"}}},
+					},
+				},
+			},
+		},
+	}
+
+	bufOut := bytes.Buffer{}
+	if err := output.PrintHTMLReport(vulnResult, &bufOut); err != nil {
+		t.Fatalf("Error writing HTML output: %v", err)
+	}
+
+	for _, want := range []string{
+		`<select name="rating"><option value="">All</option><option>Critical</option><option>Medium</option><option>Unknown</option></select>`,
+		`<select name="ecosystem"><option value="">All</option><option>Go</option><option>npm</option></select>`,
+		`<select name="license"><option value="">All</option><option>BSD-3-Clause</option><option>MIT</option></select>`,
+		`<select name="fix">`,
+		`<details open data-ecosystem="npm" data-licenses="[&#34;MIT&#34;]">`,
+		`<tr data-rating="Medium" data-fix="available">`,
+		`<tr data-rating="Critical" data-fix="none">`,
+		`<tr data-rating="Unknown">`,
+	} {
+		if !strings.Contains(bufOut.String(), want) {
+			t.Errorf("HTML report should contain %q, but got:\n%s", want, bufOut.String())
+		}
+	}
+}
+
+func TestPrintHTMLReport_NoFilters(t *testing.T) {
+	t.Parallel()
+
+	vulnResult := &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "path/to/go.mod", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{Package: models.PackageInfo{Name: "github.com/google/uuid", Version: "1.6.0", Ecosystem: "Go"}},
+				},
+			},
+		},
+	}
+
+	bufOut := bytes.Buffer{}
+	if err := output.PrintHTMLReport(vulnResult, &bufOut); err != nil {
+		t.Fatalf("Error writing HTML output: %v", err)
+	}
+
+	if strings.Contains(bufOut.String(), `<form class="filters"`) {
+		t.Errorf("HTML report should not have filters when there are no vulnerabilities, but got:\n%s", bufOut.String())
+	}
+}
+
+func TestHTMLScriptHash(t *testing.T) {
+	t.Parallel()
+
+	bufOut := bytes.Buffer{}
+	if err := output.PrintHTMLReport(&models.VulnerabilityResults{}, &bufOut); err != nil {
+		t.Fatalf("Error writing HTML output: %v", err)
+	}
+
+	_, script, _ := strings.Cut(bufOut.String(), "<script>")
+	script, _, _ = strings.Cut(script, "</script>")
+	sum := sha256.Sum256([]byte(script))
+
+	// the hash has to be of exactly what is between the tags, or browsers will refuse to run the script
+	if want := "'sha256-" + base64.StdEncoding.EncodeToString(sum[:]) + "'"; output.HTMLScriptHash() != want {
+		t.Errorf("HTMLScriptHash() = %s, want %s", output.HTMLScriptHash(), want)
+	}
+}
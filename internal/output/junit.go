@@ -0,0 +1,163 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/utility/results"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+type junitTestSuites struct {
+	XMLName    xml.Name         `xml:"testsuites"`
+	Name       string           `xml:"name,attr"`
+	Tests      int              `xml:"tests,attr"`
+	Failures   int              `xml:"failures,attr"`
+	TestSuites []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string         `xml:"name,attr"`
+	ClassName string         `xml:"classname,attr,omitempty"`
+	Failures  []junitFailure `xml:"failure"`
+	SystemOut string         `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// junitVulnFailure describes the vulnerabilities of the group as a failure,
+// using the summary of the first of them that has one
+func junitVulnFailure(pkg models.PackageVulns, group models.GroupInfo) junitFailure {
+	var summary string
+	for _, vuln := range pkg.Vulnerabilities {
+		if vuln.Summary != "" && slices.Contains(group.IDs, vuln.ID) {
+			summary = vuln.Summary
+			break
+		}
+	}
+
+	message := strings.Join(group.IDs, ", ")
+	if summary != "" {
+		message += ": " + summary
+	}
+
+	var text strings.Builder
+	if group.MaxSeverity != "" {
+		fmt.Fprintf(&text, "Severity: %s\n", group.MaxSeverity)
+	}
+	for _, id := range group.IDs {
+		fmt.Fprintln(&text, OSVBaseVulnerabilityURL+id)
+	}
+
+	return junitFailure{
+		Message: message,
+		Type:    "vulnerability",
+		Text:    text.String(),
+	}
+}
+
+// junitTestCaseForPackage creates a test case for the package, which fails if the
+// package has any called vulnerabilities or license violations
+func junitTestCaseForPackage(pkg models.PackageVulns) junitTestCase {
+	testCase := junitTestCase{
+		Name:      results.PkgToString(pkg.Package),
+		ClassName: pkg.Package.Ecosystem,
+	}
+
+	var systemOut []string
+	for _, group := range pkg.Groups {
+		if !group.IsCalled() {
+			systemOut = append(systemOut, "Uncalled vulnerability: "+strings.Join(group.IDs, ", "))
+			continue
+		}
+
+		testCase.Failures = append(testCase.Failures, junitVulnFailure(pkg, group))
+	}
+
+	for _, license := range pkg.LicenseViolations {
+		testCase.Failures = append(testCase.Failures, junitFailure{
+			Message: "License violation: " + string(license),
+			Type:    "license violation",
+		})
+	}
+
+	for _, suppressed := range pkg.Suppressed {
+		line := "Suppressed vulnerability: " + suppressed.ID
+		if suppressed.Reason != "" {
+			line += " (" + suppressed.Reason + ")"
+		}
+		systemOut = append(systemOut, line)
+	}
+
+	if pkg.Yanked != nil {
+		line := "Yanked version"
+		if pkg.Yanked.Reason != "" {
+			line += " (" + pkg.Yanked.Reason + ")"
+		}
+		systemOut = append(systemOut, line)
+	}
+
+	for _, id := range pkg.Withdrawn {
+		systemOut = append(systemOut, "Withdrawn vulnerability: "+id)
+	}
+
+	if len(systemOut) > 0 {
+		testCase.SystemOut = strings.Join(systemOut, "\n")
+	}
+
+	return testCase
+}
+
+// PrintJUnitReport prints the results as a JUnit XML report to outputWriter, with a test suite
+// for each source in which each package is a test case that fails for each of its vulnerabilities.
+//
+// Packages without any vulnerabilities are only included as passing test cases if they are in
+// the results, which is only the case if all packages were requested.
+func PrintJUnitReport(vulnResult *models.VulnerabilityResults, outputWriter io.Writer) error {
+	report := junitTestSuites{Name: "osv-scanner"}
+
+	for _, source := range vulnResult.Results {
+		suite := junitTestSuite{Name: source.Source.String()}
+
+		for _, pkg := range source.Packages {
+			testCase := junitTestCaseForPackage(pkg)
+
+			suite.Tests++
+			if len(testCase.Failures) > 0 {
+				suite.Failures++
+			}
+			suite.TestCases = append(suite.TestCases, testCase)
+		}
+
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.TestSuites = append(report.TestSuites, suite)
+	}
+
+	fmt.Fprint(outputWriter, xml.Header)
+
+	encoder := xml.NewEncoder(outputWriter)
+	encoder.Indent("", "  ")
+
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("failed to write junit report: %w", err)
+	}
+
+	fmt.Fprintln(outputWriter)
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package output_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/internal/testutility"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func TestPrintJUnitReport(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		args models.VulnerabilityResults
+		want testutility.Snapshot
+	}{
+		{
+			name: "vulnerabilities",
+			args: testutility.LoadJSONFixtureWithWindowsReplacements[models.VulnerabilityResults](t,
+				"fixtures/test-vuln-results-a.json",
+				map[string]string{
+					"/path/to/sub-rust-project/Cargo.lock": "D:\\\\path\\\\to\\\\sub-rust-project\\\\Cargo.lock",
+					"/path/to/go.mod":                      "D:\\\\path\\\\to\\\\go.mod",
+				},
+			),
+			want: testutility.NewSnapshot().WithWindowsReplacements(
+				map[string]string{
+					"lockfile:D:\\\\path\\\\to\\\\sub-rust-project\\\\Cargo.lock": "lockfile:/path/to/sub-rust-project/Cargo.lock",
+					"lockfile:D:\\\\path\\\\to\\\\go.mod":                         "lockfile:/path/to/go.mod",
+				},
+			),
+		},
+		{
+			name: "passing, uncalled, suppressed and license violations",
+			args: models.VulnerabilityResults{
+				Results: []models.PackageSource{
+					{
+						Source: models.SourceInfo{Path: "path/to/package-lock.json", Type: "lockfile"},
+						Packages: []models.PackageVulns{
+							{
+								Package:           models.PackageInfo{Name: "left-pad", Version: "1.0.0", Ecosystem: "npm"},
+								LicenseViolations: []models.License{"WTFPL"},
+							},
+							{
+								Package:    models.PackageInfo{Name: "lodash", Version: "4.17.21", Ecosystem: "npm"},
+								Suppressed: []models.SuppressedVulnerability{{ID: "GHSA-p6mc-m468-83gw", Reason: "not reachable"}},
+							},
+							{
+								Package: models.PackageInfo{Name: "react", Version: "18.2.0", Ecosystem: "npm"},
+							},
+						},
+					},
+					{
+						Source: models.SourceInfo{Path: "path/to/go.mod", Type: "lockfile"},
+						Packages: []models.PackageVulns{
+							{
+								Package:         models.PackageInfo{Name: "stdlib", Version: "1.21.7", Ecosystem: "Go"},
+								Vulnerabilities: []models.Vulnerability{{ID: "GO-2024-2598", Summary: "Verify panics on certificates with an unknown public key algorithm in crypto/x509"}},
+								Groups: []models.GroupInfo{
+									{
+										IDs:                  []string{"GO-2024-2598"},
+										ExperimentalAnalysis: map[string]models.AnalysisInfo{"GO-2024-2598": {Called: false}},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: testutility.NewSnapshot(),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			bufOut := bytes.Buffer{}
+			err := output.PrintJUnitReport(&tt.args, &bufOut)
+			if err != nil {
+				t.Errorf("Error writing JUnit output: %s", err)
+			}
+			tt.want.MatchText(t, bufOut.String())
+		})
+	}
+}
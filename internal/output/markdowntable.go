@@ -1,21 +1,255 @@
 package output
 
 import (
+	"fmt"
 	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
 
+	"github.com/google/osv-scanner/internal/utility/results"
+	"github.com/google/osv-scanner/pkg/lockfile"
 	"github.com/google/osv-scanner/pkg/models"
 
 	"github.com/jedib0t/go-pretty/v6/table"
 )
 
-// PrintTableResults prints the osv scan results into a human friendly table.
+// markdownAnchors generates anchors for headings in the same way as GitHub,
+// so that the table of contents can link to them
+type markdownAnchors map[string]int
+
+// anchor returns the anchor for the heading, which has a number appended to it
+// if another heading has already been given the same anchor
+func (a markdownAnchors) anchor(heading string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(heading) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			sb.WriteRune(r)
+		case r == ' ':
+			sb.WriteRune('-')
+		}
+	}
+
+	anchor := sb.String()
+	count := a[anchor]
+	a[anchor]++
+
+	if count > 0 {
+		anchor += "-" + strconv.Itoa(count)
+	}
+
+	return anchor
+}
+
+type markdownSection struct {
+	heading  string
+	anchor   string
+	packages []models.PackageVulns
+}
+
+// markdownSections returns a section for each source with at least
+// one package that has vulnerabilities, including suppressed ones
+func markdownSections(vulnResult *models.VulnerabilityResults, anchors markdownAnchors) []markdownSection {
+	workingDir := mustGetWorkingDirectory()
+
+	var sections []markdownSection
+	for _, sourceRes := range vulnResult.Results {
+		var packages []models.PackageVulns
+		for _, pkg := range sourceRes.Packages {
+			if len(pkg.Groups) > 0 || len(pkg.Suppressed) > 0 {
+				packages = append(packages, pkg)
+			}
+		}
+
+		if len(packages) == 0 {
+			continue
+		}
+
+		heading := sourceRes.Source.Path
+		if sourcePath, err := filepath.Rel(workingDir, heading); err == nil { // Simplify the path if possible
+			heading = sourcePath
+		}
+		if sourceRes.Source.Ref != "" {
+			heading += "@" + sourceRes.Source.Ref
+		}
+		if sourceRes.Source.Type == "manifest" {
+			heading += " (resolved from manifest, not locked)"
+		}
+
+		sections = append(sections, markdownSection{
+			heading:  heading,
+			anchor:   anchors.anchor(heading),
+			packages: packages,
+		})
+	}
+
+	return sections
+}
+
+// markdownSectionSummary describes how many packages in the section have vulnerabilities and how many
+// vulnerabilities they have in total, for use as the summary of the collapsible block containing them
+func markdownSectionSummary(section markdownSection) string {
+	vulnCount := 0
+	for _, pkg := range section.packages {
+		vulnCount += len(pkg.Groups)
+	}
+
+	return fmt.Sprintf(
+		"%d %s with %d known %s",
+		len(section.packages),
+		Form(len(section.packages), "package", "packages"),
+		vulnCount,
+		Form(vulnCount, "vulnerability", "vulnerabilities"),
+	)
+}
+
+// markdownPackageSummary describes the package and how many vulnerabilities it has,
+// for use as the summary of the collapsible block containing them
+func markdownPackageSummary(pkg models.PackageVulns) string {
+	var name string
+	if pkg.Package.Ecosystem == "" && pkg.Package.Commit != "" {
+		name = results.PkgToString(pkg.Package)
+	} else {
+		name = fmt.Sprintf("%s %s (%s)", pkg.Package.Name, pkg.Package.Version, pkg.Package.Ecosystem)
+		if lockfile.Ecosystem(pkg.Package.Ecosystem).IsDevGroup(pkg.DepGroups) {
+			name += " (dev)"
+		}
+	}
+
+	summary := fmt.Sprintf("%s: %d known %s", name, len(pkg.Groups), Form(len(pkg.Groups), "vulnerability", "vulnerabilities"))
+	if len(pkg.Suppressed) > 0 {
+		summary += fmt.Sprintf(", %d suppressed", len(pkg.Suppressed))
+	}
+
+	return summary
+}
+
+// printMarkdownPackageVulns prints the vulnerabilities of the package as tables, with
+// uncalled and suppressed vulnerabilities listed separately like in the table output
+func printMarkdownPackageVulns(pkg models.PackageVulns, outputWriter io.Writer) {
+	showFixed := packageHasFixedVersions(pkg)
+	groupsTable := func(calledVulns bool) table.Writer {
+		outputTable := table.NewWriter()
+		outputTable.SetOutputMirror(outputWriter)
+		if showFixed {
+			outputTable.AppendHeader(table.Row{"OSV URL", "CVSS", "Fixed version"})
+		} else {
+			outputTable.AppendHeader(table.Row{"OSV URL", "CVSS"})
+		}
+
+		for _, group := range pkg.Groups {
+			if group.IsCalled() != calledVulns {
+				continue
+			}
+
+			links := make([]string, 0, len(group.IDs))
+			for _, vuln := range group.IDs {
+				links = append(links, OSVBaseVulnerabilityURL+vuln)
+			}
+
+			row := table.Row{strings.Join(links, "\n"), group.MaxSeverity}
+			if showFixed {
+				row = append(row, FixedVersionsDescription(group))
+			}
+			outputTable.AppendRow(row)
+		}
+
+		return outputTable
+	}
+
+	if calledTable := groupsTable(true); calledTable.Length() != 0 {
+		fmt.Fprintln(outputWriter)
+		calledTable.RenderMarkdown()
+	}
+
+	if uncalledTable := groupsTable(false); uncalledTable.Length() != 0 {
+		fmt.Fprintf(outputWriter, "\nUncalled vulnerabilities:\n\n")
+		uncalledTable.RenderMarkdown()
+	}
+
+	if len(pkg.Suppressed) > 0 {
+		suppressedTable := table.NewWriter()
+		suppressedTable.SetOutputMirror(outputWriter)
+		suppressedTable.AppendHeader(table.Row{"OSV URL", "Reason"})
+
+		for _, suppressed := range pkg.Suppressed {
+			suppressedTable.AppendRow(table.Row{OSVBaseVulnerabilityURL + suppressed.ID, suppressed.Reason})
+		}
+
+		fmt.Fprintf(outputWriter, "\nSuppressed vulnerabilities:\n\n")
+		suppressedTable.RenderMarkdown()
+	}
+
+	if len(pkg.DependencyPaths) > 0 {
+		fmt.Fprintf(outputWriter, "\nDependency paths:\n\n")
+		for _, path := range pkg.DependencyPaths {
+			fmt.Fprintf(outputWriter, "- %s\n", DependencyPathBreadcrumb(path))
+		}
+	}
+}
+
+// PrintMarkdownTableResults prints the osv scan results as markdown, starting with a summary and
+// a table of contents that links to a section for each source, in which the packages of the source
+// and the vulnerabilities of each package are collapsible so that long reports are manageable in
+// places like pull requests.
 func PrintMarkdownTableResults(vulnResult *models.VulnerabilityResults, outputWriter io.Writer) {
-	outputTable := table.NewWriter()
-	outputTable.SetOutputMirror(outputWriter)
-	outputTable = tableBuilder(outputTable, vulnResult, false)
+	PrintMarkdownTableResultsWithOptions(vulnResult, outputWriter, TableOptions{})
+}
+
+// PrintMarkdownTableResultsWithOptions prints the results like PrintMarkdownTableResults, presented
+// as per the options, with deduplicated advisories being listed in a single table instead of by source
+func PrintMarkdownTableResultsWithOptions(vulnResult *models.VulnerabilityResults, outputWriter io.Writer, options TableOptions) {
+	fmt.Fprintln(outputWriter, NewSummary(vulnResult))
+
+	if options.DedupeAdvisories {
+		printMarkdownAdvisories(vulnResult, outputWriter)
+		printMarkdownFooter(vulnResult, outputWriter)
+
+		return
+	}
+
+	// the table of contents is a heading too, so make sure no section takes its anchor
+	anchors := markdownAnchors{}
+	anchors.anchor("Contents")
+	anchors.anchor("Yanked or withdrawn")
+	sections := markdownSections(vulnResult, anchors)
+
+	if len(sections) > 0 {
+		fmt.Fprintf(outputWriter, "\n## Contents\n\n")
+		for _, section := range sections {
+			fmt.Fprintf(outputWriter, "- [%s](#%s)\n", section.heading, section.anchor)
+		}
+	}
+
+	for _, section := range sections {
+		// the heading is kept outside of the collapsible block, so that the table of contents can link to it
+		fmt.Fprintf(outputWriter, "\n## %s\n", section.heading)
+		fmt.Fprintf(outputWriter, "\n<details>\n<summary>%s</summary>\n", markdownSectionSummary(section))
+
+		for _, pkg := range section.packages {
+			fmt.Fprintf(outputWriter, "\n<details>\n<summary>%s</summary>\n", markdownPackageSummary(pkg))
+			printMarkdownPackageVulns(pkg, outputWriter)
+			fmt.Fprintf(outputWriter, "\n</details>\n")
+		}
+
+		fmt.Fprintf(outputWriter, "\n</details>\n")
+	}
+
+	printMarkdownFooter(vulnResult, outputWriter)
+}
+
+// printMarkdownFooter prints the yanked and withdrawn packages and the licenses below the vulnerabilities
+func printMarkdownFooter(vulnResult *models.VulnerabilityResults, outputWriter io.Writer) {
+	outputYankedTable := table.NewWriter()
+	outputYankedTable.SetOutputMirror(outputWriter)
+
+	outputYankedTable = yankedTableBuilder(outputYankedTable, vulnResult)
 
-	if outputTable.Length() != 0 {
-		outputTable.RenderMarkdown()
+	if outputYankedTable.Length() != 0 {
+		fmt.Fprintf(outputWriter, "\n## Yanked or withdrawn\n\n")
+		outputYankedTable.RenderMarkdown()
 	}
 
 	outputLicenseTable := table.NewWriter()
@@ -26,5 +260,6 @@ func PrintMarkdownTableResults(vulnResult *models.VulnerabilityResults, outputWr
 	if outputLicenseTable.Length() == 0 {
 		return
 	}
+	fmt.Fprintln(outputWriter)
 	outputLicenseTable.RenderMarkdown()
 }
@@ -0,0 +1,96 @@
+package output_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/internal/testutility"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func TestPrintMarkdownTableResults(t *testing.T) {
+	t.Parallel()
+
+	vulnResult := &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "path/to/package-lock.json", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package:         models.PackageInfo{Name: "ansi-html", Version: "0.0.1", Ecosystem: "npm"},
+						Vulnerabilities: []models.Vulnerability{{ID: "GHSA-whgm-jr23-g3j9"}},
+						Groups:          []models.GroupInfo{{IDs: []string{"GHSA-whgm-jr23-g3j9"}, MaxSeverity: "7.5"}},
+					},
+					{
+						Package:   models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"},
+						DepGroups: []string{"dev"},
+						Vulnerabilities: []models.Vulnerability{
+							{ID: "GHSA-29mw-wpgm-hmr9"},
+							{ID: "GHSA-35jh-r3h4-6jhm"},
+							{ID: "CVE-2021-23337"},
+						},
+						Groups: []models.GroupInfo{
+							{IDs: []string{"GHSA-29mw-wpgm-hmr9"}, MaxSeverity: "5.3"},
+							{IDs: []string{"CVE-2021-23337", "GHSA-35jh-r3h4-6jhm"}, MaxSeverity: "7.2"},
+						},
+						Suppressed: []models.SuppressedVulnerability{{ID: "GHSA-p6mc-m468-83gw", Reason: "not reachable"}},
+						DependencyPaths: [][]string{
+							{"lodash@4.17.20"},
+							{"webpack@5.0.0", "lodash@4.17.20"},
+						},
+					},
+				},
+			},
+			{
+				Source: models.SourceInfo{Path: "path/to/go.mod", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package:         models.PackageInfo{Name: "stdlib", Version: "1.21.7", Ecosystem: "Go"},
+						Vulnerabilities: []models.Vulnerability{{ID: "GO-2024-2598"}},
+						Groups: []models.GroupInfo{
+							{
+								IDs:                  []string{"GO-2024-2598"},
+								ExperimentalAnalysis: map[string]models.AnalysisInfo{"GO-2024-2598": {Called: false}},
+							},
+						},
+					},
+				},
+			},
+			{
+				Source: models.SourceInfo{Path: "other/path/to/go.mod", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{Package: models.PackageInfo{Name: "github.com/google/uuid", Version: "1.6.0", Ecosystem: "Go"}},
+				},
+			},
+			{
+				Source: models.SourceInfo{Path: "path/to/Cargo.lock", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package: models.PackageInfo{Name: "smallvec", Version: "1.6.0", Ecosystem: "crates.io"},
+						Yanked:  &models.YankedVersion{Reason: "unsound"},
+					},
+					{
+						Package:   models.PackageInfo{Name: "time", Version: "0.1.43", Ecosystem: "crates.io"},
+						Withdrawn: []string{"RUSTSEC-2020-0071"},
+					},
+				},
+			},
+			{
+				Source: models.SourceInfo{Path: "path/to/package.json", Type: "manifest"},
+				Packages: []models.PackageVulns{
+					{
+						Package:         models.PackageInfo{Name: "ansi-html", Version: "0.0.1", Ecosystem: "npm"},
+						Vulnerabilities: []models.Vulnerability{{ID: "GHSA-whgm-jr23-g3j9"}},
+						Groups:          []models.GroupInfo{{IDs: []string{"GHSA-whgm-jr23-g3j9"}, MaxSeverity: "7.5"}},
+					},
+				},
+			},
+		},
+	}
+
+	bufOut := bytes.Buffer{}
+	output.PrintMarkdownTableResults(vulnResult, &bufOut)
+
+	testutility.NewSnapshot().MatchText(t, bufOut.String())
+}
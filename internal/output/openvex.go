@@ -0,0 +1,104 @@
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/google/osv-scanner/internal/version"
+	"github.com/google/osv-scanner/internal/vex"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// openVEXContext is the version of OpenVEX that documents are written in
+const openVEXContext = "https://openvex.dev/ns/v0.2.0"
+
+type openVEXDocument struct {
+	Context    string          `json:"@context"`
+	ID         string          `json:"@id"`
+	Author     string          `json:"author"`
+	Timestamp  string          `json:"timestamp"`
+	Version    int             `json:"version"`
+	Tooling    string          `json:"tooling"`
+	Statements []vex.Statement `json:"statements"`
+}
+
+// openVEXStatements returns a statement for each vulnerability in the results that is under investigation
+// for every package it affects, which is named after the first ID of its group with the others as aliases
+func openVEXStatements(vulnResult *models.VulnerabilityResults) []vex.Statement {
+	statements := []vex.Statement{}
+	for _, sourceRes := range vulnResult.Results {
+		for _, pkg := range sourceRes.Packages {
+			purl, ok := models.PackageToPURL(pkg.Package)
+			if !ok {
+				continue
+			}
+
+			for _, group := range pkg.Groups {
+				if len(group.IDs) == 0 {
+					continue
+				}
+
+				i := slices.IndexFunc(statements, func(s vex.Statement) bool { return s.Vulnerability.Name == group.IDs[0] })
+				if i < 0 {
+					var aliases []string
+					for _, alias := range append(slices.Clone(group.IDs[1:]), group.Aliases...) {
+						if alias != group.IDs[0] && !slices.Contains(aliases, alias) {
+							aliases = append(aliases, alias)
+						}
+					}
+
+					statements = append(statements, vex.Statement{
+						Vulnerability: vex.Vulnerability{Name: group.IDs[0], Aliases: aliases},
+						Status:        vex.StatusUnderInvestigation,
+					})
+					i = len(statements) - 1
+				}
+
+				if !slices.ContainsFunc(statements[i].Products, func(p vex.Product) bool { return p.ID == purl }) {
+					statements[i].Products = append(statements[i].Products, vex.Product{ID: purl})
+				}
+			}
+		}
+	}
+
+	return statements
+}
+
+// buildOpenVEXDocument describes the results as an OpenVEX document created at the given time,
+// whose ID is derived from its statements and when it was created
+func buildOpenVEXDocument(vulnResult *models.VulnerabilityResults, created time.Time) openVEXDocument {
+	doc := openVEXDocument{
+		Context:    openVEXContext,
+		Author:     "osv-scanner",
+		Timestamp:  created.UTC().Format(time.RFC3339),
+		Version:    1,
+		Tooling:    "osv-scanner-" + version.OSVVersion,
+		Statements: openVEXStatements(vulnResult),
+	}
+
+	idHash := sha256.New()
+	fmt.Fprint(idHash, doc.Timestamp)
+	for _, statement := range doc.Statements {
+		fmt.Fprint(idHash, "\x00", statement.Vulnerability.Name)
+		for _, product := range statement.Products {
+			fmt.Fprint(idHash, "\x00", product.ID)
+		}
+	}
+	doc.ID = "https://openvex.dev/docs/public/osv-scanner-" + hex.EncodeToString(idHash.Sum(nil))
+
+	return doc
+}
+
+// PrintOpenVEXReport prints the vulnerabilities in the results as an OpenVEX document, with each of
+// them being under investigation for the packages that it affects, as a starting point for triaging them
+func PrintOpenVEXReport(vulnResult *models.VulnerabilityResults, outputWriter io.Writer) error {
+	encoder := json.NewEncoder(outputWriter)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(buildOpenVEXDocument(vulnResult, time.Now()))
+}
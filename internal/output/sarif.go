@@ -1,6 +1,8 @@
 package output
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"log"
@@ -11,6 +13,7 @@ import (
 
 	"github.com/google/osv-scanner/internal/url"
 	"github.com/google/osv-scanner/internal/utility/results"
+	"github.com/google/osv-scanner/internal/utility/severity"
 	"github.com/google/osv-scanner/internal/version"
 	"github.com/google/osv-scanner/pkg/models"
 	"github.com/jedib0t/go-pretty/v6/table"
@@ -163,6 +166,114 @@ func stripGitHubWorkspace(path string) string {
 	return strings.TrimPrefix(path, "/github/workspace/")
 }
 
+// sarifArtifactPath returns how the file at path should be referred to in SARIF output
+func sarifArtifactPath(path string) string {
+	artifactPath := stripGitHubWorkspace(path)
+	if filepath.IsAbs(artifactPath) {
+		// this only errors if the file path is not absolute,
+		// which we've already confirmed is not the case
+		p, _ := url.FromFilePath(artifactPath)
+
+		artifactPath = p.String()
+	}
+
+	return artifactPath
+}
+
+// sarifSecuritySeverity returns the highest CVSS score of the aliased vulnerabilities as used by the
+// "security-severity" property of rules in GitHub code scanning, or "" if none of them have a score
+func sarifSecuritySeverity(gv *groupedSARIFFinding) string {
+	maxScore := -1.0
+	for _, v := range gv.AliasedVulns {
+		if score, _, _ := severity.CalculateOverallScore(v.Severity); score > maxScore {
+			maxScore = score
+		}
+	}
+
+	if maxScore < 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%.1f", maxScore)
+}
+
+// sarifFingerprint identifies the vulnerability in the version of the package across scans, as some
+// lockfiles can have more than one version of a package
+func sarifFingerprint(vulnID string, pws pkgWithSource) string {
+	// the path is made the same on every platform, so scans on different runners agree
+	sourcePath := stripGitHubWorkspace(pws.Source.Path)
+	sourcePath = filepath.ToSlash(strings.TrimPrefix(sourcePath, filepath.VolumeName(sourcePath)))
+
+	hash := sha256.Sum256([]byte(strings.Join([]string{
+		vulnID,
+		sourcePath,
+		pws.Package.Ecosystem,
+		pws.Package.Name,
+		pws.Package.Version,
+		pws.Package.Commit,
+	}, "\x00")))
+
+	return hex.EncodeToString(hash[:])
+}
+
+// introducedByDependencies maps each package to the direct dependencies that it is depended on through
+func introducedByDependencies(vulnResult *models.VulnerabilityResults) map[pkgWithSource][]models.DirectDependency {
+	introducedBy := map[pkgWithSource][]models.DirectDependency{}
+	for _, res := range vulnResult.Results {
+		for _, pkg := range res.Packages {
+			if len(pkg.IntroducedBy) > 0 {
+				introducedBy[pkgWithSource{Package: pkg.Package, Source: res.Source}] = pkg.IntroducedBy
+			}
+		}
+	}
+
+	return introducedBy
+}
+
+// addSARIFRelatedLocations points the result at where the package is declared if it is a direct dependency,
+// and at where each direct dependency that it is transitively depended on through is declared,
+// returning the message describing them
+func addSARIFRelatedLocations(run *sarif.Run, result *sarif.Result, pws pkgWithSource, deps []models.DirectDependency) string {
+	var declared string
+	var links []string
+	for _, dep := range deps {
+		if dep.Line == 0 {
+			continue
+		}
+		isSelf := dep.Name == pws.Package.Name && dep.Version == pws.Package.Version
+
+		manifestPath := sarifArtifactPath(dep.Manifest)
+		run.AddDistinctArtifact(manifestPath)
+
+		id := len(result.RelatedLocations) + 1
+		locationMessage := fmt.Sprintf("Introduced via %s@%s", dep.Name, dep.Version)
+		if isSelf {
+			locationMessage = "Declared as a direct dependency"
+		}
+		result.AddRelatedLocation(
+			sarif.NewLocationWithPhysicalLocation(
+				sarif.NewPhysicalLocation().
+					WithArtifactLocation(sarif.NewSimpleArtifactLocation(manifestPath)).
+					WithRegion(sarif.NewSimpleRegion(dep.Line, dep.Line)),
+			).
+				WithId(id).
+				WithMessage(sarif.NewTextMessage(locationMessage)),
+		)
+
+		if isSelf {
+			declared = fmt.Sprintf(" Declared as a direct dependency [here](%d).", id)
+		} else {
+			links = append(links, fmt.Sprintf("[%s@%s](%d)", dep.Name, dep.Version, id))
+		}
+	}
+
+	if len(links) == 0 {
+		return declared
+	}
+
+	return declared + fmt.Sprintf(" Introduced via %s.", strings.Join(links, ", "))
+}
+
 // createSARIFHelpText returns the text for SARIF rule's help field
 func createSARIFHelpText(gv *groupedSARIFFinding) string {
 	backtickSARIFTemplate := strings.ReplaceAll(strings.TrimSpace(SARIFTemplate), `""`, "`")
@@ -232,6 +343,7 @@ func PrintSARIFReport(vulnResult *models.VulnerabilityResults, outputWriter io.W
 	run.Tool.Driver.WithVersion(version.OSVVersion)
 
 	vulnIDMap := mapIDsToGroupedSARIFFinding(vulnResult)
+	introducedBy := introducedByDependencies(vulnResult)
 	// Sort the IDs to have deterministic loop of vulnIDMap
 	vulnIDs := []string{}
 	for vulnID := range vulnIDMap {
@@ -276,15 +388,14 @@ func PrintSARIFReport(vulnResult *models.VulnerabilityResults, outputWriter io.W
 
 		rule.DeprecatedIds = gv.AliasedIDList
 
-		for _, pws := range gv.PkgSource.StableKeys() {
-			artifactPath := stripGitHubWorkspace(pws.Source.Path)
-			if filepath.IsAbs(artifactPath) {
-				// this only errors if the file path is not absolute,
-				// which we've already confirmed is not the case
-				p, _ := url.FromFilePath(artifactPath)
+		if securitySeverity := sarifSecuritySeverity(gv); securitySeverity != "" {
+			properties := sarif.NewPropertyBag()
+			properties.AddString("security-severity", securitySeverity)
+			rule.AttachPropertyBag(properties)
+		}
 
-				artifactPath = p.String()
-			}
+		for _, pws := range gv.PkgSource.StableKeys() {
+			artifactPath := sarifArtifactPath(pws.Source.Path)
 
 			run.AddDistinctArtifact(artifactPath)
 
@@ -293,21 +404,31 @@ func PrintSARIFReport(vulnResult *models.VulnerabilityResults, outputWriter io.W
 				alsoKnownAsStr = fmt.Sprintf(" (also known as '%s')", strings.Join(gv.AliasedIDList[1:], "', '"))
 			}
 
-			run.CreateResultForRule(gv.DisplayID).
+			message := fmt.Sprintf(
+				"Package '%s' is vulnerable to '%s'%s.",
+				results.PkgToString(pws.Package),
+				gv.DisplayID,
+				alsoKnownAsStr,
+			)
+
+			result := run.CreateResultForRule(gv.DisplayID).
 				WithLevel("warning").
-				WithMessage(
-					sarif.NewTextMessage(
-						fmt.Sprintf(
-							"Package '%s' is vulnerable to '%s'%s.",
-							results.PkgToString(pws.Package),
-							gv.DisplayID,
-							alsoKnownAsStr,
-						))).
-				AddLocation(
-					sarif.NewLocationWithPhysicalLocation(
-						sarif.NewPhysicalLocation().
-							WithArtifactLocation(sarif.NewSimpleArtifactLocation(artifactPath)),
-					))
+				WithFingerPrints(map[string]interface{}{
+					"osv-scanner/v1": sarifFingerprint(gv.DisplayID, pws),
+				})
+			message += addSARIFRelatedLocations(run, result, pws, introducedBy[pws])
+			result.WithMessage(sarif.NewTextMessage(message))
+			result.AddLocation(
+				sarif.NewLocationWithPhysicalLocation(
+					sarif.NewPhysicalLocation().
+						WithArtifactLocation(sarif.NewSimpleArtifactLocation(artifactPath)),
+				))
+
+			if pws.Source.Repository != "" {
+				properties := sarif.NewPropertyBag()
+				properties.AddString("repository", stripGitHubWorkspace(pws.Source.Repository))
+				result.AttachPropertyBag(properties)
+			}
 		}
 	}
 
@@ -71,6 +71,13 @@ func TestPrintSARIFReport(t *testing.T) {
 				},
 			),
 		},
+		{
+			name: "transitive dependencies introduced via direct dependencies",
+			args: testutility.LoadJSONFixture[models.VulnerabilityResults](t,
+				"fixtures/test-vuln-results-introduced-by.json",
+			),
+			want: testutility.NewSnapshot(),
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -0,0 +1,62 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+func scanTargetsTableBuilder(outputTable table.Writer, targets []models.ScanTarget) table.Writer {
+	workingDir := mustGetWorkingDirectory()
+
+	outputTable.AppendHeader(table.Row{"Source", "Type", "Ecosystems", "Packages"})
+	for _, target := range targets {
+		sourcePath := target.Source.Path
+		if rel, err := filepath.Rel(workingDir, sourcePath); err == nil { // Simplify the path if possible
+			sourcePath = rel
+		}
+
+		// the packages of manifests are only known once they have been resolved
+		packages := strconv.Itoa(target.Packages)
+		if target.Source.Type == "manifest" {
+			packages = "unresolved"
+		}
+
+		outputTable.AppendRow(table.Row{sourcePath, target.Source.Type, strings.Join(target.Ecosystems, ", "), packages})
+	}
+
+	return outputTable
+}
+
+// PrintScanTargetsTable prints the sources that would be scanned as a human-readable table,
+// or as markdown if markdown is set
+func PrintScanTargetsTable(targets []models.ScanTarget, outputWriter io.Writer, markdown bool, terminalWidth int) {
+	if markdown {
+		terminalWidth = 0
+	}
+
+	outputTable := scanTargetsTableBuilder(newTable(outputWriter, terminalWidth), targets)
+	if markdown {
+		outputTable.RenderMarkdown()
+	} else {
+		outputTable.Render()
+	}
+
+	fmt.Fprintf(outputWriter, "\n%d %s would be scanned.\n", len(targets), Form(len(targets), "source", "sources"))
+}
+
+// PrintScanTargetsJSON writes the sources that would be scanned to the provided writer in JSON format
+func PrintScanTargetsJSON(targets []models.ScanTarget, outputWriter io.Writer) error {
+	encoder := json.NewEncoder(outputWriter)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(struct {
+		Targets []models.ScanTarget `json:"targets"`
+	}{targets})
+}
@@ -0,0 +1,125 @@
+//nolint:nosnakecase
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/osv-scanner/internal/version"
+	"github.com/google/osv-scanner/pkg/models"
+	spdx_json "github.com/spdx/tools-golang/json"
+	"github.com/spdx/tools-golang/spdx/v2/common"
+	"github.com/spdx/tools-golang/spdx/v2/v2_3"
+)
+
+// spdxNoAssertion is used for the fields of packages that are required but not known
+const spdxNoAssertion = "NOASSERTION"
+
+// spdxLicense returns the licenses of the package as an SPDX license expression
+func spdxLicense(pkg models.PackageVulns) string {
+	if len(pkg.Licenses) == 0 || slices.Contains(pkg.Licenses, "UNKNOWN") {
+		return spdxNoAssertion
+	}
+
+	licenses := make([]string, 0, len(pkg.Licenses))
+	for _, license := range pkg.Licenses {
+		licenses = append(licenses, string(license))
+	}
+
+	return strings.Join(licenses, " AND ")
+}
+
+// spdxExternalRefs returns the purl of the package along with an advisory for each of its vulnerabilities
+func spdxExternalRefs(pkg models.PackageVulns) []*v2_3.PackageExternalReference {
+	var refs []*v2_3.PackageExternalReference
+	if purl, ok := models.PackageToPURL(pkg.Package); ok {
+		refs = append(refs, &v2_3.PackageExternalReference{
+			Category: common.CategoryPackageManager,
+			RefType:  common.TypePackageManagerPURL,
+			Locator:  purl,
+		})
+	}
+
+	for _, group := range pkg.Groups {
+		for _, id := range group.IDs {
+			refs = append(refs, &v2_3.PackageExternalReference{
+				Category: common.CategorySecurity,
+				RefType:  common.TypeSecurityAdvisory,
+				Locator:  OSVBaseVulnerabilityURL + id,
+			})
+		}
+	}
+
+	return refs
+}
+
+// buildSPDXDocument describes every package in the results as an SPDX 2.3 document created at the given time,
+// listing each package once along with all the sources it was found in
+func buildSPDXDocument(vulnResult *models.VulnerabilityResults, created time.Time) *v2_3.Document {
+	doc := &v2_3.Document{
+		SPDXVersion:    v2_3.Version,
+		DataLicense:    v2_3.DataLicense,
+		SPDXIdentifier: "DOCUMENT",
+		DocumentName:   "osv-scanner",
+		CreationInfo: &v2_3.CreationInfo{
+			Creators: []common.Creator{{CreatorType: "Tool", Creator: "osv-scanner-" + version.OSVVersion}},
+			Created:  created.UTC().Format(time.RFC3339),
+		},
+	}
+
+	indices := make(map[models.PackageInfo]int)
+	namespaceHash := sha256.New()
+	fmt.Fprint(namespaceHash, doc.CreationInfo.Created)
+
+	for _, res := range vulnResult.Results {
+		for _, pkg := range res.Packages {
+			if i, ok := indices[pkg.Package]; ok {
+				doc.Packages[i].PackageSourceInfo += ", " + res.Source.String()
+				continue
+			}
+
+			version := pkg.Package.Version
+			if pkg.Package.Commit != "" {
+				version = pkg.Package.Commit
+			}
+
+			spdxPkg := &v2_3.Package{
+				PackageName:               pkg.Package.Name,
+				PackageSPDXIdentifier:     common.ElementID(fmt.Sprintf("Package-%d", len(doc.Packages))),
+				PackageVersion:            version,
+				PackageDownloadLocation:   spdxNoAssertion,
+				IsFilesAnalyzedTagPresent: true,
+				PackageLicenseConcluded:   spdxNoAssertion,
+				PackageLicenseDeclared:    spdxLicense(pkg),
+				PackageCopyrightText:      spdxNoAssertion,
+				PackageSourceInfo:         "found in " + res.Source.String(),
+				PackageExternalReferences: spdxExternalRefs(pkg),
+			}
+
+			indices[pkg.Package] = len(doc.Packages)
+			doc.Packages = append(doc.Packages, spdxPkg)
+			doc.Relationships = append(doc.Relationships, &v2_3.Relationship{
+				RefA:         common.MakeDocElementID("", "DOCUMENT"),
+				RefB:         common.MakeDocElementID("", string(spdxPkg.PackageSPDXIdentifier)),
+				Relationship: common.TypeRelationshipDescribe,
+			})
+			fmt.Fprint(namespaceHash, "\x00", pkg.Package.Ecosystem, "\x00", pkg.Package.Name, "\x00", version)
+		}
+	}
+
+	doc.DocumentNamespace = "https://spdx.org/spdxdocs/osv-scanner-" + hex.EncodeToString(namespaceHash.Sum(nil))
+
+	return doc
+}
+
+// PrintSPDXReport prints the packages in the results as an SPDX 2.3 JSON document, with the
+// purl of each package and its vulnerabilities as external references, so that the scan can
+// double as SBOM generation (which requires --experimental-all-packages to list every package)
+func PrintSPDXReport(vulnResult *models.VulnerabilityResults, outputWriter io.Writer) error {
+	return spdx_json.Write(buildSPDXDocument(vulnResult, time.Now()), outputWriter, spdx_json.Indent("  "))
+}
@@ -0,0 +1,101 @@
+package output_test
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/internal/testutility"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// the creation time and the namespace derived from it change with every run
+var spdxCreatedRe = regexp.MustCompile(`"(created|documentNamespace)": ".*"`)
+
+func TestPrintSPDXReport(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		args models.VulnerabilityResults
+		want testutility.Snapshot
+	}{
+		{
+			name: "vulnerabilities",
+			args: testutility.LoadJSONFixtureWithWindowsReplacements[models.VulnerabilityResults](t,
+				"fixtures/test-vuln-results-a.json",
+				map[string]string{
+					"/path/to/sub-rust-project/Cargo.lock": "D:\\\\path\\\\to\\\\sub-rust-project\\\\Cargo.lock",
+					"/path/to/go.mod":                      "D:\\\\path\\\\to\\\\go.mod",
+				},
+			),
+			want: testutility.NewSnapshot().WithWindowsReplacements(
+				map[string]string{
+					"lockfile:D:\\\\path\\\\to\\\\sub-rust-project\\\\Cargo.lock": "lockfile:/path/to/sub-rust-project/Cargo.lock",
+					"lockfile:D:\\\\path\\\\to\\\\go.mod":                         "lockfile:/path/to/go.mod",
+				},
+			),
+		},
+		{
+			name: "packages found in multiple sources, with licenses",
+			args: models.VulnerabilityResults{
+				Results: []models.PackageSource{
+					{
+						Source: models.SourceInfo{Path: "path/to/package-lock.json", Type: "lockfile"},
+						Packages: []models.PackageVulns{
+							{
+								Package:  models.PackageInfo{Name: "@babel/core", Version: "7.23.0", Ecosystem: "npm"},
+								Licenses: []models.License{"MIT"},
+							},
+							{
+								Package:  models.PackageInfo{Name: "left-pad", Version: "1.0.0", Ecosystem: "npm"},
+								Licenses: []models.License{"UNKNOWN"},
+							},
+						},
+					},
+					{
+						Source: models.SourceInfo{Path: "path/to/other/package-lock.json", Type: "lockfile"},
+						Packages: []models.PackageVulns{
+							{
+								Package:  models.PackageInfo{Name: "@babel/core", Version: "7.23.0", Ecosystem: "npm"},
+								Licenses: []models.License{"MIT"},
+							},
+						},
+					},
+					{
+						Source: models.SourceInfo{Path: "path/to/pom.xml", Type: "lockfile"},
+						Packages: []models.PackageVulns{
+							{
+								Package:  models.PackageInfo{Name: "org.apache.logging.log4j:log4j-core", Version: "2.17.1", Ecosystem: "Maven"},
+								Licenses: []models.License{"Apache-2.0", "MIT"},
+							},
+						},
+					},
+					{
+						Source: models.SourceInfo{Path: "path/to/submodule", Type: "git"},
+						Packages: []models.PackageVulns{
+							{
+								Package: models.PackageInfo{Name: "github.com/google/osv-scanner", Commit: "9a6bd55c9d0722cb101fe85a3b22d89e4ff4fe52"},
+							},
+						},
+					},
+				},
+			},
+			want: testutility.NewSnapshot(),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			bufOut := bytes.Buffer{}
+			err := output.PrintSPDXReport(&tt.args, &bufOut)
+			if err != nil {
+				t.Errorf("Error writing SPDX output: %s", err)
+			}
+			tt.want.MatchText(t, spdxCreatedRe.ReplaceAllString(bufOut.String(), `"$1": "<redacted>"`))
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// severityRatings are the ratings that vulnerabilities are broken down by in the summary,
+// in the order that they are printed
+var severityRatings = []string{"Critical", "High", "Medium", "Low", "Unknown"}
+
+// Summary is the totals of the findings in a set of results, which is computed once
+// so that every human-readable format reports the same numbers
+type Summary struct {
+	// AffectedPackages is the number of packages with at least one vulnerability or license violation
+	AffectedPackages int
+	// Vulnerabilities is the number of vulnerabilities, counting aliases as a single vulnerability
+	Vulnerabilities int
+	// Severities is the number of vulnerabilities with each rating, which are the
+	// severityRatings unless the ratings came from a custom severity mapping
+	Severities map[string]int
+	// LicenseViolations is the number of license violations across all packages
+	LicenseViolations int
+	// YankedPackages is the number of packages whose versions have been yanked from their registry
+	YankedPackages int
+	// WithdrawnVulnerabilities is the number of advisories that no longer apply as they were withdrawn
+	WithdrawnVulnerabilities int
+	// Ecosystems is the number of affected packages and vulnerabilities in each ecosystem
+	Ecosystems map[string]EcosystemSummary
+}
+
+// EcosystemSummary is the totals of the findings in the packages of an ecosystem
+type EcosystemSummary struct {
+	AffectedPackages int
+	Vulnerabilities  int
+}
+
+// severityRating returns the qualitative rating of a CVSS score,
+// which is the same for all CVSS versions that have ratings
+func severityRating(score string) string {
+	value, err := strconv.ParseFloat(score, 64)
+
+	switch {
+	case err != nil:
+		return "Unknown"
+	case value >= 9:
+		return "Critical"
+	case value >= 7:
+		return "High"
+	case value >= 4:
+		return "Medium"
+	default:
+		return "Low"
+	}
+}
+
+// groupRating returns the rating that the severity of the group was given when scanning,
+// or the CVSS rating of its score for results that predate groups having a rating
+func groupRating(group models.GroupInfo) string {
+	if group.SeverityRating != "" {
+		return group.SeverityRating
+	}
+
+	return severityRating(group.MaxSeverity)
+}
+
+// NewSummary computes the totals of the findings in the results
+func NewSummary(vulnResult *models.VulnerabilityResults) Summary {
+	summary := Summary{
+		Severities: make(map[string]int, len(severityRatings)),
+		Ecosystems: make(map[string]EcosystemSummary),
+	}
+
+	for _, pkgSource := range vulnResult.Results {
+		for _, pkg := range pkgSource.Packages {
+			if pkg.Yanked != nil {
+				summary.YankedPackages++
+			}
+			summary.WithdrawnVulnerabilities += len(pkg.Withdrawn)
+
+			if len(pkg.Groups) == 0 && len(pkg.LicenseViolations) == 0 {
+				continue
+			}
+
+			summary.AffectedPackages++
+			summary.LicenseViolations += len(pkg.LicenseViolations)
+
+			for _, group := range pkg.Groups {
+				summary.Vulnerabilities++
+				summary.Severities[groupRating(group)]++
+			}
+
+			ecosystem := summary.Ecosystems[pkg.Package.Ecosystem]
+			ecosystem.AffectedPackages++
+			ecosystem.Vulnerabilities += len(pkg.Groups)
+			summary.Ecosystems[pkg.Package.Ecosystem] = ecosystem
+		}
+	}
+
+	return summary
+}
+
+func (s Summary) String() string {
+	severities := make([]string, 0, len(severityRatings))
+	for _, rating := range severityRatings {
+		severities = append(severities, fmt.Sprintf("%d %s", s.Severities[rating], rating))
+	}
+
+	// ratings from a custom severity mapping are listed after the standard ones
+	custom := make([]string, 0, len(s.Severities))
+	for rating := range s.Severities {
+		if !slices.Contains(severityRatings, rating) {
+			custom = append(custom, rating)
+		}
+	}
+	slices.Sort(custom)
+	for _, rating := range custom {
+		severities = append(severities, fmt.Sprintf("%d %s", s.Severities[rating], rating))
+	}
+
+	str := fmt.Sprintf(
+		"Total %d %s affected by %d known %s (%s) and %d license %s.",
+		s.AffectedPackages,
+		Form(s.AffectedPackages, "package", "packages"),
+		s.Vulnerabilities,
+		Form(s.Vulnerabilities, "vulnerability", "vulnerabilities"),
+		strings.Join(severities, ", "),
+		s.LicenseViolations,
+		Form(s.LicenseViolations, "violation", "violations"),
+	)
+
+	// these are only mentioned when present, as they are only looked for in some scans
+	if s.YankedPackages > 0 {
+		str += fmt.Sprintf(" %d %s yanked.", s.YankedPackages, Form(s.YankedPackages, "package version has been", "package versions have been"))
+	}
+	if s.WithdrawnVulnerabilities > 0 {
+		str += fmt.Sprintf(" %d withdrawn %s ignored.", s.WithdrawnVulnerabilities, Form(s.WithdrawnVulnerabilities, "advisory was", "advisories were"))
+	}
+
+	return str
+}
+
+// PrintSummary prints the summary as a footer for the human-readable formats
+func PrintSummary(summary Summary, outputWriter io.Writer) {
+	fmt.Fprintf(outputWriter, "\n%s\n", summary)
+}
@@ -0,0 +1,133 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/maps"
+
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// summaryFinding is a vulnerability affecting a package, identified by the first ID of its group
+type summaryFinding struct {
+	id       string
+	severity string
+	score    float64
+	pkg      models.PackageInfo
+	source   string
+}
+
+// mostSevereFindings returns the count findings with the highest severity, with the
+// findings that do not have a severity coming last
+func mostSevereFindings(vulnResult *models.VulnerabilityResults, count int) []summaryFinding {
+	var findings []summaryFinding
+	for _, pkgSource := range vulnResult.Results {
+		for _, pkg := range pkgSource.Packages {
+			for _, group := range pkg.Groups {
+				score, err := strconv.ParseFloat(group.MaxSeverity, 64)
+				if err != nil {
+					score = -1
+				}
+
+				findings = append(findings, summaryFinding{
+					id:       group.IDs[0],
+					severity: group.MaxSeverity,
+					score:    score,
+					pkg:      pkg.Package,
+					source:   pkgSource.Source.Path,
+				})
+			}
+		}
+	}
+
+	slices.SortStableFunc(findings, func(a, b summaryFinding) int {
+		switch {
+		case a.score > b.score:
+			return -1
+		case a.score < b.score:
+			return 1
+		}
+
+		return strings.Compare(a.id, b.id)
+	})
+
+	if len(findings) > count {
+		findings = findings[:count]
+	}
+
+	return findings
+}
+
+func ecosystemsTableBuilder(outputTable table.Writer, summary Summary) table.Writer {
+	ecosystems := maps.Keys(summary.Ecosystems)
+	slices.Sort(ecosystems)
+
+	outputTable.AppendHeader(table.Row{"Ecosystem", "Affected packages", "Vulnerabilities"})
+	for _, ecosystem := range ecosystems {
+		counts := summary.Ecosystems[ecosystem]
+		outputTable.AppendRow(table.Row{ecosystem, counts.AffectedPackages, counts.Vulnerabilities})
+	}
+
+	return outputTable
+}
+
+func mostSevereTableBuilder(outputTable table.Writer, findings []summaryFinding) table.Writer {
+	workingDir := mustGetWorkingDirectory()
+
+	outputTable.AppendHeader(table.Row{"OSV URL", "CVSS", "Ecosystem", "Package", "Version", "Source"})
+	for _, finding := range findings {
+		source := finding.source
+		if rel, err := filepath.Rel(workingDir, source); err == nil { // Simplify the path if possible
+			source = rel
+		}
+
+		outputTable.AppendRow(table.Row{
+			OSVBaseVulnerabilityURL + finding.id,
+			finding.severity,
+			finding.pkg.Ecosystem,
+			finding.pkg.Name,
+			finding.pkg.Version,
+			source,
+		})
+	}
+
+	return outputTable
+}
+
+// PrintSummaryReport prints only the totals of the findings in the results, broken down by
+// ecosystem, along with the top most severe vulnerabilities, so that the output stays short
+// in places like CI logs while the full results are written elsewhere.
+func PrintSummaryReport(vulnResult *models.VulnerabilityResults, outputWriter io.Writer, terminalWidth int, markdown bool, top int) {
+	if markdown {
+		terminalWidth = 0
+	}
+	render := func(outputTable table.Writer) {
+		if markdown {
+			outputTable.RenderMarkdown()
+		} else {
+			outputTable.Render()
+		}
+	}
+
+	summary := NewSummary(vulnResult)
+	fmt.Fprintln(outputWriter, summary)
+
+	if len(summary.Ecosystems) > 0 {
+		fmt.Fprintln(outputWriter)
+		render(ecosystemsTableBuilder(newTable(outputWriter, terminalWidth), summary))
+	}
+
+	findings := mostSevereFindings(vulnResult, top)
+	if len(findings) == 0 {
+		return
+	}
+
+	fmt.Fprintf(outputWriter, "\nMost severe vulnerabilities (%d of %d):\n\n", len(findings), summary.Vulnerabilities)
+	render(mostSevereTableBuilder(newTable(outputWriter, terminalWidth), findings))
+}
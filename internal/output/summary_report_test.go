@@ -0,0 +1,65 @@
+package output_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/internal/testutility"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func TestPrintSummaryReport(t *testing.T) {
+	t.Parallel()
+
+	vulnResult := &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "path/to/package-lock.json", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package: models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"},
+						Groups: []models.GroupInfo{
+							{IDs: []string{"GHSA-29mw-wpgm-hmr9"}, MaxSeverity: "5.3"},
+							{IDs: []string{"GHSA-35jh-r3h4-6jhm", "CVE-2021-23337"}, MaxSeverity: "7.2"},
+						},
+						LicenseViolations: []models.License{"MIT"},
+					},
+				},
+			},
+			{
+				Source: models.SourceInfo{Path: "path/to/requirements.txt", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package: models.PackageInfo{Name: "django", Version: "2.2.0", Ecosystem: "PyPI"},
+						Groups: []models.GroupInfo{
+							{IDs: []string{"PYSEC-2019-12"}, MaxSeverity: "9.8"},
+							{IDs: []string{"PYSEC-2019-14"}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		markdown bool
+		top      int
+	}{
+		{name: "table", top: 10},
+		{name: "markdown", markdown: true, top: 10},
+		{name: "fewer than the total", top: 2},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			outputWriter := &bytes.Buffer{}
+			output.PrintSummaryReport(vulnResult, outputWriter, 0, tt.markdown, tt.top)
+
+			testutility.NewSnapshot().MatchText(t, outputWriter.String())
+		})
+	}
+}
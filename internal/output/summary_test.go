@@ -0,0 +1,141 @@
+package output_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func TestNewSummary(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		vulnResult *models.VulnerabilityResults
+		want       string
+	}{
+		{
+			name:       "no results",
+			vulnResult: &models.VulnerabilityResults{},
+			want:       "Total 0 packages affected by 0 known vulnerabilities (0 Critical, 0 High, 0 Medium, 0 Low, 0 Unknown) and 0 license violations.",
+		},
+		{
+			name: "vulnerabilities and license violations",
+			vulnResult: &models.VulnerabilityResults{
+				Results: []models.PackageSource{
+					{
+						Source: models.SourceInfo{Path: "/path/to/package-lock.json", Type: "lockfile"},
+						Packages: []models.PackageVulns{
+							{
+								Package: models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"},
+								Groups: []models.GroupInfo{
+									{IDs: []string{"GHSA-35jh-r3h4-6jhm", "CVE-2021-23337"}, MaxSeverity: "7.2"},
+									{IDs: []string{"GHSA-29mw-wpgm-hmr9"}, MaxSeverity: "5.3"},
+								},
+								LicenseViolations: []models.License{"MIT"},
+							},
+							{
+								// packages without any findings are not affected
+								Package:  models.PackageInfo{Name: "react", Version: "18.2.0", Ecosystem: "npm"},
+								Licenses: []models.License{"MIT"},
+							},
+						},
+					},
+					{
+						Source: models.SourceInfo{Path: "/path/to/requirements.txt", Type: "lockfile"},
+						Packages: []models.PackageVulns{
+							{
+								Package: models.PackageInfo{Name: "django", Version: "2.2.0", Ecosystem: "PyPI"},
+								Groups: []models.GroupInfo{
+									{IDs: []string{"PYSEC-2019-12"}, MaxSeverity: "9.8"},
+									{IDs: []string{"PYSEC-2019-13"}, MaxSeverity: "0.0"},
+									{IDs: []string{"PYSEC-2019-14"}, MaxSeverity: ""},
+								},
+							},
+							{
+								Package:           models.PackageInfo{Name: "flask", Version: "1.0.0", Ecosystem: "PyPI"},
+								LicenseViolations: []models.License{"BSD-3-Clause"},
+							},
+						},
+					},
+				},
+			},
+			want: "Total 3 packages affected by 5 known vulnerabilities (1 Critical, 1 High, 1 Medium, 1 Low, 1 Unknown) and 2 license violations.",
+		},
+		{
+			name: "ratings from a custom severity mapping",
+			vulnResult: &models.VulnerabilityResults{
+				Results: []models.PackageSource{
+					{
+						Source: models.SourceInfo{Path: "/path/to/requirements.txt", Type: "lockfile"},
+						Packages: []models.PackageVulns{
+							{
+								Package: models.PackageInfo{Name: "django", Version: "2.2.0", Ecosystem: "PyPI"},
+								Groups: []models.GroupInfo{
+									{IDs: []string{"PYSEC-2019-12"}, MaxSeverity: "9.8", SeverityRating: "Severe"},
+									{IDs: []string{"PYSEC-2019-13"}, MaxSeverity: "7.5", SeverityRating: "High"},
+									{IDs: []string{"PYSEC-2019-14"}, MaxSeverity: "5.3", SeverityRating: "Moderate"},
+									{IDs: []string{"PYSEC-2019-15"}, MaxSeverity: "5.0", SeverityRating: "Moderate"},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: "Total 1 package affected by 4 known vulnerabilities (0 Critical, 1 High, 0 Medium, 0 Low, 0 Unknown, 2 Moderate, 1 Severe) and 0 license violations.",
+		},
+		{
+			name: "single findings",
+			vulnResult: &models.VulnerabilityResults{
+				Results: []models.PackageSource{
+					{
+						Source: models.SourceInfo{Path: "/path/to/go.mod", Type: "lockfile"},
+						Packages: []models.PackageVulns{
+							{
+								Package:           models.PackageInfo{Name: "golang.org/x/net", Version: "0.1.0", Ecosystem: "Go"},
+								Groups:            []models.GroupInfo{{IDs: []string{"GO-2022-1144"}, MaxSeverity: "7.5"}},
+								LicenseViolations: []models.License{"BSD-3-Clause"},
+							},
+						},
+					},
+				},
+			},
+			want: "Total 1 package affected by 1 known vulnerability (0 Critical, 1 High, 0 Medium, 0 Low, 0 Unknown) and 1 license violation.",
+		},
+		{
+			name: "yanked versions and withdrawn advisories",
+			vulnResult: &models.VulnerabilityResults{
+				Results: []models.PackageSource{
+					{
+						Source: models.SourceInfo{Path: "/path/to/Cargo.lock", Type: "lockfile"},
+						Packages: []models.PackageVulns{
+							{
+								Package: models.PackageInfo{Name: "smallvec", Version: "1.6.0", Ecosystem: "crates.io"},
+								Yanked:  &models.YankedVersion{Reason: "unsound"},
+							},
+							{
+								Package:   models.PackageInfo{Name: "time", Version: "0.1.43", Ecosystem: "crates.io"},
+								Yanked:    &models.YankedVersion{},
+								Withdrawn: []string{"RUSTSEC-2020-0071"},
+							},
+						},
+					},
+				},
+			},
+			want: "Total 0 packages affected by 0 known vulnerabilities (0 Critical, 0 High, 0 Medium, 0 Low, 0 Unknown) and 0 license violations." +
+				" 2 package versions have been yanked. 1 withdrawn advisory was ignored.",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(tt.want, output.NewSummary(tt.vulnResult).String()); diff != "" {
+				t.Errorf("NewSummary() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -25,13 +25,36 @@ const OSVBaseVulnerabilityURL = "https://osv.dev/"
 
 // PrintTableResults prints the osv scan results into a human friendly table.
 func PrintTableResults(vulnResult *models.VulnerabilityResults, outputWriter io.Writer, terminalWidth int) {
+	PrintTableResultsWithOptions(vulnResult, outputWriter, terminalWidth, TableOptions{})
+}
+
+// PrintTableResultsWithOptions prints the results like PrintTableResults, presented as per the options
+func PrintTableResultsWithOptions(vulnResult *models.VulnerabilityResults, outputWriter io.Writer, terminalWidth int, options TableOptions) {
 	// Render the vulnerabilities.
 	outputTable := newTable(outputWriter, terminalWidth)
-	outputTable = tableBuilder(outputTable, vulnResult, terminalWidth > 0)
+	if options.DedupeAdvisories {
+		outputTable = advisoriesTableBuilder(outputTable, vulnResult, terminalWidth > 0)
+	} else {
+		outputTable = tableBuilder(outputTable, vulnResult, terminalWidth > 0)
+	}
 	if outputTable.Length() != 0 {
 		outputTable.Render()
 	}
 
+	// Render how the vulnerable packages are depended on if known.
+	outputPathsTable := newTable(outputWriter, terminalWidth)
+	outputPathsTable = dependencyPathsTableBuilder(outputPathsTable, vulnResult)
+	if outputPathsTable.Length() != 0 {
+		outputPathsTable.Render()
+	}
+
+	// Render the package versions that have been yanked or had advisories withdrawn.
+	outputYankedTable := newTable(outputWriter, terminalWidth)
+	outputYankedTable = yankedTableBuilder(outputYankedTable, vulnResult)
+	if outputYankedTable.Length() != 0 {
+		outputYankedTable.Render()
+	}
+
 	// Render the licenses if any.
 	outputLicenseTable := newTable(outputWriter, terminalWidth)
 	outputLicenseTable = licenseTableBuilder(outputLicenseTable, vulnResult)
@@ -57,23 +80,158 @@ func newTable(outputWriter io.Writer, terminalWidth int) table.Writer {
 }
 
 func tableBuilder(outputTable table.Writer, vulnResult *models.VulnerabilityResults, addStyling bool) table.Writer {
-	outputTable.AppendHeader(table.Row{"OSV URL", "CVSS", "Ecosystem", "Package", "Version", "Source"})
-	rows := tableBuilderInner(vulnResult, addStyling, true)
+	showFixed := hasFixedVersions(vulnResult)
+	if showFixed {
+		outputTable.AppendHeader(table.Row{"OSV URL", "CVSS", "Ecosystem", "Package", "Version", "Fixed version", "Source"})
+	} else {
+		outputTable.AppendHeader(table.Row{"OSV URL", "CVSS", "Ecosystem", "Package", "Version", "Source"})
+	}
+	rows := tableBuilderInner(vulnResult, addStyling, true, showFixed)
 	for _, elem := range rows {
 		outputTable.AppendRow(elem.row, table.RowConfig{AutoMerge: elem.shouldMerge})
 	}
 
-	uncalledRows := tableBuilderInner(vulnResult, addStyling, false)
-	if len(uncalledRows) == 0 {
-		return outputTable
+	uncalledRows := tableBuilderInner(vulnResult, addStyling, false, showFixed)
+	if len(uncalledRows) > 0 {
+		outputTable.AppendSeparator()
+		outputTable.AppendRow(table.Row{"Uncalled vulnerabilities"})
+		outputTable.AppendSeparator()
+
+		for _, elem := range uncalledRows {
+			outputTable.AppendRow(elem.row, table.RowConfig{AutoMerge: elem.shouldMerge})
+		}
 	}
 
-	outputTable.AppendSeparator()
-	outputTable.AppendRow(table.Row{"Uncalled vulnerabilities"})
-	outputTable.AppendSeparator()
+	suppressedRows := suppressedTableBuilderInner(vulnResult, addStyling, showFixed)
+	if len(suppressedRows) > 0 {
+		outputTable.AppendSeparator()
+		outputTable.AppendRow(table.Row{"Suppressed vulnerabilities"})
+		outputTable.AppendSeparator()
 
-	for _, elem := range uncalledRows {
-		outputTable.AppendRow(elem.row, table.RowConfig{AutoMerge: elem.shouldMerge})
+		for _, elem := range suppressedRows {
+			outputTable.AppendRow(elem.row, table.RowConfig{AutoMerge: elem.shouldMerge})
+		}
+	}
+
+	return outputTable
+}
+
+// suppressedTableBuilderInner builds a row for each vulnerability that was suppressed
+// by an inline comment, so that they remain visible for auditing
+func suppressedTableBuilderInner(vulnResult *models.VulnerabilityResults, addStyling bool, showFixed bool) []tbInnerResponse {
+	allOutputRows := []tbInnerResponse{}
+	workingDir := mustGetWorkingDirectory()
+
+	for _, sourceRes := range vulnResult.Results {
+		for _, pkg := range sourceRes.Packages {
+			source := sourceRes.Source
+			sourcePath, err := filepath.Rel(workingDir, source.Path)
+			if err == nil { // Simplify the path if possible
+				source.Path = sourcePath
+			}
+			if source.Ref != "" {
+				source.Path += "@" + source.Ref
+			}
+
+			for _, suppressed := range pkg.Suppressed {
+				link := OSVBaseVulnerabilityURL + suppressed.ID
+				if addStyling {
+					link = OSVBaseVulnerabilityURL + text.Bold.EscapeSeq() + suppressed.ID + text.Reset.EscapeSeq()
+				}
+
+				row := table.Row{link, "", pkg.Package.Ecosystem, pkg.Package.Name, pkg.Package.Version}
+				if showFixed {
+					row = append(row, "")
+				}
+
+				allOutputRows = append(allOutputRows, tbInnerResponse{
+					row: append(row, source.Path),
+				})
+			}
+		}
+	}
+
+	return allOutputRows
+}
+
+// DependencyPathBreadcrumb formats a dependency path as a breadcrumb from the direct dependency
+func DependencyPathBreadcrumb(path []string) string {
+	return strings.Join(path, " > ")
+}
+
+// dependencyPathsTableBuilder builds a row for each package that has dependency paths,
+// with each path on its own line
+func dependencyPathsTableBuilder(outputTable table.Writer, vulnResult *models.VulnerabilityResults) table.Writer {
+	workingDir := mustGetWorkingDirectory()
+
+	for _, sourceRes := range vulnResult.Results {
+		sourcePath := sourceRes.Source.Path
+		if rel, err := filepath.Rel(workingDir, sourcePath); err == nil { // Simplify the path if possible
+			sourcePath = rel
+		}
+
+		for _, pkg := range sourceRes.Packages {
+			if len(pkg.DependencyPaths) == 0 {
+				continue
+			}
+
+			breadcrumbs := make([]string, 0, len(pkg.DependencyPaths))
+			for _, path := range pkg.DependencyPaths {
+				breadcrumbs = append(breadcrumbs, DependencyPathBreadcrumb(path))
+			}
+
+			outputTable.AppendRow(table.Row{results.PkgToString(pkg.Package), sourcePath, strings.Join(breadcrumbs, "\n")})
+		}
+	}
+
+	if outputTable.Length() != 0 {
+		outputTable.AppendHeader(table.Row{"Package", "Source", "Dependency paths"})
+	}
+
+	return outputTable
+}
+
+// YankedDescription describes why the package is listed as yanked or withdrawn,
+// or returns an empty string if it is neither
+func YankedDescription(pkg models.PackageVulns) string {
+	var lines []string
+	if pkg.Yanked != nil {
+		line := "Yanked"
+		if pkg.Yanked.Reason != "" {
+			line += ": " + pkg.Yanked.Reason
+		}
+		lines = append(lines, line)
+	}
+	if len(pkg.Withdrawn) > 0 {
+		lines = append(lines, "Withdrawn advisories: "+strings.Join(pkg.Withdrawn, ", "))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// yankedTableBuilder builds a row for each package whose version has been yanked from
+// its registry or that had advisories which have since been withdrawn
+func yankedTableBuilder(outputTable table.Writer, vulnResult *models.VulnerabilityResults) table.Writer {
+	workingDir := mustGetWorkingDirectory()
+
+	for _, sourceRes := range vulnResult.Results {
+		sourcePath := sourceRes.Source.Path
+		if rel, err := filepath.Rel(workingDir, sourcePath); err == nil { // Simplify the path if possible
+			sourcePath = rel
+		}
+
+		for _, pkg := range sourceRes.Packages {
+			description := YankedDescription(pkg)
+			if description == "" {
+				continue
+			}
+
+			outputTable.AppendRow(table.Row{results.PkgToString(pkg.Package), sourcePath, description})
+		}
+	}
+
+	if outputTable.Length() != 0 {
+		outputTable.AppendHeader(table.Row{"Package", "Source", "Yanked or withdrawn"})
 	}
 
 	return outputTable
@@ -84,7 +242,7 @@ type tbInnerResponse struct {
 	shouldMerge bool
 }
 
-func tableBuilderInner(vulnResult *models.VulnerabilityResults, addStyling bool, calledVulns bool) []tbInnerResponse {
+func tableBuilderInner(vulnResult *models.VulnerabilityResults, addStyling bool, calledVulns bool, showFixed bool) []tbInnerResponse {
 	allOutputRows := []tbInnerResponse{}
 	workingDir := mustGetWorkingDirectory()
 
@@ -95,6 +253,12 @@ func tableBuilderInner(vulnResult *models.VulnerabilityResults, addStyling bool,
 			if err == nil { // Simplify the path if possible
 				source.Path = sourcePath
 			}
+			if source.Ref != "" {
+				source.Path += "@" + source.Ref
+			}
+			if source.Type == "manifest" {
+				source.Path += " (resolved from manifest, not locked)"
+			}
 
 			// Merge groups into the same row
 			for _, group := range pkg.Groups {
@@ -130,6 +294,9 @@ func tableBuilderInner(vulnResult *models.VulnerabilityResults, addStyling bool,
 					outputRow = append(outputRow, pkg.Package.Ecosystem, name, pkg.Package.Version)
 				}
 
+				if showFixed {
+					outputRow = append(outputRow, FixedVersionsDescription(group))
+				}
 				outputRow = append(outputRow, source.Path)
 				allOutputRows = append(allOutputRows, tbInnerResponse{
 					row:         outputRow,
@@ -0,0 +1,61 @@
+package pep508
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+)
+
+// script prints the values of the marker variables for the running interpreter,
+// as per https://peps.python.org/pep-0508/#environment-markers
+const script = `
+import json, os, platform, sys
+
+def format_full_version(info):
+    version = "{0.major}.{0.minor}.{0.micro}".format(info)
+    if info.releaselevel != "final":
+        version += info.releaselevel[0] + str(info.serial)
+    return version
+
+print(json.dumps({
+    "implementation_name": sys.implementation.name,
+    "implementation_version": format_full_version(sys.implementation.version),
+    "os_name": os.name,
+    "platform_machine": platform.machine(),
+    "platform_python_implementation": platform.python_implementation(),
+    "platform_release": platform.release(),
+    "platform_system": platform.system(),
+    "platform_version": platform.version(),
+    "python_full_version": platform.python_version(),
+    "python_version": ".".join(platform.python_version_tuple()[:2]),
+    "sys_platform": sys.platform,
+}))
+`
+
+var ErrNoInterpreter = errors.New("no python interpreter could be found")
+
+// DetectEnvironment returns the environment of the first Python interpreter
+// that can be found on the PATH
+func DetectEnvironment() (Environment, error) {
+	for _, name := range []string{"python3", "python"} {
+		path, err := exec.LookPath(name)
+		if err != nil {
+			continue
+		}
+
+		out, err := exec.Command(path, "-c", script).Output()
+		if err != nil {
+			return nil, fmt.Errorf("failed to run %s: %w", path, err)
+		}
+
+		var env Environment
+		if err := json.Unmarshal(out, &env); err != nil {
+			return nil, fmt.Errorf("failed to parse environment from %s: %w", path, err)
+		}
+
+		return env, nil
+	}
+
+	return nil, ErrNoInterpreter
+}
@@ -0,0 +1,317 @@
+// Package pep508 implements evaluation of the environment markers described in
+// https://peps.python.org/pep-0508/#environment-markers, which are used to
+// make Python requirements conditional on the environment they are installed in.
+package pep508
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/cachedregexp"
+	"github.com/google/osv-scanner/internal/semantic"
+)
+
+// Environment maps marker variables (such as "python_version") to their values
+type Environment map[string]string
+
+var ErrInvalidMarker = errors.New("invalid environment marker")
+
+type tokenKind int
+
+const (
+	tokenVariable tokenKind = iota
+	tokenString
+	tokenOperator
+	tokenAnd
+	tokenOr
+	tokenOpenParen
+	tokenCloseParen
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+}
+
+var variables = map[string]struct{}{
+	"implementation_name":            {},
+	"implementation_version":         {},
+	"os_name":                        {},
+	"platform_machine":               {},
+	"platform_python_implementation": {},
+	"platform_release":               {},
+	"platform_system":                {},
+	"platform_version":               {},
+	"python_full_version":            {},
+	"python_version":                 {},
+	"sys_platform":                   {},
+	"extra":                          {},
+}
+
+func tokenize(marker string) ([]token, error) {
+	re := cachedregexp.MustCompile(`^\s*(?:(?P<paren>[()])|(?P<string>'[^']*'|"[^"]*")|(?P<op>===|==|!=|~=|<=|>=|<|>|not\s+in\b|in\b)|(?P<word>[a-zA-Z_][a-zA-Z0-9_]*))`)
+
+	var tokens []token
+
+	for rest := strings.TrimSpace(marker); rest != ""; rest = strings.TrimSpace(rest) {
+		match := re.FindStringSubmatch(rest)
+
+		if match == nil {
+			return nil, fmt.Errorf("%w: unexpected %q", ErrInvalidMarker, rest)
+		}
+
+		rest = rest[len(match[0]):]
+
+		switch {
+		case match[re.SubexpIndex("paren")] == "(":
+			tokens = append(tokens, token{kind: tokenOpenParen})
+		case match[re.SubexpIndex("paren")] == ")":
+			tokens = append(tokens, token{kind: tokenCloseParen})
+		case match[re.SubexpIndex("string")] != "":
+			str := match[re.SubexpIndex("string")]
+			tokens = append(tokens, token{kind: tokenString, value: str[1 : len(str)-1]})
+		case match[re.SubexpIndex("op")] != "":
+			op := strings.Join(strings.Fields(match[re.SubexpIndex("op")]), " ")
+			tokens = append(tokens, token{kind: tokenOperator, value: op})
+		default:
+			switch word := match[re.SubexpIndex("word")]; word {
+			case "and":
+				tokens = append(tokens, token{kind: tokenAnd})
+			case "or":
+				tokens = append(tokens, token{kind: tokenOr})
+			default:
+				if _, ok := variables[word]; !ok {
+					return nil, fmt.Errorf("%w: unknown variable %q", ErrInvalidMarker, word)
+				}
+				tokens = append(tokens, token{kind: tokenVariable, value: word})
+			}
+		}
+	}
+
+	return tokens, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	env    Environment
+}
+
+func (p *parser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+
+	return p.tokens[p.pos], true
+}
+
+func (p *parser) next() (token, bool) {
+	t, ok := p.peek()
+	if ok {
+		p.pos++
+	}
+
+	return t, ok
+}
+
+// parseOr parses marker_or := marker_and ('or' marker_and)*
+func (p *parser) parseOr() (bool, error) {
+	result, err := p.parseAnd()
+	if err != nil {
+		return false, err
+	}
+
+	for {
+		if t, ok := p.peek(); !ok || t.kind != tokenOr {
+			return result, nil
+		}
+		p.pos++
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return false, err
+		}
+
+		result = result || right
+	}
+}
+
+// parseAnd parses marker_and := marker_expr ('and' marker_expr)*
+func (p *parser) parseAnd() (bool, error) {
+	result, err := p.parseExpr()
+	if err != nil {
+		return false, err
+	}
+
+	for {
+		if t, ok := p.peek(); !ok || t.kind != tokenAnd {
+			return result, nil
+		}
+		p.pos++
+
+		right, err := p.parseExpr()
+		if err != nil {
+			return false, err
+		}
+
+		result = result && right
+	}
+}
+
+// parseExpr parses marker_expr := marker_var marker_op marker_var | '(' marker_or ')'
+func (p *parser) parseExpr() (bool, error) {
+	if t, ok := p.peek(); ok && t.kind == tokenOpenParen {
+		p.pos++
+
+		result, err := p.parseOr()
+		if err != nil {
+			return false, err
+		}
+
+		if t, ok := p.next(); !ok || t.kind != tokenCloseParen {
+			return false, fmt.Errorf("%w: expected closing parenthesis", ErrInvalidMarker)
+		}
+
+		return result, nil
+	}
+
+	left, err := p.parseValue()
+	if err != nil {
+		return false, err
+	}
+
+	op, ok := p.next()
+	if !ok || op.kind != tokenOperator {
+		return false, fmt.Errorf("%w: expected an operator", ErrInvalidMarker)
+	}
+
+	right, err := p.parseValue()
+	if err != nil {
+		return false, err
+	}
+
+	return compare(left, op.value, right), nil
+}
+
+func (p *parser) parseValue() (string, error) {
+	t, ok := p.next()
+
+	switch {
+	case ok && t.kind == tokenString:
+		return t.value, nil
+	case ok && t.kind == tokenVariable:
+		return p.env[t.value], nil
+	default:
+		return "", fmt.Errorf("%w: expected a variable or string", ErrInvalidMarker)
+	}
+}
+
+func isVersion(str string) bool {
+	return cachedregexp.MustCompile(`^v?\d+(\.\d+)*([a-z0-9.+!-]*)$`).MatchString(strings.ToLower(str))
+}
+
+// isCompatible reports if the version is compatible with the given version
+// as per https://peps.python.org/pep-0440/#compatible-release
+func isCompatible(version, with string) bool {
+	if semantic.MustParse(version, "PyPI").CompareStr(with) < 0 {
+		return false
+	}
+
+	withRelease := strings.Split(cachedregexp.MustCompile(`^v?[\d.]*\d`).FindString(with), ".")
+	versionRelease := strings.Split(cachedregexp.MustCompile(`^v?[\d.]*\d`).FindString(version), ".")
+
+	if len(withRelease) < 2 || len(versionRelease) < len(withRelease)-1 {
+		return false
+	}
+
+	for i := range withRelease[:len(withRelease)-1] {
+		if withRelease[i] != versionRelease[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func compare(left, op, right string) bool {
+	switch op {
+	case "in":
+		return strings.Contains(right, left)
+	case "not in":
+		return !strings.Contains(right, left)
+	case "===":
+		return left == right
+	}
+
+	// comparisons are done using version semantics if possible, otherwise as strings
+	if !isVersion(left) || !isVersion(right) {
+		cmp := strings.Compare(left, right)
+
+		switch op {
+		case "==":
+			return cmp == 0
+		case "!=":
+			return cmp != 0
+		case "<":
+			return cmp < 0
+		case "<=":
+			return cmp <= 0
+		case ">":
+			return cmp > 0
+		case ">=":
+			return cmp >= 0
+		}
+
+		return false
+	}
+
+	if op == "~=" {
+		return isCompatible(left, right)
+	}
+
+	cmp := semantic.MustParse(left, "PyPI").CompareStr(right)
+
+	switch op {
+	case "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+
+	return false
+}
+
+// Evaluate reports if the given marker is satisfied by the environment.
+// Variables that are not present in the environment are treated as empty strings.
+func Evaluate(marker string, env Environment) (bool, error) {
+	tokens, err := tokenize(marker)
+	if err != nil {
+		return false, err
+	}
+
+	if len(tokens) == 0 {
+		return true, nil
+	}
+
+	p := &parser{tokens: tokens, env: env}
+
+	result, err := p.parseOr()
+	if err != nil {
+		return false, err
+	}
+
+	if p.pos != len(p.tokens) {
+		return false, fmt.Errorf("%w: unexpected trailing tokens", ErrInvalidMarker)
+	}
+
+	return result, nil
+}
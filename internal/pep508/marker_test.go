@@ -0,0 +1,88 @@
+package pep508_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/osv-scanner/internal/pep508"
+)
+
+func TestEvaluate(t *testing.T) {
+	t.Parallel()
+
+	env := pep508.Environment{
+		"implementation_name":            "cpython",
+		"os_name":                        "posix",
+		"platform_machine":               "x86_64",
+		"platform_python_implementation": "CPython",
+		"platform_system":                "Linux",
+		"python_full_version":            "3.10.12",
+		"python_version":                 "3.10",
+		"sys_platform":                   "linux",
+	}
+
+	tests := []struct {
+		marker string
+		want   bool
+	}{
+		{marker: "", want: true},
+		{marker: `python_version < "3.9"`, want: false},
+		{marker: `python_version >= "3.9"`, want: true},
+		{marker: `python_version > '3.9'`, want: true},
+		{marker: `python_version == "3.10"`, want: true},
+		{marker: `python_version != "3.10"`, want: false},
+		{marker: `python_full_version ~= "3.10.0"`, want: true},
+		{marker: `python_full_version ~= "3.11.0"`, want: false},
+		{marker: `python_version ~= "3.8"`, want: true},
+		{marker: `python_version === "3.10"`, want: true},
+		{marker: `"3.9" < python_version`, want: true},
+		{marker: `sys_platform == "win32"`, want: false},
+		{marker: `sys_platform != 'win32'`, want: true},
+		{marker: `platform_system == "Linux" and platform_machine == "x86_64"`, want: true},
+		{marker: `sys_platform == "win32" or sys_platform == "darwin"`, want: false},
+		{marker: `sys_platform == "win32" or python_version >= "3"`, want: true},
+		{marker: `sys_platform == "win32" or sys_platform == "linux" and python_version < "3"`, want: false},
+		{marker: `(sys_platform == "win32" or sys_platform == "linux") and python_version >= "3"`, want: true},
+		{marker: `"linux" in sys_platform`, want: true},
+		{marker: `"linux" not in sys_platform`, want: false},
+		{marker: `extra == "tests"`, want: false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.marker, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := pep508.Evaluate(tt.marker, env)
+
+			if err != nil {
+				t.Fatalf("Evaluate() unexpected error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluate_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, marker := range []string{
+		`python_version`,
+		`python_version <`,
+		`python_version < "3.9`,
+		`unknown_variable == "1"`,
+		`(python_version < "3.9"`,
+		`python_version < "3.9")`,
+		`python_version < "3.9" and`,
+		`python_version < "3.9" "3.8"`,
+	} {
+		_, err := pep508.Evaluate(marker, pep508.Environment{})
+
+		if !errors.Is(err, pep508.ErrInvalidMarker) {
+			t.Errorf("Evaluate(%q) expected \"%v\" error but got \"%v\"", marker, pep508.ErrInvalidMarker, err)
+		}
+	}
+}
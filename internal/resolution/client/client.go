@@ -14,12 +14,14 @@ type ResolutionClient struct {
 
 type DependencyClient interface {
 	resolve.Client
-	// WriteCache writes a manifest-specific resolution cache.
-	WriteCache(filepath string) error
-	// LoadCache loads a manifest-specific resolution cache.
-	LoadCache(filepath string) error
-	// PreFetch loads cache, then makes and caches likely queries needed for resolving a package with a list of requirements
-	PreFetch(ctx context.Context, requirements []resolve.RequirementVersion, manifestPath string)
+	// WriteCache writes a manifest-specific resolution cache to the path, with a client-specific
+	// extension added, which is usually from cachedir.ResolutionCachePath.
+	WriteCache(path string) error
+	// LoadCache loads a manifest-specific resolution cache from the path, with a client-specific
+	// extension added, which is usually from cachedir.ResolutionCachePath.
+	LoadCache(path string) error
+	// PreFetch loads the cache at cachePath, then makes and caches likely queries needed for resolving a package with a list of requirements
+	PreFetch(ctx context.Context, requirements []resolve.RequirementVersion, cachePath string)
 }
 
 type VulnerabilityClient interface {
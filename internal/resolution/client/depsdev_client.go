@@ -27,9 +27,9 @@ func NewDepsDevClient(addr string) (*DepsDevClient, error) {
 	return &DepsDevClient{APIClient: *resolve.NewAPIClient(c), c: c}, nil
 }
 
-func (d *DepsDevClient) PreFetch(ctx context.Context, requirements []resolve.RequirementVersion, manifestPath string) {
+func (d *DepsDevClient) PreFetch(ctx context.Context, requirements []resolve.RequirementVersion, cachePath string) {
 	// It doesn't matter if loading the cache fails
-	_ = d.LoadCache(manifestPath)
+	_ = d.LoadCache(cachePath)
 
 	// Use the deps.dev client to fetch complete dependency graphs of the direct requirements
 	for _, im := range requirements {
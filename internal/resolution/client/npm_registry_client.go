@@ -2,7 +2,6 @@ package client
 
 import (
 	"context"
-	"crypto/x509"
 	"encoding/gob"
 	"fmt"
 	"os"
@@ -14,6 +13,7 @@ import (
 	"deps.dev/util/resolve/dep"
 	"deps.dev/util/semver"
 	"github.com/google/osv-scanner/internal/resolution/datasource"
+	"github.com/google/osv-scanner/internal/tlsconfig"
 	"github.com/google/osv-scanner/pkg/depsdev"
 	"github.com/google/osv-scanner/pkg/osv"
 	"google.golang.org/grpc"
@@ -36,11 +36,7 @@ func NewNpmRegistryClient(workdir string) (*NpmRegistryClient, error) {
 		return nil, err
 	}
 
-	certPool, err := x509.SystemCertPool()
-	if err != nil {
-		return nil, fmt.Errorf("getting system cert pool: %w", err)
-	}
-	creds := credentials.NewClientTLSFromCert(certPool, "")
+	creds := credentials.NewTLS(tlsconfig.Config())
 	dialOpts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
 
 	if osv.RequestUserAgent != "" {
@@ -229,9 +225,9 @@ func isNpmBundle(pk resolve.PackageKey) bool {
 	return strings.Contains(pk.Name, ">")
 }
 
-func (c *NpmRegistryClient) PreFetch(ctx context.Context, imports []resolve.RequirementVersion, manifestPath string) {
+func (c *NpmRegistryClient) PreFetch(ctx context.Context, imports []resolve.RequirementVersion, cachePath string) {
 	// It doesn't matter if loading the cache fails
-	_ = c.LoadCache(manifestPath)
+	_ = c.LoadCache(cachePath)
 
 	// Use the deps.dev client to fetch complete dependency graphs of our direct imports
 	for _, im := range imports {
@@ -2,12 +2,12 @@ package datasource
 
 import (
 	"context"
-	"crypto/x509"
 	"fmt"
 	"sync"
 	"time"
 
 	pb "deps.dev/api/v3"
+	"github.com/google/osv-scanner/internal/tlsconfig"
 	"github.com/google/osv-scanner/pkg/osv"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -53,11 +53,7 @@ func makeVersionKey(k *pb.VersionKey) versionKey {
 }
 
 func NewDepsDevAPIClient(addr string) (*DepsDevAPIClient, error) {
-	certPool, err := x509.SystemCertPool()
-	if err != nil {
-		return nil, fmt.Errorf("getting system cert pool: %w", err)
-	}
-	creds := credentials.NewClientTLSFromCert(certPool, "")
+	creds := credentials.NewTLS(tlsconfig.Config())
 	dialOpts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
 
 	if osv.RequestUserAgent != "" {
@@ -0,0 +1,75 @@
+package datasource
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"strings"
+)
+
+const (
+	MavenAuthSchemeBearer = "Bearer"
+	MavenAuthSchemeBasic  = "Basic"
+)
+
+// MavenCredentialHelper gets the credentials for requests to a Maven registry
+// by running a command that prints them to stdout, similar to a docker credential helper.
+//
+// The command is run each time a request is made so that short-lived credentials can
+// be used, and the credentials it prints are never stored.
+type MavenCredentialHelper struct {
+	// Registry is the base URL of the registry that the credentials are for
+	Registry string
+	// Command is the executable to run, followed by any arguments to pass to it
+	Command []string
+	// Scheme is either MavenAuthSchemeBearer for a token (the default),
+	// or MavenAuthSchemeBasic for a "username:password" pair
+	Scheme string
+}
+
+// appliesTo returns true if requests to the given url should use the credentials from this helper
+func (h MavenCredentialHelper) appliesTo(u string) bool {
+	registry := strings.TrimSuffix(h.Registry, "/")
+
+	return u == registry || strings.HasPrefix(u, registry+"/")
+}
+
+// AddToHeader runs the helper and sets the Authorization header using the credentials it prints
+func (h MavenCredentialHelper) AddToHeader(ctx context.Context, header http.Header) error {
+	if len(h.Command) == 0 {
+		return errors.New("no command configured for Maven credential helper")
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, h.Command[0], h.Command[1:]...)
+	cmd.Stderr = &stderr
+
+	// the output is deliberately left out of any errors, as it may contain the credentials
+	out, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("failed to run Maven credential helper %s: %w: %s", h.Command[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	creds := strings.TrimSpace(string(out))
+	if creds == "" {
+		return fmt.Errorf("credential helper %s did not print any credentials", h.Command[0])
+	}
+
+	switch h.Scheme {
+	case "", MavenAuthSchemeBearer:
+		header.Set("Authorization", "Bearer "+creds)
+	case MavenAuthSchemeBasic:
+		if !strings.Contains(creds, ":") {
+			return fmt.Errorf("credential helper %s did not print credentials in the form username:password", h.Command[0])
+		}
+		header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(creds)))
+	default:
+		return fmt.Errorf("unsupported Maven auth scheme: %s", h.Scheme)
+	}
+
+	return nil
+}
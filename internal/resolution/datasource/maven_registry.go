@@ -3,27 +3,92 @@ package datasource
 import (
 	"context"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
 
 	"deps.dev/util/maven"
-	"github.com/google/osv-scanner/pkg/osvscanner"
 )
 
+// ErrAPIFailed describes errors related to querying API endpoints.
+//
+// This is re-exported as osvscanner.ErrAPIFailed, which cannot be used here
+// directly as osvscanner depends on this package to resolve manifests.
+var ErrAPIFailed = errors.New("API query failed")
+
 const MavenCentral = "https://repo.maven.apache.org/maven2"
 
+// errMavenProjectNotFound is returned when a registry does not have a project,
+// in which case the next registry is tried
+var errMavenProjectNotFound = errors.New("project not found")
+
 type MavenRegistryAPIClient struct {
-	registry string // Base URL of the registry that we are making requests
+	registries        []string                // Base URLs of the registries that we are making requests, in the order they are tried
+	credentialHelpers []MavenCredentialHelper // Helpers to get the credentials for requests to registries that need them
+}
+
+// NewMavenRegistryAPIClient returns a client that fetches projects from the first of
+// the registries that has them, which defaults to Maven Central if none are given
+func NewMavenRegistryAPIClient(registries ...string) *MavenRegistryAPIClient {
+	if len(registries) == 0 {
+		registries = []string{MavenCentral}
+	}
+
+	return &MavenRegistryAPIClient{registries: registries}
 }
 
-func NewMavenRegistryAPIClient(registry string) *MavenRegistryAPIClient {
-	return &MavenRegistryAPIClient{registry: registry}
+// SetCredentialHelpers sets the helpers used to authenticate requests,
+// with the first helper that applies to a request being used for it
+func (m *MavenRegistryAPIClient) SetCredentialHelpers(helpers []MavenCredentialHelper) {
+	m.credentialHelpers = helpers
 }
 
+// WithRegistries returns a copy of the client that falls back to the given registries,
+// such as those declared by a project, after its own, skipping any that it already has
+func (m *MavenRegistryAPIClient) WithRegistries(registries ...string) *MavenRegistryAPIClient {
+	client := &MavenRegistryAPIClient{
+		registries:        slices.Clone(m.registries),
+		credentialHelpers: m.credentialHelpers,
+	}
+
+	for _, registry := range registries {
+		if !slices.ContainsFunc(client.registries, func(r string) bool {
+			return strings.TrimSuffix(r, "/") == strings.TrimSuffix(registry, "/")
+		}) {
+			client.registries = append(client.registries, registry)
+		}
+	}
+
+	return client
+}
+
+// GetProject fetches the project from each of the registries in turn until one of them has it,
+// returning the errors from all of them if none do.
+//
+// Only registries that do not have the project are skipped, with any other error
+// (such as the registry being unreachable) being returned straight away.
 func (m *MavenRegistryAPIClient) GetProject(ctx context.Context, groupID, artifactID, version string) (maven.Project, error) {
-	u, err := url.JoinPath(m.registry, strings.ReplaceAll(groupID, ".", "/"), artifactID, version, fmt.Sprintf("%s-%s.pom", artifactID, version))
+	errs := make([]error, 0, len(m.registries))
+	for _, registry := range m.registries {
+		proj, err := m.getProject(ctx, registry, groupID, artifactID, version)
+		if err == nil {
+			return proj, nil
+		}
+		if ctx.Err() != nil || !errors.Is(err, errMavenProjectNotFound) {
+			return maven.Project{}, err
+		}
+
+		errs = append(errs, err)
+	}
+
+	return maven.Project{}, errors.Join(errs...)
+}
+
+func (m *MavenRegistryAPIClient) getProject(ctx context.Context, registry, groupID, artifactID, version string) (maven.Project, error) {
+	u, err := url.JoinPath(registry, strings.ReplaceAll(groupID, ".", "/"), artifactID, version, fmt.Sprintf("%s-%s.pom", artifactID, version))
 	if err != nil {
 		return maven.Project{}, fmt.Errorf("failed to join path: %w", err)
 	}
@@ -33,14 +98,27 @@ func (m *MavenRegistryAPIClient) GetProject(ctx context.Context, groupID, artifa
 		return maven.Project{}, fmt.Errorf("failed to make new request: %w", err)
 	}
 
+	for _, helper := range m.credentialHelpers {
+		if helper.appliesTo(u) {
+			if err := helper.AddToHeader(ctx, req.Header); err != nil {
+				return maven.Project{}, err
+			}
+
+			break
+		}
+	}
+
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return maven.Project{}, fmt.Errorf("%w: Maven registry query failed: %w", osvscanner.ErrAPIFailed, err)
+		return maven.Project{}, fmt.Errorf("%w: Maven registry query failed: %w", ErrAPIFailed, err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound {
+		return maven.Project{}, fmt.Errorf("%w: %w in Maven registry %s", ErrAPIFailed, errMavenProjectNotFound, registry)
+	}
 	if resp.StatusCode != http.StatusOK {
-		return maven.Project{}, fmt.Errorf("%w: Maven registry query status: %s", osvscanner.ErrAPIFailed, resp.Status)
+		return maven.Project{}, fmt.Errorf("%w: Maven registry %s query status: %s", ErrAPIFailed, registry, resp.Status)
 	}
 
 	var proj maven.Project
@@ -2,7 +2,9 @@ package datasource
 
 import (
 	"context"
+	"errors"
 	"reflect"
+	"runtime"
 	"testing"
 
 	"deps.dev/util/maven"
@@ -14,7 +16,7 @@ func TestGetProject(t *testing.T) {
 
 	srv := testutility.NewMockHTTPServer(t)
 	client := &MavenRegistryAPIClient{
-		registry: srv.URL,
+		registries: []string{srv.URL},
 	}
 	srv.SetResponse(t, "org/example/x.y.z/1.0.0/x.y.z-1.0.0.pom", []byte(`
 	<project>
@@ -39,3 +41,137 @@ func TestGetProject(t *testing.T) {
 		t.Errorf("GetProject(%s, %s, %s):\ngot %v\nwant %v\n", "org.example", "x.y.z", "1.0.0", got, want)
 	}
 }
+
+func TestGetProject_FallbackRegistries(t *testing.T) {
+	t.Parallel()
+
+	mirror := testutility.NewMockHTTPServer(t)
+	mirror.SetResponse(t, "org/example/mirrored/1.0.0/mirrored-1.0.0.pom", []byte(`
+	<project>
+	  <groupId>org.example</groupId>
+	  <artifactId>mirrored</artifactId>
+	  <version>1.0.0</version>
+	  <name>from the mirror</name>
+	</project>
+	`))
+	upstream := testutility.NewMockHTTPServer(t)
+	upstream.SetResponse(t, "org/example/mirrored/1.0.0/mirrored-1.0.0.pom", []byte(`
+	<project>
+	  <groupId>org.example</groupId>
+	  <artifactId>mirrored</artifactId>
+	  <version>1.0.0</version>
+	  <name>from upstream</name>
+	</project>
+	`))
+	upstream.SetResponse(t, "org/example/upstream-only/1.0.0/upstream-only-1.0.0.pom", []byte(`
+	<project>
+	  <groupId>org.example</groupId>
+	  <artifactId>upstream-only</artifactId>
+	  <version>1.0.0</version>
+	</project>
+	`))
+
+	client := NewMavenRegistryAPIClient(mirror.URL, upstream.URL)
+
+	got, err := client.GetProject(context.Background(), "org.example", "mirrored", "1.0.0")
+	if err != nil {
+		t.Fatalf("failed to get Maven project: %v", err)
+	}
+	if got.Name != "from the mirror" {
+		t.Errorf("expected the project to come from the first registry that has it, got %q", got.Name)
+	}
+
+	if _, err := client.GetProject(context.Background(), "org.example", "upstream-only", "1.0.0"); err != nil {
+		t.Errorf("expected the project to be fetched from the second registry: %v", err)
+	}
+
+	_, err = client.GetProject(context.Background(), "org.example", "missing", "1.0.0")
+	if !errors.Is(err, ErrAPIFailed) {
+		t.Errorf("expected project missing from every registry to fail with ErrAPIFailed, got %v", err)
+	}
+}
+
+func TestGetProject_CredentialHelper(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("credential helper test uses echo, which is not an executable on Windows")
+	}
+
+	srv := testutility.NewMockHTTPServer(t)
+	srv.SetAuthorization(t, "Bearer short-lived-token")
+	srv.SetResponse(t, "org/example/x.y.z/1.0.0/x.y.z-1.0.0.pom", []byte(`
+	<project>
+	  <groupId>org.example</groupId>
+	  <artifactId>x.y.z</artifactId>
+	  <version>1.0.0</version>
+	</project>
+	`))
+
+	client := NewMavenRegistryAPIClient(srv.URL)
+	client.SetCredentialHelpers([]MavenCredentialHelper{
+		{Registry: "https://repo.example.com/maven2", Command: []string{"echo", "wrong-token"}},
+		{Registry: srv.URL + "/", Command: []string{"echo", "short-lived-token"}},
+	})
+
+	if _, err := client.GetProject(context.Background(), "org.example", "x.y.z", "1.0.0"); err != nil {
+		t.Fatalf("failed to get Maven project %s:%s verion %s: %v", "org.example", "x.y.z", "1.0.0", err)
+	}
+
+	client.SetCredentialHelpers([]MavenCredentialHelper{
+		{Registry: srv.URL, Command: []string{"echo", "user:pass"}, Scheme: MavenAuthSchemeBasic},
+	})
+
+	if _, err := client.GetProject(context.Background(), "org.example", "x.y.z", "1.0.0"); err == nil {
+		t.Errorf("expected request with the wrong credentials to fail")
+	}
+}
+
+func TestGetProject_ErrorsAreNotFallenThrough(t *testing.T) {
+	t.Parallel()
+
+	unauthorized := testutility.NewMockHTTPServer(t)
+	unauthorized.SetAuthorization(t, "Bearer token")
+	fallback := testutility.NewMockHTTPServer(t)
+	fallback.SetResponse(t, "org/example/x.y.z/1.0.0/x.y.z-1.0.0.pom", []byte(`
+	<project>
+	  <groupId>org.example</groupId>
+	  <artifactId>x.y.z</artifactId>
+	  <version>1.0.0</version>
+	</project>
+	`))
+
+	client := NewMavenRegistryAPIClient(unauthorized.URL, fallback.URL)
+
+	_, err := client.GetProject(context.Background(), "org.example", "x.y.z", "1.0.0")
+	if !errors.Is(err, ErrAPIFailed) {
+		t.Errorf("expected the error from the first registry to be returned, got %v", err)
+	}
+}
+
+func TestWithRegistries(t *testing.T) {
+	t.Parallel()
+
+	declared := testutility.NewMockHTTPServer(t)
+	declared.SetResponse(t, "org/example/x.y.z/1.0.0/x.y.z-1.0.0.pom", []byte(`
+	<project>
+	  <groupId>org.example</groupId>
+	  <artifactId>x.y.z</artifactId>
+	  <version>1.0.0</version>
+	</project>
+	`))
+	configured := testutility.NewMockHTTPServer(t)
+
+	client := NewMavenRegistryAPIClient(configured.URL)
+	withDeclared := client.WithRegistries(configured.URL+"/", declared.URL)
+
+	if want := []string{configured.URL, declared.URL}; !reflect.DeepEqual(withDeclared.registries, want) {
+		t.Errorf("WithRegistries() registries = %v, want %v", withDeclared.registries, want)
+	}
+	if _, err := withDeclared.GetProject(context.Background(), "org.example", "x.y.z", "1.0.0"); err != nil {
+		t.Errorf("expected the project to be fetched from the declared registry: %v", err)
+	}
+	if _, err := client.GetProject(context.Background(), "org.example", "x.y.z", "1.0.0"); err == nil {
+		t.Errorf("expected the original client to not use the declared registry")
+	}
+}
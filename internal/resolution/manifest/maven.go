@@ -51,12 +51,15 @@ func (m MavenManifestIO) Read(df lockfile.DepFile) (Manifest, error) {
 	ctx := context.Background()
 
 	var reqsWithProps []resolve.RequirementVersion
+	// Dependencies and dependency management are tracked separately, as a dependency
+	// is often declared in one POM but has its version managed by another, such as an imported BOM
 	requirementOrigins := make(map[maven.DependencyKey]string)
-	addRequirementOrigins := func(deps []maven.Dependency, origin string) {
+	managementOrigins := make(map[maven.DependencyKey]string)
+	addRequirementOrigins := func(origins map[maven.DependencyKey]string, deps []maven.Dependency, origin string) {
 		for _, dep := range deps {
 			key := dep.Key()
-			if _, ok := requirementOrigins[key]; !ok {
-				requirementOrigins[key] = origin
+			if _, ok := origins[key]; !ok {
+				origins[key] = origin
 			}
 			if dep.Version.ContainsProperty() {
 				// We only need the original import if the version contains any property.
@@ -65,14 +68,14 @@ func (m MavenManifestIO) Read(df lockfile.DepFile) (Manifest, error) {
 		}
 	}
 	addAllRequirements := func(project maven.Project, origin string) {
-		addRequirementOrigins(project.Dependencies, origin)
-		addRequirementOrigins(project.DependencyManagement.Dependencies, mavenOrigin(origin, OriginManagement))
+		addRequirementOrigins(requirementOrigins, project.Dependencies, origin)
+		addRequirementOrigins(managementOrigins, project.DependencyManagement.Dependencies, mavenOrigin(origin, OriginManagement))
 		for _, profile := range project.Profiles {
-			addRequirementOrigins(profile.Dependencies, mavenOrigin(origin, OriginProfile, string(profile.ID)))
-			addRequirementOrigins(profile.DependencyManagement.Dependencies, mavenOrigin(origin, OriginProfile, string(profile.ID), OriginManagement))
+			addRequirementOrigins(requirementOrigins, profile.Dependencies, mavenOrigin(origin, OriginProfile, string(profile.ID)))
+			addRequirementOrigins(managementOrigins, profile.DependencyManagement.Dependencies, mavenOrigin(origin, OriginProfile, string(profile.ID), OriginManagement))
 		}
 		for _, plugin := range project.Build.PluginManagement.Plugins {
-			addRequirementOrigins(plugin.Dependencies, mavenOrigin(origin, OriginPlugin, plugin.ProjectKey.Name()))
+			addRequirementOrigins(requirementOrigins, plugin.Dependencies, mavenOrigin(origin, OriginPlugin, plugin.ProjectKey.Name()))
 		}
 	}
 
@@ -82,15 +85,20 @@ func (m MavenManifestIO) Read(df lockfile.DepFile) (Manifest, error) {
 	}
 	addAllRequirements(project, "")
 
-	// Merging parents data by parsing local parent pom.xml or fetching from upstream.
+	// Merging parents data by parsing local parent pom.xml or fetching from upstream,
+	// falling back to the repositories declared by the project after the configured registries.
+	m.MavenRegistryAPIClient = *m.WithRegistries(repositoryURLs(project.Repositories)...)
 	if err := m.MergeParents(ctx, &project, project.Parent, 1, df.Path(), addAllRequirements, OriginParent); err != nil {
 		return Manifest{}, fmt.Errorf("failed to merge parents: %w", err)
 	}
+	// Imported BOMs can also be fetched from the repositories declared by the parents.
+	m.MavenRegistryAPIClient = *m.WithRegistries(repositoryURLs(project.Repositories)...)
 
 	// Process the dependencies:
 	//  - dedupe dependencies and dependency management
-	//  - import dependency management (not yet transitively)
-	//  - fill in missing dependency version requirement
+	//  - import dependency management, including from BOMs imported by other BOMs
+	//  - fill in missing dependency version requirement, with the dependency management
+	//    of the project taking precedence over anything it imports
 	project.ProcessDependencies(func(groupID, artifactID, version maven.String) (maven.DependencyManagement, error) {
 		root := maven.Parent{ProjectKey: maven.ProjectKey{GroupID: groupID, ArtifactID: artifactID, Version: version}}
 		var result maven.Project
@@ -113,9 +121,9 @@ func (m MavenManifestIO) Read(df lockfile.DepFile) (Manifest, error) {
 	var requirements []resolve.RequirementVersion
 	var otherRequirements []resolve.RequirementVersion
 	groups := make(map[resolve.PackageKey][]string)
-	addRequirements := func(deps []maven.Dependency) {
+	addRequirements := func(origins map[maven.DependencyKey]string, deps []maven.Dependency) {
 		for _, dep := range deps {
-			origin := requirementOrigins[dep.Key()]
+			origin := origins[dep.Key()]
 			if strings.HasPrefix(origin, OriginParent+"@") || strings.HasPrefix(origin, OriginImport) {
 				otherRequirements = append(otherRequirements, makeRequirementVersion(dep, origin))
 			} else {
@@ -144,11 +152,11 @@ func (m MavenManifestIO) Read(df lockfile.DepFile) (Manifest, error) {
 			Type: resolve.MavenDepType(maven.Dependency{}, OriginParent),
 		})
 	}
-	addRequirements(project.Dependencies)
-	addRequirements(project.DependencyManagement.Dependencies)
+	addRequirements(requirementOrigins, project.Dependencies)
+	addRequirements(managementOrigins, project.DependencyManagement.Dependencies)
 	for _, profile := range project.Profiles {
-		addRequirements(profile.Dependencies)
-		addRequirements(profile.DependencyManagement.Dependencies)
+		addRequirements(requirementOrigins, profile.Dependencies)
+		addRequirements(managementOrigins, profile.DependencyManagement.Dependencies)
 		for _, prop := range profile.Properties.Properties {
 			properties = append(properties, PropertyWithOrigin{
 				Property: prop,
@@ -157,7 +165,7 @@ func (m MavenManifestIO) Read(df lockfile.DepFile) (Manifest, error) {
 		}
 	}
 	for _, plugin := range project.Build.PluginManagement.Plugins {
-		addRequirements(plugin.Dependencies)
+		addRequirements(requirementOrigins, plugin.Dependencies)
 	}
 
 	return Manifest{
@@ -183,11 +191,28 @@ func (m MavenManifestIO) Read(df lockfile.DepFile) (Manifest, error) {
 }
 
 // To avoid indefinite loop when fetching parents,
+// repositoryURLs returns the URLs of the repositories that can be fetched from,
+// skipping those with properties that have not been interpolated
+func repositoryURLs(repositories []maven.Repository) []string {
+	urls := make([]string, 0, len(repositories))
+	for _, repo := range repositories {
+		if repo.URL == "" || repo.URL.ContainsProperty() {
+			continue
+		}
+		urls = append(urls, string(repo.URL))
+	}
+
+	return urls
+}
+
 // set a limit on the number of parents.
 const MaxParent = 100
 
 func (m MavenManifestIO) MergeParents(ctx context.Context, result *maven.Project, current maven.Parent, start int, path string, addRequirements func(maven.Project, string), prefix string) error {
 	visited := make(map[maven.ProjectKey]bool, MaxParent)
+	// Only projects that are read locally can have a local parent, as the relative
+	// path in a project fetched from a registry means nothing on this machine.
+	isLocal := true
 	for n := start; n < MaxParent; n++ {
 		if current.GroupID == "" || current.ArtifactID == "" || current.Version == "" {
 			break
@@ -199,15 +224,19 @@ func (m MavenManifestIO) MergeParents(ctx context.Context, result *maven.Project
 		visited[current.ProjectKey] = true
 
 		var proj maven.Project
-		if current.RelativePath != "" {
-			f, err := os.Open(filepath.Join(filepath.Dir(path), string(current.RelativePath)))
+		if isLocal && current.RelativePath != "" {
+			path = filepath.Join(filepath.Dir(path), string(current.RelativePath))
+			f, err := os.Open(path)
 			if err != nil {
 				return fmt.Errorf("failed to open parent file %s: %w", current.RelativePath, err)
 			}
-			if err := xml.NewDecoder(f).Decode(&proj); err != nil {
+			err = xml.NewDecoder(f).Decode(&proj)
+			f.Close()
+			if err != nil {
 				return fmt.Errorf("failed to unmarshal project: %w", err)
 			}
 		} else {
+			isLocal = false
 			var err error
 			proj, err = m.MavenRegistryAPIClient.GetProject(ctx, string(current.GroupID), string(current.ArtifactID), string(current.Version))
 			if err != nil {
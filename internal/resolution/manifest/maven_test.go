@@ -402,3 +402,148 @@ func TestMavenWrite(t *testing.T) {
 	}
 	testutility.NewSnapshot().WithCRLFReplacement().MatchText(t, buf.String())
 }
+
+func TestMavenRead_ImportedBOMs(t *testing.T) {
+	t.Parallel()
+
+	srv := testutility.NewMockHTTPServer(t)
+	srv.SetResponse(t, "org/bom/bom-parent/1.0.0/bom-parent-1.0.0.pom", []byte(`
+	<project>
+	  <groupId>org.bom</groupId>
+	  <artifactId>bom-parent</artifactId>
+	  <version>1.0.0</version>
+	  <packaging>pom</packaging>
+	  <properties>
+		  <abc.version>1.2.3</abc.version>
+		  <nested.version>2.0.0</nested.version>
+	  </properties>
+	</project>
+	`))
+	srv.SetResponse(t, "org/bom/bom/1.0.0/bom-1.0.0.pom", []byte(`
+	<project>
+	  <parent>
+		  <groupId>org.bom</groupId>
+		  <artifactId>bom-parent</artifactId>
+		  <version>1.0.0</version>
+		  <relativePath>../bom-parent</relativePath>
+	  </parent>
+	  <artifactId>bom</artifactId>
+	  <packaging>pom</packaging>
+	  <dependencyManagement>
+		  <dependencies>
+		    <dependency>
+			    <groupId>org.example</groupId>
+			    <artifactId>abc</artifactId>
+			    <version>${abc.version}</version>
+		    </dependency>
+		    <dependency>
+			    <groupId>org.example</groupId>
+			    <artifactId>ghi</artifactId>
+			    <version>9.9.9</version>
+		    </dependency>
+		    <dependency>
+			    <groupId>org.bom</groupId>
+			    <artifactId>nested-bom</artifactId>
+			    <version>${nested.version}</version>
+			    <type>pom</type>
+			    <scope>import</scope>
+		    </dependency>
+		  </dependencies>
+	  </dependencyManagement>
+	</project>
+	`))
+	srv.SetResponse(t, "org/bom/nested-bom/2.0.0/nested-bom-2.0.0.pom", []byte(`
+	<project>
+	  <groupId>org.bom</groupId>
+	  <artifactId>nested-bom</artifactId>
+	  <version>2.0.0</version>
+	  <packaging>pom</packaging>
+	  <dependencyManagement>
+		  <dependencies>
+		    <dependency>
+			    <groupId>org.example</groupId>
+			    <artifactId>def</artifactId>
+			    <version>4.5.6</version>
+		    </dependency>
+		  </dependencies>
+	  </dependencyManagement>
+	</project>
+	`))
+
+	dir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get current directory: %v", err)
+	}
+	df, err := lockfile.OpenLocalDepFile(filepath.Join(dir, "fixtures", "bom", "pom.xml"))
+	if err != nil {
+		t.Fatalf("failed to open file: %v", err)
+	}
+	defer df.Close()
+
+	mavenIO := manifest.MavenManifestIO{
+		MavenRegistryAPIClient: *datasource.NewMavenRegistryAPIClient(srv.URL),
+	}
+
+	got, err := mavenIO.Read(df)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	got.FilePath = ""
+
+	depBOMMgmt := depTypeWithOrigin("import@org.bom:bom@management")
+	depNestedBOMMgmt := depTypeWithOrigin("import@org.bom:nested-bom@management")
+	depNestedBOMImport := depTypeWithOrigin("import@org.bom:bom@management")
+	depNestedBOMImport.AddAttr(dep.MavenArtifactType, "pom")
+	depNestedBOMImport.AddAttr(dep.Scope, "import")
+
+	requirement := func(name, version string, typ dep.Type) resolve.RequirementVersion {
+		return resolve.RequirementVersion{
+			VersionKey: resolve.VersionKey{
+				PackageKey: resolve.PackageKey{
+					System: resolve.Maven,
+					Name:   name,
+				},
+				VersionType: resolve.Requirement,
+				Version:     version,
+			},
+			Type: typ,
+		}
+	}
+
+	want := manifest.Manifest{
+		Root: resolve.Version{
+			VersionKey: resolve.VersionKey{
+				PackageKey: resolve.PackageKey{
+					System: resolve.Maven,
+					Name:   "com.mycompany.app:my-app",
+				},
+				VersionType: resolve.Concrete,
+				Version:     "1.0",
+			},
+		},
+		Requirements: []resolve.RequirementVersion{
+			// managed by the imported BOM, with a property from the parent of the BOM
+			requirement("org.example:abc", "1.2.3", dep.Type{}),
+			// managed by a BOM that is imported by the imported BOM
+			requirement("org.example:def", "4.5.6", dep.Type{}),
+			// managed by both the project and the imported BOM, with the project taking precedence
+			requirement("org.example:ghi", "3.0.0", dep.Type{}),
+			requirement("org.example:ghi", "3.0.0", depMgmt),
+		},
+		Groups: map[resolve.PackageKey][]string{},
+		EcosystemSpecific: manifest.MavenManifestSpecific{
+			Properties: []manifest.PropertyWithOrigin{},
+			RequirementsWithProperties: []resolve.RequirementVersion{
+				requirement("org.example:abc", "${abc.version}", depBOMMgmt),
+				requirement("org.bom:nested-bom", "${nested.version}", depNestedBOMImport),
+			},
+			RequirementsFromOtherPOMs: []resolve.RequirementVersion{
+				requirement("org.example:abc", "1.2.3", depBOMMgmt),
+				requirement("org.example:def", "4.5.6", depNestedBOMMgmt),
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Maven manifest mismatch:\ngot %v\nwant %v\n", got, want)
+	}
+}
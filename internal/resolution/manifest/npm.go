@@ -30,6 +30,10 @@ type PackageJSON struct {
 	// These fields are currently only used when parsing package-lock.json
 	PeerDependencies map[string]string `json:"peerDependencies"`
 	// BundleDependencies   []string          `json:"bundleDependencies"`
+
+	// Overrides are the npm overrides, and Resolutions are the yarn equivalent
+	Overrides   map[string]any    `json:"overrides"`
+	Resolutions map[string]string `json:"resolutions"`
 }
 
 func (rw NpmManifestIO) Read(f lockfile.DepFile) (Manifest, error) {
@@ -147,6 +151,20 @@ func (rw NpmManifestIO) Read(f lockfile.DepFile) (Manifest, error) {
 
 	resolve.SortDependencies(manif.Requirements)
 
+	// "$name" overrides refer to the direct dependency with that name, with
+	// the same precedence as when they conflict in the requirements
+	direct := make(map[string]string)
+	for _, deps := range []map[string]string{packagejson.Dependencies, packagejson.OptionalDependencies, packagejson.DevDependencies} {
+		for pkg, ver := range deps {
+			direct[pkg] = ver
+		}
+	}
+	overrides := parseNpmOverrides(packagejson.Overrides, direct, nil)
+	overrides = append(overrides, parseYarnResolutions(packagejson.Resolutions)...)
+	if len(overrides) > 0 {
+		manif.EcosystemSpecific = NpmManifestSpecific{Overrides: overrides}
+	}
+
 	// resolve workspaces after regular requirements
 	for i, m := range manif.LocalManifests {
 		imp, ok := workspaceReqVers[m.Root.PackageKey]
@@ -0,0 +1,114 @@
+package manifest
+
+import (
+	"slices"
+	"strings"
+
+	"golang.org/x/exp/maps"
+)
+
+// NpmManifestSpecific is the npm-specific information of a package.json
+type NpmManifestSpecific struct {
+	// Overrides are the npm overrides and yarn resolutions of the package.json,
+	// which replace the requirements of the packages that are depended upon
+	Overrides []NpmOverride
+}
+
+// NpmOverrideSelector selects a package by its name and, if Version is not empty,
+// the versions of it that satisfy the Version constraint
+type NpmOverrideSelector struct {
+	Name    string
+	Version string
+}
+
+// parseNpmOverrideSelector parses a key of the overrides such as "foo", "foo@^1.0.0", or "@scope/foo@1.x"
+func parseNpmOverrideSelector(key string) NpmOverrideSelector {
+	if i := strings.LastIndex(key, "@"); i > 0 {
+		return NpmOverrideSelector{Name: key[:i], Version: key[i+1:]}
+	}
+
+	return NpmOverrideSelector{Name: key}
+}
+
+// NpmOverride replaces the requirement on the last package of the Path with Version,
+// when the package is required within the dependencies of the packages before it.
+type NpmOverride struct {
+	// Path is the package being overridden, preceded by the packages that it has
+	// to be depended upon through (outermost first) for the override to apply
+	Path []NpmOverrideSelector
+	// Version is the requirement to use instead, which may be an alias such as "npm:pkg@1.0.0"
+	Version string
+}
+
+// parseNpmOverrides parses the overrides of the package.json, which are either a version or
+// an object of nested overrides that only apply to the dependencies of the package of its key,
+// with the "." key overriding the package itself.
+//
+// Versions starting with "$" refer to the version of the direct dependency that they name.
+func parseNpmOverrides(overrides map[string]any, direct map[string]string, parents []NpmOverrideSelector) []NpmOverride {
+	var parsed []NpmOverride
+	add := func(path []NpmOverrideSelector, version string) {
+		if name, ok := strings.CutPrefix(version, "$"); ok {
+			version, ok = direct[name]
+			if !ok {
+				return
+			}
+		}
+		parsed = append(parsed, NpmOverride{Path: path, Version: version})
+	}
+
+	// sort the keys so that overrides of the same package are always applied in the same order
+	keys := maps.Keys(overrides)
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		switch value := overrides[key].(type) {
+		case string:
+			if key == "." {
+				if len(parents) > 0 {
+					add(parents, value)
+				}
+
+				continue
+			}
+			add(append(slices.Clone(parents), parseNpmOverrideSelector(key)), value)
+		case map[string]any:
+			parsed = append(parsed, parseNpmOverrides(value, direct, append(slices.Clone(parents), parseNpmOverrideSelector(key)))...)
+		}
+	}
+
+	return parsed
+}
+
+// parseYarnResolutions parses the resolutions of the package.json, whose keys are a path of
+// package names separated by "/", such as "foo", "**/foo", "bar/foo", or "bar/**/@scope/foo".
+//
+// Resolutions are applied like npm overrides, so a resolution of "bar/foo" also applies to
+// any package named foo within the dependencies of bar, not only those that bar depends on directly.
+func parseYarnResolutions(resolutions map[string]string) []NpmOverride {
+	keys := maps.Keys(resolutions)
+	slices.Sort(keys)
+
+	var parsed []NpmOverride
+	for _, key := range keys {
+		var path []NpmOverrideSelector
+		segments := strings.Split(key, "/")
+		for i := 0; i < len(segments); i++ {
+			segment := segments[i]
+			if segment == "**" || segment == "" {
+				continue
+			}
+			if strings.HasPrefix(segment, "@") && i+1 < len(segments) {
+				i++
+				segment += "/" + segments[i]
+			}
+			path = append(path, parseNpmOverrideSelector(segment))
+		}
+
+		if len(path) > 0 {
+			parsed = append(parsed, NpmOverride{Path: path, Version: resolutions[key]})
+		}
+	}
+
+	return parsed
+}
@@ -195,6 +195,54 @@ func TestNpmWorkspaceRead(t *testing.T) {
 	}
 }
 
+func TestNpmReadOverrides(t *testing.T) {
+	t.Parallel()
+
+	df, err := lockfile.OpenLocalDepFile("./fixtures/npm-overrides/package.json")
+	if err != nil {
+		t.Fatalf("failed to open file: %v", err)
+	}
+	defer df.Close()
+
+	npmIO := manifest.NpmManifestIO{}
+	got, err := npmIO.Read(df)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+
+	want := manifest.NpmManifestSpecific{
+		Overrides: []manifest.NpmOverride{
+			{
+				Path:    []manifest.NpmOverrideSelector{{Name: "bar"}},
+				Version: "2.0.0",
+			},
+			{
+				Path:    []manifest.NpmOverrideSelector{{Name: "foo"}},
+				Version: "1.2.3",
+			},
+			{
+				Path:    []manifest.NpmOverrideSelector{{Name: "foo"}, {Name: "@scope/baz", Version: "^1.0.0"}},
+				Version: "npm:baz-fork@1.0.1",
+			},
+			{
+				Path:    []manifest.NpmOverrideSelector{{Name: "quux"}},
+				Version: "^3.0.0",
+			},
+			{
+				Path:    []manifest.NpmOverrideSelector{{Name: "corge"}},
+				Version: "4.0.0",
+			},
+			{
+				Path:    []manifest.NpmOverrideSelector{{Name: "grault"}, {Name: "@scope/garply"}},
+				Version: "5.0.0",
+			},
+		},
+	}
+	if !reflect.DeepEqual(got.EcosystemSpecific, want) {
+		t.Errorf("npm overrides mismatch:\ngot %v\nwant %v\n", got.EcosystemSpecific, want)
+	}
+}
+
 func TestNpmWrite(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,126 @@
+package resolution
+
+import (
+	"context"
+	"slices"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+	"deps.dev/util/semver"
+	"github.com/google/osv-scanner/internal/resolution/client"
+	"github.com/google/osv-scanner/internal/resolution/manifest"
+)
+
+// npmOverridesClient wraps a DependencyClient, replacing the requirements of the packages
+// being resolved according to the npm overrides (or yarn resolutions) of a manifest
+//
+// The requirements of a version do not depend on what required it, so an override that only
+// applies within the dependencies of some packages is applied whenever the package is required
+// by the last of them, regardless of what depends on that package in turn.
+type npmOverridesClient struct {
+	client.DependencyClient
+	overrides []manifest.NpmOverride
+	// the manifests themselves, whose direct requirements cannot be overridden
+	manifests map[resolve.VersionKey]bool
+}
+
+func newNpmOverridesClient(c client.DependencyClient, m manifest.Manifest, overrides []manifest.NpmOverride) *npmOverridesClient {
+	manifests := map[resolve.VersionKey]bool{m.Root.VersionKey: true}
+	for _, loc := range m.LocalManifests {
+		manifests[loc.Root.VersionKey] = true
+	}
+
+	return &npmOverridesClient{
+		DependencyClient: c,
+		overrides:        overrides,
+		manifests:        manifests,
+	}
+}
+
+// satisfiesNpmSelector reports if the version satisfies the version constraint of the selector
+func satisfiesNpmSelector(selector manifest.NpmOverrideSelector, version string) bool {
+	if selector.Version == "" {
+		return true
+	}
+
+	c, err := semver.NPM.ParseConstraint(selector.Version)
+	if err != nil {
+		return false
+	}
+
+	return c.Match(version)
+}
+
+// resolvedVersion returns the latest version that the requirement matches, which is what npm resolves it to
+func (c *npmOverridesClient) resolvedVersion(ctx context.Context, req resolve.RequirementVersion) (string, bool) {
+	versions, err := c.DependencyClient.MatchingVersions(ctx, req.VersionKey)
+	if err != nil || len(versions) == 0 {
+		return "", false
+	}
+
+	// the matching versions are sorted, so the last one is the latest
+	return versions[len(versions)-1].Version, true
+}
+
+// findOverride returns the override of the requirement of the version, preferring the overrides
+// that are nested the deepest as they are the most specific
+func (c *npmOverridesClient) findOverride(ctx context.Context, vk resolve.VersionKey, req resolve.RequirementVersion) (manifest.NpmOverride, bool) {
+	var found manifest.NpmOverride
+	for _, o := range c.overrides {
+		if len(o.Path) <= len(found.Path) {
+			continue
+		}
+
+		selector := o.Path[len(o.Path)-1]
+		if selector.Name != req.Name {
+			continue
+		}
+
+		if len(o.Path) > 1 {
+			parent := o.Path[len(o.Path)-2]
+			if parent.Name != vk.Name || !satisfiesNpmSelector(parent, vk.Version) {
+				continue
+			}
+		}
+
+		if selector.Version != "" {
+			version, ok := c.resolvedVersion(ctx, req)
+			if !ok || !satisfiesNpmSelector(selector, version) {
+				continue
+			}
+		}
+
+		found = o
+	}
+
+	return found, len(found.Path) > 0
+}
+
+func (c *npmOverridesClient) Requirements(ctx context.Context, vk resolve.VersionKey) ([]resolve.RequirementVersion, error) {
+	reqs, err := c.DependencyClient.Requirements(ctx, vk)
+	if err != nil || c.manifests[vk] {
+		return reqs, err
+	}
+
+	reqs = slices.Clone(reqs)
+	for i, req := range reqs {
+		o, ok := c.findOverride(ctx, vk, req)
+		if !ok {
+			continue
+		}
+
+		version := o.Version
+		if realPkg, realVer := manifest.SplitNPMAlias(version); realPkg != "" {
+			if !req.Type.HasAttr(dep.KnownAs) {
+				req.Type = req.Type.Clone()
+				req.Type.AddAttr(dep.KnownAs, req.Name)
+			}
+			req.Name = realPkg
+			version = realVer
+		}
+		req.Version = version
+		reqs[i] = req
+	}
+
+	return reqs, nil
+}
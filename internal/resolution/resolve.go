@@ -72,6 +72,9 @@ func Resolve(ctx context.Context, cl client.ResolutionClient, m manifest.Manifes
 		// TODO: may need to do this recursively
 	}
 	cl.DependencyClient = c
+	if specific, ok := m.EcosystemSpecific.(manifest.NpmManifestSpecific); ok && len(specific.Overrides) > 0 {
+		cl.DependencyClient = newNpmOverridesClient(c, m, specific.Overrides)
+	}
 	r, err := getResolver(m.System(), cl.DependencyClient)
 	if err != nil {
 		return nil, err
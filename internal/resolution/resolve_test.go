@@ -70,6 +70,7 @@ func TestResolve(t *testing.T) {
 		system       resolve.System
 		universe     string
 		requirements []requirement
+		overrides    []manifest.NpmOverride
 	}{
 		{
 			name:     "simple", // simple root -> dependency -> vuln
@@ -203,6 +204,33 @@ func TestResolve(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:     "overrides", // npm overrides of transitive dependencies
+			version:  "1.0.0",
+			system:   resolve.NPM,
+			universe: "./fixtures/basic-universe.yaml",
+			requirements: []requirement{
+				{
+					name:    "bad",
+					version: "^1.0.0",
+				},
+				{
+					name:    "existing",
+					version: "^1.0.0",
+				},
+			},
+			overrides: []manifest.NpmOverride{
+				{
+					// only overrides the requirements that resolve to 1.x, and not the direct requirement
+					Path:    []manifest.NpmOverrideSelector{{Name: "bad", Version: "1.x"}},
+					Version: "2.0.0",
+				},
+				{
+					Path:    []manifest.NpmOverrideSelector{{Name: "existing"}, {Name: "dependency"}},
+					Version: "^1.0.0",
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -238,6 +266,9 @@ func TestResolve(t *testing.T) {
 				}
 				m.Groups[pk] = req.groups
 			}
+			if len(tt.overrides) > 0 {
+				m.EcosystemSpecific = manifest.NpmManifestSpecific{Overrides: tt.overrides}
+			}
 
 			res, err := resolution.Resolve(context.Background(), cl, m)
 			if err != nil {
@@ -0,0 +1,72 @@
+// Package tlsconfig manages the TLS configuration that is shared by every
+// connection osv-scanner makes, such as to the OSV API, deps.dev, and registries.
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+)
+
+var (
+	mu     sync.Mutex
+	config = &tls.Config{MinVersion: tls.VersionTLS12}
+)
+
+// Configure sets the TLS configuration that will be used by all connections,
+// including those made with http.DefaultClient.
+//
+// If caCertPath is not empty, the PEM encoded certificates in the file are trusted
+// in addition to the system roots. If insecureSkipVerify is true, certificates
+// are not verified at all, which makes connections vulnerable to interception.
+func Configure(caCertPath string, insecureSkipVerify bool) error {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		//nolint:gosec // this is explicitly opted into by the user
+		InsecureSkipVerify: insecureSkipVerify,
+	}
+
+	if caCertPath != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			return fmt.Errorf("getting system cert pool: %w", err)
+		}
+
+		pem, err := os.ReadFile(caCertPath)
+		if err != nil {
+			return fmt.Errorf("reading CA certificate: %w", err)
+		}
+
+		if !pool.AppendCertsFromPEM(pem) {
+			return errors.New("no valid PEM encoded certificates found in " + caCertPath)
+		}
+
+		cfg.RootCAs = pool
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	config = cfg
+
+	// http.DefaultTransport respects the proxy environment variables (HTTPS_PROXY, NO_PROXY, etc),
+	// so update it in place rather than replacing it to keep that behavior
+	if transport, ok := http.DefaultTransport.(*http.Transport); ok {
+		transport.TLSClientConfig = cfg.Clone()
+	}
+
+	return nil
+}
+
+// Config returns a copy of the current TLS configuration, for use with clients
+// that do not use http.DefaultClient, such as gRPC connections.
+func Config() *tls.Config {
+	mu.Lock()
+	defer mu.Unlock()
+
+	return config.Clone()
+}
@@ -0,0 +1,102 @@
+package tlsconfig_test
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/osv-scanner/internal/tlsconfig"
+)
+
+func writeCert(t *testing.T, srv *httptest.Server) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("could not write certificate: %v", err)
+	}
+
+	return path
+}
+
+func get(t *testing.T, url string) error {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatalf("could not create request: %v", err)
+	}
+
+	// avoid reusing connections between tests
+	req.Close = true
+
+	resp, err := http.DefaultClient.Do(req)
+	if err == nil {
+		resp.Body.Close()
+	}
+
+	return err
+}
+
+// Do not make this test parallel because it modifies the global TLS configuration
+func TestConfigure(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { _ = tlsconfig.Configure("", false) })
+
+	if err := tlsconfig.Configure("", false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := get(t, srv.URL); err == nil {
+		t.Errorf("expected request to untrusted server to fail")
+	}
+
+	if err := tlsconfig.Configure(writeCert(t, srv), false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := get(t, srv.URL); err != nil {
+		t.Errorf("expected request to trusted server to succeed, but got %v", err)
+	}
+
+	if tlsconfig.Config().RootCAs == nil {
+		t.Errorf("expected Config() to include the custom root")
+	}
+}
+
+// Do not make this test parallel because it modifies the global TLS configuration
+func TestConfigure_InsecureSkipVerify(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { _ = tlsconfig.Configure("", false) })
+
+	if err := tlsconfig.Configure("", true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := get(t, srv.URL); err != nil {
+		t.Errorf("expected request to succeed, but got %v", err)
+	}
+}
+
+// Do not make this test parallel because it modifies the global TLS configuration
+func TestConfigure_InvalidCert(t *testing.T) {
+	if err := tlsconfig.Configure(filepath.Join(t.TempDir(), "does-not-exist.pem"), false); err == nil {
+		t.Errorf("expected an error for a missing certificate file")
+	}
+
+	path := filepath.Join(t.TempDir(), "not-a-cert.pem")
+	if err := os.WriteFile(path, []byte("hello world"), 0600); err != nil {
+		t.Fatalf("could not write file: %v", err)
+	}
+
+	if err := tlsconfig.Configure(path, false); err == nil {
+		t.Errorf("expected an error for an invalid certificate file")
+	}
+}
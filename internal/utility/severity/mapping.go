@@ -0,0 +1,95 @@
+package severity
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Threshold is the lowest score that is given the rating
+type Threshold struct {
+	Rating   string
+	MinScore float64
+}
+
+// Mapping maps scores to ratings, with its thresholds ordered from highest to lowest
+type Mapping []Threshold
+
+// DefaultMapping is the qualitative rating scale defined by CVSS, which is the same for all of its versions
+var DefaultMapping = Mapping{
+	{Rating: "Critical", MinScore: 9},
+	{Rating: "High", MinScore: 7},
+	{Rating: "Medium", MinScore: 4},
+	{Rating: "Low", MinScore: 0},
+}
+
+// ParseMapping parses thresholds in the form of "rating:minimum", such as "High:7".
+//
+// A threshold must start at 0 so that every score has a rating.
+func ParseMapping(thresholds []string) (Mapping, error) {
+	mapping := make(Mapping, 0, len(thresholds))
+	for _, threshold := range thresholds {
+		rating, minimum, ok := strings.Cut(threshold, ":")
+		if !ok || rating == "" {
+			return nil, fmt.Errorf("%q is not in the form of rating:minimum", threshold)
+		}
+
+		score, err := strconv.ParseFloat(minimum, 64)
+		if err != nil || score < 0 || score > 10 {
+			return nil, fmt.Errorf("%q does not have a minimum score between 0 and 10", threshold)
+		}
+
+		mapping = append(mapping, Threshold{Rating: rating, MinScore: score})
+	}
+
+	slices.SortStableFunc(mapping, func(a, b Threshold) int {
+		switch {
+		case a.MinScore > b.MinScore:
+			return -1
+		case a.MinScore < b.MinScore:
+			return 1
+		}
+
+		return 0
+	})
+
+	if len(mapping) == 0 || mapping[len(mapping)-1].MinScore != 0 {
+		return nil, errors.New("one of the ratings must have a minimum score of 0")
+	}
+
+	return mapping, nil
+}
+
+// Rating returns the rating of the score, which is empty if the score is negative
+// as that means that there is no score
+func (m Mapping) Rating(score float64) string {
+	if score < 0 {
+		return ""
+	}
+
+	for _, threshold := range m {
+		if score >= threshold.MinScore {
+			return threshold.Rating
+		}
+	}
+
+	return ""
+}
+
+// MinScore returns the lowest score that is given the rating, which is matched case-insensitively
+func (m Mapping) MinScore(rating string) (float64, error) {
+	for _, threshold := range m {
+		if strings.EqualFold(threshold.Rating, rating) {
+			return threshold.MinScore, nil
+		}
+	}
+
+	ratings := make([]string, 0, len(m))
+	for _, threshold := range m {
+		ratings = append(ratings, strings.ToLower(threshold.Rating))
+	}
+
+	return 0, fmt.Errorf("%q is not one of: %s", rating, strings.Join(ratings, ", "))
+}
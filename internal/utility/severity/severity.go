@@ -1,6 +1,7 @@
 package severity
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/google/osv-scanner/pkg/models"
@@ -71,3 +72,48 @@ func CalculateOverallScore(severities []models.Severity) (float64, string, error
 
 	return maxScore, maxRating, nil
 }
+
+// fallbackOrder is the order in which the CVSS versions are used when a
+// vulnerability does not have a score for the preferred version
+var fallbackOrder = []models.SeverityType{models.SeverityCVSSV4, models.SeverityCVSSV3, models.SeverityCVSSV2}
+
+// ParseVersion returns the severity type of the CVSS major version, which is one of "2", "3" or "4"
+func ParseVersion(version string) (models.SeverityType, error) {
+	switch strings.TrimPrefix(strings.ToLower(version), "v") {
+	case "2":
+		return models.SeverityCVSSV2, nil
+	case "3":
+		return models.SeverityCVSSV3, nil
+	case "4":
+		return models.SeverityCVSSV4, nil
+	}
+
+	return "", fmt.Errorf("%q is not a supported CVSS version, must be one of 2, 3 or 4", version)
+}
+
+// CalculatePreferredScore calculates the highest score of the severities that are of the
+// preferred type, falling back to the newest CVSS version that the severities have a score
+// for if there are none of that type.
+//
+// If preferred is empty, the highest score of all the severities is calculated instead.
+func CalculatePreferredScore(severities []models.Severity, preferred models.SeverityType) (float64, string, error) {
+	if preferred == "" {
+		return CalculateOverallScore(severities)
+	}
+
+	order := append([]models.SeverityType{preferred}, fallbackOrder...)
+	for _, typ := range order {
+		var ofType []models.Severity
+		for _, severity := range severities {
+			if severity.Type == typ {
+				ofType = append(ofType, severity)
+			}
+		}
+
+		if len(ofType) > 0 {
+			return CalculateOverallScore(ofType)
+		}
+	}
+
+	return CalculateOverallScore(severities)
+}
@@ -91,3 +91,123 @@ func TestSeverity_CalculateScore(t *testing.T) {
 		})
 	}
 }
+
+func TestSeverity_CalculatePreferredScore(t *testing.T) {
+	t.Parallel()
+
+	v2 := models.Severity{Type: models.SeverityCVSSV2, Score: "AV:N/AC:L/Au:N/C:C/I:C/A:C"}
+	v3 := models.Severity{Type: models.SeverityCVSSV3, Score: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:N/A:N"}
+	v4 := models.Severity{Type: models.SeverityCVSSV4, Score: "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:L/VI:N/VA:N/SC:N/SI:N/SA:N"}
+
+	tests := []struct {
+		name       string
+		severities []models.Severity
+		preferred  models.SeverityType
+		want       float64
+	}{
+		{
+			name:       "no preference uses the highest score",
+			severities: []models.Severity{v2, v3, v4},
+			want:       10.0,
+		},
+		{
+			name:       "preferred version",
+			severities: []models.Severity{v2, v3, v4},
+			preferred:  models.SeverityCVSSV3,
+			want:       7.5,
+		},
+		{
+			name:       "falls back to the newest version",
+			severities: []models.Severity{v2, v4},
+			preferred:  models.SeverityCVSSV3,
+			want:       6.9,
+		},
+		{
+			name:       "falls back to older versions",
+			severities: []models.Severity{v2},
+			preferred:  models.SeverityCVSSV4,
+			want:       10.0,
+		},
+		{
+			name:      "no severities",
+			preferred: models.SeverityCVSSV3,
+			want:      -1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, _, err := severity.CalculatePreferredScore(tt.severities, tt.preferred)
+			if err != nil {
+				t.Fatalf("CalculatePreferredScore() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("CalculatePreferredScore() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSeverity_ParseVersion(t *testing.T) {
+	t.Parallel()
+
+	for version, want := range map[string]models.SeverityType{
+		"2":  models.SeverityCVSSV2,
+		"3":  models.SeverityCVSSV3,
+		"v4": models.SeverityCVSSV4,
+	} {
+		got, err := severity.ParseVersion(version)
+		if err != nil || got != want {
+			t.Errorf("ParseVersion(%q) = %v, %v, want %v", version, got, err, want)
+		}
+	}
+
+	if _, err := severity.ParseVersion("3.1"); err == nil {
+		t.Errorf("ParseVersion(%q) did not return an error", "3.1")
+	}
+}
+
+func TestSeverity_Mapping(t *testing.T) {
+	t.Parallel()
+
+	mapping, err := severity.ParseMapping([]string{"Severe:8", "Low:0", "Moderate:5"})
+	if err != nil {
+		t.Fatalf("ParseMapping() error = %v", err)
+	}
+
+	for score, want := range map[float64]string{-1: "", 0: "Low", 4.9: "Low", 5: "Moderate", 8: "Severe", 10: "Severe"} {
+		if got := mapping.Rating(score); got != want {
+			t.Errorf("Rating(%v) = %q, want %q", score, got, want)
+		}
+	}
+
+	for _, thresholds := range [][]string{
+		{},
+		{"High:7"},
+		{"High", "Low:0"},
+		{"High:eleven", "Low:0"},
+		{"High:11", "Low:0"},
+		{":7", "Low:0"},
+	} {
+		if _, err := severity.ParseMapping(thresholds); err == nil {
+			t.Errorf("ParseMapping(%v) did not return an error", thresholds)
+		}
+	}
+}
+
+func TestSeverity_MinScore(t *testing.T) {
+	t.Parallel()
+
+	for rating, want := range map[string]float64{"low": 0, "Medium": 4, "HIGH": 7, "critical": 9} {
+		got, err := severity.DefaultMapping.MinScore(rating)
+		if err != nil || got != want {
+			t.Errorf("MinScore(%q) = %v, %v, want %v", rating, got, err, want)
+		}
+	}
+
+	if _, err := severity.DefaultMapping.MinScore("severe"); err == nil {
+		t.Errorf("MinScore(%q) did not return an error", "severe")
+	}
+}
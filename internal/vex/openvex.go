@@ -0,0 +1,178 @@
+// Package vex reads OpenVEX documents, which vendors publish to state whether
+// their products are affected by vulnerabilities in the components that they use.
+package vex
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/package-url/packageurl-go"
+)
+
+// Status is whether the products of a statement are affected by its vulnerability
+type Status string
+
+const (
+	StatusNotAffected        Status = "not_affected"
+	StatusAffected           Status = "affected"
+	StatusFixed              Status = "fixed"
+	StatusUnderInvestigation Status = "under_investigation"
+)
+
+// Document is an OpenVEX document, which is a list of statements
+type Document struct {
+	ID         string      `json:"@id"`
+	Author     string      `json:"author"`
+	Statements []Statement `json:"statements"`
+}
+
+// Statement says whether the products are affected by the vulnerability
+type Statement struct {
+	Vulnerability Vulnerability `json:"vulnerability"`
+	Products      []Product     `json:"products"`
+	// Subcomponents are the subcomponents of every product, which is only used by documents before OpenVEX v0.2.0
+	Subcomponents []Product `json:"subcomponents,omitempty"`
+	Status        Status    `json:"status"`
+	// Justification is why the products are not affected, such as "vulnerable_code_not_present"
+	Justification   string `json:"justification,omitempty"`
+	ImpactStatement string `json:"impact_statement,omitempty"`
+}
+
+// Vulnerability is the vulnerability that a statement is about, which is
+// either an object or just its name in documents before OpenVEX v0.2.0
+type Vulnerability struct {
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+func (v *Vulnerability) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &v.Name); err == nil {
+		return nil
+	}
+
+	type vulnerability Vulnerability
+
+	return json.Unmarshal(data, (*vulnerability)(v))
+}
+
+// IDs returns the name of the vulnerability along with all of its aliases
+func (v Vulnerability) IDs() []string {
+	return append([]string{v.Name}, v.Aliases...)
+}
+
+// Product is a product or a subcomponent of one, which is identified by a Package URL either
+// as its ID or in its identifiers, or is just its ID in documents before OpenVEX v0.2.0
+type Product struct {
+	ID            string       `json:"@id,omitempty"`
+	Identifiers   *Identifiers `json:"identifiers,omitempty"`
+	Subcomponents []Product    `json:"subcomponents,omitempty"`
+}
+
+// Identifiers are the identifiers of a product other than its ID
+type Identifiers struct {
+	PURL string `json:"purl,omitempty"`
+}
+
+func (p *Product) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &p.ID); err == nil {
+		return nil
+	}
+
+	type product Product
+
+	return json.Unmarshal(data, (*product)(p))
+}
+
+// matchesPURL reports whether the Package URL of the product identifies the same package as purl,
+// where a product without a version applies to every version of the package
+func (p Product) matchesPURL(purl packageurl.PackageURL) bool {
+	ids := []string{p.ID}
+	if p.Identifiers != nil {
+		ids = append(ids, p.Identifiers.PURL)
+	}
+
+	for _, id := range ids {
+		productPURL, err := packageurl.FromString(id)
+		if err != nil {
+			continue
+		}
+
+		if productPURL.Type == purl.Type &&
+			productPURL.Namespace == purl.Namespace &&
+			productPURL.Name == purl.Name &&
+			(productPURL.Version == "" || productPURL.Version == purl.Version) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AppliesTo reports whether the statement is about the package identified by the Package URL, as
+// either one of its products or a subcomponent of them, and any of the IDs of a vulnerability
+func (s Statement) AppliesTo(purl string, ids []string) bool {
+	if !slices.ContainsFunc(s.Vulnerability.IDs(), func(id string) bool { return slices.Contains(ids, id) }) {
+		return false
+	}
+
+	parsed, err := packageurl.FromString(purl)
+	if err != nil {
+		return false
+	}
+
+	for _, product := range s.Products {
+		if product.matchesPURL(parsed) || slices.ContainsFunc(product.Subcomponents, func(p Product) bool { return p.matchesPURL(parsed) }) {
+			return true
+		}
+	}
+
+	return slices.ContainsFunc(s.Subcomponents, func(p Product) bool { return p.matchesPURL(parsed) })
+}
+
+// Suppresses reports whether the statement says that its products are not affected by the vulnerability
+func (s Statement) Suppresses() bool {
+	return s.Status == StatusNotAffected || s.Status == StatusFixed
+}
+
+// Reason describes the status of the statement, along with why
+// the products are not affected by the vulnerability if it says
+func (s Statement) Reason() string {
+	reason := "VEX: " + string(s.Status)
+	if s.Justification != "" {
+		reason += " (" + s.Justification + ")"
+	}
+	if s.ImpactStatement != "" {
+		reason += ": " + s.ImpactStatement
+	}
+
+	return reason
+}
+
+// Statement returns the last statement in the document that applies to the package and any of the
+// IDs of a vulnerability, as later statements supersede earlier ones, and false if none do
+func (d Document) Statement(purl string, ids []string) (Statement, bool) {
+	for i := len(d.Statements) - 1; i >= 0; i-- {
+		if d.Statements[i].AppliesTo(purl, ids) {
+			return d.Statements[i], true
+		}
+	}
+
+	return Statement{}, false
+}
+
+// Load reads the OpenVEX document at the path
+func Load(path string) (Document, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Document{}, fmt.Errorf("failed to read VEX document: %w", err)
+	}
+
+	var doc Document
+	if err := json.Unmarshal(content, &doc); err != nil {
+		return Document{}, fmt.Errorf("failed to parse VEX document %s: %w", path, err)
+	}
+
+	return doc, nil
+}
@@ -0,0 +1,118 @@
+package vex_test
+
+import (
+	"testing"
+
+	"github.com/google/osv-scanner/internal/vex"
+)
+
+func TestDocument_Statement(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		path       string
+		purl       string
+		ids        []string
+		want       bool
+		wantStatus vex.Status
+		wantReason string
+	}{
+		{
+			name:       "subcomponent of a product, matched by an alias",
+			path:       "fixtures/openvex.json",
+			purl:       "pkg:npm/lodash@4.17.20",
+			ids:        []string{"GHSA-35jh-r3h4-6jhm"},
+			want:       true,
+			wantStatus: vex.StatusNotAffected,
+			wantReason: "VEX: not_affected (vulnerable_code_not_in_execute_path): template is never called with untrusted input",
+		},
+		{
+			name: "subcomponent of a product with a different version",
+			path: "fixtures/openvex.json",
+			purl: "pkg:npm/lodash@4.17.21",
+			ids:  []string{"CVE-2021-23337"},
+			want: false,
+		},
+		{
+			name:       "product without a version",
+			path:       "fixtures/openvex.json",
+			purl:       "pkg:npm/lodash@4.17.21",
+			ids:        []string{"CVE-2020-28500"},
+			want:       true,
+			wantStatus: vex.StatusAffected,
+			wantReason: "VEX: affected",
+		},
+		{
+			name:       "later statements supersede earlier ones",
+			path:       "fixtures/openvex.json",
+			purl:       "pkg:npm/lodash@4.17.20",
+			ids:        []string{"GHSA-p6mc-m468-83gw"},
+			want:       true,
+			wantStatus: vex.StatusFixed,
+			wantReason: "VEX: fixed",
+		},
+		{
+			name: "other vulnerability",
+			path: "fixtures/openvex.json",
+			purl: "pkg:npm/lodash@4.17.20",
+			ids:  []string{"GHSA-29mw-wpgm-hmr9"},
+			want: false,
+		},
+		{
+			name:       "subcomponent of a statement before v0.2.0",
+			path:       "fixtures/openvex-v0.0.1.json",
+			purl:       "pkg:cargo/regex@1.5.1",
+			ids:        []string{"GHSA-m5pq-gvj9-9vr8", "CVE-2022-24713"},
+			want:       true,
+			wantStatus: vex.StatusNotAffected,
+			wantReason: "VEX: not_affected (inline_mitigations_already_exist)",
+		},
+		{
+			name:       "product before v0.2.0",
+			path:       "fixtures/openvex-v0.0.1.json",
+			purl:       "pkg:golang/github.com/gogo/protobuf@1.3.1",
+			ids:        []string{"GO-2021-0053", "CVE-2021-3121"},
+			want:       true,
+			wantStatus: vex.StatusNotAffected,
+			wantReason: "VEX: not_affected (vulnerable_code_not_present)",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			doc, err := vex.Load(tt.path)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+
+			statement, ok := doc.Statement(tt.purl, tt.ids)
+			if ok != tt.want {
+				t.Fatalf("Statement() found = %v, want %v", ok, tt.want)
+			}
+			if !ok {
+				return
+			}
+
+			if statement.Status != tt.wantStatus {
+				t.Errorf("Statement() status = %q, want %q", statement.Status, tt.wantStatus)
+			}
+			if got := statement.Reason(); got != tt.wantReason {
+				t.Errorf("Reason() = %q, want %q", got, tt.wantReason)
+			}
+			if want := tt.wantStatus == vex.StatusNotAffected || tt.wantStatus == vex.StatusFixed; statement.Suppresses() != want {
+				t.Errorf("Suppresses() = %v, want %v", statement.Suppresses(), want)
+			}
+		})
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	t.Parallel()
+
+	if _, err := vex.Load("fixtures/does-not-exist.json"); err == nil {
+		t.Errorf("Load() did not return an error for a missing file")
+	}
+}
@@ -1,13 +1,17 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/BurntSushi/toml"
+	"github.com/google/osv-scanner/internal/cachedregexp"
+	"github.com/google/osv-scanner/pkg/models"
 	"github.com/google/osv-scanner/pkg/reporter"
 )
 
@@ -20,12 +24,32 @@ type ConfigManager struct {
 	DefaultConfig Config
 	// Cache to store loaded configs
 	ConfigMap map[string]Config
+	// Cache to store loaded ignore files, which is created when first needed
+	IgnoreFileMap map[string]IgnoreFileEntries
+	// Config from environment variables, which is layered over every other config
+	EnvConfig Config
 }
 
 type Config struct {
 	IgnoredVulns      []IgnoreEntry `toml:"IgnoredVulns"`
 	LoadPath          string        `toml:"LoadPath"`
 	GoVersionOverride string        `toml:"GoVersionOverride"`
+	// EcosystemAliases maps non-standard ecosystem names to their canonical OSV ecosystem
+	EcosystemAliases map[string]string `toml:"EcosystemAliases"`
+	// MavenCredentialHelpers get the credentials for Maven registries that need them
+	MavenCredentialHelpers []MavenCredentialHelper `toml:"MavenCredentialHelpers"`
+	// VEXFiles are OpenVEX documents, relative to the config file, whose statements that packages
+	// are not affected by or have fixed vulnerabilities suppress those vulnerabilities
+	VEXFiles []string `toml:"VEXFiles"`
+}
+
+// MavenCredentialHelper is a command that prints the credentials for a Maven registry
+// to stdout, which is run whenever a request is made to that registry
+type MavenCredentialHelper struct {
+	Registry string   `toml:"registry"`
+	Command  []string `toml:"command"`
+	// Scheme is either "Bearer" for a token (the default), or "Basic" for a "username:password" pair
+	Scheme string `toml:"scheme"`
 }
 
 type IgnoreEntry struct {
@@ -49,11 +73,33 @@ func (c *Config) ShouldIgnore(vulnID string) (bool, IgnoreEntry) {
 	return ignoredLine.IgnoreUntil.After(time.Now()), ignoredLine
 }
 
+// CanonicalEcosystem returns the ecosystem that the given ecosystem has been aliased to,
+// or the ecosystem unchanged if there is no alias configured for it.
+func (c *Config) CanonicalEcosystem(ecosystem string) string {
+	if canonical, ok := c.EcosystemAliases[ecosystem]; ok {
+		return canonical
+	}
+
+	return ecosystem
+}
+
+// VEXPaths returns the paths of the VEXFiles, resolving those that are relative against the directory of the config file
+func (c *Config) VEXPaths() []string {
+	paths := make([]string, 0, len(c.VEXFiles))
+	for _, path := range c.VEXFiles {
+		if !filepath.IsAbs(path) && c.LoadPath != "" {
+			path = filepath.Join(filepath.Dir(c.LoadPath), path)
+		}
+		paths = append(paths, path)
+	}
+
+	return paths
+}
+
 // Sets the override config by reading the config file at configPath.
 // Will return an error if loading the config file fails
 func (c *ConfigManager) UseOverride(configPath string) error {
-	config := Config{}
-	_, err := toml.DecodeFile(configPath, &config)
+	config, err := tryLoadConfig(configPath)
 	if err != nil {
 		return err
 	}
@@ -63,8 +109,24 @@ func (c *ConfigManager) UseOverride(configPath string) error {
 	return nil
 }
 
-// Attempts to get the config
+// Attempts to get the config, with EnvConfig layered over it
 func (c *ConfigManager) Get(r reporter.Reporter, targetPath string) Config {
+	return layerConfig(c.get(r, targetPath), c.EnvConfig)
+}
+
+// MavenCredentialHelpers returns the Maven credential helpers of the override config.
+//
+// Helpers are commands that get run, so they are only ever read from the config given with
+// --config, and never from the configs alongside manifests, which come from the project being scanned
+func (c *ConfigManager) MavenCredentialHelpers() []MavenCredentialHelper {
+	if c.OverrideConfig == nil {
+		return nil
+	}
+
+	return c.OverrideConfig.MavenCredentialHelpers
+}
+
+func (c *ConfigManager) get(r reporter.Reporter, targetPath string) Config {
 	if c.OverrideConfig != nil {
 		return *c.OverrideConfig
 	}
@@ -85,7 +147,16 @@ func (c *ConfigManager) Get(r reporter.Reporter, targetPath string) Config {
 	config, configErr := tryLoadConfig(configPath)
 	if configErr == nil {
 		r.Infof("Loaded filter from: %s\n", config.LoadPath)
+		if len(config.MavenCredentialHelpers) > 0 {
+			r.Warnf("Ignoring the MavenCredentialHelpers in %s, as they are only read from the config given with --config\n", config.LoadPath)
+			config.MavenCredentialHelpers = nil
+		}
 	} else {
+		// A config that exists but cannot be loaded is most likely a mistake,
+		// so make sure it does not get silently ignored
+		if !errors.Is(configErr, os.ErrNotExist) {
+			r.Errorf("Failed to load config %s: %v\n", configPath, configErr)
+		}
 		// If config doesn't exist, use the default config
 		config = c.DefaultConfig
 	}
@@ -120,14 +191,93 @@ func tryLoadConfig(configPath string) (Config, error) {
 	if err == nil { // File exists, and we have permission to read
 		defer file.Close()
 
-		_, err := toml.NewDecoder(file).Decode(&config)
+		md, err := toml.NewDecoder(file).Decode(&config)
 		if err != nil {
 			return Config{}, fmt.Errorf("failed to parse config file: %w", err)
 		}
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			keys := make([]string, 0, len(undecoded))
+			for _, key := range undecoded {
+				keys = append(keys, key.String())
+			}
+
+			return Config{}, fmt.Errorf("failed to parse config file: unknown keys %s", strings.Join(keys, ", "))
+		}
 		config.LoadPath = configPath
 
 		return config, nil
 	}
 
-	return Config{}, fmt.Errorf("no config file found on this path: %s", configPath)
+	return Config{}, fmt.Errorf("no config file found on this path: %s: %w", configPath, err)
+}
+
+var goVersionRegexp = cachedregexp.MustCompile(`^(go)?\d+(\.\d+){0,2}$`)
+
+// Validate checks the config for problems that would cause it to not behave
+// as expected, such as ignore entries without an id
+func (c *Config) Validate() []error {
+	var errs []error
+
+	seen := map[string]bool{}
+	for i, entry := range c.IgnoredVulns {
+		if entry.ID == "" {
+			errs = append(errs, fmt.Errorf("IgnoredVulns[%d]: id is required", i))
+
+			continue
+		}
+		if seen[entry.ID] {
+			errs = append(errs, fmt.Errorf("IgnoredVulns[%d]: %s is ignored more than once", i, entry.ID))
+		}
+		seen[entry.ID] = true
+	}
+
+	if c.GoVersionOverride != "" && !goVersionRegexp.MatchString(c.GoVersionOverride) {
+		errs = append(errs, fmt.Errorf("GoVersionOverride: %q is not a valid Go version", c.GoVersionOverride))
+	}
+
+	// map iteration is random, so sort the aliases to keep the output stable
+	aliases := make([]string, 0, len(c.EcosystemAliases))
+	for alias := range c.EcosystemAliases {
+		aliases = append(aliases, alias)
+	}
+	slices.Sort(aliases)
+
+	for _, alias := range aliases {
+		canonical := c.EcosystemAliases[alias]
+		base, _, _ := strings.Cut(canonical, ":")
+		if !slices.Contains(models.Ecosystems, models.Ecosystem(base)) {
+			errs = append(errs, fmt.Errorf("EcosystemAliases.%s: %q is not a known ecosystem", alias, canonical))
+		}
+	}
+
+	for i, helper := range c.MavenCredentialHelpers {
+		if helper.Registry == "" {
+			errs = append(errs, fmt.Errorf("MavenCredentialHelpers[%d]: registry is required", i))
+		}
+		if len(helper.Command) == 0 {
+			errs = append(errs, fmt.Errorf("MavenCredentialHelpers[%d]: command is required", i))
+		}
+		if helper.Scheme != "" && helper.Scheme != "Bearer" && helper.Scheme != "Basic" {
+			errs = append(errs, fmt.Errorf("MavenCredentialHelpers[%d]: %q is not a supported scheme", i, helper.Scheme))
+		}
+	}
+
+	for i, path := range c.VEXFiles {
+		if path == "" {
+			errs = append(errs, fmt.Errorf("VEXFiles[%d]: path is required", i))
+		}
+	}
+
+	return errs
+}
+
+// ValidateFile strictly loads the config file at configPath and checks it for any problems,
+// returning everything that was found to be wrong with it
+func ValidateFile(configPath string) []error {
+	config, err := tryLoadConfig(configPath)
+	if err != nil {
+		return []error{err}
+	}
+
+	return config.Validate()
 }
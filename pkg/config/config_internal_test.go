@@ -1,12 +1,14 @@
 package config
 
 import (
+	"os"
 	"path/filepath"
 	"reflect"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/reporter"
 )
 
 type testStruct struct {
@@ -191,3 +193,125 @@ func TestConfig_ShouldIgnore(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateFile(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		configPath string
+		wantErrs   []string
+	}{
+		{
+			name:       "valid config",
+			configPath: "../../fixtures/config-validation/valid.toml",
+			wantErrs:   nil,
+		},
+		{
+			name:       "config does not exist",
+			configPath: "../../fixtures/config-validation/does-not-exist.toml",
+			wantErrs: []string{
+				"no config file found on this path: ../../fixtures/config-validation/does-not-exist.toml: open ../../fixtures/config-validation/does-not-exist.toml: no such file or directory",
+			},
+		},
+		{
+			name:       "config with unknown keys",
+			configPath: "../../fixtures/config-validation/unknown-keys.toml",
+			wantErrs: []string{
+				"failed to parse config file: unknown keys IgnoredVulns.ignore_until, IgnoredVulns.reasn",
+			},
+		},
+		{
+			name:       "config with a malformed date",
+			configPath: "../../fixtures/config-validation/bad-date.toml",
+			wantErrs: []string{
+				"failed to parse config file: parsing time \"next tuesday\" as \"2006-01-02T15:04:05Z07:00\": cannot parse \"next tuesday\" as \"2006\"",
+			},
+		},
+		{
+			name:       "config with problems",
+			configPath: "../../fixtures/config-validation/problems.toml",
+			wantErrs: []string{
+				"IgnoredVulns[0]: id is required",
+				"IgnoredVulns[2]: GO-2022-0968 is ignored more than once",
+				"GoVersionOverride: \"latest\" is not a valid Go version",
+				"EcosystemAliases.PyPI-mirror: \"PyPi\" is not a known ecosystem",
+				"MavenCredentialHelpers[0]: command is required",
+				"MavenCredentialHelpers[0]: \"Digest\" is not a supported scheme",
+				"VEXFiles[1]: path is required",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotErrs []string
+			for _, err := range ValidateFile(tt.configPath) {
+				gotErrs = append(gotErrs, err.Error())
+			}
+
+			if diff := cmp.Diff(tt.wantErrs, gotErrs); diff != "" {
+				t.Errorf("ValidateFile() errors mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConfig_VEXPaths(t *testing.T) {
+	t.Parallel()
+
+	config := Config{
+		LoadPath: filepath.Join("path", "to", "osv-scanner.toml"),
+		VEXFiles: []string{"vex.json", filepath.Join("..", "vendor.openvex.json")},
+	}
+
+	want := []string{
+		filepath.Join("path", "to", "vex.json"),
+		filepath.Join("path", "vendor.openvex.json"),
+	}
+
+	if diff := cmp.Diff(want, config.VEXPaths()); diff != "" {
+		t.Errorf("VEXPaths() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestConfigManager_MavenCredentialHelpers(t *testing.T) {
+	t.Parallel()
+
+	helpers := "[[MavenCredentialHelpers]]\nregistry = \"https://repo.maven.apache.org/maven2\"\ncommand = [\"touch\", \"pwned\"]\n"
+
+	// a config that was committed alongside a manifest in the project being scanned
+	scannedDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(scannedDir, osvScannerConfigName), []byte(helpers), 0600); err != nil {
+		t.Fatal(err)
+	}
+	manifestPath := filepath.Join(scannedDir, "pom.xml")
+	if err := os.WriteFile(manifestPath, []byte("<project></project>"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c := ConfigManager{ConfigMap: make(map[string]Config)}
+
+	if got := c.Get(&reporter.VoidReporter{}, manifestPath).MavenCredentialHelpers; got != nil {
+		t.Errorf("expected the helpers in the config of a scanned directory to be ignored, got %v", got)
+	}
+	if got := c.MavenCredentialHelpers(); got != nil {
+		t.Errorf("expected no helpers without an override config, got %v", got)
+	}
+
+	// the config given with --config
+	overridePath := filepath.Join(t.TempDir(), "override.toml")
+	if err := os.WriteFile(overridePath, []byte(helpers), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.UseOverride(overridePath); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []MavenCredentialHelper{{Registry: "https://repo.maven.apache.org/maven2", Command: []string{"touch", "pwned"}}}
+	if diff := cmp.Diff(want, c.MavenCredentialHelpers()); diff != "" {
+		t.Errorf("MavenCredentialHelpers() mismatch (-want +got):\n%s", diff)
+	}
+}
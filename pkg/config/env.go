@@ -0,0 +1,106 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// The environment variables that config can be set by, for when mounting a config file is not practical
+const (
+	// IgnoredVulnsEnvVar is a comma-separated list of the IDs of vulnerabilities to ignore
+	IgnoredVulnsEnvVar = "OSV_SCANNER_IGNORED_VULNS"
+	// GoVersionOverrideEnvVar is the version of Go to scan for, like GoVersionOverride
+	GoVersionOverrideEnvVar = "OSV_SCANNER_GO_VERSION_OVERRIDE"
+	// EcosystemAliasesEnvVar is a comma-separated list of "alias=ecosystem" pairs, like EcosystemAliases
+	EcosystemAliasesEnvVar = "OSV_SCANNER_ECOSYSTEM_ALIASES"
+)
+
+// splitEnvList splits the value of an environment variable on commas,
+// dropping any whitespace and empty items
+func splitEnvList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// LoadEnv loads the config that is set by OSV_SCANNER_* environment variables, which are looked up
+// with lookupEnv (usually os.LookupEnv), and checks it with the same validation as config files
+func LoadEnv(lookupEnv func(string) (string, bool)) (Config, error) {
+	var config Config
+	var errs []error
+
+	if value, ok := lookupEnv(IgnoredVulnsEnvVar); ok {
+		for _, id := range splitEnvList(value) {
+			config.IgnoredVulns = append(config.IgnoredVulns, IgnoreEntry{
+				ID:     id,
+				Reason: "ignored by " + IgnoredVulnsEnvVar,
+			})
+		}
+	}
+
+	if value, ok := lookupEnv(GoVersionOverrideEnvVar); ok {
+		config.GoVersionOverride = strings.TrimSpace(value)
+	}
+
+	if value, ok := lookupEnv(EcosystemAliasesEnvVar); ok {
+		for _, pair := range splitEnvList(value) {
+			alias, canonical, ok := strings.Cut(pair, "=")
+			alias, canonical = strings.TrimSpace(alias), strings.TrimSpace(canonical)
+			if !ok || alias == "" || canonical == "" {
+				errs = append(errs, fmt.Errorf("%s: %q is not in the form of alias=ecosystem", EcosystemAliasesEnvVar, pair))
+
+				continue
+			}
+			if config.EcosystemAliases == nil {
+				config.EcosystemAliases = make(map[string]string)
+			}
+			config.EcosystemAliases[alias] = canonical
+		}
+	}
+
+	errs = append(errs, config.Validate()...)
+	if len(errs) > 0 {
+		return Config{}, fmt.Errorf("invalid config in environment variables: %w", errors.Join(errs...))
+	}
+
+	return config, nil
+}
+
+// layerConfig returns the config with the settings of the upper config taking precedence over it,
+// with ignored vulnerabilities being merged so that those in upper replace any with the same id
+func layerConfig(config Config, upper Config) Config {
+	if len(upper.IgnoredVulns) > 0 {
+		ignored := make([]IgnoreEntry, 0, len(upper.IgnoredVulns)+len(config.IgnoredVulns))
+		ignored = append(ignored, upper.IgnoredVulns...)
+		for _, entry := range config.IgnoredVulns {
+			if !slices.ContainsFunc(upper.IgnoredVulns, func(e IgnoreEntry) bool { return e.ID == entry.ID }) {
+				ignored = append(ignored, entry)
+			}
+		}
+		config.IgnoredVulns = ignored
+	}
+
+	if upper.GoVersionOverride != "" {
+		config.GoVersionOverride = upper.GoVersionOverride
+	}
+
+	if len(upper.EcosystemAliases) > 0 {
+		aliases := make(map[string]string, len(config.EcosystemAliases)+len(upper.EcosystemAliases))
+		for alias, canonical := range config.EcosystemAliases {
+			aliases[alias] = canonical
+		}
+		for alias, canonical := range upper.EcosystemAliases {
+			aliases[alias] = canonical
+		}
+		config.EcosystemAliases = aliases
+	}
+
+	return config
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+// fakeEnv returns a lookup function for the given environment variables
+func fakeEnv(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := env[key]
+
+		return value, ok
+	}
+}
+
+func TestLoadEnv(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    Config
+		wantErr bool
+	}{
+		{
+			name: "no environment variables",
+			env:  map[string]string{},
+			want: Config{},
+		},
+		{
+			name: "all environment variables",
+			env: map[string]string{
+				IgnoredVulnsEnvVar:      "GO-2022-0968, GHSA-xxxx-yyyy-zzzz,",
+				GoVersionOverrideEnvVar: "1.21.1",
+				EcosystemAliasesEnvVar:  "golang=Go, pip=PyPI",
+			},
+			want: Config{
+				IgnoredVulns: []IgnoreEntry{
+					{ID: "GO-2022-0968", Reason: "ignored by OSV_SCANNER_IGNORED_VULNS"},
+					{ID: "GHSA-xxxx-yyyy-zzzz", Reason: "ignored by OSV_SCANNER_IGNORED_VULNS"},
+				},
+				GoVersionOverride: "1.21.1",
+				EcosystemAliases:  map[string]string{"golang": "Go", "pip": "PyPI"},
+			},
+		},
+		{
+			name:    "invalid go version",
+			env:     map[string]string{GoVersionOverrideEnvVar: "latest"},
+			wantErr: true,
+		},
+		{
+			name:    "alias without an ecosystem",
+			env:     map[string]string{EcosystemAliasesEnvVar: "golang"},
+			wantErr: true,
+		},
+		{
+			name:    "alias for an unknown ecosystem",
+			env:     map[string]string{EcosystemAliasesEnvVar: "golang=NotAnEcosystem"},
+			wantErr: true,
+		},
+		{
+			name:    "vulnerability ignored more than once",
+			env:     map[string]string{IgnoredVulnsEnvVar: "GO-2022-0968,GO-2022-0968"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := LoadEnv(fakeEnv(tt.env))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("LoadEnv() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConfigManager_Get_EnvConfig(t *testing.T) {
+	t.Parallel()
+
+	manager := ConfigManager{
+		OverrideConfig: &Config{
+			IgnoredVulns: []IgnoreEntry{
+				{ID: "GO-2022-0968", Reason: "from the file"},
+				{ID: "GO-2022-1059", Reason: "from the file"},
+			},
+			GoVersionOverride: "1.20",
+			EcosystemAliases:  map[string]string{"golang": "Go", "pip": "PyPI"},
+			LoadPath:          "osv-scanner.toml",
+		},
+		EnvConfig: Config{
+			IgnoredVulns:      []IgnoreEntry{{ID: "GO-2022-1059", Reason: "from the environment"}},
+			GoVersionOverride: "1.21.1",
+			EcosystemAliases:  map[string]string{"pip": "PyPI", "golang": "Go:stdlib"},
+		},
+	}
+
+	want := Config{
+		IgnoredVulns: []IgnoreEntry{
+			{ID: "GO-2022-1059", Reason: "from the environment"},
+			{ID: "GO-2022-0968", Reason: "from the file"},
+		},
+		GoVersionOverride: "1.21.1",
+		EcosystemAliases:  map[string]string{"golang": "Go:stdlib", "pip": "PyPI"},
+		LoadPath:          "osv-scanner.toml",
+	}
+
+	got := manager.Get(&reporter.VoidReporter{}, ".")
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Get() mismatch (-want +got):\n%s", diff)
+	}
+
+	// the override itself should not be changed by the environment
+	if manager.OverrideConfig.GoVersionOverride != "1.20" {
+		t.Errorf("Get() changed the override config")
+	}
+}
@@ -0,0 +1,154 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+const osvScannerIgnoreFileName = ".osvscannerignore"
+
+// IgnoreFileEntry is a line of a .osvscannerignore file, which ignores the
+// vulnerabilities it matches in every source under the directory of the file
+type IgnoreFileEntry struct {
+	// Pattern is matched against the IDs (including aliases) of vulnerabilities and the
+	// names of the packages they affect, and can be a glob as supported by path.Match
+	Pattern string
+	// Reason is the comment at the end of the line, if there is one
+	Reason   string
+	LoadPath string
+}
+
+// IgnoreFileEntries are the entries of all the .osvscannerignore files that apply to a source
+type IgnoreFileEntries []IgnoreFileEntry
+
+// ShouldIgnore returns true if vulnID (or the package it affects) is matched by any of the entries,
+// along with an IgnoreEntry for the vulnerability so that it can be handled like those in the config
+func (e IgnoreFileEntries) ShouldIgnore(pkgName string, vulnID string) (bool, IgnoreEntry) {
+	for _, entry := range e {
+		// the patterns have already been checked when the file was parsed, so matching cannot fail
+		matchesID, _ := path.Match(entry.Pattern, vulnID)
+		matchesPkg, _ := path.Match(entry.Pattern, pkgName)
+
+		if !matchesID && !(pkgName != "" && matchesPkg) {
+			continue
+		}
+
+		reason := entry.Reason
+		if reason == "" {
+			reason = "listed in " + entry.LoadPath
+		}
+
+		return true, IgnoreEntry{ID: vulnID, Reason: reason}
+	}
+
+	return false, IgnoreEntry{}
+}
+
+// GetIgnoreEntries returns the entries of the .osvscannerignore files in the directory of
+// `targetPath` and each of its parents, stopping at the root of the git repository if there is one.
+//
+// Unlike Get, this is not affected by the override config, as ignore files
+// are about the directories they are in rather than the scan as a whole.
+func (c *ConfigManager) GetIgnoreEntries(r reporter.Reporter, targetPath string) IgnoreFileEntries {
+	dir, err := normalizeIgnoreFileLoadDir(targetPath)
+	if err != nil {
+		// as with Get, this can happen when target is not a file (e.g. Docker container, git hash...etc.)
+		return nil
+	}
+
+	if c.IgnoreFileMap == nil {
+		c.IgnoreFileMap = make(map[string]IgnoreFileEntries)
+	}
+
+	var entries IgnoreFileEntries
+	for {
+		entries = append(entries, c.getIgnoreFile(r, dir)...)
+
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			break
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	return entries
+}
+
+// getIgnoreFile returns the entries of the .osvscannerignore file in `dir`, if there is one
+func (c *ConfigManager) getIgnoreFile(r reporter.Reporter, dir string) IgnoreFileEntries {
+	ignoreFilePath := filepath.Join(dir, osvScannerIgnoreFileName)
+
+	entries, alreadyExists := c.IgnoreFileMap[ignoreFilePath]
+	if alreadyExists {
+		return entries
+	}
+
+	entries, err := tryLoadIgnoreFile(ignoreFilePath)
+	if err == nil {
+		r.Infof("Loaded ignore file from: %s\n", ignoreFilePath)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		r.Errorf("Failed to load ignore file %s: %v\n", ignoreFilePath, err)
+	}
+	c.IgnoreFileMap[ignoreFilePath] = entries
+
+	return entries
+}
+
+// Finds the absolute path of the containing folder of `target`
+func normalizeIgnoreFileLoadDir(target string) (string, error) {
+	configPath, err := normalizeConfigLoadPath(target)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Abs(filepath.Dir(configPath))
+}
+
+// tryLoadIgnoreFile parses the .osvscannerignore file at ignoreFilePath, which has
+// a pattern on each line, with blank lines and anything after a "#" being ignored
+func tryLoadIgnoreFile(ignoreFilePath string) (IgnoreFileEntries, error) {
+	file, err := os.Open(ignoreFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("no ignore file found on this path: %s: %w", ignoreFilePath, err)
+	}
+	defer file.Close()
+
+	var entries IgnoreFileEntries
+
+	scanner := bufio.NewScanner(file)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		pattern, reason, _ := strings.Cut(scanner.Text(), "#")
+		pattern = strings.TrimSpace(pattern)
+
+		if pattern == "" {
+			continue
+		}
+
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("line %d: %q is not a valid pattern: %w", lineNum, pattern, err)
+		}
+
+		entries = append(entries, IgnoreFileEntry{
+			Pattern:  pattern,
+			Reason:   strings.TrimSpace(reason),
+			LoadPath: ignoreFilePath,
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read ignore file: %w", err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+func TestConfigManager_GetIgnoreEntries(t *testing.T) {
+	t.Parallel()
+
+	outerPath, _ := filepath.Abs("../../fixtures/ignore-file/.osvscannerignore")
+	innerPath, _ := filepath.Abs("../../fixtures/ignore-file/inner/.osvscannerignore")
+
+	outerEntries := IgnoreFileEntries{
+		{Pattern: "GHSA-whgm-jr23-g3j9", Reason: "ansi-html is only used by the dev server", LoadPath: outerPath},
+		{Pattern: "CVE-2021-*", LoadPath: outerPath},
+	}
+
+	tests := []struct {
+		name       string
+		targetPath string
+		want       IgnoreFileEntries
+	}{
+		{
+			name:       "directory with an ignore file",
+			targetPath: "../../fixtures/ignore-file",
+			want:       outerEntries,
+		},
+		{
+			name:       "file in a subdirectory with its own ignore file",
+			targetPath: "../../fixtures/ignore-file/inner/package-lock.json",
+			want: append(IgnoreFileEntries{
+				{Pattern: "lodash", LoadPath: innerPath},
+				{Pattern: "@babel/*", Reason: "owned by another team", LoadPath: innerPath},
+			}, outerEntries...),
+		},
+		{
+			name:       "directory without an ignore file",
+			targetPath: "../../fixtures/testdatainner",
+			want:       nil,
+		},
+		{
+			name:       "ignore file with an invalid pattern",
+			targetPath: "../../fixtures/ignore-file-invalid",
+			want:       nil,
+		},
+		{
+			name:       "target that does not exist",
+			targetPath: "../../fixtures/ignore-file/does-not-exist",
+			want:       nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := &ConfigManager{}
+			got := c.GetIgnoreEntries(&reporter.VoidReporter{}, tt.targetPath)
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("GetIgnoreEntries() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIgnoreFileEntries_ShouldIgnore(t *testing.T) {
+	t.Parallel()
+
+	entries := IgnoreFileEntries{
+		{Pattern: "GHSA-123", Reason: "not used", LoadPath: "a/.osvscannerignore"},
+		{Pattern: "CVE-2021-*", LoadPath: "a/.osvscannerignore"},
+		{Pattern: "@babel/*", LoadPath: "a/b/.osvscannerignore"},
+	}
+
+	tests := []struct {
+		name      string
+		pkgName   string
+		vulnID    string
+		wantOk    bool
+		wantEntry IgnoreEntry
+	}{
+		{
+			name:      "matching id",
+			pkgName:   "lodash",
+			vulnID:    "GHSA-123",
+			wantOk:    true,
+			wantEntry: IgnoreEntry{ID: "GHSA-123", Reason: "not used"},
+		},
+		{
+			name:      "id matching a glob",
+			pkgName:   "lodash",
+			vulnID:    "CVE-2021-23337",
+			wantOk:    true,
+			wantEntry: IgnoreEntry{ID: "CVE-2021-23337", Reason: "listed in a/.osvscannerignore"},
+		},
+		{
+			name:      "package matching a glob",
+			pkgName:   "@babel/traverse",
+			vulnID:    "GHSA-456",
+			wantOk:    true,
+			wantEntry: IgnoreEntry{ID: "GHSA-456", Reason: "listed in a/b/.osvscannerignore"},
+		},
+		{
+			name:      "no matches",
+			pkgName:   "babel",
+			vulnID:    "CVE-2022-1",
+			wantOk:    false,
+			wantEntry: IgnoreEntry{},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotOk, gotEntry := entries.ShouldIgnore(tt.pkgName, tt.vulnID)
+			if gotOk != tt.wantOk {
+				t.Errorf("ShouldIgnore() gotOk = %v, wantOk %v", gotOk, tt.wantOk)
+			}
+			if diff := cmp.Diff(tt.wantEntry, gotEntry); diff != "" {
+				t.Errorf("ShouldIgnore() entry mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTryLoadIgnoreFile_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := tryLoadIgnoreFile("../../fixtures/ignore-file-invalid/.osvscannerignore")
+
+	want := `line 1: "GHSA-[" is not a valid pattern: syntax error in pattern`
+	if err == nil || err.Error() != want {
+		t.Errorf("tryLoadIgnoreFile() error = %v, want %s", err, want)
+	}
+}
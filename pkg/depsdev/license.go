@@ -2,9 +2,9 @@ package depsdev
 
 import (
 	"context"
-	"crypto/x509"
 	"fmt"
 
+	"github.com/google/osv-scanner/internal/tlsconfig"
 	"github.com/google/osv-scanner/pkg/lockfile"
 	"github.com/google/osv-scanner/pkg/models"
 	"github.com/google/osv-scanner/pkg/osv"
@@ -56,11 +56,7 @@ func MakeVersionRequests(queries []*depsdevpb.GetVersionRequest) ([][]models.Lic
 // connection. The order in which the requests are specified should correspond
 // to the order of licenses returned by this function.
 func MakeVersionRequestsWithContext(ctx context.Context, queries []*depsdevpb.GetVersionRequest) ([][]models.License, error) {
-	certPool, err := x509.SystemCertPool()
-	if err != nil {
-		return nil, fmt.Errorf("getting system cert pool: %w", err)
-	}
-	creds := credentials.NewClientTLSFromCert(certPool, "")
+	creds := credentials.NewTLS(tlsconfig.Config())
 	dialOpts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
 
 	if osv.RequestUserAgent != "" {
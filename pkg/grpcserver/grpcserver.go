@@ -0,0 +1,296 @@
+// Package grpcserver implements the gRPC interface of package scannerpb,
+// so that build systems can scan without running the osv-scanner command.
+package grpcserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osvscanner"
+	"github.com/google/osv-scanner/pkg/reporter"
+	"github.com/google/osv-scanner/pkg/scannerpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ScanFunc scans with the actions, reporting on its progress to r
+type ScanFunc func(ctx context.Context, actions osvscanner.ScannerActions, r reporter.Reporter) (models.VulnerabilityResults, error)
+
+// Options configures the scans of a Server
+type Options struct {
+	// Actions are used for every scan, apart from what is scanned which comes from each request,
+	// so that every scan shares the same caches and local databases
+	Actions osvscanner.ScannerActions
+	// Scan runs each scan, which is osvscanner.DoScanWithContext if nil
+	Scan ScanFunc
+	// Log is where the messages of each scan are written at the Level, which are discarded if nil
+	Log   io.Writer
+	Level reporter.VerbosityLevel
+	// DirectoryRoot is the directory of the server that the directories of requests have to be within,
+	// with relative directories being relative to it; requests cannot scan directories if it is empty,
+	// so that clients can only read the files of the server that are meant to be scanned
+	DirectoryRoot string
+}
+
+// Server scans the packages, directories and SBOMs of each request
+type Server struct {
+	scannerpb.UnimplementedScannerServer
+
+	options Options
+}
+
+var _ scannerpb.ScannerServer = (*Server)(nil)
+
+// New returns a server that scans as per the options, to be registered with scannerpb.RegisterScannerServer
+func New(options Options) *Server {
+	if options.Scan == nil {
+		options.Scan = osvscanner.DoScanWithContext
+	}
+	if options.Log == nil {
+		options.Log = io.Discard
+	}
+
+	return &Server{options: options}
+}
+
+// packageCoordinate returns the package as a coordinate of osvscanner.ParsePackageCoordinate
+func packageCoordinate(pkg *scannerpb.Package) (string, error) {
+	if pkg.GetEcosystem() == "" || pkg.GetName() == "" || pkg.GetVersion() == "" {
+		return "", errors.New("packages need either a purl, a commit, or an ecosystem, name and version")
+	}
+
+	coordinate := pkg.GetEcosystem() + ":" + pkg.GetName() + "@" + pkg.GetVersion()
+	if _, err := osvscanner.ParsePackageCoordinate(coordinate); err != nil {
+		return "", err
+	}
+
+	return coordinate, nil
+}
+
+// writeFile writes the content to a file with the base of the name in dir, returning its path
+func writeFile(dir string, name string, content []byte) (string, error) {
+	// only the base name is kept, so that a name cannot place the file outside of dir
+	base := filepath.Base(filepath.Clean("/" + filepath.FromSlash(name)))
+	if base == "." || base == string(filepath.Separator) {
+		return "", fmt.Errorf("%q is not the name of a file", name)
+	}
+
+	path := filepath.Join(dir, base)
+	if _, err := os.Stat(path); err == nil {
+		return "", fmt.Errorf("there is more than one file named %q", base)
+	}
+
+	return path, os.WriteFile(path, content, 0600)
+}
+
+// errDirectoryNotAllowed is for directories of requests that the server does not allow to be scanned
+var errDirectoryNotAllowed = errors.New("directory cannot be scanned")
+
+// withinRoot reports if the path is the root or one of the paths within it
+func withinRoot(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// resolveDirectories returns where the directories are on the server, which has to be within the
+// DirectoryRoot after following any symlinks so that a request cannot scan anything outside of it
+func (s *Server) resolveDirectories(dirs []string) ([]string, error) {
+	if len(dirs) == 0 {
+		return nil, nil
+	}
+
+	if s.options.DirectoryRoot == "" {
+		return nil, fmt.Errorf("%w: scanning directories is not enabled on this server", errDirectoryNotAllowed)
+	}
+
+	root, err := filepath.Abs(s.options.DirectoryRoot)
+	if err == nil {
+		root, err = filepath.EvalSymlinks(root)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve the directory root: %w", err)
+	}
+
+	resolved := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		path := filepath.FromSlash(dir)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(root, path)
+		}
+
+		// the path is checked before it is resolved as well, so that requests cannot tell
+		// which paths exist outside of the root by whether they can be resolved
+		if !withinRoot(root, filepath.Clean(path)) {
+			return nil, fmt.Errorf("%w: %q is not within the directory root of the server", errDirectoryNotAllowed, dir)
+		}
+
+		path, err = filepath.EvalSymlinks(path)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q could not be found", errDirectoryNotAllowed, dir)
+		}
+
+		if !withinRoot(root, path) {
+			return nil, fmt.Errorf("%w: %q is not within the directory root of the server", errDirectoryNotAllowed, dir)
+		}
+		resolved = append(resolved, path)
+	}
+
+	return resolved, nil
+}
+
+// scanActions returns the actions that scan what is in the request, writing any files that it has to dir,
+// along with the names that the files are reported as
+func (s *Server) scanActions(req *scannerpb.ScanRequest, dir string) (osvscanner.ScannerActions, map[string]string, error) {
+	dirs, err := s.resolveDirectories(req.GetDirectories())
+	if err != nil {
+		return s.options.Actions, nil, err
+	}
+
+	actions := s.options.Actions
+	actions.DirectoryPaths = dirs
+	actions.Recursive = req.GetRecursive()
+	names := make(map[string]string)
+
+	var purls []string
+	for _, pkg := range req.GetPackages() {
+		switch {
+		case pkg.GetPurl() != "":
+			purls = append(purls, pkg.GetPurl())
+		case pkg.GetCommit() != "":
+			actions.GitCommits = append(actions.GitCommits, pkg.GetCommit())
+		default:
+			coordinate, err := packageCoordinate(pkg)
+			if err != nil {
+				return actions, nil, err
+			}
+			actions.PackageCoordinates = append(actions.PackageCoordinates, coordinate)
+		}
+	}
+
+	if len(purls) > 0 {
+		path, err := writeFile(dir, "purls.txt", []byte(strings.Join(purls, "\n")))
+		if err != nil {
+			return actions, nil, err
+		}
+		actions.PURLPaths = []string{path}
+	}
+
+	sbomsDir := filepath.Join(dir, "sboms")
+	if err := os.Mkdir(sbomsDir, 0700); err != nil {
+		return actions, nil, err
+	}
+	for _, sbom := range req.GetSboms() {
+		path, err := writeFile(sbomsDir, sbom.GetName(), sbom.GetContent())
+		if err != nil {
+			return actions, nil, err
+		}
+		actions.SBOMPaths = append(actions.SBOMPaths, path)
+		names[path] = sbom.GetName()
+	}
+
+	if len(actions.DirectoryPaths) == 0 && len(actions.PackageCoordinates) == 0 && len(actions.GitCommits) == 0 &&
+		len(actions.PURLPaths) == 0 && len(actions.SBOMPaths) == 0 {
+		return actions, nil, errors.New("there is nothing to scan")
+	}
+
+	return actions, names, nil
+}
+
+// Scan finds the vulnerabilities of the packages in the request.
+//
+// Finding vulnerabilities is what the request is for rather than an error, and the
+// response of a request where no packages are found simply has no results.
+func (s *Server) Scan(ctx context.Context, req *scannerpb.ScanRequest) (*scannerpb.ScanResponse, error) {
+	dir, err := os.MkdirTemp("", "osv-scanner-grpc-")
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "could not create a directory to scan in: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	actions, names, err := s.scanActions(req, dir)
+	if errors.Is(err, errDirectoryNotAllowed) {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	r := reporter.NewJSONReporter(io.Discard, s.options.Log, s.options.Level)
+	vulnResult, err := s.options.Scan(ctx, actions, r)
+
+	switch {
+	case err == nil, errors.Is(err, osvscanner.VulnerabilitiesFoundErr), errors.Is(err, osvscanner.NoPackagesFoundErr):
+	case errors.Is(err, osvscanner.ErrAPIFailed):
+		return nil, status.Error(codes.Unavailable, err.Error())
+	case errors.Is(err, osvscanner.ErrTimedOut), errors.Is(err, context.DeadlineExceeded):
+		return nil, status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, osvscanner.ErrInterrupted), errors.Is(err, context.Canceled):
+		return nil, status.Error(codes.Canceled, err.Error())
+	default:
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return newScanResponse(vulnResult, names)
+}
+
+// newScanResponse converts the results to a response, reporting the sources at paths as their names
+func newScanResponse(vulnResult models.VulnerabilityResults, names map[string]string) (*scannerpb.ScanResponse, error) {
+	resp := &scannerpb.ScanResponse{}
+
+	for _, res := range vulnResult.Results {
+		source := &scannerpb.SourceResult{Path: res.Source.Path, Type: res.Source.Type}
+		if name, ok := names[res.Source.Path]; ok {
+			source.Path = name
+		}
+
+		for _, pkg := range res.Packages {
+			pkgResult := &scannerpb.PackageResult{
+				Package: &scannerpb.Package{
+					Ecosystem: pkg.Package.Ecosystem,
+					Name:      pkg.Package.Name,
+					Version:   pkg.Package.Version,
+					Commit:    pkg.Package.Commit,
+				},
+			}
+			if purl, ok := models.PackageToPURL(pkg.Package); ok && pkg.Package.Version != "" {
+				pkgResult.Package.Purl = purl
+			}
+
+			for _, group := range pkg.Groups {
+				pkgResult.Groups = append(pkgResult.Groups, &scannerpb.VulnerabilityGroup{
+					Ids:         group.IDs,
+					Aliases:     group.Aliases,
+					MaxSeverity: group.MaxSeverity,
+				})
+			}
+
+			for _, vuln := range pkg.Vulnerabilities {
+				osvJSON, err := json.Marshal(vuln)
+				if err != nil {
+					return nil, status.Errorf(codes.Internal, "could not encode %s: %v", vuln.ID, err)
+				}
+
+				pkgResult.Vulnerabilities = append(pkgResult.Vulnerabilities, &scannerpb.Vulnerability{
+					Id:      vuln.ID,
+					Summary: vuln.Summary,
+					Aliases: vuln.Aliases,
+					OsvJson: osvJSON,
+				})
+			}
+
+			source.Packages = append(source.Packages, pkgResult)
+		}
+
+		resp.Results = append(resp.Results, source)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,288 @@
+package grpcserver_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/grpcserver"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osvscanner"
+	"github.com/google/osv-scanner/pkg/reporter"
+	"github.com/google/osv-scanner/pkg/scannerpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+// newClient serves the server in memory, returning a client connected to it
+func newClient(t *testing.T, server *grpcserver.Server) scannerpb.ScannerClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	scannerpb.RegisterScannerServer(s, server)
+	go func() { _ = s.Serve(listener) }()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("could not connect to the server: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return scannerpb.NewScannerClient(conn)
+}
+
+func TestServer_Scan(t *testing.T) {
+	t.Parallel()
+
+	var got osvscanner.ScannerActions
+	var sbom []byte
+	vuln := models.Vulnerability{ID: "GHSA-35jh-r3h4-6jhm", Summary: "Command Injection in lodash", Aliases: []string{"CVE-2021-23337"}}
+
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "path", "to", "project"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	client := newClient(t, grpcserver.New(grpcserver.Options{
+		Actions:       osvscanner.ScannerActions{NoCache: true},
+		DirectoryRoot: root,
+		Scan: func(_ context.Context, actions osvscanner.ScannerActions, _ reporter.Reporter) (models.VulnerabilityResults, error) {
+			got = actions
+
+			var err error
+			sbom, err = os.ReadFile(actions.SBOMPaths[0])
+			if err != nil {
+				return models.VulnerabilityResults{}, err
+			}
+
+			return models.VulnerabilityResults{
+				Results: []models.PackageSource{
+					{
+						Source: models.SourceInfo{Path: actions.SBOMPaths[0], Type: "sbom"},
+						Packages: []models.PackageVulns{
+							{
+								Package:         models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"},
+								Vulnerabilities: []models.Vulnerability{vuln},
+								Groups:          []models.GroupInfo{{IDs: []string{vuln.ID}, Aliases: []string{"CVE-2021-23337", vuln.ID}, MaxSeverity: "7.2"}},
+							},
+						},
+					},
+				},
+			}, osvscanner.VulnerabilitiesFoundErr
+		},
+	}))
+
+	resp, err := client.Scan(context.Background(), &scannerpb.ScanRequest{
+		Packages: []*scannerpb.Package{
+			{Ecosystem: "npm", Name: "lodash", Version: "4.17.20"},
+			{Purl: "pkg:pypi/django@4.2.0"},
+			{Commit: "9a6bd55c9d0722cb101fe85a3b22d89e4ff4fe52"},
+		},
+		Directories: []string{"path/to/project"},
+		Recursive:   true,
+		Sboms:       []*scannerpb.File{{Name: "bom.cdx.json", Content: []byte(`{"bomFormat": "CycloneDX"}`)}},
+	})
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+
+	if !got.NoCache {
+		t.Errorf("the actions of the server were not used for the scan")
+	}
+	if diff := cmp.Diff([]string{"npm:lodash@4.17.20"}, got.PackageCoordinates); diff != "" {
+		t.Errorf("package coordinates mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"9a6bd55c9d0722cb101fe85a3b22d89e4ff4fe52"}, got.GitCommits); diff != "" {
+		t.Errorf("commits mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{filepath.Join(root, "path", "to", "project")}, got.DirectoryPaths); diff != "" || !got.Recursive {
+		t.Errorf("directories mismatch (-want +got):\n%s", diff)
+	}
+	if string(sbom) != `{"bomFormat": "CycloneDX"}` {
+		t.Errorf("scanned %q instead of the SBOM", sbom)
+	}
+	if len(got.PURLPaths) != 1 {
+		t.Fatalf("expected the purls to be written to one file, got %v", got.PURLPaths)
+	}
+	if _, err := os.Stat(filepath.Dir(got.PURLPaths[0])); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("the files of the request were not removed: %v", err)
+	}
+
+	osvJSON, err := json.Marshal(vuln)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &scannerpb.ScanResponse{
+		Results: []*scannerpb.SourceResult{
+			{
+				Path: "bom.cdx.json",
+				Type: "sbom",
+				Packages: []*scannerpb.PackageResult{
+					{
+						Package: &scannerpb.Package{Ecosystem: "npm", Name: "lodash", Version: "4.17.20", Purl: "pkg:npm/lodash@4.17.20"},
+						Groups: []*scannerpb.VulnerabilityGroup{
+							{Ids: []string{vuln.ID}, Aliases: []string{"CVE-2021-23337", vuln.ID}, MaxSeverity: "7.2"},
+						},
+						Vulnerabilities: []*scannerpb.Vulnerability{
+							{Id: vuln.ID, Summary: vuln.Summary, Aliases: vuln.Aliases, OsvJson: osvJSON},
+						},
+					},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, resp, protocmp.Transform()); diff != "" {
+		t.Errorf("Scan() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestServer_Scan_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		req      *scannerpb.ScanRequest
+		scanErr  error
+		noRoot   bool
+		wantCode codes.Code
+	}{
+		{
+			name:     "nothing to scan",
+			req:      &scannerpb.ScanRequest{},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "package without a version",
+			req:      &scannerpb.ScanRequest{Packages: []*scannerpb.Package{{Ecosystem: "npm", Name: "lodash"}}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "sboms with the same name",
+			req:      &scannerpb.ScanRequest{Sboms: []*scannerpb.File{{Name: "bom.json"}, {Name: "other/bom.json"}}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "directories without a directory root",
+			req:      &scannerpb.ScanRequest{Directories: []string{"."}},
+			noRoot:   true,
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "directory outside of the directory root",
+			req:      &scannerpb.ScanRequest{Directories: []string{".."}},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "absolute directory outside of the directory root",
+			req:      &scannerpb.ScanRequest{Directories: []string{os.TempDir()}},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "symlink to outside of the directory root",
+			req:      &scannerpb.ScanRequest{Directories: []string{"outside"}},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "directory that does not exist",
+			req:      &scannerpb.ScanRequest{Directories: []string{"does-not-exist"}},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "no packages found",
+			req:      &scannerpb.ScanRequest{Directories: []string{"."}},
+			scanErr:  osvscanner.NoPackagesFoundErr,
+			wantCode: codes.OK,
+		},
+		{
+			name:     "unknown licenses found",
+			req:      &scannerpb.ScanRequest{Directories: []string{"."}},
+			scanErr:  osvscanner.ErrUnknownLicensesFound,
+			wantCode: codes.OK,
+		},
+		{
+			name:     "osv.dev unavailable",
+			req:      &scannerpb.ScanRequest{Directories: []string{"."}},
+			scanErr:  fmt.Errorf("%w: osv.dev query failed", osvscanner.ErrAPIFailed),
+			wantCode: codes.Unavailable,
+		},
+		{
+			name:     "timed out",
+			req:      &scannerpb.ScanRequest{Directories: []string{"."}},
+			scanErr:  osvscanner.ErrTimedOut,
+			wantCode: codes.DeadlineExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			root := t.TempDir()
+			if err := os.Symlink(t.TempDir(), filepath.Join(root, "outside")); err != nil {
+				t.Fatal(err)
+			}
+			if tt.noRoot {
+				root = ""
+			}
+
+			client := newClient(t, grpcserver.New(grpcserver.Options{
+				Scan: func(context.Context, osvscanner.ScannerActions, reporter.Reporter) (models.VulnerabilityResults, error) {
+					return models.VulnerabilityResults{}, tt.scanErr
+				},
+				DirectoryRoot: root,
+			}))
+
+			_, err := client.Scan(context.Background(), tt.req)
+			if got := status.Code(err); got != tt.wantCode {
+				t.Errorf("Scan() code = %v, want %v (%v)", got, tt.wantCode, err)
+			}
+		})
+	}
+}
+
+func TestServer_Scan_Purls(t *testing.T) {
+	t.Parallel()
+
+	var purls []byte
+	client := newClient(t, grpcserver.New(grpcserver.Options{
+		Scan: func(_ context.Context, actions osvscanner.ScannerActions, _ reporter.Reporter) (models.VulnerabilityResults, error) {
+			var err error
+			purls, err = os.ReadFile(actions.PURLPaths[0])
+
+			return models.VulnerabilityResults{}, err
+		},
+	}))
+
+	_, err := client.Scan(context.Background(), &scannerpb.ScanRequest{
+		Packages: []*scannerpb.Package{{Purl: "pkg:npm/lodash@4.17.20"}, {Purl: "pkg:pypi/django@4.2.0"}},
+	})
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+
+	want := []string{"pkg:npm/lodash@4.17.20", "pkg:pypi/django@4.2.0"}
+	if diff := cmp.Diff(want, strings.Split(string(purls), "\n")); diff != "" {
+		t.Errorf("purls mismatch (-want +got):\n%s", diff)
+	}
+}
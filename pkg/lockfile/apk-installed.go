@@ -2,10 +2,15 @@ package lockfile
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"io"
+	"path"
+	"path/filepath"
 	"sort"
 	"strings"
+
+	"github.com/google/osv-scanner/internal/cachedregexp"
 )
 
 const AlpineEcosystem Ecosystem = "Alpine"
@@ -83,7 +88,7 @@ func (e ApkInstalledExtractor) Extract(f DepFile) ([]PackageDetails, error) {
 	}
 
 	alpineVersion, alpineVerErr := alpineReleaseExtractor(f)
-	if alpineVerErr == nil { // TODO: Log error? We might not be on a alpine system
+	if alpineVerErr == nil { // without a release, the packages are not scoped to a branch
 		for i := range packages {
 			packages[i].Ecosystem = Ecosystem(string(packages[i].Ecosystem) + ":" + alpineVersion)
 		}
@@ -96,30 +101,73 @@ func (e ApkInstalledExtractor) Extract(f DepFile) ([]PackageDetails, error) {
 	return packages, nil
 }
 
-// alpineReleaseExtractor extracts the release version for an alpine distro
-// will return "" if no release version can be found, or if distro is not alpine
-func alpineReleaseExtractor(opener DepFile) (string, error) {
-	alpineReleaseFile, err := opener.Open("/etc/alpine-release")
+// apkInstalledPath is where the installed database lives, relative to the root of the filesystem
+const apkInstalledPath = "lib/apk/db/installed"
+
+// alpineBranchRegexp matches the branch in the url of an apk repository, such as
+// "https://dl-cdn.alpinelinux.org/alpine/v3.18/main"
+var alpineBranchRegexp = cachedregexp.MustCompile(`/v(\d+\.\d+)/`)
+
+// readNestedFile reads the whole of the file at the path relative to the opener
+func readNestedFile(opener DepFile, path string) (string, error) {
+	file, err := opener.Open(path)
 	if err != nil {
 		return "", err
 	}
-	defer alpineReleaseFile.Close()
+	defer file.Close()
 
-	// Read to string
 	buf := new(strings.Builder)
-	_, err = io.Copy(buf, alpineReleaseFile)
-	if err != nil {
+	if _, err = io.Copy(buf, file); err != nil {
 		return "", err
 	}
 
-	// We only care about the major and minor version
-	// because that's the Alpine version that advisories are published against
-	//
-	// E.g. 3.20.0_alpha20231219  --->  v3.20
-	valueSplit := strings.Split(buf.String(), ".")
-	returnVersion := "v" + valueSplit[0] + "." + valueSplit[1]
+	return buf.String(), nil
+}
+
+// alpineReleaseExtractor extracts the branch of the alpine release that the installed database
+// belongs to, such as "v3.20", from the /etc/alpine-release or /etc/apk/repositories of the same
+// filesystem, which is the one that it has been extracted into if it is not at /lib/apk/db/installed
+//
+// An error is returned if the database is not within a filesystem that has either of those files
+func alpineReleaseExtractor(opener DepFile) (string, error) {
+	dbPath := filepath.ToSlash(opener.Path())
+	if !strings.HasSuffix(dbPath, "/"+apkInstalledPath) {
+		return "", fmt.Errorf("%s is not within the filesystem of an alpine release", opener.Path())
+	}
+	root := strings.TrimSuffix(dbPath, apkInstalledPath)
+	if !path.IsAbs(dbPath) && !filepath.IsAbs(opener.Path()) {
+		// relative paths are opened relative to the directory of the database
+		root = "../../../"
+	}
+
+	release, err := readNestedFile(opener, root+"etc/alpine-release")
+	if err == nil {
+		// We only care about the major and minor version
+		// because that's the Alpine version that advisories are published against
+		//
+		// E.g. 3.20.0_alpha20231219  --->  v3.20
+		valueSplit := strings.Split(strings.TrimSpace(release), ".")
+		if len(valueSplit) >= 2 {
+			return "v" + valueSplit[0] + "." + valueSplit[1], nil
+		}
+	}
+
+	// without (a valid) alpine-release, the branch can still be found from where packages are installed from
+	repositories, repoErr := readNestedFile(opener, root+"etc/apk/repositories")
+	if repoErr != nil {
+		return "", errors.Join(err, repoErr)
+	}
+	for _, line := range strings.Split(repositories, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		if match := alpineBranchRegexp.FindStringSubmatch(line); match != nil {
+			return "v" + match[1], nil
+		}
+	}
 
-	return returnVersion, nil
+	return "", fmt.Errorf("could not determine the alpine release of %s", opener.Path())
 }
 
 var _ Extractor = ApkInstalledExtractor{}
@@ -133,3 +133,43 @@ func TestParseApkInstalled_Multiple(t *testing.T) {
 		},
 	})
 }
+
+func TestParseApkInstalled_AlpineRelease(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseApkInstalled("fixtures/apk/rootfs/lib/apk/db/installed")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "apk-tools",
+			Version:   "2.12.10-r1",
+			Commit:    "0188f510baadbae393472103427b9c1875117136",
+			Ecosystem: "Alpine:v3.18",
+			CompareAs: lockfile.AlpineEcosystem,
+		},
+	})
+}
+
+func TestParseApkInstalled_AlpineReleaseFromRepositories(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseApkInstalled("fixtures/apk/repositories-rootfs/lib/apk/db/installed")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "apk-tools",
+			Version:   "2.12.10-r1",
+			Commit:    "0188f510baadbae393472103427b9c1875117136",
+			Ecosystem: "Alpine:v3.17",
+			CompareAs: lockfile.AlpineEcosystem,
+		},
+	})
+}
@@ -35,10 +35,10 @@ func TestKnownEcosystems(t *testing.T) {
 	expectedCount := numberOfLockfileParsers(t)
 
 	// - npm, yarn, and pnpm,
-	// - pip, poetry, pdm and pipenv,
-	// - maven and gradle,
+	// - pip, poetry, pdm, pipenv, pyproject.toml and conda environments,
+	// - maven, gradle and gradle version catalogs,
 	// all use the same ecosystem so "ignore" those parsers in the count
-	expectedCount -= 6
+	expectedCount -= 9
 
 	ecosystems := lockfile.KnownEcosystems()
 
@@ -0,0 +1,174 @@
+package lockfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// ExternalPackage is a package in the output of an external extractor
+type ExternalPackage struct {
+	Name      string   `json:"name"`
+	Version   string   `json:"version"`
+	Commit    string   `json:"commit,omitempty"`
+	Ecosystem string   `json:"ecosystem"`
+	DepGroups []string `json:"dep_groups,omitempty"`
+}
+
+// externalDescription is the output of running an external extractor with "describe"
+type externalDescription struct {
+	Name     string   `json:"name"`
+	Patterns []string `json:"patterns"`
+}
+
+// externalOutput is the output of running an external extractor with "extract"
+type externalOutput struct {
+	Packages []ExternalPackage `json:"packages"`
+}
+
+// ExternalExtractor extracts packages by running a helper executable, so that formats
+// which osv-scanner does not support can be scanned without changing it.
+//
+// The executable is run as "<command> describe" once when loaded, and must print a JSON object
+// with the "name" of the extractor and the glob "patterns" of the file names it extracts from.
+//
+// It is then run as "<command> extract <path>" for each file that matches, with the contents
+// of the file on stdin, and must print a JSON object with the "packages" in the file, each of
+// which has a "name", "version", and "ecosystem", and optionally a "commit" and "dep_groups".
+// Any non-zero exit code is treated as the file failing to be extracted.
+type ExternalExtractor struct {
+	Name     string
+	Command  string
+	Patterns []string
+}
+
+// runExternalExtractor runs the command with the args and input, decoding what it prints into v
+func runExternalExtractor(command string, input []byte, v any, args ...string) error {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(command, args...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run %s %s: %w: %s", command, args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	if err := json.Unmarshal(stdout.Bytes(), v); err != nil {
+		return fmt.Errorf("could not parse the output of %s %s: %w", command, args[0], err)
+	}
+
+	return nil
+}
+
+// NewExternalExtractor describes the extractor that is run as the command
+func NewExternalExtractor(command string) (ExternalExtractor, error) {
+	var description externalDescription
+	if err := runExternalExtractor(command, nil, &description, "describe"); err != nil {
+		return ExternalExtractor{}, err
+	}
+
+	if description.Name == "" {
+		return ExternalExtractor{}, fmt.Errorf("%s did not describe its name", command)
+	}
+
+	for _, pattern := range description.Patterns {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return ExternalExtractor{}, fmt.Errorf("%s has an invalid pattern %q: %w", command, pattern, err)
+		}
+	}
+
+	return ExternalExtractor{
+		Name:     description.Name,
+		Command:  command,
+		Patterns: description.Patterns,
+	}, nil
+}
+
+func (e ExternalExtractor) ShouldExtract(path string) bool {
+	for _, pattern := range e.Patterns {
+		if matched, _ := filepath.Match(pattern, filepath.Base(path)); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (e ExternalExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	var input bytes.Buffer
+	if _, err := input.ReadFrom(f); err != nil {
+		return []PackageDetails{}, fmt.Errorf("could not read %s: %w", f.Path(), err)
+	}
+
+	var output externalOutput
+	if err := runExternalExtractor(e.Command, input.Bytes(), &output, "extract", f.Path()); err != nil {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+
+	packages := make([]PackageDetails, 0, len(output.Packages))
+	for _, pkg := range output.Packages {
+		packages = append(packages, PackageDetails{
+			Name:      pkg.Name,
+			Version:   pkg.Version,
+			Commit:    pkg.Commit,
+			Ecosystem: Ecosystem(pkg.Ecosystem),
+			CompareAs: Ecosystem(pkg.Ecosystem),
+			DepGroups: pkg.DepGroups,
+		})
+	}
+
+	return packages, nil
+}
+
+var _ Extractor = ExternalExtractor{}
+
+// isExecutable reports if the file can be run, which on Windows is based on its extension
+func isExecutable(info os.FileInfo) bool {
+	if info.IsDir() {
+		return false
+	}
+
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(filepath.Ext(info.Name()), ".exe")
+	}
+
+	return info.Mode()&0111 != 0
+}
+
+// LoadExternalExtractors registers each executable in the directory as an ExternalExtractor,
+// returning the names that they were registered as
+func LoadExternalExtractors(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	var errs []error
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || !isExecutable(info) {
+			continue
+		}
+
+		extractor, err := NewExternalExtractor(filepath.Join(dir, entry.Name()))
+		if err == nil {
+			err = RegisterExtractor(extractor.Name, extractor)
+		}
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+		names = append(names, extractor.Name)
+	}
+
+	return names, errors.Join(errs...)
+}
@@ -0,0 +1,124 @@
+package lockfile_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+// writeExternalExtractor writes a shell script that describes itself with the description,
+// and prints the output once it has read the file from stdin if the file is not empty
+func writeExternalExtractor(t *testing.T, dir, name, description, output string) string {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("external extractor tests use shell scripts, which are not executable on Windows")
+	}
+
+	script := `#!/bin/sh
+if [ "$1" = "describe" ]; then
+  echo '` + description + `'
+  exit 0
+fi
+if [ -z "$(cat)" ]; then
+  echo "$2 is empty" >&2
+  exit 1
+fi
+echo '` + output + `'
+`
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(script), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestExternalExtractor(t *testing.T) {
+	t.Parallel()
+
+	command := writeExternalExtractor(
+		t,
+		t.TempDir(),
+		"ipkg-extractor",
+		`{"name": "ipkg", "patterns": ["*.ipkg", "ipkg.lock"]}`,
+		`{"packages": [{"name": "left-pad", "version": "1.3.0", "ecosystem": "npm", "dep_groups": ["dev"]}]}`,
+	)
+
+	extractor, err := lockfile.NewExternalExtractor(command)
+	if err != nil {
+		t.Fatalf("NewExternalExtractor() error = %v", err)
+	}
+
+	for path, want := range map[string]bool{
+		"/path/to/app.ipkg":  true,
+		"/path/to/ipkg.lock": true,
+		"/path/to/ipkg.json": false,
+	} {
+		if got := extractor.ShouldExtract(path); got != want {
+			t.Errorf("ShouldExtract(%s) = %v, want %v", path, got, want)
+		}
+	}
+
+	packages, err := extractor.Extract(TestDepFile{strings.NewReader("left-pad 1.3.0"), "/path/to/app.ipkg"})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	want := []lockfile.PackageDetails{
+		{
+			Name:      "left-pad",
+			Version:   "1.3.0",
+			Ecosystem: lockfile.NpmEcosystem,
+			CompareAs: lockfile.NpmEcosystem,
+			DepGroups: []string{"dev"},
+		},
+	}
+	if diff := cmp.Diff(want, packages); diff != "" {
+		t.Errorf("Extract() mismatch (-want +got):\n%s", diff)
+	}
+
+	_, err = extractor.Extract(openTestDepFile("/path/to/empty.ipkg"))
+	if err == nil || !strings.Contains(err.Error(), "/path/to/empty.ipkg is empty") {
+		t.Errorf("Extract() error = %v, want the error printed by the extractor", err)
+	}
+}
+
+// Do not make this test parallel because it registers extractors, which other tests use
+func TestLoadExternalExtractors(t *testing.T) {
+	dir := t.TempDir()
+	writeExternalExtractor(t, dir, "custom", `{"name": "custom.lock", "patterns": ["custom.lock"]}`, `{"packages": []}`)
+	writeExternalExtractor(t, dir, "duplicate", `{"name": "yarn.lock", "patterns": ["yarn.lock"]}`, `{"packages": []}`)
+	writeExternalExtractor(t, dir, "greedy", `{"name": "greedy", "patterns": ["*.json", "*.lock"]}`, `{"packages": []}`)
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("not executable"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	names, err := lockfile.LoadExternalExtractors(dir)
+	if !errors.Is(err, lockfile.ErrExtractorAlreadyRegistered) {
+		t.Errorf("LoadExternalExtractors() error = %v, want %v", err, lockfile.ErrExtractorAlreadyRegistered)
+	}
+	if diff := cmp.Diff([]string{"custom.lock", "greedy"}, names); diff != "" {
+		t.Errorf("LoadExternalExtractors() mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, extractedAs := lockfile.FindExtractor("/path/to/custom.lock", ""); extractedAs != "custom.lock" {
+		t.Errorf("FindExtractor() = %s, want the external extractor", extractedAs)
+	}
+
+	// patterns that overlap with the built-in extractors never take files away from them
+	for i := 0; i < 20; i++ {
+		if _, extractedAs := lockfile.FindExtractor("/path/to/package-lock.json", ""); extractedAs != "package-lock.json" {
+			t.Fatalf("FindExtractor() = %s, want the built-in extractor", extractedAs)
+		}
+	}
+	if _, extractedAs := lockfile.FindExtractor("/path/to/other.json", ""); extractedAs != "greedy" {
+		t.Errorf("FindExtractor() = %s, want the external extractor", extractedAs)
+	}
+}
@@ -9,12 +9,35 @@ import (
 
 var lockfileExtractors = map[string]Extractor{}
 
+// lockfileExtractorNames are the names of the extractors in the order they were registered,
+// which is the order that they are checked in when finding the extractor for a file
+var lockfileExtractorNames []string
+
+var ErrExtractorAlreadyRegistered = errors.New("an extractor is already registered")
+
 func registerExtractor(name string, extractor Extractor) {
+	if err := RegisterExtractor(name, extractor); err != nil {
+		panic(err.Error())
+	}
+}
+
+// RegisterExtractor adds an extractor for a format that is not supported by osv-scanner,
+// which is then used for any files that it should extract in the same way as the built-in
+// extractors (which are registered through this too), and can be requested by its name.
+//
+// Extractors are checked in the order they were registered, so a file that a built-in
+// extractor should extract is always extracted by it rather than by a registered one.
+//
+// Extractors must be registered before scanning, as this is not safe to call concurrently.
+func RegisterExtractor(name string, extractor Extractor) error {
 	if _, ok := lockfileExtractors[name]; ok {
-		panic("an extractor is already registered as " + name)
+		return fmt.Errorf("%w as %s", ErrExtractorAlreadyRegistered, name)
 	}
 
 	lockfileExtractors[name] = extractor
+	lockfileExtractorNames = append(lockfileExtractorNames, name)
+
+	return nil
 }
 
 func FindExtractor(path, extractAs string) (Extractor, string) {
@@ -22,8 +45,8 @@ func FindExtractor(path, extractAs string) (Extractor, string) {
 		return lockfileExtractors[extractAs], extractAs
 	}
 
-	for name, extractor := range lockfileExtractors {
-		if extractor.ShouldExtract(path) {
+	for _, name := range lockfileExtractorNames {
+		if extractor := lockfileExtractors[name]; extractor.ShouldExtract(path) {
 			return extractor, name
 		}
 	}
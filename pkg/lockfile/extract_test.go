@@ -36,9 +36,12 @@ func TestFindExtractor(t *testing.T) {
 		"buildscript-gradle.lockfile": "gradle.lockfile",
 		"Cargo.lock":                  "Cargo.lock",
 		"composer.lock":               "composer.lock",
+		"environment.yaml":            "environment.yml",
+		"environment.yml":             "environment.yml",
 		"Gemfile.lock":                "Gemfile.lock",
 		"go.mod":                      "go.mod",
 		"gradle.lockfile":             "gradle.lockfile",
+		"libs.versions.toml":          "libs.versions.toml",
 		"mix.lock":                    "mix.lock",
 		"pdm.lock":                    "pdm.lock",
 		"Pipfile.lock":                "Pipfile.lock",
@@ -48,6 +51,7 @@ func TestFindExtractor(t *testing.T) {
 		"poetry.lock":                 "poetry.lock",
 		"pom.xml":                     "pom.xml",
 		"pubspec.lock":                "pubspec.lock",
+		"pyproject.toml":              "pyproject.toml",
 		"renv.lock":                   "renv.lock",
 		"requirements.txt":            "requirements.txt",
 		"yarn.lock":                   "yarn.lock",
@@ -88,9 +92,11 @@ func TestExtractDeps_FindsExpectedExtractor(t *testing.T) {
 		"Cargo.lock",
 		"composer.lock",
 		"conan.lock",
+		"environment.yml",
 		"Gemfile.lock",
 		"go.mod",
 		"gradle.lockfile",
+		"libs.versions.toml",
 		"mix.lock",
 		"pdm.lock",
 		"Pipfile.lock",
@@ -100,6 +106,7 @@ func TestExtractDeps_FindsExpectedExtractor(t *testing.T) {
 		"poetry.lock",
 		"pom.xml",
 		"pubspec.lock",
+		"pyproject.toml",
 		"renv.lock",
 		"requirements.txt",
 		"yarn.lock",
@@ -0,0 +1,15 @@
+require (
+    golang.org/x/net v1.2.3
+    golang.org/x/sys v0.5.0
+    github.com/BurntSushi/toml v1.0.0
+)
+
+exclude (
+    golang.org/x/sys v0.5.0
+    github.com/BurntSushi/toml v0.9.0
+)
+
+replace (
+    golang.org/x/net => example.com/fork/net v1.4.5
+    golang.org/x/sys v0.5.0 => ./fork/sys
+)
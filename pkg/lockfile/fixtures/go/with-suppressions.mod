@@ -0,0 +1,11 @@
+module my-library
+
+go 1.17
+
+require (
+	github.com/BurntSushi/toml v1.0.0 // osv-scanner:ignore GO-2023-1234 only used for tests
+	gopkg.in/yaml.v2 v2.4.0
+	golang.org/x/net v0.1.0 // indirect
+)
+
+replace golang.org/x/net => example.com/fork/net v0.1.1 // osv-scanner:ignore GHSA-vvpx-j8f3-3w6h patched in our fork
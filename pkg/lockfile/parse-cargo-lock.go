@@ -2,7 +2,9 @@ package lockfile
 
 import (
 	"fmt"
+	"net/url"
 	"path/filepath"
+	"strings"
 
 	"github.com/BurntSushi/toml"
 )
@@ -10,6 +12,8 @@ import (
 type CargoLockPackage struct {
 	Name    string `toml:"name"`
 	Version string `toml:"version"`
+	// Source is where the package is from, which is empty for local packages
+	Source string `toml:"source"`
 }
 
 type CargoLockFile struct {
@@ -19,6 +23,42 @@ type CargoLockFile struct {
 
 const CargoEcosystem Ecosystem = "crates.io"
 
+// cratesIOSources are the sources of packages that are from the crates.io registry,
+// which is either accessed through its git index or sparse index
+var cratesIOSources = []string{
+	"registry+https://github.com/rust-lang/crates.io-index",
+	"sparse+https://index.crates.io/",
+}
+
+// parseCargoSource determines the origin of a package from its source, which
+// is empty for packages from crates.io, along with the commit of git packages
+func parseCargoSource(source string, lockfileVersion int) (string, string) {
+	for _, s := range cratesIOSources {
+		if source == s {
+			return "", ""
+		}
+	}
+
+	if source == "" {
+		return "path", ""
+	}
+
+	repo, commit, isGit := strings.Cut(strings.TrimPrefix(source, "git+"), "#")
+	if !strings.HasPrefix(source, "git+") || !isGit {
+		// packages from other registries do not have a commit
+		return source, ""
+	}
+
+	// from version 4, the query parameters of git sources (such as the branch) are percent encoded
+	if lockfileVersion >= 4 {
+		if unescaped, err := url.PathUnescape(repo); err == nil {
+			repo = unescaped
+		}
+	}
+
+	return "git+" + repo, commit
+}
+
 type CargoLockExtractor struct{}
 
 func (e CargoLockExtractor) ShouldExtract(path string) bool {
@@ -37,11 +77,15 @@ func (e CargoLockExtractor) Extract(f DepFile) ([]PackageDetails, error) {
 	packages := make([]PackageDetails, 0, len(parsedLockfile.Packages))
 
 	for _, lockPackage := range parsedLockfile.Packages {
+		origin, commit := parseCargoSource(lockPackage.Source, parsedLockfile.Version)
+
 		packages = append(packages, PackageDetails{
 			Name:      lockPackage.Name,
 			Version:   lockPackage.Version,
+			Commit:    commit,
 			Ecosystem: CargoEcosystem,
 			CompareAs: CargoEcosystem,
+			Origin:    origin,
 		})
 	}
 
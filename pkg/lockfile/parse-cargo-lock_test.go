@@ -154,6 +154,7 @@ func TestParseCargoLock_TwoPackagesWithLocal(t *testing.T) {
 			Version:   "0.1.0",
 			Ecosystem: lockfile.CargoEcosystem,
 			CompareAs: lockfile.CargoEcosystem,
+			Origin:    "path",
 		},
 	})
 }
@@ -176,3 +177,84 @@ func TestParseCargoLock_PackageWithBuildString(t *testing.T) {
 		},
 	})
 }
+
+func TestParseCargoLock_GitAndRegistrySourcesV3(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseCargoLock("fixtures/cargo/mixed-sources-v3.lock")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "gimli",
+			Version:   "0.24.0",
+			Ecosystem: lockfile.CargoEcosystem,
+			CompareAs: lockfile.CargoEcosystem,
+		},
+		{
+			Name:      "regex",
+			Version:   "1.5.4",
+			Commit:    "9b96c5e4efd3d4a7e9a7d3e07c8b5c0b2cfb3f2e",
+			Ecosystem: lockfile.CargoEcosystem,
+			CompareAs: lockfile.CargoEcosystem,
+			Origin:    "git+https://github.com/rust-lang/regex?branch=feature/x",
+		},
+		{
+			Name:      "private-crate",
+			Version:   "2.0.0",
+			Ecosystem: lockfile.CargoEcosystem,
+			CompareAs: lockfile.CargoEcosystem,
+			Origin:    "registry+https://my-registry.example.com/index",
+		},
+		{
+			Name:      "workspace-member",
+			Version:   "0.1.0",
+			Ecosystem: lockfile.CargoEcosystem,
+			CompareAs: lockfile.CargoEcosystem,
+			Origin:    "path",
+		},
+	})
+}
+
+func TestParseCargoLock_GitAndRegistrySourcesV4(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseCargoLock("fixtures/cargo/mixed-sources-v4.lock")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "gimli",
+			Version:   "0.24.0",
+			Ecosystem: lockfile.CargoEcosystem,
+			CompareAs: lockfile.CargoEcosystem,
+		},
+		{
+			Name:      "addr2line",
+			Version:   "0.15.2",
+			Ecosystem: lockfile.CargoEcosystem,
+			CompareAs: lockfile.CargoEcosystem,
+		},
+		{
+			Name:      "regex",
+			Version:   "1.5.4",
+			Commit:    "9b96c5e4efd3d4a7e9a7d3e07c8b5c0b2cfb3f2e",
+			Ecosystem: lockfile.CargoEcosystem,
+			CompareAs: lockfile.CargoEcosystem,
+			Origin:    "git+https://github.com/rust-lang/regex?branch=feature/x",
+		},
+		{
+			Name:      "workspace-member",
+			Version:   "0.1.0",
+			Ecosystem: lockfile.CargoEcosystem,
+			CompareAs: lockfile.CargoEcosystem,
+			Origin:    "path",
+		},
+	})
+}
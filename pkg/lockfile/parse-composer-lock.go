@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"unicode"
 )
 
 type ComposerPackage struct {
@@ -14,6 +15,9 @@ type ComposerPackage struct {
 	} `json:"dist"`
 }
 
+// ComposerLock is the contents of a composer.lock file, which also has the
+// platform requirements of the project (such as "php" and "ext-json") that are
+// not included as they are provided by the environment rather than Packagist
 type ComposerLock struct {
 	Packages    []ComposerPackage `json:"packages"`
 	PackagesDev []ComposerPackage `json:"packages-dev"`
@@ -21,6 +25,16 @@ type ComposerLock struct {
 
 const ComposerEcosystem Ecosystem = "Packagist"
 
+// normalizeComposerVersion removes the "v" prefix that versions are locked with if
+// their git tags have one, so that "v1.2.3" is matched in the same way as "1.2.3"
+func normalizeComposerVersion(version string) string {
+	if len(version) > 1 && (version[0] == 'v' || version[0] == 'V') && unicode.IsDigit(rune(version[1])) {
+		return version[1:]
+	}
+
+	return version
+}
+
 type ComposerLockExtractor struct{}
 
 func (e ComposerLockExtractor) ShouldExtract(path string) bool {
@@ -46,7 +60,7 @@ func (e ComposerLockExtractor) Extract(f DepFile) ([]PackageDetails, error) {
 	for _, composerPackage := range parsedLockfile.Packages {
 		packages = append(packages, PackageDetails{
 			Name:      composerPackage.Name,
-			Version:   composerPackage.Version,
+			Version:   normalizeComposerVersion(composerPackage.Version),
 			Commit:    composerPackage.Dist.Reference,
 			Ecosystem: ComposerEcosystem,
 			CompareAs: ComposerEcosystem,
@@ -56,7 +70,7 @@ func (e ComposerLockExtractor) Extract(f DepFile) ([]PackageDetails, error) {
 	for _, composerPackage := range parsedLockfile.PackagesDev {
 		packages = append(packages, PackageDetails{
 			Name:      composerPackage.Name,
-			Version:   composerPackage.Version,
+			Version:   normalizeComposerVersion(composerPackage.Version),
 			Commit:    composerPackage.Dist.Reference,
 			Ecosystem: ComposerEcosystem,
 			CompareAs: ComposerEcosystem,
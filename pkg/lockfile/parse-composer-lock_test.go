@@ -184,3 +184,46 @@ func TestParseComposerLock_TwoPackagesAlt(t *testing.T) {
 		},
 	})
 }
+
+func TestParseComposerLock_PlatformAndPrefixedVersions(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseComposerLock("fixtures/composer/platform-and-prefixed-versions.json")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	// the platform requirements are not packages from Packagist, so are not included
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "guzzlehttp/guzzle",
+			Version:   "7.4.5",
+			Commit:    "1dd98b0564cb3f6bd16ce683cb755f94c10fbd82",
+			Ecosystem: lockfile.ComposerEcosystem,
+			CompareAs: lockfile.ComposerEcosystem,
+		},
+		{
+			Name:      "monolog/monolog",
+			Version:   "2.8.0",
+			Commit:    "720488632c590286b88b80e62aa3d3d551ad4a50",
+			Ecosystem: lockfile.ComposerEcosystem,
+			CompareAs: lockfile.ComposerEcosystem,
+		},
+		{
+			Name:      "acme/internal-tools",
+			Version:   "dev-main",
+			Commit:    "9f2e1c1c0b6f0a2cd1a5d8e7d0f3b0cbb2a6c1de",
+			Ecosystem: lockfile.ComposerEcosystem,
+			CompareAs: lockfile.ComposerEcosystem,
+		},
+		{
+			Name:      "phpunit/phpunit",
+			Version:   "9.5.20",
+			Commit:    "12bc8879fb65aef2138b26fc633cb1e3620cffba",
+			Ecosystem: lockfile.ComposerEcosystem,
+			CompareAs: lockfile.ComposerEcosystem,
+			DepGroups: []string{"dev"},
+		},
+	})
+}
@@ -0,0 +1,148 @@
+package lockfile
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/cachedregexp"
+	"gopkg.in/yaml.v3"
+)
+
+// condaOrigin is the origin of packages installed by conda rather than pip,
+// which are named and versioned by their conda channel rather than PyPI and
+// so cannot be matched against PyPI advisories
+const condaOrigin = "conda"
+
+type CondaEnvironmentFile struct {
+	Dependencies []CondaDependency `yaml:"dependencies"`
+}
+
+// CondaDependency is an entry in the dependencies of a conda environment, which is
+// either a conda match spec (e.g. "numpy=1.24") or a list of requirements to be
+// installed with pip
+type CondaDependency struct {
+	Spec string
+	Pip  []string
+}
+
+var _ yaml.Unmarshaler = &CondaDependency{}
+
+func (cd *CondaDependency) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		return value.Decode(&cd.Spec)
+	}
+
+	var m struct {
+		Pip []string `yaml:"pip"`
+	}
+
+	if err := value.Decode(&m); err != nil {
+		return err
+	}
+
+	cd.Pip = m.Pip
+
+	return nil
+}
+
+// parseCondaMatchSpec parses a conda match spec such as "numpy=1.24", "numpy>=1.24,<1.26",
+// "numpy 1.24.3 py39_0", or "conda-forge::numpy==1.24.3=py39_0", using the first version
+// that the spec is bound by (if any) as the version of the package
+func parseCondaMatchSpec(spec string) PackageDetails {
+	// the channel is not needed, as conda packages cannot be matched against advisories regardless
+	if _, after, found := strings.Cut(spec, "::"); found {
+		spec = after
+	}
+
+	name, constraints := spec, ""
+	if i := strings.IndexAny(spec, " <>=!~"); i >= 0 {
+		name, constraints = spec[:i], spec[i:]
+	}
+
+	// only the first of any alternatives is considered
+	constraints, _, _ = strings.Cut(constraints, "|")
+
+	version := "0.0.0"
+
+	for _, constraint := range strings.Split(constraints, ",") {
+		re := cachedregexp.MustCompile(`^\s*(==|=|~=|>=|<=|!=|<|>)?\s*([^\s=<>!~,]+)`)
+		match := re.FindStringSubmatch(constraint)
+
+		if match == nil {
+			continue
+		}
+
+		if match[1] == "" || match[1] == "==" || match[1] == "=" || match[1] == "~=" || match[1] == ">=" {
+			// "=1.24" and "1.24.*" match any 1.24 version, so use the lowest one
+			version = strings.TrimSuffix(strings.TrimSuffix(match[2], "*"), ".")
+
+			break
+		}
+	}
+
+	return PackageDetails{
+		Name:      strings.ToLower(strings.TrimSpace(name)),
+		Version:   version,
+		Ecosystem: PipEcosystem,
+		CompareAs: PipEcosystem,
+		Origin:    condaOrigin,
+	}
+}
+
+type CondaEnvironmentExtractor struct{}
+
+func (e CondaEnvironmentExtractor) ShouldExtract(path string) bool {
+	base := filepath.Base(path)
+
+	return base == "environment.yml" || base == "environment.yaml"
+}
+
+func (e CondaEnvironmentExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	var parsedFile *CondaEnvironmentFile
+
+	err := yaml.NewDecoder(f).Decode(&parsedFile)
+
+	if err != nil && !errors.Is(err, io.EOF) {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+	if parsedFile == nil {
+		return []PackageDetails{}, nil
+	}
+
+	packages := make([]PackageDetails, 0, len(parsedFile.Dependencies))
+
+	for _, dep := range parsedFile.Dependencies {
+		if dep.Spec != "" {
+			packages = append(packages, parseCondaMatchSpec(dep.Spec))
+
+			continue
+		}
+
+		for _, line := range dep.Pip {
+			line = removeComments(line)
+
+			// requirements that are not from PyPI (such as git urls) are not supported
+			if isNotRequirementLine(line) || strings.Contains(line, "://") {
+				continue
+			}
+
+			packages = append(packages, parseLine(line))
+		}
+	}
+
+	return packages, nil
+}
+
+var _ Extractor = CondaEnvironmentExtractor{}
+
+//nolint:gochecknoinits
+func init() {
+	registerExtractor("environment.yml", CondaEnvironmentExtractor{})
+}
+
+func ParseCondaEnvironment(pathToLockfile string) ([]PackageDetails, error) {
+	return extractFromFile(pathToLockfile, CondaEnvironmentExtractor{})
+}
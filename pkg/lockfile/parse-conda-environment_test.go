@@ -0,0 +1,201 @@
+package lockfile_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestCondaEnvironmentExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: "environment.yml",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/environment.yml",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/environment.yaml",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/environment.yml/file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/my/environment.yml.file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path.to.my.environment.yml",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := lockfile.CondaEnvironmentExtractor{}
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("Extract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCondaEnvironment_FileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseCondaEnvironment("fixtures/conda/does-not-exist")
+
+	expectErrIs(t, err, fs.ErrNotExist)
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseCondaEnvironment_InvalidYaml(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseCondaEnvironment("fixtures/conda/not-yaml.txt")
+
+	expectErrContaining(t, err, "could not extract from")
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseCondaEnvironment_Empty(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseCondaEnvironment("fixtures/conda/empty.yml")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseCondaEnvironment_NoDependencies(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseCondaEnvironment("fixtures/conda/no-dependencies.yml")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseCondaEnvironment_CondaPackages(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseCondaEnvironment("fixtures/conda/conda-packages.yml")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "python",
+			Version:   "3.10",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+			Origin:    "conda",
+		},
+		{
+			Name:      "numpy",
+			Version:   "1.24",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+			Origin:    "conda",
+		},
+		{
+			Name:      "pandas",
+			Version:   "2.0.3",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+			Origin:    "conda",
+		},
+		{
+			Name:      "scipy",
+			Version:   "1.11.1",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+			Origin:    "conda",
+		},
+		{
+			Name:      "scikit-learn",
+			Version:   "1.3.0",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+			Origin:    "conda",
+		},
+		{
+			Name:      "matplotlib",
+			Version:   "0.0.0",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+			Origin:    "conda",
+		},
+	})
+}
+
+func TestParseCondaEnvironment_PipPackages(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseCondaEnvironment("fixtures/conda/pip-packages.yml")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "python",
+			Version:   "3.10",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+			Origin:    "conda",
+		},
+		{
+			Name:      "pip",
+			Version:   "0.0.0",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+			Origin:    "conda",
+		},
+		{
+			Name:      "requests",
+			Version:   "2.31.0",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+		{
+			Name:      "flask",
+			Version:   "2.3",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+	})
+}
@@ -22,6 +22,23 @@ func deduplicatePackages(packages map[string]PackageDetails) map[string]PackageD
 	return details
 }
 
+// extractGoModSuppressions returns the suppressions in the comments at the end of the line
+func extractGoModSuppressions(line *modfile.Line) []Suppression {
+	if line == nil {
+		return nil
+	}
+
+	var suppressions []Suppression
+
+	for _, comment := range line.Suffix {
+		if suppression, ok := parseSuppressionComment(strings.TrimPrefix(comment.Token, "//")); ok {
+			suppressions = append(suppressions, suppression)
+		}
+	}
+
+	return suppressions
+}
+
 type GoLockExtractor struct{}
 
 func (e GoLockExtractor) ShouldExtract(path string) bool {
@@ -45,13 +62,19 @@ func (e GoLockExtractor) Extract(f DepFile) ([]PackageDetails, error) {
 
 	for _, require := range parsedLockfile.Require {
 		packages[require.Mod.Path+"@"+require.Mod.Version] = PackageDetails{
-			Name:      require.Mod.Path,
-			Version:   strings.TrimPrefix(require.Mod.Version, "v"),
-			Ecosystem: GoEcosystem,
-			CompareAs: GoEcosystem,
+			Name:         require.Mod.Path,
+			Version:      strings.TrimPrefix(require.Mod.Version, "v"),
+			Ecosystem:    GoEcosystem,
+			CompareAs:    GoEcosystem,
+			Suppressions: extractGoModSuppressions(require.Syntax),
 		}
 	}
 
+	// excluded versions cannot be selected, so the go command will use another version instead
+	for _, exclude := range parsedLockfile.Exclude {
+		delete(packages, exclude.Mod.Path+"@"+exclude.Mod.Version)
+	}
+
 	for _, replace := range parsedLockfile.Replace {
 		var replacements []string
 
@@ -74,11 +97,23 @@ func (e GoLockExtractor) Extract(f DepFile) ([]PackageDetails, error) {
 		}
 
 		for _, replacement := range replacements {
+			// modules replaced with a local directory are built from that directory rather than
+			// the module proxy, so they are kept with their original version but cannot be matched
+			if modfile.IsDirectoryPath(replace.New.Path) {
+				pkg := packages[replacement]
+				pkg.Origin = replace.New.Path
+				packages[replacement] = pkg
+
+				continue
+			}
+
 			packages[replacement] = PackageDetails{
 				Name:      replace.New.Path,
 				Version:   strings.TrimPrefix(replace.New.Version, "v"),
 				Ecosystem: GoEcosystem,
 				CompareAs: GoEcosystem,
+				// suppressions can be on either the require or the replace directive
+				Suppressions: append(packages[replacement].Suppressions, extractGoModSuppressions(replace.Syntax)...),
 			}
 		}
 	}
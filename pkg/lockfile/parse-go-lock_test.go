@@ -243,10 +243,11 @@ func TestParseGoLock_Replacements_Local(t *testing.T) {
 
 	expectPackages(t, packages, []lockfile.PackageDetails{
 		{
-			Name:      "./fork/net",
-			Version:   "",
+			Name:      "golang.org/x/net",
+			Version:   "1.2.3",
 			Ecosystem: lockfile.GoEcosystem,
 			CompareAs: lockfile.GoEcosystem,
+			Origin:    "./fork/net",
 		},
 		{
 			Name:      "github.com/BurntSushi/toml",
@@ -325,3 +326,71 @@ func TestParseGoLock_Replacements_NoVersion(t *testing.T) {
 		},
 	})
 }
+
+func TestParseGoLock_Exclusions(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseGoLock("fixtures/go/exclude.mod")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "github.com/BurntSushi/toml",
+			Version:   "1.0.0",
+			Ecosystem: lockfile.GoEcosystem,
+			CompareAs: lockfile.GoEcosystem,
+		},
+		{
+			Name:      "example.com/fork/net",
+			Version:   "1.4.5",
+			Ecosystem: lockfile.GoEcosystem,
+			CompareAs: lockfile.GoEcosystem,
+		},
+	})
+}
+
+func TestParseGoLock_WithSuppressions(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseGoLock("fixtures/go/with-suppressions.mod")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "github.com/BurntSushi/toml",
+			Version:   "1.0.0",
+			Ecosystem: lockfile.GoEcosystem,
+			CompareAs: lockfile.GoEcosystem,
+			Suppressions: []lockfile.Suppression{
+				{ID: "GO-2023-1234", Reason: "only used for tests"},
+			},
+		},
+		{
+			Name:      "gopkg.in/yaml.v2",
+			Version:   "2.4.0",
+			Ecosystem: lockfile.GoEcosystem,
+			CompareAs: lockfile.GoEcosystem,
+		},
+		{
+			Name:      "example.com/fork/net",
+			Version:   "0.1.1",
+			Ecosystem: lockfile.GoEcosystem,
+			CompareAs: lockfile.GoEcosystem,
+			Suppressions: []lockfile.Suppression{
+				{ID: "GHSA-vvpx-j8f3-3w6h", Reason: "patched in our fork"},
+			},
+		},
+		{
+			Name:      "stdlib",
+			Version:   "1.17",
+			Ecosystem: lockfile.GoEcosystem,
+			CompareAs: lockfile.GoEcosystem,
+		},
+	})
+}
@@ -60,11 +60,19 @@ func (e GradleLockExtractor) Extract(f DepFile) ([]PackageDetails, error) {
 			continue
 		}
 
-		pkg, err := parseToGradlePackageDetail(lockLine)
+		lockLine, comment, hasComment := strings.Cut(lockLine, gradleLockFileCommentPrefix)
+
+		pkg, err := parseToGradlePackageDetail(strings.TrimSpace(lockLine))
 		if err != nil {
 			continue
 		}
 
+		if hasComment {
+			if suppression, ok := parseSuppressionComment(comment); ok {
+				pkg.Suppressions = append(pkg.Suppressions, suppression)
+			}
+		}
+
 		pkgs = append(pkgs, pkg)
 	}
 
@@ -0,0 +1,161 @@
+package lockfile
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// GradleVersionCatalogVersion represents a version in a Gradle version catalog,
+// which can either be a plain string or a table with a reference or rich version.
+type GradleVersionCatalogVersion struct {
+	Value    string `toml:"-"`
+	Ref      string `toml:"ref"`
+	Strictly string `toml:"strictly"`
+	Require  string `toml:"require"`
+	Prefer   string `toml:"prefer"`
+}
+
+func (v *GradleVersionCatalogVersion) UnmarshalTOML(data any) error {
+	switch d := data.(type) {
+	case string:
+		v.Value = d
+	case map[string]any:
+		for key, field := range map[string]*string{
+			"ref":      &v.Ref,
+			"strictly": &v.Strictly,
+			"require":  &v.Require,
+			"prefer":   &v.Prefer,
+		} {
+			if s, ok := d[key].(string); ok {
+				*field = s
+			}
+		}
+	default:
+		return fmt.Errorf("unexpected version type %T", data)
+	}
+
+	return nil
+}
+
+// GradleVersionCatalogLibrary represents a library in a Gradle version catalog,
+// which can either be a "group:artifact:version" string or a table.
+type GradleVersionCatalogLibrary struct {
+	Module  string                      `toml:"module"`
+	Group   string                      `toml:"group"`
+	Name    string                      `toml:"name"`
+	Version GradleVersionCatalogVersion `toml:"version"`
+}
+
+func (l *GradleVersionCatalogLibrary) UnmarshalTOML(data any) error {
+	switch d := data.(type) {
+	case string:
+		parts := strings.SplitN(d, ":", 3)
+		l.Module = strings.Join(parts[:min(len(parts), 2)], ":")
+		if len(parts) == 3 {
+			l.Version.Value = parts[2]
+		}
+	case map[string]any:
+		l.Module, _ = d["module"].(string)
+		l.Group, _ = d["group"].(string)
+		l.Name, _ = d["name"].(string)
+		if version, ok := d["version"]; ok {
+			return l.Version.UnmarshalTOML(version)
+		}
+	default:
+		return fmt.Errorf("unexpected library type %T", data)
+	}
+
+	return nil
+}
+
+type GradleVersionCatalogFile struct {
+	Versions  map[string]GradleVersionCatalogVersion `toml:"versions"`
+	Libraries map[string]GradleVersionCatalogLibrary `toml:"libraries"`
+}
+
+// resolve returns the concrete version of the given catalog version,
+// following references to the [versions] table.
+//
+// An empty string is returned if the version is a range or dynamic version,
+// since those cannot be resolved without a registry.
+func (c GradleVersionCatalogFile) resolve(version GradleVersionCatalogVersion) string {
+	if version.Ref != "" {
+		// references in the [versions] table cannot themselves be references
+		ref, ok := c.Versions[version.Ref]
+		if !ok || ref.Ref != "" {
+			return ""
+		}
+
+		return c.resolve(ref)
+	}
+
+	for _, v := range []string{version.Value, version.Prefer, version.Require, version.Strictly} {
+		if v == "" {
+			continue
+		}
+
+		if strings.ContainsAny(v, "[](),+") {
+			return ""
+		}
+
+		return v
+	}
+
+	return ""
+}
+
+type GradleVersionCatalogExtractor struct{}
+
+func (e GradleVersionCatalogExtractor) ShouldExtract(path string) bool {
+	return filepath.Base(path) == "libs.versions.toml"
+}
+
+func (e GradleVersionCatalogExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	var parsedCatalog *GradleVersionCatalogFile
+
+	_, err := toml.NewDecoder(f).Decode(&parsedCatalog)
+
+	if err != nil {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+
+	packages := make([]PackageDetails, 0, len(parsedCatalog.Libraries))
+
+	for _, library := range parsedCatalog.Libraries {
+		name := library.Module
+		if name == "" && library.Group != "" && library.Name != "" {
+			name = library.Group + ":" + library.Name
+		}
+
+		version := parsedCatalog.resolve(library.Version)
+
+		// libraries without a version are expected to get them from a platform or plugin,
+		// so we can't know what version they actually are
+		if !strings.Contains(name, ":") || version == "" {
+			continue
+		}
+
+		packages = append(packages, PackageDetails{
+			Name:      name,
+			Version:   version,
+			Ecosystem: MavenEcosystem,
+			CompareAs: MavenEcosystem,
+		})
+	}
+
+	return packages, nil
+}
+
+var _ Extractor = GradleVersionCatalogExtractor{}
+
+//nolint:gochecknoinits
+func init() {
+	registerExtractor("libs.versions.toml", GradleVersionCatalogExtractor{})
+}
+
+func ParseGradleVersionCatalog(pathToLockfile string) ([]PackageDetails, error) {
+	return extractFromFile(pathToLockfile, GradleVersionCatalogExtractor{})
+}
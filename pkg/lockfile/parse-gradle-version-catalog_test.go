@@ -0,0 +1,152 @@
+package lockfile_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestGradleVersionCatalogExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: "libs.versions.toml",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/gradle/libs.versions.toml",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/libs.versions.toml/file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/my/libs.versions.toml.file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path.to.my.libs.versions.toml",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := lockfile.GradleVersionCatalogExtractor{}
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("Extract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseGradleVersionCatalog_FileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseGradleVersionCatalog("fixtures/gradle-catalog/does-not-exist")
+
+	expectErrIs(t, err, fs.ErrNotExist)
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseGradleVersionCatalog_InvalidToml(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseGradleVersionCatalog("fixtures/gradle-catalog/not-toml.txt")
+
+	expectErrContaining(t, err, "could not extract from")
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseGradleVersionCatalog_Empty(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseGradleVersionCatalog("fixtures/gradle-catalog/empty.toml")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseGradleVersionCatalog_OneLibrary(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseGradleVersionCatalog("fixtures/gradle-catalog/one-library.toml")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "org.springframework.security:spring-security-crypto",
+			Version:   "5.7.3",
+			Ecosystem: lockfile.MavenEcosystem,
+			CompareAs: lockfile.MavenEcosystem,
+		},
+	})
+}
+
+func TestParseGradleVersionCatalog_MultipleLibraries(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseGradleVersionCatalog("fixtures/gradle-catalog/multiple-libraries.toml")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "org.codehaus.groovy:groovy",
+			Version:   "3.0.5",
+			Ecosystem: lockfile.MavenEcosystem,
+			CompareAs: lockfile.MavenEcosystem,
+		},
+		{
+			Name:      "org.codehaus.groovy:groovy-json",
+			Version:   "3.0.5",
+			Ecosystem: lockfile.MavenEcosystem,
+			CompareAs: lockfile.MavenEcosystem,
+		},
+		{
+			Name:      "org.apache.commons:commons-lang3",
+			Version:   "3.9",
+			Ecosystem: lockfile.MavenEcosystem,
+			CompareAs: lockfile.MavenEcosystem,
+		},
+		{
+			Name:      "junit:junit",
+			Version:   "4.13.2",
+			Ecosystem: lockfile.MavenEcosystem,
+			CompareAs: lockfile.MavenEcosystem,
+		},
+		{
+			Name:      "com.google.guava:guava",
+			Version:   "31.1-jre",
+			Ecosystem: lockfile.MavenEcosystem,
+			CompareAs: lockfile.MavenEcosystem,
+		},
+	})
+}
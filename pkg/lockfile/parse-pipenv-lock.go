@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"golang.org/x/exp/maps"
 )
@@ -26,32 +28,68 @@ func (e PipenvLockExtractor) ShouldExtract(path string) bool {
 }
 
 func (e PipenvLockExtractor) Extract(f DepFile) ([]PackageDetails, error) {
-	var parsedLockfile *PipenvLock
+	var sections map[string]json.RawMessage
 
-	err := json.NewDecoder(f).Decode(&parsedLockfile)
+	err := json.NewDecoder(f).Decode(&sections)
 
 	if err != nil {
 		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
 	}
 
+	// besides "default" and "develop", every section other than "_meta" is a custom
+	// package category, which are sorted so that the groups of packages are stable
+	categories := make([]string, 0, len(sections))
+	for category := range sections {
+		if category != "_meta" && category != "default" && category != "develop" {
+			categories = append(categories, category)
+		}
+	}
+	slices.Sort(categories)
+	categories = append([]string{"default", "develop"}, categories...)
+
 	details := make(map[string]PackageDetails)
 
-	addPkgDetails(details, parsedLockfile.Packages, "")
-	addPkgDetails(details, parsedLockfile.PackagesDev, "dev")
+	for _, category := range categories {
+		raw, ok := sections[category]
+		if !ok {
+			continue
+		}
+
+		var packages map[string]PipenvPackage
+		if err := json.Unmarshal(raw, &packages); err != nil {
+			return []PackageDetails{}, fmt.Errorf("could not extract %s from %s: %w", category, f.Path(), err)
+		}
+
+		switch category {
+		case "default":
+			addPkgDetails(details, packages, "")
+		case "develop":
+			addPkgDetails(details, packages, "dev")
+		default:
+			addPkgDetails(details, packages, category)
+		}
+	}
 
 	return maps.Values(details), nil
 }
 
+// normalizePipenvVersion removes the "==" (or "===") that pinned versions are prefixed with
+func normalizePipenvVersion(version string) string {
+	return strings.TrimSpace(strings.TrimLeft(version, "="))
+}
+
 func addPkgDetails(details map[string]PackageDetails, packages map[string]PipenvPackage, group string) {
 	for name, pipenvPackage := range packages {
-		if pipenvPackage.Version == "" {
+		version := normalizePipenvVersion(pipenvPackage.Version)
+
+		// packages from git or a local path do not have a version, and so can't be matched
+		if version == "" {
 			continue
 		}
 
-		version := pipenvPackage.Version[2:]
-
-		if _, ok := details[name+"@"+version]; !ok {
-			pkgDetails := PackageDetails{
+		pkgDetails, ok := details[name+"@"+version]
+		if !ok {
+			pkgDetails = PackageDetails{
 				Name:      name,
 				Version:   version,
 				Ecosystem: PipenvEcosystem,
@@ -60,8 +98,11 @@ func addPkgDetails(details map[string]PackageDetails, packages map[string]Pipenv
 			if group != "" {
 				pkgDetails.DepGroups = append(pkgDetails.DepGroups, group)
 			}
-			details[name+"@"+version] = pkgDetails
+		} else if len(pkgDetails.DepGroups) > 0 && group != "" && !slices.Contains(pkgDetails.DepGroups, group) {
+			// packages in the default category are always installed, so are not put in any groups
+			pkgDetails.DepGroups = append(pkgDetails.DepGroups, group)
 		}
+		details[name+"@"+version] = pkgDetails
 	}
 }
 
@@ -228,3 +228,36 @@ func TestParsePipenvLock_PackageWithoutVersion(t *testing.T) {
 
 	expectPackages(t, packages, []lockfile.PackageDetails{})
 }
+
+func TestParsePipenvLock_Categories(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParsePipenvLock("fixtures/pipenv/categories.json")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "requests",
+			Version:   "2.31.0",
+			Ecosystem: lockfile.PipenvEcosystem,
+			CompareAs: lockfile.PipenvEcosystem,
+		},
+		{
+			Name:      "pytest",
+			Version:   "7.4.3",
+			Ecosystem: lockfile.PipenvEcosystem,
+			CompareAs: lockfile.PipenvEcosystem,
+			DepGroups: []string{"dev", "docs"},
+		},
+		{
+			Name:      "sphinx",
+			Version:   "7.2.6",
+			Ecosystem: lockfile.PipenvEcosystem,
+			CompareAs: lockfile.PipenvEcosystem,
+			DepGroups: []string{"docs"},
+		},
+	})
+}
@@ -0,0 +1,248 @@
+package lockfile
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/google/osv-scanner/internal/cachedregexp"
+	"golang.org/x/exp/maps"
+)
+
+// PoetryDependency represents a dependency in the [tool.poetry.dependencies] table,
+// which can either be a version constraint string, a table, or a list of tables
+// with constraints for different environments (of which the first is used).
+type PoetryDependency struct {
+	Version  string `toml:"version"`
+	Optional bool   `toml:"optional"`
+	Markers  string `toml:"markers"`
+	Git      string `toml:"git"`
+	Path     string `toml:"path"`
+	URL      string `toml:"url"`
+}
+
+func (d *PoetryDependency) UnmarshalTOML(data any) error {
+	switch v := data.(type) {
+	case string:
+		d.Version = v
+	case map[string]any:
+		for key, field := range map[string]*string{
+			"version": &d.Version,
+			"markers": &d.Markers,
+			"git":     &d.Git,
+			"path":    &d.Path,
+			"url":     &d.URL,
+		} {
+			if s, ok := v[key].(string); ok {
+				*field = s
+			}
+		}
+		d.Optional, _ = v["optional"].(bool)
+	case []map[string]any:
+		if len(v) > 0 {
+			return d.UnmarshalTOML(v[0])
+		}
+	case []any:
+		if len(v) > 0 {
+			return d.UnmarshalTOML(v[0])
+		}
+	default:
+		return fmt.Errorf("unexpected dependency type %T", data)
+	}
+
+	return nil
+}
+
+type PyprojectTOMLFile struct {
+	Project struct {
+		Dependencies         []string            `toml:"dependencies"`
+		OptionalDependencies map[string][]string `toml:"optional-dependencies"`
+	} `toml:"project"`
+	Tool struct {
+		Poetry struct {
+			Dependencies    map[string]PoetryDependency `toml:"dependencies"`
+			DevDependencies map[string]PoetryDependency `toml:"dev-dependencies"`
+			Group           map[string]struct {
+				Dependencies map[string]PoetryDependency `toml:"dependencies"`
+			} `toml:"group"`
+		} `toml:"poetry"`
+	} `toml:"tool"`
+}
+
+// parsePoetryConstraint returns the first version that a Poetry version constraint
+// such as "^1.2.3", "~1.2", "1.2.*", or ">=1.2,<2.0" is bound by, which is the
+// lowest version that the constraint allows
+func parsePoetryConstraint(constraint string) string {
+	// only the first of any alternatives is considered
+	constraint, _, _ = strings.Cut(constraint, "|")
+
+	for _, part := range strings.Split(constraint, ",") {
+		re := cachedregexp.MustCompile(`^\s*(===|==|~=|>=|<=|!=|\^|~|=|<|>)?\s*([^\s,]+)`)
+		match := re.FindStringSubmatch(part)
+
+		if match == nil {
+			continue
+		}
+
+		switch match[1] {
+		case "", "===", "==", "=", "~=", "~", "^", ">=":
+			// "1.2.*" matches any 1.2 version, so use the lowest one
+			version := strings.TrimSuffix(strings.TrimSuffix(match[2], "*"), ".")
+			if version != "" {
+				return version
+			}
+		}
+	}
+
+	return "0.0.0"
+}
+
+// parsePoetryDependency returns the details of a dependency declared in one of the Poetry
+// tables, using the first version that its constraint is bound by as its version
+func parsePoetryDependency(name string, dep PoetryDependency) PackageDetails {
+	details := PackageDetails{
+		Name:      normalizedRequirementName(name),
+		Version:   parsePoetryConstraint(dep.Version),
+		Ecosystem: PipEcosystem,
+		CompareAs: PipEcosystem,
+		Marker:    dep.Markers,
+	}
+
+	// dependencies that are not from PyPI cannot be matched against its advisories
+	for _, origin := range []string{dep.Git, dep.Path, dep.URL} {
+		if origin != "" {
+			details.Origin = origin
+
+			break
+		}
+	}
+
+	if dep.Optional {
+		details.DepGroups = append(details.DepGroups, "optional")
+	}
+
+	return details
+}
+
+// readPoetryLockVersions returns the versions of the packages in the poetry.lock next to
+// the pyproject.toml, keyed by their normalized name, or nil if it cannot be read.
+//
+// Any errors with the poetry.lock itself are reported when it is scanned as a lockfile.
+func readPoetryLockVersions(f DepFile) map[string]string {
+	lockfile, err := f.Open("poetry.lock")
+	if err != nil {
+		return nil
+	}
+	defer lockfile.Close()
+
+	packages, err := PoetryLockExtractor{}.Extract(lockfile)
+	if err != nil {
+		return nil
+	}
+
+	versions := make(map[string]string, len(packages))
+	for _, pkg := range packages {
+		versions[normalizedRequirementName(pkg.Name)] = pkg.Version
+	}
+
+	return versions
+}
+
+type PyprojectTOMLExtractor struct{}
+
+func (e PyprojectTOMLExtractor) ShouldExtract(path string) bool {
+	return filepath.Base(path) == "pyproject.toml"
+}
+
+func (e PyprojectTOMLExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	var parsedFile *PyprojectTOMLFile
+
+	_, err := toml.NewDecoder(f).Decode(&parsedFile)
+
+	if err != nil {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+
+	locked := readPoetryLockVersions(f)
+
+	packages := map[string]PackageDetails{}
+	add := func(details PackageDetails, group string) {
+		// the locked version is what is actually installed, so prefer it over the constraint
+		if version, ok := locked[details.Name]; ok && details.Origin == "" {
+			details.Version = version
+		}
+
+		if group != "" && !slices.Contains(details.DepGroups, group) {
+			details.DepGroups = append(details.DepGroups, group)
+		}
+
+		key := details.Name + "@" + details.Version
+		if existing, ok := packages[key]; ok {
+			if len(existing.DepGroups) == 0 || len(details.DepGroups) == 0 {
+				// the package is required regardless of any groups that it is also in
+				details.DepGroups = nil
+			} else {
+				for _, g := range existing.DepGroups {
+					if !slices.Contains(details.DepGroups, g) {
+						details.DepGroups = append(details.DepGroups, g)
+					}
+				}
+			}
+		}
+		packages[key] = details
+	}
+
+	addRequirements := func(requirements []string, group string) {
+		for _, line := range requirements {
+			line = strings.TrimSpace(line)
+
+			// direct references (e.g. "name @ git+https://...") are not from PyPI
+			if line == "" || strings.Contains(line, "://") {
+				continue
+			}
+
+			add(parseLine(line), group)
+		}
+	}
+
+	addPoetryDependencies := func(deps map[string]PoetryDependency, group string) {
+		for name, dep := range deps {
+			// the python "dependency" is the versions of Python that the project supports
+			if strings.EqualFold(name, "python") {
+				continue
+			}
+
+			add(parsePoetryDependency(name, dep), group)
+		}
+	}
+
+	addRequirements(parsedFile.Project.Dependencies, "")
+	for _, requirements := range parsedFile.Project.OptionalDependencies {
+		addRequirements(requirements, "optional")
+	}
+
+	poetry := parsedFile.Tool.Poetry
+	addPoetryDependencies(poetry.Dependencies, "")
+	addPoetryDependencies(poetry.DevDependencies, "dev")
+	for name, group := range poetry.Group {
+		if name == "main" {
+			name = ""
+		}
+		addPoetryDependencies(group.Dependencies, name)
+	}
+
+	return maps.Values(packages), nil
+}
+
+var _ Extractor = PyprojectTOMLExtractor{}
+
+//nolint:gochecknoinits
+func init() {
+	registerExtractor("pyproject.toml", PyprojectTOMLExtractor{})
+}
+
+func ParsePyprojectTOML(pathToLockfile string) ([]PackageDetails, error) {
+	return extractFromFile(pathToLockfile, PyprojectTOMLExtractor{})
+}
@@ -0,0 +1,221 @@
+package lockfile_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestPyprojectTOMLExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: "pyproject.toml",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/pyproject.toml",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/pyproject.toml/file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/my/pyproject.toml.file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path.to.my.pyproject.toml",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := lockfile.PyprojectTOMLExtractor{}
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("Extract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePyprojectTOML_FileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParsePyprojectTOML("fixtures/pyproject/does-not-exist")
+
+	expectErrIs(t, err, fs.ErrNotExist)
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParsePyprojectTOML_InvalidToml(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParsePyprojectTOML("fixtures/pyproject/not-toml.txt")
+
+	expectErrContaining(t, err, "could not extract from")
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParsePyprojectTOML_Empty(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParsePyprojectTOML("fixtures/pyproject/empty.toml")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParsePyprojectTOML_PEP621(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParsePyprojectTOML("fixtures/pyproject/pep621.toml")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "requests",
+			Version:   "2.31.0",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+		{
+			Name:      "flask",
+			Version:   "2.3.2",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+		{
+			Name:      "numpy",
+			Version:   "0.0.0",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+			Marker:    "python_version >= '3.10'",
+		},
+		{
+			Name:      "sphinx",
+			Version:   "7.1",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+			DepGroups: []string{"optional"},
+		},
+	})
+}
+
+func TestParsePyprojectTOML_Poetry(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParsePyprojectTOML("fixtures/pyproject/poetry.toml")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "requests",
+			Version:   "2.31.0",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+		{
+			Name:      "django",
+			Version:   "4.2",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+		{
+			Name:      "urllib3",
+			Version:   "0.0.0",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+		{
+			Name:      "pillow",
+			Version:   "10.0",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+			DepGroups: []string{"optional"},
+		},
+		{
+			Name:      "numpy",
+			Version:   "1.24",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+		{
+			Name:      "my-fork",
+			Version:   "0.0.0",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+			Origin:    "https://github.com/example/my-fork.git",
+		},
+		{
+			Name:      "pytest",
+			Version:   "7.4.0",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+			DepGroups: []string{"dev"},
+		},
+		{
+			Name:      "black",
+			Version:   "23.7.0",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+			DepGroups: []string{"lint"},
+		},
+	})
+}
+
+func TestParsePyprojectTOML_WithPoetryLock(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParsePyprojectTOML("fixtures/pyproject/with-lock/pyproject.toml")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		// the version in the poetry.lock is preferred over the constraint
+		{
+			Name:      "requests",
+			Version:   "2.31.0",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+		// packages missing from the poetry.lock fall back to their constraint
+		{
+			Name:      "six",
+			Version:   "1.16",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+	})
+}
@@ -16,41 +16,41 @@ const PipEcosystem Ecosystem = "PyPI"
 //
 //	https://pip.pypa.io/en/stable/reference/requirements-file-format/#example
 func parseLine(line string) PackageDetails {
-	var constraint string
-	name := line
+	// per-requirement options such as --hash come after the requirement itself
+	line = cachedregexp.MustCompile(`\s+--?[a-zA-Z].*$`).ReplaceAllString(line, "")
 
-	version := "0.0.0"
+	// environment markers are separated from the requirement by a semicolon
+	requirement, marker, _ := strings.Cut(line, ";")
 
-	if strings.Contains(line, "==") {
-		constraint = "=="
+	name, specifiers := requirement, ""
+	if i := strings.IndexAny(requirement, "<>=!~"); i >= 0 {
+		name, specifiers = requirement[:i], requirement[i:]
 	}
 
-	if strings.Contains(line, ">=") {
-		constraint = ">="
-	}
+	version := "0.0.0"
 
-	if strings.Contains(line, "~=") {
-		constraint = "~="
-	}
+	// use the first specifier that is (or is bound by) an actual version
+	for _, specifier := range strings.Split(specifiers, ",") {
+		re := cachedregexp.MustCompile(`^\s*(===|==|~=|>=|<=|!=|<|>)\s*(\S*)`)
+		match := re.FindStringSubmatch(specifier)
 
-	if strings.Contains(line, "!=") {
-		constraint = "!="
-	}
+		if match == nil {
+			continue
+		}
 
-	if constraint != "" {
-		unprocessedName, unprocessedVersion, _ := strings.Cut(line, constraint)
-		name = strings.TrimSpace(unprocessedName)
+		if match[1] == "===" || match[1] == "==" || match[1] == "~=" || match[1] == ">=" {
+			version = match[2]
 
-		if constraint != "!=" {
-			version, _, _ = strings.Cut(strings.TrimSpace(unprocessedVersion), " ")
+			break
 		}
 	}
 
 	return PackageDetails{
-		Name:      normalizedRequirementName(name),
+		Name:      normalizedRequirementName(strings.TrimSpace(name)),
 		Version:   version,
 		Ecosystem: PipEcosystem,
 		CompareAs: PipEcosystem,
+		Marker:    strings.TrimSpace(marker),
 	}
 }
 
@@ -80,6 +80,18 @@ func removeComments(line string) string {
 	return strings.TrimSpace(re.ReplaceAllString(line, ""))
 }
 
+func extractSuppression(line string) (Suppression, bool) {
+	var re = cachedregexp.MustCompile(`(?:^|\s+)#(.*)$`)
+
+	matches := re.FindStringSubmatch(line)
+
+	if matches == nil {
+		return Suppression{}, false
+	}
+
+	return parseSuppressionComment(matches[1])
+}
+
 func isNotRequirementLine(line string) bool {
 	return line == "" ||
 		// flags are not supported
@@ -136,6 +148,7 @@ func parseRequirementsTxt(f DepFile, requiredAlready map[string]struct{}) ([]Pac
 			}
 		}
 
+		suppression, suppressed := extractSuppression(line)
 		line = removeComments(line)
 		if ar := strings.TrimPrefix(line, "-r "); ar != line {
 			err := func() error {
@@ -183,10 +196,18 @@ func parseRequirementsTxt(f DepFile, requiredAlready map[string]struct{}) ([]Pac
 			packages[key] = detail
 		}
 		d := packages[key]
+		if d.Marker != detail.Marker {
+			// the package is required in different environments,
+			// so treat it as being required in every environment
+			d.Marker = ""
+		}
 		if !hasGroup(d.DepGroups) {
 			d.DepGroups = append(d.DepGroups, group)
-			packages[key] = d
 		}
+		if suppressed {
+			d.Suppressions = append(d.Suppressions, suppression)
+		}
+		packages[key] = d
 	}
 
 	if err := scanner.Err(); err != nil {
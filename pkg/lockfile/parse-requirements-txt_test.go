@@ -717,3 +717,121 @@ func TestParseRequirementsTxt_LineContinuation(t *testing.T) {
 		},
 	})
 }
+
+func TestParseRequirementsTxt_WithSuppressions(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseRequirementsTxt("fixtures/pip/with-suppressions.txt")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "requests",
+			Version:   "2.19.1",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+			DepGroups: []string{"with-suppressions"},
+			Suppressions: []lockfile.Suppression{
+				{ID: "GHSA-x84v-xcm2-53pg", Reason: "we do not use proxies"},
+			},
+		},
+		{
+			Name:      "django",
+			Version:   "2.2.0",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+			DepGroups: []string{"with-suppressions"},
+			Suppressions: []lockfile.Suppression{
+				{ID: "PYSEC-2019-12"},
+			},
+		},
+		{
+			Name:      "flask",
+			Version:   "1.0.0",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+			DepGroups: []string{"with-suppressions"},
+		},
+		{
+			Name:      "jinja2",
+			Version:   "2.10",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+			DepGroups: []string{"with-suppressions"},
+		},
+	})
+}
+
+func TestParseRequirementsTxt_WithMarkersAndHashes(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseRequirementsTxt("fixtures/pip/with-markers-and-hashes.txt")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "certifi",
+			Version:   "2023.7.22",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+			DepGroups: []string{"with-markers-and-hashes"},
+		},
+		{
+			Name:      "importlib-metadata",
+			Version:   "6.8.0",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+			DepGroups: []string{"with-markers-and-hashes"},
+			Marker:    `python_version < "3.10"`,
+		},
+		{
+			Name:      "pywin32",
+			Version:   "306",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+			DepGroups: []string{"with-markers-and-hashes"},
+			Marker:    `sys_platform == "win32"`,
+		},
+		{
+			Name:      "requests",
+			Version:   "2.31.0",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+			DepGroups: []string{"with-markers-and-hashes"},
+		},
+		{
+			Name:      "urllib3",
+			Version:   "1.21.1",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+			DepGroups: []string{"with-markers-and-hashes"},
+		},
+		{
+			Name:      "charset-normalizer",
+			Version:   "2",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+			DepGroups: []string{"with-markers-and-hashes"},
+		},
+		{
+			Name:      "idna",
+			Version:   "0.0.0",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+			DepGroups: []string{"with-markers-and-hashes"},
+		},
+		{
+			Name:      "exceptiongroup",
+			Version:   "1.1.3",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+			DepGroups: []string{"with-markers-and-hashes"},
+		},
+	})
+}
@@ -24,9 +24,11 @@ var parsers = map[string]PackageDetailsParser{
 	"Cargo.lock":                  ParseCargoLock,
 	"composer.lock":               ParseComposerLock,
 	"conan.lock":                  ParseConanLock,
+	"environment.yml":             ParseCondaEnvironment,
 	"Gemfile.lock":                ParseGemfileLock,
 	"go.mod":                      ParseGoLock,
 	"gradle.lockfile":             ParseGradleLock,
+	"libs.versions.toml":          ParseGradleVersionCatalog,
 	"mix.lock":                    ParseMixLock,
 	"Pipfile.lock":                ParsePipenvLock,
 	"package-lock.json":           ParseNpmLock,
@@ -36,6 +38,7 @@ var parsers = map[string]PackageDetailsParser{
 	"poetry.lock":                 ParsePoetryLock,
 	"pom.xml":                     ParseMavenLock,
 	"pubspec.lock":                ParsePubspecLock,
+	"pyproject.toml":              ParsePyprojectTOML,
 	"renv.lock":                   ParseRenvLock,
 	"requirements.txt":            ParseRequirementsTxt,
 	"yarn.lock":                   ParseYarnLock,
@@ -46,9 +46,11 @@ func TestFindParser(t *testing.T) {
 		"buildscript-gradle.lockfile",
 		"Cargo.lock",
 		"composer.lock",
+		"environment.yml",
 		"Gemfile.lock",
 		"go.mod",
 		"gradle.lockfile",
+		"libs.versions.toml",
 		"mix.lock",
 		"pdm.lock",
 		"Pipfile.lock",
@@ -58,6 +60,7 @@ func TestFindParser(t *testing.T) {
 		"poetry.lock",
 		"pom.xml",
 		"pubspec.lock",
+		"pyproject.toml",
 		"renv.lock",
 		"requirements.txt",
 		"yarn.lock",
@@ -98,9 +101,11 @@ func TestParse_FindsExpectedParsers(t *testing.T) {
 		"Cargo.lock",
 		"composer.lock",
 		"conan.lock",
+		"environment.yml",
 		"Gemfile.lock",
 		"go.mod",
 		"gradle.lockfile",
+		"libs.versions.toml",
 		"mix.lock",
 		"Pipfile.lock",
 		"pdm.lock",
@@ -110,6 +115,7 @@ func TestParse_FindsExpectedParsers(t *testing.T) {
 		"poetry.lock",
 		"pom.xml",
 		"pubspec.lock",
+		"pyproject.toml",
 		"renv.lock",
 		"requirements.txt",
 		"yarn.lock",
@@ -0,0 +1,54 @@
+package lockfile
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+)
+
+const suppressionDirective = "osv-scanner:ignore"
+
+// suppressionFormats are the formats that inline suppressions are read from,
+// as they have a comment syntax that can be put on the same line as a package
+var suppressionFormats = []string{"go.mod", "gradle.lockfile", "requirements.txt"}
+
+// Suppression is a request made inline in a lockfile to ignore a specific
+// vulnerability for the package that it was found alongside, such as:
+//
+//	requests==2.19.1 # osv-scanner:ignore GHSA-x84v-xcm2-53pg we do not use proxies
+type Suppression struct {
+	ID     string
+	Reason string
+}
+
+// parseSuppressionComment parses the given comment (excluding its leading
+// marker, such as "#") as a suppression, returning false if the comment is
+// not a suppression.
+func parseSuppressionComment(comment string) (Suppression, bool) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(comment), suppressionDirective)
+
+	// ensure the directive is not just the prefix of a longer word
+	if !ok || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+		return Suppression{}, false
+	}
+
+	id, reason, _ := strings.Cut(strings.TrimSpace(rest), " ")
+
+	if id == "" {
+		return Suppression{}, false
+	}
+
+	return Suppression{ID: id, Reason: strings.TrimSpace(reason)}, true
+}
+
+// SupportsSuppressions reports if inline suppressions are read from lockfiles
+// parsed as the given format
+func SupportsSuppressions(parsedAs string) bool {
+	return slices.Contains(suppressionFormats, parsedAs)
+}
+
+// ContainsSuppressionDirective reports if the content of a lockfile mentions
+// the suppression directive anywhere, regardless of if it is in a comment
+func ContainsSuppressionDirective(content []byte) bool {
+	return bytes.Contains(content, []byte(suppressionDirective))
+}
@@ -0,0 +1,57 @@
+package lockfile_test
+
+import (
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestSupportsSuppressions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		parsedAs string
+		want     bool
+	}{
+		{parsedAs: "requirements.txt", want: true},
+		{parsedAs: "gradle.lockfile", want: true},
+		{parsedAs: "go.mod", want: true},
+		{parsedAs: "package-lock.json", want: false},
+		{parsedAs: "Pipfile.lock", want: false},
+		{parsedAs: "", want: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.parsedAs, func(t *testing.T) {
+			t.Parallel()
+
+			if got := lockfile.SupportsSuppressions(tt.parsedAs); got != tt.want {
+				t.Errorf("SupportsSuppressions(%q) = %v, want %v", tt.parsedAs, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContainsSuppressionDirective(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		content string
+		want    bool
+	}{
+		{content: "requests==2.19.1 # osv-scanner:ignore GHSA-x84v-xcm2-53pg", want: true},
+		{content: "gem 'rails' # osv-scanner:ignore", want: true},
+		{content: "requests==2.19.1 # not ignored", want: false},
+		{content: "", want: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.content, func(t *testing.T) {
+			t.Parallel()
+
+			if got := lockfile.ContainsSuppressionDirective([]byte(tt.content)); got != tt.want {
+				t.Errorf("ContainsSuppressionDirective(%q) = %v, want %v", tt.content, got, tt.want)
+			}
+		})
+	}
+}
@@ -7,6 +7,16 @@ type PackageDetails struct {
 	Ecosystem Ecosystem `json:"ecosystem,omitempty"`
 	CompareAs Ecosystem `json:"compareAs,omitempty"`
 	DepGroups []string  `json:"-"`
+	// Suppressions are vulnerabilities that have been ignored for this package
+	// using a comment in the lockfile it was extracted from
+	Suppressions []Suppression `json:"-"`
+	// Marker is the environment marker that must be satisfied for the package
+	// to be installed, as described in https://peps.python.org/pep-0508/#environment-markers
+	Marker string `json:"-"`
+	// Origin is where the package is from if it is not the registry of its ecosystem,
+	// such as a git repository or a local path, in which case it cannot be matched
+	// against the advisories for that ecosystem
+	Origin string `json:"-"`
 }
 
 type Ecosystem string
@@ -27,6 +27,7 @@ const (
 	EcosystemCRAN          Ecosystem = "CRAN"
 	EcosystemBioconductor  Ecosystem = "Bioconductor"
 	EcosystemSwiftURL      Ecosystem = "SwiftURL"
+	EcosystemUbuntu        Ecosystem = "Ubuntu"
 )
 
 var Ecosystems = []Ecosystem{
@@ -54,6 +55,7 @@ var Ecosystems = []Ecosystem{
 	EcosystemCRAN,
 	EcosystemBioconductor,
 	EcosystemSwiftURL,
+	EcosystemUbuntu,
 }
 
 type SeverityType string
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/package-url/packageurl-go"
+)
+
+// purlTypes is the Package URL type of each ecosystem, along with the namespace
+// for those that use the same one for every package
+var purlTypes = map[Ecosystem]struct{ purlType, namespace string }{
+	EcosystemAlpine:      {"apk", "alpine"},
+	EcosystemConanCenter: {"conan", ""},
+	EcosystemCRAN:        {"cran", ""},
+	EcosystemCratesIO:    {"cargo", ""},
+	EcosystemDebian:      {"deb", "debian"},
+	EcosystemGo:          {"golang", ""},
+	EcosystemHex:         {"hex", ""},
+	EcosystemMaven:       {"maven", ""},
+	EcosystemNPM:         {"npm", ""},
+	EcosystemNuGet:       {"nuget", ""},
+	EcosystemPackagist:   {"composer", ""},
+	EcosystemPub:         {"pub", ""},
+	EcosystemPyPI:        {"pypi", ""},
+	EcosystemRubyGems:    {"gem", ""},
+}
+
+// PackageToPURL converts models.PackageInfo to a Package URL string, which is the reverse of
+// PURLToPackage, returning false if the ecosystem of the package has no Package URL type
+func PackageToPURL(pkg PackageInfo) (string, bool) {
+	// ecosystems can have a release, such as "Alpine:v3.18", which is not part of the type
+	ecosystem, _, _ := strings.Cut(pkg.Ecosystem, ":")
+	purlType, ok := purlTypes[Ecosystem(ecosystem)]
+	if !ok || pkg.Name == "" {
+		return "", false
+	}
+
+	namespace, name := purlType.namespace, pkg.Name
+	if namespace == "" {
+		separator := "/"
+		if purlType.purlType == "maven" {
+			// Maven uses : to separate namespace and package
+			separator = ":"
+		}
+		if i := strings.LastIndex(name, separator); i > 0 {
+			namespace, name = name[:i], name[i+1:]
+		}
+	}
+
+	return packageurl.NewPackageURL(purlType.purlType, namespace, name, pkg.Version, nil, "").ToString(), true
+}
@@ -0,0 +1,72 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func TestPackageToPURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		pkg    models.PackageInfo
+		want   string
+		wantOk bool
+	}{
+		{
+			name:   "package without a namespace",
+			pkg:    models.PackageInfo{Name: "memoffset", Version: "0.6.1", Ecosystem: string(models.EcosystemCratesIO)},
+			want:   "pkg:cargo/memoffset@0.6.1",
+			wantOk: true,
+		},
+		{
+			name:   "go module",
+			pkg:    models.PackageInfo{Name: "github.com/gogo/protobuf", Version: "1.3.1", Ecosystem: string(models.EcosystemGo)},
+			want:   "pkg:golang/github.com/gogo/protobuf@1.3.1",
+			wantOk: true,
+		},
+		{
+			name:   "scoped npm package",
+			pkg:    models.PackageInfo{Name: "@babel/core", Version: "7.0.0", Ecosystem: string(models.EcosystemNPM)},
+			want:   "pkg:npm/%40babel/core@7.0.0",
+			wantOk: true,
+		},
+		{
+			name:   "maven package",
+			pkg:    models.PackageInfo{Name: "org.hdrhistogram:HdrHistogram", Version: "2.1.12", Ecosystem: string(models.EcosystemMaven)},
+			want:   "pkg:maven/org.hdrhistogram/HdrHistogram@2.1.12",
+			wantOk: true,
+		},
+		{
+			name:   "ecosystem with a release",
+			pkg:    models.PackageInfo{Name: "apk-tools", Version: "2.12.10-r1", Ecosystem: "Alpine:v3.18"},
+			want:   "pkg:apk/alpine/apk-tools@2.12.10-r1",
+			wantOk: true,
+		},
+		{
+			name:   "ecosystem without a type",
+			pkg:    models.PackageInfo{Name: "actions/checkout", Version: "v4", Ecosystem: string(models.EcosystemGitHubActions)},
+			want:   "",
+			wantOk: false,
+		},
+		{
+			name:   "git commit",
+			pkg:    models.PackageInfo{Commit: "9a6bd55c9d0722cb101fe85a3b22d89e4ff4fe52"},
+			want:   "",
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := models.PackageToPURL(tt.pkg)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("PackageToPURL() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
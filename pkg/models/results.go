@@ -1,6 +1,7 @@
 package models
 
 import (
+	"cmp"
 	"slices"
 	"strings"
 )
@@ -9,6 +10,12 @@ import (
 type VulnerabilityResults struct {
 	Results                    []PackageSource            `json:"results"`
 	ExperimentalAnalysisConfig ExperimentalAnalysisConfig `json:"experimental_config"`
+	// ScannedSources are all the sources that packages were found in,
+	// including those which do not have any findings
+	ScannedSources []ScannedSource `json:"scanned_sources"`
+	// Incomplete is set when the scan was stopped before it finished, such as by a timeout
+	// or an interrupt, in which case only the findings gathered before then are included
+	Incomplete bool `json:"incomplete,omitempty"`
 }
 
 // ExperimentalAnalysisConfig is an experimental type intended to contain the
@@ -51,6 +58,47 @@ func (vulns *VulnerabilityResults) Flatten() []VulnerabilityFlattened {
 	return results
 }
 
+// Sort orders the results deterministically, so that the same scan always
+// produces the same output: sources are sorted by path and type, packages by
+// ecosystem, name, and version, and vulnerabilities and groups by their IDs.
+func (vulns *VulnerabilityResults) Sort() {
+	slices.SortStableFunc(vulns.Results, func(a, b PackageSource) int {
+		if a.Source.Path != b.Source.Path {
+			return cmp.Compare(a.Source.Path, b.Source.Path)
+		}
+
+		return cmp.Compare(a.Source.Type, b.Source.Type)
+	})
+
+	for _, res := range vulns.Results {
+		slices.SortStableFunc(res.Packages, func(a, b PackageVulns) int {
+			if a.Package.Ecosystem != b.Package.Ecosystem {
+				return cmp.Compare(a.Package.Ecosystem, b.Package.Ecosystem)
+			}
+			if a.Package.Name != b.Package.Name {
+				return cmp.Compare(a.Package.Name, b.Package.Name)
+			}
+			if a.Package.Version != b.Package.Version {
+				return cmp.Compare(a.Package.Version, b.Package.Version)
+			}
+
+			return cmp.Compare(a.Package.Commit, b.Package.Commit)
+		})
+
+		for _, pkg := range res.Packages {
+			slices.SortStableFunc(pkg.Vulnerabilities, func(a, b Vulnerability) int {
+				return cmp.Compare(a.ID, b.ID)
+			})
+			for _, group := range pkg.Groups {
+				slices.Sort(group.IDs)
+			}
+			slices.SortStableFunc(pkg.Groups, func(a, b GroupInfo) int {
+				return cmp.Compare(a.IndexString(), b.IndexString())
+			})
+		}
+	}
+}
+
 func getGroupInfoForVuln(groups []GroupInfo, vulnID string) GroupInfo {
 	// groupIdx should never be -1 since vulnerabilities should always be in one group
 	groupIdx := slices.IndexFunc(groups, func(g GroupInfo) bool { return slices.Contains(g.IDs, vulnID) })
@@ -73,6 +121,31 @@ type VulnerabilityFlattened struct {
 type SourceInfo struct {
 	Path string `json:"path"`
 	Type string `json:"type"`
+	// Repository is the root of the repository that the source was found in,
+	// when several repositories are scanned together
+	Repository string `json:"repository,omitempty"`
+	// Ref is the git ref that the source was read at, when it was scanned
+	// as it exists at that ref rather than as it is on disk
+	Ref string `json:"ref,omitempty"`
+}
+
+// ScanTarget is a source that would be scanned and the ecosystems of its packages,
+// as found without making any network requests
+type ScanTarget struct {
+	Source     SourceInfo `json:"source"`
+	Ecosystems []string   `json:"ecosystems"`
+	// Packages is the number of packages found in the source, which is
+	// zero for sources whose packages are only known once resolved
+	Packages int `json:"packages"`
+}
+
+// ScannedSource is a source that was scanned, the ecosystems of the packages found in it, and
+// whether its packages were found by resolving it as a manifest rather than reading them from it
+type ScannedSource struct {
+	Source     SourceInfo `json:"source"`
+	Ecosystems []string   `json:"ecosystems"`
+	Packages   int        `json:"packages"`
+	Resolved   bool       `json:"resolved"`
 }
 
 type Metadata struct {
@@ -102,6 +175,42 @@ type PackageVulns struct {
 	Groups            []GroupInfo     `json:"groups,omitempty"`
 	Licenses          []License       `json:"licenses,omitempty"`
 	LicenseViolations []License       `json:"license_violations,omitempty"`
+	// Suppressed are vulnerabilities that were ignored by an inline comment in the source or a baseline
+	Suppressed []SuppressedVulnerability `json:"suppressed_vulnerabilities,omitempty"`
+	// DependencyPaths are the paths from a direct dependency down to the package, with each
+	// package in a path as "name@version", which are only known for some types of lockfile
+	DependencyPaths [][]string `json:"dependency_paths,omitempty"`
+	// Yanked is set if the version of the package has been yanked from its registry
+	Yanked *YankedVersion `json:"yanked,omitempty"`
+	// Withdrawn are the IDs of advisories that affected the package but have since been
+	// withdrawn, which are not counted as vulnerabilities
+	Withdrawn []string `json:"withdrawn_vulnerabilities,omitempty"`
+	// IntroducedBy are the direct dependencies at the start of the DependencyPaths, along with
+	// where each is declared in the manifest alongside the lockfile if that is known
+	IntroducedBy []DirectDependency `json:"introduced_by,omitempty"`
+}
+
+// DirectDependency is a dependency of the project itself that results in other packages being depended on.
+type DirectDependency struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// Manifest is the path to the manifest that declares the dependency
+	Manifest string `json:"manifest,omitempty"`
+	// Line is the line (starting from 1) of the manifest that the dependency is declared on
+	Line int `json:"line,omitempty"`
+}
+
+// YankedVersion describes a version of a package that has been yanked from its
+// registry, meaning it can still be installed from a lockfile but should no longer be used.
+type YankedVersion struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// SuppressedVulnerability is a vulnerability that was found for a package but
+// was suppressed by a comment alongside the package in its source.
+type SuppressedVulnerability struct {
+	ID     string `json:"id"`
+	Reason string `json:"reason,omitempty"`
 }
 
 type GroupInfo struct {
@@ -112,6 +221,12 @@ type GroupInfo struct {
 	// Map of Vulnerability IDs to AnalysisInfo
 	ExperimentalAnalysis map[string]AnalysisInfo `json:"experimentalAnalysis,omitempty"`
 	MaxSeverity          string                  `json:"max_severity"`
+	// SeverityRating is the rating of MaxSeverity, such as "High", which is empty if there is no score
+	SeverityRating string `json:"severity_rating,omitempty"`
+	// FixedVersions is the lowest version that fixes the vulnerabilities in each of the ranges
+	// that affect the package, which is only set when the scan is asked to report them,
+	// and is empty (rather than nil) if there is no fix available
+	FixedVersions []string `json:"fixed_versions,omitempty"`
 }
 
 // IsCalled returns true if any analysis performed determines that the vulnerability is being called
@@ -120,3 +120,81 @@ func TestFlatten(t *testing.T) {
 		t.Errorf("Flatten() returned unexpected result (-got +want):\n%s", diff)
 	}
 }
+
+func TestVulnerabilityResults_Sort(t *testing.T) {
+	t.Parallel()
+
+	vulns := models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "/b/package-lock.json", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{Package: models.PackageInfo{Name: "lodash", Version: "4.17.0", Ecosystem: "npm"}},
+					{Package: models.PackageInfo{Name: "express", Version: "4.0.0", Ecosystem: "npm"}},
+				},
+			},
+			{
+				Source: models.SourceInfo{Path: "/a/requirements.txt", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package: models.PackageInfo{Name: "flask", Version: "1.0.0", Ecosystem: "PyPI"},
+						Vulnerabilities: []models.Vulnerability{
+							{ID: "PYSEC-2019-179"},
+							{ID: "GHSA-m2qf-hxjv-5gpq"},
+							{ID: "GHSA-562c-5r94-xh97"},
+						},
+						Groups: []models.GroupInfo{
+							{IDs: []string{"PYSEC-2019-179", "GHSA-m2qf-hxjv-5gpq"}},
+							{IDs: []string{"GHSA-562c-5r94-xh97"}},
+						},
+					},
+					{Package: models.PackageInfo{Name: "django", Version: "2.0.0", Ecosystem: "PyPI"}},
+					{Package: models.PackageInfo{Name: "django", Version: "1.0.0", Ecosystem: "PyPI"}},
+				},
+			},
+			{
+				Source: models.SourceInfo{Path: "/a/requirements.txt", Type: "artifact"},
+			},
+		},
+	}
+
+	want := models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "/a/requirements.txt", Type: "artifact"},
+			},
+			{
+				Source: models.SourceInfo{Path: "/a/requirements.txt", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{Package: models.PackageInfo{Name: "django", Version: "1.0.0", Ecosystem: "PyPI"}},
+					{Package: models.PackageInfo{Name: "django", Version: "2.0.0", Ecosystem: "PyPI"}},
+					{
+						Package: models.PackageInfo{Name: "flask", Version: "1.0.0", Ecosystem: "PyPI"},
+						Vulnerabilities: []models.Vulnerability{
+							{ID: "GHSA-562c-5r94-xh97"},
+							{ID: "GHSA-m2qf-hxjv-5gpq"},
+							{ID: "PYSEC-2019-179"},
+						},
+						Groups: []models.GroupInfo{
+							{IDs: []string{"GHSA-562c-5r94-xh97"}},
+							{IDs: []string{"GHSA-m2qf-hxjv-5gpq", "PYSEC-2019-179"}},
+						},
+					},
+				},
+			},
+			{
+				Source: models.SourceInfo{Path: "/b/package-lock.json", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{Package: models.PackageInfo{Name: "express", Version: "4.0.0", Ecosystem: "npm"}},
+					{Package: models.PackageInfo{Name: "lodash", Version: "4.17.0", Ecosystem: "npm"}},
+				},
+			},
+		},
+	}
+
+	vulns.Sort()
+
+	if diff := cmp.Diff(want, vulns); diff != "" {
+		t.Errorf("Sort() returned unexpected result (-want +got):\n%s", diff)
+	}
+}
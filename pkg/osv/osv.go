@@ -4,11 +4,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
 	"math/rand"
 	"net/http"
+	"slices"
 	"time"
 
 	"github.com/google/osv-scanner/pkg/lockfile"
@@ -27,9 +28,8 @@ const (
 	BaseVulnerabilityURL = "https://osv.dev/"
 	// maxQueriesPerRequest splits up querybatch into multiple requests if
 	// number of queries exceed this number
-	maxQueriesPerRequest  = 1000
-	maxConcurrentRequests = 25
-	maxRetryAttempts      = 4
+	maxQueriesPerRequest = 1000
+	maxRetryAttempts     = 4
 	// jitterMultiplier is multiplied to the retry delay multiplied by rand(0, 1.0)
 	jitterMultiplier = 2
 )
@@ -60,6 +60,8 @@ type BatchedQuery struct {
 // MinimalVulnerability represents an unhydrated vulnerability entry from OSV.
 type MinimalVulnerability struct {
 	ID string `json:"id"`
+	// Modified is when the vulnerability was last modified, which is zero if it is not known
+	Modified time.Time `json:"modified"`
 }
 
 // Response represents a full response from OSV.
@@ -179,47 +181,121 @@ func MakeRequest(request BatchedQuery) (*BatchedResponse, error) {
 // MakeRequestWithClient sends a batched query to osv.dev with the provided
 // http client.
 func MakeRequestWithClient(request BatchedQuery, client *http.Client) (*BatchedResponse, error) {
+	return MakeRequestWithContext(context.Background(), request, client)
+}
+
+// MakeRequestWithContext sends a batched query to osv.dev with the provided
+// http client, giving up once ctx is done.
+func MakeRequestWithContext(ctx context.Context, request BatchedQuery, client *http.Client) (*BatchedResponse, error) {
+	return MakeRequestWithProgress(ctx, request, client, nil)
+}
+
+// MakeRequestWithProgress is like MakeRequestWithContext, but calls progress (if not nil) with how
+// many of the queries have been answered so far after each request to osv.dev.
+//
+// The queries are split up into requests of up to 1000 queries, which are made concurrently
+// as per SetConcurrency, with progress always being called from the same goroutine that
+// MakeRequestWithProgress is.
+func MakeRequestWithProgress(ctx context.Context, request BatchedQuery, client *http.Client, progress func(done, total int)) (*BatchedResponse, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	// API has a limit of 1000 bulk query per request
 	queryChunks := chunkBy(request.Queries, maxQueriesPerRequest)
-	var totalOsvResp BatchedResponse
-	for _, queries := range queryChunks {
-		requestBytes, err := json.Marshal(BatchedQuery{Queries: queries})
-		if err != nil {
-			return nil, err
-		}
 
-		resp, err := makeRetryRequest(func() (*http.Response, error) {
-			// Make sure request buffer is inside retry, if outside
-			// http request would finish the buffer, and retried requests would be empty
-			requestBuf := bytes.NewBuffer(requestBytes)
-			// We do not need a specific context
-			req, err := http.NewRequest(http.MethodPost, QueryEndpoint, requestBuf)
-			if err != nil {
-				return nil, err
-			}
-			req.Header.Set("Content-Type", "application/json")
-			if RequestUserAgent != "" {
-				req.Header.Set("User-Agent", RequestUserAgent)
+	// the result of each chunk is sent on the channel, which is buffered
+	// so that nothing is blocked if returning early on an error
+	chunkChan := make(chan queriedChunk, len(queryChunks))
+	requestLimiter := semaphore.NewWeighted(int64(concurrencyLimiter().maxRequests))
+
+	go func() {
+		for idx, queries := range queryChunks {
+			if err := requestLimiter.Acquire(ctx, 1); err != nil {
+				// this can only fail when ctx is done, which is noticed below
+				return
 			}
 
-			return client.Do(req)
-		})
-		if err != nil {
-			return nil, err
+			go func(idx int, queries []*Query) {
+				results, err := makeChunkRequest(ctx, queries, client)
+
+				requestLimiter.Release(1)
+				chunkChan <- queriedChunk{idx: idx, results: results, err: err}
+			}(idx, queries)
 		}
-		defer resp.Body.Close()
+	}()
+
+	chunkResults := make([][]MinimalResponse, len(queryChunks))
+	done := 0
+	for range queryChunks {
+		var result queriedChunk
+		select {
+		case result = <-chunkChan:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		if result.err != nil {
+			return nil, result.err
+		}
+
+		chunkResults[result.idx] = result.results
+		done += len(queryChunks[result.idx])
+
+		if progress != nil {
+			progress(done, len(request.Queries))
+		}
+	}
+
+	var totalOsvResp BatchedResponse
+	for _, results := range chunkResults {
+		totalOsvResp.Results = append(totalOsvResp.Results, results...)
+	}
+
+	return &totalOsvResp, nil
+}
+
+type queriedChunk struct {
+	idx     int
+	results []MinimalResponse
+	err     error
+}
 
-		var osvResp BatchedResponse
-		decoder := json.NewDecoder(resp.Body)
-		err = decoder.Decode(&osvResp)
+// makeChunkRequest sends a single request to osv.dev with the queries,
+// which must be no more than the API allows in one request
+func makeChunkRequest(ctx context.Context, queries []*Query, client *http.Client) ([]MinimalResponse, error) {
+	requestBytes, err := json.Marshal(BatchedQuery{Queries: queries})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := makeRetryRequest(ctx, func() (*http.Response, error) {
+		// Make sure request buffer is inside retry, if outside
+		// http request would finish the buffer, and retried requests would be empty
+		requestBuf := bytes.NewBuffer(requestBytes)
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, QueryEndpoint, requestBuf)
 		if err != nil {
 			return nil, err
 		}
+		req.Header.Set("Content-Type", "application/json")
+		if RequestUserAgent != "" {
+			req.Header.Set("User-Agent", RequestUserAgent)
+		}
 
-		totalOsvResp.Results = append(totalOsvResp.Results, osvResp.Results...)
+		return client.Do(req)
+	})
+	if err != nil {
+		return nil, err
 	}
+	defer resp.Body.Close()
 
-	return &totalOsvResp, nil
+	var osvResp BatchedResponse
+	decoder := json.NewDecoder(resp.Body)
+	err = decoder.Decode(&osvResp)
+	if err != nil {
+		return nil, err
+	}
+
+	return osvResp.Results, nil
 }
 
 // Get a Vulnerability for the given ID.
@@ -230,10 +306,14 @@ func Get(id string) (*models.Vulnerability, error) {
 // GetWithClient gets a Vulnerability for the given ID with the provided http
 // client.
 func GetWithClient(id string, client *http.Client) (*models.Vulnerability, error) {
-	resp, err := makeRetryRequest(func() (*http.Response, error) {
-		// We do not need a specific context
-		//nolint:noctx
-		req, err := http.NewRequest(http.MethodGet, GetEndpoint+"/"+id, nil)
+	return GetWithContext(context.Background(), id, client)
+}
+
+// GetWithContext gets a Vulnerability for the given ID with the provided http
+// client, giving up once ctx is done.
+func GetWithContext(ctx context.Context, id string, client *http.Client) (*models.Vulnerability, error) {
+	resp, err := makeRetryRequest(ctx, func() (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, GetEndpoint+"/"+id, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -268,68 +348,157 @@ func Hydrate(resp *BatchedResponse) (*HydratedBatchedResponse, error) {
 // HydrateWithClient fills the results of the batched response with the full
 // Vulnerability details using the provided http client.
 func HydrateWithClient(resp *BatchedResponse, client *http.Client) (*HydratedBatchedResponse, error) {
+	return HydrateWithProgress(resp, client, nil)
+}
+
+// HydrateWithProgress fills the results of the batched response with the full
+// Vulnerability details using the provided http client, calling progress (if not nil)
+// with how many of the vulnerabilities have been fetched after each one is.
+//
+// progress is always called from the same goroutine that HydrateWithProgress is.
+func HydrateWithProgress(resp *BatchedResponse, client *http.Client, progress func(done, total int)) (*HydratedBatchedResponse, error) {
+	return HydrateWithContext(context.Background(), resp, client, progress)
+}
+
+// hydratedVuln is the outcome of fetching one of the vulnerabilities being hydrated
+type hydratedVuln struct {
+	batchIdx  int
+	resultIdx int
+	vuln      *models.Vulnerability
+	err       error
+}
+
+// HydrateWithContext is like HydrateWithProgress, but stops fetching vulnerabilities once ctx is done.
+//
+// If ctx is done before every vulnerability has been fetched, the error from ctx is returned
+// alongside a response that only has the vulnerabilities that have already been fetched.
+func HydrateWithContext(ctx context.Context, resp *BatchedResponse, client *http.Client, progress func(done, total int)) (*HydratedBatchedResponse, error) {
 	hydrated := HydratedBatchedResponse{}
-	ctx := context.TODO()
 	// Preallocate the array to avoid slice reallocations when inserting later
 	hydrated.Results = make([]Response, len(resp.Results))
+	total := 0
 	for idx := range hydrated.Results {
 		hydrated.Results[idx].Vulns =
 			make([]models.Vulnerability, len(resp.Results[idx].Vulns))
+		total += len(resp.Results[idx].Vulns)
 	}
 
-	errChan := make(chan error)
-	rateLimiter := semaphore.NewWeighted(maxConcurrentRequests)
+	// the result of each vulnerability is sent on the channel, which is buffered
+	// so that nothing is blocked if returning early on an error
+	vulnChan := make(chan hydratedVuln, total)
+	rateLimiter := semaphore.NewWeighted(int64(concurrencyLimiter().maxRequests))
 
-	for batchIdx, response := range resp.Results {
-		for resultIdx, vuln := range response.Vulns {
-			if err := rateLimiter.Acquire(ctx, 1); err != nil {
-				log.Panicf("Failed to acquire semaphore: %v", err)
-			}
-
-			go func(id string, batchIdx int, resultIdx int) {
-				vuln, err := GetWithClient(id, client)
-				if err != nil {
-					errChan <- err
-				} else {
-					hydrated.Results[batchIdx].Vulns[resultIdx] = *vuln
+	go func() {
+		for batchIdx, response := range resp.Results {
+			for resultIdx, vuln := range response.Vulns {
+				if err := rateLimiter.Acquire(ctx, 1); err != nil {
+					// this can only fail when ctx is done, which is noticed below
+					return
 				}
 
-				rateLimiter.Release(1)
-			}(vuln.ID, batchIdx, resultIdx)
-		}
-	}
+				go func(id string, batchIdx int, resultIdx int) {
+					vuln, err := GetWithContext(ctx, id, client)
 
-	// Close error channel when all semaphores are released
-	go func() {
-		if err := rateLimiter.Acquire(ctx, maxConcurrentRequests); err != nil {
-			log.Panicf("Failed to acquire semaphore: %v", err)
+					rateLimiter.Release(1)
+					vulnChan <- hydratedVuln{batchIdx: batchIdx, resultIdx: resultIdx, vuln: vuln, err: err}
+				}(vuln.ID, batchIdx, resultIdx)
+			}
 		}
-		// Always close the error channel
-		close(errChan)
 	}()
 
-	// Range will exit when channel is closed.
-	// Channel will be closed when all semaphores are freed.
-	for err := range errChan {
-		return nil, err
+	for done := 1; done <= total; done++ {
+		var result hydratedVuln
+		select {
+		case result = <-vulnChan:
+		case <-ctx.Done():
+			return removeUnhydrated(&hydrated), ctx.Err()
+		}
+
+		if result.err != nil {
+			if ctx.Err() != nil {
+				return removeUnhydrated(&hydrated), ctx.Err()
+			}
+
+			return nil, result.err
+		}
+
+		hydrated.Results[result.batchIdx].Vulns[result.resultIdx] = *result.vuln
+
+		if progress != nil {
+			progress(done, total)
+		}
 	}
 
 	return &hydrated, nil
 }
 
+// removeUnhydrated removes the vulnerabilities that were never fetched from the response
+func removeUnhydrated(hydrated *HydratedBatchedResponse) *HydratedBatchedResponse {
+	for idx, response := range hydrated.Results {
+		hydrated.Results[idx].Vulns = slices.DeleteFunc(response.Vulns, func(vuln models.Vulnerability) bool {
+			return vuln.ID == ""
+		})
+	}
+
+	return hydrated
+}
+
 // makeRetryRequest will return an error on both network errors, and if the response is not 200
-func makeRetryRequest(action func() (*http.Response, error)) (*http.Response, error) {
+//
+// Requests that are rate limited by the server are retried after the time given
+// by its Retry-After header, if any, rather than the usual backoff, with fewer
+// requests being made at once until requests start succeeding again.
+//
+// No more attempts are made once ctx is done, with the error from ctx being returned.
+func makeRetryRequest(ctx context.Context, action func() (*http.Response, error)) (*http.Response, error) {
 	var resp *http.Response
 	var err error
+	var retryAfter time.Duration
 
 	for i := 0; i < maxRetryAttempts; i++ {
-		// rand is initialized with a random number (since go1.20), and is also safe to use concurrently
-		// we do not need to use a cryptographically secure random jitter, this is just to spread out the retry requests
-		// #nosec G404
-		jitterAmount := (rand.Float64() * float64(jitterMultiplier) * float64(i))
-		time.Sleep(time.Duration(i*i)*time.Second + time.Duration(jitterAmount*1000)*time.Millisecond)
+		var delay time.Duration
+		if retryAfter > 0 {
+			delay = retryAfter
+			retryAfter = 0
+		} else {
+			// rand is initialized with a random number (since go1.20), and is also safe to use concurrently
+			// we do not need to use a cryptographically secure random jitter, this is just to spread out the retry requests
+			// #nosec G404
+			jitterAmount := (rand.Float64() * float64(jitterMultiplier) * float64(i))
+			delay = time.Duration(i*i)*time.Second + time.Duration(jitterAmount*1000)*time.Millisecond
+		}
+
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+
+		if err := waitForRateLimit(ctx); err != nil {
+			return nil, err
+		}
+
+		l := concurrencyLimiter()
+		if err := l.acquire(ctx); err != nil {
+			return nil, err
+		}
 
 		resp, err = action()
+		l.release(err == nil && resp.StatusCode == http.StatusTooManyRequests)
+		if ctx.Err() != nil {
+			if err == nil {
+				resp.Body.Close()
+			}
+
+			return nil, ctx.Err()
+		}
+		if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+			retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+			resp.Body.Close()
+			err = errors.New("rate limited by osv.dev")
+
+			notifyRateLimited(ctx, retryAfter)
+
+			continue
+		}
 		if err == nil {
 			// Check the response for HTTP errors
 			err = checkResponseError(resp)
@@ -353,7 +522,7 @@ func MakeDetermineVersionRequest(name string, hashes []DetermineVersionHash) (*D
 		return nil, err
 	}
 
-	resp, err := makeRetryRequest(func() (*http.Response, error) {
+	resp, err := makeRetryRequest(context.Background(), func() (*http.Response, error) {
 		// Make sure request buffer is inside retry, if outside
 		// http request would finish the buffer, and retried requests would be empty
 		requestBuf := bytes.NewBuffer(requestBytes)
@@ -0,0 +1,225 @@
+package osv
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// maxRetryAfter is the longest that will be waited for when rate limited by
+// the server, regardless of what is requested by its Retry-After header
+const maxRetryAfter = 5 * time.Minute
+
+// onRateLimitedKey is the key of the context value set by WithOnRateLimited
+type onRateLimitedKey struct{}
+
+// WithOnRateLimited returns a copy of ctx which makes the requests made with it call onRateLimited
+// with how long they will wait for before being retried whenever one is rate limited by osv.dev,
+// so that concurrent callers can each be told about their own requests being rate limited
+func WithOnRateLimited(ctx context.Context, onRateLimited func(retryAfter time.Duration)) context.Context {
+	return context.WithValue(ctx, onRateLimitedKey{}, onRateLimited)
+}
+
+// notifyRateLimited calls the function set on ctx by WithOnRateLimited, if any
+func notifyRateLimited(ctx context.Context, retryAfter time.Duration) {
+	if onRateLimited, ok := ctx.Value(onRateLimitedKey{}).(func(time.Duration)); ok && onRateLimited != nil {
+		onRateLimited(retryAfter)
+	}
+}
+
+// tokenBucket paces requests to an average rate, while allowing short bursts of
+// up to one second's worth of requests
+type tokenBucket struct {
+	mu sync.Mutex
+
+	rate     float64
+	capacity float64
+	tokens   float64
+	last     time.Time
+}
+
+func newTokenBucket(requestsPerSecond float64) *tokenBucket {
+	capacity := math.Max(1, requestsPerSecond)
+
+	return &tokenBucket{
+		rate:     requestsPerSecond,
+		capacity: capacity,
+		tokens:   capacity,
+		last:     time.Now(),
+	}
+}
+
+// reserve takes a token from the bucket, returning how long to wait for before
+// the request it is for can be made
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens = math.Min(b.capacity, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+var (
+	limiterMu sync.RWMutex
+	limiter   *tokenBucket
+)
+
+// defaultConcurrency is how many requests are made to osv.dev at once unless set otherwise
+const defaultConcurrency = 25
+
+// adaptiveLimiter limits how many requests are made at once, halving the limit whenever a request
+// is rate limited and growing it back by about one for every limit's worth of successful requests
+type adaptiveLimiter struct {
+	mu sync.Mutex
+
+	maxRequests int
+	limit       float64
+	inFlight    int
+	// released is closed whenever a request finishes, to wake up those waiting for one
+	released chan struct{}
+}
+
+func newAdaptiveLimiter(maxRequests int) *adaptiveLimiter {
+	return &adaptiveLimiter{
+		maxRequests: maxRequests,
+		limit:       float64(maxRequests),
+		released:    make(chan struct{}),
+	}
+}
+
+// acquire blocks until a request can be made within the current limit,
+// returning the error from ctx if it is done first
+func (l *adaptiveLimiter) acquire(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		if l.inFlight < max(1, int(l.limit)) {
+			l.inFlight++
+			l.mu.Unlock()
+
+			return nil
+		}
+		released := l.released
+		l.mu.Unlock()
+
+		select {
+		case <-released:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// release finishes a request that was acquired, adjusting the limit by whether it was rate limited
+func (l *adaptiveLimiter) release(rateLimited bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.inFlight--
+	if rateLimited {
+		l.limit = math.Max(1, l.limit/2)
+	} else {
+		l.limit = math.Min(float64(l.maxRequests), l.limit+1/l.limit)
+	}
+
+	close(l.released)
+	l.released = make(chan struct{})
+}
+
+var (
+	concurrencyMu sync.RWMutex
+	concurrency   = newAdaptiveLimiter(defaultConcurrency)
+)
+
+// SetConcurrency sets the most requests that are made to osv.dev at once, which is temporarily
+// reduced whenever requests are rate limited. A concurrency of zero or less restores the default of 25.
+//
+// This applies to every request made by the process, so it should be set once before any are made.
+func SetConcurrency(maxRequests int) {
+	concurrencyMu.Lock()
+	defer concurrencyMu.Unlock()
+
+	if maxRequests <= 0 {
+		maxRequests = defaultConcurrency
+	}
+
+	concurrency = newAdaptiveLimiter(maxRequests)
+}
+
+// concurrencyLimiter returns the limiter of how many requests are made at once
+func concurrencyLimiter() *adaptiveLimiter {
+	concurrencyMu.RLock()
+	defer concurrencyMu.RUnlock()
+
+	return concurrency
+}
+
+// SetRateLimit limits the requests made to osv.dev to the given number per second,
+// across all concurrent requests. A limit of zero or less removes the limit.
+//
+// This applies to every request made by the process, so it should be set once before any are made.
+func SetRateLimit(requestsPerSecond float64) {
+	limiterMu.Lock()
+	defer limiterMu.Unlock()
+
+	if requestsPerSecond <= 0 {
+		limiter = nil
+		return
+	}
+
+	limiter = newTokenBucket(requestsPerSecond)
+}
+
+// waitForRateLimit blocks until a request can be made within the rate limit, if there is one,
+// returning the error from ctx if it is done first
+func waitForRateLimit(ctx context.Context) error {
+	limiterMu.RLock()
+	l := limiter
+	limiterMu.RUnlock()
+
+	if l == nil {
+		return nil
+	}
+
+	return sleep(ctx, l.reserve(time.Now()))
+}
+
+// sleep pauses for the given duration, returning early with the error from ctx if it is done first
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either a
+// number of seconds or a HTTP date, returning zero if it cannot be parsed
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	var retryAfter time.Duration
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		retryAfter = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(value); err == nil {
+		retryAfter = date.Sub(now)
+	}
+
+	return min(max(retryAfter, 0), maxRetryAfter)
+}
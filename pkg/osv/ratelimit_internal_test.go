@@ -0,0 +1,159 @@
+package osv
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_tokenBucket_reserve(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	b := newTokenBucket(2)
+	b.last = now
+
+	// the bucket starts full, allowing a burst of up to a second's worth of requests
+	for i := 0; i < 2; i++ {
+		if wait := b.reserve(now); wait != 0 {
+			t.Errorf("request %d: expected no wait, got %s", i, wait)
+		}
+	}
+
+	if wait := b.reserve(now); wait != 500*time.Millisecond {
+		t.Errorf("expected to wait 500ms, got %s", wait)
+	}
+	if wait := b.reserve(now); wait != time.Second {
+		t.Errorf("expected to wait 1s, got %s", wait)
+	}
+
+	// tokens are refilled over time, but never beyond the capacity
+	if wait := b.reserve(now.Add(time.Hour)); wait != 0 {
+		t.Errorf("expected no wait, got %s", wait)
+	}
+	if b.tokens != 1 {
+		t.Errorf("expected 1 token left, got %f", b.tokens)
+	}
+}
+
+func Test_parseRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{value: "", want: 0},
+		{value: "30", want: 30 * time.Second},
+		{value: "-5", want: 0},
+		{value: "86400", want: maxRetryAfter},
+		{value: "Mon, 01 Jan 2024 00:01:00 GMT", want: time.Minute},
+		{value: "Sun, 31 Dec 2023 23:59:00 GMT", want: 0},
+		{value: "soon", want: 0},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+
+			if got := parseRetryAfter(tt.value, now); got != tt.want {
+				t.Errorf("parseRetryAfter() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_sleep(t *testing.T) {
+	t.Parallel()
+
+	if err := sleep(context.Background(), time.Millisecond); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	if err := sleep(ctx, time.Hour); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected to stop sleeping once the context was done, but slept for %s", elapsed)
+	}
+}
+
+func Test_adaptiveLimiter(t *testing.T) {
+	t.Parallel()
+
+	l := newAdaptiveLimiter(4)
+	ctx := context.Background()
+
+	for i := 0; i < 4; i++ {
+		if err := l.acquire(ctx); err != nil {
+			t.Fatalf("request %d: expected no error, got %v", i, err)
+		}
+	}
+
+	// no more requests can be made until one is released
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := l.acquire(timeoutCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+
+	// being rate limited halves the limit, so the requests still in flight have to finish first
+	l.release(true)
+	if l.limit != 2 {
+		t.Errorf("expected a limit of 2, got %f", l.limit)
+	}
+
+	acquired := make(chan error)
+	go func() { acquired <- l.acquire(ctx) }()
+
+	l.release(false)
+	l.release(false)
+	if err := <-acquired; err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	// the limit grows back as requests succeed, but never beyond the maximum
+	for i := 0; i < 100; i++ {
+		l.inFlight++
+		l.release(false)
+	}
+	if l.limit != 4 {
+		t.Errorf("expected a limit of 4, got %f", l.limit)
+	}
+
+	// the limit never drops below a single request
+	for i := 0; i < 10; i++ {
+		l.inFlight++
+		l.release(true)
+	}
+	if l.limit != 1 {
+		t.Errorf("expected a limit of 1, got %f", l.limit)
+	}
+}
+
+func Test_notifyRateLimited(t *testing.T) {
+	t.Parallel()
+
+	var first, second []time.Duration
+	firstCtx := WithOnRateLimited(context.Background(), func(retryAfter time.Duration) { first = append(first, retryAfter) })
+	secondCtx := WithOnRateLimited(context.Background(), func(retryAfter time.Duration) { second = append(second, retryAfter) })
+
+	notifyRateLimited(firstCtx, 2*time.Second)
+	notifyRateLimited(secondCtx, 0)
+	// contexts without a function to call are not notified
+	notifyRateLimited(context.Background(), time.Second)
+
+	if len(first) != 1 || first[0] != 2*time.Second {
+		t.Errorf("expected the first context to be notified of being rate limited for 2s, got %v", first)
+	}
+	if len(second) != 1 || second[0] != 0 {
+		t.Errorf("expected the second context to be notified of being rate limited once, got %v", second)
+	}
+}
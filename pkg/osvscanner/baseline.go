@@ -0,0 +1,151 @@
+package osvscanner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+// baselineFinding is a known vulnerability of a package in a source, which is keyed on just
+// these so that it stays known when the package is upgraded to a version that is still affected
+type baselineFinding struct {
+	ID        string `json:"id"`
+	Ecosystem string `json:"ecosystem"`
+	Package   string `json:"package"`
+	// Source is the path of the source relative to the working directory, using forward
+	// slashes, so that the baseline works wherever the project has been checked out to
+	Source string `json:"source"`
+}
+
+type baseline struct {
+	Findings []baselineFinding `json:"findings"`
+}
+
+// baselineSourcePath returns the path of the source relative to the working directory,
+// falling back to the path as it is if it cannot be made relative
+func baselineSourcePath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		if wd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(wd, abs); err == nil {
+				path = rel
+			}
+		}
+	}
+
+	return filepath.ToSlash(path)
+}
+
+// baselineFindings returns the unsuppressed vulnerabilities in the results as baseline findings
+func baselineFindings(results *models.VulnerabilityResults) []baselineFinding {
+	findings := []baselineFinding{}
+	for _, pkgSrc := range results.Results {
+		source := baselineSourcePath(pkgSrc.Source.Path)
+		for _, pkgVulns := range pkgSrc.Packages {
+			for _, vuln := range pkgVulns.Vulnerabilities {
+				finding := baselineFinding{
+					ID:        vuln.ID,
+					Ecosystem: pkgVulns.Package.Ecosystem,
+					Package:   pkgVulns.Package.Name,
+					Source:    source,
+				}
+				if !slices.Contains(findings, finding) {
+					findings = append(findings, finding)
+				}
+			}
+		}
+	}
+
+	return findings
+}
+
+// loadBaseline reads the baseline at the path, recording the findings in the results
+// to it first if it does not exist yet
+func loadBaseline(r reporter.Reporter, results *models.VulnerabilityResults, path string) (baseline, error) {
+	var b baseline
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		b.Findings = baselineFindings(results)
+
+		content, err = json.MarshalIndent(b, "", "  ")
+		if err != nil {
+			return b, fmt.Errorf("failed to record baseline: %w", err)
+		}
+
+		if err := os.WriteFile(path, append(content, '\n'), 0644); err != nil {
+			return b, fmt.Errorf("failed to record baseline: %w", err)
+		}
+
+		r.Infof(
+			"Recorded %d %s in the new baseline %s\n",
+			len(b.Findings),
+			output.Form(len(b.Findings), "finding", "findings"),
+			path,
+		)
+
+		return b, nil
+	}
+	if err != nil {
+		return b, fmt.Errorf("failed to read baseline: %w", err)
+	}
+
+	if err := json.Unmarshal(content, &b); err != nil {
+		return b, fmt.Errorf("failed to parse baseline %s: %w", path, err)
+	}
+
+	return b, nil
+}
+
+// applyBaseline moves the vulnerabilities that are in the baseline at the path (by any of their
+// aliases) into the suppressed bucket of their package, so that only new findings are reported.
+// Returns the total number of vulnerabilities suppressed.
+func applyBaseline(r reporter.Reporter, results *models.VulnerabilityResults, path string) (int, error) {
+	b, err := loadBaseline(r, results, path)
+	if err != nil {
+		return 0, err
+	}
+
+	suppressedCount := 0
+	for i := range results.Results {
+		pkgSrc := &results.Results[i]
+		source := baselineSourcePath(pkgSrc.Source.Path)
+		for j := range pkgSrc.Packages {
+			pkgVulns := &pkgSrc.Packages[j]
+			known := func(id string) bool {
+				return slices.Contains(b.Findings, baselineFinding{
+					ID:        id,
+					Ecosystem: pkgVulns.Package.Ecosystem,
+					Package:   pkgVulns.Package.Name,
+					Source:    source,
+				})
+			}
+
+			reasons := map[string]string{}
+			for _, group := range pkgVulns.Groups {
+				if slices.ContainsFunc(group.Aliases, known) || slices.ContainsFunc(group.IDs, known) {
+					for _, id := range append(slices.Clone(group.IDs), group.Aliases...) {
+						reasons[id] = "in the baseline"
+					}
+				}
+			}
+
+			for _, vuln := range pkgVulns.Vulnerabilities {
+				if _, ok := reasons[vuln.ID]; ok {
+					r.Verbosef("%s is in the baseline for %s\n", vuln.ID, pkgVulns.Package.Name)
+				}
+			}
+
+			suppressedCount += moveToSuppressed(pkgVulns, reasons)
+		}
+	}
+
+	return suppressedCount, nil
+}
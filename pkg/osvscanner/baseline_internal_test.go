@@ -0,0 +1,112 @@
+package osvscanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+func baselineTestResults() models.VulnerabilityResults {
+	return models.VulnerabilityResults{
+		Results: []models.PackageSource{{
+			Source: models.SourceInfo{Path: "path/to/package-lock.json", Type: "lockfile"},
+			Packages: []models.PackageVulns{{
+				Package: models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"},
+				Vulnerabilities: []models.Vulnerability{
+					{ID: "GHSA-35jh-r3h4-6jhm"},
+					{ID: "GHSA-29mw-wpgm-hmr9"},
+				},
+				Groups: []models.GroupInfo{
+					{IDs: []string{"GHSA-35jh-r3h4-6jhm"}, Aliases: []string{"CVE-2021-23337", "GHSA-35jh-r3h4-6jhm"}},
+					{IDs: []string{"GHSA-29mw-wpgm-hmr9"}, Aliases: []string{"CVE-2020-28500", "GHSA-29mw-wpgm-hmr9"}},
+				},
+			}},
+		}},
+	}
+}
+
+func Test_applyBaseline_Records(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	results := baselineTestResults()
+
+	suppressed, err := applyBaseline(&reporter.VoidReporter{}, &results, path)
+	if err != nil {
+		t.Fatalf("applyBaseline() error = %v", err)
+	}
+
+	if suppressed != 2 {
+		t.Errorf("applyBaseline() suppressed %d vulnerabilities, want 2", suppressed)
+	}
+
+	if got := results.Results[0].Packages[0].Vulnerabilities; len(got) != 0 {
+		t.Errorf("applyBaseline() left vulnerabilities %v, want none", got)
+	}
+
+	// the baseline is read back rather than recorded again
+	b, err := loadBaseline(&reporter.VoidReporter{}, &models.VulnerabilityResults{}, path)
+	if err != nil {
+		t.Fatalf("loadBaseline() error = %v", err)
+	}
+
+	want := []baselineFinding{
+		{ID: "GHSA-35jh-r3h4-6jhm", Ecosystem: "npm", Package: "lodash", Source: "path/to/package-lock.json"},
+		{ID: "GHSA-29mw-wpgm-hmr9", Ecosystem: "npm", Package: "lodash", Source: "path/to/package-lock.json"},
+	}
+	if diff := cmp.Diff(want, b.Findings); diff != "" {
+		t.Errorf("recorded baseline mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_applyBaseline_Suppresses(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	// the finding is known by an alias, for a version of the package that has since been upgraded
+	content := `{"findings": [{"id": "CVE-2021-23337", "ecosystem": "npm", "package": "lodash", "source": "path/to/package-lock.json"}]}`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("could not write baseline: %v", err)
+	}
+
+	results := baselineTestResults()
+
+	suppressed, err := applyBaseline(&reporter.VoidReporter{}, &results, path)
+	if err != nil {
+		t.Fatalf("applyBaseline() error = %v", err)
+	}
+
+	if suppressed != 1 {
+		t.Errorf("applyBaseline() suppressed %d vulnerabilities, want 1", suppressed)
+	}
+
+	want := models.PackageVulns{
+		Package:         models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"},
+		Vulnerabilities: []models.Vulnerability{{ID: "GHSA-29mw-wpgm-hmr9"}},
+		Groups: []models.GroupInfo{
+			{IDs: []string{"GHSA-29mw-wpgm-hmr9"}, Aliases: []string{"CVE-2020-28500", "GHSA-29mw-wpgm-hmr9"}},
+		},
+		Suppressed: []models.SuppressedVulnerability{{ID: "GHSA-35jh-r3h4-6jhm", Reason: "in the baseline"}},
+	}
+	if diff := cmp.Diff(want, results.Results[0].Packages[0]); diff != "" {
+		t.Errorf("applyBaseline() package mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_applyBaseline_Invalid(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	if err := os.WriteFile(path, []byte("not json"), 0600); err != nil {
+		t.Fatalf("could not write baseline: %v", err)
+	}
+
+	results := baselineTestResults()
+	if _, err := applyBaseline(&reporter.VoidReporter{}, &results, path); err == nil {
+		t.Errorf("applyBaseline() did not return an error")
+	}
+}
@@ -0,0 +1,82 @@
+package osvscanner
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+// changedFiles are the absolute paths of the files that have changed, with a nil
+// set meaning that every file should be treated as having changed
+type changedFiles map[string]bool
+
+// includes reports if the file at the absolute path has changed
+func (c changedFiles) includes(path string) bool {
+	return c == nil || c[path]
+}
+
+// parseChangedFiles parses a list of files with one path on each line, such as is
+// output by "git diff --name-only", resolving relative paths against base.
+//
+// Paths that git has quoted due to containing unusual characters are unquoted.
+func parseChangedFiles(reader io.Reader, base string) (changedFiles, error) {
+	changed := changedFiles{}
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, `"`) {
+			if unquoted, err := strconv.Unquote(line); err == nil {
+				line = unquoted
+			}
+		}
+
+		path := filepath.FromSlash(line)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(base, path)
+		}
+		changed[filepath.Clean(path)] = true
+	}
+
+	return changed, scanner.Err()
+}
+
+// readChangedFiles reads the list of changed files at path, or from stdin if path is "-",
+// with relative paths being resolved against the current working directory
+func readChangedFiles(r reporter.Reporter, path string) (changedFiles, error) {
+	reader := io.Reader(os.Stdin)
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read changed files from %s: %w", path, err)
+		}
+		defer file.Close()
+
+		reader = file
+	}
+
+	base, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	changed, err := parseChangedFiles(reader, base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read changed files from %s: %w", path, err)
+	}
+
+	r.Infof("Only scanning lockfiles and manifests among the %d changed %s\n", len(changed), output.Form(len(changed), "file", "files"))
+
+	return changed, nil
+}
@@ -0,0 +1,89 @@
+package osvscanner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+func Test_parseChangedFiles(t *testing.T) {
+	t.Parallel()
+
+	base := filepath.FromSlash("/repo")
+	input := strings.Join([]string{
+		"package-lock.json",
+		"",
+		"  nested/go.mod  ",
+		`"with \"quotes\"/requirements.txt"`,
+		"./other/../Cargo.lock",
+	}, "\n")
+
+	got, err := parseChangedFiles(strings.NewReader(input), base)
+	if err != nil {
+		t.Fatalf("parseChangedFiles() error = %v", err)
+	}
+
+	want := changedFiles{
+		filepath.Join(base, "package-lock.json"):                 true,
+		filepath.Join(base, "nested", "go.mod"):                  true,
+		filepath.Join(base, `with "quotes"`, "requirements.txt"): true,
+		filepath.Join(base, "Cargo.lock"):                        true,
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("parseChangedFiles() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestListScanTargets_ChangedFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"requirements.txt":        "django==2.2.0\n",
+		"nested/requirements.txt": "flask==1.0.0\n",
+		"other/requirements.txt":  "requests==2.0.0\n",
+	}
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	changedPath := filepath.Join(t.TempDir(), "changed.txt")
+	changedList := filepath.Join(dir, "nested", "requirements.txt") + "\n" + filepath.Join(dir, "README.md") + "\n"
+	if err := os.WriteFile(changedPath, []byte(changedList), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ListScanTargets(ScannerActions{
+		DirectoryPaths:   []string{dir},
+		Recursive:        true,
+		SkipGit:          true,
+		ChangedFilesPath: changedPath,
+	}, &reporter.VoidReporter{})
+	if err != nil {
+		t.Fatalf("ListScanTargets() error = %v", err)
+	}
+
+	want := []models.ScanTarget{
+		{
+			Source:     models.SourceInfo{Path: filepath.Join(dir, "nested", "requirements.txt"), Type: "lockfile"},
+			Ecosystems: []string{"PyPI"},
+			Packages:   1,
+		},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ListScanTargets() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,68 @@
+package osvscanner
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// PackageCoordinate identifies a version of a package
+type PackageCoordinate struct {
+	Ecosystem string
+	Name      string
+	Version   string
+}
+
+// ParsePackageCoordinate parses a coordinate in the form of "ecosystem:name@version",
+// such as "npm:lodash@4.17.19".
+//
+// Everything after the last "@" is the version, so that scoped npm packages can be given
+// as "npm:@babel/core@7.0.0", and the rest is parsed like ParsePackageSelector.
+func ParsePackageCoordinate(coordinate string) (PackageCoordinate, error) {
+	i := strings.LastIndex(coordinate, "@")
+	if i <= 0 || i == len(coordinate)-1 || strings.HasSuffix(coordinate[:i], ":") {
+		return PackageCoordinate{}, fmt.Errorf("%q is not in the form of ecosystem:name@version", coordinate)
+	}
+
+	selector, err := ParsePackageSelector(coordinate[:i])
+	if err != nil {
+		return PackageCoordinate{}, fmt.Errorf("%q is not in the form of ecosystem:name@version: %w", coordinate, err)
+	}
+
+	return PackageCoordinate{
+		Ecosystem: selector.Ecosystem,
+		Name:      selector.Name,
+		Version:   coordinate[i+1:],
+	}, nil
+}
+
+// String returns the coordinate in the form of "ecosystem:name@version"
+func (c PackageCoordinate) String() string {
+	return c.Ecosystem + ":" + c.Name + "@" + c.Version
+}
+
+// scanPackageCoordinates returns a package for each of the coordinates,
+// which are all grouped into a single "query" source
+func scanPackageCoordinates(coordinates []string) ([]scannedPackage, error) {
+	packages := make([]scannedPackage, 0, len(coordinates))
+	for _, coordinate := range coordinates {
+		c, err := ParsePackageCoordinate(coordinate)
+		if err != nil {
+			return nil, err
+		}
+
+		packages = append(packages, scannedPackage{
+			Name:      c.Name,
+			Version:   c.Version,
+			Ecosystem: lockfile.Ecosystem(c.Ecosystem),
+			Source: models.SourceInfo{
+				Path: "query",
+				Type: "query",
+			},
+		})
+	}
+
+	return packages, nil
+}
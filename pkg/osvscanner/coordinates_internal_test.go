@@ -0,0 +1,60 @@
+package osvscanner
+
+import (
+	"testing"
+)
+
+func TestParsePackageCoordinate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		coordinate string
+		want       PackageCoordinate
+		wantErr    bool
+	}{
+		{coordinate: "npm:lodash@4.17.19", want: PackageCoordinate{Ecosystem: "npm", Name: "lodash", Version: "4.17.19"}},
+		{coordinate: "npm:@babel/core@7.0.0", want: PackageCoordinate{Ecosystem: "npm", Name: "@babel/core", Version: "7.0.0"}},
+		{coordinate: "pypi:Django@2.2.0", want: PackageCoordinate{Ecosystem: "PyPI", Name: "Django", Version: "2.2.0"}},
+		{coordinate: "Maven:org.apache.logging.log4j:log4j-core@2.14.1", want: PackageCoordinate{Ecosystem: "Maven", Name: "org.apache.logging.log4j:log4j-core", Version: "2.14.1"}},
+		{coordinate: "npm:lodash", wantErr: true},
+		{coordinate: "npm:lodash@", wantErr: true},
+		{coordinate: "npm:@4.17.19", wantErr: true},
+		{coordinate: "lodash@4.17.19", wantErr: true},
+		{coordinate: "not-an-ecosystem:lodash@4.17.19", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.coordinate, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParsePackageCoordinate(tt.coordinate)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePackageCoordinate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParsePackageCoordinate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_scanPackageCoordinates(t *testing.T) {
+	t.Parallel()
+
+	got, err := scanPackageCoordinates([]string{"npm:lodash@4.17.19", "pypi:Django@2.2.0"})
+	if err != nil {
+		t.Fatalf("scanPackageCoordinates() error = %v", err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("scanPackageCoordinates() returned %d packages, want 2", len(got))
+	}
+	for _, pkg := range got {
+		if pkg.Source.Path != "query" || pkg.Source.Type != "query" {
+			t.Errorf("scanPackageCoordinates() source = %v, want query", pkg.Source)
+		}
+	}
+	if got[1].Name != "Django" || got[1].Version != "2.2.0" || got[1].Ecosystem != "PyPI" {
+		t.Errorf("scanPackageCoordinates() = %v, want Django 2.2.0 from PyPI", got[1])
+	}
+}
@@ -0,0 +1,148 @@
+package osvscanner
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/dockerfile"
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+var debianReleases = map[string]string{
+	"jessie":   "8",
+	"stretch":  "9",
+	"buster":   "10",
+	"bullseye": "11",
+	"bookworm": "12",
+	"trixie":   "13",
+}
+
+var ubuntuReleases = map[string]string{
+	"xenial":   "16.04",
+	"bionic":   "18.04",
+	"focal":    "20.04",
+	"jammy":    "22.04",
+	"lunar":    "23.04",
+	"mantic":   "23.10",
+	"noble":    "24.04",
+	"oracular": "24.10",
+}
+
+// distroEcosystem determines the OSV ecosystem of the packages that can be installed
+// with apt in an image, based on the release in its tag.
+//
+// Tags that move between releases (such as "latest" or "stable") cannot be used,
+// as the release would depend on when the image is pulled.
+func distroEcosystem(image dockerfile.Image) (lockfile.Ecosystem, bool) {
+	name := strings.TrimPrefix(image.Name, "docker.io/")
+	name = strings.TrimPrefix(name, "library/")
+
+	// variants such as "bookworm-slim" and "jammy-20240111" are of the same release
+	release, _, _ := strings.Cut(image.Tag, "-")
+
+	switch name {
+	case "debian":
+		if r, ok := debianReleases[release]; ok {
+			release = r
+		}
+
+		// point releases such as "12.5" have the same packages as the major release
+		major, _, _ := strings.Cut(release, ".")
+		if !isNumeric(major) {
+			return "", false
+		}
+
+		return lockfile.Ecosystem(fmt.Sprintf("%s:%s", models.EcosystemDebian, major)), true
+	case "ubuntu":
+		if r, ok := ubuntuReleases[release]; ok {
+			release = r
+		}
+
+		year, month, ok := strings.Cut(release, ".")
+		if !ok || !isNumeric(year) || !isNumeric(month) {
+			return "", false
+		}
+
+		ecosystem := fmt.Sprintf("%s:%s", models.EcosystemUbuntu, release)
+		// long term support releases are in April of even years
+		if month == "04" && (year[len(year)-1]-'0')%2 == 0 {
+			ecosystem += ":LTS"
+		}
+
+		return lockfile.Ecosystem(ecosystem), true
+	}
+
+	return "", false
+}
+
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// scanDockerfile statically scans the packages that are pinned to a specific version
+// when installed with apt in a Dockerfile, using the release of the base image of their
+// stage to determine their ecosystem
+func scanDockerfile(r reporter.Reporter, path string) ([]scannedPackage, error) {
+	parsed, err := dockerfile.ParseFile(path)
+	if err != nil {
+		r.Errorf("Failed to parse Dockerfile %s: %v\n", path, err)
+		return nil, err
+	}
+
+	var packages []scannedPackage
+	for _, stage := range parsed.Stages {
+		if len(stage.Installs) == 0 {
+			continue
+		}
+
+		ecosystem, ok := distroEcosystem(stage.Base)
+		if !ok {
+			r.Warnf(
+				"%s:%d: the release of %s cannot be determined, so its installed packages cannot be assessed\n",
+				path, stage.Line, stage.Base,
+			)
+
+			continue
+		}
+
+		for _, install := range stage.Installs {
+			if install.Version == "" {
+				r.Warnf("%s:%d: %s is unpinned, cannot assess\n", path, install.Line, install.Name)
+
+				continue
+			}
+
+			packages = append(packages, scannedPackage{
+				Name:      install.Name,
+				Version:   install.Version,
+				Ecosystem: ecosystem,
+				Source: models.SourceInfo{
+					Path: path,
+					Type: "dockerfile",
+				},
+			})
+		}
+	}
+
+	r.Infof(
+		"Scanned %s file and found %d %s\n",
+		path,
+		len(packages),
+		output.Form(len(packages), "package", "packages"),
+	)
+
+	return packages, nil
+}
@@ -0,0 +1,39 @@
+package osvscanner
+
+import (
+	"testing"
+
+	"github.com/google/osv-scanner/internal/dockerfile"
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func Test_distroEcosystem(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		image dockerfile.Image
+		want  lockfile.Ecosystem
+	}{
+		{image: dockerfile.Image{Name: "debian", Tag: "12"}, want: "Debian:12"},
+		{image: dockerfile.Image{Name: "debian", Tag: "12.5-slim"}, want: "Debian:12"},
+		{image: dockerfile.Image{Name: "docker.io/library/debian", Tag: "bookworm-20240110"}, want: "Debian:12"},
+		{image: dockerfile.Image{Name: "debian", Tag: "stable"}, want: ""},
+		{image: dockerfile.Image{Name: "debian"}, want: ""},
+		{image: dockerfile.Image{Name: "ubuntu", Tag: "22.04"}, want: "Ubuntu:22.04:LTS"},
+		{image: dockerfile.Image{Name: "ubuntu", Tag: "noble-20240225"}, want: "Ubuntu:24.04:LTS"},
+		{image: dockerfile.Image{Name: "ubuntu", Tag: "23.04"}, want: "Ubuntu:23.04"},
+		{image: dockerfile.Image{Name: "ubuntu", Tag: "latest"}, want: ""},
+		{image: dockerfile.Image{Name: "alpine", Tag: "3.19"}, want: ""},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.image.String(), func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := distroEcosystem(tt.image)
+			if got != tt.want || ok != (tt.want != "") {
+				t.Errorf("distroEcosystem() = %v, %v, want %v", got, ok, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package osvscanner
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/google/osv-scanner/internal/resolution/manifest"
+	"github.com/google/osv-scanner/pkg/config"
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+// manifestScanTarget describes the manifest at path as a source without resolving its packages
+func manifestScanTarget(path string) scannedPackage {
+	target := scannedPackage{Source: models.SourceInfo{Path: path, Type: "manifest"}}
+
+	manifestIO, _ := manifest.GetManifestIO(path)
+	switch manifestIO.(type) {
+	case manifest.MavenManifestIO:
+		target.Ecosystem = lockfile.MavenEcosystem
+	case manifest.NpmManifestIO:
+		target.Ecosystem = lockfile.NpmEcosystem
+	}
+
+	return target
+}
+
+// ListScanTargets finds the sources that would be scanned with the actions and the ecosystems
+// of their packages, without making any network requests, so that which files are included
+// can be checked before running a scan.
+func ListScanTargets(actions ScannerActions, r reporter.Reporter) ([]models.ScanTarget, error) {
+	return ListScanTargetsWithContext(context.Background(), actions, r)
+}
+
+// ListScanTargetsWithContext is like ListScanTargets, but stops once ctx is done.
+func ListScanTargetsWithContext(ctx context.Context, actions ScannerActions, r reporter.Reporter) ([]models.ScanTarget, error) {
+	if r == nil {
+		r = &reporter.VoidReporter{}
+	}
+
+	scannedPackages, err := findPackages(ctx, r, actions, &config.ConfigManager{
+		DefaultConfig: config.Config{},
+		ConfigMap:     make(map[string]config.Config),
+		EnvConfig:     actions.EnvConfig,
+	}, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, timeoutErr(ctx, err)
+	}
+
+	if len(actions.OnlyPackages) > 0 {
+		selectors := make([]PackageSelector, 0, len(actions.OnlyPackages))
+		for _, selector := range actions.OnlyPackages {
+			s, err := ParsePackageSelector(selector)
+			if err != nil {
+				return nil, err
+			}
+			selectors = append(selectors, s)
+		}
+
+		scannedPackages = filterOnlyPackages(r, scannedPackages, selectors)
+	}
+
+	if len(scannedPackages) == 0 {
+		return nil, NoPackagesFoundErr
+	}
+
+	return scanTargets(scannedPackages), nil
+}
+
+// scanTargets groups the packages by the source that they were found in, sorted by path and type
+func scanTargets(scannedPackages []scannedPackage) []models.ScanTarget {
+	var targets []models.ScanTarget
+	indexes := map[models.SourceInfo]int{}
+
+	for _, pkg := range scannedPackages {
+		i, ok := indexes[pkg.Source]
+		if !ok {
+			i = len(targets)
+			indexes[pkg.Source] = i
+			targets = append(targets, models.ScanTarget{Source: pkg.Source, Ecosystems: []string{}})
+		}
+
+		target := &targets[i]
+		// manifests and docker images are included without any packages
+		if pkg.Name != "" || pkg.Commit != "" || pkg.PURL != "" {
+			target.Packages++
+		}
+
+		_, ecosystem := packageNameAndEcosystem(pkg)
+		if ecosystem != "" && !slices.Contains(target.Ecosystems, ecosystem) {
+			target.Ecosystems = append(target.Ecosystems, ecosystem)
+		}
+	}
+
+	for i := range targets {
+		slices.Sort(targets[i].Ecosystems)
+	}
+
+	slices.SortFunc(targets, func(a, b models.ScanTarget) int {
+		if a.Source.Path != b.Source.Path {
+			return cmp.Compare(a.Source.Path, b.Source.Path)
+		}
+
+		return cmp.Compare(a.Source.Type, b.Source.Type)
+	})
+
+	return targets
+}
+
+// scannedSources describes the sources that the packages were found in, for the results of a scan
+func scannedSources(scannedPackages []scannedPackage) []models.ScannedSource {
+	targets := scanTargets(scannedPackages)
+
+	sources := make([]models.ScannedSource, 0, len(targets))
+	for _, target := range targets {
+		sources = append(sources, models.ScannedSource{
+			Source:     target.Source,
+			Ecosystems: target.Ecosystems,
+			Packages:   target.Packages,
+			Resolved:   target.Source.Type == "manifest",
+		})
+	}
+
+	return sources
+}
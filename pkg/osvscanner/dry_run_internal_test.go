@@ -0,0 +1,103 @@
+package osvscanner
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+func TestListScanTargets(t *testing.T) {
+	t.Parallel()
+
+	lockfilePath, err := filepath.Abs("fixtures/paths/package-lock.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	purlsPath, err := filepath.Abs("fixtures/purls/purls.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifestPath, err := filepath.Abs("../../internal/resolution/manifest/fixtures/npm-workspaces/package.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ListScanTargets(ScannerActions{
+		LockfilePaths: []string{lockfilePath},
+		PURLPaths:     []string{purlsPath},
+		// neither the image nor the manifest are scanned, so only their ecosystems are known
+		DockerContainerNames: []string{"debian:bookworm"},
+		DirectoryPaths:       []string{filepath.Dir(manifestPath)},
+		ManifestOnly:         true,
+		SkipGit:              true,
+	}, &reporter.VoidReporter{})
+	if err != nil {
+		t.Fatalf("ListScanTargets() error = %v", err)
+	}
+
+	// the targets are sorted by their path
+	want := []models.ScanTarget{
+		{
+			Source:     models.SourceInfo{Path: manifestPath, Type: "manifest"},
+			Ecosystems: []string{"npm"},
+		},
+		{
+			Source:     models.SourceInfo{Path: lockfilePath, Type: "lockfile"},
+			Ecosystems: []string{"npm"},
+			Packages:   4,
+		},
+		{
+			Source:     models.SourceInfo{Path: purlsPath, Type: "purls"},
+			Ecosystems: []string{"Go", "Maven", "npm"},
+			Packages:   4,
+		},
+		{
+			Source:     models.SourceInfo{Path: "debian:bookworm", Type: "docker"},
+			Ecosystems: []string{"Debian"},
+		},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ListScanTargets() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestListScanTargets_NoPackages(t *testing.T) {
+	t.Parallel()
+
+	_, err := ListScanTargets(ScannerActions{
+		DirectoryPaths: []string{"fixtures/example-git"},
+		SkipGit:        true,
+	}, &reporter.VoidReporter{})
+
+	if !errors.Is(err, NoPackagesFoundErr) {
+		t.Errorf("ListScanTargets() error = %v, want %v", err, NoPackagesFoundErr)
+	}
+}
+
+func Test_scannedSources(t *testing.T) {
+	t.Parallel()
+
+	lockfileSource := models.SourceInfo{Path: "/path/to/package-lock.json", Type: "lockfile"}
+	manifestSource := models.SourceInfo{Path: "/path/to/pom.xml", Type: "manifest"}
+
+	got := scannedSources([]scannedPackage{
+		{Name: "lodash", Version: "4.17.20", Ecosystem: lockfile.NpmEcosystem, Source: lockfileSource},
+		{Name: "express", Version: "4.17.1", Ecosystem: lockfile.NpmEcosystem, Source: lockfileSource},
+		{Name: "junit:junit", Version: "4.12", Ecosystem: lockfile.MavenEcosystem, Source: manifestSource},
+	})
+
+	want := []models.ScannedSource{
+		{Source: lockfileSource, Ecosystems: []string{"npm"}, Packages: 2},
+		{Source: manifestSource, Ecosystems: []string{"Maven"}, Packages: 1, Resolved: true},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("scannedSources() mismatch (-want +got):\n%s", diff)
+	}
+}
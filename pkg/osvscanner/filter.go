@@ -0,0 +1,197 @@
+package osvscanner
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+// filterVulnerabilities removes every vulnerability that keep returns false for, preserving order.
+// Groups are updated to match the remaining vulnerabilities, and packages (and sources) which
+// are left without any findings are removed unless allPackages is true.
+// Returns the total number of vulnerabilities removed.
+func filterVulnerabilities(
+	results *models.VulnerabilityResults,
+	allPackages bool,
+	keep func(source models.PackageSource, pkg models.PackageVulns, vuln models.Vulnerability) bool,
+) int {
+	removedCount := 0
+	newResults := []models.PackageSource{} // Want 0 vulnerabilities to show in JSON as an empty list, not null.
+	for _, pkgSrc := range results.Results {
+		var newPackages []models.PackageVulns
+		for _, pkgVulns := range pkgSrc.Packages {
+			var newVulns, removed []models.Vulnerability
+			for _, vuln := range pkgVulns.Vulnerabilities {
+				if keep(pkgSrc, pkgVulns, vuln) {
+					newVulns = append(newVulns, vuln)
+				} else {
+					removed = append(removed, vuln)
+				}
+			}
+
+			if len(removed) > 0 {
+				removedCount += len(removed)
+				pkgVulns.Vulnerabilities = newVulns
+				pkgVulns.Groups = filterGroups(pkgVulns, removed)
+			}
+
+			if allPackages || len(pkgVulns.Vulnerabilities) > 0 || len(pkgVulns.LicenseViolations) > 0 || len(pkgVulns.Suppressed) > 0 {
+				newPackages = append(newPackages, pkgVulns)
+			}
+		}
+		// Don't want to include the package source at all if there are no vulns.
+		if len(newPackages) > 0 {
+			pkgSrc.Packages = newPackages
+			newResults = append(newResults, pkgSrc)
+		}
+	}
+	results.Results = newResults
+
+	return removedCount
+}
+
+// isAliasOf reports if the ID is one of the vulnerabilities or any of their aliases
+func isAliasOf(id string, vulns []models.Vulnerability) bool {
+	return slices.ContainsFunc(vulns, func(v models.Vulnerability) bool {
+		return v.ID == id || slices.Contains(v.Aliases, id)
+	})
+}
+
+// filterGroups returns the groups of the package with the IDs and aliases of the vulnerabilities that
+// were removed from it taken out, dropping any groups that become empty.
+func filterGroups(pkgVulns models.PackageVulns, removed []models.Vulnerability) []models.GroupInfo {
+	var newGroups []models.GroupInfo
+	for _, group := range pkgVulns.Groups {
+		var ids []string
+		for _, id := range group.IDs {
+			if slices.ContainsFunc(pkgVulns.Vulnerabilities, func(v models.Vulnerability) bool { return v.ID == id }) {
+				ids = append(ids, id)
+			}
+		}
+
+		if len(ids) == 0 {
+			continue
+		}
+
+		// aliases only go with the vulnerabilities that were removed if no remaining vulnerability has them
+		var aliases []string
+		for _, alias := range group.Aliases {
+			if slices.Contains(ids, alias) || !isAliasOf(alias, removed) || isAliasOf(alias, pkgVulns.Vulnerabilities) {
+				aliases = append(aliases, alias)
+			}
+		}
+
+		group.IDs = ids
+		group.Aliases = aliases
+		group.MaxSeverity = output.MaxSeverity(group, pkgVulns)
+		newGroups = append(newGroups, group)
+	}
+
+	return newGroups
+}
+
+// filterVulnsSince removes vulnerabilities that were last published or modified before the cutoff.
+// Vulnerabilities without either timestamp are kept. Returns the total number of vulnerabilities removed.
+func filterVulnsSince(r reporter.Reporter, results *models.VulnerabilityResults, since time.Time, allPackages bool) int {
+	return filterVulnerabilities(results, allPackages, func(_ models.PackageSource, _ models.PackageVulns, vuln models.Vulnerability) bool {
+		if vuln.Published.IsZero() && vuln.Modified.IsZero() {
+			r.Infof("%s has no published or modified date, so it has been kept\n", vuln.ID)
+
+			return true
+		}
+
+		return !vuln.Published.Before(since) || !vuln.Modified.Before(since)
+	})
+}
+
+// filterDevVulns removes the vulnerabilities of packages that are only development dependencies,
+// such as npm devDependencies or Maven test scope dependencies. Returns the total number of vulnerabilities removed.
+func filterDevVulns(results *models.VulnerabilityResults, allPackages bool) int {
+	return filterVulnerabilities(results, allPackages, func(_ models.PackageSource, pkg models.PackageVulns, _ models.Vulnerability) bool {
+		return !lockfile.Ecosystem(pkg.Package.Ecosystem).IsDevGroup(pkg.DepGroups)
+	})
+}
+
+// suppressVulns moves the vulnerabilities of the package that have been suppressed by an
+// inline comment in its source (along with any of their aliases) into the suppressed bucket,
+// so that they are not reported as findings but can still be audited.
+func suppressVulns(r reporter.Reporter, pkgVulns *models.PackageVulns, suppressions []lockfile.Suppression) {
+	reasons := map[string]string{}
+	for _, suppression := range suppressions {
+		matched := false
+		for _, group := range pkgVulns.Groups {
+			if !slices.Contains(group.Aliases, suppression.ID) {
+				continue
+			}
+
+			matched = true
+			for _, id := range group.Aliases {
+				reasons[id] = suppression.Reason
+			}
+		}
+
+		if !matched {
+			r.Verbosef("%s is suppressed for %s but does not affect it\n", suppression.ID, pkgVulns.Package.Name)
+		}
+	}
+
+	if len(reasons) == 0 {
+		return
+	}
+
+	var newVulns, removed []models.Vulnerability
+	for _, vuln := range pkgVulns.Vulnerabilities {
+		reason, suppressed := reasons[vuln.ID]
+		if !suppressed {
+			newVulns = append(newVulns, vuln)
+
+			continue
+		}
+		removed = append(removed, vuln)
+
+		if reason == "" {
+			r.Infof("%s has been suppressed for %s by an inline comment\n", vuln.ID, pkgVulns.Package.Name)
+		} else {
+			r.Infof("%s has been suppressed for %s by an inline comment because: %s\n", vuln.ID, pkgVulns.Package.Name, reason)
+		}
+
+		pkgVulns.Suppressed = append(pkgVulns.Suppressed, models.SuppressedVulnerability{
+			ID:     vuln.ID,
+			Reason: reason,
+		})
+	}
+
+	pkgVulns.Vulnerabilities = newVulns
+	pkgVulns.Groups = filterGroups(*pkgVulns, removed)
+}
+
+// moveToSuppressed moves the vulnerabilities of the package that have a reason into the suppressed bucket
+// with that reason, updating the groups to match the remaining vulnerabilities. Returns the number moved.
+func moveToSuppressed(pkgVulns *models.PackageVulns, reasons map[string]string) int {
+	var newVulns, removed []models.Vulnerability
+	for _, vuln := range pkgVulns.Vulnerabilities {
+		reason, suppressed := reasons[vuln.ID]
+		if !suppressed {
+			newVulns = append(newVulns, vuln)
+
+			continue
+		}
+		removed = append(removed, vuln)
+
+		pkgVulns.Suppressed = append(pkgVulns.Suppressed, models.SuppressedVulnerability{
+			ID:     vuln.ID,
+			Reason: reason,
+		})
+	}
+
+	if len(removed) > 0 {
+		pkgVulns.Vulnerabilities = newVulns
+		pkgVulns.Groups = filterGroups(*pkgVulns, removed)
+	}
+
+	return len(removed)
+}
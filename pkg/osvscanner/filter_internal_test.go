@@ -0,0 +1,223 @@
+package osvscanner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+func Test_filterVulnsSince(t *testing.T) {
+	t.Parallel()
+
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := since.Add(-24 * time.Hour)
+	after := since.Add(24 * time.Hour)
+
+	input := models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "/path/to/lockfile", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package: models.PackageInfo{Name: "pkg-a", Version: "1.0.0", Ecosystem: "npm"},
+						Vulnerabilities: []models.Vulnerability{
+							{ID: "GHSA-old", Published: before, Modified: before},
+							{ID: "GHSA-modified", Published: before, Modified: after},
+							{ID: "GHSA-undated"},
+						},
+						Groups: []models.GroupInfo{
+							{IDs: []string{"GHSA-old"}},
+							{IDs: []string{"GHSA-modified"}},
+							{IDs: []string{"GHSA-undated"}},
+						},
+					},
+					{
+						Package: models.PackageInfo{Name: "pkg-b", Version: "1.0.0", Ecosystem: "npm"},
+						Vulnerabilities: []models.Vulnerability{
+							{ID: "GHSA-also-old", Published: before, Modified: before},
+						},
+						Groups: []models.GroupInfo{
+							{IDs: []string{"GHSA-also-old"}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	got := input
+	filtered := filterVulnsSince(&reporter.VoidReporter{}, &got, since, false)
+
+	if filtered != 2 {
+		t.Errorf("filterVulnsSince() = %v, want %v", filtered, 2)
+	}
+
+	want := []models.PackageSource{
+		{
+			Source: models.SourceInfo{Path: "/path/to/lockfile", Type: "lockfile"},
+			Packages: []models.PackageVulns{
+				{
+					Package: models.PackageInfo{Name: "pkg-a", Version: "1.0.0", Ecosystem: "npm"},
+					Vulnerabilities: []models.Vulnerability{
+						{ID: "GHSA-modified", Published: before, Modified: after},
+						{ID: "GHSA-undated"},
+					},
+					Groups: []models.GroupInfo{
+						{IDs: []string{"GHSA-modified"}},
+						{IDs: []string{"GHSA-undated"}},
+					},
+				},
+			},
+		},
+	}
+
+	if diff := cmp.Diff(want, got.Results); diff != "" {
+		t.Errorf("filterVulnsSince() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_suppressVulns(t *testing.T) {
+	t.Parallel()
+
+	pkgVulns := models.PackageVulns{
+		Package: models.PackageInfo{Name: "requests", Version: "2.19.1", Ecosystem: "PyPI"},
+		Vulnerabilities: []models.Vulnerability{
+			{ID: "GHSA-x84v-xcm2-53pg", Aliases: []string{"CVE-2018-18074"}},
+			{ID: "PYSEC-2018-28", Aliases: []string{"CVE-2018-18074"}},
+			{ID: "GHSA-j8r2-6x86-q33q"},
+		},
+		Groups: []models.GroupInfo{
+			{
+				IDs:     []string{"GHSA-x84v-xcm2-53pg", "PYSEC-2018-28"},
+				Aliases: []string{"CVE-2018-18074", "GHSA-x84v-xcm2-53pg", "PYSEC-2018-28"},
+			},
+			{
+				IDs:     []string{"GHSA-j8r2-6x86-q33q"},
+				Aliases: []string{"GHSA-j8r2-6x86-q33q"},
+			},
+		},
+	}
+
+	suppressVulns(&reporter.VoidReporter{}, &pkgVulns, []lockfile.Suppression{
+		{ID: "PYSEC-2018-28", Reason: "we do not use proxies"},
+		{ID: "GHSA-aaaa-bbbb-cccc"},
+	})
+
+	want := models.PackageVulns{
+		Package: models.PackageInfo{Name: "requests", Version: "2.19.1", Ecosystem: "PyPI"},
+		Vulnerabilities: []models.Vulnerability{
+			{ID: "GHSA-j8r2-6x86-q33q"},
+		},
+		Groups: []models.GroupInfo{
+			{
+				IDs:     []string{"GHSA-j8r2-6x86-q33q"},
+				Aliases: []string{"GHSA-j8r2-6x86-q33q"},
+			},
+		},
+		Suppressed: []models.SuppressedVulnerability{
+			{ID: "GHSA-x84v-xcm2-53pg", Reason: "we do not use proxies"},
+			{ID: "PYSEC-2018-28", Reason: "we do not use proxies"},
+		},
+	}
+
+	if diff := cmp.Diff(want, pkgVulns); diff != "" {
+		t.Errorf("suppressVulns() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_filterGroups(t *testing.T) {
+	t.Parallel()
+
+	pkgVulns := models.PackageVulns{
+		Package: models.PackageInfo{Name: "requests", Version: "2.19.1", Ecosystem: "PyPI"},
+		Vulnerabilities: []models.Vulnerability{
+			{ID: "GHSA-x84v-xcm2-53pg", Aliases: []string{"CVE-2018-18074"}},
+		},
+		Groups: []models.GroupInfo{
+			{
+				IDs:     []string{"GHSA-x84v-xcm2-53pg", "PYSEC-2018-28"},
+				Aliases: []string{"CVE-2018-18074", "CVE-2018-99999", "GHSA-x84v-xcm2-53pg", "PYSEC-2018-28"},
+			},
+		},
+	}
+
+	// only the aliases that no other vulnerability in the group has go with it
+	want := []models.GroupInfo{
+		{
+			IDs:     []string{"GHSA-x84v-xcm2-53pg"},
+			Aliases: []string{"CVE-2018-18074", "GHSA-x84v-xcm2-53pg"},
+		},
+	}
+
+	removed := []models.Vulnerability{
+		{ID: "PYSEC-2018-28", Aliases: []string{"CVE-2018-18074", "CVE-2018-99999"}},
+	}
+
+	if diff := cmp.Diff(want, filterGroups(pkgVulns, removed)); diff != "" {
+		t.Errorf("filterGroups() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_filterDevVulns(t *testing.T) {
+	t.Parallel()
+
+	input := models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "/path/to/lockfile", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package:         models.PackageInfo{Name: "pkg-prod", Version: "1.0.0", Ecosystem: "npm"},
+						Vulnerabilities: []models.Vulnerability{{ID: "GHSA-prod"}},
+						Groups:          []models.GroupInfo{{IDs: []string{"GHSA-prod"}}},
+					},
+					{
+						Package:         models.PackageInfo{Name: "pkg-dev", Version: "1.0.0", Ecosystem: "npm"},
+						DepGroups:       []string{"dev"},
+						Vulnerabilities: []models.Vulnerability{{ID: "GHSA-dev-1"}, {ID: "GHSA-dev-2"}},
+						Groups:          []models.GroupInfo{{IDs: []string{"GHSA-dev-1"}}, {IDs: []string{"GHSA-dev-2"}}},
+					},
+				},
+			},
+			{
+				Source: models.SourceInfo{Path: "/path/to/pom.xml", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package:         models.PackageInfo{Name: "org.example:test-lib", Version: "1.0.0", Ecosystem: "Maven"},
+						DepGroups:       []string{"test"},
+						Vulnerabilities: []models.Vulnerability{{ID: "GHSA-test"}},
+						Groups:          []models.GroupInfo{{IDs: []string{"GHSA-test"}}},
+					},
+				},
+			},
+		},
+	}
+
+	got := input
+	filtered := filterDevVulns(&got, false)
+
+	if filtered != 3 {
+		t.Errorf("filterDevVulns() = %v, want %v", filtered, 3)
+	}
+
+	want := []models.PackageSource{
+		{
+			Source: models.SourceInfo{Path: "/path/to/lockfile", Type: "lockfile"},
+			Packages: []models.PackageVulns{
+				{
+					Package:         models.PackageInfo{Name: "pkg-prod", Version: "1.0.0", Ecosystem: "npm"},
+					Vulnerabilities: []models.Vulnerability{{ID: "GHSA-prod"}},
+					Groups:          []models.GroupInfo{{IDs: []string{"GHSA-prod"}}},
+				},
+			},
+		},
+	}
+
+	if diff := cmp.Diff(want, got.Results); diff != "" {
+		t.Errorf("filterDevVulns() results mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,91 @@
+package osvscanner
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/semantic"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// baseEcosystem returns the ecosystem without any release, such as "Debian" for "Debian:12"
+func baseEcosystem(ecosystem string) string {
+	base, _, _ := strings.Cut(ecosystem, ":")
+
+	return base
+}
+
+// compareVersions compares two versions of the ecosystem, falling back to comparing
+// them as strings if the ecosystem is not supported
+func compareVersions(ecosystem string, a, b string) int {
+	v, err := semantic.Parse(a, models.Ecosystem(ecosystem))
+	if err != nil {
+		return strings.Compare(a, b)
+	}
+
+	return v.CompareStr(b)
+}
+
+// minimumFixedVersion returns the lowest version that fixes the vulnerability within an affected
+// range, or an empty string if it has not been fixed (or is fixed by a commit instead of a version)
+func minimumFixedVersion(ecosystem string, r models.Range) string {
+	if r.Type == models.RangeGit {
+		return ""
+	}
+
+	minimum := ""
+	for _, event := range r.Events {
+		if event.Fixed == "" {
+			continue
+		}
+		if minimum == "" || compareVersions(ecosystem, event.Fixed, minimum) < 0 {
+			minimum = event.Fixed
+		}
+	}
+
+	return minimum
+}
+
+// groupFixedVersions returns the minimum fixed version of each range that the vulnerabilities
+// in the group affect the package in, which is empty if none of them have been fixed
+func groupFixedVersions(group models.GroupInfo, pkg models.PackageVulns) []string {
+	ecosystem := baseEcosystem(pkg.Package.Ecosystem)
+
+	fixed := []string{}
+	for _, vuln := range pkg.Vulnerabilities {
+		if !slices.Contains(group.IDs, vuln.ID) {
+			continue
+		}
+
+		for _, affected := range vuln.Affected {
+			if affected.Package.Name != pkg.Package.Name || baseEcosystem(string(affected.Package.Ecosystem)) != ecosystem {
+				continue
+			}
+
+			for _, r := range affected.Ranges {
+				if version := minimumFixedVersion(ecosystem, r); version != "" && !slices.Contains(fixed, version) {
+					fixed = append(fixed, version)
+				}
+			}
+		}
+	}
+
+	slices.SortFunc(fixed, func(a, b string) int {
+		return compareVersions(ecosystem, a, b)
+	})
+
+	return fixed
+}
+
+// addFixedVersions sets the versions that fix every group in the results, so that the
+// reporters can show how each vulnerability can be remediated
+func addFixedVersions(results *models.VulnerabilityResults) {
+	for i := range results.Results {
+		for j := range results.Results[i].Packages {
+			pkg := &results.Results[i].Packages[j]
+			for k, group := range pkg.Groups {
+				pkg.Groups[k].FixedVersions = groupFixedVersions(group, *pkg)
+			}
+		}
+	}
+}
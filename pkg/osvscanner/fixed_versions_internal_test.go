@@ -0,0 +1,69 @@
+package osvscanner
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func Test_addFixedVersions(t *testing.T) {
+	t.Parallel()
+
+	affected := func(name string, ecosystem models.Ecosystem, ranges ...models.Range) models.Affected {
+		return models.Affected{Package: models.Package{Name: name, Ecosystem: ecosystem}, Ranges: ranges}
+	}
+	fixedRange := func(typ models.RangeType, events ...models.Event) models.Range {
+		return models.Range{Type: typ, Events: events}
+	}
+
+	results := models.VulnerabilityResults{
+		Results: []models.PackageSource{{
+			Source: models.SourceInfo{Path: "/path/to/requirements.txt", Type: "lockfile"},
+			Packages: []models.PackageVulns{{
+				Package: models.PackageInfo{Name: "django", Version: "2.2.0", Ecosystem: "PyPI"},
+				Vulnerabilities: []models.Vulnerability{
+					{
+						ID: "GHSA-1",
+						Affected: []models.Affected{
+							affected("django", "PyPI",
+								fixedRange(models.RangeEcosystem,
+									models.Event{Introduced: "0"},
+									models.Event{Fixed: "2.2.10"},
+									models.Event{Introduced: "3.0"},
+									models.Event{Fixed: "3.0.3"},
+								),
+								fixedRange(models.RangeGit, models.Event{Introduced: "0"}, models.Event{Fixed: "abc123"}),
+							),
+							affected("django", "PyPI", fixedRange(models.RangeEcosystem, models.Event{Introduced: "4.0"}, models.Event{Fixed: "4.0.10"})),
+							affected("flask", "PyPI", fixedRange(models.RangeEcosystem, models.Event{Introduced: "0"}, models.Event{Fixed: "1.0"})),
+						},
+					},
+					{
+						ID:       "CVE-1",
+						Affected: []models.Affected{affected("django", "PyPI", fixedRange(models.RangeEcosystem, models.Event{Introduced: "0"}, models.Event{Fixed: "2.2.10"}))},
+					},
+					{
+						ID:       "GHSA-2",
+						Affected: []models.Affected{affected("django", "PyPI", fixedRange(models.RangeEcosystem, models.Event{Introduced: "0"}))},
+					},
+				},
+				Groups: []models.GroupInfo{
+					{IDs: []string{"CVE-1", "GHSA-1"}},
+					{IDs: []string{"GHSA-2"}},
+				},
+			}},
+		}},
+	}
+
+	addFixedVersions(&results)
+
+	want := []models.GroupInfo{
+		{IDs: []string{"CVE-1", "GHSA-1"}, FixedVersions: []string{"2.2.10", "4.0.10"}},
+		{IDs: []string{"GHSA-2"}, FixedVersions: []string{}},
+	}
+
+	if diff := cmp.Diff(want, results.Results[0].Packages[0].Groups); diff != "" {
+		t.Errorf("addFixedVersions() groups mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,194 @@
+package osvscanner
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/reporter"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+var errBinaryFile = errors.New("file is binary")
+
+// A gitRefFile represents a file that exists in the tree of a git repository at a particular ref
+type gitRefFile struct {
+	io.ReadCloser
+
+	tree     *object.Tree
+	repoRoot string
+	// name is the slash separated path of the file relative to the root of the tree
+	name string
+}
+
+func (f gitRefFile) Open(openPath string) (lockfile.NestedDepFile, error) {
+	if filepath.IsAbs(openPath) {
+		rel, err := filepath.Rel(f.repoRoot, openPath)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			return gitRefFile{}, fmt.Errorf("%s is not within the repository", openPath)
+		}
+
+		return openGitRefFile(f.tree, f.repoRoot, filepath.ToSlash(rel))
+	}
+
+	return openGitRefFile(f.tree, f.repoRoot, path.Join(path.Dir(f.name), filepath.ToSlash(openPath)))
+}
+
+// Path returns where the file would be in the working tree if the ref was checked out
+func (f gitRefFile) Path() string {
+	return filepath.Join(f.repoRoot, filepath.FromSlash(f.name))
+}
+
+func openGitRefFile(tree *object.Tree, repoRoot string, name string) (gitRefFile, error) {
+	file, err := tree.File(name)
+	if err != nil {
+		return gitRefFile{}, fmt.Errorf("%s: %w", name, err)
+	}
+
+	if isBinary, err := file.IsBinary(); err != nil || isBinary {
+		return gitRefFile{}, fmt.Errorf("%s: %w", name, errBinaryFile)
+	}
+
+	reader, err := file.Reader()
+	if err != nil {
+		return gitRefFile{}, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return gitRefFile{
+		ReadCloser: reader,
+		tree:       tree,
+		repoRoot:   repoRoot,
+		name:       name,
+	}, nil
+}
+
+var _ lockfile.DepFile = gitRefFile{}
+var _ lockfile.NestedDepFile = gitRefFile{}
+
+// scanGitRef scans the lockfiles within dir as they exist in the git repository
+// containing dir at the given ref, without needing the ref to be checked out,
+// limited to those that are in changed if it is not nil and that the filters include
+func scanGitRef(r reporter.Reporter, dir string, ref string, recursive bool, changed changedFiles, filters pathFilters) ([]scannedPackage, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open git repository for %s: %w", dir, err)
+	}
+
+	repoRoot := dir
+	// bare repositories don't have a worktree, in which case dir has to be the root
+	if worktree, err := repo.Worktree(); err == nil {
+		repoRoot = worktree.Filesystem.Root()
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s in %s: %w", ref, repoRoot, err)
+	}
+
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit %s: %w", hash, err)
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree of commit %s: %w", hash, err)
+	}
+
+	prefix, err := filepath.Rel(repoRoot, dir)
+	if err != nil {
+		return nil, err
+	}
+	prefix = filepath.ToSlash(prefix)
+
+	r.Infof("Scanning %s at %s (commit %s)\n", dir, ref, hash)
+
+	var packages []scannedPackage
+
+	err = tree.Files().ForEach(func(file *object.File) error {
+		name := file.Name
+		if prefix != "." {
+			if !strings.HasPrefix(name, prefix+"/") {
+				return nil
+			}
+			name = strings.TrimPrefix(name, prefix+"/")
+		}
+
+		if !recursive && strings.Contains(name, "/") {
+			return nil
+		}
+
+		if !filters.includes(name) {
+			return nil
+		}
+
+		if extractor, _ := lockfile.FindExtractor(file.Name, ""); extractor == nil {
+			return nil
+		}
+
+		if !changed.includes(filepath.Join(repoRoot, filepath.FromSlash(file.Name))) {
+			return nil
+		}
+
+		pkgs, err := scanGitRefLockfile(r, tree, repoRoot, file.Name, ref)
+		if err != nil {
+			if errors.Is(err, errBinaryFile) {
+				r.Infof("Skipped %s at %s because it is a binary file\n", file.Name, ref)
+			} else {
+				r.Errorf("Attempted to scan lockfile at %s but failed: %v\n", ref, err)
+			}
+
+			// Not fatal, so don't return and continue scanning other files
+			return nil
+		}
+		packages = append(packages, pkgs...)
+
+		return nil
+	})
+
+	return packages, err
+}
+
+// scanGitRefLockfile extracts the packages from the lockfile with the given name
+// in the tree, with the source recording the ref that the tree is from
+func scanGitRefLockfile(r reporter.Reporter, tree *object.Tree, repoRoot string, name string, ref string) ([]scannedPackage, error) {
+	f, err := openGitRefFile(tree, repoRoot, name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	parsedLockfile, err := lockfile.ExtractDeps(f, "")
+	if err != nil {
+		return nil, err
+	}
+
+	r.Infof(
+		"Scanned %s@%s file and found %d %s\n",
+		f.Path(),
+		ref,
+		len(parsedLockfile.Packages),
+		output.Form(len(parsedLockfile.Packages), "package", "packages"),
+	)
+
+	// the path is kept as the path on disk, so that config is loaded from the directory of the lockfile
+	return newScannedPackages(parsedLockfile.Packages, models.SourceInfo{
+		Path: f.Path(),
+		Type: "lockfile",
+		Ref:  ref,
+	}), nil
+}
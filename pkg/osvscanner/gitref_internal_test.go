@@ -0,0 +1,202 @@
+package osvscanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/config"
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+// commitFiles writes the given files to the worktree of the repository and commits them,
+// returning the hash of the new commit
+func commitFiles(t *testing.T, repo *git.Repository, files map[string]string) string {
+	t.Helper()
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, content := range files {
+		p := filepath.Join(worktree.Filesystem.Root(), name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := worktree.Add(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	hash, err := worktree.Commit("update", &git.CommitOptions{
+		Author: &object.Signature{Name: "osv-scanner", Email: "osv-scanner@example.com", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return hash.String()
+}
+
+func Test_scanGitRef(t *testing.T) {
+	t.Parallel()
+
+	repoDir := t.TempDir()
+	repo, err := git.PlainInit(repoDir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := commitFiles(t, repo, map[string]string{
+		"requirements.txt":        "django==2.2.0\n",
+		"nested/requirements.txt": "flask==1.0.0\n",
+		"Gemfile.lock":            "\x00\x01\x02",
+		"README.md":               "# example\n",
+	})
+	commitFiles(t, repo, map[string]string{
+		"requirements.txt": "django==4.2.0\n",
+	})
+
+	pkg := func(name, version, path, ref string) scannedPackage {
+		return scannedPackage{
+			Name:      name,
+			Version:   version,
+			Ecosystem: lockfile.PipEcosystem,
+			DepGroups: []string{"requirements"},
+			Source: models.SourceInfo{
+				Path: filepath.Join(repoDir, path),
+				Type: "lockfile",
+				Ref:  ref,
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		dir       string
+		ref       string
+		recursive bool
+		changed   changedFiles
+		want      []scannedPackage
+		wantErr   bool
+	}{
+		{
+			name: "head",
+			dir:  repoDir,
+			ref:  "HEAD",
+			want: []scannedPackage{pkg("django", "4.2.0", "requirements.txt", "HEAD")},
+		},
+		{
+			name: "previous commit",
+			dir:  repoDir,
+			ref:  "HEAD~1",
+			want: []scannedPackage{pkg("django", "2.2.0", "requirements.txt", "HEAD~1")},
+		},
+		{
+			name:      "commit hash, recursively",
+			dir:       repoDir,
+			ref:       first,
+			recursive: true,
+			want: []scannedPackage{
+				pkg("flask", "1.0.0", "nested/requirements.txt", first),
+				pkg("django", "2.2.0", "requirements.txt", first),
+			},
+		},
+		{
+			name:      "only changed files",
+			dir:       repoDir,
+			ref:       first,
+			recursive: true,
+			changed:   changedFiles{filepath.Join(repoDir, "nested", "requirements.txt"): true},
+			want:      []scannedPackage{pkg("flask", "1.0.0", "nested/requirements.txt", first)},
+		},
+		{
+			name: "subdirectory of the repository",
+			dir:  filepath.Join(repoDir, "nested"),
+			ref:  "HEAD",
+			want: []scannedPackage{pkg("flask", "1.0.0", "nested/requirements.txt", "HEAD")},
+		},
+		{
+			name:    "ref that does not exist",
+			dir:     repoDir,
+			ref:     "does-not-exist",
+			wantErr: true,
+		},
+		{
+			name:    "directory that is not a repository",
+			dir:     t.TempDir(),
+			ref:     "HEAD",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := scanGitRef(&reporter.VoidReporter{}, tt.dir, tt.ref, tt.recursive, tt.changed, pathFilters{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("scanGitRef() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("scanGitRef() packages mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_scanGitRef_AppliesConfig(t *testing.T) {
+	t.Parallel()
+
+	repoDir := t.TempDir()
+	repo, err := git.PlainInit(repoDir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commitFiles(t, repo, map[string]string{
+		"requirements.txt": "django==2.2.0\n",
+		"osv-scanner.toml": "[[IgnoredVulns]]\nid = \"GHSA-1\"\nreason = \"not exploitable\"\n",
+	})
+
+	pkgs, err := scanGitRef(&reporter.VoidReporter{}, repoDir, "HEAD", false, nil, pathFilters{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pkgs) != 1 {
+		t.Fatalf("expected 1 package, got %d", len(pkgs))
+	}
+
+	results := models.VulnerabilityResults{
+		Results: []models.PackageSource{{
+			Source: pkgs[0].Source,
+			Packages: []models.PackageVulns{{
+				Package:         models.PackageInfo{Name: pkgs[0].Name, Version: pkgs[0].Version, Ecosystem: string(pkgs[0].Ecosystem)},
+				Vulnerabilities: []models.Vulnerability{{ID: "GHSA-1"}, {ID: "GHSA-2"}},
+				Groups: []models.GroupInfo{
+					{IDs: []string{"GHSA-1"}, Aliases: []string{"GHSA-1"}},
+					{IDs: []string{"GHSA-2"}, Aliases: []string{"GHSA-2"}},
+				},
+			}},
+		}},
+	}
+
+	configManager := config.ConfigManager{
+		DefaultConfig: config.Config{},
+		ConfigMap:     make(map[string]config.Config),
+	}
+
+	if filtered := filterResults(&reporter.VoidReporter{}, &results, &configManager, false); filtered != 1 {
+		t.Errorf("filterResults() = %v, want 1", filtered)
+	}
+}
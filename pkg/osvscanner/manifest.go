@@ -0,0 +1,221 @@
+package osvscanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"deps.dev/util/resolve"
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/internal/resolution/client"
+	"github.com/google/osv-scanner/internal/resolution/datasource"
+	"github.com/google/osv-scanner/internal/resolution/manifest"
+	"github.com/google/osv-scanner/pkg/config"
+	"github.com/google/osv-scanner/pkg/depsdev"
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+// ErrUnresolvedRequirement is returned when a requirement of a manifest
+// cannot be resolved to a version while resolving strictly
+var ErrUnresolvedRequirement = errors.New("could not resolve requirement")
+
+var manifestEcosystems = map[resolve.System]lockfile.Ecosystem{
+	resolve.NPM:   lockfile.NpmEcosystem,
+	resolve.Maven: lockfile.MavenEcosystem,
+}
+
+// isResolvableManifest checks if the path is to a manifest that can be resolved by scanManifest
+func isResolvableManifest(path string) bool {
+	// the manifests of installed packages are not requirements of the project
+	if strings.Contains(filepath.ToSlash(path), "/node_modules/") {
+		return false
+	}
+
+	_, err := manifest.GetManifestIO(path)
+
+	return err == nil
+}
+
+func newManifestClient(system resolve.System, path string) (resolve.Client, error) {
+	switch system { //nolint:exhaustive
+	case resolve.NPM:
+		return client.NewNpmRegistryClient(filepath.Dir(path))
+	case resolve.Maven:
+		return client.NewDepsDevClient(depsdev.DepsdevAPI)
+	default:
+		return nil, fmt.Errorf("manifests for %v are not supported", system)
+	}
+}
+
+// manifestClients are the clients that manifests are resolved with, which are shared by the
+// manifests of a scan (even across repositories) so that their packages are only fetched once
+type manifestClients struct {
+	clients map[string]resolve.Client
+}
+
+func newManifestClients() *manifestClients {
+	return &manifestClients{clients: make(map[string]resolve.Client)}
+}
+
+// get returns the client for the manifest at path, which is shared with the other manifests
+// of the system that use the same registries (and credentials, for npm)
+func (c *manifestClients) get(system resolve.System, path string) (resolve.Client, error) {
+	key := system.String()
+	if system == resolve.NPM {
+		registries, err := datasource.LoadNpmRegistryConfig(filepath.Dir(path))
+		if err != nil {
+			return nil, err
+		}
+		key += fmt.Sprint(registries)
+	}
+
+	if cl, ok := c.clients[key]; ok {
+		return cl, nil
+	}
+
+	cl, err := newManifestClient(system, path)
+	if err != nil {
+		return nil, err
+	}
+	c.clients[key] = cl
+
+	return cl, nil
+}
+
+// mavenCredentialHelpers converts the Maven credential helpers of the config into
+// the form used by the Maven registry client
+func mavenCredentialHelpers(configHelpers []config.MavenCredentialHelper) []datasource.MavenCredentialHelper {
+	helpers := make([]datasource.MavenCredentialHelper, len(configHelpers))
+	for i, helper := range configHelpers {
+		helpers[i] = datasource.MavenCredentialHelper{
+			Registry: helper.Registry,
+			Command:  helper.Command,
+			Scheme:   helper.Scheme,
+		}
+	}
+
+	return helpers
+}
+
+// scanManifest resolves each of the direct requirements of the manifest at path to
+// the latest version that matches it, rather than using the versions from a lockfile.
+//
+// Transitive dependencies are not included, as they depend on how the requirements
+// would be resolved into a lockfile.
+//
+// Requirements that cannot be resolved are skipped with a warning,
+// unless strictResolve is set in which case an error is returned for the first of them.
+//
+// Parent POMs and BOMs are fetched from the first of mavenRegistries that has them,
+// or from Maven Central if there are none.
+//
+// The client used to resolve the requirements is shared through clients.
+func scanManifest(ctx context.Context, r reporter.Reporter, path string, showProgress bool, strictResolve bool, mavenRegistries []string, configManager *config.ConfigManager, clients *manifestClients) ([]scannedPackage, error) {
+	manifestIO, err := manifest.GetManifestIO(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if mavenIO, ok := manifestIO.(manifest.MavenManifestIO); ok {
+		mavenIO.MavenRegistryAPIClient = *datasource.NewMavenRegistryAPIClient(mavenRegistries...)
+		mavenIO.SetCredentialHelpers(mavenCredentialHelpers(configManager.MavenCredentialHelpers()))
+		manifestIO = mavenIO
+	}
+
+	f, err := lockfile.OpenLocalDepFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	m, err := manifestIO.Read(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	cl, err := clients.get(m.System(), path)
+	if err != nil {
+		return nil, err
+	}
+
+	ecosystem := manifestEcosystems[m.System()]
+	logger := reporter.Logger(r).With("manifest", path)
+
+	packages := make([]scannedPackage, 0, len(m.Requirements))
+	var unresolved []string
+	for i, req := range m.Requirements {
+		if showProgress {
+			reporter.Progress(r, i, len(m.Requirements), "Resolved %d/%d requirements")
+		}
+
+		start := time.Now()
+		versions, err := cl.MatchingVersions(ctx, req.VersionKey)
+		if err == nil && len(versions) == 0 {
+			err = errors.New("no versions match the requirement")
+		}
+		if err != nil && ctx.Err() != nil {
+			// the other requirements cannot be resolved either, so there is no point trying them
+			return nil, ctx.Err()
+		}
+		if err != nil {
+			logger.Info("Could not resolve requirement to a version", "package", req.Name, "requirement", req.Version)
+
+			if strictResolve {
+				return nil, fmt.Errorf("%w %s@%s: %w", ErrUnresolvedRequirement, req.Name, req.Version, err)
+			}
+			unresolved = append(unresolved, req.Name+"@"+req.Version)
+
+			continue
+		}
+
+		// the matching versions are sorted, so the last one is the latest
+		latest := versions[len(versions)-1]
+
+		logger.Debug("Resolved requirement",
+			"package", req.Name,
+			"requirement", req.Version,
+			"version", latest.Version,
+			"duration", time.Since(start),
+		)
+
+		packages = append(packages, scannedPackage{
+			Name:      latest.Name,
+			Version:   latest.Version,
+			Ecosystem: ecosystem,
+			DepGroups: m.Groups[req.PackageKey],
+			Source: models.SourceInfo{
+				Path: path,
+				Type: "manifest",
+			},
+		})
+	}
+
+	if showProgress {
+		reporter.Progress(r, len(m.Requirements), len(m.Requirements), "Resolved %d/%d requirements")
+	}
+
+	if len(unresolved) > 0 {
+		r.Warnf(
+			"Partial resolution of %s: could not resolve %d %s, so %s not scanned: %s\n",
+			path,
+			len(unresolved),
+			output.Form(len(unresolved), "requirement", "requirements"),
+			output.Form(len(unresolved), "it was", "they were"),
+			strings.Join(unresolved, ", "),
+		)
+	}
+
+	r.Infof(
+		"Resolved %s from manifest and found %d %s (resolved from manifest, not locked)\n",
+		path,
+		len(packages),
+		output.Form(len(packages), "package", "packages"),
+	)
+
+	return packages, nil
+}
@@ -0,0 +1,140 @@
+package osvscanner
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/osv-scanner/internal/testutility"
+	"github.com/google/osv-scanner/pkg/config"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+func Test_isResolvableManifest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "/path/to/package.json", want: true},
+		{path: "/path/to/pom.xml", want: true},
+		{path: "/path/to/node_modules/lodash/package.json", want: false},
+		{path: "/path/to/package-lock.json", want: false},
+		{path: "/path/to/requirements.txt", want: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+
+			if got := isResolvableManifest(tt.path); got != tt.want {
+				t.Errorf("isResolvableManifest() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_scanManifest_Unresolvable(t *testing.T) {
+	t.Parallel()
+
+	srv := testutility.NewMockHTTPServer(t)
+	srv.SetResponse(t, "fake-package", []byte(`{
+		"name": "fake-package",
+		"dist-tags": {"latest": "1.0.0"},
+		"versions": {"1.0.0": {"name": "fake-package", "version": "1.0.0"}}
+	}`))
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "package.json")
+	if err := os.WriteFile(path, []byte(`{"dependencies": {"fake-package": "^1.0.0", "missing-package": "^1.0.0"}}`), 0600); err != nil {
+		t.Fatalf("could not write manifest: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".npmrc"), []byte("registry="+srv.URL+"\n"), 0600); err != nil {
+		t.Fatalf("could not write npmrc: %v", err)
+	}
+
+	configManager := &config.ConfigManager{ConfigMap: make(map[string]config.Config)}
+
+	pkgs, err := scanManifest(context.Background(), &reporter.VoidReporter{}, path, false, false, nil, configManager, newManifestClients())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pkgs) != 1 || pkgs[0].Name != "fake-package" || pkgs[0].Version != "1.0.0" {
+		t.Errorf("expected only fake-package@1.0.0 to be resolved, got %v", pkgs)
+	}
+
+	_, err = scanManifest(context.Background(), &reporter.VoidReporter{}, path, false, true, nil, configManager, newManifestClients())
+	if !errors.Is(err, ErrUnresolvedRequirement) {
+		t.Errorf("expected ErrUnresolvedRequirement, got %v", err)
+	}
+	if err != nil && !strings.Contains(err.Error(), "missing-package@^1.0.0") {
+		t.Errorf("expected error to name the unresolved requirement, got %v", err)
+	}
+}
+
+func Test_scanManifest_TimedOut(t *testing.T) {
+	t.Parallel()
+
+	srv := testutility.NewMockHTTPServer(t)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "package.json")
+	if err := os.WriteFile(path, []byte(`{"dependencies": {"fake-package": "^1.0.0", "other-package": "^1.0.0"}}`), 0600); err != nil {
+		t.Fatalf("could not write manifest: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".npmrc"), []byte("registry="+srv.URL+"\n"), 0600); err != nil {
+		t.Fatalf("could not write npmrc: %v", err)
+	}
+
+	configManager := &config.ConfigManager{ConfigMap: make(map[string]config.Config)}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+
+	// requirements should not be skipped when the deadline has been exceeded, even when not strict
+	_, err := scanManifest(ctx, &reporter.VoidReporter{}, path, false, false, nil, configManager, newManifestClients())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func Test_scanManifest_MavenRegistries(t *testing.T) {
+	t.Parallel()
+
+	mirror := testutility.NewMockHTTPServer(t)
+	fallback := testutility.NewMockHTTPServer(t)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "pom.xml")
+	if err := os.WriteFile(path, []byte(`
+	<project>
+	  <groupId>com.mycompany.app</groupId>
+	  <artifactId>my-app</artifactId>
+	  <version>1.0</version>
+	  <parent>
+	    <groupId>org.missing</groupId>
+	    <artifactId>parent</artifactId>
+	    <version>1.0.0</version>
+	  </parent>
+	</project>
+	`), 0600); err != nil {
+		t.Fatalf("could not write manifest: %v", err)
+	}
+
+	configManager := &config.ConfigManager{ConfigMap: make(map[string]config.Config)}
+
+	// the parent is not in any of the registries, so every one of them should have been tried
+	_, err := scanManifest(context.Background(), &reporter.VoidReporter{}, path, false, false, []string{mirror.URL, fallback.URL}, configManager, newManifestClients())
+	if err == nil {
+		t.Fatalf("expected an error as the parent cannot be fetched")
+	}
+	for _, registry := range []string{mirror.URL, fallback.URL} {
+		if !strings.Contains(err.Error(), registry) {
+			t.Errorf("expected the parent to be fetched from %s, got %v", registry, err)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package osvscanner
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"deps.dev/util/semver"
+	"github.com/google/osv-scanner/internal/cachedregexp"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+// nodeEcosystemSpecificKey is the key of the constraint on the versions of Node that an
+// affected npm package is vulnerable under, within the ecosystem_specific field of the affected entry
+const nodeEcosystemSpecificKey = "node"
+
+// exactNodeVersion returns the version if it is a single exact version, such as "v18.17.0"
+// or "=18.17.0", rather than a range, a partial version, or an alias like "lts/hydrogen"
+func exactNodeVersion(version string) (string, bool) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "=")
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+
+	if !cachedregexp.MustCompile(`^\d+\.\d+\.\d+$`).MatchString(version) {
+		return "", false
+	}
+
+	return version, true
+}
+
+// readNodeVersion returns the Node version that the project in the directory is pinned to
+// by its .nvmrc, or otherwise the engines.node field of its package.json, along with the
+// file that it was read from. The version is empty if the file pins a range or cannot be read.
+func readNodeVersion(dir string) (version string, from string) {
+	nvmrc := filepath.Join(dir, ".nvmrc")
+	if content, err := os.ReadFile(nvmrc); err == nil {
+		version, _ = exactNodeVersion(string(content))
+
+		return version, nvmrc
+	}
+
+	packageJSON := filepath.Join(dir, "package.json")
+	content, err := os.ReadFile(packageJSON)
+	if err != nil {
+		return "", ""
+	}
+
+	var pkg struct {
+		Engines struct {
+			Node string `json:"node"`
+		} `json:"engines"`
+	}
+	if err := json.Unmarshal(content, &pkg); err != nil || pkg.Engines.Node == "" {
+		return "", ""
+	}
+
+	version, _ = exactNodeVersion(pkg.Engines.Node)
+
+	return version, packageJSON
+}
+
+// affectsNodeVersion reports if the vulnerability affects the npm package when it is run under
+// the Node version, which is only not the case if every affected entry for the package has a
+// Node constraint that the version does not satisfy. Constraints that cannot be parsed are
+// assumed to be satisfied.
+func affectsNodeVersion(r reporter.Reporter, vuln models.Vulnerability, pkg models.PackageInfo, version string) bool {
+	scoped := false
+	for _, affected := range vuln.Affected {
+		if affected.Package.Name != pkg.Name || baseEcosystem(string(affected.Package.Ecosystem)) != string(models.EcosystemNPM) {
+			continue
+		}
+
+		constraint, ok := affected.EcosystemSpecific[nodeEcosystemSpecificKey].(string)
+		if !ok {
+			return true
+		}
+
+		c, err := semver.NPM.ParseConstraint(constraint)
+		if err != nil {
+			r.Infof("%s has a Node constraint of %q that could not be parsed, so it is assumed to apply\n", vuln.ID, constraint)
+
+			return true
+		}
+
+		if c.Match(version) {
+			return true
+		}
+		scoped = true
+	}
+
+	return !scoped
+}
+
+// filterNodeVersionVulns removes the vulnerabilities of npm packages that do not apply under the
+// Node version that their project is pinned to, for vulnerabilities which are scoped to Node
+// versions by their ecosystem-specific fields. Vulnerabilities in projects whose Node version
+// cannot be determined are kept. Returns the total number of vulnerabilities removed.
+func filterNodeVersionVulns(r reporter.Reporter, results *models.VulnerabilityResults, allPackages bool) int {
+	versions := map[string]string{}
+
+	return filterVulnerabilities(results, allPackages, func(source models.PackageSource, pkg models.PackageVulns, vuln models.Vulnerability) bool {
+		if pkg.Package.Ecosystem != string(models.EcosystemNPM) {
+			return true
+		}
+
+		dir := filepath.Dir(source.Source.Path)
+		version, read := versions[dir]
+		if !read {
+			var from string
+			version, from = readNodeVersion(dir)
+			versions[dir] = version
+
+			switch {
+			case from == "":
+				r.Infof("No .nvmrc or engines.node was found for %s, so its findings are assumed to apply to every Node version\n", source.Source.Path)
+			case version == "":
+				r.Infof("%s does not pin a single Node version, so the findings of %s are assumed to apply to every Node version\n", from, source.Source.Path)
+			}
+		}
+
+		if version == "" {
+			return true
+		}
+
+		return affectsNodeVersion(r, vuln, pkg.Package, version)
+	})
+}
@@ -0,0 +1,125 @@
+package osvscanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+func Test_readNodeVersion(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{
+			name:  "nvmrc",
+			files: map[string]string{".nvmrc": "v18.17.0\n", "package.json": `{"engines": {"node": "20.0.0"}}`},
+			want:  "18.17.0",
+		},
+		{
+			name:  "nvmrc alias",
+			files: map[string]string{".nvmrc": "lts/hydrogen\n", "package.json": `{"engines": {"node": "20.0.0"}}`},
+			want:  "",
+		},
+		{
+			name:  "engines",
+			files: map[string]string{"package.json": `{"engines": {"node": "=20.1.0"}}`},
+			want:  "20.1.0",
+		},
+		{
+			name:  "engines range",
+			files: map[string]string{"package.json": `{"engines": {"node": ">=18"}}`},
+			want:  "",
+		},
+		{
+			name:  "neither",
+			files: map[string]string{},
+			want:  "",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if got, _ := readNodeVersion(dir); got != tt.want {
+				t.Errorf("readNodeVersion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_filterNodeVersionVulns(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".nvmrc"), []byte("18.17.0\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	scoped := func(id string, constraints ...string) models.Vulnerability {
+		vuln := models.Vulnerability{ID: id}
+		for _, constraint := range constraints {
+			affected := models.Affected{Package: models.Package{Name: "undici", Ecosystem: models.EcosystemNPM}}
+			if constraint != "" {
+				affected.EcosystemSpecific = map[string]interface{}{"node": constraint}
+			}
+			vuln.Affected = append(vuln.Affected, affected)
+		}
+
+		return vuln
+	}
+
+	vulns := []models.Vulnerability{
+		scoped("GHSA-applies", ">=18 <18.18.0"),
+		scoped("GHSA-does-not-apply", ">=20"),
+		scoped("GHSA-unscoped", ""),
+		scoped("GHSA-partially-scoped", ">=20", ""),
+		scoped("GHSA-unparsable", "not a constraint"),
+	}
+
+	results := models.VulnerabilityResults{
+		Results: []models.PackageSource{{
+			Source: models.SourceInfo{Path: filepath.Join(dir, "package-lock.json"), Type: "lockfile"},
+			Packages: []models.PackageVulns{{
+				Package:         models.PackageInfo{Name: "undici", Version: "5.0.0", Ecosystem: "npm"},
+				Vulnerabilities: vulns,
+				Groups: []models.GroupInfo{
+					{IDs: []string{"GHSA-applies"}},
+					{IDs: []string{"GHSA-does-not-apply"}},
+					{IDs: []string{"GHSA-unscoped"}},
+					{IDs: []string{"GHSA-partially-scoped"}},
+					{IDs: []string{"GHSA-unparsable"}},
+				},
+			}},
+		}},
+	}
+
+	if got := filterNodeVersionVulns(&reporter.VoidReporter{}, &results, false); got != 1 {
+		t.Errorf("filterNodeVersionVulns() = %d, want 1", got)
+	}
+
+	var ids []string
+	for _, vuln := range results.Results[0].Packages[0].Vulnerabilities {
+		ids = append(ids, vuln.ID)
+	}
+
+	want := []string{"GHSA-applies", "GHSA-unscoped", "GHSA-partially-scoped", "GHSA-unparsable"}
+	if diff := cmp.Diff(want, ids); diff != "" {
+		t.Errorf("filterNodeVersionVulns() mismatch (-want +got):\n%s", diff)
+	}
+}
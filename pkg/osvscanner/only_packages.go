@@ -0,0 +1,143 @@
+package osvscanner
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"deps.dev/util/resolve"
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+// PackageSelector selects the packages of the ecosystem with the name
+type PackageSelector struct {
+	Ecosystem string
+	Name      string
+}
+
+// ParsePackageSelector parses a selector in the form of "ecosystem:name", such as "npm:lodash".
+//
+// Everything after the first colon is the name, so that Maven packages can be selected with
+// "Maven:group:artifact", and the ecosystem must be one of those supported by OSV.
+func ParsePackageSelector(selector string) (PackageSelector, error) {
+	ecosystem, name, ok := strings.Cut(selector, ":")
+	if !ok || ecosystem == "" || name == "" {
+		return PackageSelector{}, fmt.Errorf("%q is not in the form of ecosystem:name", selector)
+	}
+
+	for _, eco := range models.Ecosystems {
+		if strings.EqualFold(string(eco), ecosystem) {
+			return PackageSelector{Ecosystem: string(eco), Name: name}, nil
+		}
+	}
+
+	return PackageSelector{}, fmt.Errorf("%q is not for an ecosystem supported by OSV", selector)
+}
+
+// Matches reports if the package is selected, ignoring the release of the ecosystem if any
+// so that "Debian:curl" selects curl regardless of the version of Debian it is for
+func (s PackageSelector) Matches(ecosystem, name string) bool {
+	base, _, _ := strings.Cut(ecosystem, ":")
+
+	return strings.EqualFold(base, s.Ecosystem) && strings.EqualFold(name, s.Name)
+}
+
+// packageNameAndEcosystem returns the name and ecosystem of the package, which packages
+// from SBOMs only have as part of their package URL
+func packageNameAndEcosystem(pkg scannedPackage) (string, string) {
+	if pkg.Name == "" && pkg.PURL != "" {
+		if info, err := models.PURLToPackage(pkg.PURL); err == nil {
+			return info.Name, info.Ecosystem
+		}
+	}
+
+	return pkg.Name, string(pkg.Ecosystem)
+}
+
+// transitiveDependencies returns the "name@version" of every package that the
+// packages with the keys depend on, directly or indirectly, including themselves
+func transitiveDependencies(graph *resolve.Graph, keys map[string]bool) map[string]bool {
+	var queue []resolve.NodeID
+	for i, node := range graph.Nodes {
+		if keys[node.Version.Name+"@"+node.Version.Version] {
+			queue = append(queue, resolve.NodeID(i))
+		}
+	}
+
+	dependencies := make(map[resolve.NodeID][]resolve.NodeID, len(graph.Nodes))
+	for _, edge := range graph.Edges {
+		dependencies[edge.From] = append(dependencies[edge.From], edge.To)
+	}
+
+	seen := make(map[resolve.NodeID]bool, len(queue))
+	closure := make(map[string]bool)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		node := graph.Nodes[id]
+		closure[node.Version.Name+"@"+node.Version.Version] = true
+
+		queue = append(queue, dependencies[id]...)
+	}
+
+	return closure
+}
+
+// filterOnlyPackages removes the packages that are not selected by any of the selectors,
+// keeping the packages that the selected packages depend on if that is known from the
+// lockfile they were found in.
+func filterOnlyPackages(r reporter.Reporter, packages []scannedPackage, selectors []PackageSelector) []scannedPackage {
+	selected := make([]bool, len(packages))
+	// the packages that were selected from each source, in the form of "name@version"
+	selectedBySource := map[string]map[string]bool{}
+
+	for i, pkg := range packages {
+		name, ecosystem := packageNameAndEcosystem(pkg)
+		if !slices.ContainsFunc(selectors, func(s PackageSelector) bool { return s.Matches(ecosystem, name) }) {
+			continue
+		}
+
+		selected[i] = true
+		if pkg.Source.Type == "lockfile" {
+			if selectedBySource[pkg.Source.Path] == nil {
+				selectedBySource[pkg.Source.Path] = map[string]bool{}
+			}
+			selectedBySource[pkg.Source.Path][pkg.Name+"@"+pkg.Version] = true
+		}
+	}
+
+	for path, keys := range selectedBySource {
+		graph := readDependencyGraph(r, path)
+		if graph == nil {
+			continue
+		}
+
+		closure := transitiveDependencies(graph, keys)
+		for i, pkg := range packages {
+			if pkg.Source.Path == path && pkg.Source.Type == "lockfile" && closure[pkg.Name+"@"+pkg.Version] {
+				selected[i] = true
+			}
+		}
+	}
+
+	out := make([]scannedPackage, 0, len(packages))
+	for i, pkg := range packages {
+		if selected[i] {
+			out = append(out, pkg)
+		}
+	}
+
+	if skipped := len(packages) - len(out); skipped > 0 {
+		r.Infof("Skipped %d %s not selected by --only-packages.\n", skipped, output.Form(skipped, "package", "packages"))
+	}
+
+	return out
+}
@@ -0,0 +1,72 @@
+package osvscanner
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+func TestParsePackageSelector(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		selector string
+		want     PackageSelector
+		wantErr  bool
+	}{
+		{selector: "npm:lodash", want: PackageSelector{Ecosystem: "npm", Name: "lodash"}},
+		{selector: "pypi:Django", want: PackageSelector{Ecosystem: "PyPI", Name: "Django"}},
+		{selector: "Maven:org.apache.logging.log4j:log4j-core", want: PackageSelector{Ecosystem: "Maven", Name: "org.apache.logging.log4j:log4j-core"}},
+		{selector: "lodash", wantErr: true},
+		{selector: "npm:", wantErr: true},
+		{selector: ":lodash", wantErr: true},
+		{selector: "not-an-ecosystem:lodash", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.selector, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParsePackageSelector(tt.selector)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePackageSelector() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParsePackageSelector() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_filterOnlyPackages(t *testing.T) {
+	t.Parallel()
+
+	lockfileSource := models.SourceInfo{Path: filepath.FromSlash("fixtures/paths/package-lock.json"), Type: "lockfile"}
+	sbomSource := models.SourceInfo{Path: "bom.json", Type: "sbom"}
+
+	packages := []scannedPackage{
+		{Name: "body-parser", Version: "1.19.0", Ecosystem: lockfile.NpmEcosystem, Source: lockfileSource},
+		{Name: "express", Version: "4.17.1", Ecosystem: lockfile.NpmEcosystem, Source: lockfileSource},
+		{Name: "lodash", Version: "4.17.20", Ecosystem: lockfile.NpmEcosystem, Source: lockfileSource},
+		{Name: "qs", Version: "6.7.0", Ecosystem: lockfile.NpmEcosystem, Source: lockfileSource},
+		{PURL: "pkg:deb/debian/curl@7.74.0?distro=debian-11", Source: sbomSource},
+		{PURL: "pkg:deb/debian/openssl@1.1.1n?distro=debian-11", Source: sbomSource},
+		{Commit: "9a6bd55c9d0722cb101fe85a3b22d89e4ff4fe52", Source: models.SourceInfo{Path: "/repo", Type: "git"}},
+	}
+
+	got := filterOnlyPackages(&reporter.VoidReporter{}, packages, []PackageSelector{
+		{Ecosystem: "npm", Name: "express"},
+		{Ecosystem: "Debian", Name: "curl"},
+	})
+
+	// qs is kept as express depends on it
+	want := []scannedPackage{packages[1], packages[3], packages[4]}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("filterOnlyPackages() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -2,20 +2,27 @@ package osvscanner
 
 import (
 	"bufio"
+	"context"
 	"crypto/md5" //nolint:gosec
 	"errors"
 	"fmt"
 	"io/fs"
+	"net/http"
 	"os"
 	"os/exec"
-	"path"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/google/osv-scanner/internal/ci"
 	"github.com/google/osv-scanner/internal/customgitignore"
 	"github.com/google/osv-scanner/internal/image"
 	"github.com/google/osv-scanner/internal/local"
 	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/internal/pep508"
+	"github.com/google/osv-scanner/internal/resolution/datasource"
 	"github.com/google/osv-scanner/internal/sbom"
 	"github.com/google/osv-scanner/internal/semantic"
 	"github.com/google/osv-scanner/internal/version"
@@ -34,6 +41,7 @@ import (
 type ScannerActions struct {
 	LockfilePaths        []string
 	SBOMPaths            []string
+	DockerfilePaths      []string
 	DirectoryPaths       []string
 	GitCommits           []string
 	Recursive            bool
@@ -42,6 +50,76 @@ type ScannerActions struct {
 	DockerContainerNames []string
 	ConfigOverridePath   string
 	CallAnalysisStates   map[string]bool
+	// Since excludes vulnerabilities that were last published or modified before this time, if set
+	Since time.Time
+	// GitRef scans the lockfiles in DirectoryPaths as they exist in their git repository at this ref, if set
+	GitRef string
+	// NoCache disables reusing the results of previous OSV queries for packages
+	// whose ecosystem's database has not been updated since
+	NoCache bool
+	// ManifestOnly scans the manifests in DirectoryPaths instead of their lockfiles,
+	// resolving each requirement to the latest version that satisfies it
+	ManifestOnly bool
+	// StrictResolve stops the scan if any requirement of a manifest cannot be resolved,
+	// instead of skipping it with a warning
+	StrictResolve bool
+	// NoProgress disables showing the progress of long-running operations,
+	// which is otherwise shown if the reporter supports it
+	NoProgress bool
+	// ExcludeDev excludes vulnerabilities in packages that are only development dependencies
+	ExcludeDev bool
+	// PURLPaths are files with a package URL on each line, whose packages are
+	// queried for directly rather than being found by parsing any files
+	PURLPaths []string
+	// ShowPaths records the paths from a direct dependency down to each package with
+	// vulnerabilities, for lockfiles that record which packages depend on which
+	ShowPaths bool
+	// MavenRegistries are the registries that Maven parent POMs and BOMs are fetched from
+	// when resolving manifests, in the order they are tried, instead of Maven Central
+	MavenRegistries []string
+	// OnlyPackages limits the scan to the packages selected by these "ecosystem:name" selectors,
+	// and the packages that they depend on if that is known
+	OnlyPackages []string
+	// ChangedFilesPath is a file listing the files that have changed, one per line such as from
+	// "git diff --name-only", or "-" for stdin; if set, only the lockfiles and manifests in
+	// DirectoryPaths that are listed are scanned
+	ChangedFilesPath string
+	// CVSSVersion is the CVSS version ("2", "3" or "4") whose scores are used for the severity of
+	// vulnerabilities that have scores for several versions, instead of the highest of all of them
+	CVSSVersion string
+	// SeverityMapping are "rating:minimum" thresholds, such as "High:7", that scores are rated by
+	// instead of the qualitative ratings defined by CVSS
+	SeverityMapping []string
+	// CacheDir is the directory that the query cache, local databases and resolution caches
+	// are stored in, defaulting to the osv-scanner directory within the user cache directory
+	CacheDir string
+	// ReportFixedVersions adds the versions that fix each vulnerability to the results
+	ReportFixedVersions bool
+	// RepositoryPaths are the roots of repositories, which are scanned like DirectoryPaths
+	// with the source of each package being attributed to the repository that it is in
+	RepositoryPaths []string
+	// RepositoriesFilePath is a file listing more RepositoryPaths, one per line
+	RepositoriesFilePath string
+	// ScopeNodeVersion removes the vulnerabilities of npm packages that are scoped to versions of
+	// Node other than the one pinned by the .nvmrc or engines.node of the package.json of the project
+	ScopeNodeVersion bool
+	// IncludePaths are globs of the paths within each directory that are scanned, relative to it
+	IncludePaths []string
+	// ExcludePaths are globs of the paths within each directory that are not scanned,
+	// taking precedence over IncludePaths
+	ExcludePaths []string
+	// PackageCoordinates are versions of packages to check without a manifest,
+	// in the form of "ecosystem:name@version" as parsed by ParsePackageCoordinate
+	PackageCoordinates []string
+	// EnvConfig is config from environment variables as loaded by config.LoadEnv,
+	// which takes precedence over any config files
+	EnvConfig config.Config
+	// BaselinePath is a file of known findings recorded by a previous scan, which are suppressed
+	// so that only new findings are reported; it is created with the findings of the scan if it does not exist
+	BaselinePath string
+	// VEXPaths are OpenVEX documents whose statements that packages are not affected by
+	// or have fixed vulnerabilities suppress those vulnerabilities, along with the VEXFiles of the config
+	VEXPaths []string
 
 	ExperimentalScannerActions
 }
@@ -55,6 +133,47 @@ type ExperimentalScannerActions struct {
 	ScanOCIImage          string
 
 	LocalDBPath string
+	// QueryCacheTTL is how long the results of querying OSV are used for after being cached,
+	// even if the database of their ecosystem has been updated since, with zero meaning
+	// they are only used until it is updated
+	QueryCacheTTL time.Duration
+	// LocalDBMirrorURL overrides where local databases are downloaded from
+	LocalDBMirrorURL string
+	// LocalDBMirrorHeader is sent when downloading local databases, in the form of "Name: value"
+	LocalDBMirrorHeader string
+	// LocalDBSignatureKey is the path to a PEM encoded public key that local databases must be signed by,
+	// with the signature of each being next to it on the db host as made by "cosign sign-blob --key".
+	// Keyless signatures and Sigstore bundles are not supported.
+	LocalDBSignatureKey string
+	// DownloadAllEcosystems downloads the local database of every ecosystem rather than only
+	// those of the packages being scanned, which requires comparing locally while online
+	DownloadAllEcosystems bool
+	// AdvisorySources are where else local databases are downloaded from, in the form of
+	// [<kind>:]<url> with the kind being either "osv" (the default) or "ghsa"
+	AdvisorySources []string
+
+	// ExcludeInactivePythonPackages skips Python packages whose environment markers
+	// are not satisfied by the Python interpreter on the PATH
+	ExcludeInactivePythonPackages bool
+
+	// CheckYanked asks the registries of the packages whether their versions have been yanked
+	CheckYanked bool
+
+	// FailOnUnknownLicense fails the scan with ErrUnknownLicensesFound if the license
+	// of any package could not be determined, which requires licenses to be scanned
+	FailOnUnknownLicense bool
+
+	// WarnOnLicenseViolations reports license violations as a warning instead of failing the
+	// scan with ErrLicenseViolationsFound, for when licenses are only being reported on
+	WarnOnLicenseViolations bool
+
+	// FailOnSeverity is the rating of the SeverityMapping, such as "high", that the severity of a vulnerability has
+	// to be at least for it to fail the scan with VulnerabilitiesFoundErr, which any vulnerability does if empty
+	FailOnSeverity string
+
+	// PreviousResults are the results of a previous scan to compare against, so that only the findings
+	// that are not in them are returned, failing the scan with ErrNewVulnerabilitiesFound if any of those would fail it
+	PreviousResults *models.VulnerabilityResults
 }
 
 // NoPackagesFoundErr for when no packages are found during a scan.
@@ -68,13 +187,48 @@ var NoPackagesFoundErr = errors.New("no packages found in scan")
 //nolint:errname,stylecheck // Would require version major bump to change
 var VulnerabilitiesFoundErr = errors.New("vulnerabilities found")
 
+// ErrLicenseViolationsFound is for when license violations are found without any vulnerabilities.
+//
+// For backwards compatibility, this error wraps VulnerabilitiesFoundErr.
+var ErrLicenseViolationsFound = fmt.Errorf("%w: license violations found", VulnerabilitiesFoundErr)
+
+// ErrVulnerabilitiesAndLicenseViolationsFound is for when both vulnerabilities and license
+// violations are found, so that they can be told apart from only one or the other.
+//
+// For backwards compatibility, this error wraps VulnerabilitiesFoundErr.
+var ErrVulnerabilitiesAndLicenseViolationsFound = fmt.Errorf("%w: and license violations found", VulnerabilitiesFoundErr)
+
+// ErrNewVulnerabilitiesFound is for when results are compared against previous results,
+// and there are vulnerabilities that are not in the previous results.
+//
+// For backwards compatibility, this error wraps VulnerabilitiesFoundErr.
+var ErrNewVulnerabilitiesFound = fmt.Errorf("%w: new vulnerabilities found", VulnerabilitiesFoundErr)
+
+// ErrUnknownLicensesFound is for when, with FailOnUnknownLicense, the license of a package
+// could not be determined and there are neither vulnerabilities nor license violations.
+//
+// For backwards compatibility, this error wraps VulnerabilitiesFoundErr.
+var ErrUnknownLicensesFound = fmt.Errorf("%w: packages with unknown licenses found", VulnerabilitiesFoundErr)
+
 // Deprecated: This error is no longer returned, check the results to determine if this is the case
 //
 //nolint:errname,stylecheck // Would require version bump to change
 var OnlyUncalledVulnerabilitiesFoundErr = errors.New("only uncalled vulnerabilities found")
 
 // ErrAPIFailed describes errors related to querying API endpoints.
-var ErrAPIFailed = errors.New("API query failed")
+var ErrAPIFailed = datasource.ErrAPIFailed
+
+// ErrTimedOut is for when the deadline of the context given to DoScanWithContext is exceeded.
+//
+// Any results that were found before then are returned alongside this error,
+// such as the vulnerabilities that had already been fetched.
+var ErrTimedOut = errors.New("scan timed out")
+
+// ErrInterrupted is for when the context given to DoScanWithContext is cancelled,
+// such as when the scan is interrupted by a signal.
+//
+// Like with ErrTimedOut, any results that were found before then are returned alongside this error.
+var ErrInterrupted = errors.New("scan interrupted")
 
 var (
 	vendoredLibNames = map[string]struct{}{
@@ -103,7 +257,15 @@ const (
 //   - Any lockfiles with scanLockfile
 //   - Any SBOM files with scanSBOMFile
 //   - Any git repositories with scanGit
-func scanDir(r reporter.Reporter, dir string, skipGit bool, recursive bool, useGitIgnore bool, compareOffline bool) ([]scannedPackage, error) {
+//
+// If manifestOnly is set, any manifests are scanned with scanManifest instead of lockfiles and SBOMs,
+// with the scan being stopped if a manifest cannot be completely resolved when strictResolve is set
+func scanDir(ctx context.Context, r reporter.Reporter, dir string, skipGit bool, recursive bool, useGitIgnore bool, compareOffline bool, manifestOnly bool, dryRun bool, changed changedFiles, strictResolve bool, showProgress bool, mavenRegistries []string, configManager *config.ConfigManager, clients *manifestClients, filters pathFilters) ([]scannedPackage, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
 	var ignoreMatcher *gitIgnoreMatcher
 	if useGitIgnore {
 		var err error
@@ -118,18 +280,36 @@ func scanDir(r reporter.Reporter, dir string, skipGit bool, recursive bool, useG
 
 	var scannedPackages []scannedPackage
 
-	return scannedPackages, filepath.WalkDir(dir, func(path string, info os.DirEntry, err error) error {
+	// how many files have been searched is shown as the walk goes, as a large
+	// directory can take long enough to look like the scan has hung otherwise
+	searched := 0
+	progressFormat := "Searched %d files in " + strings.ReplaceAll(dir, "%", "%%")
+
+	err = filepath.WalkDir(dir, func(path string, info os.DirEntry, err error) error {
 		if err != nil {
 			r.Infof("Failed to walk %s: %v\n", path, err)
 			return err
 		}
 
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		path, err = filepath.Abs(path)
 		if err != nil {
 			r.Errorf("Failed to walk path %s\n", err)
 			return err
 		}
 
+		// the filters are checked first so that nothing is read from the files that they skip
+		rel, _ := filepath.Rel(absDir, path)
+		if info.IsDir() && filters.skipsDir(rel) {
+			return filepath.SkipDir
+		}
+		if !info.IsDir() && !filters.includes(rel) {
+			return nil
+		}
+
 		if useGitIgnore {
 			match, err := ignoreMatcher.match(path, info.IsDir())
 			if err != nil {
@@ -147,7 +327,18 @@ func scanDir(r reporter.Reporter, dir string, skipGit bool, recursive bool, useG
 			}
 		}
 
-		if !skipGit && info.IsDir() && info.Name() == ".git" {
+		if !info.IsDir() && !changed.includes(path) {
+			return nil
+		}
+
+		if !info.IsDir() {
+			searched++
+			if showProgress && searched%filesPerProgressUpdate == 0 {
+				reporter.Progress(r, searched, reporter.UnknownTotal, progressFormat)
+			}
+		}
+
+		if !skipGit && info.IsDir() && info.Name() == ".git" && filters.includes(rel) {
 			pkgs, err := scanGit(r, filepath.Dir(path)+"/")
 			if err != nil {
 				r.Infof("scan failed for git repository, %s: %v\n", path, err)
@@ -158,7 +349,20 @@ func scanDir(r reporter.Reporter, dir string, skipGit bool, recursive bool, useG
 			return filepath.SkipDir
 		}
 
-		if !info.IsDir() {
+		if !info.IsDir() && manifestOnly {
+			if isResolvableManifest(path) && dryRun {
+				scannedPackages = append(scannedPackages, manifestScanTarget(path))
+			} else if isResolvableManifest(path) {
+				pkgs, err := scanManifest(ctx, r, path, showProgress, strictResolve, mavenRegistries, configManager, clients)
+				if err != nil && (strictResolve || ctx.Err() != nil) {
+					return fmt.Errorf("failed to resolve manifest %s: %w", path, err)
+				}
+				if err != nil {
+					r.Errorf("Attempted to resolve manifest but failed: %s: %v\n", path, err)
+				}
+				scannedPackages = append(scannedPackages, pkgs...)
+			}
+		} else if !info.IsDir() {
 			if extractor, _ := lockfile.FindExtractor(path, ""); extractor != nil {
 				pkgs, err := scanLockfile(r, path, "")
 				if err != nil {
@@ -173,7 +377,9 @@ func scanDir(r reporter.Reporter, dir string, skipGit bool, recursive bool, useG
 			scannedPackages = append(scannedPackages, pkgs...)
 		}
 
-		if info.IsDir() && !compareOffline {
+		// vendored libraries are identified by their contents rather than any one file,
+		// so they cannot be limited to those that have changed
+		if info.IsDir() && !compareOffline && !dryRun && changed == nil && filters.includes(rel) {
 			if _, ok := vendoredLibNames[strings.ToLower(filepath.Base(path))]; ok {
 				pkgs, err := scanDirWithVendoredLibs(r, path)
 				if err != nil {
@@ -190,8 +396,18 @@ func scanDir(r reporter.Reporter, dir string, skipGit bool, recursive bool, useG
 
 		return nil
 	})
+
+	if showProgress {
+		reporter.Progress(r, searched, searched, progressFormat)
+	}
+
+	return scannedPackages, err
 }
 
+// filesPerProgressUpdate is how many files are searched between each update
+// of the progress of walking a directory
+const filesPerProgressUpdate = 100
+
 type gitIgnoreMatcher struct {
 	matcher  gitignore.Matcher
 	repoPath string
@@ -373,6 +589,15 @@ func scanLockfile(r reporter.Reporter, path string, parseAs string) ([]scannedPa
 		parsedAsComment = fmt.Sprintf("as a %s ", parseAs)
 	}
 
+	if !lockfile.SupportsSuppressions(parsedLockfile.ParsedAs) {
+		if b, err := os.ReadFile(path); err == nil && lockfile.ContainsSuppressionDirective(b) {
+			r.Warnf(
+				"Warning: %s contains inline suppressions, which are only supported in go.mod, gradle.lockfile and requirements.txt files, so they will not be applied\n",
+				path,
+			)
+		}
+	}
+
 	r.Infof(
 		"Scanned %s file %sand found %d %s\n",
 		path,
@@ -381,22 +606,30 @@ func scanLockfile(r reporter.Reporter, path string, parseAs string) ([]scannedPa
 		output.Form(len(parsedLockfile.Packages), "package", "packages"),
 	)
 
-	packages := make([]scannedPackage, len(parsedLockfile.Packages))
-	for i, pkgDetail := range parsedLockfile.Packages {
+	return newScannedPackages(parsedLockfile.Packages, models.SourceInfo{
+		Path: path,
+		Type: "lockfile",
+	}), nil
+}
+
+// newScannedPackages converts the packages extracted from a lockfile into scannedPackages from the given source
+func newScannedPackages(pkgDetails []lockfile.PackageDetails, source models.SourceInfo) []scannedPackage {
+	packages := make([]scannedPackage, len(pkgDetails))
+	for i, pkgDetail := range pkgDetails {
 		packages[i] = scannedPackage{
-			Name:      pkgDetail.Name,
-			Version:   pkgDetail.Version,
-			Commit:    pkgDetail.Commit,
-			Ecosystem: pkgDetail.Ecosystem,
-			DepGroups: pkgDetail.DepGroups,
-			Source: models.SourceInfo{
-				Path: path,
-				Type: "lockfile",
-			},
+			Name:         pkgDetail.Name,
+			Version:      pkgDetail.Version,
+			Commit:       pkgDetail.Commit,
+			Ecosystem:    pkgDetail.Ecosystem,
+			DepGroups:    pkgDetail.DepGroups,
+			Suppressions: pkgDetail.Suppressions,
+			Marker:       pkgDetail.Marker,
+			Origin:       pkgDetail.Origin,
+			Source:       source,
 		}
 	}
 
-	return packages, nil
+	return packages
 }
 
 // scanSBOMFile will load, identify, and parse the SBOM path passed in, and add the dependencies specified
@@ -504,30 +737,6 @@ func getCommitSHA(repoDir string) (string, error) {
 	return head.Hash().String(), nil
 }
 
-func getSubmodules(repoDir string) (submodules []*git.SubmoduleStatus, err error) {
-	repo, err := git.PlainOpen(repoDir)
-	if err != nil {
-		return nil, err
-	}
-	worktree, err := repo.Worktree()
-	if err != nil {
-		return nil, err
-	}
-	ss, err := worktree.Submodules()
-	if err != nil {
-		return nil, err
-	}
-	for _, s := range ss {
-		status, err := s.Status()
-		if err != nil {
-			continue
-		}
-		submodules = append(submodules, status)
-	}
-
-	return submodules, nil
-}
-
 // Scan git repository. Expects repoDir to end with /
 func scanGit(r reporter.Reporter, repoDir string) ([]scannedPackage, error) {
 	commit, err := getCommitSHA(repoDir)
@@ -546,8 +755,8 @@ func scanGit(r reporter.Reporter, repoDir string) ([]scannedPackage, error) {
 	}
 
 	for _, s := range submodules {
-		r.Infof("Scanning submodule %s at commit %s\n", s.Path, s.Expected.String())
-		packages = append(packages, createCommitQueryPackage(s.Expected.String(), path.Join(repoDir, s.Path)))
+		r.Infof("Scanning submodule %s at commit %s\n", s.Path, s.Commit)
+		packages = append(packages, createSubmoduleQueryPackage(s, repoDir))
 	}
 
 	return packages, nil
@@ -618,11 +827,12 @@ func filterResults(r reporter.Reporter, results *models.VulnerabilityResults, co
 	newResults := []models.PackageSource{} // Want 0 vulnerabilities to show in JSON as an empty list, not null.
 	for _, pkgSrc := range results.Results {
 		configToUse := configManager.Get(r, pkgSrc.Source.Path)
+		ignoreEntries := configManager.GetIgnoreEntries(r, pkgSrc.Source.Path)
 		var newPackages []models.PackageVulns
 		for _, pkgVulns := range pkgSrc.Packages {
-			newVulns := filterPackageVulns(r, pkgVulns, configToUse)
+			newVulns := filterPackageVulns(r, pkgVulns, configToUse, ignoreEntries)
 			removedCount += len(pkgVulns.Vulnerabilities) - len(newVulns.Vulnerabilities)
-			if allPackages || len(newVulns.Vulnerabilities) > 0 || len(pkgVulns.LicenseViolations) > 0 {
+			if allPackages || len(newVulns.Vulnerabilities) > 0 || len(pkgVulns.LicenseViolations) > 0 || len(pkgVulns.Suppressed) > 0 {
 				newPackages = append(newPackages, newVulns)
 			}
 		}
@@ -637,8 +847,8 @@ func filterResults(r reporter.Reporter, results *models.VulnerabilityResults, co
 	return removedCount
 }
 
-// Filters package-grouped vulnerabilities according to config, preserving ordering. Returns filtered package vulnerabilities.
-func filterPackageVulns(r reporter.Reporter, pkgVulns models.PackageVulns, configToUse config.Config) models.PackageVulns {
+// Filters package-grouped vulnerabilities according to config and any ignore files, preserving ordering. Returns filtered package vulnerabilities.
+func filterPackageVulns(r reporter.Reporter, pkgVulns models.PackageVulns, configToUse config.Config, ignoreEntries config.IgnoreFileEntries) models.PackageVulns {
 	ignoredVulns := map[string]struct{}{}
 	// Iterate over groups first to remove all aliases of ignored vulnerabilities.
 	var newGroups []models.GroupInfo
@@ -646,7 +856,10 @@ func filterPackageVulns(r reporter.Reporter, pkgVulns models.PackageVulns, confi
 		ignore := false
 		for _, id := range group.Aliases {
 			var ignoreLine config.IgnoreEntry
-			if ignore, ignoreLine = configToUse.ShouldIgnore(id); ignore {
+			if ignore, ignoreLine = configToUse.ShouldIgnore(id); !ignore {
+				ignore, ignoreLine = ignoreEntries.ShouldIgnore(pkgVulns.Package.Name, id)
+			}
+			if ignore {
 				for _, id := range group.Aliases {
 					ignoredVulns[id] = struct{}{}
 				}
@@ -731,10 +944,50 @@ type scannedPackage struct {
 	Version   string
 	Source    models.SourceInfo
 	DepGroups []string
+	// Suppressions are vulnerabilities that have been ignored inline in the source
+	Suppressions []lockfile.Suppression
+	// Marker is the PEP 508 environment marker that the package is gated by, if any
+	Marker string
+	// Origin is where the package is from if it is not the registry of its ecosystem
+	Origin string
+}
+
+// timeoutErr wraps err with ErrTimedOut if it happened because the deadline of ctx was exceeded,
+// or with ErrInterrupted if it happened because ctx was cancelled
+func timeoutErr(ctx context.Context, err error) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %w", ErrTimedOut, err)
+	}
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return fmt.Errorf("%w: %w", ErrInterrupted, err)
+	}
+
+	return err
 }
 
 // Perform osv scanner action, with optional reporter to output information
+//
+// The returned models.VulnerabilityResults is the complete result of the scan,
+// containing every package source along with its packages, licenses and
+// matched vulnerabilities, so it can be processed programmatically before being
+// passed to a reporter.Reporter (if at all). Packages without any findings are
+// only included when ShowAllPackages is set.
+//
+// The reporter is only used for runtime diagnostics, and may be nil.
+//
+// The results are also returned alongside VulnerabilitiesFoundErr, so callers
+// should check for that error with errors.Is rather than discarding the results.
 func DoScan(actions ScannerActions, r reporter.Reporter) (models.VulnerabilityResults, error) {
+	return DoScanWithContext(context.Background(), actions, r)
+}
+
+// DoScanWithContext is like DoScan, but stops the scan once ctx is done.
+//
+// If the deadline of ctx is exceeded, ErrTimedOut is returned alongside whatever results
+// were found before then, which will not include any vulnerabilities if the deadline was
+// exceeded before they started being fetched. Likewise, ErrInterrupted is returned if ctx
+// is cancelled. Either way, the results are marked as Incomplete.
+func DoScanWithContext(ctx context.Context, actions ScannerActions, r reporter.Reporter) (models.VulnerabilityResults, error) {
 	if r == nil {
 		r = &reporter.VoidReporter{}
 	}
@@ -746,19 +999,45 @@ func DoScan(actions ScannerActions, r reporter.Reporter) (models.VulnerabilityRe
 	if actions.CompareLocally {
 		actions.SkipGit = true
 
+		if actions.ManifestOnly {
+			return models.VulnerabilityResults{}, errors.New("cannot resolve manifests locally")
+		}
+
 		if len(actions.ScanLicensesAllowlist) > 0 || actions.ScanLicensesSummary {
 			return models.VulnerabilityResults{}, errors.New("cannot retrieve licenses locally")
 		}
 	}
 
+	if actions.DownloadAllEcosystems {
+		if actions.CompareOffline {
+			return models.VulnerabilityResults{}, errors.New("cannot download databases while offline")
+		}
+
+		if !actions.CompareLocally {
+			return models.VulnerabilityResults{}, errors.New("cannot download databases without comparing locally")
+		}
+	}
+
+	advisorySources := make([]local.AdvisorySource, 0, len(actions.AdvisorySources))
+	for _, s := range actions.AdvisorySources {
+		source, err := local.ParseAdvisorySource(s)
+		if err != nil {
+			return models.VulnerabilityResults{}, err
+		}
+
+		advisorySources = append(advisorySources, source)
+	}
+
+	if len(advisorySources) > 0 && !actions.CompareLocally {
+		return models.VulnerabilityResults{}, errors.New("cannot use advisory sources without comparing locally")
+	}
+
 	configManager := config.ConfigManager{
 		DefaultConfig: config.Config{},
 		ConfigMap:     make(map[string]config.Config),
+		EnvConfig:     actions.EnvConfig,
 	}
 
-	//nolint:prealloc // Not sure how many there will be in advance.
-	var scannedPackages []scannedPackage
-
 	if actions.ConfigOverridePath != "" {
 		err := configManager.UseOverride(actions.ConfigOverridePath)
 		if err != nil {
@@ -767,11 +1046,308 @@ func DoScan(actions ScannerActions, r reporter.Reporter) (models.VulnerabilityRe
 		}
 	}
 
+	preferredCVSS, severityMapping, err := parseSeverityOptions(actions)
+	if err != nil {
+		return models.VulnerabilityResults{}, err
+	}
+
+	failOnScore, err := parseFailOnSeverity(actions, severityMapping)
+	if err != nil {
+		return models.VulnerabilityResults{}, err
+	}
+
+	scannedPackages, err := findPackages(ctx, r, actions, &configManager, false)
+	if err != nil {
+		return models.VulnerabilityResults{Incomplete: ctx.Err() != nil}, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return models.VulnerabilityResults{Incomplete: true}, timeoutErr(ctx, err)
+	}
+
+	if len(actions.OnlyPackages) > 0 {
+		selectors := make([]PackageSelector, 0, len(actions.OnlyPackages))
+		for _, selector := range actions.OnlyPackages {
+			s, err := ParsePackageSelector(selector)
+			if err != nil {
+				return models.VulnerabilityResults{}, err
+			}
+			selectors = append(selectors, s)
+		}
+
+		scannedPackages = filterOnlyPackages(r, scannedPackages, selectors)
+	}
+
+	if len(scannedPackages) == 0 {
+		return models.VulnerabilityResults{}, NoPackagesFoundErr
+	}
+
+	filteredScannedPackages := filterUnscannablePackages(scannedPackages)
+	filteredScannedPackages = filterUnregisteredPackages(r, filteredScannedPackages)
+
+	if len(filteredScannedPackages) != len(scannedPackages) {
+		r.Infof("Filtered %d local package/s from the scan.\n", len(scannedPackages)-len(filteredScannedPackages))
+	}
+
+	if actions.ExcludeInactivePythonPackages {
+		var err error
+		filteredScannedPackages, err = filterInactivePythonPackages(r, filteredScannedPackages)
+		if err != nil {
+			return models.VulnerabilityResults{}, err
+		}
+	}
+
+	overrideGoVersion(r, filteredScannedPackages, &configManager)
+	remapEcosystems(r, filteredScannedPackages, &configManager)
+
+	var rateLimitedOnce sync.Once
+	ctx = osv.WithOnRateLimited(ctx, func(retryAfter time.Duration) {
+		rateLimitedOnce.Do(func() {
+			if retryAfter > 0 {
+				r.Warnf("Requests are being rate limited by osv.dev, retrying after %s with fewer requests at once. Use --rate-limit to pace requests.\n", retryAfter)
+			} else {
+				r.Warnf("Requests are being rate limited by osv.dev, retrying with fewer requests at once. Use --rate-limit to pace requests.\n")
+			}
+		})
+	})
+
+	if actions.DownloadAllEcosystems {
+		if err := local.DownloadDatabases(ctx, r, actions.LocalDBPath, actions.CacheDir, local.Mirror{
+			URL:          actions.LocalDBMirrorURL,
+			Header:       actions.LocalDBMirrorHeader,
+			SignatureKey: actions.LocalDBSignatureKey,
+			Sources:      advisorySources,
+		}, !actions.NoProgress); err != nil {
+			return models.VulnerabilityResults{}, timeoutErr(ctx, err)
+		}
+	}
+
+	// the vulnerabilities that were fetched before the deadline are still reported if the scan
+	// times out or is interrupted
+	var timedOutErr error
+
+	vulnsResp, err := makeRequest(ctx, r, filteredScannedPackages, actions.CompareLocally, actions.CompareOffline, !actions.NoCache, !actions.NoProgress, actions.LocalDBPath, actions.CacheDir, actions.QueryCacheTTL, local.Mirror{
+		URL:          actions.LocalDBMirrorURL,
+		Header:       actions.LocalDBMirrorHeader,
+		SignatureKey: actions.LocalDBSignatureKey,
+		Sources:      advisorySources,
+	})
+	if err != nil {
+		if vulnsResp == nil || ctx.Err() == nil {
+			return models.VulnerabilityResults{Incomplete: ctx.Err() != nil}, timeoutErr(ctx, err)
+		}
+		timedOutErr = timeoutErr(ctx, err)
+	}
+
+	if timedOutErr == nil {
+		reportUnmatchedSubmodules(r, filteredScannedPackages, vulnsResp)
+	}
+
+	var licensesResp [][]models.License
+	if len(actions.ScanLicensesAllowlist) > 0 || actions.ScanLicensesSummary {
+		if timedOutErr == nil {
+			licensesResp, err = makeLicensesRequests(ctx, r, filteredScannedPackages)
+		}
+		if err != nil {
+			if ctx.Err() == nil {
+				return models.VulnerabilityResults{}, err
+			}
+			timedOutErr = timeoutErr(ctx, err)
+		}
+		if licensesResp == nil {
+			licensesResp = make([][]models.License, len(filteredScannedPackages))
+		}
+	}
+
+	var yankedResp []*models.YankedVersion
+	if actions.CheckYanked {
+		switch {
+		case actions.CompareOffline:
+			r.Warnf("Not checking for yanked versions, as registries cannot be queried when offline\n")
+		case timedOutErr == nil:
+			yankedResp, err = makeYankedRequests(ctx, r, filteredScannedPackages)
+			if err != nil {
+				timedOutErr = timeoutErr(ctx, err)
+			}
+		}
+	}
+
+	results := buildVulnerabilityResults(r, filteredScannedPackages, vulnsResp, licensesResp, yankedResp, actions)
+	results.ScannedSources = scannedSources(scannedPackages)
+
+	filtered := filterResults(r, &results, &configManager, actions.ShowAllPackages)
+	if filtered > 0 {
+		r.Infof(
+			"Filtered %d %s from output\n",
+			filtered,
+			output.Form(filtered, "vulnerability", "vulnerabilities"),
+		)
+	}
+
+	suppressed, err := applyVEX(r, &results, &configManager, actions.VEXPaths)
+	if err != nil {
+		return models.VulnerabilityResults{}, err
+	}
+	if suppressed > 0 {
+		r.Infof(
+			"Suppressed %d %s with VEX statements\n",
+			suppressed,
+			output.Form(suppressed, "vulnerability", "vulnerabilities"),
+		)
+	}
+
+	if !actions.Since.IsZero() {
+		filtered := filterVulnsSince(r, &results, actions.Since, actions.ShowAllPackages)
+		if filtered > 0 {
+			r.Infof(
+				"Filtered %d %s published or modified before %s from output\n",
+				filtered,
+				output.Form(filtered, "vulnerability", "vulnerabilities"),
+				actions.Since.Format(time.RFC3339),
+			)
+		}
+	}
+
+	if actions.ExcludeDev {
+		filtered := filterDevVulns(&results, actions.ShowAllPackages)
+		if filtered > 0 {
+			r.Infof(
+				"Filtered %d %s in development dependencies from output\n",
+				filtered,
+				output.Form(filtered, "vulnerability", "vulnerabilities"),
+			)
+		}
+	}
+
+	if actions.ScopeNodeVersion {
+		filtered := filterNodeVersionVulns(r, &results, actions.ShowAllPackages)
+		if filtered > 0 {
+			r.Infof(
+				"Filtered %d %s that do not apply to the pinned Node version from output\n",
+				filtered,
+				output.Form(filtered, "vulnerability", "vulnerabilities"),
+			)
+		}
+	}
+
+	if actions.BaselinePath != "" {
+		baselined, errBaseline := applyBaseline(r, &results, actions.BaselinePath)
+		if errBaseline != nil {
+			return models.VulnerabilityResults{}, errBaseline
+		}
+		if baselined > 0 {
+			r.Infof(
+				"Suppressed %d %s in the baseline %s\n",
+				baselined,
+				output.Form(baselined, "vulnerability", "vulnerabilities"),
+				actions.BaselinePath,
+			)
+		}
+	}
+
+	if actions.ShowPaths {
+		addDependencyPaths(r, &results)
+	}
+
+	applySeverities(&results, preferredCVSS, severityMapping)
+
+	if actions.ReportFixedVersions {
+		addFixedVersions(&results)
+	}
+
+	// only the findings that are not in the previous results are reported and fail the scan,
+	// while license violations still fail it for every package as they are not findings
+	reported := results
+	if actions.PreviousResults != nil {
+		reported = ci.Compare(r, *actions.PreviousResults, results)
+	}
+
+	if timedOutErr != nil {
+		reported.Incomplete = true
+
+		return reported, timedOutErr
+	}
+
+	if len(results.Results) > 0 {
+		// TODO: in the next breaking release of osv-scanner, consider
+		// returning a ScanError instead of an error.
+		return reported, resultErr(r, actions, results, reported, failOnScore)
+	}
+
+	return reported, nil
+}
+
+// resultErr determines the correct error to return for the results of a scan, which is for the
+// vulnerabilities in the reported findings and the license violations of every package in the results
+func resultErr(r reporter.Reporter, actions ScannerActions, results, reported models.VulnerabilityResults, failOnScore float64) error {
+	var vuln bool
+	onlyUncalledVuln := true
+	var licenseViolation bool
+	var unscoredVulns int
+	for _, vf := range reported.Flatten() {
+		if vf.Vulnerability.ID != "" && meetsSeverityThreshold(vf.GroupInfo, failOnScore) {
+			vuln = true
+			if vf.GroupInfo.IsCalled() {
+				onlyUncalledVuln = false
+			}
+		} else if vf.Vulnerability.ID != "" && vf.GroupInfo.MaxSeverity == "" {
+			unscoredVulns++
+		}
+	}
+	for _, vf := range results.Flatten() {
+		if len(vf.LicenseViolations) > 0 {
+			licenseViolation = true
+		}
+	}
+	if unscoredVulns > 0 {
+		r.Warnf(
+			"%d %s no severity score, so %s not fail the scan with --fail-on-severity\n",
+			unscoredVulns,
+			output.Form(unscoredVulns, "vulnerability has", "vulnerabilities have"),
+			output.Form(unscoredVulns, "does", "do"),
+		)
+	}
+	onlyUncalledVuln = onlyUncalledVuln && vuln
+	licenseViolation = licenseViolation && len(actions.ScanLicensesAllowlist) > 0
+	if licenseViolation && actions.WarnOnLicenseViolations {
+		r.Warnf("License violations were found, which are only being reported rather than failing the scan\n")
+		licenseViolation = false
+	}
+	unknownLicense := actions.FailOnUnknownLicense && reportUnknownLicenses(r, &results)
+
+	vulnsFoundErr := VulnerabilitiesFoundErr
+	if actions.PreviousResults != nil {
+		vulnsFoundErr = ErrNewVulnerabilitiesFound
+	}
+
+	switch {
+	case vuln && !onlyUncalledVuln && licenseViolation:
+		return ErrVulnerabilitiesAndLicenseViolationsFound
+	case vuln && !onlyUncalledVuln:
+		return vulnsFoundErr
+	case licenseViolation:
+		return ErrLicenseViolationsFound
+	case unknownLicense:
+		return ErrUnknownLicensesFound
+	default:
+		// There is no error.
+		return nil
+	}
+}
+
+// findPackages finds the packages to scan from everything the actions are set to scan.
+//
+// When dryRun is set, no network requests are made, so any manifests are included without
+// their packages being resolved and docker images without them being run.
+func findPackages(ctx context.Context, r reporter.Reporter, actions ScannerActions, configManager *config.ConfigManager, dryRun bool) ([]scannedPackage, error) {
+	//nolint:prealloc // Not sure how many there will be in advance.
+	var scannedPackages []scannedPackage
+
 	if actions.ExperimentalScannerActions.ScanOCIImage != "" {
 		r.Infof("Scanning image %s\n", actions.ExperimentalScannerActions.ScanOCIImage)
 		pkgs, err := scanImage(r, actions.ExperimentalScannerActions.ScanOCIImage)
 		if err != nil {
-			return models.VulnerabilityResults{}, err
+			return nil, err
 		}
 
 		scannedPackages = append(scannedPackages, pkgs...)
@@ -779,6 +1355,15 @@ func DoScan(actions ScannerActions, r reporter.Reporter) (models.VulnerabilityRe
 
 	// TODO: Deprecated
 	for _, container := range actions.DockerContainerNames {
+		if dryRun {
+			scannedPackages = append(scannedPackages, scannedPackage{
+				Ecosystem: "Debian",
+				Source:    models.SourceInfo{Path: container, Type: "docker"},
+			})
+
+			continue
+		}
+
 		pkgs, _ := scanDebianDocker(r, container)
 		scannedPackages = append(scannedPackages, pkgs...)
 	}
@@ -788,11 +1373,11 @@ func DoScan(actions ScannerActions, r reporter.Reporter) (models.VulnerabilityRe
 		lockfilePath, err := filepath.Abs(lockfilePath)
 		if err != nil {
 			r.Errorf("Failed to resolved path with error %s\n", err)
-			return models.VulnerabilityResults{}, err
+			return nil, err
 		}
 		pkgs, err := scanLockfile(r, lockfilePath, parseAs)
 		if err != nil {
-			return models.VulnerabilityResults{}, err
+			return nil, err
 		}
 		scannedPackages = append(scannedPackages, pkgs...)
 	}
@@ -800,93 +1385,96 @@ func DoScan(actions ScannerActions, r reporter.Reporter) (models.VulnerabilityRe
 	for _, sbomElem := range actions.SBOMPaths {
 		sbomElem, err := filepath.Abs(sbomElem)
 		if err != nil {
-			return models.VulnerabilityResults{}, fmt.Errorf("failed to resolved path with error %w", err)
+			return nil, fmt.Errorf("failed to resolved path with error %w", err)
 		}
 		pkgs, err := scanSBOMFile(r, sbomElem, false)
 		if err != nil {
-			return models.VulnerabilityResults{}, err
+			return nil, err
 		}
 		scannedPackages = append(scannedPackages, pkgs...)
 	}
 
-	for _, commit := range actions.GitCommits {
-		scannedPackages = append(scannedPackages, createCommitQueryPackage(commit, "HASH"))
+	for _, dockerfileElem := range actions.DockerfilePaths {
+		dockerfileElem, err := filepath.Abs(dockerfileElem)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolved path with error %w", err)
+		}
+		pkgs, err := scanDockerfile(r, dockerfileElem)
+		if err != nil {
+			return nil, err
+		}
+		scannedPackages = append(scannedPackages, pkgs...)
 	}
 
-	for _, dir := range actions.DirectoryPaths {
-		r.Infof("Scanning dir %s\n", dir)
-		pkgs, err := scanDir(r, dir, actions.SkipGit, actions.Recursive, !actions.NoIgnore, actions.CompareOffline)
+	for _, purlsElem := range actions.PURLPaths {
+		purlsElem, err := filepath.Abs(purlsElem)
 		if err != nil {
-			return models.VulnerabilityResults{}, err
+			return nil, fmt.Errorf("failed to resolved path with error %w", err)
+		}
+		pkgs, err := scanPURLFile(r, purlsElem)
+		if err != nil {
+			return nil, err
 		}
 		scannedPackages = append(scannedPackages, pkgs...)
 	}
 
-	if len(scannedPackages) == 0 {
-		return models.VulnerabilityResults{}, NoPackagesFoundErr
+	for _, commit := range actions.GitCommits {
+		scannedPackages = append(scannedPackages, createCommitQueryPackage(commit, "HASH"))
 	}
 
-	filteredScannedPackages := filterUnscannablePackages(scannedPackages)
-
-	if len(filteredScannedPackages) != len(scannedPackages) {
-		r.Infof("Filtered %d local package/s from the scan.\n", len(scannedPackages)-len(filteredScannedPackages))
+	if len(actions.PackageCoordinates) > 0 {
+		pkgs, err := scanPackageCoordinates(actions.PackageCoordinates)
+		if err != nil {
+			return nil, err
+		}
+		scannedPackages = append(scannedPackages, pkgs...)
 	}
 
-	overrideGoVersion(r, filteredScannedPackages, &configManager)
-
-	vulnsResp, err := makeRequest(r, filteredScannedPackages, actions.CompareLocally, actions.CompareOffline, actions.LocalDBPath)
+	repositories, err := repositoryRoots(actions)
 	if err != nil {
-		return models.VulnerabilityResults{}, err
+		return nil, err
 	}
+	dirs := append(slices.Clone(actions.DirectoryPaths), repositories...)
 
-	var licensesResp [][]models.License
-	if len(actions.ScanLicensesAllowlist) > 0 || actions.ScanLicensesSummary {
-		licensesResp, err = makeLicensesRequests(filteredScannedPackages)
+	var changed changedFiles
+	if actions.ChangedFilesPath != "" && len(dirs) > 0 {
+		changed, err = readChangedFiles(r, actions.ChangedFilesPath)
 		if err != nil {
-			return models.VulnerabilityResults{}, err
+			return nil, err
 		}
 	}
-	results := buildVulnerabilityResults(r, filteredScannedPackages, vulnsResp, licensesResp, actions)
 
-	filtered := filterResults(r, &results, &configManager, actions.ShowAllPackages)
-	if filtered > 0 {
-		r.Infof(
-			"Filtered %d %s from output\n",
-			filtered,
-			output.Form(filtered, "vulnerability", "vulnerabilities"),
-		)
+	filters, err := newPathFilters(actions.IncludePaths, actions.ExcludePaths)
+	if err != nil {
+		return nil, err
 	}
 
-	if len(results.Results) > 0 {
-		// Determine the correct error to return.
-		// TODO: in the next breaking release of osv-scanner, consider
-		// returning a ScanError instead of an error.
-		var vuln bool
-		onlyUncalledVuln := true
-		var licenseViolation bool
-		for _, vf := range results.Flatten() {
-			if vf.Vulnerability.ID != "" {
-				vuln = true
-				if vf.GroupInfo.IsCalled() {
-					onlyUncalledVuln = false
-				}
-			}
-			if len(vf.LicenseViolations) > 0 {
-				licenseViolation = true
+	// the manifests of every directory share clients, so that common dependencies are only fetched once
+	clients := newManifestClients()
+	for _, dir := range dirs {
+		if actions.GitRef != "" {
+			pkgs, err := scanGitRef(r, dir, actions.GitRef, actions.Recursive, changed, filters)
+			if err != nil {
+				return nil, err
 			}
+			scannedPackages = append(scannedPackages, pkgs...)
+
+			continue
 		}
-		onlyUncalledVuln = onlyUncalledVuln && vuln
-		licenseViolation = licenseViolation && len(actions.ScanLicensesAllowlist) > 0
 
-		if (!vuln || onlyUncalledVuln) && !licenseViolation {
-			// There is no error.
-			return results, nil
-		} else {
-			return results, VulnerabilitiesFoundErr
+		r.Infof("Scanning dir %s\n", dir)
+		pkgs, err := scanDir(ctx, r, dir, actions.SkipGit, actions.Recursive, !actions.NoIgnore, actions.CompareOffline, actions.ManifestOnly, dryRun, changed, actions.StrictResolve, !actions.NoProgress, actions.MavenRegistries, configManager, clients, filters)
+		if err != nil {
+			return nil, timeoutErr(ctx, err)
 		}
+		scannedPackages = append(scannedPackages, pkgs...)
+	}
+
+	if len(repositories) > 0 {
+		attributeRepositories(scannedPackages, repositories)
 	}
 
-	return results, nil
+	return scannedPackages, nil
 }
 
 // filterUnscannablePackages removes packages that don't have enough information to be scanned
@@ -908,6 +1496,70 @@ func filterUnscannablePackages(packages []scannedPackage) []scannedPackage {
 	return out
 }
 
+// filterUnregisteredPackages removes packages that are not from the registry of their
+// ecosystem, such as git and path dependencies, as they could be falsely matched against
+// advisories for registry packages that happen to have the same name and version
+func filterUnregisteredPackages(r reporter.Reporter, packages []scannedPackage) []scannedPackage {
+	out := make([]scannedPackage, 0, len(packages))
+	for _, p := range packages {
+		if p.Origin == "" {
+			out = append(out, p)
+
+			continue
+		}
+
+		r.Infof("Not matching %s@%s from %s against %s advisories as it is from %s\n", p.Name, p.Version, p.Source.Path, p.Ecosystem, p.Origin)
+	}
+
+	return out
+}
+
+// filterInactivePythonPackages removes packages with environment markers that
+// are not satisfied by the Python interpreter on the PATH
+func filterInactivePythonPackages(r reporter.Reporter, packages []scannedPackage) ([]scannedPackage, error) {
+	var env pep508.Environment
+
+	out := make([]scannedPackage, 0, len(packages))
+	for _, p := range packages {
+		if p.Marker == "" {
+			out = append(out, p)
+
+			continue
+		}
+
+		if env == nil {
+			var err error
+			if env, err = pep508.DetectEnvironment(); err != nil {
+				return nil, fmt.Errorf("could not determine python environment: %w", err)
+			}
+		}
+
+		active, err := pep508.Evaluate(p.Marker, env)
+
+		if err != nil {
+			r.Warnf("Could not evaluate environment marker of %s: %v\n", p.Name, err)
+		}
+
+		if err != nil || active {
+			out = append(out, p)
+
+			continue
+		}
+
+		r.Verbosef("Excluded %s from the scan as its environment marker is not satisfied: %s\n", p.Name, p.Marker)
+	}
+
+	if excluded := len(packages) - len(out); excluded > 0 {
+		r.Infof(
+			"Excluded %d Python %s from the scan due to environment markers.\n",
+			excluded,
+			output.Form(excluded, "package", "packages"),
+		)
+	}
+
+	return out, nil
+}
+
 // patchPackageForRequest modifies packages before they are sent to osv.dev to
 // account for edge cases.
 func patchPackageForRequest(pkg scannedPackage) scannedPackage {
@@ -935,11 +1587,17 @@ func patchPackageForRequest(pkg scannedPackage) scannedPackage {
 }
 
 func makeRequest(
+	ctx context.Context,
 	r reporter.Reporter,
 	packages []scannedPackage,
 	compareLocally bool,
 	compareOffline bool,
-	localDBPath string) (*osv.HydratedBatchedResponse, error) {
+	useQueryCache bool,
+	showProgress bool,
+	localDBPath string,
+	cacheDir string,
+	queryCacheTTL time.Duration,
+	localDBMirror local.Mirror) (*osv.HydratedBatchedResponse, error) {
 	// Make OSV queries from the packages.
 	var query osv.BatchedQuery
 	for _, p := range packages {
@@ -962,9 +1620,9 @@ func makeRequest(
 	}
 
 	if compareLocally {
-		hydratedResp, err := local.MakeRequest(r, query, compareOffline, localDBPath)
+		hydratedResp, err := local.MakeRequest(ctx, r, query, compareOffline, localDBPath, cacheDir, localDBMirror)
 		if err != nil {
-			return &osv.HydratedBatchedResponse{}, fmt.Errorf("local comparison failed %w", err)
+			return nil, fmt.Errorf("local comparison failed %w", err)
 		}
 
 		return hydratedResp, nil
@@ -974,20 +1632,181 @@ func makeRequest(
 		osv.RequestUserAgent = "osv-scanner-api_v" + version.OSVVersion
 	}
 
-	resp, err := osv.MakeRequest(query)
+	var cache *local.QueryCache
+	var vulnCache *local.VulnCache
+	if useQueryCache {
+		var err error
+		cache, err = local.LoadQueryCache(cacheDir, localDBMirror, queryCacheTTL)
+		if err != nil {
+			r.Verbosef("Not using the query cache: %v\n", err)
+		}
+		vulnCache, err = local.LoadVulnCache(cacheDir, queryCacheTTL)
+		if err != nil {
+			r.Verbosef("Not using the vulnerability cache: %v\n", err)
+		}
+	}
+
+	start := time.Now()
+	resp, err := makeCachedRequest(ctx, r, query, cache, showProgress)
 	if err != nil {
-		return &osv.HydratedBatchedResponse{}, fmt.Errorf("%w: osv.dev query failed: %w", ErrAPIFailed, err)
+		return nil, fmt.Errorf("%w: osv.dev query failed: %w", ErrAPIFailed, err)
 	}
 
-	hydratedResp, err := osv.Hydrate(resp)
+	hydratedResp, err := makeCachedHydration(ctx, r, resp, vulnCache, showProgress)
 	if err != nil {
-		return &osv.HydratedBatchedResponse{}, fmt.Errorf("%w: failed to hydrate OSV response: %w", ErrAPIFailed, err)
+		if ctx.Err() != nil {
+			// the vulnerabilities that have already been fetched are returned too
+			return hydratedResp, fmt.Errorf("failed to hydrate OSV response: %w", err)
+		}
+
+		return nil, fmt.Errorf("%w: failed to hydrate OSV response: %w", ErrAPIFailed, err)
 	}
 
+	reporter.Logger(r).Debug("Queried OSV",
+		"url", osv.QueryEndpoint,
+		"queries", len(query.Queries),
+		"duration", time.Since(start),
+	)
+
 	return hydratedResp, nil
 }
 
-func makeLicensesRequests(packages []scannedPackage) ([][]models.License, error) {
+// minQueriesForProgress is how many packages need to be queried for before
+// the progress is shown, which is when they need more than one request to OSV
+const minQueriesForProgress = 1000
+
+// makeBatchedRequest queries OSV for the packages, showing the progress after
+// each request if showProgress is set
+func makeBatchedRequest(ctx context.Context, r reporter.Reporter, query osv.BatchedQuery, showProgress bool) (*osv.BatchedResponse, error) {
+	if !showProgress || len(query.Queries) <= minQueriesForProgress {
+		return osv.MakeRequestWithContext(ctx, query, http.DefaultClient)
+	}
+
+	reporter.Progress(r, 0, len(query.Queries), "Queried OSV for %d/%d packages")
+
+	return osv.MakeRequestWithProgress(ctx, query, http.DefaultClient, func(done, total int) {
+		reporter.Progress(r, done, total, "Queried OSV for %d/%d packages")
+	})
+}
+
+// makeCachedRequest queries OSV for any queries that don't have results in the cache,
+// updating the cache with the new results. The cache is not used if it is nil.
+func makeCachedRequest(ctx context.Context, r reporter.Reporter, query osv.BatchedQuery, cache *local.QueryCache, showProgress bool) (*osv.BatchedResponse, error) {
+	if cache == nil {
+		return makeBatchedRequest(ctx, r, query, showProgress)
+	}
+
+	results := make([]osv.MinimalResponse, len(query.Queries))
+
+	var uncached osv.BatchedQuery
+	var uncachedIndexes []int
+	for i, q := range query.Queries {
+		if vulns, ok := cache.Get(q); ok {
+			results[i] = osv.MinimalResponse{Vulns: vulns}
+
+			continue
+		}
+
+		uncached.Queries = append(uncached.Queries, q)
+		uncachedIndexes = append(uncachedIndexes, i)
+	}
+
+	r.Verbosef("Found results for %d of %d queries in the query cache\n", len(query.Queries)-len(uncached.Queries), len(query.Queries))
+
+	if len(uncached.Queries) == 0 {
+		return &osv.BatchedResponse{Results: results}, nil
+	}
+
+	resp, err := makeBatchedRequest(ctx, r, uncached, showProgress)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, result := range resp.Results {
+		results[uncachedIndexes[i]] = result
+		cache.Set(uncached.Queries[i], result.Vulns)
+	}
+
+	if err := cache.Write(); err != nil {
+		r.Verbosef("Failed to write the query cache: %v\n", err)
+	}
+
+	return &osv.BatchedResponse{Results: results}, nil
+}
+
+// makeCachedHydration fetches the details of the vulnerabilities in the response from OSV, apart from
+// those that have not been modified since they were cached, updating the cache with those that were
+// fetched. The cache is not used if it is nil.
+//
+// Like osv.HydrateWithContext, the vulnerabilities that have already been fetched are returned
+// alongside the error from ctx if it is done before every vulnerability has been fetched.
+func makeCachedHydration(ctx context.Context, r reporter.Reporter, resp *osv.BatchedResponse, cache *local.VulnCache, showProgress bool) (*osv.HydratedBatchedResponse, error) {
+	progress := func(done, total int) {
+		if showProgress {
+			reporter.Progress(r, done, total, "Fetched %d/%d vulnerabilities")
+		}
+	}
+
+	if cache == nil {
+		return osv.HydrateWithContext(ctx, resp, http.DefaultClient, progress)
+	}
+
+	hydrated := osv.HydratedBatchedResponse{Results: make([]osv.Response, len(resp.Results))}
+	uncached := osv.BatchedResponse{Results: make([]osv.MinimalResponse, len(resp.Results))}
+	total, cachedCount := 0, 0
+	for i, result := range resp.Results {
+		// the vulnerabilities are kept in the same order as the response, with those
+		// that are fetched being filled in by their ID once they have been
+		hydrated.Results[i].Vulns = make([]models.Vulnerability, len(result.Vulns))
+		for j, vuln := range result.Vulns {
+			total++
+			if details, ok := cache.Get(vuln); ok {
+				hydrated.Results[i].Vulns[j] = details
+				cachedCount++
+
+				continue
+			}
+
+			uncached.Results[i].Vulns = append(uncached.Results[i].Vulns, vuln)
+		}
+	}
+
+	r.Verbosef("Found the details of %d of %d vulnerabilities in the vulnerability cache\n", cachedCount, total)
+
+	fetched, err := osv.HydrateWithContext(ctx, &uncached, http.DefaultClient, progress)
+	if fetched != nil {
+		for i, result := range fetched.Results {
+			for _, vuln := range result.Vulns {
+				cache.Set(vuln)
+
+				for j, minimal := range resp.Results[i].Vulns {
+					if minimal.ID == vuln.ID && hydrated.Results[i].Vulns[j].ID == "" {
+						hydrated.Results[i].Vulns[j] = vuln
+					}
+				}
+			}
+		}
+
+		if err := cache.Write(); err != nil {
+			r.Verbosef("Failed to write the vulnerability cache: %v\n", err)
+		}
+	}
+
+	if err != nil && ctx.Err() == nil {
+		return nil, err
+	}
+
+	// any vulnerabilities that were not fetched before ctx was done are left out
+	for i, result := range hydrated.Results {
+		hydrated.Results[i].Vulns = slices.DeleteFunc(result.Vulns, func(vuln models.Vulnerability) bool {
+			return vuln.ID == ""
+		})
+	}
+
+	return &hydrated, err
+}
+
+func makeLicensesRequests(ctx context.Context, r reporter.Reporter, packages []scannedPackage) ([][]models.License, error) {
 	queries := make([]*depsdevpb.GetVersionRequest, len(packages))
 	for i, pkg := range packages {
 		system, ok := depsdev.System[pkg.Ecosystem]
@@ -996,11 +1815,18 @@ func makeLicensesRequests(packages []scannedPackage) ([][]models.License, error)
 		}
 		queries[i] = depsdev.VersionQuery(system, pkg.Name, pkg.Version)
 	}
-	licenses, err := depsdev.MakeVersionRequests(queries)
+	start := time.Now()
+	licenses, err := depsdev.MakeVersionRequestsWithContext(ctx, queries)
 	if err != nil {
 		return nil, fmt.Errorf("%w: deps.dev query failed: %w", ErrAPIFailed, err)
 	}
 
+	reporter.Logger(r).Debug("Queried deps.dev for licenses",
+		"url", depsdev.DepsdevAPI,
+		"packages", len(packages),
+		"duration", time.Since(start),
+	)
+
 	return licenses, nil
 }
 
@@ -1017,3 +1843,36 @@ func overrideGoVersion(r reporter.Reporter, packages []scannedPackage, configMan
 		}
 	}
 }
+
+// Remaps ecosystems to their canonical OSV ecosystem using osv-scanner.toml
+//
+// This is done before any querying so that both the API and local databases
+// are looked up using the canonical ecosystem.
+func remapEcosystems(r reporter.Reporter, packages []scannedPackage, configManager *config.ConfigManager) {
+	for i, pkg := range packages {
+		if pkg.Ecosystem == "" {
+			continue
+		}
+
+		configToUse := configManager.Get(r, pkg.Source.Path)
+		canonical := lockfile.Ecosystem(configToUse.CanonicalEcosystem(string(pkg.Ecosystem)))
+
+		if canonical == pkg.Ecosystem {
+			continue
+		}
+
+		if !isKnownEcosystem(canonical) {
+			r.Warnf("%s is aliased to %s, which is not a recognized ecosystem\n", pkg.Ecosystem, canonical)
+		}
+
+		packages[i].Ecosystem = canonical
+	}
+}
+
+// isKnownEcosystem checks if the ecosystem is one supported by OSV,
+// ignoring any release suffix such as in "Debian:10"
+func isKnownEcosystem(ecosystem lockfile.Ecosystem) bool {
+	base, _, _ := strings.Cut(string(ecosystem), ":")
+
+	return slices.Contains(models.Ecosystems, models.Ecosystem(base))
+}
@@ -1,6 +1,8 @@
 package osvscanner
 
 import (
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -35,6 +37,11 @@ func Test_filterResults(t *testing.T) {
 			path: "fixtures/filter/some",
 			want: 11,
 		},
+		{
+			name: "filter_with_ignore_files",
+			path: "fixtures/filter/ignore-file",
+			want: 3,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt // Reinitialize for t.Parallel()
@@ -112,3 +119,169 @@ func Test_scanGit(t *testing.T) {
 		t.Errorf("can't find .git folder")
 	}
 }
+
+func Test_remapEcosystems(t *testing.T) {
+	t.Parallel()
+
+	configManager := config.ConfigManager{
+		OverrideConfig: &config.Config{
+			EcosystemAliases: map[string]string{
+				"pypi-mirror": "PyPI",
+				"internal":    "SomethingElse",
+			},
+		},
+		ConfigMap: make(map[string]config.Config),
+	}
+
+	packages := []scannedPackage{
+		{Name: "requests", Version: "2.0.0", Ecosystem: "pypi-mirror"},
+		{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"},
+		{Name: "thing", Version: "1.0.0", Ecosystem: "internal"},
+		{Commit: "abc123"},
+	}
+
+	remapEcosystems(&reporter.VoidReporter{}, packages, &configManager)
+
+	want := []scannedPackage{
+		{Name: "requests", Version: "2.0.0", Ecosystem: "PyPI"},
+		{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"},
+		{Name: "thing", Version: "1.0.0", Ecosystem: "SomethingElse"},
+		{Commit: "abc123"},
+	}
+
+	if diff := cmp.Diff(want, packages); diff != "" {
+		t.Errorf("remapEcosystems() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_timeoutErr(t *testing.T) {
+	t.Parallel()
+
+	errFailed := errors.New("request failed")
+
+	expired, cancelExpired := context.WithTimeout(context.Background(), 0)
+	defer cancelExpired()
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want error
+	}{
+		{name: "deadline exceeded", ctx: expired, want: ErrTimedOut},
+		{name: "cancelled", ctx: cancelled, want: ErrInterrupted},
+		{name: "not done", ctx: context.Background(), want: errFailed},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := timeoutErr(tt.ctx, errFailed)
+			if !errors.Is(got, tt.want) || !errors.Is(got, errFailed) {
+				t.Errorf("timeoutErr() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_resultErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, err := range []error{
+		ErrLicenseViolationsFound,
+		ErrVulnerabilitiesAndLicenseViolationsFound,
+		ErrNewVulnerabilitiesFound,
+		ErrUnknownLicensesFound,
+	} {
+		if !errors.Is(err, VulnerabilitiesFoundErr) {
+			t.Errorf("errors.Is(%v, VulnerabilitiesFoundErr) = false, want true", err)
+		}
+	}
+}
+
+func Test_resultErr_WithPreviousResults(t *testing.T) {
+	t.Parallel()
+
+	newResults := func(severity string, licenseViolations ...models.License) models.VulnerabilityResults {
+		return models.VulnerabilityResults{
+			Results: []models.PackageSource{{
+				Source: models.SourceInfo{Path: "/path/to/package-lock.json", Type: "lockfile"},
+				Packages: []models.PackageVulns{{
+					Package:           models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"},
+					Vulnerabilities:   []models.Vulnerability{{ID: "GHSA-1"}},
+					Groups:            []models.GroupInfo{{IDs: []string{"GHSA-1"}, MaxSeverity: severity}},
+					LicenseViolations: licenseViolations,
+				}},
+			}},
+		}
+	}
+	noFindings := models.VulnerabilityResults{Results: []models.PackageSource{}}
+
+	tests := []struct {
+		name     string
+		actions  ScannerActions
+		results  models.VulnerabilityResults
+		reported models.VulnerabilityResults
+		want     error
+	}{
+		{
+			name:     "new vulnerability",
+			results:  newResults("7.5"),
+			reported: newResults("7.5"),
+			want:     ErrNewVulnerabilitiesFound,
+		},
+		{
+			name:     "no new vulnerabilities",
+			results:  newResults("7.5"),
+			reported: noFindings,
+			want:     nil,
+		},
+		{
+			name:     "new vulnerability below the severity to fail on",
+			actions:  ScannerActions{ExperimentalScannerActions: ExperimentalScannerActions{FailOnSeverity: "critical"}},
+			results:  newResults("7.5"),
+			reported: newResults("7.5"),
+			want:     nil,
+		},
+		{
+			name:     "license violations without new vulnerabilities",
+			actions:  ScannerActions{ExperimentalScannerActions: ExperimentalScannerActions{ScanLicensesAllowlist: []string{"MIT"}}},
+			results:  newResults("7.5", "GPL-3.0"),
+			reported: noFindings,
+			want:     ErrLicenseViolationsFound,
+		},
+		{
+			name:     "license violations and new vulnerabilities",
+			actions:  ScannerActions{ExperimentalScannerActions: ExperimentalScannerActions{ScanLicensesAllowlist: []string{"MIT"}}},
+			results:  newResults("7.5", "GPL-3.0"),
+			reported: newResults("7.5", "GPL-3.0"),
+			want:     ErrVulnerabilitiesAndLicenseViolationsFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tt.actions.PreviousResults = &models.VulnerabilityResults{}
+
+			_, mapping, err := parseSeverityOptions(tt.actions)
+			if err != nil {
+				t.Fatalf("parseSeverityOptions() error = %v", err)
+			}
+
+			failOnScore, err := parseFailOnSeverity(tt.actions, mapping)
+			if err != nil {
+				t.Fatalf("parseFailOnSeverity() error = %v", err)
+			}
+
+			r := &reporter.VoidReporter{}
+			if got := resultErr(r, tt.actions, tt.results, tt.reported, failOnScore); !errors.Is(got, tt.want) {
+				t.Errorf("resultErr() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package osvscanner
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// pathFilters decide which of the files within a scanned directory are scanned using globs,
+// which are matched against paths relative to the directory and support "**" to match any
+// number of directories. Excludes take precedence over includes, and if there are no
+// includes then every file that is not excluded is scanned.
+//
+// A glob that matches a directory also matches everything within it.
+type pathFilters struct {
+	include []string
+	exclude []string
+}
+
+// newPathFilters creates pathFilters from the globs, checking that they are all valid
+func newPathFilters(include []string, exclude []string) (pathFilters, error) {
+	for _, glob := range append(append([]string{}, include...), exclude...) {
+		for _, segment := range strings.Split(filepath.ToSlash(glob), "/") {
+			if _, err := path.Match(segment, ""); err != nil {
+				return pathFilters{}, fmt.Errorf("invalid glob %q: %w", glob, err)
+			}
+		}
+	}
+
+	return pathFilters{include: include, exclude: exclude}, nil
+}
+
+// matchGlobSegments reports if the segments of a path match those of a glob,
+// where a "**" segment matches zero or more segments of the path
+func matchGlobSegments(glob []string, segments []string) bool {
+	if len(glob) == 0 {
+		return len(segments) == 0
+	}
+
+	if glob[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchGlobSegments(glob[1:], segments[i:]) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if len(segments) == 0 {
+		return false
+	}
+
+	if matched, _ := path.Match(glob[0], segments[0]); !matched {
+		return false
+	}
+
+	return matchGlobSegments(glob[1:], segments[1:])
+}
+
+// matchesAny reports if any of the globs match the relative path or any of its parent directories
+func matchesAny(globs []string, rel string) bool {
+	segments := strings.Split(filepath.ToSlash(rel), "/")
+
+	for _, glob := range globs {
+		globSegments := strings.Split(strings.TrimSuffix(filepath.ToSlash(glob), "/"), "/")
+
+		for i := 1; i <= len(segments); i++ {
+			if matchGlobSegments(globSegments, segments[:i]) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// skipsDir reports if the directory at the relative path is excluded, in which case nothing
+// within it is scanned. Directories are never skipped for not being included, as they may
+// contain files that are.
+func (f pathFilters) skipsDir(rel string) bool {
+	return rel != "." && matchesAny(f.exclude, rel)
+}
+
+// includes reports if the file (or directory that is scanned as a whole,
+// such as a git repository) at the relative path should be scanned
+func (f pathFilters) includes(rel string) bool {
+	if rel == "." {
+		return true
+	}
+
+	if matchesAny(f.exclude, rel) {
+		return false
+	}
+
+	return len(f.include) == 0 || matchesAny(f.include, rel)
+}
@@ -0,0 +1,158 @@
+package osvscanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+func Test_pathFilters(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		include     []string
+		exclude     []string
+		rel         string
+		wantInclude bool
+		wantSkipDir bool
+	}{
+		{
+			name:        "no filters",
+			rel:         "vendor/package-lock.json",
+			wantInclude: true,
+		},
+		{
+			name:        "excluded directory",
+			exclude:     []string{"vendor"},
+			rel:         "vendor",
+			wantSkipDir: true,
+		},
+		{
+			name:        "file within an excluded directory",
+			exclude:     []string{"vendor"},
+			rel:         "vendor/github.com/package-lock.json",
+			wantSkipDir: true,
+		},
+		{
+			name:        "excluded directory is only excluded at the root",
+			exclude:     []string{"vendor"},
+			rel:         "services/vendor/package-lock.json",
+			wantInclude: true,
+		},
+		{
+			name:        "excluded directory at any depth",
+			exclude:     []string{"**/testdata"},
+			rel:         "services/api/testdata",
+			wantSkipDir: true,
+		},
+		{
+			name:        "included path",
+			include:     []string{"services/**/package.json"},
+			rel:         "services/api/web/package.json",
+			wantInclude: true,
+		},
+		{
+			name:        "double star matches no directories",
+			include:     []string{"services/**/package.json"},
+			rel:         "services/package.json",
+			wantInclude: true,
+		},
+		{
+			name:    "path that is not included",
+			include: []string{"services/**"},
+			rel:     "tools/package.json",
+		},
+		{
+			name:        "directory that is not included is still walked",
+			include:     []string{"**/package.json"},
+			rel:         "tools",
+			wantInclude: false,
+			wantSkipDir: false,
+		},
+		{
+			name:        "excludes take precedence over includes",
+			include:     []string{"services/**"},
+			exclude:     []string{"services/legacy"},
+			rel:         "services/legacy/package.json",
+			wantSkipDir: true,
+		},
+		{
+			name:        "root is never filtered",
+			include:     []string{"services/**"},
+			exclude:     []string{"**"},
+			rel:         ".",
+			wantInclude: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			filters, err := newPathFilters(tt.include, tt.exclude)
+			if err != nil {
+				t.Fatalf("newPathFilters() error = %v", err)
+			}
+
+			if got := filters.includes(tt.rel); got != tt.wantInclude {
+				t.Errorf("includes(%q) = %v, want %v", tt.rel, got, tt.wantInclude)
+			}
+			if got := filters.skipsDir(tt.rel); got != tt.wantSkipDir {
+				t.Errorf("skipsDir(%q) = %v, want %v", tt.rel, got, tt.wantSkipDir)
+			}
+		})
+	}
+}
+
+func Test_newPathFilters_InvalidGlob(t *testing.T) {
+	t.Parallel()
+
+	if _, err := newPathFilters([]string{"services/[a"}, nil); err == nil {
+		t.Errorf("newPathFilters() expected an error for an invalid glob")
+	}
+}
+
+func TestListScanTargets_PathFilters(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for _, name := range []string{"api", "vendor/lib", "api/testdata"} {
+		if err := os.MkdirAll(filepath.Join(dir, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name, "requirements.txt"), []byte("django==2.2.0\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := ListScanTargets(ScannerActions{
+		DirectoryPaths: []string{dir},
+		Recursive:      true,
+		SkipGit:        true,
+		IncludePaths:   []string{"**/requirements.txt"},
+		ExcludePaths:   []string{"vendor", "**/testdata"},
+	}, &reporter.VoidReporter{})
+	if err != nil {
+		t.Fatalf("ListScanTargets() error = %v", err)
+	}
+
+	want := []models.ScanTarget{
+		{
+			Source: models.SourceInfo{
+				Path: filepath.Join(dir, "api", "requirements.txt"),
+				Type: "lockfile",
+			},
+			Ecosystems: []string{"PyPI"},
+			Packages:   1,
+		},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ListScanTargets() mismatch (-want +got):\n%s", diff)
+	}
+}
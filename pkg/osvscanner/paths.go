@@ -0,0 +1,169 @@
+package osvscanner
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"deps.dev/util/resolve"
+	"github.com/google/osv-scanner/internal/cachedregexp"
+	"github.com/google/osv-scanner/internal/resolution"
+	resolutionlockfile "github.com/google/osv-scanner/internal/resolution/lockfile"
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+// readDependencyGraph reads the lockfile at path as a graph of which packages depend on which,
+// returning nil if the lockfile does not record that information
+func readDependencyGraph(r reporter.Reporter, path string) *resolve.Graph {
+	lockfileIO, err := resolutionlockfile.GetLockfileIO(path)
+	if err != nil {
+		return nil
+	}
+
+	f, err := lockfile.OpenLocalDepFile(path)
+	if err != nil {
+		r.Warnf("Failed to read the dependency paths in %s: %v\n", path, err)
+		return nil
+	}
+	defer f.Close()
+
+	graph, err := lockfileIO.Read(f)
+	if err != nil {
+		r.Warnf("Failed to read the dependency paths in %s: %v\n", path, err)
+		return nil
+	}
+
+	return graph
+}
+
+// dependencyPaths returns every path through the graph from a direct dependency down to
+// the package with name and version, with each package in the path as "name@version"
+func dependencyPaths(graph *resolve.Graph, name, version string) [][]string {
+	var nodes []resolve.NodeID
+	// the root node is the project itself, so it is never a dependency
+	for i, node := range graph.Nodes[1:] {
+		if node.Version.Name == name && node.Version.Version == version {
+			nodes = append(nodes, resolve.NodeID(i+1))
+		}
+	}
+
+	var paths [][]string
+	for _, chains := range resolution.ComputeChains(graph, nodes) {
+		for _, chain := range chains {
+			// the edge from the root node is at the end of the chain
+			path := make([]string, 0, len(chain.Edges))
+			for i := len(chain.Edges) - 1; i >= 0; i-- {
+				vk, _ := chain.At(i)
+				path = append(path, vk.Name+"@"+vk.Version)
+			}
+
+			paths = append(paths, path)
+		}
+	}
+
+	// the same package can be installed in more than one place, which can result in identical paths
+	slices.SortFunc(paths, func(a, b []string) int {
+		return strings.Compare(strings.Join(a, " "), strings.Join(b, " "))
+	})
+
+	return slices.CompactFunc(paths, slices.Equal[[]string])
+}
+
+// manifestDependenciesRe matches the start of the sections of a package.json that declare dependencies
+var manifestDependenciesRe = cachedregexp.MustCompile(`^\s*"(dependencies|devDependencies|optionalDependencies|peerDependencies)"\s*:\s*\{`)
+
+// manifestDependencyLines returns the path to the manifest alongside the lockfile at path, along
+// with the line that each dependency is first declared on in it, if the lockfile has a manifest
+func manifestDependencyLines(path string) (string, map[string]int) {
+	if filepath.Base(path) != "package-lock.json" {
+		return "", nil
+	}
+
+	manifestPath := filepath.Join(filepath.Dir(path), "package.json")
+	f, err := os.Open(manifestPath)
+	if err != nil {
+		return "", nil
+	}
+	defer f.Close()
+
+	lines := make(map[string]int)
+	inDependencies := false
+	scanner := bufio.NewScanner(f)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case manifestDependenciesRe.MatchString(line):
+			// sections can be empty, such as "dependencies": {}
+			inDependencies = !strings.HasSuffix(strings.TrimSuffix(line, ","), "}")
+		case strings.HasPrefix(line, "}"):
+			inDependencies = false
+		case inDependencies && strings.HasPrefix(line, `"`):
+			name, _, ok := strings.Cut(line[1:], `"`)
+			if _, seen := lines[name]; ok && !seen {
+				lines[name] = lineNum
+			}
+		}
+	}
+
+	return manifestPath, lines
+}
+
+// introducedBy returns the direct dependencies at the start of the paths, along with where they
+// are declared in the manifest if they are in the lines of it
+func introducedBy(paths [][]string, manifestPath string, lines map[string]int) []models.DirectDependency {
+	var deps []models.DirectDependency
+	for _, path := range paths {
+		// the version comes after the last "@", as scoped npm packages start with one
+		i := strings.LastIndex(path[0], "@")
+		if i <= 0 {
+			continue
+		}
+		dep := models.DirectDependency{Name: path[0][:i], Version: path[0][i+1:]}
+		if line, ok := lines[dep.Name]; ok {
+			dep.Manifest = manifestPath
+			dep.Line = line
+		}
+
+		if !slices.Contains(deps, dep) {
+			deps = append(deps, dep)
+		}
+	}
+
+	return deps
+}
+
+// addDependencyPaths records how each package with vulnerabilities ended up being depended on,
+// for the sources that are lockfiles which record which packages depend on which
+func addDependencyPaths(r reporter.Reporter, results *models.VulnerabilityResults) {
+	for i := range results.Results {
+		source := &results.Results[i]
+		if source.Source.Type != "lockfile" {
+			continue
+		}
+
+		var graph *resolve.Graph
+		var manifestPath string
+		var manifestLines map[string]int
+		for j := range source.Packages {
+			pkg := &source.Packages[j]
+			if len(pkg.Groups) == 0 && len(pkg.Suppressed) == 0 {
+				continue
+			}
+
+			// only read the graph once it is known to be needed, as doing so can be slow
+			if graph == nil {
+				if graph = readDependencyGraph(r, source.Source.Path); graph == nil {
+					break
+				}
+				manifestPath, manifestLines = manifestDependencyLines(source.Source.Path)
+			}
+
+			pkg.DependencyPaths = dependencyPaths(graph, pkg.Package.Name, pkg.Package.Version)
+			pkg.IntroducedBy = introducedBy(pkg.DependencyPaths, manifestPath, manifestLines)
+		}
+	}
+}
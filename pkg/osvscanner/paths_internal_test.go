@@ -0,0 +1,72 @@
+package osvscanner
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+func Test_addDependencyPaths(t *testing.T) {
+	t.Parallel()
+
+	vulnerable := func(name, version string) models.PackageVulns {
+		return models.PackageVulns{
+			Package: models.PackageInfo{Name: name, Version: version, Ecosystem: "npm"},
+			Groups:  []models.GroupInfo{{IDs: []string{"GHSA-0000-0000-0000"}}},
+		}
+	}
+
+	results := models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: filepath.FromSlash("fixtures/paths/package-lock.json"), Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					vulnerable("lodash", "4.17.20"),
+					vulnerable("qs", "6.7.0"),
+					// packages without any vulnerabilities are not worth explaining
+					{Package: models.PackageInfo{Name: "express", Version: "4.17.1", Ecosystem: "npm"}},
+				},
+			},
+			{
+				// sources that do not record which packages depend on which are left alone
+				Source:   models.SourceInfo{Path: filepath.FromSlash("fixtures/purls/purls.txt"), Type: "purls"},
+				Packages: []models.PackageVulns{vulnerable("lodash", "4.17.20")},
+			},
+		},
+	}
+
+	addDependencyPaths(&reporter.VoidReporter{}, &results)
+
+	want := [][][]string{
+		{{"lodash@4.17.20"}},
+		{{"body-parser@1.19.0", "qs@6.7.0"}, {"express@4.17.1", "qs@6.7.0"}},
+		nil,
+	}
+	for i, pkg := range results.Results[0].Packages {
+		if !reflect.DeepEqual(pkg.DependencyPaths, want[i]) {
+			t.Errorf("paths of %s = %v, want %v", pkg.Package.Name, pkg.DependencyPaths, want[i])
+		}
+	}
+
+	manifest := filepath.FromSlash("fixtures/paths/package.json")
+	wantIntroducedBy := [][]models.DirectDependency{
+		{{Name: "lodash", Version: "4.17.20", Manifest: manifest, Line: 6}},
+		{
+			{Name: "body-parser", Version: "1.19.0", Manifest: manifest, Line: 9},
+			{Name: "express", Version: "4.17.1", Manifest: manifest, Line: 5},
+		},
+		nil,
+	}
+	for i, pkg := range results.Results[0].Packages {
+		if !reflect.DeepEqual(pkg.IntroducedBy, wantIntroducedBy[i]) {
+			t.Errorf("introduced by of %s = %v, want %v", pkg.Package.Name, pkg.IntroducedBy, wantIntroducedBy[i])
+		}
+	}
+
+	if paths := results.Results[1].Packages[0].DependencyPaths; paths != nil {
+		t.Errorf("expected no paths for purls, got %v", paths)
+	}
+}
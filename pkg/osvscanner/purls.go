@@ -0,0 +1,81 @@
+package osvscanner
+
+import (
+	"bufio"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+// scanPURLFile scans the file at path which has a package URL on each line, such as
+// one exported by another SBOM tool, skipping blank lines and lines starting with "#".
+//
+// Package URLs that are invalid, have no version, or are for an ecosystem that OSV
+// does not support are skipped with a warning rather than stopping the scan.
+func scanPURLFile(r reporter.Reporter, path string) ([]scannedPackage, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		r.Errorf("Failed to read package URLs from %s: %v\n", path, err)
+		return nil, err
+	}
+	defer file.Close()
+
+	var packages []scannedPackage
+
+	scanner := bufio.NewScanner(file)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		pkg, err := models.PURLToPackage(line)
+		if err != nil {
+			r.Warnf("%s:%d: %s is not a valid package URL: %v\n", path, lineNum, line, err)
+
+			continue
+		}
+
+		if pkg.Version == "" {
+			r.Warnf("%s:%d: %s does not have a version, cannot assess\n", path, lineNum, line)
+
+			continue
+		}
+
+		base, _, _ := strings.Cut(pkg.Ecosystem, ":")
+		if !slices.Contains(models.Ecosystems, models.Ecosystem(base)) {
+			r.Warnf("%s:%d: %s is not for an ecosystem supported by OSV, cannot assess\n", path, lineNum, line)
+
+			continue
+		}
+
+		packages = append(packages, scannedPackage{
+			Name:      pkg.Name,
+			Version:   pkg.Version,
+			Ecosystem: lockfile.Ecosystem(pkg.Ecosystem),
+			Source: models.SourceInfo{
+				Path: path,
+				Type: "purls",
+			},
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		r.Errorf("Failed to read package URLs from %s: %v\n", path, err)
+		return nil, err
+	}
+
+	r.Infof(
+		"Scanned %s file and found %d %s\n",
+		path,
+		len(packages),
+		output.Form(len(packages), "package", "packages"),
+	)
+
+	return packages, nil
+}
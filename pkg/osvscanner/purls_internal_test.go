@@ -0,0 +1,43 @@
+package osvscanner
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+func Test_scanPURLFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.FromSlash("fixtures/purls/purls.txt")
+	source := models.SourceInfo{Path: path, Type: "purls"}
+
+	got, err := scanPURLFile(&reporter.VoidReporter{}, path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// package URLs that are invalid, unversioned, or unsupported are skipped
+	want := []scannedPackage{
+		{Name: "lodash", Version: "4.17.20", Ecosystem: "npm", Source: source},
+		{Name: "@babel/traverse", Version: "7.23.0", Ecosystem: "npm", Source: source},
+		{Name: "org.apache.logging.log4j:log4j-core", Version: "2.14.1", Ecosystem: "Maven", Source: source},
+		{Name: "github.com/gogo/protobuf", Version: "v1.3.1", Ecosystem: "Go", Source: source},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("scanPURLFile() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_scanPURLFile_NotFound(t *testing.T) {
+	t.Parallel()
+
+	_, err := scanPURLFile(&reporter.VoidReporter{}, filepath.FromSlash("fixtures/purls/does-not-exist.txt"))
+	if err == nil {
+		t.Errorf("expected an error for a file that does not exist")
+	}
+}
@@ -0,0 +1,86 @@
+package osvscanner
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// parseRepositories parses a list of repository roots with one path on each line,
+// resolving relative paths against base. Empty lines and lines starting with "#" are skipped.
+func parseRepositories(reader io.Reader, base string) ([]string, error) {
+	var roots []string
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		path := filepath.FromSlash(line)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(base, path)
+		}
+		roots = append(roots, filepath.Clean(path))
+	}
+
+	return roots, scanner.Err()
+}
+
+// repositoryRoots returns the absolute paths of the roots of the repositories to scan, from both
+// RepositoryPaths and the file at RepositoriesFilePath, with relative paths being resolved
+// against the current working directory
+func repositoryRoots(actions ScannerActions) ([]string, error) {
+	base, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	roots := make([]string, 0, len(actions.RepositoryPaths))
+	for _, path := range actions.RepositoryPaths {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(base, path)
+		}
+		roots = append(roots, filepath.Clean(path))
+	}
+
+	if actions.RepositoriesFilePath == "" {
+		return roots, nil
+	}
+
+	file, err := os.Open(actions.RepositoriesFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read repositories from %s: %w", actions.RepositoriesFilePath, err)
+	}
+	defer file.Close()
+
+	listed, err := parseRepositories(file, base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read repositories from %s: %w", actions.RepositoriesFilePath, err)
+	}
+
+	return append(roots, listed...), nil
+}
+
+// attributeRepositories sets the repository of the source of each package to the root that
+// contains it, preferring the innermost root if the repositories are nested
+func attributeRepositories(scannedPackages []scannedPackage, roots []string) {
+	for i, pkg := range scannedPackages {
+		repository := ""
+		for _, root := range roots {
+			rel, err := filepath.Rel(root, pkg.Source.Path)
+			if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				continue
+			}
+
+			if len(root) > len(repository) {
+				repository = root
+			}
+		}
+		scannedPackages[i].Source.Repository = repository
+	}
+}
@@ -0,0 +1,117 @@
+package osvscanner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+func Test_parseRepositories(t *testing.T) {
+	t.Parallel()
+
+	base := filepath.FromSlash("/fleet")
+	input := strings.Join([]string{
+		"# the services",
+		"service-a",
+		"",
+		"  ./service-b/  ",
+		filepath.FromSlash("/elsewhere/library"),
+	}, "\n")
+
+	got, err := parseRepositories(strings.NewReader(input), base)
+	if err != nil {
+		t.Fatalf("parseRepositories() error = %v", err)
+	}
+
+	want := []string{
+		filepath.Join(base, "service-a"),
+		filepath.Join(base, "service-b"),
+		filepath.FromSlash("/elsewhere/library"),
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("parseRepositories() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_attributeRepositories(t *testing.T) {
+	t.Parallel()
+
+	outer := filepath.FromSlash("/fleet/monorepo")
+	inner := filepath.FromSlash("/fleet/monorepo/vendor/library")
+
+	pkgs := []scannedPackage{
+		{Name: "a", Source: models.SourceInfo{Path: filepath.Join(outer, "package-lock.json")}},
+		{Name: "b", Source: models.SourceInfo{Path: filepath.Join(inner, "go.mod")}},
+		{Name: "c", Source: models.SourceInfo{Path: filepath.FromSlash("/fleet/monorepo-other/Cargo.lock")}},
+	}
+
+	attributeRepositories(pkgs, []string{inner, outer})
+
+	var got []string
+	for _, pkg := range pkgs {
+		got = append(got, pkg.Source.Repository)
+	}
+
+	if diff := cmp.Diff([]string{outer, inner, ""}, got); diff != "" {
+		t.Errorf("attributeRepositories() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestListScanTargets_Repositories(t *testing.T) {
+	t.Parallel()
+
+	fleet := t.TempDir()
+	for _, name := range []string{"service-a", "service-b"} {
+		if err := os.MkdirAll(filepath.Join(fleet, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(fleet, name, "requirements.txt"), []byte("django==2.2.0\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reposFile := filepath.Join(t.TempDir(), "repositories.txt")
+	if err := os.WriteFile(reposFile, []byte(filepath.Join(fleet, "service-b")+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ListScanTargets(ScannerActions{
+		RepositoryPaths:      []string{filepath.Join(fleet, "service-a")},
+		RepositoriesFilePath: reposFile,
+		SkipGit:              true,
+	}, &reporter.VoidReporter{})
+	if err != nil {
+		t.Fatalf("ListScanTargets() error = %v", err)
+	}
+
+	want := []models.ScanTarget{
+		{
+			Source: models.SourceInfo{
+				Path:       filepath.Join(fleet, "service-a", "requirements.txt"),
+				Type:       "lockfile",
+				Repository: filepath.Join(fleet, "service-a"),
+			},
+			Ecosystems: []string{"PyPI"},
+			Packages:   1,
+		},
+		{
+			Source: models.SourceInfo{
+				Path:       filepath.Join(fleet, "service-b", "requirements.txt"),
+				Type:       "lockfile",
+				Repository: filepath.Join(fleet, "service-b"),
+			},
+			Ecosystems: []string{"PyPI"},
+			Packages:   1,
+		},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ListScanTargets() mismatch (-want +got):\n%s", diff)
+	}
+}
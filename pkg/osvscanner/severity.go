@@ -0,0 +1,98 @@
+package osvscanner
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/google/osv-scanner/internal/utility/severity"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// parseSeverityOptions parses the CVSS version and severity mapping of the actions,
+// defaulting to the highest score of any version and the ratings defined by CVSS
+func parseSeverityOptions(actions ScannerActions) (models.SeverityType, severity.Mapping, error) {
+	var preferred models.SeverityType
+	if actions.CVSSVersion != "" {
+		var err error
+		preferred, err = severity.ParseVersion(actions.CVSSVersion)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	mapping := severity.DefaultMapping
+	if len(actions.SeverityMapping) > 0 {
+		var err error
+		mapping, err = severity.ParseMapping(actions.SeverityMapping)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid severity mapping: %w", err)
+		}
+	}
+
+	return preferred, mapping, nil
+}
+
+// parseFailOnSeverity returns the lowest score that vulnerabilities need to have to fail the scan
+// for the rating of the mapping, which is negative if every vulnerability fails it, including
+// those that have no score
+func parseFailOnSeverity(actions ScannerActions, mapping severity.Mapping) (float64, error) {
+	if actions.FailOnSeverity == "" {
+		return -1, nil
+	}
+
+	minScore, err := mapping.MinScore(actions.FailOnSeverity)
+	if err != nil {
+		return 0, fmt.Errorf("invalid severity to fail on: %w", err)
+	}
+
+	return minScore, nil
+}
+
+// meetsSeverityThreshold reports whether the vulnerabilities of the group have a score of at least
+// minScore as set by applySeverities, which groups without a score only do if minScore is negative
+func meetsSeverityThreshold(group models.GroupInfo, minScore float64) bool {
+	if minScore < 0 {
+		return true
+	}
+
+	score, err := strconv.ParseFloat(group.MaxSeverity, 64)
+
+	return err == nil && score >= minScore
+}
+
+// groupScore returns the highest score of the vulnerabilities in the group,
+// using the scores of the preferred CVSS version if they have one
+func groupScore(group models.GroupInfo, pkg models.PackageVulns, preferred models.SeverityType) float64 {
+	maxScore := -1.0
+	for _, vulnID := range group.IDs {
+		for _, vuln := range pkg.Vulnerabilities {
+			if vuln.ID != vulnID {
+				continue
+			}
+
+			score, _, _ := severity.CalculatePreferredScore(vuln.Severity, preferred)
+			maxScore = max(maxScore, score)
+		}
+	}
+
+	return maxScore
+}
+
+// applySeverities sets the severity of every group in the results once, so that
+// all the reporters use the same score and rating for each of them
+func applySeverities(results *models.VulnerabilityResults, preferred models.SeverityType, mapping severity.Mapping) {
+	for i := range results.Results {
+		for j := range results.Results[i].Packages {
+			pkg := &results.Results[i].Packages[j]
+			for k, group := range pkg.Groups {
+				score := groupScore(group, *pkg, preferred)
+
+				pkg.Groups[k].MaxSeverity = ""
+				if score >= 0 {
+					pkg.Groups[k].MaxSeverity = fmt.Sprintf("%.1f", score)
+				}
+				pkg.Groups[k].SeverityRating = mapping.Rating(score)
+			}
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package osvscanner
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/internal/utility/severity"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func Test_applySeverities(t *testing.T) {
+	t.Parallel()
+
+	newResults := func() models.VulnerabilityResults {
+		return models.VulnerabilityResults{
+			Results: []models.PackageSource{{
+				Source: models.SourceInfo{Path: "/path/to/requirements.txt", Type: "lockfile"},
+				Packages: []models.PackageVulns{{
+					Package: models.PackageInfo{Name: "django", Version: "2.2.0", Ecosystem: "PyPI"},
+					Vulnerabilities: []models.Vulnerability{
+						{
+							ID: "GHSA-1",
+							Severity: []models.Severity{
+								{Type: models.SeverityCVSSV3, Score: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:N/A:N"},
+								{Type: models.SeverityCVSSV4, Score: "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N"},
+							},
+						},
+						{ID: "GHSA-2"},
+					},
+					Groups: []models.GroupInfo{
+						{IDs: []string{"GHSA-1"}},
+						{IDs: []string{"GHSA-2"}},
+					},
+				}},
+			}},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		actions    ScannerActions
+		wantGroups []models.GroupInfo
+	}{
+		{
+			name: "defaults",
+			wantGroups: []models.GroupInfo{
+				{IDs: []string{"GHSA-1"}, MaxSeverity: "9.3", SeverityRating: "Critical"},
+				{IDs: []string{"GHSA-2"}},
+			},
+		},
+		{
+			name:    "preferred version and custom mapping",
+			actions: ScannerActions{CVSSVersion: "3", SeverityMapping: []string{"Low:0", "Moderate:5", "Severe:8"}},
+			wantGroups: []models.GroupInfo{
+				{IDs: []string{"GHSA-1"}, MaxSeverity: "7.5", SeverityRating: "Moderate"},
+				{IDs: []string{"GHSA-2"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			preferred, mapping, err := parseSeverityOptions(tt.actions)
+			if err != nil {
+				t.Fatalf("parseSeverityOptions() error = %v", err)
+			}
+
+			results := newResults()
+			applySeverities(&results, preferred, mapping)
+
+			if diff := cmp.Diff(tt.wantGroups, results.Results[0].Packages[0].Groups); diff != "" {
+				t.Errorf("applySeverities() groups mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_meetsSeverityThreshold(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		failOn string
+		group  models.GroupInfo
+		want   bool
+	}{
+		{failOn: "", group: models.GroupInfo{MaxSeverity: "2.0"}, want: true},
+		{failOn: "", group: models.GroupInfo{}, want: true},
+		{failOn: "low", group: models.GroupInfo{MaxSeverity: "0.0"}, want: true},
+		{failOn: "high", group: models.GroupInfo{MaxSeverity: "6.9"}, want: false},
+		{failOn: "high", group: models.GroupInfo{MaxSeverity: "7.0"}, want: true},
+		{failOn: "Critical", group: models.GroupInfo{MaxSeverity: "9.8"}, want: true},
+		{failOn: "low", group: models.GroupInfo{}, want: false},
+	}
+	for _, tt := range tests {
+		minScore, err := parseFailOnSeverity(ScannerActions{ExperimentalScannerActions: ExperimentalScannerActions{FailOnSeverity: tt.failOn}}, severity.DefaultMapping)
+		if err != nil {
+			t.Fatalf("parseFailOnSeverity(%q) error = %v", tt.failOn, err)
+		}
+
+		if got := meetsSeverityThreshold(tt.group, minScore); got != tt.want {
+			t.Errorf("meetsSeverityThreshold(%q, %q) = %v, want %v", tt.group.MaxSeverity, tt.failOn, got, tt.want)
+		}
+	}
+
+	if _, err := parseFailOnSeverity(ScannerActions{ExperimentalScannerActions: ExperimentalScannerActions{FailOnSeverity: "severe"}}, severity.DefaultMapping); err == nil {
+		t.Errorf("parseFailOnSeverity(%q) did not return an error", "severe")
+	}
+}
+
+func Test_parseFailOnSeverity_WithSeverityMapping(t *testing.T) {
+	t.Parallel()
+
+	actions := ScannerActions{
+		SeverityMapping: []string{"Low:0", "Moderate:5", "Severe:8"},
+		ExperimentalScannerActions: ExperimentalScannerActions{
+			FailOnSeverity: "severe",
+		},
+	}
+
+	_, mapping, err := parseSeverityOptions(actions)
+	if err != nil {
+		t.Fatalf("parseSeverityOptions() error = %v", err)
+	}
+
+	minScore, err := parseFailOnSeverity(actions, mapping)
+	if err != nil {
+		t.Fatalf("parseFailOnSeverity(%q) error = %v", actions.FailOnSeverity, err)
+	}
+
+	if minScore != 8 {
+		t.Errorf("parseFailOnSeverity(%q) = %v, want 8", actions.FailOnSeverity, minScore)
+	}
+
+	// the ratings defined by CVSS are not used when there is a custom mapping
+	actions.FailOnSeverity = "high"
+	if _, err := parseFailOnSeverity(actions, mapping); err == nil {
+		t.Errorf("parseFailOnSeverity(%q) did not return an error", actions.FailOnSeverity)
+	}
+}
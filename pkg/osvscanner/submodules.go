@@ -0,0 +1,96 @@
+package osvscanner
+
+import (
+	"path"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/pkg/osv"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+// submodule is a git submodule that its superproject has pinned to a commit
+type submodule struct {
+	// the path of the submodule within the superproject
+	Path string
+	// the url of the upstream repository of the submodule
+	URL string
+	// the commit that the submodule is pinned to
+	Commit string
+}
+
+// normalizeSubmoduleURL converts scp-like urls (git@github.com:google/osv-scanner.git)
+// into https ones, and removes any trailing .git, so that upstreams are reported consistently
+func normalizeSubmoduleURL(url string) string {
+	if host, repo, ok := strings.Cut(url, ":"); ok && !strings.Contains(url, "://") && strings.Contains(host, "@") {
+		_, host, _ = strings.Cut(host, "@")
+		url = "https://" + host + "/" + repo
+	}
+
+	return strings.TrimSuffix(strings.TrimSuffix(url, "/"), ".git")
+}
+
+// getSubmodules returns the submodules in the .gitmodules of the repository along with the
+// commits that they are pinned to in its index, which does not need them to be initialized
+func getSubmodules(repoDir string) ([]submodule, error) {
+	repo, err := git.PlainOpen(repoDir)
+	if err != nil {
+		return nil, err
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	ss, err := worktree.Submodules()
+	if err != nil {
+		return nil, err
+	}
+
+	submodules := make([]submodule, 0, len(ss))
+	for _, s := range ss {
+		status, err := s.Status()
+		if err != nil || status.Expected.IsZero() {
+			continue
+		}
+		submodules = append(submodules, submodule{
+			Path:   status.Path,
+			URL:    normalizeSubmoduleURL(s.Config().URL),
+			Commit: status.Expected.String(),
+		})
+	}
+
+	return submodules, nil
+}
+
+// createSubmoduleQueryPackage creates a package for the submodule of the repository,
+// which is queried by the commit it is pinned to and named after its upstream
+func createSubmoduleQueryPackage(s submodule, repoDir string) scannedPackage {
+	pkg := createCommitQueryPackage(s.Commit, path.Join(repoDir, s.Path))
+	pkg.Name = s.URL
+	pkg.Source.Type = "submodule"
+
+	return pkg
+}
+
+// reportUnmatchedSubmodules lists the submodules that did not match any OSV records,
+// which is usually because their upstream repository is not tracked by OSV
+func reportUnmatchedSubmodules(r reporter.Reporter, packages []scannedPackage, vulnsResp *osv.HydratedBatchedResponse) {
+	var unmatched []string
+	for i, pkg := range packages {
+		if pkg.Source.Type == "submodule" && i < len(vulnsResp.Results) && len(vulnsResp.Results[i].Vulns) == 0 {
+			unmatched = append(unmatched, pkg.Source.Path+" ("+pkg.Name+")")
+		}
+	}
+
+	if len(unmatched) == 0 {
+		return
+	}
+
+	r.Infof(
+		"%d %s did not match any OSV records, which may be because OSV does not track their upstream repositories:\n  %s\n",
+		len(unmatched),
+		output.Form(len(unmatched), "submodule", "submodules"),
+		strings.Join(unmatched, "\n  "),
+	)
+}
@@ -0,0 +1,130 @@
+package osvscanner
+
+import (
+	"bytes"
+	"io"
+	"path"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+func Test_normalizeSubmoduleURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{url: "https://github.com/madler/zlib.git", want: "https://github.com/madler/zlib"},
+		{url: "https://github.com/madler/zlib/", want: "https://github.com/madler/zlib"},
+		{url: "git@github.com:madler/zlib.git", want: "https://github.com/madler/zlib"},
+		{url: "ssh://git@github.com/madler/zlib.git", want: "ssh://git@github.com/madler/zlib"},
+		{url: "../zlib", want: "../zlib"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.url, func(t *testing.T) {
+			t.Parallel()
+
+			if got := normalizeSubmoduleURL(tt.url); got != tt.want {
+				t.Errorf("normalizeSubmoduleURL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// pinSubmodule adds a gitlink to the index of the repository, which pins the submodule
+// at the path to the commit without needing the submodule itself to be cloned
+func pinSubmodule(t *testing.T, repo *git.Repository, name string, commit string) {
+	t.Helper()
+
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idx.Entries = append(idx.Entries, &index.Entry{
+		Name: name,
+		Hash: plumbing.NewHash(commit),
+		Mode: filemode.Submodule,
+	})
+
+	if err := repo.Storer.SetIndex(idx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_scanGit_Submodules(t *testing.T) {
+	t.Parallel()
+
+	repoDir := t.TempDir()
+	repo, err := git.PlainInit(repoDir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	head := commitFiles(t, repo, map[string]string{
+		".gitmodules": `[submodule "third_party/zlib"]
+	path = third_party/zlib
+	url = git@github.com:madler/zlib.git
+[submodule "third_party/unpinned"]
+	path = third_party/unpinned
+	url = https://example.com/unpinned.git
+`,
+	})
+	pinSubmodule(t, repo, "third_party/zlib", "04f42ceca40f73e2978b50e93806c2a18c1281fc")
+
+	got, err := scanGit(&reporter.VoidReporter{}, repoDir+"/")
+	if err != nil {
+		t.Fatalf("scanGit() error = %v", err)
+	}
+
+	want := []scannedPackage{
+		{
+			Commit: head,
+			Source: models.SourceInfo{Path: repoDir + "/", Type: "git"},
+		},
+		{
+			Name:   "https://github.com/madler/zlib",
+			Commit: "04f42ceca40f73e2978b50e93806c2a18c1281fc",
+			Source: models.SourceInfo{Path: path.Join(repoDir, "third_party/zlib"), Type: "submodule"},
+		},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("scanGit() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_reportUnmatchedSubmodules(t *testing.T) {
+	t.Parallel()
+
+	packages := []scannedPackage{
+		createCommitQueryPackage("1234567890", "/repo"),
+		createSubmoduleQueryPackage(submodule{Path: "third_party/zlib", URL: "https://github.com/madler/zlib", Commit: "abcdef"}, "/repo"),
+		createSubmoduleQueryPackage(submodule{Path: "third_party/lib", URL: "https://example.com/lib", Commit: "fedcba"}, "/repo"),
+	}
+	resp := &osv.HydratedBatchedResponse{
+		Results: []osv.Response{
+			{},
+			{Vulns: []models.Vulnerability{{ID: "OSV-2024-1"}}},
+			{},
+		},
+	}
+
+	var stdout bytes.Buffer
+	reportUnmatchedSubmodules(reporter.NewTableReporter(&stdout, io.Discard, reporter.InfoLevel, false, 0), packages, resp)
+
+	want := "1 submodule did not match any OSV records, which may be because OSV does not track their upstream repositories:\n  /repo/third_party/lib (https://example.com/lib)\n"
+	if diff := cmp.Diff(want, stdout.String()); diff != "" {
+		t.Errorf("reportUnmatchedSubmodules() mismatch (-want +got):\n%s", diff)
+	}
+}
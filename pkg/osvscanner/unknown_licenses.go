@@ -0,0 +1,37 @@
+package osvscanner
+
+import (
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+// unknownLicense is the license of packages whose license could not be determined
+const unknownLicense models.License = "UNKNOWN"
+
+// reportUnknownLicenses lists the packages in the results whose license could not be
+// determined, returning if there are any
+func reportUnknownLicenses(r reporter.Reporter, results *models.VulnerabilityResults) bool {
+	var unknown []string
+	for _, pkgSource := range results.Results {
+		for _, pkg := range pkgSource.Packages {
+			for _, license := range pkg.Licenses {
+				if license == unknownLicense {
+					unknown = append(unknown, pkg.Package.Ecosystem+"/"+pkg.Package.Name+"@"+pkg.Package.Version+" (from "+pkgSource.Source.Path+")")
+					break
+				}
+			}
+		}
+	}
+
+	if len(unknown) == 0 {
+		return false
+	}
+
+	r.Warnf("The licenses of %d %s could not be determined:\n", len(unknown), output.Form(len(unknown), "package", "packages"))
+	for _, pkg := range unknown {
+		r.Warnf("  %s\n", pkg)
+	}
+
+	return true
+}
@@ -0,0 +1,47 @@
+package osvscanner
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+func Test_reportUnknownLicenses(t *testing.T) {
+	t.Parallel()
+
+	results := models.VulnerabilityResults{
+		Results: []models.PackageSource{{
+			Source: models.SourceInfo{Path: "/path/to/package-lock.json", Type: "lockfile"},
+			Packages: []models.PackageVulns{
+				{
+					Package:  models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"},
+					Licenses: []models.License{"MIT"},
+				},
+				{
+					Package:  models.PackageInfo{Name: "mystery", Version: "1.0.0", Ecosystem: "npm"},
+					Licenses: []models.License{"MIT", "UNKNOWN"},
+				},
+			},
+		}},
+	}
+
+	out := &bytes.Buffer{}
+	r := reporter.NewTableReporter(out, io.Discard, reporter.WarnLevel, false, 0)
+
+	if !reportUnknownLicenses(r, &results) {
+		t.Errorf("reportUnknownLicenses() = false, want true")
+	}
+
+	want := "The licenses of 1 package could not be determined:\n  npm/mystery@1.0.0 (from /path/to/package-lock.json)\n"
+	if out.String() != want {
+		t.Errorf("reportUnknownLicenses() printed %q, want %q", out.String(), want)
+	}
+
+	results.Results[0].Packages = results.Results[0].Packages[:1]
+	if reportUnknownLicenses(&reporter.VoidReporter{}, &results) {
+		t.Errorf("reportUnknownLicenses() = true, want false when every license is known")
+	}
+}
@@ -0,0 +1,78 @@
+package osvscanner
+
+import (
+	"slices"
+
+	"github.com/google/osv-scanner/internal/vex"
+	"github.com/google/osv-scanner/pkg/config"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+// applyVEX moves the vulnerabilities that the OpenVEX documents at the paths, and those in the
+// VEXFiles of the config of each source, say that a package is not affected by or has fixed into
+// the suppressed bucket of the package, with the justification as the reason.
+// Returns the total number of vulnerabilities suppressed.
+func applyVEX(r reporter.Reporter, results *models.VulnerabilityResults, configManager *config.ConfigManager, paths []string) (int, error) {
+	docs := map[string]vex.Document{}
+
+	suppressedCount := 0
+	for i := range results.Results {
+		pkgSrc := &results.Results[i]
+		configToUse := configManager.Get(r, pkgSrc.Source.Path)
+
+		var sourceDocs []vex.Document
+		for _, path := range append(slices.Clone(paths), configToUse.VEXPaths()...) {
+			doc, loaded := docs[path]
+			if !loaded {
+				var err error
+				doc, err = vex.Load(path)
+				if err != nil {
+					return 0, err
+				}
+				docs[path] = doc
+				r.Infof("Loaded VEX statements from: %s\n", path)
+			}
+			sourceDocs = append(sourceDocs, doc)
+		}
+
+		if len(sourceDocs) == 0 {
+			continue
+		}
+
+		for j := range pkgSrc.Packages {
+			pkgVulns := &pkgSrc.Packages[j]
+			purl, ok := models.PackageToPURL(pkgVulns.Package)
+			if !ok {
+				continue
+			}
+
+			reasons := map[string]string{}
+			for _, group := range pkgVulns.Groups {
+				ids := append(slices.Clone(group.IDs), group.Aliases...)
+
+				// documents that are given later take precedence over earlier ones
+				var statement vex.Statement
+				var found bool
+				for _, doc := range sourceDocs {
+					if s, ok := doc.Statement(purl, ids); ok {
+						statement, found = s, true
+					}
+				}
+
+				if !found || !statement.Suppresses() {
+					continue
+				}
+
+				r.Verbosef("%s has been suppressed for %s by a VEX statement\n", group.IDs[0], pkgVulns.Package.Name)
+				for _, id := range ids {
+					reasons[id] = statement.Reason()
+				}
+			}
+
+			suppressedCount += moveToSuppressed(pkgVulns, reasons)
+		}
+	}
+
+	return suppressedCount, nil
+}
@@ -0,0 +1,96 @@
+package osvscanner
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/config"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+func vexTestResults() models.VulnerabilityResults {
+	return models.VulnerabilityResults{
+		Results: []models.PackageSource{{
+			Source: models.SourceInfo{Path: "path/to/package-lock.json", Type: "lockfile"},
+			Packages: []models.PackageVulns{{
+				Package: models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"},
+				Vulnerabilities: []models.Vulnerability{
+					{ID: "GHSA-35jh-r3h4-6jhm"},
+					{ID: "GHSA-29mw-wpgm-hmr9"},
+				},
+				Groups: []models.GroupInfo{
+					{IDs: []string{"GHSA-35jh-r3h4-6jhm"}, Aliases: []string{"CVE-2021-23337", "GHSA-35jh-r3h4-6jhm"}},
+					{IDs: []string{"GHSA-29mw-wpgm-hmr9"}, Aliases: []string{"CVE-2020-28500", "GHSA-29mw-wpgm-hmr9"}},
+				},
+			}},
+		}},
+	}
+}
+
+func Test_applyVEX(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		paths          []string
+		config         config.Config
+		wantSuppressed []models.SuppressedVulnerability
+	}{
+		{
+			name:  "not affected",
+			paths: []string{"fixtures/vex/openvex.json"},
+			wantSuppressed: []models.SuppressedVulnerability{
+				{ID: "GHSA-35jh-r3h4-6jhm", Reason: "VEX: not_affected (vulnerable_code_not_in_execute_path)"},
+			},
+		},
+		{
+			name:  "later documents take precedence",
+			paths: []string{"fixtures/vex/openvex.json", "fixtures/vex/vendor.openvex.json"},
+			wantSuppressed: []models.SuppressedVulnerability{
+				{ID: "GHSA-35jh-r3h4-6jhm", Reason: "VEX: not_affected (vulnerable_code_not_in_execute_path)"},
+				{ID: "GHSA-29mw-wpgm-hmr9", Reason: "VEX: fixed"},
+			},
+		},
+		{
+			name:   "from the config",
+			config: config.Config{LoadPath: "fixtures/vex/osv-scanner.toml", VEXFiles: []string{"vendor.openvex.json"}},
+			wantSuppressed: []models.SuppressedVulnerability{
+				{ID: "GHSA-29mw-wpgm-hmr9", Reason: "VEX: fixed"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			results := vexTestResults()
+			configManager := &config.ConfigManager{OverrideConfig: &tt.config, ConfigMap: map[string]config.Config{}}
+
+			suppressed, err := applyVEX(&reporter.VoidReporter{}, &results, configManager, tt.paths)
+			if err != nil {
+				t.Fatalf("applyVEX() error = %v", err)
+			}
+
+			if suppressed != len(tt.wantSuppressed) {
+				t.Errorf("applyVEX() suppressed %d vulnerabilities, want %d", suppressed, len(tt.wantSuppressed))
+			}
+
+			if diff := cmp.Diff(tt.wantSuppressed, results.Results[0].Packages[0].Suppressed); diff != "" {
+				t.Errorf("applyVEX() suppressed mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_applyVEX_MissingDocument(t *testing.T) {
+	t.Parallel()
+
+	results := vexTestResults()
+	configManager := &config.ConfigManager{ConfigMap: map[string]config.Config{}}
+
+	if _, err := applyVEX(&reporter.VoidReporter{}, &results, configManager, []string{"fixtures/vex/does-not-exist.json"}); err == nil {
+		t.Errorf("applyVEX() did not return an error")
+	}
+}
@@ -1,26 +1,25 @@
 package osvscanner
 
 import (
-	"sort"
-	"strings"
-
 	"github.com/google/osv-scanner/internal/output"
 	"github.com/google/osv-scanner/internal/sourceanalysis"
 	"github.com/google/osv-scanner/pkg/grouper"
 	"github.com/google/osv-scanner/pkg/models"
 	"github.com/google/osv-scanner/pkg/osv"
 	"github.com/google/osv-scanner/pkg/reporter"
+	"github.com/google/osv-scanner/pkg/spdx"
 )
 
-// buildVulnerablityResults takes the responses from the OSV API and the deps.dev API
-// and converts this into a VulnerabilityResults. As part is this, it groups
-// vulnerability information by source location.
+// buildVulnerablityResults takes the responses from the OSV API, the deps.dev API and
+// the package registries and converts this into a VulnerabilityResults. As part is this,
+// it groups vulnerability information by source location.
 // TODO: This function is getting long, we should refactor it
 func buildVulnerabilityResults(
 	r reporter.Reporter,
 	packages []scannedPackage,
 	vulnsResp *osv.HydratedBatchedResponse,
 	licensesResp [][]models.License,
+	yankedResp []*models.YankedVersion,
 	actions ScannerActions,
 ) models.VulnerabilityResults {
 	results := models.VulnerabilityResults{
@@ -54,22 +53,35 @@ func buildVulnerabilityResults(
 
 		pkg.DepGroups = rawPkg.DepGroups
 
-		if len(vulnsResp.Results[i].Vulns) > 0 {
+		vulns := make([]models.Vulnerability, 0, len(vulnsResp.Results[i].Vulns))
+		for _, vuln := range vulnsResp.Results[i].Vulns {
+			// withdrawn advisories are no longer considered to be vulnerabilities by their publisher
+			if !vuln.Withdrawn.IsZero() {
+				pkg.Withdrawn = append(pkg.Withdrawn, vuln.ID)
+				continue
+			}
+			vulns = append(vulns, vuln)
+		}
+		if len(pkg.Withdrawn) > 0 {
+			includePackage = true
+		}
+
+		if len(vulns) > 0 {
 			includePackage = true
-			pkg.Vulnerabilities = vulnsResp.Results[i].Vulns
+			pkg.Vulnerabilities = vulns
 			pkg.Groups = grouper.Group(grouper.ConvertVulnerabilityToIDAliases(pkg.Vulnerabilities))
 			for i, group := range pkg.Groups {
 				pkg.Groups[i].MaxSeverity = output.MaxSeverity(group, pkg)
 			}
+
+			if len(rawPkg.Suppressions) > 0 {
+				suppressVulns(r, &pkg, rawPkg.Suppressions)
+			}
 		}
 		if len(actions.ScanLicensesAllowlist) > 0 {
 			pkg.Licenses = licensesResp[i]
-			allowlist := make(map[string]bool)
-			for _, license := range actions.ScanLicensesAllowlist {
-				allowlist[strings.ToLower(license)] = true
-			}
 			for _, license := range pkg.Licenses {
-				if !allowlist[strings.ToLower(string(license))] {
+				if !spdx.Satisfies(string(license), actions.ScanLicensesAllowlist) {
 					pkg.LicenseViolations = append(pkg.LicenseViolations, license)
 				}
 			}
@@ -80,6 +92,10 @@ func buildVulnerabilityResults(
 		if actions.ScanLicensesSummary {
 			pkg.Licenses = licensesResp[i]
 		}
+		if yankedResp != nil && yankedResp[i] != nil {
+			includePackage = true
+			pkg.Yanked = yankedResp[i]
+		}
 		if includePackage {
 			groupedBySource[rawPkg.Source] = append(groupedBySource[rawPkg.Source], pkg)
 		}
@@ -93,13 +109,7 @@ func buildVulnerabilityResults(
 		})
 	}
 
-	sort.Slice(results.Results, func(i, j int) bool {
-		if results.Results[i].Source.Path == results.Results[j].Source.Path {
-			return results.Results[i].Source.Type < results.Results[j].Source.Type
-		}
-
-		return results.Results[i].Source.Path < results.Results[j].Source.Path
-	})
+	results.Sort()
 
 	if len(actions.ScanLicensesAllowlist) > 0 || actions.ScanLicensesSummary {
 		results.ExperimentalAnalysisConfig.Licenses.Summary = actions.ScanLicensesSummary
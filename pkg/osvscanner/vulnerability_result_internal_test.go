@@ -109,11 +109,11 @@ func Test_assembleResult(t *testing.T) {
 							},
 							Vulnerabilities: []models.Vulnerability{
 								{
-									ID:      "GHSA-123",
-									Aliases: []string{"CVE-123"},
+									ID: "CVE-123",
 								},
 								{
-									ID: "CVE-123",
+									ID:      "GHSA-123",
+									Aliases: []string{"CVE-123"},
 								},
 							},
 							Groups: []models.GroupInfo{
@@ -182,11 +182,11 @@ func Test_assembleResult(t *testing.T) {
 							},
 							Vulnerabilities: []models.Vulnerability{
 								{
-									ID:      "GHSA-123",
-									Aliases: []string{"CVE-123"},
+									ID: "CVE-123",
 								},
 								{
-									ID: "CVE-123",
+									ID:      "GHSA-123",
+									Aliases: []string{"CVE-123"},
 								},
 							},
 							Groups: []models.GroupInfo{
@@ -268,11 +268,11 @@ func Test_assembleResult(t *testing.T) {
 							},
 							Vulnerabilities: []models.Vulnerability{
 								{
-									ID:      "GHSA-123",
-									Aliases: []string{"CVE-123"},
+									ID: "CVE-123",
 								},
 								{
-									ID: "CVE-123",
+									ID:      "GHSA-123",
+									Aliases: []string{"CVE-123"},
 								},
 							},
 							Groups: []models.GroupInfo{
@@ -355,11 +355,11 @@ func Test_assembleResult(t *testing.T) {
 							},
 							Vulnerabilities: []models.Vulnerability{
 								{
-									ID:      "GHSA-123",
-									Aliases: []string{"CVE-123"},
+									ID: "CVE-123",
 								},
 								{
-									ID: "CVE-123",
+									ID:      "GHSA-123",
+									Aliases: []string{"CVE-123"},
 								},
 							},
 							Groups: []models.GroupInfo{
@@ -436,11 +436,11 @@ func Test_assembleResult(t *testing.T) {
 							},
 							Vulnerabilities: []models.Vulnerability{
 								{
-									ID:      "GHSA-123",
-									Aliases: []string{"CVE-123"},
+									ID: "CVE-123",
 								},
 								{
-									ID: "CVE-123",
+									ID:      "GHSA-123",
+									Aliases: []string{"CVE-123"},
 								},
 							},
 							Groups: []models.GroupInfo{
@@ -493,7 +493,7 @@ func Test_assembleResult(t *testing.T) {
 		tt := tt // Reinitialize for t.Parallel()
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			if got := buildVulnerabilityResults(tt.args.r, tt.args.packages, tt.args.vulnsResp, tt.args.licensesResp, tt.args.actions); !reflect.DeepEqual(got, tt.want) {
+			if got := buildVulnerabilityResults(tt.args.r, tt.args.packages, tt.args.vulnsResp, tt.args.licensesResp, nil, tt.args.actions); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("buildVulnerabilityResults() = %v,\nwant %v", got, tt.want)
 			}
 		})
@@ -0,0 +1,128 @@
+package osvscanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+	"github.com/google/osv-scanner/pkg/reporter"
+	"golang.org/x/sync/errgroup"
+)
+
+// the registries are variables so that they can be replaced when testing
+var (
+	cratesIOAPI = "https://crates.io/api/v1"
+	pypiAPI     = "https://pypi.org/pypi"
+)
+
+// maxYankedRequests is the number of registry requests that are made at once
+const maxYankedRequests = 10
+
+type cratesIOVersionResponse struct {
+	Version struct {
+		Yanked      bool   `json:"yanked"`
+		YankMessage string `json:"yank_message"`
+	} `json:"version"`
+}
+
+type pypiVersionResponse struct {
+	Info struct {
+		Yanked       bool   `json:"yanked"`
+		YankedReason string `json:"yanked_reason"`
+	} `json:"info"`
+}
+
+// getRegistryJSON decodes the response of the registry for the url into v
+func getRegistryJSON(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	// crates.io rejects requests that do not identify who is making them
+	if osv.RequestUserAgent != "" {
+		req.Header.Set("User-Agent", osv.RequestUserAgent)
+	} else {
+		req.Header.Set("User-Agent", "osv-scanner")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s", url, resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// checkYanked asks the registry of the package if its version has been yanked,
+// returning nil if it has not or if the registry does not support yanking versions
+func checkYanked(ctx context.Context, pkg scannedPackage) (*models.YankedVersion, error) {
+	switch pkg.Ecosystem {
+	case lockfile.CargoEcosystem:
+		var resp cratesIOVersionResponse
+		err := getRegistryJSON(ctx, cratesIOAPI+"/crates/"+url.PathEscape(pkg.Name)+"/"+url.PathEscape(pkg.Version), &resp)
+		if err != nil || !resp.Version.Yanked {
+			return nil, err
+		}
+
+		return &models.YankedVersion{Reason: resp.Version.YankMessage}, nil
+	case lockfile.PipEcosystem:
+		var resp pypiVersionResponse
+		err := getRegistryJSON(ctx, pypiAPI+"/"+url.PathEscape(pkg.Name)+"/"+url.PathEscape(pkg.Version)+"/json", &resp)
+		if err != nil || !resp.Info.Yanked {
+			return nil, err
+		}
+
+		return &models.YankedVersion{Reason: resp.Info.YankedReason}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// makeYankedRequests checks which of the packages have had their versions yanked from their
+// registries, which is currently only supported for crates.io and PyPI.
+//
+// Packages that cannot be checked are reported with a warning rather than failing the scan,
+// as a missing version is usually a private package.
+func makeYankedRequests(ctx context.Context, r reporter.Reporter, packages []scannedPackage) ([]*models.YankedVersion, error) {
+	yanked := make([]*models.YankedVersion, len(packages))
+	errs := make([]error, len(packages))
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(maxYankedRequests)
+
+	for i, pkg := range packages {
+		if pkg.Name == "" || pkg.Version == "" {
+			continue
+		}
+
+		i, pkg := i, pkg
+		g.Go(func() error {
+			yanked[i], errs[i] = checkYanked(ctx, pkg)
+
+			return ctx.Err()
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	for i, err := range errs {
+		if err != nil {
+			r.Warnf("Failed to check if %s@%s has been yanked: %v\n", packages[i].Name, packages[i].Version, err)
+		}
+	}
+
+	return yanked, nil
+}
@@ -0,0 +1,101 @@
+package osvscanner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/internal/testutility"
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+// the registries are replaced for the duration of the test, so it cannot be run in parallel
+func Test_makeYankedRequests(t *testing.T) {
+	srv := testutility.NewMockHTTPServer(t)
+	srv.SetResponse(t, "crates/smallvec/1.6.0", []byte(`{"version": {"num": "1.6.0", "yanked": true, "yank_message": "unsound"}}`))
+	srv.SetResponse(t, "crates/smallvec/1.13.2", []byte(`{"version": {"num": "1.13.2", "yanked": false, "yank_message": null}}`))
+	srv.SetResponse(t, "pypi/requests/2.32.0/json", []byte(`{"info": {"version": "2.32.0", "yanked": true, "yanked_reason": "conflicts with certifi"}}`))
+	srv.SetResponse(t, "pypi/requests/2.32.3/json", []byte(`{"info": {"version": "2.32.3", "yanked": false, "yanked_reason": null}}`))
+
+	originalCratesIOAPI, originalPyPIAPI := cratesIOAPI, pypiAPI
+	cratesIOAPI, pypiAPI = srv.URL, srv.URL+"/pypi"
+	t.Cleanup(func() { cratesIOAPI, pypiAPI = originalCratesIOAPI, originalPyPIAPI })
+
+	packages := []scannedPackage{
+		{Name: "smallvec", Version: "1.6.0", Ecosystem: lockfile.CargoEcosystem},
+		{Name: "smallvec", Version: "1.13.2", Ecosystem: lockfile.CargoEcosystem},
+		{Name: "requests", Version: "2.32.0", Ecosystem: lockfile.PipEcosystem},
+		{Name: "requests", Version: "2.32.3", Ecosystem: lockfile.PipEcosystem},
+		// not in the registry, such as a private package
+		{Name: "internal-tools", Version: "1.0.0", Ecosystem: lockfile.PipEcosystem},
+		// npm does not support yanking versions
+		{Name: "lodash", Version: "4.17.20", Ecosystem: lockfile.NpmEcosystem},
+	}
+
+	got, err := makeYankedRequests(context.Background(), &reporter.VoidReporter{}, packages)
+	if err != nil {
+		t.Fatalf("makeYankedRequests() error = %v", err)
+	}
+
+	want := []*models.YankedVersion{
+		{Reason: "unsound"},
+		nil,
+		{Reason: "conflicts with certifi"},
+		nil,
+		nil,
+		nil,
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("makeYankedRequests() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_buildVulnerabilityResults_YankedAndWithdrawn(t *testing.T) {
+	t.Parallel()
+
+	source := models.SourceInfo{Path: "dir/Cargo.lock", Type: "lockfile"}
+	packages := []scannedPackage{
+		{Name: "smallvec", Version: "1.6.0", Ecosystem: lockfile.CargoEcosystem, Source: source},
+		{Name: "time", Version: "0.1.43", Ecosystem: lockfile.CargoEcosystem, Source: source},
+		{Name: "serde", Version: "1.0.0", Ecosystem: lockfile.CargoEcosystem, Source: source},
+	}
+	vulnsResp := &osv.HydratedBatchedResponse{
+		Results: []osv.Response{
+			{},
+			{Vulns: models.Vulnerabilities{
+				{ID: "RUSTSEC-2020-0071", Withdrawn: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+			}},
+			{},
+		},
+	}
+	yankedResp := []*models.YankedVersion{{Reason: "unsound"}, nil, nil}
+
+	got := buildVulnerabilityResults(&reporter.VoidReporter{}, packages, vulnsResp, nil, yankedResp, ScannerActions{})
+
+	want := models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: source,
+				Packages: []models.PackageVulns{
+					{
+						Package: models.PackageInfo{Name: "smallvec", Version: "1.6.0", Ecosystem: "crates.io"},
+						Yanked:  &models.YankedVersion{Reason: "unsound"},
+					},
+					{
+						Package:   models.PackageInfo{Name: "time", Version: "0.1.43", Ecosystem: "crates.io"},
+						Withdrawn: []string{"RUSTSEC-2020-0071"},
+					},
+				},
+			},
+		},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("buildVulnerabilityResults() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,60 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// CSAFReporter prints the vulnerabilities in the results as a CSAF 2.0 VEX document,
+// so that they can be consumed by tooling that supports CSAF
+type CSAFReporter struct {
+	hasErrored bool
+	stdout     io.Writer
+	stderr     io.Writer
+	level      VerbosityLevel
+}
+
+func NewCSAFReporter(stdout io.Writer, stderr io.Writer, level VerbosityLevel) *CSAFReporter {
+	return &CSAFReporter{
+		stdout:     stdout,
+		stderr:     stderr,
+		level:      level,
+		hasErrored: false,
+	}
+}
+
+func (r *CSAFReporter) Errorf(format string, a ...any) {
+	fmt.Fprintf(r.stderr, format, a...)
+	r.hasErrored = true
+}
+
+func (r *CSAFReporter) HasErrored() bool {
+	return r.hasErrored
+}
+
+func (r *CSAFReporter) Warnf(format string, a ...any) {
+	if WarnLevel <= r.level {
+		fmt.Fprintf(r.stderr, format, a...)
+	}
+}
+
+func (r *CSAFReporter) Infof(format string, a ...any) {
+	if InfoLevel <= r.level {
+		fmt.Fprintf(r.stderr, format, a...)
+	}
+}
+
+func (r *CSAFReporter) Verbosef(format string, a ...any) {
+	if VerboseLevel <= r.level {
+		fmt.Fprintf(r.stderr, format, a...)
+	}
+}
+
+func (r *CSAFReporter) PrintResult(vulnResult *models.VulnerabilityResults) error {
+	vulnResult.Sort()
+
+	return output.PrintCSAFReport(vulnResult, r.stdout)
+}
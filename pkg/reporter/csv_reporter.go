@@ -0,0 +1,77 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// CSVOptions customizes the columns printed by the CSVReporter
+type CSVOptions struct {
+	// Columns are the columns to print in order, which are all of CSVColumns if empty
+	Columns []string
+}
+
+// CSVColumns returns the columns that can be printed by the CSVReporter, in their default order
+func CSVColumns() []string {
+	return output.CSVColumns()
+}
+
+// CSVReporter prints the vulnerability results as comma-separated values,
+// with a row for each vulnerability of every package, such as for spreadsheets
+type CSVReporter struct {
+	hasErrored bool
+	stdout     io.Writer
+	stderr     io.Writer
+	level      VerbosityLevel
+	options    CSVOptions
+}
+
+func NewCSVReporter(stdout io.Writer, stderr io.Writer, level VerbosityLevel) *CSVReporter {
+	return NewCSVReporterWithOptions(stdout, stderr, level, CSVOptions{})
+}
+
+func NewCSVReporterWithOptions(stdout io.Writer, stderr io.Writer, level VerbosityLevel, options CSVOptions) *CSVReporter {
+	return &CSVReporter{
+		stdout:     stdout,
+		stderr:     stderr,
+		level:      level,
+		hasErrored: false,
+		options:    options,
+	}
+}
+
+func (r *CSVReporter) Errorf(format string, a ...any) {
+	fmt.Fprintf(r.stderr, format, a...)
+	r.hasErrored = true
+}
+
+func (r *CSVReporter) HasErrored() bool {
+	return r.hasErrored
+}
+
+func (r *CSVReporter) Warnf(format string, a ...any) {
+	if WarnLevel <= r.level {
+		fmt.Fprintf(r.stderr, format, a...)
+	}
+}
+
+func (r *CSVReporter) Infof(format string, a ...any) {
+	if InfoLevel <= r.level {
+		fmt.Fprintf(r.stderr, format, a...)
+	}
+}
+
+func (r *CSVReporter) Verbosef(format string, a ...any) {
+	if VerboseLevel <= r.level {
+		fmt.Fprintf(r.stderr, format, a...)
+	}
+}
+
+func (r *CSVReporter) PrintResult(vulnResult *models.VulnerabilityResults) error {
+	vulnResult.Sort()
+
+	return output.PrintCSVReport(vulnResult, r.stdout, r.options.Columns)
+}
@@ -5,26 +5,76 @@ import (
 	"io"
 )
 
-var format = []string{"table", "json", "markdown", "sarif", "gh-annotations"}
+var format = []string{"table", "json", "markdown", "sarif", "gh-annotations", "junit", "html", "spdx-2-3", "csv", "openvex", "csaf-2-0"}
 
 func Format() []string {
 	return format
 }
 
+var fileNames = map[string]string{
+	"table":          "results.txt",
+	"json":           "results.json",
+	"markdown":       "results.md",
+	"sarif":          "results.sarif",
+	"gh-annotations": "results-gh-annotations.txt",
+	"junit":          "results-junit.xml",
+	"html":           "results.html",
+	"spdx-2-3":       "results.spdx.json",
+	"csv":            "results.csv",
+	"openvex":        "results.openvex.json",
+	"csaf-2-0":       "results.csaf.json",
+}
+
+// FileName returns the name of the file that results in the given format
+// should be written to when writing them to a directory
+func FileName(format string) (string, error) {
+	name, ok := fileNames[format]
+	if !ok {
+		return "", fmt.Errorf("%v is not a valid format", format)
+	}
+
+	return name, nil
+}
+
+// Options customizes the output of the reporters that support it
+type Options struct {
+	HTML  HTMLOptions
+	Table TableOptions
+	CSV   CSVOptions
+}
+
 // New returns an implementation of the reporter interface depending on the format passed in
 // set terminalWidth as 0 to indicate the output is not a terminal
 func New(format string, stdout, stderr io.Writer, level VerbosityLevel, terminalWidth int) (Reporter, error) {
+	return NewWithOptions(format, stdout, stderr, level, terminalWidth, Options{})
+}
+
+// NewWithOptions returns an implementation of the reporter interface like New,
+// with the output of the reporter customized by the options
+func NewWithOptions(format string, stdout, stderr io.Writer, level VerbosityLevel, terminalWidth int, options Options) (Reporter, error) {
 	switch format {
 	case "json":
 		return NewJSONReporter(stdout, stderr, level), nil
 	case "table":
-		return NewTableReporter(stdout, stderr, level, false, terminalWidth), nil
+		return NewTableReporterWithOptions(stdout, stderr, level, false, terminalWidth, options.Table), nil
 	case "markdown":
-		return NewTableReporter(stdout, stderr, level, true, terminalWidth), nil
+		return NewTableReporterWithOptions(stdout, stderr, level, true, terminalWidth, options.Table), nil
 	case "sarif":
 		return NewSarifReporter(stdout, stderr, level), nil
 	case "gh-annotations":
 		return NewGHAnnotationsReporter(stdout, stderr, level), nil
+	case "junit":
+		return NewJUnitReporter(stdout, stderr, level), nil
+	case "html":
+		return NewHTMLReporterWithOptions(stdout, stderr, level, options.HTML), nil
+	case "spdx-2-3":
+		return NewSPDXReporter(stdout, stderr, level), nil
+	case "csv":
+		return NewCSVReporterWithOptions(stdout, stderr, level, options.CSV), nil
+	case "openvex":
+		return NewOpenVEXReporter(stdout, stderr, level), nil
+	case "csaf-2-0":
+		return NewCSAFReporter(stdout, stderr, level), nil
 	default:
 		return nil, fmt.Errorf("%v is not a valid format", format)
 	}
@@ -0,0 +1,82 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// HTMLOptions customizes the branding of the report printed by the HTMLReporter
+type HTMLOptions struct {
+	// Title replaces "OSV-Scanner report" as the title and heading of the report
+	Title string
+	// Header is a blurb that is shown under the heading
+	Header string
+	// ScannedAt is when the scan ran, which is only shown if it is not zero
+	ScannedAt time.Time
+	// Rescan shows a button for re-running the scan, for reports that are being served
+	Rescan bool
+}
+
+type HTMLReporter struct {
+	hasErrored bool
+	stdout     io.Writer
+	stderr     io.Writer
+	level      VerbosityLevel
+	options    HTMLOptions
+}
+
+func NewHTMLReporter(stdout io.Writer, stderr io.Writer, level VerbosityLevel) *HTMLReporter {
+	return NewHTMLReporterWithOptions(stdout, stderr, level, HTMLOptions{})
+}
+
+func NewHTMLReporterWithOptions(stdout io.Writer, stderr io.Writer, level VerbosityLevel, options HTMLOptions) *HTMLReporter {
+	return &HTMLReporter{
+		stdout:     stdout,
+		stderr:     stderr,
+		level:      level,
+		hasErrored: false,
+		options:    options,
+	}
+}
+
+func (r *HTMLReporter) Errorf(format string, a ...any) {
+	fmt.Fprintf(r.stderr, format, a...)
+	r.hasErrored = true
+}
+
+func (r *HTMLReporter) HasErrored() bool {
+	return r.hasErrored
+}
+
+func (r *HTMLReporter) Warnf(format string, a ...any) {
+	if WarnLevel <= r.level {
+		fmt.Fprintf(r.stderr, format, a...)
+	}
+}
+
+func (r *HTMLReporter) Infof(format string, a ...any) {
+	if InfoLevel <= r.level {
+		fmt.Fprintf(r.stderr, format, a...)
+	}
+}
+
+func (r *HTMLReporter) Verbosef(format string, a ...any) {
+	if VerboseLevel <= r.level {
+		fmt.Fprintf(r.stderr, format, a...)
+	}
+}
+
+func (r *HTMLReporter) PrintResult(vulnResult *models.VulnerabilityResults) error {
+	vulnResult.Sort()
+
+	return output.PrintHTMLReportWithOptions(vulnResult, r.stdout, output.HTMLOptions{
+		Title:     r.options.Title,
+		Header:    r.options.Header,
+		ScannedAt: r.options.ScannedAt,
+		Rescan:    r.options.Rescan,
+	})
+}
@@ -0,0 +1,74 @@
+package reporter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// HTTPReporter sends vulnerability results in JSON format as the body of a POST request
+// to a URL, such as a webhook or an internal collector. Runtime information
+// will be written to stderr.
+type HTTPReporter struct {
+	*JSONReporter
+	url     string
+	header  http.Header
+	timeout time.Duration
+}
+
+// NewHTTPReporter creates a reporter that POSTs the results to url with the given
+// headers, giving up on the request if it takes longer than timeout (if timeout is not zero).
+//
+// Requests are made with http.DefaultClient, so respect the proxy environment variables.
+func NewHTTPReporter(url string, header http.Header, timeout time.Duration, stderr io.Writer, level VerbosityLevel) *HTTPReporter {
+	return &HTTPReporter{
+		JSONReporter: NewJSONReporter(io.Discard, stderr, level),
+		url:          url,
+		header:       header,
+		timeout:      timeout,
+	}
+}
+
+func (r *HTTPReporter) PrintResult(vulnResult *models.VulnerabilityResults) error {
+	vulnResult.Sort()
+
+	var body bytes.Buffer
+	if err := output.PrintJSONResults(vulnResult, &body); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.url, &body)
+	if err != nil {
+		return fmt.Errorf("failed to make new request: %w", err)
+	}
+
+	for name, values := range r.header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send results: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("failed to send results to %s: %s", r.url, resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package reporter_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+func TestHTTPReporter_PrintResult(t *testing.T) {
+	t.Parallel()
+
+	var got models.VulnerabilityResults
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			t.Errorf("expected a POST request, got %s", req.Method)
+		}
+		if auth := req.Header.Get("Authorization"); auth != "Bearer token" {
+			t.Errorf("expected Authorization header to be sent, got \"%s\"", auth)
+		}
+		if err := json.NewDecoder(req.Body).Decode(&got); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer token")
+	r := reporter.NewHTTPReporter(srv.URL, header, time.Minute, io.Discard, reporter.InfoLevel)
+
+	err := r.PrintResult(&models.VulnerabilityResults{
+		Results: []models.PackageSource{{Source: models.SourceInfo{Path: "/path/to/lockfile", Type: "lockfile"}}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(got.Results) != 1 || got.Results[0].Source.Path != "/path/to/lockfile" {
+		t.Errorf("expected the results to be sent, got %v", got)
+	}
+}
+
+func TestHTTPReporter_PrintResult_Non2xx(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	r := reporter.NewHTTPReporter(srv.URL, http.Header{}, time.Minute, io.Discard, reporter.InfoLevel)
+
+	if err := r.PrintResult(&models.VulnerabilityResults{}); err == nil {
+		t.Error("expected an error when the server does not accept the results")
+	}
+}
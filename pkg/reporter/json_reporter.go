@@ -15,6 +15,7 @@ type JSONReporter struct {
 	stdout     io.Writer
 	stderr     io.Writer
 	level      VerbosityLevel
+	progress   progressTracker
 }
 
 func NewJSONReporter(stdout io.Writer, stderr io.Writer, level VerbosityLevel) *JSONReporter {
@@ -53,6 +54,16 @@ func (r *JSONReporter) Verbosef(format string, a ...any) {
 	}
 }
 
+// Progressf logs the progress to stderr periodically like Infof, as the results are not
+// printed to a terminal
+func (r *JSONReporter) Progressf(done, total int, format string) {
+	if InfoLevel <= r.level {
+		r.progress.log(Logger(r), done, total, format)
+	}
+}
+
 func (r *JSONReporter) PrintResult(vulnResult *models.VulnerabilityResults) error {
+	vulnResult.Sort()
+
 	return output.PrintJSONResults(vulnResult, r.stdout)
 }
@@ -0,0 +1,60 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// JUnitReporter prints the vulnerability results as a JUnit XML report,
+// so that they can be shown in dashboards for test results
+type JUnitReporter struct {
+	hasErrored bool
+	stdout     io.Writer
+	stderr     io.Writer
+	level      VerbosityLevel
+}
+
+func NewJUnitReporter(stdout io.Writer, stderr io.Writer, level VerbosityLevel) *JUnitReporter {
+	return &JUnitReporter{
+		stdout:     stdout,
+		stderr:     stderr,
+		level:      level,
+		hasErrored: false,
+	}
+}
+
+func (r *JUnitReporter) Errorf(format string, a ...any) {
+	fmt.Fprintf(r.stderr, format, a...)
+	r.hasErrored = true
+}
+
+func (r *JUnitReporter) HasErrored() bool {
+	return r.hasErrored
+}
+
+func (r *JUnitReporter) Warnf(format string, a ...any) {
+	if WarnLevel <= r.level {
+		fmt.Fprintf(r.stderr, format, a...)
+	}
+}
+
+func (r *JUnitReporter) Infof(format string, a ...any) {
+	if InfoLevel <= r.level {
+		fmt.Fprintf(r.stderr, format, a...)
+	}
+}
+
+func (r *JUnitReporter) Verbosef(format string, a ...any) {
+	if VerboseLevel <= r.level {
+		fmt.Fprintf(r.stderr, format, a...)
+	}
+}
+
+func (r *JUnitReporter) PrintResult(vulnResult *models.VulnerabilityResults) error {
+	vulnResult.Sort()
+
+	return output.PrintJUnitReport(vulnResult, r.stdout)
+}
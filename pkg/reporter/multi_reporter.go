@@ -0,0 +1,63 @@
+package reporter
+
+import (
+	"errors"
+	"log/slog"
+
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// MultiReporter prints runtime information with one reporter, and prints vulnerability
+// results with each of a set of other reporters, so that results can be written in
+// several formats at once without duplicating the runtime information.
+type MultiReporter struct {
+	runtime Reporter
+	results []Reporter
+}
+
+func NewMultiReporter(runtime Reporter, results ...Reporter) *MultiReporter {
+	return &MultiReporter{
+		runtime: runtime,
+		results: results,
+	}
+}
+
+func (r *MultiReporter) Errorf(format string, a ...any) {
+	r.runtime.Errorf(format, a...)
+}
+
+func (r *MultiReporter) HasErrored() bool {
+	return r.runtime.HasErrored()
+}
+
+func (r *MultiReporter) Warnf(format string, a ...any) {
+	r.runtime.Warnf(format, a...)
+}
+
+func (r *MultiReporter) Infof(format string, a ...any) {
+	r.runtime.Infof(format, a...)
+}
+
+func (r *MultiReporter) Verbosef(format string, a ...any) {
+	r.runtime.Verbosef(format, a...)
+}
+
+func (r *MultiReporter) Progressf(done, total int, format string) {
+	Progress(r.runtime, done, total, format)
+}
+
+// Logger returns the logger of the runtime reporter, so that structured
+// diagnostics keep their attributes if it is a StructuredReporter
+func (r *MultiReporter) Logger() *slog.Logger {
+	return Logger(r.runtime)
+}
+
+// PrintResult prints the results with every reporter, even if some of them fail
+func (r *MultiReporter) PrintResult(vulnResult *models.VulnerabilityResults) error {
+	var errs []error
+	for _, reporter := range r.results {
+		errs = append(errs, reporter.PrintResult(vulnResult))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,87 @@
+package reporter_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+func TestMultiReporter(t *testing.T) {
+	t.Parallel()
+
+	runtime := &bytes.Buffer{}
+	jsonOut := &bytes.Buffer{}
+	markdownOut := &bytes.Buffer{}
+
+	r := reporter.NewMultiReporter(
+		reporter.NewTableReporter(runtime, runtime, reporter.InfoLevel, false, 0),
+		reporter.NewJSONReporter(jsonOut, io.Discard, reporter.InfoLevel),
+		reporter.NewTableReporter(markdownOut, io.Discard, reporter.InfoLevel, true, 0),
+	)
+
+	r.Infof("hello world!")
+
+	if runtime.String() != "hello world!" {
+		t.Errorf("expected runtime information to be printed once, got \"%s\"", runtime.String())
+	}
+	if jsonOut.Len() != 0 || markdownOut.Len() != 0 {
+		t.Error("runtime information should not have been printed with the results")
+	}
+
+	err := r.PrintResult(&models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "/path/to/go.mod", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package: models.PackageInfo{Name: "golang.org/x/net", Version: "0.1.0", Ecosystem: "Go"},
+						Groups:  []models.GroupInfo{{IDs: []string{"GO-2022-1144"}, MaxSeverity: "7.5"}},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !json.Valid(jsonOut.Bytes()) {
+		t.Errorf("expected the results to be printed as json, got \"%s\"", jsonOut.String())
+	}
+	if !strings.Contains(markdownOut.String(), "GO-2022-1144") {
+		t.Errorf("expected the results to be printed as markdown, got \"%s\"", markdownOut.String())
+	}
+
+	if r.HasErrored() {
+		t.Error("HasErrored() should have returned false")
+	}
+	r.Errorf("oh no")
+	if !r.HasErrored() {
+		t.Error("HasErrored() should have returned true")
+	}
+}
+
+func TestFileName(t *testing.T) {
+	t.Parallel()
+
+	seen := map[string]bool{}
+	for _, format := range reporter.Format() {
+		name, err := reporter.FileName(format)
+		if err != nil {
+			t.Errorf("expected %s to have a file name: %v", format, err)
+		}
+		if seen[name] {
+			t.Errorf("%s has the same file name as another format: %s", format, name)
+		}
+		seen[name] = true
+	}
+
+	if _, err := reporter.FileName("unknown"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,60 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// OpenVEXReporter prints the vulnerabilities in the results as an OpenVEX document,
+// so that they can be triaged by changing the status of its statements
+type OpenVEXReporter struct {
+	hasErrored bool
+	stdout     io.Writer
+	stderr     io.Writer
+	level      VerbosityLevel
+}
+
+func NewOpenVEXReporter(stdout io.Writer, stderr io.Writer, level VerbosityLevel) *OpenVEXReporter {
+	return &OpenVEXReporter{
+		stdout:     stdout,
+		stderr:     stderr,
+		level:      level,
+		hasErrored: false,
+	}
+}
+
+func (r *OpenVEXReporter) Errorf(format string, a ...any) {
+	fmt.Fprintf(r.stderr, format, a...)
+	r.hasErrored = true
+}
+
+func (r *OpenVEXReporter) HasErrored() bool {
+	return r.hasErrored
+}
+
+func (r *OpenVEXReporter) Warnf(format string, a ...any) {
+	if WarnLevel <= r.level {
+		fmt.Fprintf(r.stderr, format, a...)
+	}
+}
+
+func (r *OpenVEXReporter) Infof(format string, a ...any) {
+	if InfoLevel <= r.level {
+		fmt.Fprintf(r.stderr, format, a...)
+	}
+}
+
+func (r *OpenVEXReporter) Verbosef(format string, a ...any) {
+	if VerboseLevel <= r.level {
+		fmt.Fprintf(r.stderr, format, a...)
+	}
+}
+
+func (r *OpenVEXReporter) PrintResult(vulnResult *models.VulnerabilityResults) error {
+	vulnResult.Sort()
+
+	return output.PrintOpenVEXReport(vulnResult, r.stdout)
+}
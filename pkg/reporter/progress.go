@@ -0,0 +1,154 @@
+package reporter
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// ProgressReporter is a Reporter that can show the progress of long-running operations,
+// either on a single line when it is printing to a terminal or otherwise periodically in its logs.
+type ProgressReporter interface {
+	Reporter
+	// Progressf shows how many of the total items an operation has completed so far,
+	// with the text describing the operation, such as "Resolved %d/%d dependencies".
+	//
+	// If the total is UnknownTotal the text only has the one verb for how many items
+	// have been completed, such as "Searched %d files".
+	//
+	// The progress is cleared once done is equal to total.
+	Progressf(done, total int, format string)
+}
+
+// UnknownTotal is the total of operations that do not know how many items they have until
+// they are finished, such as walking a directory, which are finished by giving done as the total
+const UnknownTotal = -1
+
+// Progress shows the progress of an operation if the reporter can
+func Progress(r Reporter, done, total int, format string) {
+	if pr, ok := r.(ProgressReporter); ok {
+		pr.Progressf(done, total, format)
+	}
+}
+
+// progressLogInterval is how often the progress of an operation is logged when it cannot be
+// shown on a terminal, so that long scans do not look like they have hung in CI logs
+const progressLogInterval = 10 * time.Second
+
+// progressBarWidth is how many characters wide the bar of the progress shown on terminals is
+const progressBarWidth = 20
+
+// progressTracker times the operations that a reporter is showing the progress of,
+// to estimate how long is left of them and to decide when to log their progress
+type progressTracker struct {
+	// now is time.Now outside of tests
+	now func() time.Time
+
+	format string
+	start  time.Time
+	// logged is when the progress of the operation was last logged, if it has been
+	logged time.Time
+	// unknownTotal is whether the operation did not know its total before it finished
+	unknownTotal bool
+}
+
+// update starts timing a new operation if the progress is not from the current one,
+// which is the case for any progress that starts from zero or has a different format
+func (p *progressTracker) update(done, total int, format string) {
+	if p.now == nil {
+		p.now = time.Now
+	}
+
+	if done == 0 || format != p.format || p.start.IsZero() {
+		p.finish()
+		p.format = format
+		p.start = p.now()
+	}
+	if total == UnknownTotal {
+		p.unknownTotal = true
+	}
+}
+
+// finish stops timing the operation, so that any more progress with its format is a new one
+func (p *progressTracker) finish() {
+	p.format = ""
+	p.start = time.Time{}
+	p.logged = time.Time{}
+	p.unknownTotal = false
+}
+
+// eta estimates how long is left of the operation from how long it has taken so far,
+// which is only done once it has been running for long enough to be a useful estimate
+func (p *progressTracker) eta(done, total int) (time.Duration, bool) {
+	elapsed := p.now().Sub(p.start)
+	if done <= 0 || total < done || elapsed < time.Second {
+		return 0, false
+	}
+
+	return (elapsed * time.Duration(total-done) / time.Duration(done)).Round(time.Second), true
+}
+
+// log records the progress with the logger every progressLogInterval, along with when the
+// operation finishes if its progress was logged at all, so that short operations are not logged
+func (p *progressTracker) log(logger *slog.Logger, done, total int, format string) {
+	p.update(done, total, format)
+
+	if total != UnknownTotal && done >= total {
+		if !p.logged.IsZero() && p.unknownTotal {
+			logger.Info(formatProgress(done, UnknownTotal, format), "done", done)
+		} else if !p.logged.IsZero() {
+			logger.Info(formatProgress(done, total, format), "done", done, "total", total)
+		}
+		p.finish()
+
+		return
+	}
+
+	last := p.logged
+	if last.IsZero() {
+		last = p.start
+	}
+	if p.now().Sub(last) < progressLogInterval {
+		return
+	}
+	p.logged = p.now()
+
+	attrs := []any{"done", done}
+	if total != UnknownTotal {
+		attrs = append(attrs, "total", total)
+	}
+	if eta, ok := p.eta(done, total); ok {
+		attrs = append(attrs, "eta", eta)
+	}
+
+	logger.Info(formatProgress(done, total, format), attrs...)
+}
+
+// line returns the progress as a single line for a terminal, with a bar and an estimate
+// of how long is left if the total is known
+func (p *progressTracker) line(done, total int, format string) string {
+	p.update(done, total, format)
+
+	if total == UnknownTotal {
+		return formatProgress(done, total, format)
+	}
+
+	filled := progressBarWidth * done / total
+	line := "[" + strings.Repeat("=", filled) + ">" + strings.Repeat(" ", progressBarWidth-filled-1) + "] " +
+		formatProgress(done, total, format)
+
+	if eta, ok := p.eta(done, total); ok {
+		line += fmt.Sprintf(" (%s left)", eta)
+	}
+
+	return line
+}
+
+func formatProgress(done, total int, format string) string {
+	if total == UnknownTotal {
+		return fmt.Sprintf(format, done)
+	}
+
+	return fmt.Sprintf(format, done, total)
+}
@@ -0,0 +1,135 @@
+package reporter
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeClock is a clock for progressTracker that only moves when it is told to
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestLogger(writer *bytes.Buffer) *slog.Logger {
+	return slog.New(slog.NewTextHandler(writer, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+
+			return a
+		},
+	}))
+}
+
+func TestProgressTracker_Log(t *testing.T) {
+	t.Parallel()
+
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	p := &progressTracker{now: clock.Now}
+	writer := &bytes.Buffer{}
+	logger := newTestLogger(writer)
+
+	// operations that finish quickly are never logged
+	p.log(logger, 0, 10, "Fetched %d/%d vulnerabilities")
+	clock.Advance(time.Second)
+	p.log(logger, 10, 10, "Fetched %d/%d vulnerabilities")
+
+	p.log(logger, 0, 3000, "Queried OSV for %d/%d packages")
+	clock.Advance(5 * time.Second)
+	p.log(logger, 1000, 3000, "Queried OSV for %d/%d packages")
+	clock.Advance(5 * time.Second)
+	p.log(logger, 2000, 3000, "Queried OSV for %d/%d packages")
+	clock.Advance(5 * time.Second)
+	p.log(logger, 3000, 3000, "Queried OSV for %d/%d packages")
+
+	p.log(logger, 100, UnknownTotal, "Searched %d files")
+	clock.Advance(11 * time.Second)
+	p.log(logger, 200, UnknownTotal, "Searched %d files")
+	p.log(logger, 250, 250, "Searched %d files")
+
+	want := `level=INFO msg="Queried OSV for 2000/3000 packages" done=2000 total=3000 eta=5s
+level=INFO msg="Queried OSV for 3000/3000 packages" done=3000 total=3000
+level=INFO msg="Searched 200 files" done=200
+level=INFO msg="Searched 250 files" done=250
+`
+
+	if diff := cmp.Diff(want, writer.String()); diff != "" {
+		t.Errorf("progressTracker.log() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestProgressTracker_Line(t *testing.T) {
+	t.Parallel()
+
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	p := &progressTracker{now: clock.Now}
+
+	tests := []struct {
+		name    string
+		advance time.Duration
+		done    int
+		total   int
+		format  string
+		want    string
+	}{
+		{
+			name:   "start of an operation",
+			done:   0,
+			total:  2000,
+			format: "Fetched %d/%d vulnerabilities",
+			want:   "[>                   ] Fetched 0/2000 vulnerabilities",
+		},
+		{
+			name:    "too soon to estimate",
+			advance: 500 * time.Millisecond,
+			done:    100,
+			total:   2000,
+			format:  "Fetched %d/%d vulnerabilities",
+			want:    "[=>                  ] Fetched 100/2000 vulnerabilities",
+		},
+		{
+			name:    "estimated",
+			advance: 29*time.Second + 500*time.Millisecond,
+			done:    500,
+			total:   2000,
+			format:  "Fetched %d/%d vulnerabilities",
+			want:    "[=====>              ] Fetched 500/2000 vulnerabilities (1m30s left)",
+		},
+		{
+			name:    "new operation",
+			advance: time.Minute,
+			done:    1,
+			total:   4,
+			format:  "Resolved %d/%d requirements",
+			want:    "[=====>              ] Resolved 1/4 requirements",
+		},
+		{
+			name:   "unknown total",
+			done:   300,
+			total:  UnknownTotal,
+			format: "Searched %d files",
+			want:   "Searched 300 files",
+		},
+	}
+
+	for _, tt := range tests {
+		clock.Advance(tt.advance)
+
+		if got := p.line(tt.done, tt.total, tt.format); got != tt.want {
+			t.Errorf("%s: progressTracker.line() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
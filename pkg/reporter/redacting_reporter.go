@@ -0,0 +1,108 @@
+package reporter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// RedactingReporter prints vulnerability results with another reporter after replacing the names
+// of the packages that match a pattern with a token, so that results can be shared without
+// revealing internal packages while their vulnerabilities stay visible.
+//
+// The token is derived from the name, so a package has the same token everywhere in the results.
+type RedactingReporter struct {
+	Reporter
+	pattern *regexp.Regexp
+}
+
+func NewRedactingReporter(r Reporter, pattern *regexp.Regexp) *RedactingReporter {
+	return &RedactingReporter{
+		Reporter: r,
+		pattern:  pattern,
+	}
+}
+
+func (r *RedactingReporter) Progressf(done, total int, format string) {
+	Progress(r.Reporter, done, total, format)
+}
+
+func (r *RedactingReporter) Logger() *slog.Logger {
+	return Logger(r.Reporter)
+}
+
+// PrintResult prints a redacted copy of the results, leaving the results themselves unchanged
+func (r *RedactingReporter) PrintResult(vulnResult *models.VulnerabilityResults) error {
+	redacted := *vulnResult
+	redacted.Results = make([]models.PackageSource, 0, len(vulnResult.Results))
+
+	for _, res := range vulnResult.Results {
+		packages := make([]models.PackageVulns, 0, len(res.Packages))
+		for _, pkg := range res.Packages {
+			packages = append(packages, r.redactPackage(pkg))
+		}
+		redacted.Results = append(redacted.Results, models.PackageSource{
+			Source:   res.Source,
+			Packages: packages,
+		})
+	}
+
+	return r.Reporter.PrintResult(&redacted)
+}
+
+// redactName returns the token for the name if it matches the pattern, or the name otherwise
+func (r *RedactingReporter) redactName(name string) string {
+	if name == "" || !r.pattern.MatchString(name) {
+		return name
+	}
+
+	hash := sha256.Sum256([]byte(name))
+
+	return "redacted-" + hex.EncodeToString(hash[:6])
+}
+
+func (r *RedactingReporter) redactPackage(pkg models.PackageVulns) models.PackageVulns {
+	pkg.Package.Name = r.redactName(pkg.Package.Name)
+
+	if pkg.DependencyPaths != nil {
+		paths := make([][]string, 0, len(pkg.DependencyPaths))
+		for _, path := range pkg.DependencyPaths {
+			redactedPath := make([]string, 0, len(path))
+			for _, dep := range path {
+				// the version comes after the last "@", as scoped npm packages start with one
+				if i := strings.LastIndex(dep, "@"); i > 0 {
+					dep = r.redactName(dep[:i]) + dep[i:]
+				} else {
+					dep = r.redactName(dep)
+				}
+				redactedPath = append(redactedPath, dep)
+			}
+			paths = append(paths, redactedPath)
+		}
+		pkg.DependencyPaths = paths
+	}
+
+	if pkg.IntroducedBy != nil {
+		pkg.IntroducedBy = slices.Clone(pkg.IntroducedBy)
+		for i := range pkg.IntroducedBy {
+			pkg.IntroducedBy[i].Name = r.redactName(pkg.IntroducedBy[i].Name)
+		}
+	}
+
+	// advisories can name the package too, such as for malicious packages
+	pkg.Vulnerabilities = slices.Clone(pkg.Vulnerabilities)
+	for i, vuln := range pkg.Vulnerabilities {
+		affected := slices.Clone(vuln.Affected)
+		for j := range affected {
+			affected[j].Package.Name = r.redactName(affected[j].Package.Name)
+		}
+		pkg.Vulnerabilities[i].Affected = affected
+	}
+
+	return pkg
+}
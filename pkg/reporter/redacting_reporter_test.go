@@ -0,0 +1,103 @@
+package reporter_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+// resultRecorder records the results that it is asked to print
+type resultRecorder struct {
+	reporter.VoidReporter
+	printed *models.VulnerabilityResults
+}
+
+func (r *resultRecorder) PrintResult(vulnResult *models.VulnerabilityResults) error {
+	r.printed = vulnResult
+
+	return nil
+}
+
+func TestRedactingReporter(t *testing.T) {
+	t.Parallel()
+
+	results := &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "/path/to/package-lock.json", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package: models.PackageInfo{Name: "@acme/billing", Version: "1.2.3", Ecosystem: "npm"},
+						Vulnerabilities: []models.Vulnerability{
+							{
+								ID:       "MAL-2024-1",
+								Affected: []models.Affected{{Package: models.Package{Name: "@acme/billing", Ecosystem: "npm"}}},
+							},
+						},
+						Groups:          []models.GroupInfo{{IDs: []string{"MAL-2024-1"}, MaxSeverity: "9.8"}},
+						DependencyPaths: [][]string{{"@acme/app@2.0.0", "@acme/billing@1.2.3"}},
+						IntroducedBy:    []models.DirectDependency{{Name: "@acme/app", Version: "2.0.0", Line: 5}},
+					},
+					{
+						Package: models.PackageInfo{Name: "lodash", Version: "4.17.19", Ecosystem: "npm"},
+						Groups:  []models.GroupInfo{{IDs: []string{"GHSA-p6mc-m468-83gw"}, MaxSeverity: "7.4"}},
+					},
+				},
+			},
+		},
+	}
+
+	recorder := &resultRecorder{}
+	r := reporter.NewRedactingReporter(recorder, regexp.MustCompile(`^@acme/`))
+
+	if err := r.PrintResult(results); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	redacted := recorder.printed.Results[0].Packages
+	billing := redacted[0].Package.Name
+
+	if !strings.HasPrefix(billing, "redacted-") {
+		t.Errorf("expected @acme/billing to be redacted, got %q", billing)
+	}
+	if got := redacted[0].Vulnerabilities[0].Affected[0].Package.Name; got != billing {
+		t.Errorf("expected the affected package to be redacted to %q, got %q", billing, got)
+	}
+	if got := redacted[0].DependencyPaths[0][1]; got != billing+"@1.2.3" {
+		t.Errorf("expected the dependency path to be redacted to %q, got %q", billing+"@1.2.3", got)
+	}
+	if got := redacted[0].IntroducedBy[0].Name; got+"@2.0.0" != redacted[0].DependencyPaths[0][0] {
+		t.Errorf("expected the direct dependency to be redacted like its dependency path, got %q", got)
+	}
+	if got := redacted[0].DependencyPaths[0][0]; !strings.HasPrefix(got, "redacted-") || got == billing+"@2.0.0" {
+		t.Errorf("expected @acme/app to be redacted to its own token, got %q", got)
+	}
+
+	// vulnerabilities and packages that do not match should be left as they are
+	if diff := cmp.Diff(results.Results[0].Packages[0].Groups, redacted[0].Groups); diff != "" {
+		t.Errorf("groups mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(results.Results[0].Packages[1], redacted[1]); diff != "" {
+		t.Errorf("unmatched package mismatch (-want +got):\n%s", diff)
+	}
+
+	// the original results should not have been changed
+	if got := results.Results[0].Packages[0].Package.Name; got != "@acme/billing" {
+		t.Errorf("expected the original results to be unchanged, got %q", got)
+	}
+	if got := results.Results[0].Packages[0].Vulnerabilities[0].Affected[0].Package.Name; got != "@acme/billing" {
+		t.Errorf("expected the original vulnerabilities to be unchanged, got %q", got)
+	}
+
+	// the same name should always be redacted to the same token
+	if err := r.PrintResult(results); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := recorder.printed.Results[0].Packages[0].Package.Name; got != billing {
+		t.Errorf("expected redaction to be deterministic, got %q and %q", billing, got)
+	}
+}
@@ -52,5 +52,7 @@ func (r *SARIFReporter) Verbosef(format string, a ...any) {
 }
 
 func (r *SARIFReporter) PrintResult(vulnResult *models.VulnerabilityResults) error {
+	vulnResult.Sort()
+
 	return output.PrintSARIFReport(vulnResult, r.stdout)
 }
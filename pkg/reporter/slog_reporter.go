@@ -0,0 +1,175 @@
+package reporter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// StructuredReporter is a Reporter that can also record diagnostics with structured key/value attributes.
+type StructuredReporter interface {
+	Reporter
+	// Logger returns the logger that structured diagnostics should be recorded with.
+	Logger() *slog.Logger
+}
+
+// Logger returns a logger for recording diagnostics with structured key/value attributes.
+//
+// If the reporter is a StructuredReporter its logger is used, otherwise the attributes
+// are formatted as text after the message and printed with the reporter, using
+// Errorf, Warnf, Infof or Verbosef depending on the level of the record.
+func Logger(r Reporter) *slog.Logger {
+	if sr, ok := r.(StructuredReporter); ok {
+		return sr.Logger()
+	}
+
+	h := &reporterHandler{r: r, buf: &bytes.Buffer{}, mu: &sync.Mutex{}}
+	h.text = slog.NewTextHandler(h.buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			// the message is printed as is ahead of the attributes,
+			// and the reporter decides what to do with the level
+			if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey || a.Key == slog.MessageKey) {
+				return slog.Attr{}
+			}
+
+			return a
+		},
+	})
+
+	return slog.New(h)
+}
+
+// reporterHandler is a slog.Handler that prints records with a Reporter,
+// using a text handler to format their attributes
+type reporterHandler struct {
+	r    Reporter
+	text slog.Handler
+
+	// buf is what text writes to, which is shared by every handler derived from it
+	buf *bytes.Buffer
+	mu  *sync.Mutex
+}
+
+func (h *reporterHandler) Enabled(context.Context, slog.Level) bool {
+	// the reporter decides what is actually printed
+	return true
+}
+
+func (h *reporterHandler) Handle(ctx context.Context, record slog.Record) error {
+	h.mu.Lock()
+	h.buf.Reset()
+	err := h.text.Handle(ctx, record)
+	attrs := strings.TrimSpace(h.buf.String())
+	h.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	msg := record.Message
+	if attrs != "" {
+		msg += " " + attrs
+	}
+
+	switch {
+	case record.Level >= slog.LevelError:
+		h.r.Errorf("%s\n", msg)
+	case record.Level >= slog.LevelWarn:
+		h.r.Warnf("%s\n", msg)
+	case record.Level >= slog.LevelInfo:
+		h.r.Infof("%s\n", msg)
+	default:
+		h.r.Verbosef("%s\n", msg)
+	}
+
+	return nil
+}
+
+func (h *reporterHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &reporterHandler{r: h.r, text: h.text.WithAttrs(attrs), buf: h.buf, mu: h.mu}
+}
+
+func (h *reporterHandler) WithGroup(name string) slog.Handler {
+	return &reporterHandler{r: h.r, text: h.text.WithGroup(name), buf: h.buf, mu: h.mu}
+}
+
+// SlogReporter records runtime information with a *slog.Logger, for embedding OSV-Scanner
+// in programs that use structured logging. Errors are recorded at slog.LevelError, warnings
+// at slog.LevelWarn, info at slog.LevelInfo, and verbose information at slog.LevelDebug.
+//
+// Results are not printed, as they should be handled using what is returned from the scan.
+type SlogReporter struct {
+	hasErrored bool
+	logger     *slog.Logger
+	progress   progressTracker
+}
+
+func NewSlogReporter(logger *slog.Logger) *SlogReporter {
+	return &SlogReporter{
+		logger:     logger,
+		hasErrored: false,
+	}
+}
+
+func (r *SlogReporter) log(level slog.Level, format string, a ...any) {
+	r.logger.Log(context.Background(), level, strings.TrimSuffix(fmt.Sprintf(format, a...), "\n"))
+}
+
+func (r *SlogReporter) Errorf(format string, a ...any) {
+	r.log(slog.LevelError, format, a...)
+	r.hasErrored = true
+}
+
+func (r *SlogReporter) HasErrored() bool {
+	return r.hasErrored
+}
+
+func (r *SlogReporter) Warnf(format string, a ...any) {
+	r.log(slog.LevelWarn, format, a...)
+}
+
+func (r *SlogReporter) Infof(format string, a ...any) {
+	r.log(slog.LevelInfo, format, a...)
+}
+
+func (r *SlogReporter) Verbosef(format string, a ...any) {
+	r.log(slog.LevelDebug, format, a...)
+}
+
+// Progressf logs the progress periodically at slog.LevelInfo, with how many items are done,
+// the total if it is known, and an estimate of how long is left as attributes
+func (r *SlogReporter) Progressf(done, total int, format string) {
+	r.progress.log(r.logger, done, total, format)
+}
+
+func (r *SlogReporter) Logger() *slog.Logger {
+	return r.logger
+}
+
+func (r *SlogReporter) PrintResult(vulnResult *models.VulnerabilityResults) error {
+	return nil
+}
+
+var logFormats = []string{"text", "json"}
+
+// LogFormats are the formats that the CLI can print runtime information in, with
+// "text" being printed by the reporter and "json" by a NewJSONLogReporter
+func LogFormats() []string {
+	return logFormats
+}
+
+// NewJSONLogReporter returns a reporter that prints results with r, while recording runtime information
+// as JSON lines written to w, at the slog level of each verbosity up to the given one, so that it can be
+// processed separately from the results.
+func NewJSONLogReporter(r Reporter, w io.Writer, level VerbosityLevel) *MultiReporter {
+	logger := slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level.SlogLevel()}))
+
+	return NewMultiReporter(NewSlogReporter(logger), r)
+}
@@ -0,0 +1,150 @@
+package reporter_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+func TestSlogReporter(t *testing.T) {
+	t.Parallel()
+
+	writer := &bytes.Buffer{}
+	logger := slog.New(slog.NewTextHandler(writer, &slog.HandlerOptions{
+		Level: slog.LevelInfo,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+
+			return a
+		},
+	}))
+	r := reporter.NewSlogReporter(logger)
+
+	r.Verbosef("hidden\n")
+	r.Infof("Scanned %s file\n", "package-lock.json")
+	r.Warnf("careful")
+
+	if r.HasErrored() {
+		t.Error("HasErrored() should have returned false")
+	}
+
+	r.Errorf("failed\n")
+	reporter.Logger(r).Info("Queried OSV", "queries", 2)
+
+	expected := "level=INFO msg=\"Scanned package-lock.json file\"\n" +
+		"level=WARN msg=careful\n" +
+		"level=ERROR msg=failed\n" +
+		"level=INFO msg=\"Queried OSV\" queries=2\n"
+
+	if writer.String() != expected {
+		t.Errorf("expected \"%s\", got \"%s\"", expected, writer.String())
+	}
+	if !r.HasErrored() {
+		t.Error("HasErrored() should have returned true")
+	}
+}
+
+func TestLogger(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		lvl              reporter.VerbosityLevel
+		expectedPrintout string
+	}{
+		{
+			lvl: reporter.VerboseLevel,
+			expectedPrintout: "Resolved requirement manifest=/path/to/package.json package.name=lodash package.version=\"^4.0.0 || ^5.0.0\"\n" +
+				"Could not resolve requirement manifest=/path/to/package.json\n" +
+				"Something went wrong\n",
+		},
+		{
+			lvl: reporter.InfoLevel,
+			expectedPrintout: "Could not resolve requirement manifest=/path/to/package.json\n" +
+				"Something went wrong\n",
+		},
+		{
+			lvl:              reporter.ErrorLevel,
+			expectedPrintout: "Something went wrong\n",
+		},
+	}
+
+	for _, test := range tests {
+		writer := &bytes.Buffer{}
+		r := reporter.NewTableReporter(writer, writer, test.lvl, false, 0)
+		logger := reporter.Logger(r).With("manifest", "/path/to/package.json")
+
+		logger.Debug("Resolved requirement", slog.Group("package", "name", "lodash", "version", "^4.0.0 || ^5.0.0"))
+		logger.Info("Could not resolve requirement")
+		reporter.Logger(r).Error("Something went wrong")
+
+		if writer.String() != test.expectedPrintout {
+			t.Errorf("expected \"%s\", got \"%s\"", test.expectedPrintout, writer.String())
+		}
+		if !r.HasErrored() {
+			t.Error("HasErrored() should have returned true")
+		}
+	}
+}
+
+func TestLogger_Void(t *testing.T) {
+	t.Parallel()
+
+	r := &reporter.VoidReporter{}
+	reporter.Logger(r).Warn("nothing to see", "key", "value")
+
+	if r.HasErrored() {
+		t.Error("HasErrored() should have returned false")
+	}
+}
+
+func TestNewJSONLogReporter(t *testing.T) {
+	t.Parallel()
+
+	logs := &bytes.Buffer{}
+	results := &bytes.Buffer{}
+	r := reporter.NewJSONLogReporter(reporter.NewJSONReporter(results, io.Discard, reporter.VerboseLevel), logs, reporter.InfoLevel)
+
+	r.Verbosef("hidden\n")
+	r.Infof("Scanned %s file\n", "package-lock.json")
+	reporter.Logger(reporter.NewRedactingReporter(r, regexp.MustCompile("^internal-"))).Warn("Queried OSV", "queries", 2)
+	r.Errorf("failed\n")
+
+	if err := r.PrintResult(&models.VulnerabilityResults{Results: []models.PackageSource{}}); err != nil {
+		t.Fatalf("PrintResult() error = %v", err)
+	}
+
+	var got []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("log line is not JSON: %v\n%s", err, line)
+		}
+		delete(record, slog.TimeKey)
+		got = append(got, record)
+	}
+
+	want := []map[string]any{
+		{"level": "INFO", "msg": "Scanned package-lock.json file"},
+		{"level": "WARN", "msg": "Queried OSV", "queries": float64(2)},
+		{"level": "ERROR", "msg": "failed"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("logs mismatch (-want +got):\n%s", diff)
+	}
+	if !r.HasErrored() {
+		t.Error("HasErrored() should have returned true")
+	}
+	if !strings.Contains(results.String(), `"results": []`) {
+		t.Errorf("results were not printed by the reporter, got \"%s\"", results.String())
+	}
+}
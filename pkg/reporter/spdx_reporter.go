@@ -0,0 +1,60 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// SPDXReporter prints the packages in the results as an SPDX 2.3 JSON document,
+// so that the scan can be used as a software bill of materials
+type SPDXReporter struct {
+	hasErrored bool
+	stdout     io.Writer
+	stderr     io.Writer
+	level      VerbosityLevel
+}
+
+func NewSPDXReporter(stdout io.Writer, stderr io.Writer, level VerbosityLevel) *SPDXReporter {
+	return &SPDXReporter{
+		stdout:     stdout,
+		stderr:     stderr,
+		level:      level,
+		hasErrored: false,
+	}
+}
+
+func (r *SPDXReporter) Errorf(format string, a ...any) {
+	fmt.Fprintf(r.stderr, format, a...)
+	r.hasErrored = true
+}
+
+func (r *SPDXReporter) HasErrored() bool {
+	return r.hasErrored
+}
+
+func (r *SPDXReporter) Warnf(format string, a ...any) {
+	if WarnLevel <= r.level {
+		fmt.Fprintf(r.stderr, format, a...)
+	}
+}
+
+func (r *SPDXReporter) Infof(format string, a ...any) {
+	if InfoLevel <= r.level {
+		fmt.Fprintf(r.stderr, format, a...)
+	}
+}
+
+func (r *SPDXReporter) Verbosef(format string, a ...any) {
+	if VerboseLevel <= r.level {
+		fmt.Fprintf(r.stderr, format, a...)
+	}
+}
+
+func (r *SPDXReporter) PrintResult(vulnResult *models.VulnerabilityResults) error {
+	vulnResult.Sort()
+
+	return output.PrintSPDXReport(vulnResult, r.stdout)
+}
@@ -0,0 +1,37 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// SummaryReporter prints runtime information in the same way as the TableReporter, but only
+// prints the totals of the vulnerability results and the top most severe vulnerabilities,
+// rather than every finding, so that logs stay short when the full results are saved elsewhere.
+type SummaryReporter struct {
+	*TableReporter
+	top int
+}
+
+func NewSummaryReporter(stdout io.Writer, stderr io.Writer, level VerbosityLevel, markdown bool, terminalWidth int, top int) *SummaryReporter {
+	return &SummaryReporter{
+		TableReporter: NewTableReporter(stdout, stderr, level, markdown, terminalWidth),
+		top:           top,
+	}
+}
+
+func (r *SummaryReporter) PrintResult(vulnResult *models.VulnerabilityResults) error {
+	r.clearProgress()
+
+	if len(vulnResult.Results) == 0 && !r.hasErrored {
+		fmt.Fprintf(r.stdout, "No issues found\n")
+		return nil
+	}
+
+	output.PrintSummaryReport(vulnResult, r.stdout, r.terminalWidth, r.markdown, r.top)
+
+	return nil
+}
@@ -8,6 +8,13 @@ import (
 	"github.com/google/osv-scanner/pkg/models"
 )
 
+// TableOptions customizes how the TableReporter presents the results
+type TableOptions struct {
+	// DedupeAdvisories lists each advisory once along with all the packages that it affects,
+	// rather than once for every package that it affects
+	DedupeAdvisories bool
+}
+
 type TableReporter struct {
 	hasErrored bool
 	stdout     io.Writer
@@ -16,9 +23,17 @@ type TableReporter struct {
 	markdown   bool
 	// 0 indicates not a terminal output
 	terminalWidth int
+	// progressShown is true if there is progress on the current line of stdout
+	progressShown bool
+	progress      progressTracker
+	options       TableOptions
 }
 
 func NewTableReporter(stdout io.Writer, stderr io.Writer, level VerbosityLevel, markdown bool, terminalWidth int) *TableReporter {
+	return NewTableReporterWithOptions(stdout, stderr, level, markdown, terminalWidth, TableOptions{})
+}
+
+func NewTableReporterWithOptions(stdout io.Writer, stderr io.Writer, level VerbosityLevel, markdown bool, terminalWidth int, options TableOptions) *TableReporter {
 	return &TableReporter{
 		stdout:        stdout,
 		stderr:        stderr,
@@ -26,10 +41,12 @@ func NewTableReporter(stdout io.Writer, stderr io.Writer, level VerbosityLevel,
 		level:         level,
 		markdown:      markdown,
 		terminalWidth: terminalWidth,
+		options:       options,
 	}
 }
 
 func (r *TableReporter) Errorf(format string, a ...any) {
+	r.clearProgress()
 	fmt.Fprintf(r.stderr, format, a...)
 	r.hasErrored = true
 }
@@ -40,33 +57,73 @@ func (r *TableReporter) HasErrored() bool {
 
 func (r *TableReporter) Warnf(format string, a ...any) {
 	if WarnLevel <= r.level {
+		r.clearProgress()
 		fmt.Fprintf(r.stdout, format, a...)
 	}
 }
 
 func (r *TableReporter) Infof(format string, a ...any) {
 	if InfoLevel <= r.level {
+		r.clearProgress()
 		fmt.Fprintf(r.stdout, format, a...)
 	}
 }
 
 func (r *TableReporter) Verbosef(format string, a ...any) {
 	if VerboseLevel <= r.level {
+		r.clearProgress()
 		fmt.Fprintf(r.stdout, format, a...)
 	}
 }
 
+// Progressf shows the progress on the current line of stdout with a bar and an estimate of how
+// long is left if it is a terminal, otherwise logging it periodically like Infof
+func (r *TableReporter) Progressf(done, total int, format string) {
+	if InfoLevel > r.level {
+		return
+	}
+
+	if r.terminalWidth == 0 {
+		r.progress.log(Logger(r), done, total, format)
+
+		return
+	}
+
+	if total != UnknownTotal && done >= total {
+		r.clearProgress()
+		r.progress.finish()
+
+		return
+	}
+
+	fmt.Fprint(r.stdout, "\r\033[K"+r.progress.line(done, total, format))
+	r.progressShown = true
+}
+
+func (r *TableReporter) clearProgress() {
+	if r.progressShown {
+		fmt.Fprint(r.stdout, "\r\033[K")
+		r.progressShown = false
+	}
+}
+
 func (r *TableReporter) PrintResult(vulnResult *models.VulnerabilityResults) error {
+	r.clearProgress()
+
 	if len(vulnResult.Results) == 0 && !r.hasErrored {
 		fmt.Fprintf(r.stdout, "No issues found\n")
 		return nil
 	}
 
+	// the markdown output starts with the summary, rather than having it as a footer
 	if r.markdown {
-		output.PrintMarkdownTableResults(vulnResult, r.stdout)
-	} else {
-		output.PrintTableResults(vulnResult, r.stdout, r.terminalWidth)
+		output.PrintMarkdownTableResultsWithOptions(vulnResult, r.stdout, output.TableOptions(r.options))
+
+		return nil
 	}
 
+	output.PrintTableResultsWithOptions(vulnResult, r.stdout, r.terminalWidth, output.TableOptions(r.options))
+	output.PrintSummary(output.NewSummary(vulnResult), r.stdout)
+
 	return nil
 }
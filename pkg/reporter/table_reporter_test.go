@@ -96,3 +96,53 @@ func TestTableReporter_Verbosef(t *testing.T) {
 		}
 	}
 }
+
+func TestTableReporter_Progressf(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		lvl              reporter.VerbosityLevel
+		terminalWidth    int
+		expectedPrintout string
+	}{
+		{
+			name:          "terminal",
+			lvl:           reporter.InfoLevel,
+			terminalWidth: 80,
+			expectedPrintout: "\r\033[K[======>             ] Resolved 1/3 requirements" +
+				"\r\033[K[=============>      ] Resolved 2/3 requirements\r\033[Khello world!" +
+				"\r\033[K[=============>      ] Resolved 2/3 requirements\r\033[K" +
+				"\r\033[KSearched 100 files\r\033[K",
+		},
+		{
+			name:             "not a terminal",
+			lvl:              reporter.InfoLevel,
+			terminalWidth:    0,
+			expectedPrintout: "hello world!",
+		},
+		{
+			name:             "errors only",
+			lvl:              reporter.ErrorLevel,
+			terminalWidth:    80,
+			expectedPrintout: "",
+		},
+	}
+
+	for _, test := range tests {
+		writer := &bytes.Buffer{}
+		r := reporter.NewTableReporter(writer, io.Discard, test.lvl, false, test.terminalWidth)
+
+		reporter.Progress(r, 1, 3, "Resolved %d/%d requirements")
+		reporter.Progress(r, 2, 3, "Resolved %d/%d requirements")
+		r.Infof("hello world!")
+		reporter.Progress(r, 2, 3, "Resolved %d/%d requirements")
+		reporter.Progress(r, 3, 3, "Resolved %d/%d requirements")
+		reporter.Progress(r, 100, reporter.UnknownTotal, "Searched %d files")
+		reporter.Progress(r, 150, 150, "Searched %d files")
+
+		if writer.String() != test.expectedPrintout {
+			t.Errorf("%s: expected %q, got %q", test.name, test.expectedPrintout, writer.String())
+		}
+	}
+}
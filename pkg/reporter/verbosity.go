@@ -2,6 +2,7 @@ package reporter
 
 import (
 	"fmt"
+	"log/slog"
 	"strings"
 )
 
@@ -46,3 +47,17 @@ func ParseVerbosityLevel(text string) (VerbosityLevel, error) {
 		return l, fmt.Errorf("invalid verbosity level \"%s\" - must be one of: %s", text, strings.Join(VerbosityLevels(), ", "))
 	}
 }
+
+// SlogLevel returns the level that the SlogReporter records messages at the verbosity level
+func (l VerbosityLevel) SlogLevel() slog.Level {
+	switch l {
+	case ErrorLevel:
+		return slog.LevelError
+	case WarnLevel:
+		return slog.LevelWarn
+	case InfoLevel:
+		return slog.LevelInfo
+	default:
+		return slog.LevelDebug
+	}
+}
@@ -0,0 +1,5 @@
+// Package scannerpb is the gRPC interface of the scanner, as served by
+// "osv-scanner serve-api --grpc-address" and implemented by package grpcserver.
+package scannerpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative scanner.proto
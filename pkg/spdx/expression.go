@@ -0,0 +1,270 @@
+package spdx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidExpression = errors.New("invalid spdx license expression")
+
+// expression is a node of a parsed SPDX license expression, which is either
+// a compound of other expressions joined by an operator, or a single license
+type expression struct {
+	// operator is "and" or "or" for compound expressions, and empty for licenses
+	operator string
+	operands []*expression
+
+	// license is the lowercased identifier of the license, without any "+" suffix
+	license string
+	// orLater is true if the license was suffixed with "+"
+	orLater bool
+	// exception is the lowercased identifier of the exception the license is used with, if any
+	exception string
+}
+
+// tokenize splits an expression into parentheses and words
+func tokenize(expr string) []string {
+	var tokens []string
+	var current strings.Builder
+
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, r := range expr {
+		switch {
+		case r == '(' || r == ')':
+			flush()
+			tokens = append(tokens, string(r))
+		case r == ' ' || r == '\t' || r == '\n':
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *parser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+
+	return p.tokens[p.pos]
+}
+
+// isOperator checks if the token is the given operator, which can be either all upper or lowercase
+func isOperator(token, operator string) bool {
+	return token == strings.ToUpper(operator) || token == operator
+}
+
+func (p *parser) parseCompound(operator string, next func() (*expression, error)) (*expression, error) {
+	left, err := next()
+	if err != nil {
+		return nil, err
+	}
+
+	operands := []*expression{left}
+	for isOperator(p.peek(), operator) {
+		p.pos++
+
+		right, err := next()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, right)
+	}
+
+	if len(operands) == 1 {
+		return left, nil
+	}
+
+	return &expression{operator: operator, operands: operands}, nil
+}
+
+func (p *parser) parseOr() (*expression, error) {
+	return p.parseCompound("or", p.parseAnd)
+}
+
+func (p *parser) parseAnd() (*expression, error) {
+	return p.parseCompound("and", p.parseWith)
+}
+
+func (p *parser) parseWith() (*expression, error) {
+	expr, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	if !isOperator(p.peek(), "with") {
+		return expr, nil
+	}
+	p.pos++
+
+	if expr.license == "" {
+		return nil, fmt.Errorf("%w: WITH must follow a license", ErrInvalidExpression)
+	}
+
+	exception := p.peek()
+	if !isIdentifier(exception) {
+		return nil, fmt.Errorf("%w: expected an exception after WITH, got %q", ErrInvalidExpression, exception)
+	}
+	p.pos++
+
+	expr.exception = strings.ToLower(exception)
+
+	return expr, nil
+}
+
+func (p *parser) parsePrimary() (*expression, error) {
+	token := p.peek()
+
+	if token == "(" {
+		p.pos++
+
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("%w: missing closing parenthesis", ErrInvalidExpression)
+		}
+		p.pos++
+
+		return expr, nil
+	}
+
+	if !isIdentifier(token) {
+		return nil, fmt.Errorf("%w: expected a license, got %q", ErrInvalidExpression, token)
+	}
+	p.pos++
+
+	license := strings.ToLower(token)
+	// some deprecated identifiers include the "+", in which case it should be kept
+	if IDs[license] {
+		return &expression{license: license}, nil
+	}
+
+	base, orLater := strings.CutSuffix(license, "+")
+
+	return &expression{license: base, orLater: orLater}, nil
+}
+
+// isIdentifier checks if the token can be used as a license or exception identifier
+func isIdentifier(token string) bool {
+	if token == "" || token == "(" || token == ")" {
+		return false
+	}
+
+	for _, operator := range []string{"and", "or", "with"} {
+		if isOperator(token, operator) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parseExpression parses an SPDX license expression, such as "(MIT OR Apache-2.0)"
+func parseExpression(expr string) (*expression, error) {
+	p := &parser{tokens: tokenize(expr)}
+
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("%w: expression is empty", ErrInvalidExpression)
+	}
+
+	parsed, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos != len(p.tokens) {
+		return nil, fmt.Errorf("%w: unexpected %q", ErrInvalidExpression, p.peek())
+	}
+
+	return parsed, nil
+}
+
+// licenses returns all the licenses that are used in the expression
+func (e *expression) licenses() []string {
+	if e.operator == "" {
+		return []string{e.license}
+	}
+
+	var licenses []string
+	for _, operand := range e.operands {
+		licenses = append(licenses, operand.licenses()...)
+	}
+
+	return licenses
+}
+
+// satisfiedBy checks if the expression can be complied with using only the allowed licenses.
+//
+// Exceptions only ever grant additional permissions, and "or later" licenses can always be
+// used under the version that they name, so both are satisfied by their base license.
+func (e *expression) satisfiedBy(allowed map[string]bool) bool {
+	switch e.operator {
+	case "and":
+		for _, operand := range e.operands {
+			if !operand.satisfiedBy(allowed) {
+				return false
+			}
+		}
+
+		return true
+	case "or":
+		for _, operand := range e.operands {
+			if operand.satisfiedBy(allowed) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return allowed[e.license] ||
+		(e.orLater && allowed[e.license+"+"]) ||
+		(e.exception != "" && allowed[e.license+" with "+e.exception])
+}
+
+// isRecognized checks if the license is a known spdx identifier, or is a user defined one
+func isRecognized(license string) bool {
+	return IDs[license] || license == "unknown" ||
+		strings.HasPrefix(license, "licenseref-") ||
+		strings.HasPrefix(license, "documentref-")
+}
+
+// Satisfies checks if a package with the given license, which may be an SPDX
+// license expression, can be used while only complying with licenses in the allowlist.
+//
+// Licenses that are not valid expressions must be in the allowlist exactly.
+func Satisfies(license string, allowlist []string) bool {
+	allowed := make(map[string]bool, len(allowlist))
+	for _, l := range allowlist {
+		allowed[strings.Join(strings.Fields(strings.ToLower(l)), " ")] = true
+	}
+
+	if allowed[strings.Join(strings.Fields(strings.ToLower(license)), " ")] {
+		return true
+	}
+
+	expr, err := parseExpression(license)
+	if err != nil {
+		return false
+	}
+
+	return expr.satisfiedBy(allowed)
+}
@@ -0,0 +1,63 @@
+package spdx
+
+import (
+	"testing"
+)
+
+func TestSatisfies(t *testing.T) {
+	t.Parallel()
+
+	allowlist := []string{"MIT", "apache-2.0", "GPL-2.0-only", "LGPL-2.1+", "GPL-3.0 WITH GCC-exception-3.1"}
+
+	tests := []struct {
+		license string
+		want    bool
+	}{
+		{license: "MIT", want: true},
+		{license: "mit", want: true},
+		{license: "BSD-3-Clause", want: false},
+		{license: "MIT OR Apache-2.0", want: true},
+		{license: "(MIT OR Apache-2.0)", want: true},
+		{license: "MIT OR BSD-3-Clause", want: true},
+		{license: "BSD-3-Clause OR MIT", want: true},
+		{license: "MIT AND Apache-2.0", want: true},
+		{license: "MIT AND BSD-3-Clause", want: false},
+		{license: "mit and bsd-3-clause or apache-2.0", want: true},
+		{license: "MIT AND (BSD-3-Clause OR Apache-2.0)", want: true},
+		{license: "(MIT AND BSD-3-Clause) OR (Apache-2.0 AND ISC)", want: false},
+		{license: "GPL-2.0-only WITH Classpath-exception-2.0", want: true},
+		{license: "GPL-3.0 WITH GCC-exception-3.1", want: true},
+		{license: "GPL-3.0 WITH Classpath-exception-2.0", want: false},
+		{license: "LGPL-2.1+", want: true},
+		{license: "LGPL-2.1", want: false},
+		{license: "UNKNOWN", want: false},
+		{license: "MIT OR", want: false},
+		{license: "non-standard", want: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.license, func(t *testing.T) {
+			t.Parallel()
+
+			if got := Satisfies(tt.license, allowlist); got != tt.want {
+				t.Errorf("Satisfies(%q) = %v, want %v", tt.license, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSatisfies_ExactMatch(t *testing.T) {
+	t.Parallel()
+
+	if !Satisfies("UNKNOWN", []string{"unknown"}) {
+		t.Errorf("expected UNKNOWN to be satisfied by an allowlist containing it")
+	}
+
+	if !Satisfies("MIT  OR  BSD-3-Clause", []string{"MIT OR BSD-3-Clause"}) {
+		t.Errorf("expected an expression to be satisfied by an allowlist containing it")
+	}
+
+	if !Satisfies("non-standard", []string{"non-standard"}) {
+		t.Errorf("expected an invalid expression to be satisfied by an allowlist containing it")
+	}
+}
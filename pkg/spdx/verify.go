@@ -4,13 +4,30 @@ import "strings"
 
 // Unrecognized filters licenses for non-spdx identifiers. The "unknown" string is
 // also treated as a valid identifier.
+//
+// Licenses can be SPDX license expressions, which are unrecognized if they are
+// invalid or if any of the licenses that they use are not spdx identifiers.
 func Unrecognized(licenses []string) (unrecognized []string) {
 	for _, license := range licenses {
-		l := strings.ToLower(license)
-		if !IDs[l] && l != "unknown" {
+		if !isRecognized(strings.ToLower(license)) && !isRecognizedExpression(license) {
 			unrecognized = append(unrecognized, license)
 		}
 	}
 
 	return unrecognized
 }
+
+func isRecognizedExpression(license string) bool {
+	expr, err := parseExpression(license)
+	if err != nil {
+		return false
+	}
+
+	for _, l := range expr.licenses() {
+		if !isRecognized(l) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -24,6 +24,14 @@ func Test_unrecognized(t *testing.T) {
 			name:     "some recognized, some unrecognized licenses",
 			licenses: []string{"agpl-1.0", "unrecognized license", "apache-1.0"},
 			want:     []string{"unrecognized license"},
+		}, {
+			name:     "license expressions",
+			licenses: []string{"MIT OR Apache-2.0", "(MIT AND BSD-3-Clause) or GPL-2.0+", "GPL-2.0-only WITH Classpath-exception-2.0", "LicenseRef-custom"},
+			want:     nil,
+		}, {
+			name:     "invalid license expressions",
+			licenses: []string{"MIT OR", "(MIT AND Apache-2.0", "MIT OR mit-ish", "AND"},
+			want:     []string{"MIT OR", "(MIT AND Apache-2.0", "MIT OR mit-ish", "AND"},
 		},
 	}
 	for _, tt := range tests {